@@ -0,0 +1,83 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// A manifest of ACLs, grouped by principal, which is the format read by confluent acl restore.
+type manifest struct {
+	Principals []principal `yaml:"principals" json:"principals"`
+}
+
+type principal struct {
+	Principal string `yaml:"principal" json:"principal"`
+	ACLs      []acl  `yaml:"acls" json:"acls"`
+}
+
+// An acl grants or denies operations on resources which match a name or prefix. ACLs which only differ in their
+// operation are merged.
+type acl struct {
+	ResourceType string   `yaml:"resource_type" json:"resource_type"`
+	ResourceName string   `yaml:"resource_name" json:"resource_name"`
+	PatternType  string   `yaml:"pattern_type" json:"pattern_type"`
+	Operations   []string `yaml:"operations" json:"operations"`
+	Permission   string   `yaml:"permission" json:"permission"`
+	Host         string   `yaml:"host,omitempty" json:"host,omitempty"`
+}
+
+// group merges the ACLs by principal and resource, in a stable order so that exports can be diffed.
+func group(listed []cli.ACL) manifest {
+	type key struct {
+		principal, resourceType, resourceName, patternType, permission, host string
+	}
+
+	operations := map[key][]string{}
+	for _, a := range listed {
+		k := key{a.Principal, strings.ToUpper(a.ResourceType), a.ResourceName, strings.ToUpper(a.PatternType), strings.ToUpper(a.Permission), a.Host}
+		operations[k] = append(operations[k], strings.ToUpper(a.Operation))
+	}
+
+	byPrincipal := map[string][]acl{}
+	for k, ops := range operations {
+		sort.Strings(ops)
+		host := k.host
+		if host == "*" {
+			host = ""
+		}
+		byPrincipal[k.principal] = append(byPrincipal[k.principal], acl{
+			ResourceType: k.resourceType,
+			ResourceName: k.resourceName,
+			PatternType:  k.patternType,
+			Operations:   ops,
+			Permission:   k.permission,
+			Host:         host,
+		})
+	}
+
+	var m manifest
+	for name, acls := range byPrincipal {
+		sort.Slice(acls, func(i, j int) bool {
+			a, b := acls[i], acls[j]
+			if a.ResourceType != b.ResourceType {
+				return a.ResourceType < b.ResourceType
+			}
+			if a.ResourceName != b.ResourceName {
+				return a.ResourceName < b.ResourceName
+			}
+			if a.PatternType != b.PatternType {
+				return a.PatternType < b.PatternType
+			}
+			if a.Permission != b.Permission {
+				return a.Permission < b.Permission
+			}
+			return a.Host < b.Host
+		})
+		m.Principals = append(m.Principals, principal{Principal: name, ACLs: acls})
+	}
+	sort.Slice(m.Principals, func(i, j int) bool { return m.Principals[i].Principal < m.Principals[j].Principal })
+
+	return m
+}
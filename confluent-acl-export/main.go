@@ -0,0 +1,115 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "export",
+		Short:   "Export the ACLs in a Kafka cluster to a file.",
+		Long:    "Export the ACLs in a Kafka cluster, including prefixed patterns, to a declarative YAML or JSON file grouped by principal, which can be audited, kept under version control, and applied with confluent acl restore.",
+		RunE:    export,
+		Example: "confluent acl export --cluster lkc-123456 --file acls.yaml",
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	output.AddFlag(&cmd, "Format of the ACLs")
+	output.SetDefault(&cmd, output.YAML)
+	cmd.Flags().String("file", "", "File to write the ACLs to. Defaults to stdout.")
+	cmd.Flags().String("principal", "", `Only export the ACLs of this principal, such as "User:sa-123456".`)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-acl-export")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-acl-export"))
+
+	exitcode.Execute(&cmd)
+}
+
+func export(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	principal, err := cmd.Flags().GetString("principal")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"kafka", "acl", "list"}, cli.ClusterFlags(cluster, environment)...)
+	if principal != "" {
+		args = append(args, "--principal", principal)
+	}
+
+	var listed []cli.ACL
+	if err := cli.JSON(&listed, args...); err != nil {
+		return err
+	}
+
+	m := group(listed)
+
+	out := cmd.OutOrStdout()
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := output.Print(out, format, m, func() { print(out, m) }); err != nil {
+		return err
+	}
+
+	if file != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d ACLs of %d principals to %s.\n", len(listed), len(m.Principals), file)
+	}
+	return nil
+}
+
+// print prints the ACLs as a table, one row per resource of each principal, which can't be restored but is easier to
+// read than the manifest.
+func print(w io.Writer, m manifest) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Principal\tPermission\tOperations\tResource Type\tResource Name\tPattern Type\tHost")
+	for _, p := range m.Principals {
+		for _, a := range p.ACLs {
+			host := a.Host
+			if host == "" {
+				host = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", p.Principal, a.Permission, strings.Join(a.Operations, ", "), a.ResourceType, a.ResourceName, a.PatternType, host)
+		}
+	}
+	_ = tw.Flush()
+}
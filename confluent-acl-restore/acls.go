@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// A manifest of ACLs, grouped by principal, in the format written by confluent acl export.
+type manifest struct {
+	Principals []principal `yaml:"principals" json:"principals"`
+}
+
+type principal struct {
+	Principal string `yaml:"principal" json:"principal"`
+	ACLs      []acl  `yaml:"acls" json:"acls"`
+}
+
+type acl struct {
+	ResourceType string   `yaml:"resource_type" json:"resource_type"`
+	ResourceName string   `yaml:"resource_name" json:"resource_name"`
+	PatternType  string   `yaml:"pattern_type" json:"pattern_type"`
+	Operations   []string `yaml:"operations" json:"operations"`
+	Permission   string   `yaml:"permission" json:"permission"`
+	Host         string   `yaml:"host,omitempty" json:"host,omitempty"`
+}
+
+// binding is a single ACL, with one operation, normalized so that ACLs from the manifest and the cluster compare equal.
+type binding struct {
+	principal    string
+	resourceType string
+	resourceName string
+	patternType  string
+	operation    string
+	permission   string
+	host         string
+}
+
+// listedBinding returns the binding of an ACL which the confluent CLI listed.
+func listedBinding(l cli.ACL) binding {
+	return normalize(binding{l.Principal, l.ResourceType, l.ResourceName, l.PatternType, l.Operation, l.Permission, l.Host})
+}
+
+func normalize(b binding) binding {
+	b.resourceType = strings.ToUpper(b.resourceType)
+	b.patternType = strings.ToUpper(b.patternType)
+	b.operation = strings.ToUpper(strings.ReplaceAll(b.operation, "-", "_"))
+	b.permission = strings.ToUpper(b.permission)
+	if b.patternType == "" {
+		b.patternType = "LITERAL"
+	}
+	if b.host == "" {
+		b.host = "*"
+	}
+	if b.resourceType == "CLUSTER" {
+		b.resourceName = "kafka-cluster"
+	}
+	return b
+}
+
+func (b binding) String() string {
+	resource := fmt.Sprintf("%s %s", b.resourceType, b.resourceName)
+	if b.patternType == "PREFIXED" {
+		resource += "*"
+	}
+	s := fmt.Sprintf("%s %s %s on %s", b.principal, b.permission, b.operation, resource)
+	if b.host != "*" {
+		s += " from " + b.host
+	}
+	return s
+}
+
+func readManifest(path string) ([]binding, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the ACLs: %w", err)
+	}
+
+	var m manifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf(`failed to parse the ACLs "%s": %w`, path, err)
+	}
+
+	var bindings []binding
+	for _, p := range m.Principals {
+		if p.Principal == "" {
+			return nil, fmt.Errorf(`every principal in "%s" must set "principal"`, path)
+		}
+		for _, a := range p.ACLs {
+			if _, ok := resourceFlags[strings.ToUpper(a.ResourceType)]; !ok {
+				return nil, fmt.Errorf(`unsupported resource type "%s" for %s, supported resource types: CLUSTER, GROUP, TOPIC, TRANSACTIONAL_ID`, a.ResourceType, p.Principal)
+			}
+			if permission := strings.ToUpper(a.Permission); permission != "ALLOW" && permission != "DENY" {
+				return nil, fmt.Errorf(`unsupported permission "%s" for %s, supported permissions: ALLOW, DENY`, a.Permission, p.Principal)
+			}
+			if len(a.Operations) == 0 {
+				return nil, fmt.Errorf("an ACL of %s on %s %s has no operations", p.Principal, a.ResourceType, a.ResourceName)
+			}
+			for _, op := range a.Operations {
+				bindings = append(bindings, normalize(binding{p.Principal, a.ResourceType, a.ResourceName, a.PatternType, op, a.Permission, a.Host}))
+			}
+		}
+	}
+	return bindings, nil
+}
+
+// resourceFlags are the flags of "confluent kafka acl create" and "delete" which select each type of resource.
+var resourceFlags = map[string]string{
+	"CLUSTER":          "--cluster-scope",
+	"GROUP":            "--consumer-group",
+	"TOPIC":            "--topic",
+	"TRANSACTIONAL_ID": "--transactional-id",
+}
+
+// aclArgs returns the flags of "confluent kafka acl create" or "delete" for the bindings, which must only differ in their
+// operation.
+func aclArgs(bindings []binding) []string {
+	b := bindings[0]
+
+	var operations []string
+	for _, o := range bindings {
+		operations = append(operations, strings.ToLower(strings.ReplaceAll(o.operation, "_", "-")))
+	}
+	sort.Strings(operations)
+
+	args := []string{"--principal", b.principal, "--operations", strings.Join(operations, ",")}
+	if b.permission == "DENY" {
+		args = append(args, "--deny")
+	} else {
+		args = append(args, "--allow")
+	}
+	if b.host != "*" {
+		args = append(args, "--host", b.host)
+	}
+
+	flag := resourceFlags[b.resourceType]
+	if b.resourceType == "CLUSTER" {
+		args = append(args, flag)
+	} else {
+		args = append(args, flag, b.resourceName)
+	}
+	if b.patternType == "PREFIXED" {
+		args = append(args, "--prefix")
+	}
+	return args
+}
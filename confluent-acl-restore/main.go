@@ -0,0 +1,174 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "restore <file>",
+		Short:   "Apply a file of ACLs to a Kafka cluster.",
+		Long:    "Apply a declarative file of ACLs, such as one written by confluent acl export, to a Kafka cluster. ACLs which already exist are skipped, so the file can be applied repeatedly, such as to manage ACLs as code or restore them after a cluster is rebuilt.",
+		Args:    cobra.ExactArgs(1),
+		RunE:    restore,
+		Example: "confluent acl restore acls.yaml --cluster lkc-123456 --dry-run",
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	dryrun.AddFlag(&cmd, "Print the ACLs which would be created and deleted, without changing them.")
+	cmd.Flags().Bool("prune", false, "Delete ACLs which aren't in the file.")
+	output.AddFlag(&cmd, "Format of the changes")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-acl-restore")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-acl-restore"))
+
+	exitcode.Execute(&cmd)
+}
+
+func restore(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	prune, err := cmd.Flags().GetBool("prune")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	wanted, err := readManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	var listed []cli.ACL
+	if err := cli.JSON(&listed, append([]string{"kafka", "acl", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	existing := map[binding]bool{}
+	for _, l := range listed {
+		existing[listedBinding(l)] = true
+	}
+
+	var create []binding
+	seen := map[binding]bool{}
+	for _, b := range wanted {
+		if !existing[b] && !seen[b] {
+			create = append(create, b)
+		}
+		seen[b] = true
+	}
+
+	var remove []binding
+	if prune {
+		for b := range existing {
+			if !seen[b] {
+				remove = append(remove, b)
+			}
+		}
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, group := range byResource(create) {
+			for _, b := range group {
+				plan.Add("create", "ACL", b.String())
+			}
+		}
+		for _, group := range byResource(remove) {
+			for _, b := range group {
+				plan.Add("delete", "ACL", b.String())
+			}
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := output.Progress(cmd)
+	fmt.Fprintf(out, "%d ACLs in the file already exist.\n", len(seen)-len(create))
+	var applied dryrun.Plan
+	if len(create) == 0 && len(remove) == 0 {
+		return dryrun.PrintApplied(cmd, applied)
+	}
+
+	for _, group := range byResource(create) {
+		for _, b := range group {
+			fmt.Fprintf(out, "+ %s\n", b)
+		}
+		if _, err := cli.Run(append(append([]string{"kafka", "acl", "create"}, aclArgs(group)...), scope...)...); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, applied))
+		}
+		for _, b := range group {
+			applied.Add("create", "ACL", b.String())
+		}
+	}
+
+	for _, group := range byResource(remove) {
+		for _, b := range group {
+			fmt.Fprintf(out, "- %s\n", b)
+		}
+		if _, err := cli.Run(append(append([]string{"kafka", "acl", "delete", "--force"}, aclArgs(group)...), scope...)...); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, applied))
+		}
+		for _, b := range group {
+			applied.Add("delete", "ACL", b.String())
+		}
+	}
+
+	fmt.Fprintf(out, "Created %d ACLs and deleted %d.\n", len(create), len(remove))
+	return dryrun.PrintApplied(cmd, applied)
+}
+
+// byResource groups bindings which only differ in their operation, so that they're created with one command, in a
+// stable order.
+func byResource(bindings []binding) [][]binding {
+	groups := map[binding][]binding{}
+	for _, b := range bindings {
+		k := b
+		k.operation = ""
+		groups[k] = append(groups[k], b)
+	}
+
+	var keys []binding
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	var sorted [][]binding
+	for _, k := range keys {
+		group := groups[k]
+		sort.Slice(group, func(i, j int) bool { return group[i].operation < group[j].operation })
+		sorted = append(sorted, group)
+	}
+	return sorted
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+type user struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+type serviceAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// An entry is an API key in the inventory.
+type entry struct {
+	Key          string    `json:"key"`
+	Description  string    `json:"description"`
+	Owner        string    `json:"owner"`
+	OwnerName    string    `json:"owner_name"`
+	OwnerExists  bool      `json:"owner_exists"`
+	ResourceType string    `json:"resource_type"`
+	Resource     string    `json:"resource"`
+	Created      time.Time `json:"created"`
+	AgeDays      int       `json:"age_days"`
+}
+
+// inventory joins the API keys with their owners, oldest key first. Owners which are neither a user nor a service
+// account of the organization were deleted, and their keys should be too.
+func inventory(keys []cli.APIKey, users []user, serviceAccounts []serviceAccount, now time.Time) []entry {
+	owners := map[string]string{}
+	for _, u := range users {
+		owners[u.ID] = u.Email
+	}
+	for _, sa := range serviceAccounts {
+		owners[sa.ID] = sa.Name
+	}
+
+	entries := make([]entry, len(keys))
+	for i, k := range keys {
+		name, ok := owners[k.OwnerResourceID]
+		if !ok {
+			name = k.OwnerEmail
+		}
+
+		e := entry{
+			Key:          k.Key,
+			Description:  k.Description,
+			Owner:        k.OwnerResourceID,
+			OwnerName:    name,
+			OwnerExists:  ok,
+			ResourceType: k.ResourceType,
+			Resource:     k.ResourceID,
+		}
+		if created, err := time.Parse(time.RFC3339, k.Created); err == nil {
+			e.Created = created
+			e.AgeDays = int(now.Sub(created).Hours() / 24)
+		}
+		entries[i] = e
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Created.Equal(entries[j].Created) {
+			return entries[i].Created.Before(entries[j].Created)
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "inventory",
+		Short: "Report every API key in the organization.",
+		Long:  "Report every API key in the organization, with its owner, resource, creation date, and age, and whether its owner still exists, as a table, JSON, or CSV.",
+		Args:  cobra.NoArgs,
+		RunE:  report,
+		Example: `confluent api-key inventory --output csv > api-keys.csv
+confluent api-key inventory --min-age-days 90 --orphaned-only`,
+	}
+
+	output.AddFlag(&cmd, "Format of the report", "csv")
+	cmd.Flags().Int("min-age-days", 0, "Only report keys which are at least this many days old.")
+	cmd.Flags().Bool("orphaned-only", false, "Only report keys whose owner was deleted.")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-api_key-inventory")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-api_key-inventory"))
+
+	exitcode.Execute(&cmd)
+}
+
+func report(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	minAgeDays, err := cmd.Flags().GetInt("min-age-days")
+	cobra.CheckErr(err)
+
+	orphanedOnly, err := cmd.Flags().GetBool("orphaned-only")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	var keys []cli.APIKey
+	if err := cli.JSON(&keys, "api-key", "list"); err != nil {
+		return err
+	}
+
+	var users []user
+	if err := cli.JSON(&users, "iam", "user", "list"); err != nil {
+		return err
+	}
+
+	var serviceAccounts []serviceAccount
+	if err := cli.JSON(&serviceAccounts, "iam", "service-account", "list"); err != nil {
+		return err
+	}
+
+	var entries []entry
+	for _, e := range inventory(keys, users, serviceAccounts, time.Now()) {
+		if e.AgeDays < minAgeDays || orphanedOnly && e.OwnerExists {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		if entries == nil {
+			entries = []entry{}
+		}
+		return output.Print(out, format, entries, nil)
+	case "csv":
+		return writeCSV(out, entries)
+	default:
+		print(out, entries)
+		return nil
+	}
+}
+
+var columns = []string{"Key", "Description", "Owner", "Owner Name", "Owner Exists", "Resource Type", "Resource", "Created", "Age (Days)"}
+
+func (e entry) row() []string {
+	created := ""
+	if !e.Created.IsZero() {
+		created = e.Created.UTC().Format(time.RFC3339)
+	}
+	return []string{e.Key, e.Description, e.Owner, e.OwnerName, strconv.FormatBool(e.OwnerExists), e.ResourceType, e.Resource, created, strconv.Itoa(e.AgeDays)}
+}
+
+func writeCSV(w io.Writer, entries []entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write(e.row()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func print(w io.Writer, entries []entry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, c := range columns {
+		if i > 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, c)
+	}
+	fmt.Fprintln(tw)
+
+	orphaned := 0
+	for _, e := range entries {
+		for i, v := range e.row() {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, v)
+		}
+		fmt.Fprintln(tw)
+		if !e.OwnerExists {
+			orphaned++
+		}
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%d API keys, %d of deleted owners.\n", len(entries), orphaned)
+}
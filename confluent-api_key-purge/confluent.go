@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/cloud"
+)
+
+// deleteKey deletes the API key with the Cloud API if the client isn't nil, in REST mode, or else with the CLI.
+func deleteKey(api *cloud.Client, key string) error {
+	if api != nil {
+		return api.Do(http.MethodDelete, "/iam/v2/api-keys/"+url.PathEscape(key), nil, nil, nil)
+	}
+	_, err := cli.Run("api-key", "delete", key, "--force")
+	return err
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// exclusions are the API keys which are never deleted, such as those of production, even by a purge which matches them.
+type exclusions []*regexp.Regexp
+
+// readExclusions reads an exclusions file, which has a key ID, an owner account, or a pattern of a resource on each
+// line, such as lkc-prod.*. Each line is a regular expression which must match the whole key ID, owner, or resource.
+// Blank lines and lines starting with # are skipped.
+func readExclusions(path string) (exclusions, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the exclusions file: %w", err)
+	}
+	defer file.Close()
+
+	var e exclusions
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + line + ")$")
+		if err != nil {
+			return nil, fmt.Errorf(`invalid exclusion "%s": %w`, line, err)
+		}
+		e = append(e, re)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the exclusions file: %w", err)
+	}
+	return e, nil
+}
+
+// excluded returns whether the key, its owner, or its resource is excluded.
+func (e exclusions) excluded(k cli.APIKey) bool {
+	for _, re := range e {
+		if re.MatchString(k.Key) || re.MatchString(k.OwnerResourceID) || k.ResourceID != "" && re.MatchString(k.ResourceID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/cloud"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/parallel"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resourceTypes are the types of resources which API keys are for.
+var resourceTypes = []string{"kafka", "schema-registry", "ksql", "flink", "cloud"}
+
+// result is what happened to an API key, for the JSON output.
+type result struct {
+	Key          string `json:"key"`
+	Description  string `json:"description"`
+	Owner        string `json:"owner"`
+	ResourceType string `json:"resource_type"`
+	Resource     string `json:"resource"`
+	Created      string `json:"created,omitempty"`
+	AgeDays      int    `json:"age_days"`
+	Orphaned     bool   `json:"orphaned,omitempty"`
+	Deleted      bool   `json:"deleted"`
+	Error        string `json:"error,omitempty"`
+}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := newCommand()
+
+	completion.Add(cmd, "confluent-api_key-purge")
+	cobra.CheckErr(plugin.ApplyConfig(cmd, "confluent-api_key-purge"))
+
+	exitcode.Execute(cmd)
+}
+
+func newCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete API keys in bulk.",
+		Long:  "Deletes API keys for the current user, specified environment, or service account, after prompting for confirmation. Keys in the exclusions file are never deleted. With --interactive, the keys to purge are picked from a checklist of the keys which the filters found. With --report-only, the keys which would be purged are listed with their owner, resource, and age, such as in CSV to circulate for approval, and nothing is deleted.",
+		Args:  cobra.NoArgs,
+		RunE:  purge,
+		Example: `confluent api-key purge --sa sa-123456 --dry-run
+confluent api-key purge --orphaned --report-only
+confluent api-key purge --env env-123456 --older-than 90d --interactive
+confluent api-key purge --service-account sa-123456 --resource-type schema-registry --older-than 90d
+confluent api-key purge --env env-123456 --exclusions-file protected-keys.txt --force --output json
+confluent api-key purge --resource-type kafka --older-than 180d --report-only --output csv > purge.csv`,
+	}
+
+	cmd.Flags().String("resource", "", "The resource id to filter results by.")
+	cmd.Flags().String("env", "", "The environment id to purge keys from.")
+	cmd.Flags().String("sa", "", "The service account id to purge keys from. --service-account is an alias.")
+	cmd.Flags().String("resource-type", "", "Only purge keys for a type of resource: "+strings.Join(resourceTypes, ", ")+".")
+	cmd.Flags().Bool("orphaned", false, "Only purge the keys of every environment whose owner, a service account or a user, was deleted.")
+	cmd.Flags().String("exclusions-file", "", "File of the API keys to never delete, with a key ID, an owner account, or a regular expression of resources on each line.")
+	cmd.Flags().String("older-than", "", "Only purge keys created longer ago than this, as a number of days such as 90d, or a duration such as 36h.")
+	dryrun.AddFlag(cmd, "List the API keys which would be deleted without deleting them.")
+	cmd.Flags().Bool("force", false, "Delete the API keys without prompting for confirmation.")
+	cmd.Flags().Bool("interactive", false, "Pick the API keys to purge from a checklist of the keys which would be purged, with their owner, resource, and age.")
+	cmd.Flags().Bool("report-only", false, "List the API keys which would be purged, with their owner, resource, and age, without deleting them.")
+	output.AddFlag(cmd, "Format of the output", "csv")
+	cmd.Flags().Int("parallelism", 8, "How many API keys to delete at once.")
+	cloud.AddFlag(cmd)
+	prompt.AddFlags(cmd)
+	logging.AddFlags(cmd)
+
+	// Accept --service-account as an alias of --sa.
+	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "service-account" {
+			name = "sa"
+		}
+		return pflag.NormalizedName(name)
+	})
+
+	cmd.MarkFlagsMutuallyExclusive("env", "sa")
+	cmd.MarkFlagsMutuallyExclusive("orphaned", "sa")
+	cmd.MarkFlagsMutuallyExclusive("report-only", "dry-run")
+	cmd.MarkFlagsMutuallyExclusive("report-only", "force")
+	cmd.MarkFlagsMutuallyExclusive("interactive", "report-only")
+	cmd.MarkFlagsMutuallyExclusive("interactive", "force")
+
+	return cmd
+}
+
+func purge(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	resource, err := cmd.Flags().GetString("resource")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("env")
+	cobra.CheckErr(err)
+
+	serviceAccount, err := cmd.Flags().GetString("sa")
+	cobra.CheckErr(err)
+
+	resourceType, err := cmd.Flags().GetString("resource-type")
+	cobra.CheckErr(err)
+
+	olderThanFlag, err := cmd.Flags().GetString("older-than")
+	cobra.CheckErr(err)
+
+	exclusionsFile, err := cmd.Flags().GetString("exclusions-file")
+	cobra.CheckErr(err)
+
+	orphaned, err := cmd.Flags().GetBool("orphaned")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	reportOnly, err := cmd.Flags().GetBool("report-only")
+	cobra.CheckErr(err)
+
+	interactive, err := cmd.Flags().GetBool("interactive")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	if resourceType != "" && !slices.Contains(resourceTypes, resourceType) {
+		return exitcode.Invalid(`unsupported --resource-type "%s", supported types: %s`, resourceType, strings.Join(resourceTypes, ", "))
+	}
+	var olderThan time.Duration
+	if olderThanFlag != "" {
+		if olderThan, err = parseAge(olderThanFlag); err != nil {
+			return err
+		}
+	}
+	var excluded exclusions
+	if exclusionsFile != "" {
+		if excluded, err = readExclusions(exclusionsFile); err != nil {
+			return exitcode.With(exitcode.Validation, err)
+		}
+	}
+
+	args := []string{"api-key", "list"}
+	if resource != "" {
+		args = append(args, "--resource", resource)
+	}
+	switch {
+	case environment != "":
+		args = append(args, "--environment", environment)
+	case serviceAccount != "":
+		args = append(args, "--service-account", serviceAccount)
+	case orphaned:
+		// The keys of deleted owners aren't the current user's, so every key is listed to find them.
+	default:
+		args = append(args, "--current-user")
+	}
+
+	var listed []cli.APIKey
+	if err := cli.JSON(&listed, args...); err != nil {
+		return err
+	}
+	now := time.Now()
+	keys := filterKeys(listed, resourceType, olderThan, now)
+	if orphaned {
+		if keys, err = orphanedKeys(keys); err != nil {
+			return err
+		}
+	}
+
+	// Excluded keys are dropped before anything is listed, so that they're never deleted, nor reported as purgeable.
+	var protected int
+	keys = slices.DeleteFunc(keys, func(k cli.APIKey) bool {
+		if excluded.excluded(k) {
+			protected++
+			return true
+		}
+		return false
+	})
+	if protected > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Keeping %d API keys of the exclusions file.\n", protected)
+	}
+
+	results := make([]result, len(keys))
+	for i, k := range keys {
+		results[i] = result{Key: k.Key, Description: k.Description, Owner: k.OwnerResourceID, ResourceType: k.ResourceType, Resource: k.ResourceID, Orphaned: orphaned}
+		if created, err := time.Parse(time.RFC3339, k.Created); err == nil {
+			results[i].Created = created.UTC().Format(time.RFC3339)
+			results[i].AgeDays = int(now.Sub(created).Hours() / 24)
+		}
+	}
+
+	if len(keys) == 0 || reportOnly {
+		return printResults(cmd, format, results, reportOnly)
+	}
+	p := prompt.New(cmd)
+	question := fmt.Sprintf("Found %d API keys, are you sure you want to purge them?", len(results))
+	if interactive {
+		if results, err = pickKeys(p, results); err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not purging keys.")
+			return nil
+		}
+		question = fmt.Sprintf("Are you sure you want to purge the %d API keys which are checked?", len(results))
+	}
+	if dryRun {
+		var plan dryrun.Plan
+		for _, r := range results {
+			plan.AddDetail("delete", "API key", r.Key, describeKey(r))
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	if !force {
+		ok, err := p.Confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not purging keys.")
+			return nil
+		}
+	}
+
+	// In REST mode, the keys are deleted with the Cloud API, rather than with a CLI command for each.
+	var api *cloud.Client
+	if cloud.Enabled() {
+		if api, err = cloud.New(); err != nil {
+			return err
+		}
+	}
+
+	// Every key is attempted, so that one which can't be deleted doesn't leave the rest behind.
+	bar := progress.NewBar(cmd.ErrOrStderr(), "API keys deleted", len(results))
+	errs := parallel.Each(results, parallelism, func(i int, r result) error {
+		defer bar.Add(1)
+		if err := deleteKey(api, r.Key); err != nil {
+			results[i].Error = err.Error()
+			return fmt.Errorf("failed to delete API key %s: %w", r.Key, err)
+		}
+		results[i].Deleted = true
+		return nil
+	})
+	bar.Done()
+
+	if err := printResults(cmd, format, results, false); err != nil {
+		return err
+	}
+	return parallel.Summary(errs, "delete", "API keys")
+}
+
+// pickKeys returns the results of the keys which are left checked in a checklist of them, so that the keys to keep are
+// unchecked by hand.
+func pickKeys(p *prompt.Prompter, results []result) ([]result, error) {
+	options := make([]prompt.Option, len(results))
+	for i, r := range results {
+		detail := describeKey(r)
+		if r.Created != "" {
+			detail += fmt.Sprintf(", %d days old", r.AgeDays)
+		}
+		options[i] = prompt.Option{ID: r.Key, Name: r.Description, Detail: strings.TrimPrefix(detail, ", ")}
+	}
+	checked, err := p.Checklist("--interactive", "API keys to purge", options)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(results, func(r result) bool { return !slices.Contains(checked, r.Key) }), nil
+}
+
+// printResults prints what happened to the keys, or with --report-only, the keys which would be purged.
+func printResults(cmd *cobra.Command, format string, results []result, reportOnly bool) error {
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		return output.Print(out, format, results, nil)
+	case "csv":
+		return writeCSV(out, results)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No API keys found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if reportOnly {
+		fmt.Fprintln(tw, "Key\tOwner\tResource\tAge (Days)\tDescription")
+		for _, r := range results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Key, r.Owner, r.Resource, age(r), r.Description)
+		}
+		_ = tw.Flush()
+		fmt.Fprintf(out, "\n%d API keys would be purged.\n", len(results))
+		return nil
+	}
+
+	fmt.Fprintln(tw, "Key\tOwner\tResource\tAge (Days)\tDescription\tStatus")
+	deleted := 0
+	for _, r := range results {
+		if r.Deleted {
+			deleted++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Key, r.Owner, r.Resource, age(r), r.Description, r.status())
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(out, "\nDeleted %d of %d API keys.\n", deleted, len(results))
+	return nil
+}
+
+func writeCSV(w io.Writer, results []result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Key", "Description", "Owner", "Resource Type", "Resource", "Created", "Age (Days)", "Status"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := cw.Write([]string{r.Key, r.Description, r.Owner, r.ResourceType, r.Resource, r.Created, age(r), r.status()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r result) status() string {
+	switch {
+	case r.Deleted:
+		return "deleted"
+	case r.Error != "":
+		return "failed"
+	}
+	return ""
+}
+
+// age returns the age of the key in days, or nothing if its creation time isn't known.
+func age(r result) string {
+	if r.Created == "" {
+		return ""
+	}
+	return strconv.Itoa(r.AgeDays)
+}
+
+// filterKeys returns the keys for the type of resource, if any, which were created longer ago than olderThan, if it
+// isn't 0. Keys whose creation time is unknown are never old enough.
+func filterKeys(keys []cli.APIKey, resourceType string, olderThan time.Duration, now time.Time) []cli.APIKey {
+	var filtered []cli.APIKey
+	for _, k := range keys {
+		if resourceType != "" && !strings.EqualFold(k.ResourceType, resourceType) {
+			continue
+		}
+		if olderThan > 0 {
+			created, err := time.Parse(time.RFC3339, k.Created)
+			if err != nil || now.Sub(created) < olderThan {
+				continue
+			}
+		}
+		filtered = append(filtered, k)
+	}
+	return filtered
+}
+
+// orphanedKeys returns the keys whose owner is neither a user nor a service account of the organization, since it was
+// deleted. Those keys can't be used by anyone who is accountable for them, and are the first to purge.
+func orphanedKeys(keys []cli.APIKey) ([]cli.APIKey, error) {
+	var users []struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&users, "iam", "user", "list"); err != nil {
+		return nil, err
+	}
+	var serviceAccounts []struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&serviceAccounts, "iam", "service-account", "list"); err != nil {
+		return nil, err
+	}
+
+	exists := map[string]bool{}
+	for _, o := range append(users, serviceAccounts...) {
+		exists[o.ID] = true
+	}
+	var orphaned []cli.APIKey
+	for _, k := range keys {
+		if k.OwnerResourceID != "" && !exists[k.OwnerResourceID] {
+			orphaned = append(orphaned, k)
+		}
+	}
+	return orphaned, nil
+}
+
+// parseAge parses --older-than, which is a duration, or a number of days, such as 90d.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, exitcode.Invalid(`invalid --older-than "%s", expected a number of days such as 90d, or a duration such as 36h`, s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, exitcode.Invalid(`invalid --older-than "%s", expected a number of days such as 90d, or a duration such as 36h`, s)
+	}
+	return d, nil
+}
+
+// describeKey describes what the key is for, such as "of sa-123456 for lkc-123456".
+func describeKey(r result) string {
+	var words []string
+	if r.Owner != "" {
+		words = append(words, "of", r.Owner)
+	}
+	if r.Orphaned {
+		words = append(words, "(deleted)")
+	}
+	if r.Resource != "" {
+		words = append(words, "for", r.Resource)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/clitest"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+)
+
+func TestMain(m *testing.M) {
+	clitest.Main(m)
+}
+
+// onList scripts the API keys of env-123456: a Kafka key and a Schema Registry key of the same service account.
+func onList(fake *clitest.Fake) {
+	fake.On("api-key", "list", "--environment", "env-123456").Return([]cli.APIKey{
+		{Key: "KAFKAKEY", Description: "orders producer", OwnerResourceID: "sa-123456", ResourceType: "kafka", ResourceID: "lkc-123456", Created: "2024-01-02T03:04:05Z"},
+		{Key: "SRKEY", Description: "orders schemas", OwnerResourceID: "sa-123456", ResourceType: "schema-registry", ResourceID: "lsrc-123456", Created: "2024-01-02T03:04:05Z"},
+	})
+}
+
+// purged decodes the results of --output json, by key.
+func purged(t *testing.T, stdout string) map[string]result {
+	t.Helper()
+	var results []result
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("stdout isn't JSON: %v\n%s", err, stdout)
+	}
+	byKey := map[string]result{}
+	for _, r := range results {
+		byKey[r.Key] = r
+	}
+	return byKey
+}
+
+func TestPurgeDryRun(t *testing.T) {
+	fake := clitest.New(t)
+	onList(fake)
+
+	stdout, _, err := clitest.Execute(newCommand(), "--env", "env-123456", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := fake.Called("api-key", "delete"); n != 0 {
+		t.Errorf("deleted %d API keys in a dry run", n)
+	}
+	for _, key := range []string{"KAFKAKEY", "SRKEY"} {
+		if !strings.Contains(stdout, key) {
+			t.Errorf("the plan doesn't delete %s:\n%s", key, stdout)
+		}
+	}
+}
+
+func TestPurgeDeletes(t *testing.T) {
+	fake := clitest.New(t)
+	onList(fake)
+	fake.On("api-key", "delete")
+
+	stdout, _, err := clitest.Execute(newCommand(), "--env", "env-123456", "--resource-type", "kafka", "--force", "--output", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the key of the resource type is deleted, without a prompt since --force is passed.
+	if fake.Called("api-key", "delete", "KAFKAKEY", "--force") != 1 {
+		t.Error("the Kafka API key wasn't deleted")
+	}
+	if fake.Called("api-key", "delete", "SRKEY") != 0 {
+		t.Error("the Schema Registry API key was deleted, although --resource-type is kafka")
+	}
+	results := purged(t, stdout)
+	if len(results) != 1 || !results["KAFKAKEY"].Deleted {
+		t.Errorf("printed %v, want only KAFKAKEY, deleted", results)
+	}
+}
+
+func TestPurgeAsks(t *testing.T) {
+	fake := clitest.New(t)
+	onList(fake)
+
+	cmd := newCommand()
+	cmd.SetIn(strings.NewReader("n\n"))
+	if _, _, err := clitest.Execute(cmd, "--env", "env-123456"); err != nil {
+		t.Fatal(err)
+	}
+	if n := fake.Called("api-key", "delete"); n != 0 {
+		t.Errorf("deleted %d API keys, although the purge wasn't confirmed", n)
+	}
+}
+
+func TestPurgePartialFailure(t *testing.T) {
+	fake := clitest.New(t)
+	onList(fake)
+	fake.On("api-key", "delete", "SRKEY").Fail(`Error: API key "SRKEY" is protected`)
+	fake.On("api-key", "delete")
+
+	cmd := newCommand()
+	cmd.SetIn(strings.NewReader("y\n"))
+	stdout, _, err := clitest.Execute(cmd, "--env", "env-123456", "--output", "json")
+	if err == nil {
+		t.Fatal("the purge succeeded, although a key failed to delete")
+	}
+	if code := exitcode.Of(err); code != exitcode.PartialFailure {
+		t.Errorf("exit code %d, want %d, since one of the keys was deleted", code, exitcode.PartialFailure)
+	}
+
+	// Every key is attempted, and the results are printed despite the failure.
+	if fake.Called("api-key", "delete", "KAFKAKEY") != 1 {
+		t.Error("the Kafka API key wasn't deleted after the other key failed")
+	}
+	results := purged(t, stdout)
+	if !results["KAFKAKEY"].Deleted {
+		t.Errorf("KAFKAKEY wasn't printed as deleted: %v", results["KAFKAKEY"])
+	}
+	if r := results["SRKEY"]; r.Deleted || !strings.Contains(r.Error, "protected") {
+		t.Errorf("SRKEY wasn't printed as failed: %v", r)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newCleanupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cleanup",
+		Short:   "Delete rotated API keys whose grace period is over.",
+		Long:    "Delete API keys which were rotated by \"confluent api-key rotate\" and whose grace period is over. Run it on a schedule, such as a daily CI job, to finish rotations without waiting for them.",
+		Args:    cobra.NoArgs,
+		RunE:    cleanup,
+		Example: "confluent api-key rotate cleanup --service-account sa-123456 --dry-run",
+	}
+
+	cmd.Flags().String("resource", "", "Only delete keys for this resource.")
+	cmd.Flags().String("service-account", "", "Only delete keys of this service account.")
+	dryrun.AddFlag(cmd, "Print the keys which would be deleted without deleting them.")
+	output.AddFlag(cmd, "Format of the keys which were deleted")
+
+	return cmd
+}
+
+func cleanup(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	resource, err := cmd.Flags().GetString("resource")
+	cobra.CheckErr(err)
+
+	serviceAccount, err := cmd.Flags().GetString("service-account")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	keys, err := listKeys(resource, serviceAccount)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var expired []cli.APIKey
+	for _, k := range keys {
+		if at, ok := deleteAfter(k); ok && !at.After(now) {
+			expired = append(expired, k)
+		}
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, k := range expired {
+			plan.AddDetail("delete", "API key", k.Key, fmt.Sprintf("of %s for %s", k.OwnerResourceID, k.ResourceID))
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	var deleted dryrun.Plan
+	for _, k := range expired {
+		if err := deleteKey(k.Key); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, deleted))
+		}
+		fmt.Fprintf(output.Progress(cmd), "Deleted API key %s of %s for %s.\n", k.Key, k.OwnerResourceID, k.ResourceID)
+		deleted.AddDetail("delete", "API key", k.Key, fmt.Sprintf("of %s for %s", k.OwnerResourceID, k.ResourceID))
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %d API keys.\n", len(expired))
+	return dryrun.PrintApplied(cmd, deleted)
+}
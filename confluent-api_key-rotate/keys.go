@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// deleteAfterPattern matches the note added to the description of a rotated key, which says when it may be deleted.
+var deleteAfterPattern = regexp.MustCompile(`\s*\[rotated, delete after (\S+)\]$`)
+
+// deleteAfter returns when a rotated key may be deleted, and whether the key was rotated.
+func deleteAfter(k cli.APIKey) (time.Time, bool) {
+	m := deleteAfterPattern.FindStringSubmatch(k.Description)
+	if m == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func listKeys(resource, serviceAccount string) ([]cli.APIKey, error) {
+	args := []string{"api-key", "list"}
+	if resource != "" {
+		args = append(args, "--resource", resource)
+	}
+	if serviceAccount != "" {
+		args = append(args, "--service-account", serviceAccount)
+	}
+
+	var keys []cli.APIKey
+	if err := cli.JSON(&keys, args...); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func createKey(resource, serviceAccount, environment, description string) (cli.CreatedAPIKey, error) {
+	args := []string{"api-key", "create", "--resource", resource, "--service-account", serviceAccount}
+	if environment != "" {
+		args = append(args, "--environment", environment)
+	}
+	if description != "" {
+		args = append(args, "--description", description)
+	}
+
+	var key cli.CreatedAPIKey
+	if err := cli.JSON(&key, args...); err != nil {
+		return cli.CreatedAPIKey{}, err
+	}
+	if key.Key == "" || key.Secret == "" {
+		return cli.CreatedAPIKey{}, fmt.Errorf("confluent api-key create didn't return a key and secret")
+	}
+	return key, nil
+}
+
+// markKey notes in the key's description when it may be deleted, so that "confluent api-key rotate cleanup" can delete
+// it once the grace period is over.
+func markKey(k cli.APIKey, at time.Time) error {
+	description := strings.TrimSpace(deleteAfterPattern.ReplaceAllString(k.Description, ""))
+	description = strings.TrimSpace(fmt.Sprintf("%s [rotated, delete after %s]", description, at.UTC().Format(time.RFC3339)))
+	_, err := cli.Run("api-key", "update", k.Key, "--description", description)
+	return err
+}
+
+func deleteKey(key string) error {
+	_, err := cli.Run("api-key", "delete", key, "--force")
+	return err
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate the API key of a service account.",
+		Long:  "Create a new API key for a service account and resource, print it or write it to Vault or AWS Secrets Manager, and delete the service account's old keys for the resource after a grace period. Old keys are deleted by waiting with --wait, or later by running \"confluent api-key rotate cleanup\".",
+		Args:  cobra.NoArgs,
+		RunE:  rotate,
+		Example: `confluent api-key rotate --resource lkc-123456 --service-account sa-123456 --grace-period 0
+confluent api-key rotate --resource lkc-123456 --service-account sa-123456 --vault-path secret/data/orders-app`,
+	}
+
+	cmd.Flags().String("resource", "", "ID of the resource the API key is for, such as a Kafka cluster.")
+	cmd.Flags().String("service-account", "", "ID of the service account which owns the API key.")
+	cmd.Flags().String("environment", "", "ID of the resource's environment, for resources which need one.")
+	cmd.Flags().StringSlice("key", nil, "Old API keys to delete. Defaults to every other key of the service account for the resource.")
+	cmd.Flags().String("description", "", "Description of the new API key.")
+	cmd.Flags().Duration("grace-period", 24*time.Hour, "How long the old keys keep working. With 0, they're deleted immediately.")
+	cmd.Flags().Bool("wait", false, "Wait for the grace period to end, and then delete the old keys.")
+	dryrun.AddFlag(&cmd, "Print the key which would be created and the keys which would be deleted, without changing them.")
+	output.AddFlag(&cmd, "How to print the new key", "env")
+	cmd.Flags().String("vault-path", "", "API path of a Vault KV secret to write the new key to, instead of printing its secret.")
+	cmd.Flags().String("aws-secret-arn", "", "ARN of an AWS Secrets Manager secret to write the new key to, instead of printing its secret.")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("resource"))
+	cobra.CheckErr(cmd.MarkFlagRequired("service-account"))
+	cmd.MarkFlagsMutuallyExclusive("vault-path", "aws-secret-arn")
+
+	cmd.AddCommand(newCleanupCommand())
+
+	completion.Add(&cmd, "confluent-api_key-rotate")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-api_key-rotate"))
+
+	exitcode.Execute(&cmd)
+}
+
+func rotate(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	resource, err := cmd.Flags().GetString("resource")
+	cobra.CheckErr(err)
+
+	serviceAccount, err := cmd.Flags().GetString("service-account")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	oldKeys, err := cmd.Flags().GetStringSlice("key")
+	cobra.CheckErr(err)
+
+	description, err := cmd.Flags().GetString("description")
+	cobra.CheckErr(err)
+
+	gracePeriod, err := cmd.Flags().GetDuration("grace-period")
+	cobra.CheckErr(err)
+
+	wait, err := cmd.Flags().GetBool("wait")
+	cobra.CheckErr(err)
+
+	vaultPath, err := cmd.Flags().GetString("vault-path")
+	cobra.CheckErr(err)
+
+	awsSecretARN, err := cmd.Flags().GetString("aws-secret-arn")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if gracePeriod < 0 {
+		return exitcode.Invalid("--grace-period must not be negative")
+	}
+
+	existing, err := listKeys(resource, serviceAccount)
+	if err != nil {
+		return err
+	}
+
+	// Keys are checked before the new one is created, so that a typo doesn't leave an extra key behind.
+	// Keys which were already rotated keep the grace period they were given.
+	var old []cli.APIKey
+	for _, k := range existing {
+		if _, ok := deleteAfter(k); !ok {
+			old = append(old, k)
+		}
+	}
+	if len(oldKeys) > 0 {
+		byKey := map[string]cli.APIKey{}
+		for _, k := range existing {
+			byKey[k.Key] = k
+		}
+		old = nil
+		for _, key := range oldKeys {
+			k, ok := byKey[key]
+			if !ok {
+				return fmt.Errorf(`API key "%s" isn't a key of "%s" for "%s"`, key, serviceAccount, resource)
+			}
+			old = append(old, k)
+		}
+	}
+
+	if description == "" && len(old) == 1 {
+		description = deleteAfterPattern.ReplaceAllString(old[0].Description, "")
+	}
+
+	if dryRun {
+		return dryrun.Print(cmd, plan(resource, serviceAccount, old, gracePeriod, wait, vaultPath, awsSecretARN))
+	}
+
+	key, err := createKey(resource, serviceAccount, environment, description)
+	if err != nil {
+		return err
+	}
+
+	// The new key must be stored before the old ones are scheduled for deletion, or its secret would be lost.
+	stored := storedKey{Key: key.Key, Secret: key.Secret}
+	switch {
+	case vaultPath != "":
+		if err := writeVault(vaultPath, stored); err != nil {
+			return fmt.Errorf("created API key %s, but %w", key.Key, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote API key %s to Vault at \"%s\".\n", key.Key, vaultPath)
+	case awsSecretARN != "":
+		if err := writeAWS(context.Background(), awsSecretARN, stored); err != nil {
+			return fmt.Errorf("created API key %s, but %w", key.Key, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote API key %s to \"%s\".\n", key.Key, awsSecretARN)
+	default:
+		if err := printKey(cmd, format, stored); err != nil {
+			return err
+		}
+	}
+
+	if len(old) == 0 {
+		return nil
+	}
+
+	if gracePeriod > 0 {
+		at := time.Now().Add(gracePeriod)
+		for _, k := range old {
+			if err := markKey(k, at); err != nil {
+				return err
+			}
+		}
+		if !wait {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Marked %d old API keys for deletion after %s. Run \"confluent api-key rotate cleanup\" to delete them.\n", len(old), at.UTC().Format(time.RFC3339))
+			return nil
+		}
+
+		fmt.Fprintf(cmd.ErrOrStderr(), "Waiting %s to delete %d old API keys.\n", gracePeriod, len(old))
+		time.Sleep(gracePeriod)
+	}
+
+	for _, k := range old {
+		if err := deleteKey(k.Key); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Deleted API key %s.\n", k.Key)
+	}
+	return nil
+}
+
+// plan is what a rotation would do: create the new key, store it, and delete the old keys, now or once the grace period
+// is over.
+func plan(resource, serviceAccount string, old []cli.APIKey, gracePeriod time.Duration, wait bool, vaultPath, awsSecretARN string) dryrun.Plan {
+	var p dryrun.Plan
+	p.AddDetail("create", "API key", "", fmt.Sprintf("of %s for %s", serviceAccount, resource))
+	switch {
+	case vaultPath != "":
+		p.AddDetail("write", "the new API key", "", fmt.Sprintf(`to Vault at "%s"`, vaultPath))
+	case awsSecretARN != "":
+		p.AddDetail("write", "the new API key", "", fmt.Sprintf(`to "%s"`, awsSecretARN))
+	}
+
+	for _, k := range old {
+		switch {
+		case gracePeriod == 0:
+			p.Add("delete", "API key", k.Key)
+		case wait:
+			p.AddDetail("delete", "API key", k.Key, "after "+gracePeriod.String())
+		default:
+			p.AddDetail("mark", "API key", k.Key, "for deletion after "+gracePeriod.String())
+		}
+	}
+	return p
+}
+
+func printKey(cmd *cobra.Command, format string, key storedKey) error {
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		return output.Print(out, format, key, nil)
+	case "env":
+		fmt.Fprintf(out, "API_KEY=%s\nAPI_SECRET=%s\n", key.Key, key.Secret)
+	default:
+		fmt.Fprintf(out, "API Key:    %s\nAPI Secret: %s\n", key.Key, key.Secret)
+	}
+	return nil
+}
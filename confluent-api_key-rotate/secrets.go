@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// storedKey is the secret written to a secrets manager.
+type storedKey struct {
+	Key    string `json:"api_key"`
+	Secret string `json:"api_secret"`
+}
+
+// writeVault writes the key to a KV secret, using the same environment variables as the Vault CLI. The path is the
+// secret's API path, which includes "data/" for version 2 of the KV secrets engine, e.g. "secret/data/orders-app".
+func writeVault(path string, key storedKey) error {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return fmt.Errorf("VAULT_ADDR must be set to write the API key to Vault")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return fmt.Errorf("VAULT_TOKEN must be set to write the API key to Vault")
+	}
+
+	// Version 2 of the KV secrets engine nests the secret's data, so that it can be written alongside options.
+	var body any = key
+	if strings.Contains("/"+strings.Trim(path, "/")+"/", "/data/") {
+		body = map[string]any{"data": key}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	req.Header.Set("Content-Type", "application/json")
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write the API key to Vault: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		var body struct {
+			Errors []string `json:"errors"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&body); err == nil && len(body.Errors) > 0 {
+			return fmt.Errorf(`failed to write "%s" to Vault: %s`, path, strings.Join(body.Errors, ", "))
+		}
+		return fmt.Errorf(`failed to write "%s" to Vault: %s`, path, res.Status)
+	}
+
+	return nil
+}
+
+// writeAWS writes the key as a new version of an AWS Secrets Manager secret, using the default AWS credential chain.
+// The region is taken from the ARN.
+func writeAWS(ctx context.Context, secretARN string, key storedKey) error {
+	parsed, err := arn.Parse(secretARN)
+	if err != nil {
+		return fmt.Errorf(`invalid AWS ARN "%s": %w`, secretARN, err)
+	}
+	if parsed.Service != "secretsmanager" {
+		return fmt.Errorf(`unsupported AWS service "%s", the ARN must be a Secrets Manager secret`, parsed.Service)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(parsed.Region))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	b, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := secretsmanager.NewFromConfig(cfg).PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretARN),
+		SecretString: aws.String(string(b)),
+	}); err != nil {
+		return fmt.Errorf("failed to write the API key to AWS Secrets Manager: %w", err)
+	}
+
+	return nil
+}
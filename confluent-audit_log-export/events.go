@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// event is the part of an audit log event, a CloudEvent, which it's filtered by. Events are exported as they were
+// logged, not as they're decoded.
+type event struct {
+	Type string `json:"type"`
+	Data struct {
+		MethodName         string `json:"methodName"`
+		AuthenticationInfo struct {
+			Principal string `json:"principal"`
+		} `json:"authenticationInfo"`
+	} `json:"data"`
+}
+
+// filter matches events by their principal, type, and method. Empty lists match every event.
+type filter struct {
+	principals []string
+	types      []string
+	methods    []string
+}
+
+func (f filter) match(value []byte) bool {
+	var e event
+	if err := json.Unmarshal(value, &e); err != nil {
+		return false
+	}
+
+	// Principals are logged as "User:<ID>", but are matched with or without the prefix.
+	principal := e.Data.AuthenticationInfo.Principal
+	if len(f.principals) > 0 && !slices.Contains(f.principals, principal) && !slices.Contains(f.principals, strings.TrimPrefix(principal, "User:")) {
+		return false
+	}
+	if len(f.types) > 0 && !slices.Contains(f.types, e.Type) {
+		return false
+	}
+	if len(f.methods) > 0 && !slices.Contains(f.methods, e.Data.MethodName) {
+		return false
+	}
+	return true
+}
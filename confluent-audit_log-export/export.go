@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// exporter exports the events of the audit log topic which are in the time range and match the filter. The partitions
+// are exported one after another, so events are in order within a partition, but not across them.
+type exporter struct {
+	client    *kafka.Client
+	topic     string
+	start     int64
+	end       int64
+	filter    filter
+	sink      sink
+	batchSize int
+	log       io.Writer
+}
+
+func (e exporter) export() (int, error) {
+	partitions, err := e.client.Metadata(e.topic)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, p := range partitions {
+		n, err := e.exportPartition(p)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("failed to export partition %d: %w", p.ID, err)
+		}
+	}
+	return total, nil
+}
+
+// exportPartition exports the events from the start of the time range to its end, or to the end of the partition when
+// the export started, whichever is first.
+func (e exporter) exportPartition(p kafka.Partition) (int, error) {
+	start := int64(kafka.EarliestOffset)
+	if e.start > 0 {
+		start = e.start
+	}
+	offset, err := e.client.Offset(e.topic, p, start)
+	if err != nil {
+		return 0, err
+	}
+	end, err := e.client.Offset(e.topic, p, kafka.LatestOffset)
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		// No event is as new as the start of the time range.
+		return 0, nil
+	}
+
+	exported := 0
+	var batch [][]byte
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := e.sink.write(batch); err != nil {
+			return err
+		}
+		exported += len(batch)
+		batch = nil
+		return nil
+	}
+
+	for offset < end {
+		records, next, err := e.client.Fetch(e.topic, p, offset)
+		if err != nil {
+			return exported, err
+		}
+		if next <= offset {
+			break
+		}
+		offset = next
+
+		for _, r := range records {
+			if r.Offset >= end || e.end > 0 && r.Timestamp > e.end {
+				offset = end
+				break
+			}
+			if !e.filter.match(r.Value) {
+				continue
+			}
+			batch = append(batch, r.Value)
+			if len(batch) >= e.batchSize {
+				if err := flush(); err != nil {
+					return exported, err
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return exported, err
+	}
+
+	fmt.Fprintf(e.log, "Partition %d: exported %d events.\n", p.ID, exported)
+	return exported, nil
+}
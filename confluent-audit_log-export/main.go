@@ -0,0 +1,177 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "export",
+		Short: "Export audit log events to JSON Lines or a webhook.",
+		Long:  "Consume the audit log topic of the organization, and export the events which match the filters and time range to a JSON Lines file, or post them to a webhook, such as the HTTP collector of a SIEM.",
+		Args:  cobra.NoArgs,
+		RunE:  export,
+		Example: `confluent audit-log export --api-key ABCDEFGHIJKLMNOP --start 2024-05-01T00:00:00Z --file audit.jsonl
+confluent audit-log export --api-key ABCDEFGHIJKLMNOP --principal sa-123456 --method kafka.CreateTopics,kafka.DeleteTopics
+confluent audit-log export --api-key ABCDEFGHIJKLMNOP --webhook https://siem.example.com/collector --webhook-header "Authorization=Bearer $TOKEN"`,
+	}
+
+	cmd.Flags().String("api-key", "", "Kafka API key of the audit log cluster.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the audit log cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the audit log cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().String("start", "", "RFC 3339 timestamp of the oldest event to export. Defaults to the oldest event in the audit log.")
+	cmd.Flags().String("end", "", "RFC 3339 timestamp of the newest event to export. Defaults to when the export starts.")
+	cmd.Flags().StringSlice("principal", nil, "Only export the events of these principals, such as u-123456 or sa-123456.")
+	cmd.Flags().StringSlice("event-type", nil, "Only export events of these types, such as io.confluent.kafka.server/authorization.")
+	cmd.Flags().StringSlice("method", nil, "Only export events of these methods, such as kafka.CreateTopics.")
+	cmd.Flags().String("file", "", "File to write the events to as JSON Lines. Defaults to stdout.")
+	cmd.Flags().String("webhook", "", "URL to post the events to as JSON Lines, instead of writing them to a file.")
+	cmd.Flags().StringToString("webhook-header", nil, `Headers of the webhook requests, as "<name>=<value>" pairs.`)
+	cmd.Flags().Int("batch-size", 500, "How many events to post to the webhook at once.")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("api-key"))
+	cmd.MarkFlagsMutuallyExclusive("file", "webhook")
+
+	completion.Add(&cmd, "confluent-audit_log-export")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-audit_log-export"))
+
+	exitcode.Execute(&cmd)
+}
+
+func export(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	startFlag, err := cmd.Flags().GetString("start")
+	cobra.CheckErr(err)
+
+	endFlag, err := cmd.Flags().GetString("end")
+	cobra.CheckErr(err)
+
+	principals, err := cmd.Flags().GetStringSlice("principal")
+	cobra.CheckErr(err)
+
+	types, err := cmd.Flags().GetStringSlice("event-type")
+	cobra.CheckErr(err)
+
+	methods, err := cmd.Flags().GetStringSlice("method")
+	cobra.CheckErr(err)
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	webhookURL, err := cmd.Flags().GetString("webhook")
+	cobra.CheckErr(err)
+
+	webhookHeaders, err := cmd.Flags().GetStringToString("webhook-header")
+	cobra.CheckErr(err)
+
+	batchSize, err := cmd.Flags().GetInt("batch-size")
+	cobra.CheckErr(err)
+
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if apiSecret == "" {
+		return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required")
+	}
+	if batchSize < 1 {
+		return exitcode.Invalid("--batch-size must be at least 1")
+	}
+
+	var start, end int64
+	for _, t := range []struct {
+		flag, value string
+		millis      *int64
+	}{{"start", startFlag, &start}, {"end", endFlag, &end}} {
+		if t.value == "" {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return fmt.Errorf(`invalid --%s "%s", expected an RFC 3339 timestamp such as 2024-05-01T00:00:00Z`, t.flag, t.value)
+		}
+		*t.millis = ts.UnixMilli()
+	}
+	if start > 0 && end > 0 && start >= end {
+		return exitcode.Invalid("--start must be before --end")
+	}
+
+	var auditLog struct {
+		ClusterID     string `json:"cluster_id"`
+		EnvironmentID string `json:"environment_id"`
+		TopicName     string `json:"topic_name"`
+	}
+	if err := cli.JSON(&auditLog, "audit-log", "describe"); err != nil {
+		return err
+	}
+
+	if bootstrap == "" {
+		var cluster cli.KafkaCluster
+		if err := cli.JSON(&cluster, "kafka", "cluster", "describe", auditLog.ClusterID, "--environment", auditLog.EnvironmentID); err != nil {
+			return fmt.Errorf("failed to find the bootstrap server of the audit log cluster, pass it with --bootstrap: %w", err)
+		}
+		bootstrap = cluster.Endpoint
+	}
+
+	var s sink
+	switch {
+	case webhookURL != "":
+		s = newWebhook(webhookURL, webhookHeaders)
+	case file != "":
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		s = lines{w: f}
+	default:
+		s = lines{w: cmd.OutOrStdout()}
+	}
+
+	client := kafka.NewClient("confluent-audit_log-export", bootstrap, apiKey, apiSecret)
+	defer client.Close()
+
+	e := exporter{
+		client:    client,
+		topic:     auditLog.TopicName,
+		start:     start,
+		end:       end,
+		filter:    filter{principals: principals, types: types, methods: methods},
+		sink:      s,
+		batchSize: batchSize,
+		log:       cmd.ErrOrStderr(),
+	}
+	total, err := e.export()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d events from %s.\n", total, auditLog.TopicName)
+	return nil
+}
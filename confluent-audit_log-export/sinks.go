@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// A sink is where the exported events are written, a batch at a time.
+type sink interface {
+	write(events [][]byte) error
+}
+
+// lines writes the events as JSON Lines.
+type lines struct {
+	w io.Writer
+}
+
+func (l lines) write(events [][]byte) error {
+	for _, e := range events {
+		if _, err := l.w.Write(append(bytes.TrimSpace(e), '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhook posts each batch of events as JSON Lines, such as to the HTTP collector of a SIEM.
+type webhook struct {
+	url     string
+	headers http.Header
+	client  *http.Client
+}
+
+func newWebhook(url string, headers map[string]string) webhook {
+	h := http.Header{}
+	h.Set("Content-Type", "application/x-ndjson")
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+	return webhook{url: url, headers: h, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (w webhook) write(events [][]byte) error {
+	var body bytes.Buffer
+	if err := (lines{w: &body}).write(events); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header = w.headers.Clone()
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post events to the webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("failed to post events to the webhook: %s: %s", res.Status, bytes.TrimSpace(b))
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"golang.org/x/oauth2/google"
+)
+
+// gcpKMSURL is the URL of the Cloud KMS API, which keys are read from with their resource names.
+const gcpKMSURL = "https://cloudkms.googleapis.com/v1/"
+
+// A rotation is the status of a key in its cloud provider's key management service.
+type rotation struct {
+	// Enabled is whether the key is rotated automatically, which is unknown if it couldn't be read.
+	Enabled *bool `json:"enabled"`
+	// Period is how often the key is rotated, as an ISO 8601 duration, such as P90D, if the provider reports it.
+	Period string `json:"period,omitempty"`
+	// State is the state of the key in the provider, such as Enabled, Disabled, or PendingDeletion.
+	State string `json:"state,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// A checker reads the rotation status of keys from AWS KMS, Azure Key Vault, and Google Cloud KMS, with the default
+// credentials of each, such as from the environment. The credentials of each provider are only loaded once.
+type checker struct {
+	ctx context.Context
+
+	mu        sync.Mutex
+	azureCred azcore.TokenCredential
+	gcpClient *http.Client
+}
+
+func (c *checker) check(provider, key string) rotation {
+	var (
+		r   rotation
+		err error
+	)
+	switch strings.ToUpper(provider) {
+	case "AWS":
+		r, err = c.aws(key)
+	case "AZURE":
+		r, err = c.azure(key)
+	case "GCP":
+		r, err = c.gcp(key)
+	default:
+		err = fmt.Errorf(`unsupported provider "%s"`, provider)
+	}
+	if err != nil {
+		return rotation{Error: err.Error()}
+	}
+	return r
+}
+
+// aws reads a KMS key, whose ARN has its region.
+func (c *checker) aws(key string) (rotation, error) {
+	parsed, err := arn.Parse(key)
+	if err != nil {
+		return rotation{}, fmt.Errorf(`invalid AWS ARN "%s": %w`, key, err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(c.ctx, config.WithRegion(parsed.Region))
+	if err != nil {
+		return rotation{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	client := kms.NewFromConfig(cfg)
+
+	status, err := client.GetKeyRotationStatus(c.ctx, &kms.GetKeyRotationStatusInput{KeyId: aws.String(key)})
+	if err != nil {
+		return rotation{}, fmt.Errorf("failed to read the rotation status from AWS KMS: %w", err)
+	}
+	described, err := client.DescribeKey(c.ctx, &kms.DescribeKeyInput{KeyId: aws.String(key)})
+	if err != nil {
+		return rotation{}, fmt.Errorf("failed to describe the key in AWS KMS: %w", err)
+	}
+
+	return rotation{Enabled: &status.KeyRotationEnabled, State: string(described.KeyMetadata.KeyState)}, nil
+}
+
+// azure reads a Key Vault key, whose ID is its URL, e.g. https://example.vault.azure.net/keys/confluent/0123abcd.
+func (c *checker) azure(key string) (rotation, error) {
+	u, err := url.Parse(key)
+	if err != nil {
+		return rotation{}, fmt.Errorf(`invalid Azure key ID "%s": %w`, key, err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "keys" {
+		return rotation{}, fmt.Errorf(`invalid Azure key ID "%s", expected e.g. "https://example.vault.azure.net/keys/confluent/0123abcd"`, key)
+	}
+
+	c.mu.Lock()
+	if c.azureCred == nil {
+		if c.azureCred, err = azidentity.NewDefaultAzureCredential(nil); err != nil {
+			c.mu.Unlock()
+			return rotation{}, fmt.Errorf("failed to load Azure credentials: %w", err)
+		}
+	}
+	cred := c.azureCred
+	c.mu.Unlock()
+
+	client, err := azkeys.NewClient(u.Scheme+"://"+u.Host, cred, nil)
+	if err != nil {
+		return rotation{}, err
+	}
+
+	policy, err := client.GetKeyRotationPolicy(c.ctx, parts[1], nil)
+	if err != nil {
+		return rotation{}, fmt.Errorf("failed to read the rotation policy from Azure Key Vault: %w", err)
+	}
+	got, err := client.GetKey(c.ctx, parts[1], "", nil)
+	if err != nil {
+		return rotation{}, fmt.Errorf("failed to read the key from Azure Key Vault: %w", err)
+	}
+
+	r := rotation{Enabled: new(bool), State: "Disabled"}
+	if a := got.Attributes; a != nil && a.Enabled != nil && *a.Enabled {
+		r.State = "Enabled"
+	}
+	for _, action := range policy.LifetimeActions {
+		if action.Action == nil || action.Action.Type == nil || *action.Action.Type != azkeys.KeyRotationPolicyActionRotate {
+			continue
+		}
+		*r.Enabled = true
+		if action.Trigger != nil && action.Trigger.TimeAfterCreate != nil {
+			r.Period = *action.Trigger.TimeAfterCreate
+		}
+	}
+	return r, nil
+}
+
+// gcp reads a Cloud KMS key, whose ID is its resource name, e.g.
+// projects/example/locations/us/keyRings/confluent/cryptoKeys/confluent.
+func (c *checker) gcp(key string) (rotation, error) {
+	if !strings.HasPrefix(key, "projects/") || !strings.Contains(key, "/cryptoKeys/") {
+		return rotation{}, fmt.Errorf(`invalid GCP key "%s", expected e.g. "projects/example/locations/us/keyRings/confluent/cryptoKeys/confluent"`, key)
+	}
+
+	c.mu.Lock()
+	if c.gcpClient == nil {
+		client, err := google.DefaultClient(c.ctx, "https://www.googleapis.com/auth/cloudkms")
+		if err != nil {
+			c.mu.Unlock()
+			return rotation{}, fmt.Errorf("failed to load Google Cloud credentials: %w", err)
+		}
+		c.gcpClient = client
+	}
+	client := c.gcpClient
+	c.mu.Unlock()
+
+	res, err := client.Get(gcpKMSURL + key)
+	if err != nil {
+		return rotation{}, fmt.Errorf("failed to read the key from Google Cloud KMS: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		RotationPeriod string `json:"rotationPeriod"`
+		Primary        struct {
+			State string `json:"state"`
+		} `json:"primary"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil && res.StatusCode == http.StatusOK {
+		return rotation{}, fmt.Errorf("failed to decode the Google Cloud KMS response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		if body.Error.Message != "" {
+			return rotation{}, fmt.Errorf("failed to read the key from Google Cloud KMS: %s", body.Error.Message)
+		}
+		return rotation{}, fmt.Errorf("failed to read the key from Google Cloud KMS: %s", res.Status)
+	}
+
+	// The rotation period is a number of seconds, such as "7776000s", which is reported in days, like those of Azure.
+	r := rotation{Enabled: new(bool), State: body.Primary.State}
+	if body.RotationPeriod != "" {
+		*r.Enabled = true
+		r.Period = body.RotationPeriod
+		if seconds, err := strconv.ParseInt(strings.TrimSuffix(body.RotationPeriod, "s"), 10, 64); err == nil && seconds%86400 == 0 {
+			r.Period = fmt.Sprintf("P%dD", seconds/86400)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// byokKey is a key in the output of "confluent byok list".
+type byokKey struct {
+	ID        string    `json:"id"`
+	Key       string    `json:"key"`
+	Provider  string    `json:"provider"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// entry is a key in the report, with the clusters which are encrypted with it.
+type entry struct {
+	ID       string    `json:"id"`
+	Provider string    `json:"provider"`
+	Key      string    `json:"key"`
+	State    string    `json:"state"`
+	Created  time.Time `json:"created"`
+	AgeDays  int       `json:"age_days"`
+	Clusters []string  `json:"clusters"`
+	Rotation *rotation `json:"rotation,omitempty"`
+}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "audit",
+		Short: "Audit the self-managed encryption keys of the organization.",
+		Long:  "Report every self-managed encryption key (BYOK) registered in the organization, with the Kafka clusters which are encrypted with it and its age. With the credentials of the key's cloud provider, such as from the environment, its automatic rotation status and state in AWS KMS, Azure Key Vault, or Google Cloud KMS are reported too.",
+		Args:  cobra.NoArgs,
+		RunE:  audit,
+		Example: `confluent byok audit
+confluent byok audit --no-cloud --output csv > byok.csv`,
+	}
+
+	cmd.Flags().Bool("no-cloud", false, "Don't read the rotation status of the keys from their cloud providers.")
+	output.AddFlag(&cmd, "Format of the report", "csv")
+	cmd.Flags().Int("parallelism", 8, "How many clusters or keys to describe at once.")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-byok-audit")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-byok-audit"))
+
+	exitcode.Execute(&cmd)
+}
+
+func audit(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	noCloud, err := cmd.Flags().GetBool("no-cloud")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	var keys []byokKey
+	if err := cli.JSON(&keys, "byok", "list"); err != nil {
+		return err
+	}
+
+	clusters, err := encryptedClusters(parallelism)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entries := make([]entry, len(keys))
+	for i, k := range keys {
+		entries[i] = entry{
+			ID:       k.ID,
+			Provider: k.Provider,
+			Key:      k.Key,
+			State:    k.State,
+			Created:  k.CreatedAt,
+			AgeDays:  int(now.Sub(k.CreatedAt).Hours() / 24),
+			Clusters: clusters[k.ID],
+		}
+		if entries[i].Clusters == nil {
+			entries[i].Clusters = []string{}
+		}
+		sort.Strings(entries[i].Clusters)
+	}
+	// The oldest keys are the most overdue for review, so they're listed first.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Created.Before(entries[j].Created) })
+
+	if !noCloud {
+		c := &checker{ctx: context.Background()}
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, parallelism)
+		)
+		for i := range entries {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(e *entry) {
+				defer func() { <-sem; wg.Done() }()
+				r := c.check(e.Provider, e.Key)
+				e.Rotation = &r
+			}(&entries[i])
+		}
+		wg.Wait()
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		if entries == nil {
+			entries = []entry{}
+		}
+		return output.Print(out, format, entries, nil)
+	case "csv":
+		return writeCSV(out, entries)
+	default:
+		print(out, entries)
+		if !noCloud {
+			for _, e := range entries {
+				if e.Rotation.Error != "" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Failed to read the rotation status of %s: %s\n", e.ID, e.Rotation.Error)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// encryptedClusters lists the Kafka clusters in every environment which are encrypted with a self-managed key, by key
+// ID. Only dedicated clusters can be, so only they are described.
+func encryptedClusters(parallelism int) (map[string][]string, error) {
+	var environments []cli.Environment
+	if err := cli.JSON(&environments, "environment", "list"); err != nil {
+		return nil, err
+	}
+
+	type cluster struct {
+		id, environment string
+	}
+	var dedicated []cluster
+	for _, e := range environments {
+		var listed []cli.KafkaCluster
+		if err := cli.JSON(&listed, "kafka", "cluster", "list", "--environment", e.ID); err != nil {
+			return nil, err
+		}
+		for _, c := range listed {
+			if strings.EqualFold(c.Type, "DEDICATED") {
+				dedicated = append(dedicated, cluster{id: c.ID, environment: e.ID})
+			}
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sem      = make(chan struct{}, parallelism)
+		errs     = make([]error, len(dedicated))
+		clusters = map[string][]string{}
+	)
+	for i, c := range dedicated {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, c cluster) {
+			defer func() { <-sem; wg.Done() }()
+
+			var described cli.KafkaCluster
+			if err := cli.JSON(&described, "kafka", "cluster", "describe", c.id, "--environment", c.environment); err != nil {
+				errs[i] = err
+				return
+			}
+			if described.ByokID == "" {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			clusters[described.ByokID] = append(clusters[described.ByokID], c.id)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clusters, nil
+}
+
+var columns = []string{"ID", "Provider", "State", "Created", "Age (Days)", "Clusters", "Rotation", "Cloud State", "Key"}
+
+func (e entry) row() []string {
+	rotation, state := "-", "-"
+	if r := e.Rotation; r != nil {
+		switch {
+		case r.Enabled == nil:
+			rotation = "unknown"
+		case *r.Enabled && r.Period != "":
+			rotation = "enabled (" + r.Period + ")"
+		case *r.Enabled:
+			rotation = "enabled"
+		default:
+			rotation = "disabled"
+		}
+		if r.State != "" {
+			state = r.State
+		}
+	}
+	created := ""
+	if !e.Created.IsZero() {
+		created = e.Created.UTC().Format(time.RFC3339)
+	}
+	return []string{e.ID, e.Provider, e.State, created, strconv.Itoa(e.AgeDays), strings.Join(e.Clusters, ","), rotation, state, e.Key}
+}
+
+func writeCSV(w io.Writer, entries []entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write(e.row()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func print(w io.Writer, entries []entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No self-managed keys found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+
+	unused, unrotated := 0, 0
+	for _, e := range entries {
+		fmt.Fprintln(tw, strings.Join(e.row(), "\t"))
+		if len(e.Clusters) == 0 {
+			unused++
+		}
+		if e.Rotation != nil && e.Rotation.Enabled != nil && !*e.Rotation.Enabled {
+			unrotated++
+		}
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%d keys, %d not used by any cluster, %d not rotated automatically.\n", len(entries), unused, unrotated)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// Sources of certificates.
+const (
+	sourceCertificateAuthority = "certificate-authority"
+	sourceIdentityProvider     = "identity-provider"
+	sourceFile                 = "file"
+	sourceEndpoint             = "endpoint"
+)
+
+// A certificate is a certificate which expires, and where it was found.
+type certificate struct {
+	Source string `json:"source"`
+	// Name is the name of the certificate authority or identity provider, or the file or endpoint.
+	Name    string    `json:"name"`
+	ID      string    `json:"id,omitempty"`
+	Subject string    `json:"subject,omitempty"`
+	Serial  string    `json:"serial_number,omitempty"`
+	Expires time.Time `json:"expires"`
+}
+
+// certificateAuthority is a certificate authority in the output of "confluent iam certificate-authority list", which
+// has the expiration date and serial number of each certificate of its chain.
+type certificateAuthority struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	ExpirationDates []string `json:"expiration_dates"`
+	SerialNumbers   []string `json:"serial_numbers"`
+}
+
+// identityProvider is a provider in the output of "confluent iam provider list".
+type identityProvider struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// certificateAuthorities returns the certificates of the chains of the organization's certificate authorities, which
+// are the identity providers of mTLS.
+func certificateAuthorities() ([]certificate, error) {
+	var authorities []certificateAuthority
+	if err := cli.JSON(&authorities, "iam", "certificate-authority", "list"); err != nil {
+		return nil, err
+	}
+
+	var certs []certificate
+	for _, a := range authorities {
+		for i, date := range a.ExpirationDates {
+			expires, err := parseTime(date)
+			if err != nil {
+				return nil, fmt.Errorf(`invalid expiration date "%s" of certificate authority "%s"`, date, a.Name)
+			}
+			c := certificate{Source: sourceCertificateAuthority, Name: a.Name, ID: a.ID, Expires: expires}
+			if i < len(a.SerialNumbers) {
+				c.Serial = a.SerialNumbers[i]
+			}
+			certs = append(certs, c)
+		}
+	}
+	return certs, nil
+}
+
+// timeLayouts are the layouts which the CLI may print times in.
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05 -0700 MST", time.DateTime, time.DateOnly}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`invalid time "%s"`, s)
+}
+
+// identityProviders returns the certificates of the keys of the organization's OIDC identity providers, which are in
+// the x5c parameter of the keys of their JWKS. Keys without certificates don't expire, so they're left out.
+func identityProviders(client *http.Client) ([]certificate, []error) {
+	var providers []identityProvider
+	if err := cli.JSON(&providers, "iam", "provider", "list"); err != nil {
+		return nil, []error{err}
+	}
+
+	var certs []certificate
+	var errs []error
+	for _, p := range providers {
+		if p.JWKSURI == "" {
+			continue
+		}
+		keys, err := jwksCertificates(client, p.JWKSURI)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`failed to read the keys of identity provider "%s": %w`, p.Name, err))
+			continue
+		}
+		for _, k := range keys {
+			certs = append(certs, fromX509(sourceIdentityProvider, p.Name, p.ID, k))
+		}
+	}
+	return certs, errs
+}
+
+func jwksCertificates(client *http.Client, uri string) ([]*x509.Certificate, error) {
+	res, err := client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", uri, res.Status)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			X5C []string `json:"x5c"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", uri, err)
+	}
+
+	// Only the first certificate of each chain is the key's, the others are of the authorities which issued it.
+	var certs []*x509.Certificate
+	for _, k := range jwks.Keys {
+		if len(k.X5C) == 0 {
+			continue
+		}
+		der, err := base64.StdEncoding.DecodeString(k.X5C[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %w", uri, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %w", uri, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// files returns the certificates of PEM files, such as those of mTLS clients.
+func files(paths []string) ([]certificate, error) {
+	var certs []certificate
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for block, rest := pem.Decode(b); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate in %s: %w", path, err)
+			}
+			certs = append(certs, fromX509(sourceFile, path, "", cert))
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("%s has no PEM certificates", path)
+		}
+	}
+	return certs, nil
+}
+
+// endpoints returns the certificates which TLS endpoints serve, such as those of custom domains and proxies. The
+// certificates aren't verified, so that an expired one is reported rather than failing.
+func endpoints(addrs []string, timeout time.Duration) ([]certificate, []error) {
+	var certs []certificate
+	var errs []error
+	for _, addr := range addrs {
+		if !strings.Contains(addr, ":") {
+			addr += ":443"
+		}
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`invalid endpoint "%s": %w`, addr, err))
+			continue
+		}
+
+		dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: timeout}, Config: &tls.Config{ServerName: host, InsecureSkipVerify: true}}
+		conn, err := dialer.Dial("tcp", addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to connect to %s: %w", addr, err))
+			continue
+		}
+		for _, cert := range conn.(*tls.Conn).ConnectionState().PeerCertificates {
+			certs = append(certs, fromX509(sourceEndpoint, addr, "", cert))
+		}
+		_ = conn.Close()
+	}
+	return certs, errs
+}
+
+func fromX509(source, name, id string, cert *x509.Certificate) certificate {
+	return certificate{
+		Source:  source,
+		Name:    name,
+		ID:      id,
+		Subject: cert.Subject.String(),
+		Serial:  fmt.Sprintf("%X", cert.SerialNumber),
+		Expires: cert.NotAfter.UTC(),
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errExpiring fails the plugin with exitcode.Findings when a certificate expires within the warning threshold, so that
+// alerts fire.
+var errExpiring = exitcode.New(exitcode.Findings, "some certificates are expired or expire soon")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "check",
+		Short: "Report when the organization's certificates expire.",
+		Long:  "Scan the certificate authorities of mTLS, the keys of the OIDC identity providers, and custom certificates in PEM files or served by TLS endpoints, and report how many days each has until it expires, exiting with code 2 if any expire within the warning threshold, such as to alert before clients fail to authenticate.",
+		Args:  cobra.NoArgs,
+		RunE:  check,
+		Example: `confluent cert expiry check
+confluent cert expiry check --warn-days 60 --certificate client.pem --endpoint kafka.example.com:9092 --output json`,
+	}
+
+	cmd.Flags().Int("warn-days", 30, "Warn about certificates which expire within this many days.")
+	cmd.Flags().StringSlice("skip", nil, "Sources of the organization to not scan: certificate-authorities, identity-providers.")
+	cmd.Flags().StringSlice("certificate", nil, "PEM files of custom certificates to check.")
+	cmd.Flags().StringSlice("endpoint", nil, `TLS endpoints to check the certificates of, as "<host>:<port>". The port defaults to 443.`)
+	cmd.Flags().Duration("timeout", 10*time.Second, "How long to wait for each JWKS and endpoint.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-cert-expiry-check")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-cert-expiry-check"))
+
+	exitcode.Execute(&cmd)
+}
+
+// A checked certificate is a certificate, with how long it has until it expires.
+type checkedCertificate struct {
+	certificate
+	DaysLeft int    `json:"days_left"`
+	Status   string `json:"status"`
+}
+
+func check(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	warnDays, err := cmd.Flags().GetInt("warn-days")
+	cobra.CheckErr(err)
+
+	skip, err := cmd.Flags().GetStringSlice("skip")
+	cobra.CheckErr(err)
+
+	paths, err := cmd.Flags().GetStringSlice("certificate")
+	cobra.CheckErr(err)
+
+	addrs, err := cmd.Flags().GetStringSlice("endpoint")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if warnDays < 0 {
+		return exitcode.Invalid("--warn-days must not be negative")
+	}
+	for _, s := range skip {
+		if s != "certificate-authorities" && s != "identity-providers" {
+			return fmt.Errorf(`unsupported source "%s", supported sources: certificate-authorities, identity-providers`, s)
+		}
+	}
+
+	// The certificates of each file are read first, so that a typo fails before the organization is scanned.
+	certs, err := files(paths)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if !slices.Contains(skip, "certificate-authorities") {
+		authorities, err := certificateAuthorities()
+		if err != nil {
+			return err
+		}
+		certs = append(certs, authorities...)
+	}
+	if !slices.Contains(skip, "identity-providers") {
+		providers, providerErrs := identityProviders(&http.Client{Timeout: timeout})
+		certs = append(certs, providers...)
+		errs = append(errs, providerErrs...)
+	}
+	served, endpointErrs := endpoints(addrs, timeout)
+	certs = append(certs, served...)
+	errs = append(errs, endpointErrs...)
+
+	now := time.Now()
+	checked := make([]checkedCertificate, len(certs))
+	expiring := 0
+	for i, c := range certs {
+		checked[i] = checkedCertificate{certificate: c, DaysLeft: int(math.Floor(c.Expires.Sub(now).Hours() / 24)), Status: "ok"}
+		switch {
+		case !c.Expires.After(now):
+			checked[i].Status = "expired"
+		case checked[i].DaysLeft < warnDays:
+			checked[i].Status = "expiring"
+		default:
+			continue
+		}
+		expiring++
+	}
+	sort.SliceStable(checked, func(i, j int) bool { return checked[i].Expires.Before(checked[j].Expires) })
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, checked, func() { print(out, checked, expiring, warnDays) }); err != nil {
+		return err
+	}
+
+	// A source which couldn't be scanned may hide an expiring certificate, so it fails the check too.
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if expiring > 0 {
+		return errExpiring
+	}
+	return nil
+}
+
+func print(w io.Writer, checked []checkedCertificate, expiring, warnDays int) {
+	if len(checked) == 0 {
+		fmt.Fprintln(w, "No certificates found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Source\tName\tSubject\tSerial Number\tExpires\tDays Left\tStatus")
+	for _, c := range checked {
+		subject, serial := c.Subject, c.Serial
+		if subject == "" {
+			subject = "-"
+		}
+		if serial == "" {
+			serial = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", c.Source, c.Name, subject, serial, c.Expires.UTC().Format(time.DateOnly), c.DaysLeft, c.Status)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%d of %d certificates are expired or expire within %d days.\n", expiring, len(checked), warnDays)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Units of the dimensions, which they're printed in.
+const (
+	bytesUnit   = "bytes"
+	countUnit   = "count"
+	rateUnit    = "rate"
+	percentUnit = "percent"
+)
+
+// A dimension of a Dedicated cluster's capacity, and the metric it's measured by. The capacities are the guidelines
+// per CKU of the Confluent Cloud documentation. delta is whether the metric's value is a count in each time bucket,
+// rather than a gauge, so that it's divided by the bucket's length into a rate per second. limit is whether the
+// capacity is a hard limit, which the peak is sized for, rather than a percentile.
+type dimension struct {
+	name   string
+	metric string
+	unit   string
+	perCKU float64
+	delta  bool
+	limit  bool
+}
+
+var dimensions = []dimension{
+	{name: "Ingress", metric: "io.confluent.kafka.server/received_bytes", unit: bytesUnit, perCKU: 60e6, delta: true},
+	{name: "Egress", metric: "io.confluent.kafka.server/sent_bytes", unit: bytesUnit, perCKU: 180e6, delta: true},
+	{name: "Partitions", metric: "io.confluent.kafka.server/partition_count", unit: countUnit, perCKU: 4500, limit: true},
+	{name: "Connections", metric: "io.confluent.kafka.server/active_connection_count", unit: countUnit, perCKU: 18000, limit: true},
+	{name: "Requests", metric: "io.confluent.kafka.server/request_count", unit: rateUnit, perCKU: 15000, delta: true},
+	// The cluster load is a fraction of the cluster's current capacity, rather than of a CKU's.
+	{name: "Cluster load", metric: "io.confluent.kafka.server/cluster_load_percent", unit: percentUnit},
+}
+
+// A usage is how much of a dimension the cluster used over the window, and how many CKUs it needs for it.
+type usage struct {
+	Dimension      string  `json:"dimension"`
+	Peak           float64 `json:"peak"`
+	Percentile     float64 `json:"percentile"`
+	CapacityPerCKU float64 `json:"capacity_per_cku,omitempty"`
+	// Utilization is the value which the CKUs are sized for, as a fraction of the cluster's current capacity.
+	Utilization float64 `json:"utilization"`
+	CKUsNeeded  int     `json:"ckus_needed"`
+	NoData      bool    `json:"no_data,omitempty"`
+
+	unit string
+}
+
+// A report of a cluster's usage, and the CKUs it's recommended to have.
+type report struct {
+	Cluster         string    `json:"cluster"`
+	Availability    string    `json:"availability"`
+	CKUs            int       `json:"ckus"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	Percentile      float64   `json:"percentile"`
+	Headroom        float64   `json:"headroom"`
+	Usage           []usage   `json:"usage"`
+	RecommendedCKUs int       `json:"recommended_ckus"`
+	Action          string    `json:"action"`
+}
+
+// measure returns the usage of the dimension, from the metric's value in each time bucket of the granularity.
+func measure(d dimension, points []point, bucket time.Duration, ckus int, percentile, headroom float64) usage {
+	u := usage{Dimension: d.name, CapacityPerCKU: d.perCKU, unit: d.unit}
+	if len(points) == 0 {
+		u.NoData = true
+		return u
+	}
+
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+		if d.delta {
+			values[i] /= bucket.Seconds()
+		}
+	}
+	sort.Float64s(values)
+	u.Peak = values[len(values)-1]
+	u.Percentile = values[int(math.Ceil(percentile/100*float64(len(values))))-1]
+
+	sized := u.Percentile
+	if d.limit {
+		sized = u.Peak
+	}
+
+	// The CKUs are sized so that the value uses at most 1 - headroom of their capacity.
+	var needed float64
+	if d.perCKU == 0 {
+		u.Utilization = sized
+		needed = sized * float64(ckus) / (1 - headroom)
+	} else {
+		u.Utilization = sized / (d.perCKU * float64(ckus))
+		needed = sized / (d.perCKU * (1 - headroom))
+	}
+	u.CKUsNeeded = int(math.Ceil(needed))
+	return u
+}
+
+// recommend returns the CKUs which the cluster needs for the dimension which needs the most, and at least the minimum
+// of its availability, and whether to expand or shrink to them.
+func recommend(usages []usage, ckus int, multiZone bool) (int, string) {
+	recommended := 1
+	if multiZone {
+		recommended = 2
+	}
+	for _, u := range usages {
+		recommended = max(recommended, u.CKUsNeeded)
+	}
+
+	switch {
+	case recommended > ckus:
+		return recommended, "expand"
+	case recommended < ckus:
+		return recommended, "shrink"
+	default:
+		return recommended, "keep"
+	}
+}
+
+func (u usage) format(v float64) string {
+	switch u.unit {
+	case bytesUnit:
+		return fmt.Sprintf("%.1f MB/s", v/1e6)
+	case rateUnit:
+		return fmt.Sprintf("%.0f/s", v)
+	case percentUnit:
+		return fmt.Sprintf("%.0f%%", v*100)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+	"github.com/spf13/cobra"
+)
+
+// granularities are the sizes of the time buckets which the Metrics API supports, short enough to find peaks in.
+var granularities = map[string]time.Duration{
+	"PT1M":  time.Minute,
+	"PT5M":  5 * time.Minute,
+	"PT15M": 15 * time.Minute,
+	"PT30M": 30 * time.Minute,
+	"PT1H":  time.Hour,
+}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "advisor",
+		Short: "Recommend how many CKUs a Dedicated cluster needs.",
+		Long:  "Query the Metrics API for the ingress, egress, partitions, connections, requests, and load of a Dedicated Kafka cluster over a lookback window, compare them with the capacity of a CKU, and recommend whether to expand or shrink the cluster, with the numbers behind it.",
+		Args:  cobra.NoArgs,
+		RunE:  advise,
+		Example: `confluent cku advisor --cluster lkc-123456
+confluent cku advisor --cluster lkc-123456 --window 720h --percentile 99 --headroom 0.4 --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().Duration("window", 7*24*time.Hour, "Length of the lookback window.")
+	cmd.Flags().String("granularity", "PT5M", "Size of the time buckets: PT1M, PT5M, PT15M, PT30M, or PT1H.")
+	cmd.Flags().Float64("percentile", 95, "Percentile of the time buckets which the throughput, requests, and load are sized for. Partitions and connections are sized for their peak, since their capacity is a hard limit.")
+	cmd.Flags().Float64("headroom", 0.3, "Fraction of the capacity to keep free, such as for spikes and failures.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().String("metrics-api-key", "", "Cloud API key to query the Metrics API with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("metrics-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-cku-advisor")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-cku-advisor"))
+
+	exitcode.Execute(&cmd)
+}
+
+func advise(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	window, err := cmd.Flags().GetDuration("window")
+	cobra.CheckErr(err)
+
+	granularity, err := cmd.Flags().GetString("granularity")
+	cobra.CheckErr(err)
+
+	percentile, err := cmd.Flags().GetFloat64("percentile")
+	cobra.CheckErr(err)
+
+	headroom, err := cmd.Flags().GetFloat64("headroom")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("metrics-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("metrics-api-secret")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	bucket, ok := granularities[granularity]
+	if !ok {
+		return fmt.Errorf(`unsupported granularity "%s", supported granularities: PT1M, PT5M, PT15M, PT30M, PT1H`, granularity)
+	}
+	if window < bucket {
+		return exitcode.Invalid("--window must be at least as long as --granularity")
+	}
+	if percentile <= 0 || percentile > 100 {
+		return exitcode.Invalid("--percentile must be more than 0 and at most 100")
+	}
+	if headroom < 0 || headroom >= 1 {
+		return exitcode.Invalid("--headroom must be at least 0 and less than 1")
+	}
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if key == "" || secret == "" {
+		return exitcode.Invalid("a Cloud API key is required to query the Metrics API, pass --metrics-api-key and --metrics-api-secret or set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	cluster, err := cli.DescribeKafkaCluster(clusterID, environment)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(cluster.Type, "DEDICATED") || cluster.ClusterSize < 1 {
+		return fmt.Errorf(`cluster "%s" is a %s cluster, only Dedicated clusters have CKUs`, cluster.ID, strings.ToLower(cluster.Type))
+	}
+
+	end := time.Now().UTC().Truncate(bucket)
+	start := end.Add(-window)
+	q := querier{
+		cluster:     cluster.ID,
+		interval:    fmt.Sprintf("%s/%s", start.Format(time.RFC3339), end.Format(time.RFC3339)),
+		granularity: granularity,
+		key:         key,
+		secret:      secret,
+		client:      retry.Client(30 * time.Second),
+	}
+
+	r := report{
+		Cluster:      cluster.ID,
+		Availability: cluster.Availability,
+		CKUs:         cluster.ClusterSize,
+		Start:        start,
+		End:          end,
+		Percentile:   percentile,
+		Headroom:     headroom,
+		Usage:        []usage{},
+	}
+	for _, d := range dimensions {
+		points, err := q.query(d.metric)
+		if err != nil {
+			return err
+		}
+		r.Usage = append(r.Usage, measure(d, points, bucket, cluster.ClusterSize, percentile, headroom))
+	}
+	multiZone := strings.Contains(strings.ToLower(cluster.Availability), "multi")
+	r.RecommendedCKUs, r.Action = recommend(r.Usage, cluster.ClusterSize, multiZone)
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		return output.Print(out, format, r, nil)
+	}
+	print(out, r)
+	return nil
+}
+
+func print(w io.Writer, r report) {
+	availability := ""
+	if r.Availability != "" {
+		availability = r.Availability + ", "
+	}
+	fmt.Fprintf(w, "Cluster %s (%s%d CKUs), from %s to %s:\n\n", r.Cluster, availability, r.CKUs, r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Dimension\tPeak\tp%g\tCapacity per CKU\tUtilization\tCKUs Needed\n", r.Percentile)
+	for _, u := range r.Usage {
+		if u.NoData {
+			fmt.Fprintf(tw, "%s\tno data\t\t\t\t\n", u.Dimension)
+			continue
+		}
+		capacity := "-"
+		if u.CapacityPerCKU > 0 {
+			capacity = u.format(u.CapacityPerCKU)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.0f%%\t%d\n", u.Dimension, u.format(u.Peak), u.format(u.Percentile), capacity, u.Utilization*100, u.CKUsNeeded)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintln(w)
+	switch r.Action {
+	case "expand":
+		fmt.Fprintf(w, "Expand from %d to %d CKUs, to keep %.0f%% of the capacity free.\n", r.CKUs, r.RecommendedCKUs, r.Headroom*100)
+	case "shrink":
+		fmt.Fprintf(w, "Shrink from %d to %d CKUs, which still keeps %.0f%% of the capacity free.\n", r.CKUs, r.RecommendedCKUs, r.Headroom*100)
+	default:
+		fmt.Fprintf(w, "Keep %d CKUs.\n", r.CKUs)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+)
+
+const metricsURL = "https://api.telemetry.confluent.cloud/v2/metrics/cloud/query"
+
+type point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+type querier struct {
+	cluster     string
+	interval    string
+	granularity string
+	key         string
+	secret      string
+	client      *http.Client
+}
+
+// query returns the metric's value in each time bucket, following the pages of the response.
+func (q querier) query(metric string) ([]point, error) {
+	return paging.All(func(token string) ([]point, string, error) {
+		body := map[string]any{
+			"aggregations": []map[string]string{{"metric": metric}},
+			"filter":       map[string]any{"field": "resource.kafka.id", "op": "EQ", "value": q.cluster},
+			"granularity":  q.granularity,
+			"intervals":    []string{q.interval},
+			"limit":        1000,
+		}
+		url := paging.WithToken(metricsURL, token)
+
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(q.key, q.secret)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := q.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var page struct {
+			paging.MetricsPage[point]
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			var details []string
+			for _, e := range page.Errors {
+				details = append(details, e.Detail)
+			}
+			if len(details) > 0 {
+				return nil, "", fmt.Errorf("failed to query %s: %s: %s", metric, res.Status, strings.Join(details, "; "))
+			}
+			return nil, "", fmt.Errorf("failed to query %s: %s", metric, res.Status)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", metric, err)
+		}
+
+		return page.Data, page.Next(), nil
+	})
+}
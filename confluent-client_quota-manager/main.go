@@ -0,0 +1,151 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "manager <spec>",
+		Short: "Reconcile Kafka client quotas with a spec.",
+		Long:  "Reconcile the client quotas of a Kafka cluster with a YAML spec of the ingress and egress throughputs of each principal: create the quotas of the principals which have none, update those which differ, and give principals which share a quota with others and need different throughputs a quota of their own. The current and desired quotas are reported, and with --dry-run, the changes are printed without making them.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  manage,
+		Example: `confluent client-quota manager quotas.yaml --cluster lkc-123456 --dry-run
+confluent client-quota manager quotas.yaml --cluster lkc-123456 --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	dryrun.AddFlag(&cmd, "Print the changes without making them.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Int("parallelism", 8, "How many quotas to create or update at once.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-client_quota-manager")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-client_quota-manager"))
+
+	exitcode.Execute(&cmd)
+}
+
+func manage(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	wanted, err := readSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	var quotas []clientQuota
+	if err := cli.JSON(&quotas, append([]string{"kafka", "quota", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	changes := plan(wanted, quotas)
+	if dryRun {
+		var p dryrun.Plan
+		for _, c := range changes {
+			c.addTo(&p)
+		}
+		return dryrun.Print(cmd, p)
+	}
+	apply(changes, scope, parallelism)
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, changes, func() { print(out, changes) }); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range changes {
+		if c.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to change the quotas of %d principals", failed)
+	}
+	return nil
+}
+
+func print(w io.Writer, changes []change) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Principal\tQuota\tCurrent Ingress\tDesired Ingress\tCurrent Egress\tDesired Egress\tAction\tResult")
+	counts := map[string]int{}
+	for _, c := range changes {
+		result := ""
+		switch {
+		case c.Action == "none":
+		case c.Done:
+			result = "done"
+			counts[c.Action]++
+		case c.Error != "":
+			result = "failed: " + c.Error
+		}
+		quota := c.Quota
+		if quota == "" {
+			quota = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Principal, quota, formatRate(c.CurrentIngress), formatRate(c.DesiredIngress), formatRate(c.CurrentEgress), formatRate(c.DesiredEgress), c.Action, result)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nCreated %d quotas, updated %d, and moved %d principals to quotas of their own.\n", counts["create"], counts["update"], counts["move"])
+}
+
+// formatRate formats a throughput in decimal units, as Confluent Cloud bills them.
+func formatRate(r *int64) string {
+	if r == nil {
+		return "-"
+	}
+	b := float64(*r)
+	units := []string{"B/s", "KB/s", "MB/s", "GB/s"}
+	i := 0
+	for b >= 1000 && i < len(units)-1 {
+		b /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
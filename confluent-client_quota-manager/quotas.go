@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+)
+
+// managedDescription is the description of the quotas which the plugin creates.
+const managedDescription = "Managed by confluent client-quota manager."
+
+// clientQuota is a quota in the output of "confluent kafka quota list".
+type clientQuota struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"display_name"`
+	Ingress    rate       `json:"ingress"`
+	Egress     rate       `json:"egress"`
+	Principals principals `json:"principals"`
+}
+
+// rate is a throughput in bytes per second, which the CLI may print as a string or a number, and which is empty for
+// no limit.
+type rate struct {
+	value *int64
+}
+
+func (r *rate) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf(`invalid throughput "%s"`, s)
+	}
+	r.value = &v
+	return nil
+}
+
+// principals are the principals of a quota, which the CLI may print as a list or a comma-separated string.
+type principals []string
+
+func (p *principals) UnmarshalJSON(b []byte) error {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		*p = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	for _, principal := range strings.Split(s, ",") {
+		if principal = strings.TrimSpace(principal); principal != "" {
+			*p = append(*p, principal)
+		}
+	}
+	return nil
+}
+
+// A change reconciles the quota of a principal with the spec:
+//   - "create" creates a quota for the principal, which has none.
+//   - "update" changes the throughputs of the principal's quota, which only applies to it.
+//   - "move" removes the principal from a quota which it shares with other principals, and creates one of its own.
+type change struct {
+	Principal      string `json:"principal"`
+	Quota          string `json:"quota,omitempty"`
+	CurrentIngress *int64 `json:"current_ingress"`
+	CurrentEgress  *int64 `json:"current_egress"`
+	DesiredIngress *int64 `json:"desired_ingress"`
+	DesiredEgress  *int64 `json:"desired_egress"`
+	Action         string `json:"action"`
+	Done           bool   `json:"done"`
+	Error          string `json:"error,omitempty"`
+}
+
+// plan compares the quotas of the principals with the spec, in the order of the principals.
+func plan(wanted map[string]limits, quotas []clientQuota) []change {
+	byPrincipal := map[string]clientQuota{}
+	for _, q := range quotas {
+		for _, p := range q.Principals {
+			byPrincipal[p] = q
+		}
+	}
+
+	names := make([]string, 0, len(wanted))
+	for principal := range wanted {
+		names = append(names, principal)
+	}
+	sort.Strings(names)
+
+	changes := make([]change, len(names))
+	for i, principal := range names {
+		w := wanted[principal]
+		c := change{Principal: principal, DesiredIngress: w.ingress, DesiredEgress: w.egress, Action: "create"}
+		if q, ok := byPrincipal[principal]; ok {
+			c.Quota = q.ID
+			c.CurrentIngress, c.CurrentEgress = q.Ingress.value, q.Egress.value
+			switch {
+			case matches(c.CurrentIngress, c.DesiredIngress) && matches(c.CurrentEgress, c.DesiredEgress):
+				c.Action = "none"
+			case len(q.Principals) == 1:
+				c.Action = "update"
+			default:
+				c.Action = "move"
+			}
+		}
+		changes[i] = c
+	}
+	return changes
+}
+
+// matches reports whether a current throughput is the desired one, which it always is if the spec leaves it out.
+func matches(current, desired *int64) bool {
+	if desired == nil {
+		return true
+	}
+	return current != nil && *current == *desired
+}
+
+// apply makes the changes in parallel, recording whether each was made.
+func apply(changes []change, scope []string, parallelism int) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, parallelism)
+	)
+	for i := range changes {
+		if changes[i].Action == "none" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c *change) {
+			defer func() { <-sem; wg.Done() }()
+			if err := c.apply(scope); err != nil {
+				c.Error = err.Error()
+				return
+			}
+			c.Done = true
+		}(&changes[i])
+	}
+	wg.Wait()
+}
+
+func (c *change) apply(scope []string) error {
+	if c.Action == "update" {
+		_, err := cli.Run(append(append([]string{"kafka", "quota", "update", c.Quota}, c.limitFlags()...), scope...)...)
+		return err
+	}
+
+	if c.Action == "move" {
+		if _, err := cli.Run(append([]string{"kafka", "quota", "update", c.Quota, "--remove-principals", c.Principal}, scope...)...); err != nil {
+			return err
+		}
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	args := []string{"kafka", "quota", "create", "--name", c.Principal, "--description", managedDescription, "--principals", c.Principal}
+	if err := cli.JSON(&created, append(append(args, c.limitFlags()...), scope...)...); err != nil {
+		return err
+	}
+	c.Quota = created.ID
+	return nil
+}
+
+// addTo adds the change to a dry run's plan.
+func (c *change) addTo(p *dryrun.Plan) {
+	ingress, egress := c.limits()
+	switch c.Action {
+	case "create":
+		p.AddDetail("create", "client quota", c.Principal, "with "+describeLimits(ingress, egress))
+	case "update":
+		p.AddDetail("update", "client quota", c.Quota, fmt.Sprintf("of %s to %s", c.Principal, describeLimits(ingress, egress)))
+	case "move":
+		p.AddDetail("move", "principal", c.Principal, fmt.Sprintf(`from client quota "%s" to a quota of its own with %s`, c.Quota, describeLimits(ingress, egress)))
+	}
+}
+
+func describeLimits(ingress, egress *int64) string {
+	var limits []string
+	if ingress != nil {
+		limits = append(limits, "ingress "+formatRate(ingress))
+	}
+	if egress != nil {
+		limits = append(limits, "egress "+formatRate(egress))
+	}
+	return strings.Join(limits, " and ")
+}
+
+// limits returns the throughputs of the quota once the change is made. A quota which is moved keeps the other
+// throughput of the quota it was moved from.
+func (c *change) limits() (ingress, egress *int64) {
+	ingress, egress = c.DesiredIngress, c.DesiredEgress
+	if c.Action == "move" {
+		if ingress == nil {
+			ingress = c.CurrentIngress
+		}
+		if egress == nil {
+			egress = c.CurrentEgress
+		}
+	}
+	return ingress, egress
+}
+
+// limitFlags returns the throughput flags of "confluent kafka quota create" or "update", for the throughputs in the
+// spec.
+func (c *change) limitFlags() []string {
+	ingress, egress := c.limits()
+
+	var args []string
+	if ingress != nil {
+		args = append(args, "--ingress", strconv.FormatInt(*ingress, 10))
+	}
+	if egress != nil {
+		args = append(args, "--egress", strconv.FormatInt(*egress, 10))
+	}
+	return args
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rateUnits are the decimal units of throughputs in a spec, as Confluent Cloud bills them.
+var rateUnits = map[string]int64{"": 1, "B": 1, "KB": 1000, "MB": 1000 * 1000, "GB": 1000 * 1000 * 1000}
+
+// rateFormat matches a throughput such as "10MB", "1.5 GB/s", or "1048576".
+var rateFormat = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([KMG]?B)?(?:/S)?$`)
+
+// A spec maps principals to the client quotas which should apply to them, e.g.:
+//
+//	quotas:
+//	  sa-123456:
+//	    ingress: 10MB
+//	    egress: 20MB
+//	  <default>:
+//	    ingress: 1MB
+//
+// The "<default>" principal is the quota of every principal without one of its own. Either throughput may be left out,
+// to leave it as it is, or for a new quota, unlimited.
+type spec struct {
+	Quotas map[string]quotaSpec `yaml:"quotas"`
+}
+
+type quotaSpec struct {
+	Ingress string `yaml:"ingress"`
+	Egress  string `yaml:"egress"`
+}
+
+// limits are the throughputs of a quota in bytes per second, which are nil if the spec leaves them out.
+type limits struct {
+	ingress *int64
+	egress  *int64
+}
+
+func readSpec(path string) (map[string]limits, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the spec: %w", err)
+	}
+
+	var s spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf(`failed to parse the spec "%s": %w`, path, err)
+	}
+	if len(s.Quotas) == 0 {
+		return nil, fmt.Errorf(`the spec "%s" has no quotas`, path)
+	}
+
+	wanted := map[string]limits{}
+	for principal, q := range s.Quotas {
+		if q.Ingress == "" && q.Egress == "" {
+			return nil, fmt.Errorf(`the quota of "%s" needs an ingress or egress throughput`, principal)
+		}
+		var l limits
+		if l.ingress, err = parseRate(q.Ingress); err != nil {
+			return nil, fmt.Errorf(`invalid ingress of "%s": %w`, principal, err)
+		}
+		if l.egress, err = parseRate(q.Egress); err != nil {
+			return nil, fmt.Errorf(`invalid egress of "%s": %w`, principal, err)
+		}
+		wanted[principal] = l
+	}
+	return wanted, nil
+}
+
+// parseRate parses a throughput in bytes per second, or returns nil if it's empty.
+func parseRate(s string) (*int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m := rateFormat.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return nil, fmt.Errorf(`"%s" is not a throughput, such as 1048576, 500KB, or 10MB`, s)
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil, err
+	}
+	rate := int64(value * float64(rateUnits[m[2]]))
+	if rate <= 0 {
+		return nil, fmt.Errorf(`"%s" must be at least 1 byte per second`, s)
+	}
+	return &rate, nil
+}
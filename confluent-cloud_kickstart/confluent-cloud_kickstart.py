@@ -18,13 +18,203 @@ import json
 from pathlib import Path
 from datetime import datetime
 import os
+import re
+import signal
+import sys
+import time
+import urllib.request
+
+
+# The resources which the plugin created, with the confluent CLI arguments which delete them, which are printed if it
+# fails or is interrupted, so that none are left behind unnoticed.
+created = []
+
+# The files of the runs, one per name, which record the resources that each run created, and the files that it wrote,
+# so that --teardown can delete them.
+RUNS_DIR = os.path.join(Path.home(), '.confluent', 'cloud-kickstart')
+
+
+def run_file(name):
+    return os.path.join(RUNS_DIR, re.sub(r'[^\w.-]', '_', name) + '.json')
+
+
+def read_run(name):
+    path = run_file(name)
+    if not os.path.exists(path):
+        return {'name': name, 'resources': [], 'files': []}
+    with open(path, encoding='utf-8') as f:
+        return json.load(f)
+
+
+def write_run(run):
+    os.makedirs(RUNS_DIR, exist_ok=True)
+    path = run_file(run['name'])
+    with open(path + '.new', 'w', encoding='utf-8') as f:
+        json.dump(run, f, indent=2)
+    os.replace(path + '.new', path)
+
+
+def record(kind, resource_id, description, delete_args):
+    """Records a resource which was created as soon as it is, with the confluent CLI arguments which delete it, so that
+    a run which failed or was interrupted can be torn down, or resumed. Runs with the same name add to the same record."""
+    created.append((description, delete_args))
+    run = read_run(args.name)
+    run['resources'].append({'kind': kind, 'id': resource_id, 'description': description, 'delete': delete_args})
+    write_run(run)
+
+
+def record_file(file_name, resource_id=None):
+    """Records a file which was written, and that it holds the credentials of the API key with the ID, if any, so that a
+    re-run can use the key again."""
+    run = read_run(args.name)
+    if file_name not in run['files']:
+        run['files'].append(file_name)
+    for resource in run['resources']:
+        if resource_id is not None and resource.get('id') == resource_id:
+            resource['file'] = file_name
+    write_run(run)
+
+
+def recorded(kind):
+    """Returns the last resource of the kind which the runs with the name created, if any."""
+    for resource in reversed(read_run(args.name)['resources']):
+        if resource.get('kind') == kind:
+            return resource
+    return None
+
+
+def report_created(verb):
+    if not created:
+        return
+    print(f'\nThe plugin {verb} after creating these resources:', file=sys.stderr)
+    for description, _ in created:
+        print(f'  {description}', file=sys.stderr)
+    print('Delete them with:', file=sys.stderr)
+    for _, delete_args in reversed(created):
+        print('  confluent ' + ' '.join(delete_args), file=sys.stderr)
+    print(f'Or with: confluent cloud-kickstart --teardown --name "{args.name}"', file=sys.stderr)
+
+
+def teardown(name, yes, non_interactive, dry_run):
+    """Deletes the resources which the runs with the name created, last first, since later ones depend on earlier ones,
+    and the files which they wrote. A resource which was already deleted is skipped. The ones which couldn't be deleted
+    stay recorded, so that tearing down again retries them. Returns what was deleted, or with dry_run would be, as the
+    actions of the Go plugins' --dry-run, for -o json or yaml."""
+    if not os.path.exists(run_file(name)):
+        parser.error(f'no run named "{name}" is recorded in {RUNS_DIR}')
+    run = read_run(name)
+    resources = list(reversed(run['resources']))
+    files = [f for f in run['files'] if os.path.exists(f)]
+
+    print(f'Tearing down the run "{name}", which created:')
+    for resource in resources:
+        print(f'  {resource["description"]}')
+    for file_name in files:
+        print(f'  {file_name}')
+    if dry_run:
+        for resource in resources:
+            print(f'Would run: confluent {" ".join(resource["delete"])}')
+        for file_name in files:
+            print(f'Would remove: {file_name}')
+        return [deleted_action(resource) for resource in resources] + [removed_action(f) for f in files]
+
+    if yes:
+        answer = 'y'
+    elif non_interactive:
+        parser.error(f'not asking whether to tear down the run "{name}" with --non-interactive, pass --yes to confirm')
+    else:
+        answer = input('Are you sure you want to delete them? y|n  ')
+    if answer != 'y':
+        print('Quitting and leaving them in place')
+        return []
+
+    failed = []
+    done = []
+    for resource in resources:
+        print(f'Deleting {resource["description"]}')
+        results = subprocess.run(['confluent'] + resource['delete'], capture_output=True)
+        stderr = str(results.stderr, 'UTF-8')
+        if results.returncode != 0 and exit_code(stderr) != 4:
+            print(stderr.strip(), file=sys.stderr)
+            failed.append((resource, exit_code(stderr)))
+        else:
+            done.append(deleted_action(resource))
+    for file_name in files:
+        print(f'Removing {file_name}')
+        os.remove(file_name)
+        done.append(removed_action(file_name))
+
+    if failed:
+        run['resources'] = [resource for resource, _ in reversed(failed)]
+        run['files'] = []
+        write_run(run)
+        print(f'\nFailed to delete {len(failed)} of {len(resources)} resources, tear down again to retry:', file=sys.stderr)
+        for resource, _ in failed:
+            print(f'  {resource["description"]}', file=sys.stderr)
+        print_document({'dry_run': False, 'actions': done})
+        exit(failed[0][1])
+    os.remove(run_file(name))
+    print(f'Tore down the run "{name}".')
+    return done
+
+
+def deleted_action(resource):
+    return {'action': 'delete', 'resource': resource['kind'], 'name': resource['id'], 'detail': resource['description']}
+
+
+def removed_action(file_name):
+    return {'action': 'remove', 'resource': 'file', 'name': file_name}
+
+
+def print_document(document):
+    """Prints the result of the run with -o json or yaml, as the only output on stdout, and nothing with -o table."""
+    if args.output == 'json':
+        json.dump(document, result_out, indent=2)
+        print(file=result_out, flush=True)
+    elif args.output == 'yaml':
+        import yaml
+        yaml.safe_dump(document, result_out, sort_keys=False)
+        result_out.flush()
+
+
+def interrupted(signum, frame):
+    report_created('was interrupted')
+    sys.exit(130)
+
+
+# The exit codes of the Go plugins for the failures of the confluent CLI, found in its error, so that scripts can react
+# to the failures of this plugin as to theirs. They're documented in the README.
+EXIT_CODES = [
+    (6, re.compile(r'too many requests|rate.?limit|throttl|\b429\b', re.I)),
+    (3, re.compile(r'not logged in|must be logged in|log in|unauthori[sz]ed|forbidden|\b40[13]\b|permission denied|'
+                   r'not authorized|authentication failed|invalid (api key|credentials)|token (is )?expired', re.I)),
+    (5, re.compile(r'quota|exceeds? (the |your )?(limit|maximum)|limit (exceeded|reached)', re.I)),
+    (4, re.compile(r'not found|\b404\b|does not exist|doesn\'t exist', re.I)),
+]
+EXIT_VALIDATION = 7
+
+
+def exit_code(stderr):
+    for code, pattern in EXIT_CODES:
+        if pattern.search(stderr):
+            return code
+    return 1
+
+
+class ArgumentParser(argparse.ArgumentParser):
+    # Invalid arguments exit with the exit code of the Go plugins, rather than with argparse's 2, which theirs use for
+    # checks which found problems.
+    def error(self, message):
+        self.print_usage(sys.stderr)
+        self.exit(EXIT_VALIDATION, f'{self.prog}: error: {message}\n')
 
 
 def cli(cmd_args, print_output, capture_output=True, fmt_json=True):
     results = subprocess.run(cmd_args, capture_output=capture_output)
     if results.returncode != 0:
-        print(str(results.stderr, 'UTF-8'))
-        exit(results.returncode)
+        print(str(results.stderr, 'UTF-8'), file=sys.stderr)
+        report_created('failed')
+        exit(exit_code(str(results.stderr, 'UTF-8')))
 
     if capture_output:
         if fmt_json:
@@ -38,8 +228,45 @@ def cli(cmd_args, print_output, capture_output=True, fmt_json=True):
         return final_result
 
 
-def write_to_file(file_name, text, json_fmt=True):
+def try_cli(cmd_args, print_output):
+    """Runs a command which describes a resource as cli does, but returns None rather than failing if it isn't found."""
+    results = subprocess.run(cmd_args, capture_output=True)
+    if results.returncode != 0 and exit_code(str(results.stderr, 'UTF-8')) == 4:
+        return None
+    if results.returncode != 0:
+        print(str(results.stderr, 'UTF-8'), file=sys.stderr)
+        report_created('failed')
+        exit(exit_code(str(results.stderr, 'UTF-8')))
+    final_result = json.loads(results.stdout)
+    if print_output:
+        print("Debug: %s" % final_result)
+    return final_result
+
+
+def resume_cluster(debug):
+    """Returns the Kafka cluster which an earlier run with the name created, if it still exists."""
+    resource = recorded('kafka-cluster')
+    if resource is None:
+        return None
+    return try_cli(["confluent", "kafka", "cluster", "describe", resource['id'], "-o", "json"], debug)
+
+
+def resume_api_key(kind, debug):
+    """Returns the credentials of the API key of the kind which an earlier run with the name created, with the file
+    which the run wrote them to, if the key still exists and the file does too, since its secret can't be read back
+    otherwise."""
+    resource = recorded(kind)
+    if resource is None or not resource.get('file') or not os.path.exists(resource['file']):
+        return None
+    if try_cli(["confluent", "api-key", "describe", resource['id'], "-o", "json"], debug) is None:
+        return None
+    with open(resource['file'], encoding='utf-8') as f:
+        return json.load(f), resource['file']
+
+
+def write_to_file(file_name, text, json_fmt=True, resource_id=None):
     print("Writing %s to %s" % (file_name, save_dir))
+    record_file(file_name, resource_id)
     with open(file_name, 'w', encoding='utf-8') as out_file:
         if json_fmt:
             json.dump(text, out_file, indent=2, sort_keys=True)
@@ -47,38 +274,452 @@ def write_to_file(file_name, text, json_fmt=True):
             out_file.writelines(text)
 
 
+# How often the status of a cluster or network which is being provisioned is checked.
+POLL_INTERVAL = 15
+
+# The networking which each type of cluster supports. Basic and Standard clusters only have public endpoints, and the
+# PrivateLink of Enterprise clusters is a PrivateLink attachment of the environment rather than a network.
+NETWORKING = {
+    'basic': ['public'],
+    'standard': ['public'],
+    'enterprise': ['public', 'privatelink'],
+    'dedicated': ['public', 'privatelink', 'peering', 'transitgateway'],
+}
+
+
+def wait_for(what, describe_args, ready, debug):
+    """Waits until the resource which the command describes has one of the ready statuses, such as a Dedicated cluster,
+    which takes up to an hour to provision, and fails if it fails to provision or takes longer than --wait-timeout."""
+    deadline = time.monotonic() + args.wait_timeout * 60
+    started = time.monotonic()
+    while True:
+        described = cli(describe_args, debug)
+        status = str(described.get('status') or described.get('phase') or '').upper()
+        if status in ready:
+            print(f'{what} is {status.lower()} after {int(time.monotonic() - started)}s')
+            return described
+        if status in ('FAILED', 'ERROR', 'DELETING'):
+            print(f'{what} failed to provision, its status is {status.lower()}', file=sys.stderr)
+            report_created('failed')
+            exit(1)
+        if time.monotonic() > deadline:
+            print(f'{what} is still {status.lower()} after {args.wait_timeout} minutes, re-run the plugin to keep waiting',
+                  file=sys.stderr)
+            report_created('timed out')
+            exit(1)
+        print(f'Waiting for {what}, which is {status.lower() or "provisioning"}')
+        time.sleep(POLL_INTERVAL)
+
+
+def provision_network(env_id, debug):
+    """Returns the ID of the network of a Dedicated cluster with private networking, which is --network, or the ready
+    network of the environment with the cloud, region, and connection type, or a new one, which it waits for. For an
+    Enterprise cluster with PrivateLink, it makes sure that the environment has a PrivateLink attachment in the
+    region, which the cluster's endpoints are reached through, and returns None, since the cluster takes no network."""
+    if args.networking == 'public':
+        return None
+
+    if args.type == 'enterprise':
+        resource = recorded('private-link-attachment')
+        if resource is not None and try_cli(["confluent", "network", "private-link", "attachment", "describe",
+                                              resource['id'], "--environment", env_id, "-o", "json"], debug) is not None:
+            print(f'Using the PrivateLink attachment {resource["id"]} of an earlier run')
+            return None
+        attachments = cli(["confluent", "network", "private-link", "attachment", "list", "--environment", env_id,
+                           "-o", "json"], debug)
+        for attachment in attachments:
+            if attachment.get('cloud', '').lower() == args.cloud and attachment.get('region') == args.region:
+                print(f'Using the PrivateLink attachment {attachment["id"]} of the environment')
+                return None
+        print(f'Creating a PrivateLink attachment in {args.cloud} {args.region}')
+        attachment = cli(["confluent", "network", "private-link", "attachment", "create", f'{args.name}-attachment',
+                          "--cloud", args.cloud, "--region", args.region, "--environment", env_id, "-o", "json"], debug)
+        record('private-link-attachment', attachment['id'], f'PrivateLink attachment {attachment["id"]}',
+               ["network", "private-link", "attachment", "delete", attachment['id'], "--environment", env_id, "--force"])
+        wait_for(f'PrivateLink attachment {attachment["id"]}',
+                 ["confluent", "network", "private-link", "attachment", "describe", attachment['id'],
+                  "--environment", env_id, "-o", "json"], ('WAITING_FOR_CONNECTIONS', 'READY'), debug)
+        print('Connect your VPC or VNet to it with confluent network private-link attachment connection create')
+        return None
+
+    if args.network:
+        return args.network
+    resource = recorded('network')
+    if resource is not None and try_cli(["confluent", "network", "describe", resource['id'], "--environment", env_id,
+                                          "-o", "json"], debug) is not None:
+        print(f'Using the network {resource["id"]} of an earlier run')
+        return resource['id']
+    for network in cli(["confluent", "network", "list", "--environment", env_id, "-o", "json"], debug):
+        types = [t.lower().replace('_', '') for t in network.get('connection_types') or []]
+        if network.get('cloud', '').lower() == args.cloud and network.get('region') == args.region and \
+                args.networking in types and str(network.get('phase', '')).upper() == 'READY':
+            print(f'Using the {args.networking} network {network["id"]} of the environment')
+            return network['id']
+
+    print(f'Creating a {args.networking} network in {args.cloud} {args.region}')
+    network_args = ["confluent", "network", "create", f'{args.name}-network', "--cloud", args.cloud,
+                    "--region", args.region, "--connection-types", args.networking, "--environment", env_id, "-o", "json"]
+    if args.cidr:
+        network_args += ["--cidr", args.cidr]
+    network = cli(network_args, debug)
+    record('network', network['id'], f'network {network["id"]}',
+           ["network", "delete", network['id'], "--environment", env_id, "--force"])
+    wait_for(f'network {network["id"]}', ["confluent", "network", "describe", network['id'], "--environment", env_id,
+                                          "-o", "json"], ('READY',), debug)
+    return network['id']
+
+
+# The topics of --sample-data, each with the Avro schema of its values, and the records which are produced to it, by
+# their keys.
+SAMPLES = {
+    'customers': {
+        'schema': {
+            'type': 'record', 'name': 'Customer', 'namespace': 'io.confluent.kickstart',
+            'fields': [
+                {'name': 'customer_id', 'type': 'string'},
+                {'name': 'name', 'type': 'string'},
+                {'name': 'email', 'type': 'string'},
+                {'name': 'country', 'type': 'string'},
+            ],
+        },
+        'records': {
+            'c-1': {'customer_id': 'c-1', 'name': 'Ada Lovelace', 'email': 'ada@example.com', 'country': 'GB'},
+            'c-2': {'customer_id': 'c-2', 'name': 'Grace Hopper', 'email': 'grace@example.com', 'country': 'US'},
+            'c-3': {'customer_id': 'c-3', 'name': 'Alan Turing', 'email': 'alan@example.com', 'country': 'GB'},
+        },
+    },
+    'orders': {
+        'schema': {
+            'type': 'record', 'name': 'Order', 'namespace': 'io.confluent.kickstart',
+            'fields': [
+                {'name': 'order_id', 'type': 'string'},
+                {'name': 'customer_id', 'type': 'string'},
+                {'name': 'product', 'type': 'string'},
+                {'name': 'quantity', 'type': 'int'},
+                {'name': 'price', 'type': 'double'},
+                {'name': 'ordered_at', 'type': {'type': 'long', 'logicalType': 'timestamp-millis'}},
+            ],
+        },
+        'records': {
+            'o-1': {'order_id': 'o-1', 'customer_id': 'c-1', 'product': 'keyboard', 'quantity': 1, 'price': 49.99,
+                    'ordered_at': 1700000000000},
+            'o-2': {'order_id': 'o-2', 'customer_id': 'c-2', 'product': 'monitor', 'quantity': 2, 'price': 189.5,
+                    'ordered_at': 1700000060000},
+            'o-3': {'order_id': 'o-3', 'customer_id': 'c-1', 'product': 'mouse', 'quantity': 3, 'price': 19.99,
+                    'ordered_at': 1700000120000},
+            'o-4': {'order_id': 'o-4', 'customer_id': 'c-3', 'product': 'laptop', 'quantity': 1, 'price': 1299.0,
+                    'ordered_at': 1700000180000},
+        },
+    },
+}
+
+# How many times a command with a new API key, such as producing the sample records, is tried, POLL_INTERVAL apart, since
+# a new API key takes a minute or two to be usable.
+API_KEY_ATTEMPTS = 8
+
+
+def retry_auth(cmd_args, api_key, debug, stdin=None):
+    """Runs a command with a new API key as cli does, retrying while the key isn't usable yet."""
+    for attempt in range(1, API_KEY_ATTEMPTS + 1):
+        results = subprocess.run(cmd_args, input=stdin, capture_output=True)
+        if results.returncode == 0:
+            if debug:
+                print("Debug: %s" % str(results.stdout, 'UTF-8'))
+            return
+        stderr = str(results.stderr, 'UTF-8')
+        if exit_code(stderr) != 3 or attempt == API_KEY_ATTEMPTS:
+            print(stderr, file=sys.stderr)
+            report_created('failed')
+            exit(exit_code(stderr))
+        print(f'Waiting for the API key {api_key} to be usable')
+        time.sleep(POLL_INTERVAL)
+
+
+def produce(topic, schema_file, records, creds_json, sr_creds_json, debug):
+    """Produces the records to the topic, serialized with the Avro schema of the file."""
+    produce_args = ["confluent", "kafka", "topic", "produce", topic, "--cluster", cluster_json['id'],
+                    "--api-key", creds_json['api_key'], "--api-secret", creds_json['api_secret'],
+                    "--value-format", "avro", "--schema", schema_file,
+                    "--schema-registry-api-key", sr_creds_json['api_key'],
+                    "--schema-registry-api-secret", sr_creds_json['api_secret'],
+                    "--parse-key", "--delimiter", "|"]
+    lines = ''.join(f'{key}|{json.dumps(value)}\n' for key, value in records.items())
+    retry_auth(produce_args, creds_json['api_key'], debug, lines.encode('utf-8'))
+
+
+# The roles which the service account of --use-rbac is granted on each topic of --sample-data, to consume from it and
+# produce to it, rather than ACLs, along with DeveloperRead on the consumer groups which start with the run name. With
+# --service-account-keys, it's granted them on the subjects of the topics too, and on the topics and subjects of
+# --resource-prefix, and DeveloperRead on its consumer groups.
+RBAC_ROLES = ['DeveloperRead', 'DeveloperWrite']
+GROUP_ROLES = ['DeveloperRead']
+
+
+def use_service_account(debug):
+    """Returns the ID of the service account which owns the Kafka API key with --use-rbac, and the Schema Registry API
+    key too with --service-account-keys: the one which an earlier run with the name created, if it still exists, or else
+    a new one."""
+    resource = recorded('service-account')
+    if resource is not None and try_cli(["confluent", "iam", "service-account", "describe", resource['id'], "-o",
+                                         "json"], debug) is not None:
+        print(f'Using the service account {resource["id"]} of an earlier run')
+        return resource['id']
+    print(f'Creating the service account {args.name}-kickstart')
+    sa_json = cli(["confluent", "iam", "service-account", "create", f'{args.name}-kickstart', "--description",
+                   f'Clients of the Kafka cluster "{args.name}" of confluent cloud-kickstart', "-o", "json"], debug)
+    record('service-account', sa_json['id'], f'service account {sa_json["id"]}',
+           ["iam", "service-account", "delete", sa_json['id'], "--force"])
+    return sa_json['id']
+
+
+def bind_roles(sa_id, resource, roles, debug, prefixed=False):
+    """Grants the service account the roles on the resource, such as Topic:orders, or on every resource whose name starts
+    with it if it's prefixed, unless an earlier run did. Subjects are resources of Schema Registry, and the others of the
+    Kafka cluster."""
+    bound = {r['id'] for r in read_run(args.name)['resources'] if r.get('kind') == 'role-binding'}
+    kind, name = resource.split(':', 1)
+    if kind == 'Subject':
+        scope_args = ["--environment", env_id, "--schema-registry-cluster", sr_json['id']]
+    else:
+        scope_args = ["--environment", env_id, "--cloud-cluster", cluster_json['id'],
+                      "--kafka-cluster", cluster_json['id']]
+    for role in roles:
+        binding_id = f'User:{sa_id}/{role}/{resource}' + ('*' if prefixed else '')
+        if binding_id in bound:
+            continue
+        what = f'the {kind.lower()}s starting with {name}' if prefixed else f'the {kind.lower()} {name}'
+        print(f'Granting {sa_id} {role} on {what}')
+        binding_args = ["--principal", f'User:{sa_id}', "--role", role, "--resource", resource] + scope_args
+        if prefixed:
+            binding_args.append("--prefix")
+        cli(["confluent", "iam", "rbac", "role-binding", "create"] + binding_args + ["-o", "json"], debug)
+        record('role-binding', binding_id, f'role binding {role} of {sa_id} on {what}',
+               ["iam", "rbac", "role-binding", "delete"] + binding_args + ["--force"])
+
+
+def create_samples(creds_json, sr_creds_json, debug):
+    """Creates the topics of SAMPLES, registers the schemas of their values, and produces their records, returning
+    them for the manifest. A topic is only recorded once its records are produced, so a re-run which finds it recorded
+    doesn't produce them again."""
+    produced = {r['id'] for r in read_run(args.name)['resources'] if r.get('kind') == 'topic'}
+    topics = []
+    for topic, sample in SAMPLES.items():
+        subject = f'{topic}-value'
+        schema_file = save_dir + '/' + subject + '.avsc'
+        write_to_file(schema_file, sample['schema'])
+
+        print(f'Creating the topic {topic}')
+        cli(["confluent", "kafka", "topic", "create", topic, "--cluster", cluster_json['id'], "--if-not-exists"],
+            debug, fmt_json=False)
+        # The service account's key can only produce the records once it's granted its roles on the topic, which can
+        # take a moment to apply, which producing is retried for.
+        if sa_id is not None:
+            bind_roles(sa_id, f'Topic:{topic}', RBAC_ROLES, debug)
+        # Registering a schema which is already registered returns its ID, so a re-run registers it again as it is.
+        print(f'Registering the Avro schema of {subject}')
+        schema_json = cli(["confluent", "schema-registry", "schema", "create", "--subject", subject,
+                           "--schema", schema_file, "--type", "avro", "-o", "json"], debug)
+        if args.service_account_keys:
+            bind_roles(sa_id, f'Subject:{subject}', RBAC_ROLES, debug)
+        if topic in produced:
+            print(f'The sample records of {topic} were produced by an earlier run')
+        else:
+            print(f'Producing {len(sample["records"])} sample records to {topic}')
+            produce(topic, schema_file, sample['records'], creds_json, sr_creds_json, debug)
+            record('topic', topic, f'topic {topic} of the Kafka cluster {cluster_json["id"]}',
+                   ["kafka", "topic", "delete", topic, "--cluster", cluster_json['id'], "--force"])
+            record('subject', subject, f'Schema Registry subject {subject}',
+                   ["schema-registry", "schema", "delete", "--subject", subject, "--version", "all", "--force"])
+        topics.append({'name': topic, 'subject': subject, 'schema_id': schema_json.get('id'),
+                       'schema_file': schema_file, 'records': len(sample['records'])})
+    return topics
+
+
+def bootstrap(cluster):
+    """Returns the bootstrap server of a described cluster, without the protocol of its endpoint."""
+    return re.sub(r'^[A-Z_]+://', '', cluster.get('endpoint') or '') or None
+
+
+def create_dr_cluster(debug):
+    """Creates the DR cluster of --dr-region, unless an earlier run with the name did and it still exists. It's created
+    right after the source cluster, so that both are provisioned at once."""
+    resource = recorded('dr-kafka-cluster')
+    if resource is not None:
+        dr_json = try_cli(["confluent", "kafka", "cluster", "describe", resource['id'], "-o", "json"], debug)
+        if dr_json is not None:
+            print(f'Using the DR Kafka cluster "{args.name}-dr" ({dr_json["id"]}) of an earlier run')
+            return dr_json
+    print(f'Creating the {args.type} Kafka cluster "{args.name}-dr" in {args.dr_region}')
+    create_args = ["confluent", "kafka", "cluster", "create", f'{args.name}-dr',
+                   "-o", "json", "--cloud", args.cloud, "--region", args.dr_region, "--type", args.type]
+    if args.type == 'dedicated':
+        create_args += ["--cku", str(args.cku)]
+    if args.availability:
+        create_args += ["--availability", args.availability]
+    dr_json = cli(create_args, debug)
+    record('dr-kafka-cluster', dr_json['id'], f'Kafka cluster "{args.name}-dr" ({dr_json["id"]})',
+           ["kafka", "cluster", "delete", dr_json['id'], "--force"])
+    return dr_json
+
+
+def link_dr_cluster(dr_json, creds_json, debug):
+    """Creates a cluster link from the source cluster to the DR cluster, with the API key of the source cluster, and a
+    mirror topic of each topic of --sample-data, unless an earlier run did. Returns the link's name."""
+    link = f'{args.name}-dr-link'
+    if recorded('cluster-link') is not None:
+        print(f'Using the cluster link {link} of an earlier run')
+    else:
+        print(f'Creating the cluster link {link} from {cluster_json["id"]} to {dr_json["id"]}')
+        retry_auth(["confluent", "kafka", "link", "create", link, "--cluster", dr_json['id'],
+                    "--source-cluster", cluster_json['id'], "--source-bootstrap-server", bootstrap(cluster_json),
+                    "--source-api-key", creds_json['api_key'], "--source-api-secret", creds_json['api_secret']],
+                   creds_json['api_key'], debug)
+        record('cluster-link', link, f'cluster link {link} of the Kafka cluster {dr_json["id"]}',
+               ["kafka", "link", "delete", link, "--cluster", dr_json['id'], "--force"])
+
+    # The mirror topics are deleted before the link, since it can't be deleted while it has any.
+    mirrored = {r['id'] for r in read_run(args.name)['resources'] if r.get('kind') == 'mirror-topic'}
+    for topic in SAMPLES:
+        if topic in mirrored:
+            continue
+        print(f'Mirroring the topic {topic} to {dr_json["id"]}')
+        cli(["confluent", "kafka", "mirror", "create", topic, "--link", link, "--cluster", dr_json['id']], debug,
+            fmt_json=False)
+        record('mirror-topic', topic, f'mirror topic {topic} of the Kafka cluster {dr_json["id"]}',
+               ["kafka", "topic", "delete", topic, "--cluster", dr_json['id'], "--force"])
+    return link
+
+
 def resolve_environment(environment_name, debug):
+    """Returns the ID of the environment with the name, --environment-name or else the name of the run, and creates and
+    records it if it doesn't exist, so that a re-run uses it again and --teardown deletes it."""
     env_id = None
-    if environment_name is not None:
-        all_env_json = cli(["confluent", "environment", "list", "-o", "json"], debug)
-        for env_json in all_env_json:
-            if environment_name == env_json['name']:
-                # environment names are unique so it's safe to short circuit
-                env_id = env_json['id']
-                break
-        if not env_id:
-            print(f'Creating new environment {environment_name}')
-            new_env_json = cli(["confluent", "environment", "create", environment_name, "-o", "json"], debug)
-            env_id = new_env_json['id']
-    else:
-        env_id = create_environment(environment_name)
+    all_env_json = cli(["confluent", "environment", "list", "-o", "json"], debug)
+    for env_json in all_env_json:
+        if environment_name == env_json['name']:
+            # environment names are unique so it's safe to short circuit
+            env_id = env_json['id']
+            break
+    if not env_id:
+        print(f'Creating new environment {environment_name}')
+        new_env_json = cli(["confluent", "environment", "create", environment_name, "-o", "json"], debug)
+        env_id = new_env_json['id']
+        record('environment', env_id, f'environment "{environment_name}" ({env_id})',
+               ["environment", "delete", env_id, "--force"])
 
     print(f'Setting the active environment to {environment_name} ({env_id})')
-    cli(["confluent", "environment", "use", env_id], debug, capture_output=False)
+    # The CLI's output is captured, rather than printed, so that it isn't in the document of -o json.
+    cli(["confluent", "environment", "use", env_id], debug, fmt_json=False)
+    return env_id
 
 
-usage_message = '''confluent cloud-kickstart [-h] --name NAME [--env ENV] [--cloud {aws,azure,gcp}] [--region REGION] [--geo {apac,eu,us}]
+def store_secrets(store, path, secrets):
+    """Stores the secrets, a dict of their names and values, as one secret in Vault or AWS Secrets Manager, and returns
+    their references, such as ${vault:secret/data/kickstart#kafka_api_secret}, which confluent connect deploy resolves
+    too. Vault is written to with VAULT_ADDR, VAULT_TOKEN, and optionally VAULT_NAMESPACE, as its CLI is, and AWS
+    with the aws CLI and its default credentials."""
+    if store == 'vault':
+        addr, token = os.environ.get('VAULT_ADDR'), os.environ.get('VAULT_TOKEN')
+        if not addr or not token:
+            parser.error('VAULT_ADDR and VAULT_TOKEN must be set to store the secrets in Vault')
+        # Version 2 of the KV secrets engine, whose API paths include "data/", takes the secret under "data".
+        body = {'data': secrets} if '/data/' in path else secrets
+        request = urllib.request.Request(f'{addr.rstrip("/")}/v1/{path}', data=json.dumps(body).encode(),
+                                         method='POST', headers={'X-Vault-Token': token})
+        if os.environ.get('VAULT_NAMESPACE'):
+            request.add_header('X-Vault-Namespace', os.environ['VAULT_NAMESPACE'])
+        try:
+            urllib.request.urlopen(request, timeout=30).close()
+        except OSError as e:
+            print(f'Failed to store the secrets in Vault at {path}: {e}', file=sys.stderr)
+            exit(exit_code(str(e)))
+        ref = f'vault:{path}'
+    else:
+        secret = json.dumps(secrets)
+        results = subprocess.run(['aws', 'secretsmanager', 'create-secret', '--name', path, '--secret-string', secret,
+                                  '--output', 'json'], capture_output=True)
+        if results.returncode != 0 and b'ResourceExistsException' in results.stderr:
+            results = subprocess.run(['aws', 'secretsmanager', 'put-secret-value', '--secret-id', path,
+                                      '--secret-string', secret, '--output', 'json'], capture_output=True)
+        if results.returncode != 0:
+            print(f'Failed to store the secrets in AWS Secrets Manager as {path}: {str(results.stderr, "UTF-8")}',
+                  file=sys.stderr)
+            exit(exit_code(str(results.stderr, 'UTF-8')))
+        ref = 'aws:' + json.loads(results.stdout)['ARN']
+    return {name: f'${{{ref}#{name}}}' for name in secrets}
+
+
+def write_manifest(file_name, manifest):
+    """Writes the manifest of the run as YAML if the file ends in .yaml or .yml, and as JSON otherwise, only readable by
+    the current user, since it can hold the API secrets."""
+    print(f'Writing the manifest of the run to {file_name}')
+    record_file(file_name)
+    if file_name.endswith(('.yaml', '.yml')):
+        import yaml
+        text = yaml.safe_dump(manifest, sort_keys=False)
+    else:
+        text = json.dumps(manifest, indent=2) + '\n'
+    descriptor = os.open(file_name, os.O_WRONLY | os.O_CREAT | os.O_TRUNC, 0o600)
+    with open(descriptor, 'w', encoding='utf-8') as out_file:
+        out_file.write(text)
+
+
+def apply_plugin_config(parser, plugin):
+    """Sets the defaults of the arguments from ~/.confluent/plugins.yaml, or $CONFLUENT_PLUGINS_CONFIG, which the
+    Go plugins read too: first from its defaults, then from the settings of the plugin. Arguments which are passed
+    override them. Reading it requires PyYAML, without which it's ignored with a warning."""
+    path = os.environ.get('CONFLUENT_PLUGINS_CONFIG') or os.path.join(os.path.expanduser('~'), '.confluent', 'plugins.yaml')
+    if not os.path.exists(path):
+        return
+    try:
+        import yaml
+    except ImportError:
+        print(f'Warning: ignoring {path}, which requires PyYAML to read (pip install pyyaml)', file=sys.stderr)
+        return
+
+    with open(path, encoding='utf-8') as config_file:
+        config = yaml.safe_load(config_file) or {}
+    actions = {action.dest: action for action in parser._actions}
+    settings = {name: value for name, value in (config.get('defaults') or {}).items()
+                if name.replace('-', '_') in actions}
+    for name, value in ((config.get('plugins') or {}).get(plugin) or {}).items():
+        if name.replace('-', '_') not in actions:
+            parser.error(f'{plugin} has no flag --{name}, which is set for it in {path}')
+        settings[name] = value
+
+    for name, value in settings.items():
+        action = actions[name.replace('-', '_')]
+        if isinstance(action, argparse._StoreTrueAction):
+            action.default = bool(value)
+        else:
+            values = [str(v) for v in value] if isinstance(value, list) else [str(value)]
+            for v in values:
+                if action.choices and v not in action.choices:
+                    parser.error(f'invalid {name} "{v}" in {path}, expected one of {", ".join(action.choices)}')
+            action.default = values if action.nargs in ('*', '+') else values[0]
+        # A required argument which has a default in the config file doesn't have to be passed.
+        action.required = False
+
+
+usage_message = '''confluent cloud-kickstart [-h] --name NAME [--environment-name ENVIRONMENT_NAME] [--cloud {aws,azure,gcp}] [--region REGION] [--geo {apac,eu,us}]
 [--client {clojure,cpp,csharp,go,groovy,java,kotlin,ktor,nodejs,python,restapi,ruby,rust,scala,springboot}] [--debug {y,n}] [--dir DIR]
+[--type {basic,standard,enterprise,dedicated}] [--cku CKU] [--availability {single-zone,multi-zone}]
+[--networking {public,privatelink,peering,transitgateway}] [--network NETWORK] [--cidr CIDR] [--wait-timeout WAIT_TIMEOUT]
+[--sample-data] [--dr-region DR_REGION] [--use-rbac] [--manifest MANIFEST] [--secrets-store {manifest,vault,aws}] [--secrets-path SECRETS_PATH] [--force-recreate]
+[--output {table,json,yaml}] [--quiet]
+confluent cloud-kickstart --teardown --name NAME [--dry-run] [--yes] [--non-interactive]
 '''
 
-parser = argparse.ArgumentParser(description='Creates a Kafka cluster with API keys, '
-                                             'Schema Registry with API keys and a client '
-                                             'config properties file.'
-                                             '\nThis plugin assumes confluent CLI v3.0.0 or greater',
-                                 usage=usage_message)
+parser = ArgumentParser(description='Creates a Kafka cluster with API keys, '
+                                    'Schema Registry with API keys and a client '
+                                    'config properties file.'
+                                    '\nThis plugin assumes confluent CLI v3.0.0 or greater',
+                        usage=usage_message)
 
 parser.add_argument('--name', required=True, help='The name for your Confluent Kafka Cluster')
-parser.add_argument('--environment-name', help='Environment name to use, will create it if the environment does not exist')
+parser.add_argument('--environment-name',
+                    help='Environment name to use, will create it if the environment does not exist. Defaults to the '
+                         '--name of the run')
 parser.add_argument('--cloud', default='aws', choices=['aws', 'azure', 'gcp'],
                     help='Cloud Provider, Defaults to aws')
 parser.add_argument('--region', default='us-west-2', help='Cloud region e.g us-west-2 (aws), '
@@ -94,29 +735,228 @@ parser.add_argument("--output-format", choices=['properties', 'stdout'], default
 parser.add_argument("--debug", choices=['y', 'n'], default='n',
                     help="Prints the results of every command, defaults to n")
 parser.add_argument("--dir", help='Directory to save credentials and client configs, defaults to download directory')
+parser.add_argument('--type', choices=['basic', 'standard', 'enterprise', 'dedicated'], default='basic',
+                    help='Type of the Kafka cluster, defaults to basic')
+parser.add_argument('--cku', type=int, help='Number of CKUs of a dedicated cluster, defaults to 1')
+parser.add_argument('--availability', choices=['single-zone', 'multi-zone'],
+                    help='Availability of the cluster, multi-zone for production, defaults to that of the CLI')
+parser.add_argument('--networking', choices=['public', 'privatelink', 'peering', 'transitgateway'], default='public',
+                    help='How the cluster is reached: over the internet, or privately, which dedicated clusters support '
+                         'with a network of each kind, and enterprise clusters with a PrivateLink attachment. '
+                         'Defaults to public')
+parser.add_argument('--network', help='ID of the network of a dedicated cluster with private networking, rather than '
+                                      'a ready one of the environment, or a new one')
+parser.add_argument('--cidr', help='CIDR block of a new network for peering or a transit gateway, such as 10.1.0.0/16')
+parser.add_argument('--wait-timeout', type=int, default=90,
+                    help='Minutes to wait for the cluster and its network to be provisioned, defaults to 90')
+parser.add_argument('--sample-data', action='store_true',
+                    help='Create the topics customers and orders, register Avro schemas for their values with Schema '
+                         'Registry, and produce a few records with them, to show serialization end to end')
+parser.add_argument('--dr-region',
+                    help='Also create a DR cluster of the same type in this region, and a cluster link to it from the '
+                         'cluster, which mirrors the topics of --sample-data, to demo replication and disaster recovery')
+parser.add_argument('--use-rbac', action='store_true',
+                    help='Create a service account to own the Kafka API key, and grant it DeveloperRead and '
+                         'DeveloperWrite role bindings on the topics of --sample-data, and DeveloperRead on the consumer '
+                         'groups which start with --name, rather than use a key of the user')
+parser.add_argument('--service-account-keys', action='store_true',
+                    help='Create a service account to own the Kafka and Schema Registry API keys, granted role bindings '
+                         'on nothing but the topics, subjects, and consumer groups of the run, rather than create keys of '
+                         'the user, so that the clients keep working once the user is deactivated')
+parser.add_argument('--resource-prefix',
+                    help='With --service-account-keys, the prefix of the topics, subjects, and consumer groups which '
+                         'the service account is granted role bindings on, defaults to the name')
+parser.add_argument('--manifest',
+                    help='File to write the IDs, endpoints, and API keys of what the run created to, as JSON, or as YAML '
+                         'if it ends in .yaml or .yml, for automation to read')
+parser.add_argument('--secrets-store', choices=['manifest', 'vault', 'aws'], default='manifest',
+                    help='Where to store the API secrets of the manifest: in it, or in Vault or AWS Secrets Manager, '
+                         'in which case the manifest references them. Defaults to manifest')
+parser.add_argument('--secrets-path',
+                    help='Path of the Vault secret, such as secret/data/kickstart/demo, or name of the AWS secret, '
+                         'which defaults to confluent-cloud-kickstart/NAME')
+parser.add_argument('--force-recreate', action='store_true',
+                    help='Delete what earlier runs with the name created and create it all again, rather than use what '
+                         'still exists')
+parser.add_argument('--output', '-o', choices=['table', 'json', 'yaml', 'text'], default='table',
+                    help='With json or yaml, print a single document of the IDs of what the run created and its API '
+                         'keys, as in the manifest, once it succeeds, or with --teardown of what was deleted, and the '
+                         'progress to stderr. text is the same as table, which it was named before. Defaults to table')
+parser.add_argument('--quiet', '-q', action='store_true',
+                    help='Print nothing but errors, and the document with -o json or yaml, such as in provisioning '
+                         'scripts')
+parser.add_argument('--teardown', action='store_true',
+                    help='Delete the resources which the runs with the name created, and the files which they wrote, '
+                         'rather than create them')
+parser.add_argument('--dry-run', action='store_true', help='With --teardown, print what would be deleted without deleting it')
+parser.add_argument('--yes', '-y', action='store_true', help='Tear down without asking for confirmation, such as in CI')
+parser.add_argument('--non-interactive', action='store_true',
+                    help='Fail rather than ask for confirmation, unless --yes is passed, such as in CI')
 
+apply_plugin_config(parser, 'confluent-cloud_kickstart')
 args = parser.parse_args()
+non_interactive = args.non_interactive or bool(os.environ.get('CONFLUENT_PLUGINS_NON_INTERACTIVE'))
+if args.output == 'text':
+    args.output = 'table'
+if args.output == 'yaml':
+    try:
+        import yaml
+    except ImportError:
+        parser.error('--output yaml requires PyYAML (pip install pyyaml), or pass --output json')
+if args.teardown and args.quiet:
+    parser.error('--quiet is only supported when creating resources, not with --teardown')
+
+# With -o json or yaml, stdout only has the document, so that scripts can pipe it into jq, and the progress goes to
+# stderr, or nowhere with --quiet. Errors always go to stderr.
+result_out = sys.stdout
+if args.quiet:
+    sys.stdout = open(os.devnull, 'w')
+elif args.output != 'table':
+    sys.stdout = sys.stderr
+
+if args.teardown:
+    print_document({'dry_run': args.dry_run, 'actions': teardown(args.name, args.yes, non_interactive, args.dry_run)})
+    exit(0)
+if args.dry_run:
+    parser.error('--dry-run is only supported with --teardown')
+if args.networking not in NETWORKING[args.type]:
+    parser.error(f'a {args.type} cluster supports --networking {", ".join(NETWORKING[args.type])}')
+if args.cku is not None and args.type != 'dedicated':
+    parser.error('--cku is only supported with --type dedicated')
+if args.type == 'dedicated' and args.cku is None:
+    args.cku = 1
+if args.cku is not None and args.cku < 1:
+    parser.error('--cku must be at least 1')
+if (args.network or args.cidr) and args.networking not in ('privatelink', 'peering', 'transitgateway'):
+    parser.error('--network and --cidr are only supported with private --networking')
+if args.type == 'enterprise' and (args.network or args.cidr):
+    parser.error('an enterprise cluster takes no --network or --cidr, its PrivateLink attachment is of the environment')
+if args.networking in ('peering', 'transitgateway') and not args.network and not args.cidr:
+    parser.error(f'a new network for --networking {args.networking} needs --cidr, or pass an existing --network')
+if args.dr_region:
+    if args.type not in ('dedicated', 'enterprise'):
+        parser.error('a cluster link needs dedicated or enterprise clusters, pass --dr-region with --type dedicated or '
+                     'enterprise')
+    if args.dr_region == args.region:
+        parser.error('--dr-region must be another region than --region')
+    if args.networking != 'public':
+        parser.error('--dr-region only supports public --networking')
+    if args.use_rbac or args.service_account_keys:
+        parser.error('the cluster link of --dr-region reads the topics with the Kafka API key of the user, which '
+                     '--use-rbac and --service-account-keys replace')
+    # The topics of the sample data are the ones which are mirrored.
+    args.sample_data = True
+if args.use_rbac and not args.sample_data:
+    parser.error('--use-rbac grants role bindings on the topics of --sample-data, pass it too')
+if args.resource_prefix is not None and not args.service_account_keys:
+    parser.error('--resource-prefix is only supported with --service-account-keys')
+if args.resource_prefix == '':
+    parser.error('--resource-prefix can\'t be empty, the service account would be granted every resource')
+if args.secrets_store != 'manifest' and not args.manifest:
+    parser.error(f'--secrets-store {args.secrets_store} needs --manifest')
+if args.secrets_store == 'vault' and not args.secrets_path:
+    parser.error('--secrets-store vault needs --secrets-path, such as secret/data/kickstart/demo')
+if args.quiet and args.output == 'table' and args.output_format == 'stdout':
+    parser.error('--output-format stdout prints the API keys, which --quiet drops, pass -o json to print them')
+if args.manifest and args.manifest.endswith(('.yaml', '.yml')):
+    try:
+        import yaml
+    except ImportError:
+        parser.error(f'writing {args.manifest} as YAML requires PyYAML (pip install pyyaml), or write it as .json')
 save_dir = args.dir
 if save_dir is None:
     save_dir = str(os.path.join(Path.home(), "Downloads"))
 
 debug = False if args.debug == 'n' else True
+signal.signal(signal.SIGINT, interrupted)
 
-resolve_environment(args.environment_name, debug)
+# A re-run with the same name, such as after a partial failure, resumes: it uses the resources which an earlier run
+# created and which still exist, and only creates the others.
+if args.force_recreate and os.path.exists(run_file(args.name)):
+    teardown(args.name, True, non_interactive, False)
 
-print("Creating the Kafka cluster")
-cluster_json = cli(["confluent", "kafka", "cluster", "create", args.name,
-                    "-o", "json", "--cloud", args.cloud, "--region", args.region], debug)
+if args.environment_name is None:
+    args.environment_name = args.name
+env_id = resolve_environment(args.environment_name, debug)
 
-print("Generating API keys for the Kafka cluster")
-creds_json = cli(["confluent", "api-key", "create", "--resource", cluster_json['id'], "-o", "json"], debug)
+cluster_json = resume_cluster(debug)
+if cluster_json is not None:
+    print(f'Using the Kafka cluster "{args.name}" ({cluster_json["id"]}) of an earlier run')
+else:
+    network_id = provision_network(env_id, debug)
+    print(f'Creating the {args.type} Kafka cluster')
+    create_args = ["confluent", "kafka", "cluster", "create", args.name,
+                   "-o", "json", "--cloud", args.cloud, "--region", args.region, "--type", args.type]
+    if args.type == 'dedicated':
+        create_args += ["--cku", str(args.cku)]
+    if args.availability:
+        create_args += ["--availability", args.availability]
+    if network_id:
+        create_args += ["--network", network_id]
+    cluster_json = cli(create_args, debug)
+    record('kafka-cluster', cluster_json['id'], f'Kafka cluster "{args.name}" ({cluster_json["id"]})',
+           ["kafka", "cluster", "delete", cluster_json['id'], "--force"])
 
-print("Enabling Schema Registry")
-sr_json = cli(["confluent", "schema-registry", "cluster", "enable", "--cloud",
-               cluster_json['provider'], "--geo", args.geo, "-o", "json"], debug)
+dr_json = create_dr_cluster(debug) if args.dr_region else None
 
-print("Generating API keys for Schema Registry")
-sr_creds_json = cli(["confluent", "api-key", "create", "--resource", sr_json['id'], "-o", "json"], debug)
+# A cluster's endpoint is only known once it's provisioned, which takes a few minutes for a Dedicated cluster, and
+# longer still with private networking.
+cluster_json = wait_for(f'Kafka cluster {cluster_json["id"]}', ["confluent", "kafka", "cluster", "describe",
+                                                               cluster_json['id'], "-o", "json"], ('UP',), debug)
+
+# With --use-rbac, the Kafka API key is owned by a service account, which is only granted roles on the topics which the
+# run creates, rather than by the user, who can reach the whole cluster. With --service-account-keys, the Schema
+# Registry API key is too, so that neither key stops working when the user is deactivated.
+sa_id = use_service_account(debug) if args.use_rbac or args.service_account_keys else None
+
+cluster_keys_file = save_dir + '/' + "cluster-api-keys-" + cluster_json['id'] + ".json"
+resumed = resume_api_key('kafka-api-key', debug)
+if resumed is not None:
+    creds_json, cluster_keys_file = resumed
+    print(f'Using the API key {creds_json["api_key"]} of the Kafka cluster of an earlier run')
+else:
+    print("Generating API keys for the Kafka cluster")
+    key_args = ["confluent", "api-key", "create", "--resource", cluster_json['id'], "-o", "json"]
+    if sa_id is not None:
+        key_args += ["--service-account", sa_id]
+    creds_json = cli(key_args, debug)
+    record('kafka-api-key', creds_json['api_key'], f'API key {creds_json["api_key"]}',
+           ["api-key", "delete", creds_json['api_key'], "--force"])
+
+# Schema Registry is enabled once per environment, so it's used as it is if it already is.
+sr_json = try_cli(["confluent", "schema-registry", "cluster", "describe", "-o", "json"], debug)
+if sr_json is not None:
+    sr_json['id'] = sr_json.get('cluster_id') or sr_json.get('id')
+    print(f'Using Schema Registry {sr_json["id"]}, which is already enabled')
+else:
+    print("Enabling Schema Registry")
+    sr_json = cli(["confluent", "schema-registry", "cluster", "enable", "--cloud",
+                   cluster_json['provider'], "--geo", args.geo, "-o", "json"], debug)
+
+ts = f'{datetime.now():%Y-%m-%d_%H-%M-%S%z}'
+sr_keys_file = save_dir + '/' + "sr-api-keys-" + ts + '_' + sr_json['id'] + ".json"
+resumed = resume_api_key('schema-registry-api-key', debug)
+if resumed is not None:
+    sr_creds_json, sr_keys_file = resumed
+    print(f'Using the API key {sr_creds_json["api_key"]} of Schema Registry of an earlier run')
+else:
+    print("Generating API keys for Schema Registry")
+    sr_key_args = ["confluent", "api-key", "create", "--resource", sr_json['id'], "-o", "json"]
+    if args.service_account_keys:
+        sr_key_args += ["--service-account", sa_id]
+    sr_creds_json = cli(sr_key_args, debug)
+    record('schema-registry-api-key', sr_creds_json['api_key'], f'API key {sr_creds_json["api_key"]}',
+           ["api-key", "delete", sr_creds_json['api_key'], "--force"])
+
+# The clients of the run name their topics, subjects, and consumer groups with the prefix, which are all that the service
+# account can reach. With --use-rbac, they consume the sample topics with consumer groups named after the run.
+if args.service_account_keys:
+    prefix = args.resource_prefix or args.name
+    bind_roles(sa_id, f'Topic:{prefix}', RBAC_ROLES, debug, prefixed=True)
+    bind_roles(sa_id, f'Group:{prefix}', GROUP_ROLES, debug, prefixed=True)
+    bind_roles(sa_id, f'Subject:{prefix}', RBAC_ROLES, debug, prefixed=True)
+elif args.use_rbac:
+    bind_roles(sa_id, f'Group:{args.name}', GROUP_ROLES, debug, prefixed=True)
 
 print("Enabling the API key for the Kafka cluster")
 cli(["confluent", "api-key", "use", creds_json['api_key'], "--resource", cluster_json['id']], debug, fmt_json=False)
@@ -124,6 +964,24 @@ cli(["confluent", "api-key", "use", creds_json['api_key'], "--resource", cluster
 print("Setting created cluster for use in subsequent commands")
 cli(["confluent", "kafka", "cluster", "use", cluster_json['id']], debug, fmt_json=False)
 
+topics = create_samples(creds_json, sr_creds_json, debug) if args.sample_data else []
+
+link = None
+if dr_json is not None:
+    dr_json = wait_for(f'Kafka cluster {dr_json["id"]}', ["confluent", "kafka", "cluster", "describe", dr_json['id'],
+                                                          "-o", "json"], ('UP',), debug)
+    dr_keys_file = save_dir + '/' + "cluster-api-keys-" + dr_json['id'] + ".json"
+    resumed = resume_api_key('dr-kafka-api-key', debug)
+    if resumed is not None:
+        dr_creds_json, dr_keys_file = resumed
+        print(f'Using the API key {dr_creds_json["api_key"]} of the DR Kafka cluster of an earlier run')
+    else:
+        print("Generating API keys for the DR Kafka cluster")
+        dr_creds_json = cli(["confluent", "api-key", "create", "--resource", dr_json['id'], "-o", "json"], debug)
+        record('dr-kafka-api-key', dr_creds_json['api_key'], f'API key {dr_creds_json["api_key"]}',
+               ["api-key", "delete", dr_creds_json['api_key'], "--force"])
+    link = link_dr_cluster(dr_json, creds_json, debug)
+
 if args.output_format == 'properties':
     print("Generating client configuration")
     client_config = cli(["confluent", "kafka", "client-config", "create", args.client,
@@ -133,17 +991,63 @@ if args.output_format == 'properties':
                          "--schema-registry-api-secret", sr_creds_json['api_secret']],
                         debug, fmt_json=False)
 
-    cluster_keys_file = save_dir + '/' + "cluster-api-keys-" + cluster_json['id'] + ".json"
-    write_to_file(cluster_keys_file, creds_json)
-
-    ts = date_string = f'{datetime.now():%Y-%m-%d_%H-%M-%S%z}'
-    sr_keys_file = save_dir + '/' + "sr-api-keys-" + ts + '_' + sr_json['id'] + ".json"
-    write_to_file(sr_keys_file, sr_creds_json)
+    write_to_file(cluster_keys_file, creds_json, resource_id=creds_json['api_key'])
+    write_to_file(sr_keys_file, sr_creds_json, resource_id=sr_creds_json['api_key'])
+    if dr_json is not None:
+        write_to_file(dr_keys_file, dr_creds_json, resource_id=dr_creds_json['api_key'])
 
     client_configs_file = save_dir + '/' + args.client + '_configs_' + cluster_json['id'] + ".properties"
     write_to_file(client_configs_file, client_config, json_fmt=False)
 else:
+    client_configs_file = None
     print("\nKafka API key:    %s" % creds_json['api_key'])
     print("Kafka API secret: %s\n" % creds_json['api_secret'])
     print("Schema Registry API key:    %s" % sr_creds_json['api_key'])
-    print("Schema Registry API secret: %s" % sr_creds_json['api_secret'])
\ No newline at end of file
+    print("Schema Registry API secret: %s" % sr_creds_json['api_secret'])
+    if dr_json is not None:
+        print("\nDR Kafka API key:    %s" % dr_creds_json['api_key'])
+        print("DR Kafka API secret: %s" % dr_creds_json['api_secret'])
+
+if args.manifest or args.output != 'table':
+    secrets = {'kafka_api_secret': creds_json['api_secret'], 'schema_registry_api_secret': sr_creds_json['api_secret']}
+    if dr_json is not None:
+        secrets['dr_kafka_api_secret'] = dr_creds_json['api_secret']
+    if args.secrets_store != 'manifest':
+        secrets = store_secrets(args.secrets_store, args.secrets_path or f'confluent-cloud-kickstart/{args.name}',
+                                secrets)
+    manifest = {
+        'name': args.name,
+        'environment': {'id': env_id, 'name': args.environment_name},
+        'kafka_cluster': {
+            'id': cluster_json['id'],
+            'name': args.name,
+            'cloud': cluster_json.get('provider', args.cloud),
+            'region': cluster_json.get('region', args.region),
+            'type': cluster_json.get('type', args.type).lower(),
+            'cku': cluster_json.get('cku') or args.cku,
+            'networking': args.networking,
+            'network': cluster_json.get('network') or None,
+            'bootstrap_endpoint': bootstrap(cluster_json),
+            'rest_endpoint': cluster_json.get('rest_endpoint'),
+        },
+        'schema_registry': {'id': sr_json['id'], 'endpoint': sr_json.get('endpoint_url')},
+        'kafka_api_key': {'key': creds_json['api_key'], 'secret': secrets['kafka_api_secret']},
+        'service_account': sa_id,
+        'schema_registry_api_key': {'key': sr_creds_json['api_key'], 'secret': secrets['schema_registry_api_secret']},
+        'client_config_file': client_configs_file,
+        'topics': topics,
+        'dr': None if dr_json is None else {
+            'kafka_cluster': {
+                'id': dr_json['id'],
+                'name': f'{args.name}-dr',
+                'region': dr_json.get('region', args.dr_region),
+                'bootstrap_endpoint': bootstrap(dr_json),
+            },
+            'kafka_api_key': {'key': dr_creds_json['api_key'], 'secret': secrets['dr_kafka_api_secret']},
+            'cluster_link': link,
+            'mirror_topics': list(SAMPLES),
+        },
+    }
+    if args.manifest:
+        write_manifest(args.manifest, manifest)
+    print_document(manifest)
\ No newline at end of file
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// describeACL describes an ACL by all of its fields, so that two ACLs are equal if their descriptions are.
+func describeACL(a cli.ACL) string {
+	return fmt.Sprintf("%s %s %s on %s:%s (%s) from %s", a.Principal, strings.ToUpper(a.Permission), strings.ToUpper(a.Operation), strings.ToUpper(a.ResourceType), a.ResourceName, strings.ToUpper(a.PatternType), a.Host)
+}
+
+// An aclDiff lists the ACLs which are only in one of the clusters. ACLs are the same if they grant or deny the same
+// principal the same operation on the same resources.
+type aclDiff struct {
+	OnlyInSource []cli.ACL `json:"only_in_source"`
+	OnlyInTarget []cli.ACL `json:"only_in_target"`
+}
+
+func (d aclDiff) empty() bool {
+	return len(d.OnlyInSource) == 0 && len(d.OnlyInTarget) == 0
+}
+
+func (s *side) readACLs() error {
+	var listed []cli.ACL
+	if err := cli.JSON(&listed, append([]string{"kafka", "acl", "list"}, s.scope...)...); err != nil {
+		return err
+	}
+
+	s.acls = map[string]cli.ACL{}
+	for _, a := range listed {
+		s.acls[describeACL(a)] = a
+	}
+	return nil
+}
+
+func compareACLs(source, target *side) aclDiff {
+	return aclDiff{OnlyInSource: onlyIn(source.acls, target.acls), OnlyInTarget: onlyIn(target.acls, source.acls)}
+}
+
+// onlyIn returns the ACLs in a which aren't in b.
+func onlyIn(a, b map[string]cli.ACL) []cli.ACL {
+	var keys []string
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	acls := []cli.ACL{}
+	for _, k := range keys {
+		acls = append(acls, a[k])
+	}
+	return acls
+}
+
+func (d aclDiff) print(w io.Writer, source, target *side) {
+	for _, acls := range []struct {
+		side *side
+		acls []cli.ACL
+	}{{source, d.OnlyInSource}, {target, d.OnlyInTarget}} {
+		if len(acls.acls) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "ACLs only in %s:\n", acls.side.name)
+		for _, a := range acls.acls {
+			fmt.Fprintf(w, "  %s\n", describeACL(a))
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A topicDiff is a diff of the topics in two clusters.
+type topicDiff struct {
+	OnlyInSource                []string           `json:"only_in_source"`
+	OnlyInTarget                []string           `json:"only_in_target"`
+	PartitionCountMismatches    []countMismatch    `json:"partition_count_mismatches"`
+	ReplicationFactorMismatches []countMismatch    `json:"replication_factor_mismatches"`
+	ConfigDifferences           []configDifference `json:"config_differences"`
+}
+
+type countMismatch struct {
+	Topic  string `json:"topic"`
+	Source int    `json:"source"`
+	Target int    `json:"target"`
+}
+
+// A configDifference is a config which is set on either topic, and has a different value on the other. Values which
+// are the cluster's default are marked as such, since the defaults of clusters may differ.
+type configDifference struct {
+	Topic         string `json:"topic"`
+	Config        string `json:"config"`
+	Source        string `json:"source"`
+	SourceDefault bool   `json:"source_default"`
+	Target        string `json:"target"`
+	TargetDefault bool   `json:"target_default"`
+}
+
+func (d topicDiff) empty() bool {
+	return len(d.OnlyInSource) == 0 && len(d.OnlyInTarget) == 0 && len(d.PartitionCountMismatches) == 0 &&
+		len(d.ReplicationFactorMismatches) == 0 && len(d.ConfigDifferences) == 0
+}
+
+// common returns the names of the topics in both clusters.
+func common(source, target *side) []string {
+	var names []string
+	for name := range source.topics {
+		if _, ok := target.topics[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compareTopics diffs the topics, ignoring the configs in ignored. Configs are only compared if they were read.
+func compareTopics(source, target *side, ignored map[string]bool) topicDiff {
+	d := topicDiff{
+		OnlyInSource:                []string{},
+		OnlyInTarget:                []string{},
+		PartitionCountMismatches:    []countMismatch{},
+		ReplicationFactorMismatches: []countMismatch{},
+		ConfigDifferences:           []configDifference{},
+	}
+
+	for name := range source.topics {
+		if _, ok := target.topics[name]; !ok {
+			d.OnlyInSource = append(d.OnlyInSource, name)
+		}
+	}
+	for name := range target.topics {
+		if _, ok := source.topics[name]; !ok {
+			d.OnlyInTarget = append(d.OnlyInTarget, name)
+		}
+	}
+	sort.Strings(d.OnlyInSource)
+	sort.Strings(d.OnlyInTarget)
+
+	for _, name := range common(source, target) {
+		s, t := source.topics[name], target.topics[name]
+		if s.PartitionCount != t.PartitionCount {
+			d.PartitionCountMismatches = append(d.PartitionCountMismatches, countMismatch{Topic: name, Source: s.PartitionCount, Target: t.PartitionCount})
+		}
+		if s.ReplicationFactor != t.ReplicationFactor {
+			d.ReplicationFactorMismatches = append(d.ReplicationFactorMismatches, countMismatch{Topic: name, Source: s.ReplicationFactor, Target: t.ReplicationFactor})
+		}
+
+		sc, tc := source.configs[name], target.configs[name]
+		names := map[string]bool{}
+		for c := range sc {
+			names[c] = true
+		}
+		for c := range tc {
+			names[c] = true
+		}
+		var configs []string
+		for c := range names {
+			configs = append(configs, c)
+		}
+		sort.Strings(configs)
+
+		for _, c := range configs {
+			sv, tv := sc[c], tc[c]
+			if ignored[c] || sv.IsSensitive || tv.IsSensitive || sv.IsDefaultValue && tv.IsDefaultValue || sv.Value == tv.Value {
+				continue
+			}
+			d.ConfigDifferences = append(d.ConfigDifferences, configDifference{
+				Topic:         name,
+				Config:        c,
+				Source:        sv.Value,
+				SourceDefault: sv.IsDefaultValue,
+				Target:        tv.Value,
+				TargetDefault: tv.IsDefaultValue,
+			})
+		}
+	}
+
+	return d
+}
+
+func (d topicDiff) print(w io.Writer, source, target *side) {
+	if len(d.OnlyInSource) > 0 {
+		fmt.Fprintf(w, "Topics only in %s:\n", source.name)
+		for _, name := range d.OnlyInSource {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	if len(d.OnlyInTarget) > 0 {
+		fmt.Fprintf(w, "Topics only in %s:\n", target.name)
+		for _, name := range d.OnlyInTarget {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	if len(d.PartitionCountMismatches) > 0 {
+		fmt.Fprintln(w, "Partition counts:")
+		for _, m := range d.PartitionCountMismatches {
+			fmt.Fprintf(w, "  %s: %d -> %d\n", m.Topic, m.Source, m.Target)
+		}
+	}
+	if len(d.ReplicationFactorMismatches) > 0 {
+		fmt.Fprintln(w, "Replication factors:")
+		for _, m := range d.ReplicationFactorMismatches {
+			fmt.Fprintf(w, "  %s: %d -> %d\n", m.Topic, m.Source, m.Target)
+		}
+	}
+	if len(d.ConfigDifferences) > 0 {
+		fmt.Fprintln(w, "Configs:")
+		for _, c := range d.ConfigDifferences {
+			fmt.Fprintf(w, "  %s %s: %s -> %s\n", c.Topic, c.Config, configValue(c.Source, c.SourceDefault), configValue(c.Target, c.TargetDefault))
+		}
+	}
+}
+
+func configValue(value string, isDefault bool) string {
+	if value == "" {
+		value = `""`
+	}
+	if isDefault {
+		return value + " (default)"
+	}
+	return value
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errDrift fails the plugin with exitcode.Findings when the clusters differ, so that a DR check can alert on it.
+var errDrift = exitcode.New(exitcode.Findings, "the clusters differ")
+
+// A report of the drift between two clusters. Sections which were skipped are omitted.
+type report struct {
+	Topics  topicDiff   `json:"topics"`
+	ACLs    *aclDiff    `json:"acls,omitempty"`
+	Schemas *schemaDiff `json:"schemas,omitempty"`
+}
+
+func (r report) empty() bool {
+	return r.Topics.empty() && (r.ACLs == nil || r.ACLs.empty()) && (r.Schemas == nil || r.Schemas.empty())
+}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "diff",
+		Short:   "Compare two Kafka clusters end to end.",
+		Long:    "Compare the topics and their configs, the ACLs, and the schema subjects of the topics of two Kafka clusters, such as a production cluster and its DR cluster, and report the drift between them. Exits with code 2 if there is drift.",
+		Args:    cobra.NoArgs,
+		RunE:    diffClusters,
+		Example: "confluent cluster diff --source-cluster lkc-123456 --source-environment env-123456 --target-cluster lkc-654321 --target-environment env-654321 --output json",
+	}
+
+	cmd.Flags().String("source-cluster", "", "Kafka cluster ID of the source. Defaults to the current cluster.")
+	cmd.Flags().String("source-environment", "", "Environment ID of the source. Defaults to the current environment.")
+	cmd.Flags().String("target-cluster", "", "Kafka cluster ID of the target.")
+	cmd.Flags().String("target-environment", "", "Environment ID of the target. Defaults to the current environment.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().String("prefix", "", "Only compare topics whose names start with this prefix.")
+	cmd.Flags().Bool("include-internal", false, "Compare internal topics too.")
+	cmd.Flags().StringSlice("ignore-config", nil, "Configs to ignore, such as those which are expected to differ between clusters.")
+	cmd.Flags().StringSlice("skip", nil, "Parts not to compare: configs, acls, or schemas.")
+	cmd.Flags().Bool("all-subjects", false, "Compare every subject in the Schema Registries, not only those of the clusters' topics.")
+	cmd.Flags().Int("parallelism", 8, "How many topics' configs or subjects' schemas to read at once.")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("target-cluster"))
+
+	completion.Add(&cmd, "confluent-cluster-diff")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-cluster-diff"))
+
+	exitcode.Execute(&cmd)
+}
+
+func diffClusters(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	sourceCluster, err := cmd.Flags().GetString("source-cluster")
+	cobra.CheckErr(err)
+
+	sourceEnvironment, err := cmd.Flags().GetString("source-environment")
+	cobra.CheckErr(err)
+
+	targetCluster, err := cmd.Flags().GetString("target-cluster")
+	cobra.CheckErr(err)
+
+	targetEnvironment, err := cmd.Flags().GetString("target-environment")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	internal, err := cmd.Flags().GetBool("include-internal")
+	cobra.CheckErr(err)
+
+	ignoreConfigs, err := cmd.Flags().GetStringSlice("ignore-config")
+	cobra.CheckErr(err)
+
+	skip, err := cmd.Flags().GetStringSlice("skip")
+	cobra.CheckErr(err)
+
+	allSubjects, err := cmd.Flags().GetBool("all-subjects")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	skipped := map[string]bool{}
+	for _, s := range skip {
+		if s != "configs" && s != "acls" && s != "schemas" {
+			return fmt.Errorf(`unsupported part "%s", supported parts: configs, acls, schemas`, s)
+		}
+		skipped[s] = true
+	}
+
+	source := &side{name: sourceCluster, scope: cli.ClusterFlags(sourceCluster, sourceEnvironment), environment: cli.ClusterFlags("", sourceEnvironment)}
+	if source.name == "" {
+		source.name = "the current cluster"
+	}
+	target := &side{name: targetCluster, scope: cli.ClusterFlags(targetCluster, targetEnvironment), environment: cli.ClusterFlags("", targetEnvironment)}
+	sides := []*side{source, target}
+
+	for _, s := range sides {
+		if err := s.read(prefix, internal); err != nil {
+			return err
+		}
+	}
+	if !skipped["configs"] {
+		names := common(source, target)
+		for _, s := range sides {
+			if err := s.readConfigs(names, parallelism); err != nil {
+				return err
+			}
+		}
+	}
+
+	ignored := map[string]bool{}
+	for _, c := range ignoreConfigs {
+		ignored[c] = true
+	}
+	r := report{Topics: compareTopics(source, target, ignored)}
+
+	if !skipped["acls"] {
+		for _, s := range sides {
+			if err := s.readACLs(); err != nil {
+				return err
+			}
+		}
+		d := compareACLs(source, target)
+		r.ACLs = &d
+	}
+
+	if !skipped["schemas"] {
+		for _, s := range sides {
+			if err := s.readSchemas(allSubjects, parallelism); err != nil {
+				return err
+			}
+		}
+		d := compareSchemas(source, target)
+		r.Schemas = &d
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, r, func() { r.print(out, source, target) }); err != nil {
+		return err
+	}
+
+	if !r.empty() {
+		return errDrift
+	}
+	return nil
+}
+
+func (r report) print(w io.Writer, source, target *side) {
+	if r.empty() {
+		fmt.Fprintln(w, "No drift.")
+		return
+	}
+
+	r.Topics.print(w, source, target)
+	if r.ACLs != nil {
+		r.ACLs.print(w, source, target)
+	}
+	if r.Schemas != nil {
+		r.Schemas.print(w, source, target)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// schema is the output of "confluent schema-registry schema describe".
+type schema struct {
+	ID     int    `json:"schema_id"`
+	Schema string `json:"schema"`
+	Type   string `json:"type"`
+}
+
+// A schemaDiff lists the subjects which are only in one of the Schema Registries, and the subjects whose latest
+// schemas differ. Schema IDs are compared too, since clients of a cluster which was failed over to can only read
+// records written with the same IDs.
+type schemaDiff struct {
+	OnlyInSource      []string           `json:"only_in_source"`
+	OnlyInTarget      []string           `json:"only_in_target"`
+	SchemaDifferences []schemaDifference `json:"schema_differences"`
+	IDMismatches      []idMismatch       `json:"id_mismatches"`
+}
+
+type schemaDifference struct {
+	Subject string `json:"subject"`
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+}
+
+type idMismatch struct {
+	Subject string `json:"subject"`
+	Source  int    `json:"source"`
+	Target  int    `json:"target"`
+}
+
+func (d schemaDiff) empty() bool {
+	return len(d.OnlyInSource) == 0 && len(d.OnlyInTarget) == 0 && len(d.SchemaDifferences) == 0 && len(d.IDMismatches) == 0
+}
+
+// topicSubject reports whether the subject is named after one of the cluster's topics, with the default topic name
+// strategy.
+func (s *side) topicSubject(subject string) bool {
+	for _, suffix := range []string{"-key", "-value"} {
+		if topic, ok := strings.CutSuffix(subject, suffix); ok {
+			if _, ok := s.topics[topic]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readSchemas reads the latest schema of each subject in the environment's Schema Registry, in parallel. Unless all
+// is set, only the subjects of the cluster's topics are read, since other clusters may share the Schema Registry.
+func (s *side) readSchemas(all bool, parallelism int) error {
+	var subjects []struct {
+		Subject string `json:"subject"`
+	}
+	if err := cli.JSON(&subjects, append([]string{"schema-registry", "subject", "list"}, s.environment...)...); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, subject := range subjects {
+		if all || s.topicSubject(subject.Subject) {
+			names = append(names, subject.Subject)
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, parallelism)
+		errs = make([]error, len(names))
+	)
+	s.schemas = map[string]schema{}
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() { <-sem; wg.Done() }()
+
+			var sc schema
+			if err := cli.JSON(&sc, append([]string{"schema-registry", "schema", "describe", "--subject", name, "--version", "latest"}, s.environment...)...); err != nil {
+				errs[i] = fmt.Errorf(`failed to read the schema of subject "%s": %w`, name, err)
+				return
+			}
+
+			mu.Lock()
+			s.schemas[name] = sc
+			mu.Unlock()
+		}(i, name)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func compareSchemas(source, target *side) schemaDiff {
+	d := schemaDiff{
+		OnlyInSource:      []string{},
+		OnlyInTarget:      []string{},
+		SchemaDifferences: []schemaDifference{},
+		IDMismatches:      []idMismatch{},
+	}
+
+	var subjects []string
+	for subject := range source.schemas {
+		if _, ok := target.schemas[subject]; ok {
+			subjects = append(subjects, subject)
+		} else {
+			d.OnlyInSource = append(d.OnlyInSource, subject)
+		}
+	}
+	for subject := range target.schemas {
+		if _, ok := source.schemas[subject]; !ok {
+			d.OnlyInTarget = append(d.OnlyInTarget, subject)
+		}
+	}
+	sort.Strings(subjects)
+	sort.Strings(d.OnlyInSource)
+	sort.Strings(d.OnlyInTarget)
+
+	for _, subject := range subjects {
+		s, t := source.schemas[subject], target.schemas[subject]
+		if s.Schema != t.Schema || !strings.EqualFold(s.Type, t.Type) {
+			d.SchemaDifferences = append(d.SchemaDifferences, schemaDifference{Subject: subject, Source: s.Schema, Target: t.Schema})
+		}
+		if s.ID != t.ID {
+			d.IDMismatches = append(d.IDMismatches, idMismatch{Subject: subject, Source: s.ID, Target: t.ID})
+		}
+	}
+
+	return d
+}
+
+func (d schemaDiff) print(w io.Writer, source, target *side) {
+	if len(d.OnlyInSource) > 0 {
+		fmt.Fprintf(w, "Subjects only in %s:\n", source.name)
+		for _, subject := range d.OnlyInSource {
+			fmt.Fprintf(w, "  %s\n", subject)
+		}
+	}
+	if len(d.OnlyInTarget) > 0 {
+		fmt.Fprintf(w, "Subjects only in %s:\n", target.name)
+		for _, subject := range d.OnlyInTarget {
+			fmt.Fprintf(w, "  %s\n", subject)
+		}
+	}
+	if len(d.SchemaDifferences) > 0 {
+		fmt.Fprintln(w, "Latest schemas:")
+		for _, s := range d.SchemaDifferences {
+			fmt.Fprintf(w, "  %s differs\n", s.Subject)
+		}
+	}
+	if len(d.IDMismatches) > 0 {
+		fmt.Fprintln(w, "Schema IDs:")
+		for _, m := range d.IDMismatches {
+			fmt.Fprintf(w, "  %s: %d -> %d\n", m.Subject, m.Source, m.Target)
+		}
+	}
+}
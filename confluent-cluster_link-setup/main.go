@@ -0,0 +1,230 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "setup [link]",
+		Short: "Set up a cluster link and its mirror topics.",
+		Long:  "Set up a cluster link from a source cluster to a destination cluster: create an API key for the link on the source cluster, the ACLs which the link needs, the link itself, and mirror topics for the topics with the names or which match a regular expression. Values which aren't passed as flags are asked for when run interactively, and the plan is printed before anything is changed.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  setup,
+		Example: `confluent cluster-link setup
+confluent cluster-link setup dr-link --source-cluster lkc-123456 --source-environment env-123456 --destination-cluster lkc-654321 --destination-environment env-654321 --service-account sa-123456 --topic-regex "orders.*" --config consumer.offset.sync.enable=true --yes`,
+	}
+
+	cmd.Flags().String("source-cluster", "", "ID of the cluster to mirror topics from.")
+	cmd.Flags().String("source-environment", "", "Environment ID of the source cluster. Defaults to the current environment.")
+	cmd.Flags().String("destination-cluster", "", "ID of the cluster to create the link and mirror topics in.")
+	cmd.Flags().String("destination-environment", "", "Environment ID of the destination cluster. Defaults to the current environment.")
+	cmd.Flags().String("service-account", "", "Service account to create the link's API key for, and to grant the ACLs on the source cluster which the link needs.")
+	cmd.Flags().String("source-api-key", "", "Existing API key of the source cluster for the link, instead of creating one.")
+	cmd.Flags().String("source-api-secret", "", "Secret of --source-api-key. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("consumer-service-account", "", "Service account to grant the ACLs to consume the mirror topics on the destination cluster, such as the consumers which fail over to it.")
+	cmd.Flags().StringToString("config", nil, `Configs of the link, as "<name>=<value>" pairs, such as consumer.offset.sync.enable=true.`)
+	cmd.Flags().StringSlice("topic", nil, "Topics to mirror.")
+	cmd.Flags().String("topic-regex", "", "Regular expression of the topics to mirror.")
+	dryrun.AddFlag(&cmd, "Print the plan without changing anything.")
+	cmd.Flags().Bool("force", false, "Skip the confirmation prompt.")
+	cobra.CheckErr(cmd.Flags().MarkDeprecated("force", "use --yes instead"))
+	output.AddFlag(&cmd, "Format of the steps which were done")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("service-account", "source-api-key")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "yes")
+
+	completion.Add(&cmd, "confluent-cluster_link-setup")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-cluster_link-setup"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+func setup(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	l := &link{}
+	if len(args) > 0 {
+		l.name = args[0]
+	}
+
+	var err error
+	l.source, err = cmd.Flags().GetString("source-cluster")
+	cobra.CheckErr(err)
+
+	l.sourceEnvironment, err = cmd.Flags().GetString("source-environment")
+	cobra.CheckErr(err)
+
+	l.destination, err = cmd.Flags().GetString("destination-cluster")
+	cobra.CheckErr(err)
+
+	l.destinationEnvironment, err = cmd.Flags().GetString("destination-environment")
+	cobra.CheckErr(err)
+
+	l.serviceAccount, err = cmd.Flags().GetString("service-account")
+	cobra.CheckErr(err)
+
+	l.sourceAPIKey, err = cmd.Flags().GetString("source-api-key")
+	cobra.CheckErr(err)
+
+	l.sourceAPISecret, err = cmd.Flags().GetString("source-api-secret")
+	cobra.CheckErr(err)
+
+	l.consumerServiceAccount, err = cmd.Flags().GetString("consumer-service-account")
+	cobra.CheckErr(err)
+
+	l.configs, err = cmd.Flags().GetStringToString("config")
+	cobra.CheckErr(err)
+
+	topics, err := cmd.Flags().GetStringSlice("topic")
+	cobra.CheckErr(err)
+
+	topicRegex, err := cmd.Flags().GetString("topic-regex")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	p := prompt.New(cmd)
+	if err := p.Value(&l.name, "link", "Name of the cluster link", true); err != nil {
+		return err
+	}
+	// Each cluster is picked from the clusters of its environment, which is asked for first.
+	for _, q := range []struct {
+		cluster, environment *string
+		flag, question, side string
+	}{
+		{&l.source, &l.sourceEnvironment, "source-cluster", "Source cluster", "source"},
+		{&l.destination, &l.destinationEnvironment, "destination-cluster", "Destination cluster", "destination"},
+	} {
+		if *q.cluster == "" {
+			question := fmt.Sprintf("Environment ID of the %s cluster (leave empty for the current environment)", q.side)
+			if err := p.Value(q.environment, q.side+"-environment", question, false); err != nil {
+				return err
+			}
+		}
+		environment := *q.environment
+		list := func() ([]prompt.Option, error) { return prompt.KafkaClusterOptions(environment) }
+		if err := p.Select(q.cluster, q.flag, q.question, list); err != nil {
+			return err
+		}
+	}
+	if l.sourceAPIKey == "" {
+		if err := p.Select(&l.serviceAccount, "service-account", "Service account to create the link's API key for", prompt.ServiceAccountOptions); err != nil {
+			return fmt.Errorf(`%w, pass either "service-account" or "source-api-key"`, err)
+		}
+	}
+	if len(topics) == 0 && topicRegex == "" {
+		if err := p.Value(&topicRegex, "topic-regex", "Regular expression of the topics to mirror (leave empty to only create the link)", false); err != nil {
+			return err
+		}
+	}
+
+	if l.sourceAPIKey != "" && l.sourceAPISecret == "" {
+		l.sourceAPISecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+		if l.sourceAPISecret == "" {
+			return exitcode.Invalid("--source-api-secret or CONFLUENT_KAFKA_API_SECRET is required with --source-api-key")
+		}
+	}
+
+	var pattern *regexp.Regexp
+	if topicRegex != "" {
+		// The whole name has to match, as with the include filters of cluster link auto-create.
+		if pattern, err = regexp.Compile("^(?:" + topicRegex + ")$"); err != nil {
+			return exitcode.Invalid(`invalid --topic-regex "%s": %w`, topicRegex, err)
+		}
+	}
+
+	source, err := cli.DescribeKafkaCluster(l.source, l.sourceEnvironment)
+	if err != nil {
+		return err
+	}
+	l.sourceBootstrap = strings.TrimPrefix(source.Endpoint, "SASL_SSL://")
+
+	if l.topics, err = mirrorTopics(topics, pattern, l.sourceScope()); err != nil {
+		return err
+	}
+
+	steps, err := l.plan()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, s := range steps {
+			plan.Actions = append(plan.Actions, s.action)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := output.Progress(cmd)
+	if len(steps) == 0 {
+		fmt.Fprintf(out, "The cluster link \"%s\" and its mirror topics are already set up.\n", l.name)
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+	fmt.Fprintln(out, "Plan:")
+	for i, s := range steps {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, s.description())
+	}
+
+	if !force {
+		ok, err := p.Confirm("Set up the cluster link?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not setting up the cluster link.")
+			return nil
+		}
+	}
+
+	// Each step depends on the ones before it, so the setup stops at the first failure. Re-running it skips the link and
+	// mirror topics which were created.
+	var done dryrun.Plan
+	for i, s := range steps {
+		if err := s.run(); err != nil {
+			if errors.Is(err, interrupt.ErrInterrupted) {
+				err = fmt.Errorf("the setup was interrupted at step %d, and can be re-run to finish it: %w", i+1, err)
+			} else {
+				err = fmt.Errorf("step %d failed, so the setup stopped: %w", i+1, err)
+			}
+			return errors.Join(err, dryrun.PrintApplied(cmd, done))
+		}
+		fmt.Fprintf(out, "Done: %s\n", s.description())
+		done.Actions = append(done.Actions, s.action)
+	}
+	return dryrun.PrintApplied(cmd, done)
+}
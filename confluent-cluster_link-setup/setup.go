@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+)
+
+// A step of the setup, which is printed as part of the plan before anything is changed.
+type step struct {
+	action dryrun.Action
+	run    func() error
+}
+
+// description describes the step as a sentence, such as `Create mirror topic "orders" on lkc-123456.`
+func (s step) description() string {
+	a := s.action.String()
+	return strings.ToUpper(a[:1]) + a[1:] + "."
+}
+
+// A link to set up, from the source cluster to the destination cluster, where the mirror topics are created.
+type link struct {
+	name string
+
+	source            string
+	sourceEnvironment string
+	sourceBootstrap   string
+	sourceAPIKey      string
+	sourceAPISecret   string
+	// newKey is whether the API key was created by the setup.
+	newKey bool
+
+	destination            string
+	destinationEnvironment string
+
+	// serviceAccount owns the link's API key, and is granted the ACLs on the source cluster which the link needs.
+	serviceAccount string
+	// consumerServiceAccount is granted the ACLs to consume the mirror topics on the destination cluster.
+	consumerServiceAccount string
+
+	configs map[string]string
+	topics  []string
+}
+
+// An acl is an ACL to create, in the flags of "confluent kafka acl create".
+type acl struct {
+	operations []string
+	resource   []string
+}
+
+func (l *link) sourceScope() []string {
+	return cli.ClusterFlags(l.source, l.sourceEnvironment)
+}
+
+func (l *link) destinationScope() []string {
+	return cli.ClusterFlags(l.destination, l.destinationEnvironment)
+}
+
+// mirrorTopics returns the topics of the source cluster with the names, or which match the pattern, except internal
+// topics, which can't be mirrored.
+func mirrorTopics(names []string, pattern *regexp.Regexp, scope []string) ([]string, error) {
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, scope...)...); err != nil {
+		return nil, err
+	}
+
+	exists := map[string]bool{}
+	for _, t := range listed {
+		exists[t.Name] = true
+	}
+
+	selected := map[string]bool{}
+	for _, name := range names {
+		if !exists[name] {
+			return nil, fmt.Errorf(`topic "%s" not found in the source cluster`, name)
+		}
+		selected[name] = true
+	}
+	if pattern != nil {
+		for _, t := range listed {
+			if !strings.HasPrefix(t.Name, "_") && pattern.MatchString(t.Name) {
+				selected[t.Name] = true
+			}
+		}
+	}
+
+	topics := make([]string, 0, len(selected))
+	for name := range selected {
+		topics = append(topics, name)
+	}
+	sort.Strings(topics)
+	return topics, nil
+}
+
+// plan returns the steps to set up the link, skipping the link and mirror topics which already exist, along with their
+// API key and ACLs, so that the setup can be re-run to mirror more topics.
+func (l *link) plan() ([]step, error) {
+	var links []struct {
+		Name string `json:"link_name"`
+	}
+	if err := cli.JSON(&links, append([]string{"kafka", "link", "list"}, l.destinationScope()...)...); err != nil {
+		return nil, err
+	}
+	exists := false
+	for _, existing := range links {
+		if existing.Name == l.name {
+			exists = true
+		}
+	}
+
+	mirrored := map[string]bool{}
+	if exists {
+		var mirrors []struct {
+			Name string `json:"mirror_topic_name"`
+		}
+		if err := cli.JSON(&mirrors, append([]string{"kafka", "mirror", "list", "--link", l.name}, l.destinationScope()...)...); err != nil {
+			return nil, err
+		}
+		for _, m := range mirrors {
+			mirrored[m.Name] = true
+		}
+	}
+	var topics []string
+	for _, topic := range l.topics {
+		if !mirrored[topic] {
+			topics = append(topics, topic)
+		}
+	}
+
+	var steps []step
+	if !exists && l.sourceAPIKey == "" {
+		steps = append(steps, step{
+			action: dryrun.Action{Verb: "create", Resource: "API key", Detail: fmt.Sprintf("for %s on %s", l.serviceAccount, l.source)},
+			run:    l.createKey,
+		})
+	}
+
+	if l.serviceAccount != "" {
+		for _, a := range l.sourceACLs(topics, !exists) {
+			steps = append(steps, l.aclStep(l.serviceAccount, a, l.source, l.sourceScope()))
+		}
+	}
+
+	if !exists {
+		detail := fmt.Sprintf("from %s to %s", l.source, l.destination)
+		if len(l.configs) > 0 {
+			detail += ", with " + formatConfigs(l.configs)
+		}
+		steps = append(steps, step{action: dryrun.Action{Verb: "create", Resource: "cluster link", Name: l.name, Detail: detail}, run: l.createLink})
+	}
+
+	for _, topic := range topics {
+		topic := topic
+		steps = append(steps, step{
+			action: dryrun.Action{Verb: "create", Resource: "mirror topic", Name: topic, Detail: "on " + l.destination},
+			run: func() error {
+				_, err := cli.Run(append([]string{"kafka", "mirror", "create", topic, "--link", l.name}, l.destinationScope()...)...)
+				return err
+			},
+		})
+	}
+
+	if l.consumerServiceAccount != "" {
+		for _, topic := range topics {
+			a := acl{operations: []string{"read", "describe"}, resource: []string{"--topic", topic}}
+			steps = append(steps, l.aclStep(l.consumerServiceAccount, a, l.destination, l.destinationScope()))
+		}
+	}
+
+	return steps, nil
+}
+
+func (l *link) aclStep(serviceAccount string, a acl, cluster string, scope []string) step {
+	return step{
+		action: dryrun.Action{Verb: "create", Resource: "ACL", Detail: fmt.Sprintf("allowing %s to %s %s on %s", serviceAccount, strings.Join(a.operations, ", "), describe(a.resource), cluster)},
+		run: func() error {
+			args := []string{"kafka", "acl", "create", "--allow", "--service-account", serviceAccount, "--operations", strings.Join(a.operations, ",")}
+			args = append(args, a.resource...)
+			_, err := cli.Run(append(args, scope...)...)
+			return err
+		},
+	}
+}
+
+// sourceACLs are the ACLs which the link's service account needs on the source cluster: to read the mirrored topics and
+// their configs, and for a new link, to describe the cluster, as well as the consumer groups and topics to sync their
+// offsets and ACLs.
+func (l *link) sourceACLs(topics []string, newLink bool) []acl {
+	var acls []acl
+	for _, topic := range topics {
+		acls = append(acls, acl{operations: []string{"read", "describe-configs"}, resource: []string{"--topic", topic}})
+	}
+	if !newLink {
+		return acls
+	}
+	acls = append(acls, acl{operations: []string{"describe"}, resource: []string{"--cluster-scope"}})
+	if l.configs["consumer.offset.sync.enable"] == "true" {
+		acls = append(acls, acl{operations: []string{"describe"}, resource: []string{"--consumer-group", "*"}})
+	}
+	if l.configs["acl.sync.enable"] == "true" {
+		acls = append(acls, acl{operations: []string{"describe"}, resource: []string{"--topic", "*"}})
+	}
+	return acls
+}
+
+func describe(resource []string) string {
+	switch {
+	case resource[0] == "--cluster-scope":
+		return "the cluster"
+	case resource[1] == "*":
+		return fmt.Sprintf("all %ss", strings.ReplaceAll(strings.TrimPrefix(resource[0], "--"), "-", " "))
+	default:
+		return fmt.Sprintf(`%s "%s"`, strings.ReplaceAll(strings.TrimPrefix(resource[0], "--"), "-", " "), resource[1])
+	}
+}
+
+func (l *link) createKey() error {
+	args := []string{"api-key", "create", "--resource", l.source, "--service-account", l.serviceAccount, "--description", fmt.Sprintf(`Cluster link "%s"`, l.name)}
+	if l.sourceEnvironment != "" {
+		args = append(args, "--environment", l.sourceEnvironment)
+	}
+
+	var key cli.CreatedAPIKey
+	if err := cli.JSON(&key, args...); err != nil {
+		return err
+	}
+	if key.Key == "" || key.Secret == "" {
+		return fmt.Errorf("confluent api-key create didn't return a key and secret")
+	}
+	l.sourceAPIKey, l.sourceAPISecret, l.newKey = key.Key, key.Secret, true
+	return nil
+}
+
+// keyRetries is how many times creating the link is retried with a new API key, which takes a while to be usable.
+const keyRetries = 6
+
+func (l *link) createLink() error {
+	// The configs are passed in a file, which is only readable by the current user, along with the API key, so that the
+	// secret isn't in the process list, or in the error if the CLI fails.
+	configs := map[string]string{
+		"security.protocol": "SASL_SSL",
+		"sasl.mechanism":    "PLAIN",
+		"sasl.jaas.config":  fmt.Sprintf(`org.apache.kafka.common.security.plain.PlainLoginModule required username="%s" password="%s";`, l.sourceAPIKey, l.sourceAPISecret),
+	}
+	for key, value := range l.configs {
+		configs[key] = value
+	}
+	var properties strings.Builder
+	for _, key := range sortedKeys(configs) {
+		fmt.Fprintf(&properties, "%s=%s\n", key, configs[key])
+	}
+	path, err := platform.WriteTemp("cluster-link-*.properties", []byte(properties.String()))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	args := []string{
+		"kafka", "link", "create", l.name,
+		"--source-cluster", l.source,
+		"--source-bootstrap-server", l.sourceBootstrap,
+		"--config", path,
+	}
+	args = append(args, l.destinationScope()...)
+
+	for i := 0; ; i++ {
+		_, err := cli.Run(args...)
+		if err == nil || !l.newKey || i == keyRetries {
+			return err
+		}
+		if err := interrupt.Sleep(10 * time.Second); err != nil {
+			return err
+		}
+	}
+}
+
+func formatConfigs(configs map[string]string) string {
+	pairs := make([]string, 0, len(configs))
+	for _, key := range sortedKeys(configs) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, configs[key]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// pollInterval is how often a connector's status is checked while waiting for it to run.
+const pollInterval = 10 * time.Second
+
+// connectorStatus is the part of "confluent connect cluster describe" which the deploy waits on.
+type connectorStatus struct {
+	Connector struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Trace  string `json:"trace"`
+	} `json:"connector"`
+	Tasks []struct {
+		ID    int    `json:"task_id"`
+		State string `json:"state"`
+	} `json:"tasks"`
+}
+
+// deploy creates the connector, or updates the configs of the existing connector with its name, and returns its ID.
+func deploy(c connector, existing *cli.Connector, scope []string) (string, error) {
+	path, err := c.writeConfigFile()
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+
+	if existing != nil {
+		args := append([]string{"connect", "cluster", "update", existing.ID, "--config-file", path}, scope...)
+		if _, err := cli.Run(args...); err != nil {
+			return "", err
+		}
+		return existing.ID, nil
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&created, append([]string{"connect", "cluster", "create", "--config-file", path}, scope...)...); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// wait waits until the connector and all of its tasks are running, and fails as soon as any of them fails.
+func wait(id string, scope []string, deadline time.Time) error {
+	for {
+		var s connectorStatus
+		if err := cli.JSON(&s, append([]string{"connect", "cluster", "describe", id}, scope...)...); err != nil {
+			return err
+		}
+
+		switch s.Connector.Status {
+		case "FAILED":
+			if trace, _, _ := strings.Cut(strings.TrimSpace(s.Connector.Trace), "\n"); trace != "" {
+				return fmt.Errorf(`connector "%s" (%s) failed: %s`, s.Connector.Name, id, trace)
+			}
+			return fmt.Errorf(`connector "%s" (%s) failed`, s.Connector.Name, id)
+		case "PAUSED":
+			return fmt.Errorf(`connector "%s" (%s) is paused, and must be resumed with "confluent connect cluster resume %s"`, s.Connector.Name, id, id)
+		case "RUNNING":
+			running := true
+			for _, t := range s.Tasks {
+				if t.State == "FAILED" {
+					return fmt.Errorf(`task %d of connector "%s" (%s) failed`, t.ID, s.Connector.Name, id)
+				}
+				if t.State != "RUNNING" {
+					running = false
+				}
+			}
+			if running {
+				return nil
+			}
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			return fmt.Errorf(`timed out waiting for connector "%s" (%s) to run, its status is %s`, s.Connector.Name, id, s.Connector.Status)
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/confluentinc/cli-plugins/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "deploy <file|directory>...",
+		Short: "Deploy fully-managed connectors from config files.",
+		Long:  "Create fully-managed connectors from YAML or JSON config files, or update the configs of those which already exist, and wait for them to run. Credentials can be templated into the configs from environment variables, HashiCorp Vault, or AWS Secrets Manager, so that the files can be kept in git.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  deployConnectors,
+		Example: `confluent connect deploy connectors/ --cluster lkc-123456
+KAFKA_API_SECRET=... confluent connect deploy orders-sink.yaml --timeout 20m`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for the connectors to run.")
+	cmd.Flags().Bool("no-wait", false, "Don't wait for the connectors to run.")
+	dryrun.AddFlag(&cmd, "Render the configs and print which connectors would be created or updated, without deploying them.")
+	output.AddFlag(&cmd, "Format of the connectors which were deployed")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-connect-deploy")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-connect-deploy"))
+
+	exitcode.Execute(&cmd)
+}
+
+func deployConnectors(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	noWait, err := cmd.Flags().GetBool("no-wait")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	files, err := configFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no connector config files found")
+	}
+
+	// Every config is rendered before anything is deployed, so that a missing secret doesn't leave a partial deploy.
+	r := secrets.NewResolver(context.Background())
+	var connectors []connector
+	names := map[string]string{}
+	for _, file := range files {
+		c, err := readConnector(file)
+		if err != nil {
+			return err
+		}
+		if other, ok := names[c.Name]; ok {
+			return fmt.Errorf(`connector "%s" is in both "%s" and "%s"`, c.Name, other, file)
+		}
+		names[c.Name] = file
+
+		if c, err = render(r, c); err != nil {
+			return err
+		}
+		connectors = append(connectors, c)
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	var listed []cli.Connector
+	if err := cli.JSON(&listed, append([]string{"connect", "cluster", "list"}, scope...)...); err != nil {
+		return err
+	}
+	existing := map[string]*cli.Connector{}
+	for i, l := range listed {
+		existing[l.Name] = &listed[i]
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, c := range connectors {
+			if e := existing[c.Name]; e != nil {
+				plan.AddDetail("update", "connector", c.Name, fmt.Sprintf("(%s) from %s", e.ID, c.file))
+			} else {
+				plan.AddDetail("create", "connector", c.Name, "from "+c.file)
+			}
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := output.Progress(cmd)
+
+	// Every connector is deployed before waiting for any of them, since each can take minutes to provision.
+	ids := make([]string, len(connectors))
+	var errs []error
+	var deployed dryrun.Plan
+	for i, c := range connectors {
+		id, err := deploy(c, existing[c.Name], scope)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`failed to deploy connector "%s": %w`, c.Name, err))
+			continue
+		}
+		ids[i] = id
+		if existing[c.Name] != nil {
+			fmt.Fprintf(out, "Updated connector \"%s\" (%s).\n", c.Name, id)
+			deployed.AddDetail("update", "connector", c.Name, fmt.Sprintf("(%s) from %s", id, c.file))
+		} else {
+			fmt.Fprintf(out, "Created connector \"%s\" (%s).\n", c.Name, id)
+			deployed.AddDetail("create", "connector", c.Name, fmt.Sprintf("(%s) from %s", id, c.file))
+		}
+	}
+
+	if !noWait {
+		deadline := time.Now().Add(timeout)
+		for i, c := range connectors {
+			if ids[i] == "" {
+				continue
+			}
+			s := progress.Spin(out, fmt.Sprintf(`Waiting for connector "%s" (%s) to run`, c.Name, ids[i]))
+			err := wait(ids[i], scope, deadline)
+			s.Stop()
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Fprintf(out, "Connector \"%s\" (%s) is running.\n", c.Name, ids[i])
+		}
+	}
+
+	return errors.Join(append(errs, dryrun.PrintApplied(cmd, deployed))...)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A connector is read from a config file in the format of "confluent connect cluster create --config-file", in YAML or
+// JSON, either with the connector's configs under "config":
+//
+//	name: orders-sink
+//	config:
+//	  connector.class: S3_SINK
+//	  kafka.api.key: ${KAFKA_API_KEY}
+//	  kafka.api.secret: ${vault:secret/data/connect#kafka_api_secret}
+//
+// or with the configs at the top level, alongside the name.
+type connector struct {
+	Name   string
+	Config map[string]string
+
+	file string
+}
+
+// describedConnector is the part of "confluent connect cluster describe" which is compared with the config files.
+type describedConnector struct {
+	Configs []struct {
+		Config string `json:"config"`
+		Value  string `json:"value"`
+	} `json:"configs"`
+}
+
+// configFiles lists the YAML and JSON files in the directories, in order, along with any other paths.
+func configFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, e := range entries {
+			if ext := filepath.Ext(e.Name()); !e.IsDir() && (ext == ".yaml" || ext == ".yml" || ext == ".json") {
+				names = append(names, filepath.Join(path, e.Name()))
+			}
+		}
+		sort.Strings(names)
+		files = append(files, names...)
+	}
+	return files, nil
+}
+
+// readConnector reads a config file, which may also be JSON, since YAML is a superset of it.
+func readConnector(path string) (connector, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return connector{}, fmt.Errorf("failed to read the connector config: %w", err)
+	}
+
+	var file map[string]any
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return connector{}, fmt.Errorf(`failed to parse the connector config "%s": %w`, path, err)
+	}
+
+	configs := file
+	if nested, ok := file["config"]; ok {
+		if configs, ok = nested.(map[string]any); !ok {
+			return connector{}, fmt.Errorf(`"config" in "%s" must be a map of connector configs`, path)
+		}
+	}
+
+	c := connector{Config: map[string]string{}, file: path}
+	if name, ok := file["name"].(string); ok {
+		c.Name = name
+	}
+	if c.Name == "" {
+		if name, ok := configs["name"].(string); ok {
+			c.Name = name
+		}
+	}
+	if c.Name == "" {
+		return connector{}, fmt.Errorf(`the connector config "%s" has no name`, path)
+	}
+
+	for key, value := range configs {
+		switch v := value.(type) {
+		case string:
+			c.Config[key] = v
+		case int, float64, bool:
+			// Connector configs are strings, but YAML numbers and booleans are easier to write unquoted.
+			c.Config[key] = fmt.Sprint(v)
+		default:
+			return connector{}, fmt.Errorf(`config "%s" in "%s" must be a string, number, or boolean`, key, path)
+		}
+	}
+	c.Config["name"] = c.Name
+
+	return c, nil
+}
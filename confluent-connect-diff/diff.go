@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/secrets"
+)
+
+// masked matches a value which Confluent Cloud hides, such as a password.
+var masked = regexp.MustCompile(`^\*+$`)
+
+// A diff of the connectors in the config files and those which are deployed.
+type diff struct {
+	NotDeployed []string        `json:"not_deployed"`
+	Extraneous  []string        `json:"extraneous"`
+	Connectors  []connectorDiff `json:"connectors"`
+}
+
+// A connectorDiff is how a deployed connector differs from its config file. Added configs are in the file but not
+// deployed, and removed configs are deployed but not in the file.
+type connectorDiff struct {
+	Name    string         `json:"name"`
+	ID      string         `json:"id"`
+	Added   []configValue  `json:"added"`
+	Removed []configValue  `json:"removed"`
+	Changed []configChange `json:"changed"`
+}
+
+// A configValue's value is left out if it's a secret.
+type configValue struct {
+	Config string `json:"config"`
+	Value  string `json:"value,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+type configChange struct {
+	Config   string `json:"config"`
+	Deployed string `json:"deployed"`
+	File     string `json:"file"`
+}
+
+func (d diff) empty() bool {
+	return len(d.NotDeployed) == 0 && len(d.Extraneous) == 0 && len(d.Connectors) == 0
+}
+
+// render replaces the environment variable placeholders in a config value. It reports whether the value is a secret,
+// which is read from a secrets manager, and isn't rendered.
+func render(value string) (string, bool, error) {
+	secret := false
+	var err error
+	rendered := secrets.Placeholder.ReplaceAllStringFunc(value, func(p string) string {
+		ref := secrets.Placeholder.FindStringSubmatch(p)[1]
+		if strings.Contains(ref, ":") {
+			secret = true
+			return p
+		}
+		v, ok := os.LookupEnv(ref)
+		if !ok && err == nil {
+			err = fmt.Errorf("environment variable %s is not set", ref)
+		}
+		return v
+	})
+	return rendered, secret, err
+}
+
+// compare diffs a connector's config file with its deployed configs. Secrets are never compared, since Confluent
+// Cloud masks them, and only whether they're set is.
+func compare(c connector, id string, deployed map[string]string, ignored map[string]bool) (connectorDiff, error) {
+	d := connectorDiff{Name: c.Name, ID: id, Added: []configValue{}, Removed: []configValue{}, Changed: []configChange{}}
+
+	keys := map[string]bool{}
+	for key := range c.Config {
+		keys[key] = true
+	}
+	for key := range deployed {
+		keys[key] = true
+	}
+	var sorted []string
+	for key := range keys {
+		if !ignored[key] {
+			sorted = append(sorted, key)
+		}
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		local, inFile := c.Config[key]
+		remote, isDeployed := deployed[key]
+
+		secret := masked.MatchString(remote)
+		if inFile {
+			var err error
+			var fromSecretsManager bool
+			if local, fromSecretsManager, err = render(local); err != nil {
+				// Secrets aren't compared, so they don't need to be rendered.
+				if !secret {
+					return connectorDiff{}, fmt.Errorf(`failed to render config "%s" of connector "%s": %w`, key, c.Name, err)
+				}
+			}
+			secret = secret || fromSecretsManager
+		}
+
+		switch {
+		case inFile && !isDeployed:
+			d.Added = append(d.Added, value(key, local, secret))
+		case !inFile && isDeployed:
+			d.Removed = append(d.Removed, value(key, remote, secret))
+		case !secret && local != remote:
+			d.Changed = append(d.Changed, configChange{Config: key, Deployed: remote, File: local})
+		}
+	}
+
+	return d, nil
+}
+
+func value(key, value string, secret bool) configValue {
+	if secret {
+		return configValue{Config: key, Secret: true}
+	}
+	return configValue{Config: key, Value: value}
+}
+
+func (d connectorDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d diff) print(w io.Writer) {
+	if d.empty() {
+		fmt.Fprintln(w, "No differences.")
+		return
+	}
+
+	if len(d.NotDeployed) > 0 {
+		fmt.Fprintln(w, "Connectors which aren't deployed:")
+		for _, name := range d.NotDeployed {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	if len(d.Extraneous) > 0 {
+		fmt.Fprintln(w, "Connectors which aren't in the config files:")
+		for _, name := range d.Extraneous {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	for _, c := range d.Connectors {
+		fmt.Fprintf(w, "Connector \"%s\" (%s):\n", c.Name, c.ID)
+		for _, v := range c.Added {
+			fmt.Fprintf(w, "  + %s\n", v)
+		}
+		for _, v := range c.Removed {
+			fmt.Fprintf(w, "  - %s\n", v)
+		}
+		for _, change := range c.Changed {
+			fmt.Fprintf(w, "  ~ %s: %s -> %s\n", change.Config, quote(change.Deployed), quote(change.File))
+		}
+	}
+}
+
+func (v configValue) String() string {
+	if v.Secret {
+		return fmt.Sprintf("%s (secret)", v.Config)
+	}
+	return fmt.Sprintf("%s: %s", v.Config, quote(v.Value))
+}
+
+func quote(value string) string {
+	if value == "" {
+		return `""`
+	}
+	return value
+}
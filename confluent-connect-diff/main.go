@@ -0,0 +1,151 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errDrift fails the plugin with exitcode.Findings when the deployed connectors differ from the config files, so that
+// CI can detect drift.
+var errDrift = exitcode.New(exitcode.Findings, "the connectors have drifted from the config files")
+
+// managedConfigs are set by Confluent Cloud on every connector, and aren't in config files.
+var managedConfigs = []string{"cloud.environment", "cloud.provider", "kafka.endpoint", "kafka.region"}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "diff <file|directory>...",
+		Short: "Compare deployed connectors with their config files.",
+		Long:  "Compare the configs of deployed fully-managed connectors with YAML or JSON config files, such as those deployed by confluent connect deploy, and print the configs which were added, removed, or changed, without comparing secrets. Exits with code 2 if there are differences.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  diffConnectors,
+		Example: `confluent connect diff connectors/ --cluster lkc-123456
+confluent connect diff orders-sink.yaml --ignore-config tasks.max --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	output.AddFlag(&cmd, "Format of the diff")
+	cmd.Flags().StringSlice("ignore-config", managedConfigs, "Configs to ignore, such as those which Confluent Cloud sets on every connector.")
+	cmd.Flags().Bool("include-extraneous", false, "Report deployed connectors which aren't in the config files too.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-connect-diff")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-connect-diff"))
+
+	exitcode.Execute(&cmd)
+}
+
+func diffConnectors(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	ignoreConfigs, err := cmd.Flags().GetStringSlice("ignore-config")
+	cobra.CheckErr(err)
+
+	extraneous, err := cmd.Flags().GetBool("include-extraneous")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	files, err := configFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no connector config files found")
+	}
+
+	var connectors []connector
+	names := map[string]string{}
+	for _, file := range files {
+		c, err := readConnector(file)
+		if err != nil {
+			return err
+		}
+		if other, ok := names[c.Name]; ok {
+			return fmt.Errorf(`connector "%s" is in both "%s" and "%s"`, c.Name, other, file)
+		}
+		names[c.Name] = file
+		connectors = append(connectors, c)
+	}
+
+	ignored := map[string]bool{}
+	for _, c := range ignoreConfigs {
+		ignored[c] = true
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	var listed []cli.Connector
+	if err := cli.JSON(&listed, append([]string{"connect", "cluster", "list"}, scope...)...); err != nil {
+		return err
+	}
+	d := diff{NotDeployed: []string{}, Extraneous: []string{}, Connectors: []connectorDiff{}}
+	ids := map[string]string{}
+	for _, l := range listed {
+		ids[l.Name] = l.ID
+		if _, ok := names[l.Name]; !ok && extraneous {
+			d.Extraneous = append(d.Extraneous, l.Name)
+		}
+	}
+
+	for _, c := range connectors {
+		id, ok := ids[c.Name]
+		if !ok {
+			d.NotDeployed = append(d.NotDeployed, c.Name)
+			continue
+		}
+
+		var described describedConnector
+		if err := cli.JSON(&described, append([]string{"connect", "cluster", "describe", id}, scope...)...); err != nil {
+			return err
+		}
+		deployed := map[string]string{}
+		for _, config := range described.Configs {
+			deployed[config.Config] = config.Value
+		}
+
+		cd, err := compare(c, id, deployed, ignored)
+		if err != nil {
+			return err
+		}
+		if !cd.empty() {
+			d.Connectors = append(d.Connectors, cd)
+		}
+	}
+	sort.Strings(d.NotDeployed)
+	sort.Strings(d.Extraneous)
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, d, func() { d.print(out) }); err != nil {
+		return err
+	}
+
+	if !d.empty() {
+		return errDrift
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// The headers which Kafka Connect adds to the records in a DLQ, when errors.deadletterqueue.context.headers.enable is
+// true, which it always is in Confluent Cloud.
+const (
+	headerTopic            = "__connect.errors.topic"
+	headerPartition        = "__connect.errors.partition"
+	headerOffset           = "__connect.errors.offset"
+	headerStage            = "__connect.errors.stage"
+	headerExceptionClass   = "__connect.errors.exception.class.name"
+	headerExceptionMessage = "__connect.errors.exception.message"
+)
+
+// dlqTopic returns the name of the connector's DLQ topic, which is dlq-<connector ID> unless the connector's configs
+// name another.
+func dlqTopic(c cli.Connector, scope []string) (string, error) {
+	var described struct {
+		Configs []struct {
+			Config string `json:"config"`
+			Value  string `json:"value"`
+		} `json:"configs"`
+	}
+	if err := cli.JSON(&described, append([]string{"connect", "cluster", "describe", c.ID}, scope...)...); err != nil {
+		return "", err
+	}
+	for _, config := range described.Configs {
+		if config.Config == "errors.deadletterqueue.topic.name" && config.Value != "" {
+			return config.Value, nil
+		}
+	}
+	return "dlq-" + c.ID, nil
+}
+
+// A failedRecord is a record in a DLQ, decoded along with the error context in its headers.
+type failedRecord struct {
+	Partition         int32     `json:"partition"`
+	Offset            int64     `json:"offset"`
+	Timestamp         time.Time `json:"timestamp"`
+	Key               string    `json:"key"`
+	Value             string    `json:"value"`
+	OriginalTopic     string    `json:"original_topic,omitempty"`
+	OriginalPartition string    `json:"original_partition,omitempty"`
+	OriginalOffset    string    `json:"original_offset,omitempty"`
+	Stage             string    `json:"stage,omitempty"`
+	Exception         string    `json:"exception,omitempty"`
+	Message           string    `json:"message,omitempty"`
+}
+
+// A reason is why records failed, and how many of the sampled records failed because of it.
+type reason struct {
+	Count     int    `json:"count"`
+	Stage     string `json:"stage"`
+	Exception string `json:"exception"`
+	Message   string `json:"message"`
+}
+
+// sample reads up to limit of the newest records of the topic, across all of its partitions, newest first. It also
+// returns how many records the topic has.
+func sample(client *kafka.Client, topic string, limit int) ([]failedRecord, int64, error) {
+	partitions, err := client.Metadata(topic)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var records []failedRecord
+	var total int64
+	for _, p := range partitions {
+		earliest, err := client.Offset(topic, p, kafka.EarliestOffset)
+		if err != nil {
+			return nil, 0, err
+		}
+		end, err := client.Offset(topic, p, kafka.LatestOffset)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += end - earliest
+
+		// The newest records of every partition are read, since any of them may be among the newest of the topic.
+		offset := max(earliest, end-int64(limit))
+		for offset < end {
+			fetched, next, err := client.Fetch(topic, p, offset)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to read partition %d of %s: %w", p.ID, topic, err)
+			}
+			if next <= offset {
+				break
+			}
+			offset = next
+
+			for _, r := range fetched {
+				if r.Offset < end {
+					records = append(records, decode(p.ID, r))
+				}
+			}
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Timestamp.After(records[j].Timestamp) })
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, total, nil
+}
+
+func decode(partition int32, r kafka.Record) failedRecord {
+	f := failedRecord{
+		Partition: partition,
+		Offset:    r.Offset,
+		Timestamp: time.UnixMilli(r.Timestamp).UTC(),
+		Key:       decodeBytes(r.Key),
+		Value:     decodeBytes(r.Value),
+	}
+	for _, h := range r.Headers {
+		value := string(h.Value)
+		switch h.Key {
+		case headerTopic:
+			f.OriginalTopic = value
+		case headerPartition:
+			f.OriginalPartition = value
+		case headerOffset:
+			f.OriginalOffset = value
+		case headerStage:
+			f.Stage = value
+		case headerExceptionClass:
+			f.Exception = value
+		case headerExceptionMessage:
+			f.Message = value
+		}
+	}
+	return f
+}
+
+// decodeBytes decodes a key or value as text if it is, and otherwise as base64. Values serialized with Schema Registry
+// are prefixed with their schema ID, and JSON Schema values are still readable after it.
+func decodeBytes(b []byte) string {
+	if b == nil {
+		return ""
+	}
+
+	prefix := ""
+	if len(b) >= 5 && b[0] == 0 {
+		prefix = fmt.Sprintf("(schema %d) ", binary.BigEndian.Uint32(b[1:5]))
+		b = b[5:]
+	}
+
+	text := utf8.Valid(b)
+	for _, r := range string(b) {
+		if r < ' ' && r != '\n' && r != '\r' && r != '\t' {
+			text = false
+			break
+		}
+	}
+	if text {
+		return prefix + string(b)
+	}
+	return prefix + "base64:" + base64.StdEncoding.EncodeToString(b)
+}
+
+// summarize counts the records by why they failed, most common first.
+func summarize(records []failedRecord) []reason {
+	counts := map[reason]int{}
+	for _, r := range records {
+		message, _, _ := strings.Cut(r.Message, "\n")
+		counts[reason{Stage: r.Stage, Exception: r.Exception, Message: message}]++
+	}
+
+	reasons := make([]reason, 0, len(counts))
+	for r, count := range counts {
+		r.Count = count
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+		return reasons[i].Exception+reasons[i].Message < reasons[j].Exception+reasons[j].Message
+	})
+	return reasons
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// A report of the failed records in a connector's DLQ.
+type report struct {
+	Connector string         `json:"connector"`
+	ID        string         `json:"id"`
+	Topic     string         `json:"topic"`
+	Records   int64          `json:"records"`
+	Sampled   int            `json:"sampled"`
+	Reasons   []reason       `json:"reasons"`
+	Samples   []failedRecord `json:"samples,omitempty"`
+}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "dlq [connector]...",
+		Short: "Summarize why records in connectors' DLQs failed.",
+		Long:  "Find the dead letter queue (DLQ) topics of sink connectors, sample their newest records, and summarize why they failed by count, from the error context headers which Kafka Connect adds to them. The sampled records can be printed too, decoded along with the topic, partition, and offset they came from.",
+		RunE:  inspect,
+		Example: `confluent connect dlq --api-key ABCDEFGHIJKLMNOP
+confluent connect dlq orders-sink --api-key ABCDEFGHIJKLMNOP --limit 20 --show-records`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().Int("limit", 100, "How many of the newest records of each DLQ to sample.")
+	cmd.Flags().Bool("show-records", false, "Print the sampled records too.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("api-key"))
+
+	completion.Add(&cmd, "confluent-connect-dlq")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-connect-dlq"))
+
+	exitcode.Execute(&cmd)
+}
+
+func inspect(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	limit, err := cmd.Flags().GetInt("limit")
+	cobra.CheckErr(err)
+
+	showRecords, err := cmd.Flags().GetBool("show-records")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if apiSecret == "" {
+		return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required")
+	}
+	if limit < 1 {
+		return exitcode.Invalid("--limit must be at least 1")
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	var listed []cli.Connector
+	if err := cli.JSON(&listed, append([]string{"connect", "cluster", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	// Connectors can be passed by ID or name. Otherwise, every sink connector is inspected, since only sinks have DLQs.
+	var connectors []cli.Connector
+	if len(args) == 0 {
+		for _, c := range listed {
+			if c.Type == "sink" {
+				connectors = append(connectors, c)
+			}
+		}
+	}
+	for _, arg := range args {
+		found := false
+		for _, c := range listed {
+			if c.ID == arg || c.Name == arg {
+				connectors = append(connectors, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(`connector "%s" not found`, arg)
+		}
+	}
+
+	var topics []cli.Topic
+	if err := cli.JSON(&topics, append([]string{"kafka", "topic", "list"}, scope...)...); err != nil {
+		return err
+	}
+	exists := map[string]bool{}
+	for _, t := range topics {
+		exists[t.Name] = true
+	}
+
+	if bootstrap == "" {
+		described, err := cli.DescribeKafkaCluster(cluster, environment)
+		if err != nil {
+			return err
+		}
+		bootstrap = described.Endpoint
+	}
+
+	client := kafka.NewClient("confluent-connect-dlq", bootstrap, apiKey, apiSecret)
+	defer client.Close()
+
+	reports := []report{}
+	for _, c := range connectors {
+		topic, err := dlqTopic(c, scope)
+		if err != nil {
+			return err
+		}
+		if !exists[topic] {
+			if len(args) > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Connector \"%s\" (%s) has no DLQ topic.\n", c.Name, c.ID)
+			}
+			continue
+		}
+
+		records, total, err := sample(client, topic, limit)
+		if err != nil {
+			return err
+		}
+		r := report{Connector: c.Name, ID: c.ID, Topic: topic, Records: total, Sampled: len(records), Reasons: summarize(records)}
+		if showRecords {
+			r.Samples = records
+		}
+		reports = append(reports, r)
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		return output.Print(out, format, reports, nil)
+	}
+	print(out, reports)
+	return nil
+}
+
+func print(w io.Writer, reports []report) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No connectors have DLQ topics.")
+		return
+	}
+
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Connector \"%s\" (%s): %d records in %s, the newest %d sampled.\n", r.Connector, r.ID, r.Records, r.Topic, r.Sampled)
+		if r.Sampled == 0 {
+			continue
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Count\tStage\tException\tMessage")
+		for _, reason := range r.Reasons {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", reason.Count, dash(reason.Stage), dash(reason.Exception), truncate(dash(reason.Message)))
+		}
+		_ = tw.Flush()
+
+		if len(r.Samples) > 0 {
+			fmt.Fprintln(w, "Records:")
+		}
+		for _, s := range r.Samples {
+			fmt.Fprintf(w, "  %s partition %d offset %d", s.Timestamp.Format(time.RFC3339), s.Partition, s.Offset)
+			if s.OriginalTopic != "" {
+				fmt.Fprintf(w, ", from %s partition %s offset %s", s.OriginalTopic, s.OriginalPartition, s.OriginalOffset)
+			}
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "    Key: %s\n", truncate(s.Key))
+			fmt.Fprintf(w, "    Value: %s\n", truncate(s.Value))
+			if s.Exception != "" {
+				fmt.Fprintf(w, "    Error: %s: %s\n", s.Exception, truncate(s.Message))
+			}
+		}
+	}
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// truncate shortens a value to its first line, and at most 120 characters, to keep the report readable.
+func truncate(s string) string {
+	line, _, cut := strings.Cut(s, "\n")
+	if len(line) > 120 {
+		return line[:117] + "..."
+	}
+	if cut {
+		return line + "..."
+	}
+	return line
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/confluentinc/cli-plugins/internal/cloud"
+)
+
+// connectAPI describes, pauses, and resumes the connectors of a cluster with the Connect API of Confluent Cloud, in
+// REST mode, rather than with a CLI command for each.
+type connectAPI struct {
+	client      *cloud.Client
+	environment string
+	cluster     string
+}
+
+func (a *connectAPI) path(connector, action string) string {
+	return fmt.Sprintf("/connect/v1/environments/%s/clusters/%s/connectors/%s/%s", url.PathEscape(a.environment), url.PathEscape(a.cluster), url.PathEscape(connector), action)
+}
+
+// status returns the status of the connector, as "confluent connect cluster describe" shows it.
+func (a *connectAPI) status(connector string) (connectorStatus, error) {
+	var res struct {
+		Connector struct {
+			State string `json:"state"`
+			Trace string `json:"trace"`
+		} `json:"connector"`
+		Tasks []struct {
+			ID    int    `json:"id"`
+			State string `json:"state"`
+		} `json:"tasks"`
+	}
+	if err := a.client.Do(http.MethodGet, a.path(connector, "status"), nil, nil, &res); err != nil {
+		return connectorStatus{}, err
+	}
+
+	var s connectorStatus
+	s.Connector.Status = res.Connector.State
+	s.Connector.Trace = res.Connector.Trace
+	for _, t := range res.Tasks {
+		s.Tasks = append(s.Tasks, taskStatus{ID: t.ID, State: t.State})
+	}
+	return s, nil
+}
+
+// do pauses or resumes the connector.
+func (a *connectAPI) do(connector, action string) error {
+	return a.client.Do(http.MethodPut, a.path(connector, action), nil, nil, nil)
+}
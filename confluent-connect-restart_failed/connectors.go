@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/parallel"
+)
+
+// connectorStatus is the part of "confluent connect cluster describe" which shows whether a connector failed.
+type connectorStatus struct {
+	Connector struct {
+		Status string `json:"status"`
+		Trace  string `json:"trace"`
+	} `json:"connector"`
+	Tasks []taskStatus `json:"tasks"`
+}
+
+// taskStatus is the state of a task of a connector.
+type taskStatus struct {
+	ID    int    `json:"task_id"`
+	State string `json:"state"`
+}
+
+// A failure is a connector which failed, or which has failed tasks, and what happened when it was restarted.
+type failure struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	FailedTasks []int  `json:"failed_tasks"`
+	Cause       string `json:"cause"`
+	Restarted   bool   `json:"restarted"`
+	Error       string `json:"error,omitempty"`
+}
+
+// scan describes the connectors in parallel, with the Connect API if api isn't nil or else with the CLI, and returns
+// those which failed, in the order they were listed. Paused connectors are skipped, since they were paused on purpose.
+func scan(connectors []cli.Connector, scope []string, api *connectAPI, parallelism int) ([]failure, error) {
+	statuses := make([]connectorStatus, len(connectors))
+	errs := parallel.Each(connectors, parallelism, func(i int, c cli.Connector) error {
+		if c.Status == "PAUSED" {
+			return nil
+		}
+		if api != nil {
+			var err error
+			statuses[i], err = api.status(c.Name)
+			return err
+		}
+		return cli.JSON(&statuses[i], append([]string{"connect", "cluster", "describe", c.ID}, scope...)...)
+	})
+
+	var failures []failure
+	for i, c := range connectors {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		s := statuses[i]
+		f := failure{ID: c.ID, Name: c.Name, Status: s.Connector.Status, FailedTasks: []int{}}
+		for _, t := range s.Tasks {
+			if t.State == "FAILED" {
+				f.FailedTasks = append(f.FailedTasks, t.ID)
+			}
+		}
+		if f.Status != "FAILED" && len(f.FailedTasks) == 0 {
+			continue
+		}
+
+		// The trace is a Java stack trace, whose first line is the exception and its message.
+		f.Cause, _, _ = strings.Cut(strings.TrimSpace(s.Connector.Trace), "\n")
+		if f.Cause == "" && len(f.FailedTasks) > 0 {
+			f.Cause = fmt.Sprintf("%d of %d tasks failed", len(f.FailedTasks), len(s.Tasks))
+		}
+		failures = append(failures, f)
+	}
+	return failures, nil
+}
+
+// restart restarts the failed connectors in parallel, with the Connect API if api isn't nil or else with the CLI, and
+// returns the error of each one. The CLI can't restart a connector, but pausing and resuming it restarts it along with
+// all of its tasks.
+func restart(failures []failure, scope []string, api *connectAPI, parallelism int) []error {
+	errs := parallel.Each(failures, parallelism, func(i int, f failure) error {
+		for _, action := range []string{"pause", "resume"} {
+			var err error
+			if api != nil {
+				err = api.do(f.Name, action)
+			} else {
+				_, err = cli.Run(append([]string{"connect", "cluster", action, f.ID}, scope...)...)
+			}
+			if err != nil {
+				failures[i].Error = err.Error()
+				return fmt.Errorf(`failed to restart connector "%s": %w`, f.Name, err)
+			}
+		}
+		failures[i].Restarted = true
+		return nil
+	})
+	// Connectors which weren't restarted since the plugin was interrupted have no error of their own.
+	for i, err := range errs {
+		if err != nil && failures[i].Error == "" {
+			failures[i].Error = err.Error()
+		}
+	}
+	return errs
+}
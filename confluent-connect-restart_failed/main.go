@@ -0,0 +1,199 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/cloud"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/parallel"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "restart-failed",
+		Short: "Restart failed connectors in bulk.",
+		Long:  "Find the connectors in a Kafka cluster which failed, or which have failed tasks, print why they failed, and restart them, after prompting for confirmation.",
+		Args:  cobra.NoArgs,
+		RunE:  restartFailed,
+		Example: `confluent connect restart-failed --cluster lkc-123456 --dry-run
+confluent connect restart-failed --cluster lkc-123456 --force --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	dryrun.AddFlag(&cmd, "List the failed connectors without restarting them.")
+	cmd.Flags().Bool("force", false, "Restart the connectors without prompting for confirmation.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Int("parallelism", 8, "How many connectors to describe or restart at once.")
+	cloud.AddFlag(&cmd)
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	completion.Add(&cmd, "confluent-connect-restart_failed")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-connect-restart_failed"))
+
+	exitcode.Execute(&cmd)
+}
+
+func restartFailed(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	// In REST mode, the connectors are described and restarted with the Connect API, which needs the IDs of the
+	// cluster and environment, since it doesn't know the CLI's current ones.
+	var api *connectAPI
+	if cloud.Enabled() {
+		if cluster == "" || environment == "" {
+			return exitcode.Invalid("--rest needs --cluster and --environment")
+		}
+		client, err := cloud.New()
+		if err != nil {
+			return err
+		}
+		api = &connectAPI{client: client, environment: environment, cluster: cluster}
+	}
+
+	var connectors []cli.Connector
+	if err := cli.JSON(&connectors, append([]string{"connect", "cluster", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	failures, err := scan(connectors, scope, api, parallelism)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, f := range failures {
+			detail := fmt.Sprintf("(%s)", f.ID)
+			if f.Cause != "" {
+				detail += ", which failed with: " + truncate(f.Cause)
+			}
+			plan.AddDetail("restart", "connector", f.Name, detail)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+	if len(failures) == 0 {
+		return printFailures(cmd, format, failures)
+	}
+
+	if !force {
+		// The causes are printed before asking, so that it's clear what would be restarted.
+		if format == output.Table {
+			printTable(cmd.OutOrStdout(), failures, true)
+		}
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Found %d failed connectors, are you sure you want to restart them?", len(failures)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not restarting connectors.")
+			return nil
+		}
+	}
+
+	errs := restart(failures, scope, api, parallelism)
+
+	if err := printFailures(cmd, format, failures); err != nil {
+		return err
+	}
+	return parallel.Summary(errs, "restart", "connectors")
+}
+
+func printFailures(cmd *cobra.Command, format string, failures []failure) error {
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if failures == nil {
+			failures = []failure{}
+		}
+		return output.Print(out, format, failures, nil)
+	}
+
+	if len(failures) == 0 {
+		fmt.Fprintln(out, "No failed connectors found.")
+		return nil
+	}
+
+	restarted := printTable(out, failures, false)
+	fmt.Fprintf(out, "\nRestarted %d of %d connectors.\n", restarted, len(failures))
+	return nil
+}
+
+// printTable prints the failures with what happened to them, or that they would be restarted if they're pending
+// confirmation, and returns how many were restarted.
+func printTable(w io.Writer, failures []failure, pending bool) int {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tName\tStatus\tFailed Tasks\tCause\tResult")
+	restarted := 0
+	for _, f := range failures {
+		tasks := make([]string, len(f.FailedTasks))
+		for i, t := range f.FailedTasks {
+			tasks[i] = fmt.Sprint(t)
+		}
+		result := "would restart"
+		switch {
+		case f.Restarted:
+			result = "restarted"
+			restarted++
+		case f.Error != "":
+			result = "failed: " + f.Error
+		case !pending:
+			result = ""
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", f.ID, f.Name, f.Status, strings.Join(tasks, ","), truncate(f.Cause), result)
+	}
+	_ = tw.Flush()
+	return restarted
+}
+
+// truncate shortens a cause to keep the table readable.
+func truncate(s string) string {
+	if len(s) > 100 {
+		return s[:97] + "..."
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+)
+
+// pollInterval is how often a connector's status is checked while waiting for it to run.
+const pollInterval = 10 * time.Second
+
+// masked matches a value which Confluent Cloud hides, such as a password.
+var masked = regexp.MustCompile(`^\*+$`)
+
+// describedConnector is the part of "confluent connect cluster describe" which is rotated and waited on.
+type describedConnector struct {
+	Connector struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Trace  string `json:"trace"`
+	} `json:"connector"`
+	Tasks []struct {
+		ID    int    `json:"task_id"`
+		State string `json:"state"`
+	} `json:"tasks"`
+	Configs []struct {
+		Config string `json:"config"`
+		Value  string `json:"value"`
+	} `json:"configs"`
+}
+
+// find finds a connector by ID or name.
+func find(connector string, scope []string) (cli.Connector, error) {
+	var listed []cli.Connector
+	if err := cli.JSON(&listed, append([]string{"connect", "cluster", "list"}, scope...)...); err != nil {
+		return cli.Connector{}, err
+	}
+	for _, l := range listed {
+		if l.ID == connector || l.Name == connector {
+			return l, nil
+		}
+	}
+	return cli.Connector{}, fmt.Errorf(`connector "%s" not found`, connector)
+}
+
+func describe(id string, scope []string) (describedConnector, error) {
+	var d describedConnector
+	err := cli.JSON(&d, append([]string{"connect", "cluster", "describe", id}, scope...)...)
+	return d, err
+}
+
+// update replaces the connector's configs. The configs are written to a temporary file for the confluent CLI, which is
+// only readable by the current user, since it contains the secrets, and is removed straight away.
+func update(c cli.Connector, configs map[string]string, scope []string) error {
+	file := struct {
+		Name   string            `json:"name"`
+		Config map[string]string `json:"config"`
+	}{Name: c.Name, Config: configs}
+	b, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	path, err := platform.WriteTemp("connect-secret-rotate-*.json", b)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	_, err = cli.Run(append([]string{"connect", "cluster", "update", c.ID, "--config-file", path}, scope...)...)
+	return err
+}
+
+// wait waits until the connector and all of its tasks are running, and fails as soon as any of them fails. The status
+// is only checked after an interval, since right after an update it may still be that of the previous configs.
+func wait(c cli.Connector, scope []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		time.Sleep(pollInterval)
+
+		s, err := describe(c.ID, scope)
+		if err != nil {
+			return err
+		}
+
+		switch s.Connector.Status {
+		case "FAILED":
+			if trace, _, _ := strings.Cut(strings.TrimSpace(s.Connector.Trace), "\n"); trace != "" {
+				return fmt.Errorf(`connector "%s" (%s) failed: %s`, c.Name, c.ID, trace)
+			}
+			return fmt.Errorf(`connector "%s" (%s) failed`, c.Name, c.ID)
+		case "PAUSED":
+			return fmt.Errorf(`connector "%s" (%s) is paused, and must be resumed with "confluent connect cluster resume %s"`, c.Name, c.ID, c.ID)
+		case "RUNNING":
+			running := true
+			for _, t := range s.Tasks {
+				if t.State == "FAILED" {
+					return fmt.Errorf(`task %d of connector "%s" (%s) failed`, t.ID, c.Name, c.ID)
+				}
+				if t.State != "RUNNING" {
+					running = false
+				}
+			}
+			if running {
+				return nil
+			}
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			return fmt.Errorf(`timed out waiting for connector "%s" (%s) to run, its status is %s`, c.Name, c.ID, s.Connector.Status)
+		}
+	}
+}
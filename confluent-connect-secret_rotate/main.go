@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "secret-rotate <connector>",
+		Short: "Rotate the credentials of a fully-managed connector.",
+		Long:  "Update configs of a fully-managed connector, such as its Kafka API key and secret, in place, with values which may be read from environment variables, HashiCorp Vault, or AWS Secrets Manager. The connector is then waited on to run again, and if it doesn't, its previous configs are restored. The connector is either its ID or its name.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  secretRotate,
+		Example: `confluent connect secret-rotate orders-sink --config kafka.api.key=ABCDEFGHIJKLMNOP --config 'kafka.api.secret=${KAFKA_API_SECRET}' --previous 'kafka.api.secret=${OLD_KAFKA_API_SECRET}'
+confluent connect secret-rotate lcc-123456 --config 'aws.secret.access.key=${vault:secret/data/connect#secret_access_key}' --no-rollback`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().StringArray("config", nil, `Config to set, as a "<key>=<value>" pair. May be repeated.`)
+	cmd.Flags().StringArray("previous", nil, `Previous value of a secret config to roll back to, as a "<key>=<value>" pair, since Confluent Cloud masks secrets. May be repeated.`)
+	cmd.Flags().Bool("no-rollback", false, "Don't restore the previous configs if the connector doesn't run with the new ones.")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "How long to wait for the connector to run with the new configs.")
+	dryrun.AddFlag(&cmd, "Render the configs and print which would be updated, without updating them.")
+	output.AddFlag(&cmd, "Format of the configs which were updated")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("config"))
+	cmd.MarkFlagsMutuallyExclusive("previous", "no-rollback")
+
+	completion.Add(&cmd, "confluent-connect-secret_rotate")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-connect-secret_rotate"))
+
+	exitcode.Execute(&cmd)
+}
+
+func secretRotate(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	configFlags, err := cmd.Flags().GetStringArray("config")
+	cobra.CheckErr(err)
+
+	previousFlags, err := cmd.Flags().GetStringArray("previous")
+	cobra.CheckErr(err)
+
+	noRollback, err := cmd.Flags().GetBool("no-rollback")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	configs, err := parseConfigs("config", configFlags)
+	if err != nil {
+		return err
+	}
+	previous, err := parseConfigs("previous", previousFlags)
+	if err != nil {
+		return err
+	}
+	for key := range previous {
+		if _, ok := configs[key]; !ok {
+			return exitcode.Invalid(`--previous "%s" is not a config which is set with --config`, key)
+		}
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	c, err := find(args[0], scope)
+	if err != nil {
+		return err
+	}
+	described, err := describe(c.ID, scope)
+	if err != nil {
+		return err
+	}
+	deployed := map[string]string{}
+	for _, config := range described.Configs {
+		deployed[config.Config] = config.Value
+	}
+
+	// The update replaces every config, but the secrets which aren't rotated can't be sent back as they were, since
+	// Confluent Cloud masks them.
+	var unknown []string
+	for key, value := range deployed {
+		if _, ok := configs[key]; !ok && masked.MatchString(value) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf(`connector "%s" has secret configs which Confluent Cloud masks, and which must be passed with --config too, since every config is replaced: %s`, c.Name, strings.Join(unknown, ", "))
+	}
+
+	// The previous values of rotated secrets are masked too, so they can only be rolled back to if they're passed.
+	rollback := map[string]string{}
+	var missing []string
+	for key, value := range deployed {
+		rollback[key] = value
+		if _, ok := configs[key]; !ok {
+			continue
+		}
+		if p, ok := previous[key]; ok {
+			rollback[key] = p
+		} else if masked.MatchString(value) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 && !noRollback {
+		sort.Strings(missing)
+		return fmt.Errorf("the previous values of the secret configs %s are masked by Confluent Cloud, pass them with --previous to be able to roll back, or pass --no-rollback", strings.Join(missing, ", "))
+	}
+
+	r := secrets.NewResolver(context.Background())
+	updated := map[string]string{}
+	for key, value := range deployed {
+		updated[key] = value
+	}
+	for key, value := range configs {
+		if updated[key], err = r.Render(value); err != nil {
+			return fmt.Errorf(`failed to render config "%s": %w`, key, err)
+		}
+	}
+	for key := range previous {
+		if rollback[key], err = r.Render(rollback[key]); err != nil {
+			return fmt.Errorf(`failed to render the previous value of config "%s": %w`, key, err)
+		}
+	}
+	updated["name"] = c.Name
+	rollback["name"] = c.Name
+
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var plan dryrun.Plan
+	plan.AddDetail("update", "connector", c.Name, fmt.Sprintf("(%s) with new values of %s", c.ID, strings.Join(keys, ", ")))
+	if dryRun {
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := output.Progress(cmd)
+
+	if err := update(c, updated, scope); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Updated configs %s of connector \"%s\" (%s).\n", strings.Join(keys, ", "), c.Name, c.ID)
+
+	err = wait(c, scope, timeout)
+	if err == nil {
+		fmt.Fprintf(out, "Connector \"%s\" (%s) is running.\n", c.Name, c.ID)
+		return dryrun.PrintApplied(cmd, plan)
+	}
+	if noRollback {
+		return errors.Join(err, dryrun.PrintApplied(cmd, plan))
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Connector \"%s\" (%s) didn't run with the new configs, rolling back: %v\n", c.Name, c.ID, err)
+	if rollbackErr := update(c, rollback, scope); rollbackErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to roll back: %w", rollbackErr), dryrun.PrintApplied(cmd, plan))
+	}
+	plan.AddDetail("roll back", "connector", c.Name, fmt.Sprintf("(%s) to the previous values of %s", c.ID, strings.Join(keys, ", ")))
+	if rollbackErr := wait(c, scope, timeout); rollbackErr != nil {
+		return errors.Join(err, fmt.Errorf("rolled back, but the connector doesn't run with the previous configs either: %w", rollbackErr), dryrun.PrintApplied(cmd, plan))
+	}
+	return errors.Join(fmt.Errorf(`rolled back connector "%s" (%s) to its previous configs, which it runs with: %w`, c.Name, c.ID, err), dryrun.PrintApplied(cmd, plan))
+}
+
+// parseConfigs parses "<key>=<value>" pairs of a flag.
+func parseConfigs(flag string, pairs []string) (map[string]string, error) {
+	configs := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf(`invalid --%s "%s", expected a "<key>=<value>" pair`, flag, pair)
+		}
+		if _, ok := configs[key]; ok {
+			return nil, exitcode.Invalid(`--%s "%s" is passed more than once`, flag, key)
+		}
+		configs[key] = value
+	}
+	return configs, nil
+}
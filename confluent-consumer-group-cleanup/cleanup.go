@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// An idleGroup is an empty consumer group which hasn't consumed any record produced since the cutoff.
+type idleGroup struct {
+	Group string `json:"group"`
+	// Topics are the topics which the group has committed offsets for.
+	Topics []string `json:"topics"`
+}
+
+// A result is how many consumer groups were checked, and the idle ones, sorted by group.
+type result struct {
+	Groups   int         `json:"groups"`
+	Excluded int         `json:"excluded"`
+	Active   int         `json:"active"`
+	Idle     []idleGroup `json:"idle"`
+}
+
+// A finder finds the idle consumer groups of a cluster. Kafka doesn't record when a group committed its offsets, so a
+// group is idle if it has no members, and none of its offsets is past a record produced since the cutoff: a group
+// which is still consuming a topic which still has records produced to it would have committed a later offset.
+type finder struct {
+	client  *kafka.Client
+	exclude []*regexp.Regexp
+	cutoff  time.Time
+
+	// partitions are the partitions of each topic, which are shared by the groups which consume it.
+	partitions map[string][]kafka.Partition
+}
+
+func (f *finder) find(groups []string) (result, error) {
+	r := result{Idle: []idleGroup{}}
+	sort.Strings(groups)
+	for _, group := range groups {
+		r.Groups++
+		if f.excluded(group) {
+			r.Excluded++
+			continue
+		}
+
+		idle, topics, err := f.idle(group)
+		if err != nil {
+			return result{}, err
+		}
+		if !idle {
+			r.Active++
+			continue
+		}
+		r.Idle = append(r.Idle, idleGroup{Group: group, Topics: topics})
+	}
+	return r, nil
+}
+
+// idle returns whether the group is idle, and the topics which it has committed offsets for.
+func (f *finder) idle(group string) (bool, []string, error) {
+	if err := f.client.FindCoordinator(group); err != nil {
+		return false, nil, err
+	}
+	state, members, err := f.client.DescribeGroup(group)
+	if err != nil {
+		return false, nil, err
+	}
+	if members > 0 || state != "Empty" {
+		return false, nil, nil
+	}
+
+	offsets, err := f.client.GroupOffsets(group)
+	if err != nil {
+		return false, nil, err
+	}
+	topics := sortedKeys(offsets)
+	for _, topic := range topics {
+		partitions, err := f.topicPartitions(topic)
+		if err != nil {
+			return false, nil, err
+		}
+		// The offsets of the partitions of a deleted topic, or of partitions beyond its current ones, are stale.
+		for _, p := range partitions {
+			committed, ok := offsets[topic][p.ID]
+			if !ok {
+				continue
+			}
+			since, err := f.client.Offset(topic, p, f.cutoff.UnixMilli())
+			if err != nil {
+				return false, nil, fmt.Errorf(`failed to read the offsets of partition %d of topic "%s": %w`, p.ID, topic, err)
+			}
+			if since >= 0 && committed > since {
+				return false, nil, nil
+			}
+		}
+	}
+	return true, topics, nil
+}
+
+func (f *finder) topicPartitions(topic string) ([]kafka.Partition, error) {
+	if partitions, ok := f.partitions[topic]; ok {
+		return partitions, nil
+	}
+	// A topic which doesn't exist has no partitions.
+	partitions, err := f.client.Metadata(topic)
+	if err != nil && !errors.Is(err, kafka.ErrUnknownTopic) {
+		return nil, err
+	}
+	f.partitions[topic] = partitions
+	return partitions, nil
+}
+
+func (f *finder) excluded(group string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// readExcludeFile reads the patterns of an exclude file, one per line. Blank lines and lines starting with # are
+// skipped.
+func readExcludeFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the exclude file: %w", err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the exclude file: %w", err)
+	}
+	return patterns, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
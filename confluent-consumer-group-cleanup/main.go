@@ -0,0 +1,231 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "cleanup",
+		Short: "Delete idle consumer groups in bulk.",
+		Long:  "Find the consumer groups of a Kafka cluster which have no members and haven't consumed any record produced in a period, such as the dead groups of old test runs, and delete them and their committed offsets once confirmed. Groups which match an exclude pattern are never deleted.",
+		Args:  cobra.NoArgs,
+		RunE:  cleanup,
+		Example: `confluent consumer group cleanup --idle 720h --api-key ABCDEFGHIJKLMNOP --dry-run
+confluent consumer group cleanup --idle 168h --exclude '^prod-' --exclude-file keep.txt --force`,
+	}
+
+	cmd.Flags().Duration("idle", 30*24*time.Hour, "How long a group must not have consumed anything for to be deleted.")
+	cmd.Flags().StringSlice("exclude", nil, "Regular expressions of the groups to never delete.")
+	cmd.Flags().String("exclude-file", "", "File of regular expressions of the groups to never delete, one per line.")
+	dryrun.AddFlag(&cmd, "Print the groups which would be deleted without deleting them.")
+	cmd.Flags().Bool("force", false, "Delete the groups without prompting for confirmation.")
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	output.AddFlag(&cmd, "Format of the idle groups")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+	cobra.CheckErr(cmd.MarkFlagRequired("api-key"))
+
+	completion.Add(&cmd, "confluent-consumer-group-cleanup")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-consumer-group-cleanup"))
+
+	exitcode.Execute(&cmd)
+}
+
+func cleanup(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	idle, err := cmd.Flags().GetDuration("idle")
+	cobra.CheckErr(err)
+
+	patterns, err := cmd.Flags().GetStringSlice("exclude")
+	cobra.CheckErr(err)
+
+	excludeFile, err := cmd.Flags().GetString("exclude-file")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if idle <= 0 {
+		return exitcode.Invalid("--idle must be more than 0")
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if apiSecret == "" {
+		return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required")
+	}
+
+	if excludeFile != "" {
+		filePatterns, err := readExcludeFile(excludeFile)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, filePatterns...)
+	}
+	var exclude []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf(`invalid exclude pattern "%s": %w`, pattern, err)
+		}
+		exclude = append(exclude, re)
+	}
+
+	if bootstrap == "" {
+		described, err := cli.DescribeKafkaCluster(clusterID, environment)
+		if err != nil {
+			return err
+		}
+		bootstrap = described.Endpoint
+	}
+
+	var list []struct {
+		ConsumerGroup string `json:"consumer_group"`
+	}
+	if err := cli.JSON(&list, append([]string{"kafka", "consumer", "group", "list"}, cli.ClusterFlags(clusterID, environment)...)...); err != nil {
+		return err
+	}
+	groups := make([]string, len(list))
+	for i, g := range list {
+		groups[i] = g.ConsumerGroup
+	}
+
+	client := kafka.NewClient("confluent-consumer-group-cleanup", bootstrap, apiKey, apiSecret)
+	defer client.Close()
+
+	f := &finder{client: client, exclude: exclude, cutoff: time.Now().Add(-idle), partitions: map[string][]kafka.Partition{}}
+	r, err := f.find(groups)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, g := range r.Idle {
+			detail := "with no committed offsets"
+			if len(g.Topics) > 0 {
+				detail = "with the committed offsets of " + strings.Join(g.Topics, ", ")
+			}
+			plan.AddDetail("delete", "consumer group", g.Group, detail)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, r, func() { print(out, r, idle) }); err != nil {
+		return err
+	}
+
+	if len(r.Idle) == 0 {
+		return nil
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to delete %d consumer groups? Their committed offsets can't be recovered.", len(r.Idle)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not deleting consumer groups.")
+			return nil
+		}
+	}
+
+	// A group which failed to be deleted, such as one which a consumer joined since it was found, doesn't stop the
+	// others from being deleted.
+	var errs []error
+	deleted := 0
+	for _, g := range r.Idle {
+		if err := client.FindCoordinator(g.Group); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := client.DeleteGroup(g.Group); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted++
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %d consumer groups.\n", deleted)
+	return errors.Join(errs...)
+}
+
+func print(w io.Writer, r result, idle time.Duration) {
+	if len(r.Idle) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Group\tTopics")
+		for _, g := range r.Idle {
+			topics := strings.Join(g.Topics, ", ")
+			if topics == "" {
+				topics = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\n", g.Group, topics)
+		}
+		_ = tw.Flush()
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d of %d consumer groups have been idle for %s (%d active, %d excluded).\n", len(r.Idle), r.Groups, formatDuration(idle), r.Active, r.Excluded)
+}
+
+// formatDuration formats a duration in days, if it's a whole number of them.
+func formatDuration(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "reset <group>",
+		Short: "Preview and reset the offsets of a consumer group.",
+		Long:  "Reset the committed offsets of a consumer group to the earliest or latest offsets, to the first offsets at a timestamp, or to specific offsets. The effect of the reset is previewed, with how many records each partition would reprocess or skip, and the offsets are only committed with --execute. The group must have no active members.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  reset,
+		Example: `confluent consumer group reset orders-service --to earliest --topic orders --api-key ABCDEFGHIJKLMNOP
+confluent consumer group reset orders-service --to timestamp --timestamp 2024-05-01T00:00:00Z --execute
+confluent consumer group reset orders-service --to offset --offsets-file offsets.csv --execute`,
+	}
+
+	cmd.Flags().String("to", "", "Where to reset the offsets to: earliest, latest, timestamp, or offset.")
+	cmd.Flags().String("timestamp", "", "RFC 3339 timestamp to reset the offsets to, with --to timestamp.")
+	cmd.Flags().Int64("offset", -1, "Offset to reset every partition to, with --to offset.")
+	cmd.Flags().String("offsets-file", "", `CSV file of "<topic>,<partition>,<offset>" lines to reset partitions to, with --to offset.`)
+	cmd.Flags().StringSlice("topic", nil, "Topics to reset the offsets of. Defaults to the topics which the group has committed offsets for.")
+	cmd.Flags().Bool("execute", false, "Commit the new offsets, instead of only previewing them.")
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	output.AddFlag(&cmd, "Format of the preview")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("to"))
+	cobra.CheckErr(cmd.MarkFlagRequired("api-key"))
+
+	completion.Add(&cmd, "confluent-consumer-group-reset")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-consumer-group-reset"))
+
+	exitcode.Execute(&cmd)
+}
+
+func reset(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	group := args[0]
+
+	to, err := cmd.Flags().GetString("to")
+	cobra.CheckErr(err)
+
+	timestamp, err := cmd.Flags().GetString("timestamp")
+	cobra.CheckErr(err)
+
+	offset, err := cmd.Flags().GetInt64("offset")
+	cobra.CheckErr(err)
+
+	offsetsFile, err := cmd.Flags().GetString("offsets-file")
+	cobra.CheckErr(err)
+
+	topics, err := cmd.Flags().GetStringSlice("topic")
+	cobra.CheckErr(err)
+
+	execute, err := cmd.Flags().GetBool("execute")
+	cobra.CheckErr(err)
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if apiSecret == "" {
+		return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required")
+	}
+
+	t := target{to: to, offset: offset}
+	switch to {
+	case "earliest", "latest":
+	case "timestamp":
+		if timestamp == "" {
+			return exitcode.Invalid("--timestamp is required with --to timestamp")
+		}
+		ts, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return fmt.Errorf(`invalid timestamp "%s", expected an RFC 3339 timestamp such as 2024-05-01T00:00:00Z`, timestamp)
+		}
+		t.timestamp = ts.UnixMilli()
+	case "offset":
+		if offset < 0 && offsetsFile == "" {
+			return exitcode.Invalid("--offset or --offsets-file is required with --to offset")
+		}
+		if offsetsFile != "" {
+			if t.offsets, err = readOffsetsFile(offsetsFile); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf(`unsupported target "%s", supported targets: earliest, latest, timestamp, offset`, to)
+	}
+
+	scope := cli.ClusterFlags(clusterID, environment)
+
+	if len(topics) == 0 && offset < 0 && t.offsets != nil {
+		for topic := range t.offsets {
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+	}
+	if len(topics) == 0 {
+		if topics, err = committedTopics(group, scope); err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return fmt.Errorf(`consumer group "%s" has no committed offsets, pass the topics to reset with --topic`, group)
+		}
+	}
+
+	if bootstrap == "" {
+		described, err := cli.DescribeKafkaCluster(clusterID, environment)
+		if err != nil {
+			return err
+		}
+		bootstrap = described.Endpoint
+	}
+
+	client := kafka.NewClient("confluent-consumer-group-reset", bootstrap, apiKey, apiSecret)
+	defer client.Close()
+
+	if err := client.FindCoordinator(group); err != nil {
+		return err
+	}
+
+	var resets []partitionReset
+	for _, topic := range topics {
+		r, err := plan(client, group, topic, t)
+		if err != nil {
+			return err
+		}
+		resets = append(resets, r...)
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if resets == nil {
+			resets = []partitionReset{}
+		}
+		if err := output.Print(out, format, resets, nil); err != nil {
+			return err
+		}
+	} else {
+		print(out, group, resets)
+	}
+
+	if !execute {
+		if format == output.Table {
+			fmt.Fprintln(out, "Pass --execute to reset the offsets.")
+		}
+		return nil
+	}
+
+	// Offsets which are committed while the group has members would be overwritten by the members' next commits.
+	state, members, err := client.DescribeGroup(group)
+	if err != nil {
+		return err
+	}
+	if members > 0 {
+		return fmt.Errorf(`consumer group "%s" is %s with %d members, which must be stopped before its offsets can be reset`, group, state, members)
+	}
+
+	byTopic := map[string]map[int32]int64{}
+	for _, r := range resets {
+		if byTopic[r.Topic] == nil {
+			byTopic[r.Topic] = map[int32]int64{}
+		}
+		byTopic[r.Topic][r.Partition] = r.NewOffset
+	}
+	for _, topic := range topics {
+		if len(byTopic[topic]) == 0 {
+			continue
+		}
+		if err := client.CommitOffsets(group, topic, byTopic[topic]); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Reset the offsets of %d partitions of consumer group \"%s\".\n", len(resets), group)
+	return nil
+}
+
+// committedTopics returns the topics which the group has committed offsets for.
+func committedTopics(group string, scope []string) ([]string, error) {
+	var lags []struct {
+		Topic string `json:"topic"`
+	}
+	if err := cli.JSON(&lags, append([]string{"kafka", "consumer", "group", "lag", "list", group}, scope...)...); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var topics []string
+	for _, l := range lags {
+		if !seen[l.Topic] {
+			seen[l.Topic] = true
+			topics = append(topics, l.Topic)
+		}
+	}
+	sort.Strings(topics)
+	return topics, nil
+}
+
+func print(w io.Writer, group string, resets []partitionReset) {
+	if len(resets) == 0 {
+		fmt.Fprintln(w, "No partitions to reset.")
+		return
+	}
+
+	var reprocessed, skipped int64
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Topic\tPartition\tCurrent Offset\tNew Offset\tLog End Offset\tEffect")
+	for _, r := range resets {
+		current, effect := "-", "-"
+		if r.CurrentOffset != nil {
+			current = fmt.Sprint(*r.CurrentOffset)
+			switch {
+			case r.Reprocessed > 0:
+				effect = fmt.Sprintf("reprocess %d", r.Reprocessed)
+			case r.Skipped > 0:
+				effect = fmt.Sprintf("skip %d", r.Skipped)
+			default:
+				effect = "unchanged"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\t%s\n", r.Topic, r.Partition, current, r.NewOffset, r.LogEnd, effect)
+		reprocessed += r.Reprocessed
+		skipped += r.Skipped
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nResetting consumer group \"%s\" reprocesses %d records and skips %d records.\n", group, reprocessed, skipped)
+}
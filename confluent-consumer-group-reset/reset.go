@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// A target is where to reset the group's offsets to: earliest, latest, timestamp, or offset.
+type target struct {
+	to        string
+	timestamp int64
+	// offset applies to every partition, unless it's -1, and offsets to the partitions of the offsets file.
+	offset  int64
+	offsets map[string]map[int32]int64
+}
+
+// partitionReset is the effect of resetting the group's offset of one partition. The current offset is nil if the group
+// hasn't committed one, in which case its consumers start from their auto.offset.reset.
+type partitionReset struct {
+	Topic         string `json:"topic"`
+	Partition     int32  `json:"partition"`
+	CurrentOffset *int64 `json:"current_offset"`
+	NewOffset     int64  `json:"new_offset"`
+	LogStart      int64  `json:"log_start_offset"`
+	LogEnd        int64  `json:"log_end_offset"`
+	Reprocessed   int64  `json:"reprocessed"`
+	Skipped       int64  `json:"skipped"`
+}
+
+// readOffsetsFile reads the offset of each partition from a CSV file of "<topic>,<partition>,<offset>" lines, like the
+// files of kafka-consumer-groups --from-file.
+func readOffsetsFile(path string) (map[string]map[int32]int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offsets: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 3
+	r.Comment = '#'
+	lines, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse offsets from "%s": %w`, path, err)
+	}
+
+	offsets := map[string]map[int32]int64{}
+	for i, line := range lines {
+		partition, err := strconv.ParseInt(line[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf(`invalid partition "%s" on line %d of "%s"`, line[1], i+1, path)
+		}
+		offset, err := strconv.ParseInt(line[2], 10, 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf(`invalid offset "%s" on line %d of "%s"`, line[2], i+1, path)
+		}
+		if offsets[line[0]] == nil {
+			offsets[line[0]] = map[int32]int64{}
+		}
+		offsets[line[0]][int32(partition)] = offset
+	}
+	return offsets, nil
+}
+
+// plan works out the new offset of each of the topic's partitions, without committing them. New offsets are kept
+// within the partition's log, like kafka-consumer-groups does.
+func plan(client *kafka.Client, group, topic string, t target) ([]partitionReset, error) {
+	partitions, err := client.Metadata(topic)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := client.CommittedOffsets(group, topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	var resets []partitionReset
+	for _, p := range partitions {
+		newOffset := int64(-1)
+		switch t.to {
+		case "offset":
+			newOffset = t.offset
+			if offset, ok := t.offsets[topic][p.ID]; ok {
+				newOffset = offset
+			}
+			if newOffset < 0 {
+				// The partition isn't in the offsets file, so it's left as it is.
+				continue
+			}
+		case "timestamp":
+			if newOffset, err = client.Offset(topic, p, t.timestamp); err != nil {
+				return nil, err
+			}
+		}
+
+		logStart, err := client.Offset(topic, p, kafka.EarliestOffset)
+		if err != nil {
+			return nil, err
+		}
+		logEnd, err := client.Offset(topic, p, kafka.LatestOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case t.to == "earliest":
+			newOffset = logStart
+		case t.to == "latest", newOffset < 0, newOffset > logEnd:
+			// No record is as new as the timestamp, or the offset is past the end.
+			newOffset = logEnd
+		case newOffset < logStart:
+			newOffset = logStart
+		}
+
+		r := partitionReset{Topic: topic, Partition: p.ID, NewOffset: newOffset, LogStart: logStart, LogEnd: logEnd}
+		if current, ok := committed[p.ID]; ok && current >= 0 {
+			r.CurrentOffset = &current
+			if newOffset < current {
+				r.Reprocessed = current - newOffset
+			} else {
+				r.Skipped = newOffset - current
+			}
+		}
+		resets = append(resets, r)
+	}
+
+	sort.Slice(resets, func(i, j int) bool { return resets[i].Partition < resets[j].Partition })
+	return resets, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+type partitionLag struct {
+	Partition     int    `json:"partition"`
+	CurrentOffset int64  `json:"current_offset"`
+	LogEndOffset  int64  `json:"log_end_offset"`
+	Lag           int64  `json:"lag"`
+	ConsumerID    string `json:"consumer_id,omitempty"`
+}
+
+// topicLag is the lag of one consumer group on one topic, which is the sum of the lag of its partitions.
+type topicLag struct {
+	Group      string         `json:"group"`
+	Topic      string         `json:"topic"`
+	Lag        int64          `json:"lag"`
+	Partitions []partitionLag `json:"partitions"`
+}
+
+type reader struct {
+	cluster     []string
+	groups      []string
+	topics      map[string]bool
+	parallelism int
+}
+
+// read returns the lag of every group on every topic which it has committed offsets for, sorted by group and topic.
+func (r reader) read() ([]topicLag, error) {
+	groups := r.groups
+	if len(groups) == 0 {
+		var list []struct {
+			ConsumerGroup string `json:"consumer_group"`
+		}
+		if err := cli.JSON(&list, append([]string{"kafka", "consumer", "group", "list"}, r.cluster...)...); err != nil {
+			return nil, err
+		}
+		for _, g := range list {
+			groups = append(groups, g.ConsumerGroup)
+		}
+	}
+
+	lags := make([][]topicLag, len(groups))
+	errs := make([]error, len(groups))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, r.parallelism)
+	for i, group := range groups {
+		wg.Add(1)
+		go func(i int, group string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			lags[i], errs[i] = r.readGroup(group)
+		}(i, group)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var all []topicLag
+	for _, l := range lags {
+		all = append(all, l...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Group != all[j].Group {
+			return all[i].Group < all[j].Group
+		}
+		return all[i].Topic < all[j].Topic
+	})
+	return all, nil
+}
+
+func (r reader) readGroup(group string) ([]topicLag, error) {
+	var partitions []struct {
+		Topic string `json:"topic"`
+		partitionLag
+	}
+	if err := cli.JSON(&partitions, append([]string{"kafka", "consumer", "group", "lag", "list", group}, r.cluster...)...); err != nil {
+		return nil, fmt.Errorf(`failed to read the lag of consumer group "%s": %w`, group, err)
+	}
+
+	byTopic := map[string]*topicLag{}
+	var lags []topicLag
+	for _, p := range partitions {
+		if r.topics != nil && !r.topics[p.Topic] {
+			continue
+		}
+		t, ok := byTopic[p.Topic]
+		if !ok {
+			t = &topicLag{Group: group, Topic: p.Topic}
+			byTopic[p.Topic] = t
+		}
+		t.Lag += p.Lag
+		t.Partitions = append(t.Partitions, p.partitionLag)
+	}
+	for _, t := range byTopic {
+		sort.Slice(t.Partitions, func(i, j int) bool { return t.Partitions[i].Partition < t.Partitions[j].Partition })
+		lags = append(lags, *t)
+	}
+	return lags, nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errLagging fails the plugin with exitcode.Findings when a group's lag exceeds the threshold, so that alerting scripts
+// can act on it.
+var errLagging = exitcode.New(exitcode.Findings, "consumer lag exceeds the threshold")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "lag",
+		Short: "Report the lag of the consumer groups in a Kafka cluster.",
+		Long:  "Report the lag of every consumer group in a Kafka cluster, or of the groups passed with --group, per topic or per partition. With --threshold, exits with code 2 if the lag of any group on a topic exceeds it.",
+		Args:  cobra.NoArgs,
+		RunE:  lag,
+		Example: `confluent consumer lag --cluster lkc-123456 --partitions
+confluent consumer lag --group orders-service --threshold 10000 --output json
+confluent consumer lag --watch 10s`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().StringSlice("group", nil, "Consumer groups to report the lag of. Defaults to every group.")
+	cmd.Flags().StringSlice("topic", nil, "Only report the lag on these topics.")
+	cmd.Flags().Bool("partitions", false, "Report the lag of each partition, instead of each topic.")
+	cmd.Flags().Int64("threshold", 0, "Exit with code 2 if the lag of any group on a topic exceeds this.")
+	cmd.Flags().Duration("watch", 0, "Refresh the report at this interval until interrupted.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Int("parallelism", 8, "How many consumer groups to read at once.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-consumer-lag")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-consumer-lag"))
+
+	exitcode.Execute(&cmd)
+}
+
+func lag(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	groups, err := cmd.Flags().GetStringSlice("group")
+	cobra.CheckErr(err)
+
+	topics, err := cmd.Flags().GetStringSlice("topic")
+	cobra.CheckErr(err)
+
+	partitions, err := cmd.Flags().GetBool("partitions")
+	cobra.CheckErr(err)
+
+	threshold, err := cmd.Flags().GetInt64("threshold")
+	cobra.CheckErr(err)
+
+	watch, err := cmd.Flags().GetDuration("watch")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	if watch < 0 {
+		return exitcode.Invalid("--watch must not be negative")
+	}
+	// A threshold of 0 is meaningful, so whether it's set is told from whether the flag was passed.
+	checkThreshold := cmd.Flags().Changed("threshold")
+
+	r := reader{cluster: cli.ClusterFlags(cluster, environment), groups: groups, parallelism: parallelism}
+	if len(topics) > 0 {
+		r.topics = map[string]bool{}
+		for _, t := range topics {
+			r.topics[t] = true
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	report := func() (bool, error) {
+		lags, err := r.read()
+		if err != nil {
+			return false, err
+		}
+
+		var lagging []topicLag
+		if checkThreshold {
+			for _, l := range lags {
+				if l.Lag > threshold {
+					lagging = append(lagging, l)
+				}
+			}
+		}
+
+		if format != output.Table {
+			if lags == nil {
+				lags = []topicLag{}
+			}
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			return len(lagging) > 0, encoder.Encode(lags)
+		}
+
+		if watch > 0 {
+			// Clear the terminal, so that the report is redrawn in place, unless its output is piped.
+			if platform.Terminal(out) {
+				fmt.Fprint(out, "\033[H\033[2J")
+			}
+			fmt.Fprintf(out, "Every %s: %s\n\n", watch, time.Now().Format(time.TimeOnly))
+		}
+		print(out, lags, partitions)
+		if len(lagging) > 0 {
+			fmt.Fprintf(out, "\n%d consumer groups and topics have a lag over %d:\n", len(lagging), threshold)
+			for _, l := range lagging {
+				fmt.Fprintf(out, "  %s on %s: %d\n", l.Group, l.Topic, l.Lag)
+			}
+		}
+		return len(lagging) > 0, nil
+	}
+
+	if watch == 0 {
+		exceeded, err := report()
+		if err != nil {
+			return err
+		}
+		if exceeded {
+			return errLagging
+		}
+		return nil
+	}
+
+	// A watch runs until it's interrupted, so a lag over the threshold is only reported, and doesn't end it.
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(watch)
+	defer ticker.Stop()
+	for {
+		if _, err := report(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func print(w io.Writer, lags []topicLag, partitions bool) {
+	if len(lags) == 0 {
+		fmt.Fprintln(w, "No consumer groups have committed offsets.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if partitions {
+		fmt.Fprintln(tw, "Group\tTopic\tPartition\tCurrent Offset\tLog End Offset\tLag\tConsumer")
+		for _, l := range lags {
+			for _, p := range l.Partitions {
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n", l.Group, l.Topic, p.Partition, p.CurrentOffset, p.LogEndOffset, p.Lag, p.ConsumerID)
+			}
+		}
+	} else {
+		fmt.Fprintln(tw, "Group\tTopic\tPartitions\tLag")
+		for _, l := range lags {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", l.Group, l.Topic, len(l.Partitions), l.Lag)
+		}
+	}
+	_ = tw.Flush()
+}
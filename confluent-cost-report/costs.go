@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+const dateFormat = "2006-01-02"
+
+// cost is a line of "confluent billing cost list".
+type cost struct {
+	EnvironmentID string  `json:"environment_id"`
+	ResourceID    string  `json:"resource_id"`
+	ResourceName  string  `json:"resource_name"`
+	Product       string  `json:"product"`
+	Amount        float64 `json:"amount"`
+}
+
+// listCosts returns the costs from the start date until the end date, exclusive. The Billing API only returns a month at
+// a time, so longer ranges are listed a month at a time.
+func listCosts(start, end time.Time) ([]cost, error) {
+	var costs []cost
+	for from := start; from.Before(end); {
+		to := from.AddDate(0, 1, 0)
+		if to.After(end) {
+			to = end
+		}
+
+		var page []cost
+		if err := cli.JSON(&page, "billing", "cost", "list", "--start-date", from.Format(dateFormat), "--end-date", to.Format(dateFormat)); err != nil {
+			return nil, err
+		}
+		costs = append(costs, page...)
+		from = to
+	}
+	return costs, nil
+}
+
+// A group of costs, by the dimensions which the report is grouped by. Dimensions which it isn't grouped by are empty.
+type group struct {
+	Environment     string `json:"environment,omitempty"`
+	EnvironmentName string `json:"environment_name,omitempty"`
+	Resource        string `json:"resource,omitempty"`
+	ResourceName    string `json:"resource_name,omitempty"`
+	Product         string `json:"product,omitempty"`
+}
+
+type line struct {
+	group
+	Amount float64 `json:"amount"`
+	// Previous is the amount in the previous period, with --compare.
+	Previous *float64 `json:"previous,omitempty"`
+}
+
+// breakdown sums the costs by the dimensions which the report is grouped by.
+func breakdown(costs []cost, by map[string]bool, environments map[string]string) map[group]float64 {
+	amounts := map[group]float64{}
+	for _, c := range costs {
+		var g group
+		if by["environment"] {
+			g.Environment, g.EnvironmentName = c.EnvironmentID, environments[c.EnvironmentID]
+		}
+		if by["resource"] {
+			g.Resource, g.ResourceName = c.ResourceID, c.ResourceName
+		}
+		if by["product"] {
+			g.Product = c.Product
+		}
+		amounts[g] += c.Amount
+	}
+	return amounts
+}
+
+// lines joins the amounts of the period with those of the previous period, if it was compared, from the largest amount
+// to the smallest.
+func lines(amounts, previous map[group]float64) []line {
+	var ls []line
+	for g, amount := range amounts {
+		l := line{group: g, Amount: amount}
+		if previous != nil {
+			p := previous[g]
+			l.Previous = &p
+		}
+		ls = append(ls, l)
+	}
+	for g, p := range previous {
+		if _, ok := amounts[g]; !ok {
+			p := p
+			ls = append(ls, line{group: g, Previous: &p})
+		}
+	}
+
+	sort.Slice(ls, func(i, j int) bool {
+		if ls[i].Amount != ls[j].Amount {
+			return ls[i].Amount > ls[j].Amount
+		}
+		return fmt.Sprint(ls[i].group) < fmt.Sprint(ls[j].group)
+	})
+	return ls
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+var dimensions = []string{"environment", "resource", "product"}
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "report",
+		Short: "Break down Confluent Cloud costs.",
+		Long:  "Break down the costs of a Confluent Cloud organization for a date range per environment, resource, such as a Kafka cluster or connector, and product, and print them as a table, CSV, or JSON. With --compare, the costs are compared with the month before.",
+		Args:  cobra.NoArgs,
+		RunE:  report,
+		Example: `confluent cost report --compare
+confluent cost report --start 2024-01-01 --end 2024-04-01 --group-by environment,resource --output csv > costs.csv`,
+	}
+
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	cmd.Flags().String("start", thisMonth.AddDate(0, -1, 0).Format(dateFormat), "First day of the date range.")
+	cmd.Flags().String("end", thisMonth.Format(dateFormat), "Day after the last day of the date range.")
+	cmd.Flags().StringSlice("group-by", []string{"environment", "product"}, fmt.Sprintf("Dimensions to break down the costs by: %s.", strings.Join(dimensions, ", ")))
+	cmd.Flags().Bool("compare", false, "Compare the costs with those of the same date range a month before.")
+	output.AddFlag(&cmd, "Format of the report", "csv")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-cost-report")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-cost-report"))
+
+	exitcode.Execute(&cmd)
+}
+
+func report(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	startFlag, err := cmd.Flags().GetString("start")
+	cobra.CheckErr(err)
+
+	endFlag, err := cmd.Flags().GetString("end")
+	cobra.CheckErr(err)
+
+	groupBy, err := cmd.Flags().GetStringSlice("group-by")
+	cobra.CheckErr(err)
+
+	compare, err := cmd.Flags().GetBool("compare")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	start, err := time.Parse(dateFormat, startFlag)
+	if err != nil {
+		return fmt.Errorf(`invalid start date "%s", expected a date such as 2024-01-01`, startFlag)
+	}
+	end, err := time.Parse(dateFormat, endFlag)
+	if err != nil {
+		return fmt.Errorf(`invalid end date "%s", expected a date such as 2024-02-01`, endFlag)
+	}
+	if !start.Before(end) {
+		return exitcode.Invalid("--start must be before --end")
+	}
+
+	if len(groupBy) == 0 {
+		return exitcode.Invalid("--group-by must have at least one dimension")
+	}
+	by := map[string]bool{}
+	for _, d := range groupBy {
+		if !slices.Contains(dimensions, d) {
+			return fmt.Errorf(`unsupported dimension "%s", supported dimensions: %s`, d, strings.Join(dimensions, ", "))
+		}
+		by[d] = true
+	}
+
+	environments := map[string]string{}
+	if by["environment"] {
+		var list []cli.Environment
+		if err := cli.JSON(&list, "environment", "list"); err != nil {
+			return err
+		}
+		for _, e := range list {
+			environments[e.ID] = e.Name
+		}
+	}
+
+	costs, err := listCosts(start, end)
+	if err != nil {
+		return err
+	}
+	amounts := breakdown(costs, by, environments)
+
+	var previous map[group]float64
+	if compare {
+		costs, err := listCosts(start.AddDate(0, -1, 0), end.AddDate(0, -1, 0))
+		if err != nil {
+			return err
+		}
+		previous = breakdown(costs, by, environments)
+	}
+
+	ls := lines(amounts, previous)
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		if ls == nil {
+			ls = []line{}
+		}
+		return output.Print(out, format, ls, nil)
+	case "csv":
+		return writeCSV(out, by, compare, ls)
+	default:
+		print(out, by, compare, ls)
+		return nil
+	}
+}
+
+func columns(by map[string]bool, compare bool) []string {
+	var cs []string
+	if by["environment"] {
+		cs = append(cs, "Environment", "Environment Name")
+	}
+	if by["resource"] {
+		cs = append(cs, "Resource", "Resource Name")
+	}
+	if by["product"] {
+		cs = append(cs, "Product")
+	}
+	cs = append(cs, "Amount")
+	if compare {
+		cs = append(cs, "Previous", "Change", "Change (%)")
+	}
+	return cs
+}
+
+func (l line) row(by map[string]bool) []string {
+	var r []string
+	if by["environment"] {
+		r = append(r, l.Environment, l.EnvironmentName)
+	}
+	if by["resource"] {
+		r = append(r, l.Resource, l.ResourceName)
+	}
+	if by["product"] {
+		r = append(r, l.Product)
+	}
+	r = append(r, formatAmount(l.Amount))
+	if l.Previous != nil {
+		r = append(r, formatAmount(*l.Previous), formatAmount(l.Amount-*l.Previous), change(l.Amount, *l.Previous))
+	}
+	return r
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// change formats the change from the previous amount as a percentage, which is empty if there was no previous amount.
+func change(amount, previous float64) string {
+	if previous == 0 {
+		return ""
+	}
+	return strconv.FormatFloat((amount-previous)/previous*100, 'f', 1, 64)
+}
+
+func writeCSV(w io.Writer, by map[string]bool, compare bool, ls []line) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns(by, compare)); err != nil {
+		return err
+	}
+	for _, l := range ls {
+		if err := cw.Write(l.row(by)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func print(w io.Writer, by map[string]bool, compare bool, ls []line) {
+	if len(ls) == 0 {
+		fmt.Fprintln(w, "No costs in the date range.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	write := func(values []string) {
+		for i, v := range values {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, v)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	write(columns(by, compare))
+	total := line{}
+	if compare {
+		total.Previous = new(float64)
+	}
+	for _, l := range ls {
+		write(l.row(by))
+		total.Amount += l.Amount
+		if compare {
+			*total.Previous += *l.Previous
+		}
+	}
+
+	// The total's row has the label in the first column, and is blank in the others of the dimensions.
+	t := total.row(by)
+	t[0] = "Total"
+	for i := 1; i < len(columns(by, false))-1; i++ {
+		t[i] = ""
+	}
+	write(t)
+	_ = tw.Flush()
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+)
+
+const connectorClass = "DatagenSource"
+
+// pollInterval is how often a connector's status and its schema are checked while waiting for them.
+const pollInterval = 10 * time.Second
+
+// quickstarts are the templates of the fully-managed Datagen source connector.
+var quickstarts = []string{
+	"CAMPAIGN_FINANCE",
+	"CLICKSTREAM",
+	"CLICKSTREAM_CODES",
+	"CLICKSTREAM_USERS",
+	"CREDIT_CARDS",
+	"DEVICE_INFORMATION",
+	"FLEET_MGMT_DESCRIPTION",
+	"FLEET_MGMT_LOCATION",
+	"FLEET_MGMT_SENSORS",
+	"GAMING_GAMES",
+	"GAMING_PLAYER_ACTIVITY",
+	"GAMING_PLAYERS",
+	"INSURANCE_CUSTOMER_ACTIVITY",
+	"INSURANCE_CUSTOMERS",
+	"INSURANCE_OFFERS",
+	"INVENTORY",
+	"ORDERS",
+	"PAGEVIEWS",
+	"PAYROLL_BONUS",
+	"PAYROLL_EMPLOYEE",
+	"PAYROLL_EMPLOYEE_LOCATION",
+	"PIZZA_ORDERS",
+	"PIZZA_ORDERS_CANCELLED",
+	"PIZZA_ORDERS_COMPLETED",
+	"PRODUCT",
+	"PURCHASES",
+	"RATINGS",
+	"SHOE_CLICKSTREAM",
+	"SHOE_CUSTOMERS",
+	"SHOE_ORDERS",
+	"SHOES",
+	"SIEM_LOGS",
+	"STOCK_TRADES",
+	"STORES",
+	"SYSLOG_LOGS",
+	"TRANSACTIONS",
+	"USERS",
+	"USERS_ARRAY",
+}
+
+// formats are the output formats of the connector, of which all but JSON register a schema for the topic's values.
+var formats = []string{"AVRO", "JSON_SR", "PROTOBUF", "JSON"}
+
+// A datagen is a Datagen source connector which writes a quickstart's records to a topic. The connectors which the
+// plugin manages are named after their topic, with a prefix, so that they can be found again to be removed.
+type datagen struct {
+	Name       string `json:"name"`
+	ID         string `json:"id,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Quickstart string `json:"quickstart"`
+	Topic      string `json:"topic"`
+	Format     string `json:"format"`
+	Schema     string `json:"schema"`
+}
+
+// hasSchema returns whether the connector registers a schema for the values of its topic.
+func (d datagen) hasSchema() bool {
+	return d.Format != "JSON"
+}
+
+// subject is the subject of the topic's values, which the connector registers its schema under.
+func (d datagen) subject() string {
+	return d.Topic + "-value"
+}
+
+// connectorDescription is the output of "confluent connect cluster describe".
+type connectorDescription struct {
+	Connector struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Trace  string `json:"trace"`
+	} `json:"connector"`
+	Tasks []struct {
+		ID    int    `json:"task_id"`
+		State string `json:"state"`
+	} `json:"tasks"`
+	Configs []struct {
+		Config string `json:"config"`
+		Value  string `json:"value"`
+	} `json:"configs"`
+}
+
+func (d connectorDescription) config(name string) string {
+	for _, c := range d.Configs {
+		if c.Config == name {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// manager manages the Datagen connectors with a prefix in a Kafka cluster.
+type manager struct {
+	prefix      string
+	scope       []string
+	environment []string
+}
+
+func (m manager) name(topic string) string {
+	return m.prefix + "-" + topic
+}
+
+// parseTarget parses a quickstart and the topic to write its records to, which defaults to the quickstart's name in
+// lower case.
+func parseTarget(arg string) (string, string, error) {
+	quickstart, topic, _ := strings.Cut(arg, ":")
+	quickstart = strings.ToUpper(quickstart)
+	if !slices.Contains(quickstarts, quickstart) {
+		return "", "", fmt.Errorf(`unsupported quickstart "%s", supported quickstarts: %s`, quickstart, strings.Join(quickstarts, ", "))
+	}
+	if topic == "" {
+		topic = strings.ToLower(quickstart)
+	}
+	return quickstart, topic, nil
+}
+
+// list returns the managed connectors, sorted by name.
+func (m manager) list() ([]datagen, error) {
+	var listed []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := cli.JSON(&listed, append([]string{"connect", "cluster", "list"}, m.scope...)...); err != nil {
+		return nil, err
+	}
+
+	var datagens []datagen
+	for _, l := range listed {
+		if !strings.HasPrefix(l.Name, m.prefix+"-") {
+			continue
+		}
+		var d connectorDescription
+		if err := cli.JSON(&d, append([]string{"connect", "cluster", "describe", l.ID}, m.scope...)...); err != nil {
+			return nil, err
+		}
+		if d.config("connector.class") != connectorClass {
+			continue
+		}
+		datagens = append(datagens, datagen{
+			Name:       l.Name,
+			ID:         l.ID,
+			Status:     l.Status,
+			Quickstart: d.config("quickstart"),
+			Topic:      d.config("kafka.topic"),
+			Format:     d.config("output.data.format"),
+		})
+	}
+	slices.SortFunc(datagens, func(a, b datagen) int { return strings.Compare(a.Name, b.Name) })
+	return datagens, nil
+}
+
+// create creates the connector, and returns its ID.
+func (m manager) create(d datagen, apiKey, apiSecret string, interval time.Duration, tasks int) (string, error) {
+	configs := map[string]string{
+		"name":               d.Name,
+		"connector.class":    connectorClass,
+		"kafka.auth.mode":    "KAFKA_API_KEY",
+		"kafka.api.key":      apiKey,
+		"kafka.api.secret":   apiSecret,
+		"kafka.topic":        d.Topic,
+		"quickstart":         d.Quickstart,
+		"output.data.format": d.Format,
+		"max.interval":       fmt.Sprint(interval.Milliseconds()),
+		"tasks.max":          fmt.Sprint(tasks),
+	}
+	b, err := json.Marshal(map[string]any{"name": d.Name, "config": configs})
+	if err != nil {
+		return "", err
+	}
+	// The file is only readable by the current user, since it contains the API secret.
+	path, err := platform.WriteTemp("datagen-manager-*.json", b)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&created, append([]string{"connect", "cluster", "create", "--config-file", path}, m.scope...)...); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// wait waits until the connector and all of its tasks are running, and fails as soon as any of them fails.
+func (m manager) wait(d datagen, deadline time.Time) error {
+	for {
+		var s connectorDescription
+		if err := cli.JSON(&s, append([]string{"connect", "cluster", "describe", d.ID}, m.scope...)...); err != nil {
+			return err
+		}
+
+		switch s.Connector.Status {
+		case "FAILED":
+			if trace, _, _ := strings.Cut(strings.TrimSpace(s.Connector.Trace), "\n"); trace != "" {
+				return fmt.Errorf(`connector "%s" (%s) failed: %s`, d.Name, d.ID, trace)
+			}
+			return fmt.Errorf(`connector "%s" (%s) failed`, d.Name, d.ID)
+		case "RUNNING":
+			running := true
+			for _, t := range s.Tasks {
+				if t.State == "FAILED" {
+					return fmt.Errorf(`task %d of connector "%s" (%s) failed`, t.ID, d.Name, d.ID)
+				}
+				if t.State != "RUNNING" {
+					running = false
+				}
+			}
+			if running {
+				return nil
+			}
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			return fmt.Errorf(`timed out waiting for connector "%s" (%s) to run, its status is %s`, d.Name, d.ID, s.Connector.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// subjects returns the subjects of the environment's Schema Registry.
+func (m manager) subjects() (map[string]bool, error) {
+	var listed []struct {
+		Subject string `json:"subject"`
+	}
+	if err := cli.JSON(&listed, append([]string{"schema-registry", "subject", "list"}, m.environment...)...); err != nil {
+		return nil, err
+	}
+	subjects := map[string]bool{}
+	for _, s := range listed {
+		subjects[s.Subject] = true
+	}
+	return subjects, nil
+}
+
+// waitForSchema waits until the connector's schema is registered, which happens when it produces its first record.
+func (m manager) waitForSchema(d datagen, deadline time.Time) error {
+	for {
+		subjects, err := m.subjects()
+		if err != nil {
+			return err
+		}
+		if subjects[d.subject()] {
+			return nil
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			return fmt.Errorf(`connector "%s" (%s) is running, but no schema was registered under "%s"`, d.Name, d.ID, d.subject())
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// schemaStatus sets whether the schema of each connector is registered.
+func (m manager) schemaStatus(datagens []datagen) error {
+	subjects, err := m.subjects()
+	if err != nil {
+		return err
+	}
+	for i, d := range datagens {
+		switch {
+		case !d.hasSchema():
+			datagens[i].Schema = "none"
+		case subjects[d.subject()]:
+			datagens[i].Schema = "registered"
+		default:
+			datagens[i].Schema = "missing"
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+func newCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <quickstart>[:<topic>]...",
+		Short: "Create Datagen connectors for quickstarts.",
+		Long:  "Create a Datagen source connector for each quickstart, writing to the topic after it, or to a topic named after the quickstart, and create the topics which don't exist. The plugin then waits for the connectors to run and checks that they registered the schemas of their topics.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  create,
+		Example: `confluent datagen manager create orders users pageviews --api-key ABCDEFGHIJKLMNOP
+confluent datagen manager create stock_trades:trades --format JSON --interval 100ms --no-wait`,
+	}
+
+	cmd.Flags().String("api-key", "", "Kafka API key which the connectors produce with.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("format", "AVRO", fmt.Sprintf("Output format of the records: %s.", strings.Join(formats, ", ")))
+	cmd.Flags().Duration("interval", time.Second, "Maximum interval between the records of each task.")
+	cmd.Flags().Int("tasks", 1, "Number of tasks of each connector.")
+	cmd.Flags().Int("partitions", 6, "Number of partitions of the topics which are created.")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for the connectors to run and register their schemas.")
+	cmd.Flags().Bool("no-wait", false, "Don't wait for the connectors to run, or check their schemas.")
+	output.AddFlag(cmd, "Format of the connectors which were created")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("api-key"))
+
+	return cmd
+}
+
+func create(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	format, err := cmd.Flags().GetString("format")
+	cobra.CheckErr(err)
+
+	interval, err := cmd.Flags().GetDuration("interval")
+	cobra.CheckErr(err)
+
+	tasks, err := cmd.Flags().GetInt("tasks")
+	cobra.CheckErr(err)
+
+	partitions, err := cmd.Flags().GetInt("partitions")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	noWait, err := cmd.Flags().GetBool("no-wait")
+	cobra.CheckErr(err)
+
+	outputFormat, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	format = strings.ToUpper(format)
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf(`unsupported format "%s", supported formats: %s`, format, strings.Join(formats, ", "))
+	}
+	if interval < time.Millisecond {
+		return exitcode.Invalid("--interval must be at least 1ms")
+	}
+	if tasks < 1 {
+		return exitcode.Invalid("--tasks must be at least 1")
+	}
+	if partitions < 1 {
+		return exitcode.Invalid("--partitions must be at least 1")
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if apiSecret == "" {
+		return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required")
+	}
+
+	m := newManager(cmd)
+
+	var datagens []datagen
+	topics := map[string]bool{}
+	for _, arg := range args {
+		quickstart, topic, err := parseTarget(arg)
+		if err != nil {
+			return err
+		}
+		if topics[topic] {
+			return fmt.Errorf(`topic "%s" is the target of more than one quickstart`, topic)
+		}
+		topics[topic] = true
+		datagens = append(datagens, datagen{Name: m.name(topic), Quickstart: quickstart, Topic: topic, Format: format})
+	}
+
+	var listed []struct {
+		Name string `json:"name"`
+	}
+	if err := cli.JSON(&listed, append([]string{"connect", "cluster", "list"}, m.scope...)...); err != nil {
+		return err
+	}
+	for _, l := range listed {
+		for _, d := range datagens {
+			if l.Name == d.Name {
+				return fmt.Errorf(`connector "%s" already exists, delete it with "confluent datagen manager delete %s" first`, d.Name, d.Topic)
+			}
+		}
+	}
+
+	var existing []cli.Topic
+	if err := cli.JSON(&existing, append([]string{"kafka", "topic", "list"}, m.scope...)...); err != nil {
+		return err
+	}
+	for _, t := range existing {
+		delete(topics, t.Name)
+	}
+
+	// The topics and connectors which were created are reported if any of them fail, or the command is interrupted.
+	var ledger interrupt.Ledger
+	out := output.Progress(cmd)
+	for _, d := range datagens {
+		if !topics[d.Topic] {
+			continue
+		}
+		if _, err := cli.Run(append([]string{"kafka", "topic", "create", d.Topic, "--partitions", fmt.Sprint(partitions)}, m.scope...)...); err != nil {
+			return ledger.Report(cmd.ErrOrStderr(), err)
+		}
+		fmt.Fprintf(out, "Created topic \"%s\".\n", d.Topic)
+		ledger.Add("topic", d.Topic, "", append([]string{"kafka", "topic", "delete", d.Topic, "--force"}, m.scope...)...)
+	}
+
+	// Every connector is created before waiting for any of them, since each can take minutes to provision.
+	var errs []error
+	for i, d := range datagens {
+		if interrupt.Err() != nil {
+			break
+		}
+		id, err := m.create(d, apiKey, apiSecret, interval, tasks)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`failed to create connector "%s": %w`, d.Name, err))
+			continue
+		}
+		datagens[i].ID = id
+		fmt.Fprintf(out, "Created connector \"%s\" (%s) for quickstart %s.\n", d.Name, id, d.Quickstart)
+		ledger.Add("connector", d.Name, id, append([]string{"connect", "cluster", "delete", id, "--force"}, m.scope...)...)
+	}
+
+	if noWait {
+		return errors.Join(ledger.Report(cmd.ErrOrStderr(), errors.Join(errs...)), printCreated(cmd, outputFormat, datagens))
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, d := range datagens {
+		if interrupt.Err() != nil {
+			break
+		}
+		if d.ID == "" {
+			continue
+		}
+		s := progress.Spin(out, fmt.Sprintf(`Waiting for connector "%s" (%s) to run`, d.Name, d.ID))
+		err := m.wait(d, deadline)
+		s.Stop()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !d.hasSchema() {
+			fmt.Fprintf(out, "Connector \"%s\" (%s) is running.\n", d.Name, d.ID)
+			continue
+		}
+		s = progress.Spin(out, fmt.Sprintf(`Waiting for connector "%s" (%s) to register schema "%s"`, d.Name, d.ID, d.subject()))
+		err = m.waitForSchema(d, deadline)
+		s.Stop()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(out, "Connector \"%s\" (%s) is running and registered schema \"%s\".\n", d.Name, d.ID, d.subject())
+	}
+
+	return errors.Join(ledger.Report(cmd.ErrOrStderr(), errors.Join(errs...)), printCreated(cmd, outputFormat, datagens))
+}
+
+// printCreated prints the connectors which were created with --output json or yaml, as list prints them.
+func printCreated(cmd *cobra.Command, format string, datagens []datagen) error {
+	if format == output.Table {
+		return nil
+	}
+	created := []datagen{}
+	for _, d := range datagens {
+		if d.ID != "" {
+			created = append(created, d)
+		}
+	}
+	return output.Print(cmd.OutOrStdout(), format, created, nil)
+}
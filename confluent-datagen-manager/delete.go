@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [topic]...",
+		Short: "Delete the managed Datagen connectors.",
+		Long:  "Delete the Datagen connectors with the prefix, or only those writing to the topics, after prompting for confirmation, and optionally their topics and the subjects of their schemas.",
+		Args:  cobra.ArbitraryArgs,
+		RunE:  deleteConnectors,
+		Example: `confluent datagen manager delete --dry-run
+confluent datagen manager delete orders --delete-topics --delete-schemas --force`,
+	}
+
+	cmd.Flags().Bool("delete-topics", false, "Also delete the topics of the connectors.")
+	cmd.Flags().Bool("delete-schemas", false, "Also delete the subjects of the schemas of the connectors' topics.")
+	dryrun.AddFlag(cmd, "Print what would be deleted without deleting it.")
+	cmd.Flags().Bool("force", false, "Delete without prompting for confirmation.")
+	output.AddFlag(cmd, "Format of what was deleted")
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	return cmd
+}
+
+func deleteConnectors(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	deleteTopics, err := cmd.Flags().GetBool("delete-topics")
+	cobra.CheckErr(err)
+
+	deleteSchemas, err := cmd.Flags().GetBool("delete-schemas")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	m := newManager(cmd)
+
+	listed, err := m.list()
+	if err != nil {
+		return err
+	}
+
+	var datagens []datagen
+	for _, d := range listed {
+		if len(args) == 0 || slices.Contains(args, d.Topic) {
+			datagens = append(datagens, d)
+		}
+	}
+	for _, topic := range args {
+		if !slices.ContainsFunc(datagens, func(d datagen) bool { return d.Topic == topic }) {
+			return fmt.Errorf(`no Datagen connector named %s-<topic> writes to topic "%s"`, m.prefix, topic)
+		}
+	}
+
+	out := output.Progress(cmd)
+	if len(datagens) == 0 {
+		fmt.Fprintf(out, "No Datagen connectors named %s-<topic> found.\n", m.prefix)
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	var subjects map[string]bool
+	if deleteSchemas {
+		if subjects, err = m.subjects(); err != nil {
+			return err
+		}
+	}
+
+	// The deletions are listed in the order they're made, so that the connectors stop producing before their topics
+	// are deleted.
+	var deletions [][]string
+	var descriptions []string
+	var plan dryrun.Plan
+	for _, d := range datagens {
+		deletions = append(deletions, append([]string{"connect", "cluster", "delete", d.ID, "--force"}, m.scope...))
+		descriptions = append(descriptions, fmt.Sprintf(`connector "%s" (%s)`, d.Name, d.ID))
+		plan.AddDetail("delete", "connector", d.Name, "("+d.ID+")")
+	}
+	for _, d := range datagens {
+		if deleteTopics {
+			deletions = append(deletions, append([]string{"kafka", "topic", "delete", d.Topic, "--force"}, m.scope...))
+			descriptions = append(descriptions, fmt.Sprintf(`topic "%s"`, d.Topic))
+			plan.Add("delete", "topic", d.Topic)
+		}
+		if deleteSchemas && subjects[d.subject()] {
+			deletions = append(deletions, append([]string{"schema-registry", "schema", "delete", "--subject", d.subject(), "--version", "all", "--force"}, m.environment...))
+			descriptions = append(descriptions, fmt.Sprintf(`subject "%s"`, d.subject()))
+			plan.Add("delete", "subject", d.subject())
+		}
+	}
+
+	if dryRun {
+		return dryrun.Print(cmd, plan)
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to delete %s?", strings.Join(descriptions, ", ")))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not deleting anything.")
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+	}
+
+	var errs []error
+	var deleted dryrun.Plan
+	for i, args := range deletions {
+		if _, err := cli.Run(args...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", descriptions[i], err))
+			continue
+		}
+		fmt.Fprintf(out, "Deleted %s.\n", descriptions[i])
+		deleted.Actions = append(deleted.Actions, plan.Actions[i])
+	}
+	return errors.Join(append(errs, dryrun.PrintApplied(cmd, deleted))...)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the managed Datagen connectors.",
+		Long:  "List the Datagen connectors with the prefix, with their status, and whether the schemas of their topics are registered.",
+		Args:  cobra.NoArgs,
+		RunE:  list,
+	}
+
+	output.AddFlag(cmd, "Format of the list")
+
+	return cmd
+}
+
+func list(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	m := newManager(cmd)
+
+	datagens, err := m.list()
+	if err != nil {
+		return err
+	}
+	if len(datagens) > 0 {
+		if err := m.schemaStatus(datagens); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if datagens == nil {
+			datagens = []datagen{}
+		}
+		return output.Print(out, format, datagens, nil)
+	}
+
+	if len(datagens) == 0 {
+		fmt.Fprintf(out, "No Datagen connectors named %s-<topic> found.\n", m.prefix)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Name\tID\tStatus\tQuickstart\tTopic\tFormat\tSchema")
+	for _, d := range datagens {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", d.Name, d.ID, d.Status, d.Quickstart, d.Topic, d.Format, d.Schema)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	_ "embed"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "manager",
+		Short: "Manage Datagen source connectors in bulk.",
+		Long:  "Create Datagen source connectors for a list of quickstarts and topics with \"confluent datagen manager create\", check that they're running and registered their schemas with \"confluent datagen manager list\", and remove them with \"confluent datagen manager delete\", such as to set up and tear down workshops.",
+		Args:  cobra.NoArgs,
+		Example: `confluent datagen manager create orders users:workshop-users --api-key ABCDEFGHIJKLMNOP
+confluent datagen manager delete --delete-topics --force`,
+	}
+
+	cmd.PersistentFlags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.PersistentFlags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.PersistentFlags().String("prefix", "datagen", "Prefix of the names of the managed connectors, which are named <prefix>-<topic>.")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.AddCommand(newCreateCommand())
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newDeleteCommand())
+
+	completion.Add(&cmd, "confluent-datagen-manager")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-datagen-manager"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+func newManager(cmd *cobra.Command) manager {
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	return manager{
+		prefix:      prefix,
+		scope:       cli.ClusterFlags(cluster, environment),
+		environment: cli.ClusterFlags("", environment),
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// stoppedStatuses are the statuses of mirror topics which were already promoted or failed over, and are left alone.
+var stoppedStatuses = map[string]bool{
+	"PENDING_STOPPED": true,
+	"STOPPED":         true,
+}
+
+// A mirror is a mirror topic of the cluster link, and what became of it.
+type mirror struct {
+	Topic       string `json:"topic"`
+	SourceTopic string `json:"source_topic"`
+	Status      string `json:"status"`
+	// Lag is the lag of the most lagging partition when the failover started, which, when the source cluster is lost,
+	// is how many messages of a partition may not have been replicated.
+	Lag      int64  `json:"lag"`
+	Stopped  bool   `json:"stopped"`
+	Error    string `json:"error,omitempty"`
+	skipped  bool
+	finished time.Time
+}
+
+func (m mirror) result(action string) string {
+	switch {
+	case m.skipped:
+		return fmt.Sprintf("Skipped, already %s", m.Status)
+	case m.Error != "":
+		return "Failed: " + m.Error
+	case m.Stopped:
+		return fmt.Sprintf("%s at %s", action, m.finished.Format(time.TimeOnly))
+	default:
+		return "Not attempted"
+	}
+}
+
+// A failover promotes the mirror topics of a cluster link on the destination cluster, so that clients can use them.
+type failover struct {
+	link  string
+	scope []string
+	// promote syncs the mirror topics with the source cluster before stopping them, which requires the source cluster to
+	// be reachable. Otherwise they're failed over, and stopped at once.
+	promote bool
+
+	sourceCluster string
+	offsetSync    bool
+	mirrors       []*mirror
+}
+
+// command is the CLI command which stops the mirror topics.
+func (f *failover) command() string {
+	if f.promote {
+		return "promote"
+	}
+	return "failover"
+}
+
+func (f *failover) verb() string {
+	if f.promote {
+		return "promote"
+	}
+	return "fail over"
+}
+
+// action is the past tense of the verb, for the report.
+func (f *failover) action() string {
+	if f.promote {
+		return "Promoted"
+	}
+	return "Failed over"
+}
+
+// read reads the cluster link and its mirror topics, or those of the topics, in the destination cluster.
+func (f *failover) read(topics []string) error {
+	var described struct {
+		SourceCluster string `json:"source_cluster"`
+	}
+	if err := cli.JSON(&described, append([]string{"kafka", "link", "describe", f.link}, f.scope...)...); err != nil {
+		return err
+	}
+	f.sourceCluster = described.SourceCluster
+
+	var configs []struct {
+		Name  string `json:"config_name"`
+		Value string `json:"config_value"`
+	}
+	if err := cli.JSON(&configs, append([]string{"kafka", "link", "configuration", "list", f.link}, f.scope...)...); err != nil {
+		return err
+	}
+	for _, c := range configs {
+		if c.Name == "consumer.offset.sync.enable" {
+			f.offsetSync = c.Value == "true"
+		}
+	}
+
+	var listed []struct {
+		MirrorTopicName          string `json:"mirror_topic_name"`
+		SourceTopicName          string `json:"source_topic_name"`
+		MirrorStatus             string `json:"mirror_status"`
+		MaxPerPartitionMirrorLag int64  `json:"max_per_partition_mirror_lag"`
+	}
+	if err := cli.JSON(&listed, append([]string{"kafka", "mirror", "list", "--link", f.link}, f.scope...)...); err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, t := range topics {
+		wanted[t] = true
+	}
+	for _, l := range listed {
+		if len(topics) > 0 && !wanted[l.MirrorTopicName] {
+			continue
+		}
+		delete(wanted, l.MirrorTopicName)
+		f.mirrors = append(f.mirrors, &mirror{
+			Topic:       l.MirrorTopicName,
+			SourceTopic: l.SourceTopicName,
+			Status:      l.MirrorStatus,
+			Lag:         l.MaxPerPartitionMirrorLag,
+			skipped:     stoppedStatuses[l.MirrorStatus],
+		})
+	}
+	if len(wanted) > 0 {
+		var missing []string
+		for t := range wanted {
+			missing = append(missing, t)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf(`cluster link "%s" has no mirror topics %s`, f.link, strings.Join(missing, ", "))
+	}
+
+	sort.Slice(f.mirrors, func(i, j int) bool { return f.mirrors[i].Topic < f.mirrors[j].Topic })
+	return nil
+}
+
+// stop promotes or fails over a mirror topic. The CLI reports errors per partition, so the first is the mirror's.
+func (f *failover) stop(m *mirror) {
+	var results []struct {
+		ErrorMessage string `json:"error_message"`
+	}
+	args := append([]string{"kafka", "mirror", f.command(), m.Topic, "--link", f.link}, f.scope...)
+	if err := cli.JSON(&results, args...); err != nil {
+		m.Error = err.Error()
+		return
+	}
+	for _, r := range results {
+		if r.ErrorMessage != "" {
+			m.Error = r.ErrorMessage
+			return
+		}
+	}
+	m.Stopped = true
+	m.finished = time.Now()
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "failover",
+		Short: "Fail over the mirror topics of a cluster link.",
+		Long:  "Fail over or promote the mirror topics of a cluster link on the disaster recovery cluster, so that clients can write to them, carry the committed offsets of consumer groups over from the source cluster where it's still reachable, and write a runbook of what was done.",
+		Args:  cobra.NoArgs,
+		RunE:  failoverLink,
+		Example: `confluent dr failover --link dr-link --cluster lkc-123456 --dry-run
+confluent dr failover --link dr-link --cluster lkc-123456 --group orders-service --api-key ABCDEFGHIJKLMNOP --report failover.md
+confluent dr failover --link dr-link --cluster lkc-123456 --topic orders --promote --force`,
+	}
+
+	cmd.Flags().String("link", "", "Name of the cluster link to fail over.")
+	cmd.Flags().String("cluster", "", "ID of the disaster recovery cluster, which is the destination of the link. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID of the disaster recovery cluster. Defaults to the current environment.")
+	cmd.Flags().String("source-environment", "", "Environment ID of the source cluster. Defaults to --environment.")
+	cmd.Flags().StringSlice("topic", nil, "Mirror topics to fail over. Defaults to every mirror topic of the link.")
+	cmd.Flags().Bool("promote", false, "Sync the mirror topics with the source cluster before stopping them, for a planned failover while it's reachable.")
+	cmd.Flags().StringSlice("group", nil, "Consumer groups to carry the committed offsets of over from the source cluster.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the disaster recovery cluster, to commit the offsets of --group with.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the disaster recovery cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the disaster recovery cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().String("report", "", "Also write the runbook of the failover to this Markdown file.")
+	output.AddFlag(&cmd, "Format of the runbook, which is Markdown as a table")
+	dryrun.AddFlag(&cmd, "List what would be failed over, without doing it.")
+	cmd.Flags().Bool("force", false, "Fail over without prompting for confirmation.")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("link"))
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	completion.Add(&cmd, "confluent-dr-failover")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-dr-failover"))
+
+	exitcode.Execute(&cmd)
+}
+
+func failoverLink(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	link, err := cmd.Flags().GetString("link")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	sourceEnvironment, err := cmd.Flags().GetString("source-environment")
+	cobra.CheckErr(err)
+
+	topics, err := cmd.Flags().GetStringSlice("topic")
+	cobra.CheckErr(err)
+
+	promote, err := cmd.Flags().GetBool("promote")
+	cobra.CheckErr(err)
+
+	groups, err := cmd.Flags().GetStringSlice("group")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	reportPath, err := cmd.Flags().GetString("report")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if len(groups) > 0 {
+		if apiKey == "" {
+			return exitcode.Invalid("--api-key is required with --group")
+		}
+		if apiSecret == "" {
+			apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+		}
+		if apiSecret == "" {
+			return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required with --group")
+		}
+	}
+	if sourceEnvironment == "" {
+		sourceEnvironment = environment
+	}
+
+	f := &failover{link: link, scope: cli.ClusterFlags(cluster, environment), promote: promote}
+	if err := f.read(topics); err != nil {
+		return err
+	}
+
+	var pending []*mirror
+	for _, m := range f.mirrors {
+		if !m.skipped {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Cluster link \"%s\" has no mirror topics to fail over.\n", link)
+		return nil
+	}
+
+	if dryRun {
+		printNotes(cmd.ErrOrStderr(), f)
+		return dryrun.Print(cmd, planFailover(f, pending, groups))
+	}
+	printPlan(output.Progress(cmd), f, pending, groups)
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to %s %d mirror topics? They can't be made mirror topics again.", f.verb(), len(pending)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not failing over.")
+			return nil
+		}
+	}
+
+	r := &runbook{f: f, cluster: cluster, environment: environment, started: time.Now(), progress: cmd.ErrOrStderr()}
+	r.logf("Started to %s %d mirror topics of cluster link %s.", f.verb(), len(pending), link)
+
+	// The topics are stopped one at a time, so that a failure is reported against its topic, and doesn't stop the others.
+	failed := 0
+	for _, m := range pending {
+		f.stop(m)
+		if m.Error != "" {
+			failed++
+			r.logf("Failed to %s mirror topic %s: %s", f.verb(), m.Topic, m.Error)
+			continue
+		}
+		r.logf("%s mirror topic %s, which had a lag of %d.", f.action(), m.Topic, m.Lag)
+	}
+
+	// Offsets are carried over once the topics are stopped, since the link would otherwise keep syncing over them.
+	stopped := map[string]string{}
+	for _, m := range pending {
+		if m.Stopped {
+			stopped[m.SourceTopic] = m.Topic
+		}
+	}
+	if len(groups) > 0 && len(stopped) > 0 {
+		if bootstrap == "" {
+			var described cli.KafkaCluster
+			if err := cli.JSON(&described, append([]string{"kafka", "cluster", "describe"}, describeArgs(cluster, environment)...)...); err != nil {
+				return err
+			}
+			bootstrap = described.Endpoint
+		}
+		client := kafka.NewClient("confluent-dr-failover", bootstrap, apiKey, apiSecret)
+		defer client.Close()
+
+		sourceScope := cli.ClusterFlags(f.sourceCluster, sourceEnvironment)
+		for _, group := range groups {
+			g := &groupRewrite{Group: group}
+			r.groups = append(r.groups, g)
+			rewriteGroup(client, g, stopped, sourceScope, f.offsetSync)
+			if g.Error != "" {
+				r.logf("Failed to rewrite the offsets of consumer group %s: %s", group, g.Error)
+			} else {
+				r.logf("Consumer group %s: %s.", group, g.Result)
+			}
+		}
+	}
+
+	r.finished = time.Now()
+	r.logf("Finished.")
+
+	out := cmd.OutOrStdout()
+	if format == output.Table {
+		fmt.Fprintln(out)
+		r.write(out)
+	} else if err := output.Print(out, format, r.result(), nil); err != nil {
+		return err
+	}
+	if reportPath != "" {
+		var b strings.Builder
+		r.write(&b)
+		if err := os.WriteFile(reportPath, []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write the runbook: %w", err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d mirror topics", f.verb(), failed, len(pending))
+	}
+	for _, g := range r.groups {
+		if g.Error != "" {
+			return fmt.Errorf("failed to rewrite the offsets of some consumer groups")
+		}
+	}
+	return nil
+}
+
+// rewriteGroup carries the offsets of a group over from the source cluster, and records how it went. When the
+// source cluster is unreachable, the offsets which the cluster link last synced are the best there are, if it syncs
+// them.
+func rewriteGroup(client *kafka.Client, g *groupRewrite, topics map[string]string, sourceScope []string, offsetSync bool) {
+	offsets, err := sourceOffsets(g.Group, topics, sourceScope)
+	if err != nil {
+		if offsetSync {
+			g.Result = "Kept the offsets last synced by the cluster link, since the source cluster is unreachable"
+			return
+		}
+		g.Error = fmt.Sprintf("the source cluster is unreachable, and the cluster link doesn't sync offsets: %s", err)
+		return
+	}
+	if len(offsets) == 0 {
+		g.Result = "No committed offsets on the failed over topics"
+		return
+	}
+
+	if err := rewrite(client, g, offsets); err != nil {
+		g.Error = err.Error()
+		return
+	}
+	g.Result = "Rewrote the offsets from the source cluster"
+	if g.Clamped > 0 {
+		g.Result += fmt.Sprintf(", moving %d back to the end of their partitions", g.Clamped)
+	}
+}
+
+func describeArgs(cluster, environment string) []string {
+	var args []string
+	if cluster != "" {
+		args = append(args, cluster)
+	}
+	if environment != "" {
+		args = append(args, "--environment", environment)
+	}
+	return args
+}
+
+func printPlan(w io.Writer, f *failover, pending []*mirror, groups []string) {
+	fmt.Fprintf(w, "Would %s %d mirror topics of cluster link \"%s\" from source cluster %s:\n", f.verb(), len(pending), f.link, f.sourceCluster)
+	for _, m := range pending {
+		fmt.Fprintf(w, "  %s, which is %s with a lag of %d\n", m.Topic, m.Status, m.Lag)
+	}
+	if len(groups) > 0 {
+		fmt.Fprintf(w, "Would rewrite the offsets of consumer groups %s.\n", strings.Join(groups, ", "))
+	}
+	printNotes(w, f)
+}
+
+// planFailover is what printPlan prints, for --dry-run.
+func planFailover(f *failover, pending []*mirror, groups []string) dryrun.Plan {
+	var plan dryrun.Plan
+	for _, m := range pending {
+		plan.AddDetail(f.verb(), "mirror topic", m.Topic, fmt.Sprintf("(%s with a lag of %d)", m.Status, m.Lag))
+	}
+	for _, group := range groups {
+		plan.AddDetail("rewrite", "offsets of consumer group", group, "from source cluster "+f.sourceCluster)
+	}
+	return plan
+}
+
+// printNotes prints the mirror topics which are skipped, and what failing over without --promote may lose.
+func printNotes(w io.Writer, f *failover) {
+	for _, m := range f.mirrors {
+		if m.skipped {
+			fmt.Fprintf(w, "Skipping %s, which is already %s.\n", m.Topic, m.Status)
+		}
+	}
+	if !f.promote {
+		fmt.Fprintln(w, "Failing over doesn't wait for the mirror topics to catch up, so messages within their lag may be lost. Pass --promote if the source cluster is reachable.")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// A groupRewrite is how the offsets of a consumer group were carried over to the destination cluster.
+type groupRewrite struct {
+	Group      string `json:"group"`
+	Partitions int    `json:"partitions"`
+	// Clamped is how many partitions' offsets were past the end of the destination's, since their last messages weren't
+	// replicated, and were moved back to the end.
+	Clamped int    `json:"clamped"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
+
+// sourceOffsets reads the committed offsets of the group in the source cluster on the topics, by topic and partition.
+// Mirror topics have the same offsets as their source topics, so they carry over as they are.
+func sourceOffsets(group string, topics map[string]string, scope []string) (map[string]map[int32]int64, error) {
+	var lags []struct {
+		Topic         string `json:"topic"`
+		Partition     int32  `json:"partition"`
+		CurrentOffset int64  `json:"current_offset"`
+	}
+	if err := cli.JSON(&lags, append([]string{"kafka", "consumer", "group", "lag", "list", group}, scope...)...); err != nil {
+		return nil, err
+	}
+
+	offsets := map[string]map[int32]int64{}
+	for _, l := range lags {
+		topic, ok := topics[l.Topic]
+		if !ok || l.CurrentOffset < 0 {
+			continue
+		}
+		if offsets[topic] == nil {
+			offsets[topic] = map[int32]int64{}
+		}
+		offsets[topic][l.Partition] = l.CurrentOffset
+	}
+	return offsets, nil
+}
+
+// rewrite commits the offsets of the group in the destination cluster, kept within the promoted topics' logs. The
+// group must be empty there, since its consumers would otherwise overwrite them.
+func rewrite(client *kafka.Client, r *groupRewrite, offsets map[string]map[int32]int64) error {
+	if err := client.FindCoordinator(r.Group); err != nil {
+		return err
+	}
+	state, members, err := client.DescribeGroup(r.Group)
+	if err != nil {
+		return err
+	}
+	if members > 0 {
+		return fmt.Errorf("the group is %s with %d members in the destination cluster", state, members)
+	}
+
+	var topics []string
+	for topic := range offsets {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	for _, topic := range topics {
+		partitions, err := client.Metadata(topic)
+		if err != nil {
+			return err
+		}
+		for _, p := range partitions {
+			offset, ok := offsets[topic][p.ID]
+			if !ok {
+				continue
+			}
+			end, err := client.Offset(topic, p, kafka.LatestOffset)
+			if err != nil {
+				return fmt.Errorf(`failed to read the end of partition %d of topic "%s": %w`, p.ID, topic, err)
+			}
+			if offset > end {
+				offsets[topic][p.ID] = end
+				r.Clamped++
+			}
+		}
+		if err := client.CommitOffsets(r.Group, topic, offsets[topic]); err != nil {
+			return err
+		}
+		r.Partitions += len(offsets[topic])
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A runbook records what a failover did, as it does it, so that it can be reviewed and shared once it's over.
+type runbook struct {
+	f           *failover
+	cluster     string
+	environment string
+	groups      []*groupRewrite
+	started     time.Time
+	finished    time.Time
+	steps       []string
+	progress    io.Writer
+}
+
+// logf records a step of the failover, and reports it as it happens.
+func (r *runbook) logf(format string, args ...any) {
+	step := fmt.Sprintf("%s %s", time.Now().UTC().Format(time.TimeOnly), fmt.Sprintf(format, args...))
+	r.steps = append(r.steps, step)
+	fmt.Fprintln(r.progress, step)
+}
+
+// A result is the runbook with --output json or yaml.
+type result struct {
+	Link          string          `json:"link"`
+	SourceCluster string          `json:"source_cluster"`
+	Promote       bool            `json:"promote"`
+	Started       time.Time       `json:"started"`
+	Finished      time.Time       `json:"finished"`
+	MirrorTopics  []*mirror       `json:"mirror_topics"`
+	Groups        []*groupRewrite `json:"consumer_groups"`
+	Steps         []string        `json:"steps"`
+}
+
+// result returns the runbook with the mirror topics which were failed over, and not those which were skipped.
+func (r *runbook) result() result {
+	res := result{
+		Link:          r.f.link,
+		SourceCluster: r.f.sourceCluster,
+		Promote:       r.f.promote,
+		Started:       r.started.UTC(),
+		Finished:      r.finished.UTC(),
+		MirrorTopics:  []*mirror{},
+		Groups:        r.groups,
+		Steps:         r.steps,
+	}
+	for _, m := range r.f.mirrors {
+		if !m.skipped {
+			res.MirrorTopics = append(res.MirrorTopics, m)
+		}
+	}
+	if res.Groups == nil {
+		res.Groups = []*groupRewrite{}
+	}
+	return res
+}
+
+// write writes the runbook as Markdown.
+func (r *runbook) write(w io.Writer) {
+	f := r.f
+	fmt.Fprintf(w, "# Failover of cluster link %s\n\n", f.link)
+
+	fmt.Fprintf(w, "* Started: %s\n", r.started.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "* Finished: %s\n", r.finished.UTC().Format(time.RFC3339))
+	destination := orCurrent(r.cluster, "cluster")
+	if r.environment != "" {
+		destination += " in " + r.environment
+	}
+	fmt.Fprintf(w, "* Destination cluster: %s\n", destination)
+	fmt.Fprintf(w, "* Source cluster: %s\n", f.sourceCluster)
+	if f.promote {
+		fmt.Fprintln(w, "* Mode: promote, so the mirror topics were synced with the source cluster before they were stopped")
+	} else {
+		fmt.Fprintln(w, "* Mode: failover, so the mirror topics were stopped without syncing with the source cluster")
+	}
+
+	fmt.Fprintln(w, "\n## Mirror topics")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Mirror Topic | Source Topic | Status Before | Max Lag Before | Result |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+	for _, m := range f.mirrors {
+		fmt.Fprintf(w, "| %s | %s | %s | %d | %s |\n", m.Topic, m.SourceTopic, m.Status, m.Lag, cell(m.result(f.action())))
+	}
+	if !f.promote {
+		var lost int64
+		for _, m := range f.mirrors {
+			if m.Stopped {
+				lost = max(lost, m.Lag)
+			}
+		}
+		if lost > 0 {
+			fmt.Fprintf(w, "\nUp to %d messages of each partition of the failed over topics may not have been replicated.\n", lost)
+		}
+	}
+
+	if len(r.groups) > 0 {
+		fmt.Fprintln(w, "\n## Consumer group offsets")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Group | Partitions | Result |")
+		fmt.Fprintln(w, "| --- | --- | --- |")
+		for _, g := range r.groups {
+			result := g.Result
+			if g.Error != "" {
+				result = "Failed: " + g.Error
+			}
+			fmt.Fprintf(w, "| %s | %d | %s |\n", g.Group, g.Partitions, cell(result))
+		}
+	}
+
+	fmt.Fprintln(w, "\n## Steps")
+	fmt.Fprintln(w)
+	for _, s := range r.steps {
+		fmt.Fprintf(w, "1. %s\n", s)
+	}
+
+	fmt.Fprintln(w, "\n## Next steps")
+	fmt.Fprintln(w)
+	for _, m := range f.mirrors {
+		if m.Error != "" {
+			fmt.Fprintf(w, "* Retry or investigate mirror topic %s, which wasn't stopped.\n", m.Topic)
+		}
+	}
+	for _, g := range r.groups {
+		if g.Error != "" {
+			fmt.Fprintf(w, "* Set the offsets of consumer group %s, which weren't rewritten, before its consumers start.\n", g.Group)
+		}
+	}
+	fmt.Fprintf(w, "* Point producers and consumers of the promoted topics at %s.\n", destination)
+	fmt.Fprintf(w, "* Delete or reverse cluster link %s once the source cluster is back, so that it doesn't diverge.\n", f.link)
+}
+
+// cell escapes the pipes of a value, which would otherwise end its Markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+func orCurrent(id, resource string) string {
+	if id == "" {
+		return "the current " + resource
+	}
+	return id
+}
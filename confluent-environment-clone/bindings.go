@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// describeBinding describes a role binding by its principal, role, and the resource which the role is bound to.
+func describeBinding(b cli.RoleBinding) string {
+	parts := []string{b.Principal, b.Role, b.Environment}
+	if b.CloudCluster != "" {
+		parts = append(parts, b.CloudCluster)
+	}
+	if b.LogicalCluster != "" && b.LogicalCluster != b.CloudCluster {
+		parts = append(parts, b.LogicalCluster)
+	}
+	if b.ResourceType != "" {
+		resource := b.ResourceType + ":" + b.Name
+		if strings.EqualFold(b.PatternType, "PREFIXED") {
+			resource += "*"
+		}
+		parts = append(parts, resource)
+	}
+	return strings.Join(parts, " ")
+}
+
+// cloneBindings binds the same principals to the same roles in the target, on the clones of the source's environment,
+// clusters, and resources. Bindings on clusters which weren't cloned, such as ksqlDB clusters or Flink, are skipped.
+func (c *cloner) cloneBindings() error {
+	var roles []struct {
+		Name string `json:"name"`
+	}
+	if err := cli.JSON(&roles, "iam", "rbac", "role", "list"); err != nil {
+		return err
+	}
+
+	for _, r := range roles {
+		var bindings []cli.RoleBinding
+		if err := cli.JSON(&bindings, "iam", "rbac", "role-binding", "list", "--role", r.Name, "--environment", c.source, "--inclusive"); err != nil {
+			return err
+		}
+
+		for _, b := range bindings {
+			args, ok := c.bindingArgs(b)
+			if !ok {
+				c.skipped("role binding", describeBinding(b), "its cluster wasn't cloned")
+				continue
+			}
+			if _, err := cli.Run(append([]string{"iam", "rbac", "role-binding", "create"}, args...)...); err != nil {
+				return err
+			}
+
+			clone := b
+			clone.Environment = c.target
+			clone.CloudCluster = c.ids[b.CloudCluster]
+			clone.LogicalCluster = c.ids[b.LogicalCluster]
+			c.cloned("role binding", describeBinding(b), describeBinding(clone))
+		}
+	}
+	return nil
+}
+
+// bindingArgs returns the flags of "confluent iam rbac role-binding create" for the clone of a binding.
+func (c *cloner) bindingArgs(b cli.RoleBinding) ([]string, bool) {
+	args := []string{"--principal", b.Principal, "--role", b.Role, "--environment", c.target}
+
+	if b.CloudCluster != "" {
+		cluster, ok := c.ids[b.CloudCluster]
+		if !ok {
+			return nil, false
+		}
+		args = append(args, "--cloud-cluster", cluster)
+	}
+
+	logical := b.LogicalCluster
+	if logical == "" && b.ResourceType != "" && strings.HasPrefix(b.CloudCluster, "lkc-") {
+		logical = b.CloudCluster
+	}
+	if logical != "" {
+		cluster, ok := c.ids[logical]
+		if !ok {
+			return nil, false
+		}
+		if strings.HasPrefix(cluster, "lsrc-") {
+			args = append(args, "--schema-registry-cluster", cluster)
+		} else {
+			args = append(args, "--kafka-cluster", cluster)
+		}
+	}
+
+	if b.ResourceType != "" {
+		args = append(args, "--resource", b.ResourceType+":"+b.Name)
+		if strings.EqualFold(b.PatternType, "PREFIXED") {
+			args = append(args, "--prefix")
+		}
+	}
+	return args, true
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A mapping is a resource of the source environment, and the resource it was cloned to. Resources which weren't cloned
+// have no target, and a note of why.
+type mapping struct {
+	Kind   string `json:"kind"`
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+type cloner struct {
+	source, target string
+	connectorsDir  string
+	// progress is where the wait for the clusters to be provisioned is shown.
+	progress io.Writer
+
+	// ids maps the IDs of the source's clusters to the IDs of their clones, for the resources inside them.
+	ids     map[string]string
+	mapping []mapping
+}
+
+func newCloner(source, target string) *cloner {
+	return &cloner{source: source, target: target, ids: map[string]string{source: target}}
+}
+
+func (c *cloner) cloned(kind, source, target string) {
+	c.mapping = append(c.mapping, mapping{Kind: kind, Source: source, Target: target})
+	if target != "" {
+		c.ids[source] = target
+	}
+}
+
+func (c *cloner) skipped(kind, source, format string, a ...any) {
+	c.mapping = append(c.mapping, mapping{Kind: kind, Source: source, Note: fmt.Sprintf(format, a...)})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// cloneClusters creates a cluster of the same type, cloud, region, and availability for each cluster in the source, and
+// waits for them to be provisioned, so that topics can be created in them.
+func (c *cloner) cloneClusters(timeout time.Duration) ([]string, error) {
+	var listed []cli.KafkaCluster
+	if err := cli.JSON(&listed, "kafka", "cluster", "list", "--environment", c.source); err != nil {
+		return nil, err
+	}
+
+	var clusters []string
+	for _, l := range listed {
+		var cluster cli.KafkaCluster
+		if err := cli.JSON(&cluster, "kafka", "cluster", "describe", l.ID, "--environment", c.source); err != nil {
+			return nil, err
+		}
+
+		args := []string{"kafka", "cluster", "create", cluster.Name, "--environment", c.target,
+			"--cloud", cluster.Provider, "--region", cluster.Region, "--type", strings.ToLower(cluster.Type)}
+		if cluster.Availability != "" {
+			args = append(args, "--availability", cluster.Availability)
+		}
+		if strings.EqualFold(cluster.Type, "DEDICATED") && cluster.ClusterSize > 0 {
+			args = append(args, "--cku", strconv.Itoa(cluster.ClusterSize))
+		}
+
+		var created cli.KafkaCluster
+		if err := cli.JSON(&created, args...); err != nil {
+			return nil, fmt.Errorf(`failed to clone cluster "%s": %w`, cluster.ID, err)
+		}
+		c.cloned("Kafka cluster", cluster.ID, created.ID)
+		clusters = append(clusters, cluster.ID)
+	}
+
+	if len(clusters) == 0 {
+		return clusters, nil
+	}
+
+	s := progress.Spin(c.progress, "Waiting for the cloned Kafka clusters to be provisioned")
+	defer s.Stop()
+	deadline := time.Now().Add(timeout)
+	for _, id := range clusters {
+		if err := c.waitForCluster(c.ids[id], deadline, s); err != nil {
+			return nil, err
+		}
+	}
+	return clusters, nil
+}
+
+// waitForCluster waits for the cluster to be up, and shows its status on the spinner.
+func (c *cloner) waitForCluster(id string, deadline time.Time, s *progress.Spinner) error {
+	for {
+		var cluster cli.KafkaCluster
+		if err := cli.JSON(&cluster, "kafka", "cluster", "describe", id, "--environment", c.target); err != nil {
+			return err
+		}
+		s.Status(fmt.Sprintf("%s is %s", id, cluster.Status))
+		if strings.EqualFold(cluster.Status, "UP") {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf(`cluster "%s" wasn't provisioned within --cluster-timeout, and is still %s`, id, cluster.Status)
+		}
+		if err := interrupt.Sleep(15 * time.Second); err != nil {
+			return err
+		}
+	}
+}
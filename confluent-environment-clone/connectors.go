@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+)
+
+// maskedValue is how the CLI prints the secrets in a connector's config, which can't be read back.
+const maskedValue = "****************"
+
+// environmentConfigs are set by Confluent Cloud for the cluster a connector runs in, and are dropped from the clone.
+var environmentConfigs = map[string]bool{
+	"cloud.environment": true,
+	"cloud.provider":    true,
+	"kafka.endpoint":    true,
+	"kafka.region":      true,
+}
+
+// connectorDescription is the output of "confluent connect cluster describe".
+type connectorDescription struct {
+	Connector struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"connector"`
+	Configs []struct {
+		Config string `json:"config"`
+		Value  string `json:"value"`
+	} `json:"configs"`
+}
+
+// cloneConnectors creates the connectors of a cluster in its clone. Connectors whose configs contain secrets, such as
+// API keys or database passwords, can't be created without them, so their configs are written to a file without the
+// secrets instead, which can be completed and created with "confluent connect cluster create --config-file".
+func (c *cloner) cloneConnectors(cluster string) error {
+	var connectors []struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&connectors, "connect", "cluster", "list", "--cluster", cluster, "--environment", c.source); err != nil {
+		return err
+	}
+
+	for _, conn := range connectors {
+		var d connectorDescription
+		if err := cli.JSON(&d, "connect", "cluster", "describe", conn.ID, "--cluster", cluster, "--environment", c.source); err != nil {
+			return err
+		}
+
+		configs := map[string]string{}
+		var secrets []string
+		for _, cfg := range d.Configs {
+			switch {
+			case environmentConfigs[cfg.Config]:
+			case cfg.Value == maskedValue:
+				secrets = append(secrets, cfg.Config)
+			default:
+				configs[cfg.Config] = cfg.Value
+			}
+		}
+		configs["name"] = d.Connector.Name
+
+		b, err := json.MarshalIndent(configs, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if len(secrets) > 0 {
+			if err := os.MkdirAll(c.connectorsDir, 0700); err != nil {
+				return err
+			}
+			file := filepath.Join(c.connectorsDir, d.Connector.Name+".json")
+			if err := os.WriteFile(file, b, 0600); err != nil {
+				return err
+			}
+			c.skipped("connector", d.Connector.ID, `wrote the config to "%s", set %s and create it in %s`, file, strings.Join(secrets, ", "), c.ids[cluster])
+			continue
+		}
+
+		id, err := c.createConnector(cluster, b)
+		if err != nil {
+			return fmt.Errorf(`failed to clone connector "%s": %w`, d.Connector.Name, err)
+		}
+		c.cloned("connector", d.Connector.ID, id)
+	}
+	return nil
+}
+
+func (c *cloner) createConnector(cluster string, config []byte) (string, error) {
+	path, err := platform.WriteTemp("connector-*.json", config)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&created, "connect", "cluster", "create", "--config-file", path, "--cluster", c.ids[cluster], "--environment", c.target); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
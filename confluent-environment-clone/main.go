@@ -0,0 +1,178 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "clone",
+		Short: "Clone the structure of an environment into a new environment.",
+		Long:  "Clone the Kafka clusters, topics, schema subjects, role bindings, and connectors of an environment into a new or existing environment, such as for staging or testing, and print a report mapping each resource to its clone. Data isn't copied, and neither are the secrets in connector configs.",
+		Args:  cobra.NoArgs,
+		RunE:  clone,
+		Example: `confluent environment clone --source-environment env-123456 --name staging
+confluent environment clone --source-environment env-123456 --target-environment env-654321 --skip connectors --output json`,
+	}
+
+	cmd.Flags().String("source-environment", "", "ID of the environment to clone.")
+	cmd.Flags().String("name", "", "Name of a new environment to clone into.")
+	cmd.Flags().String("target-environment", "", "ID of an existing environment to clone into, instead of a new one.")
+	cmd.Flags().String("governance-package", "", "Stream Governance package of the new environment, such as essentials, which is needed to clone schemas.")
+	cmd.Flags().StringSlice("skip", nil, "Resources not to clone: topics, schemas, role-bindings, or connectors.")
+	cmd.Flags().String("connectors-dir", "connectors", "Directory to write the configs of connectors which need secrets to.")
+	cmd.Flags().Duration("cluster-timeout", time.Hour, "How long to wait for the cloned clusters to be provisioned.")
+	output.AddFlag(&cmd, "Format of the report")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("source-environment"))
+	cmd.MarkFlagsMutuallyExclusive("name", "target-environment")
+
+	completion.Add(&cmd, "confluent-environment-clone")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-environment-clone"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+func clone(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	source, err := cmd.Flags().GetString("source-environment")
+	cobra.CheckErr(err)
+
+	name, err := cmd.Flags().GetString("name")
+	cobra.CheckErr(err)
+
+	target, err := cmd.Flags().GetString("target-environment")
+	cobra.CheckErr(err)
+
+	governancePackage, err := cmd.Flags().GetString("governance-package")
+	cobra.CheckErr(err)
+
+	skip, err := cmd.Flags().GetStringSlice("skip")
+	cobra.CheckErr(err)
+
+	connectorsDir, err := cmd.Flags().GetString("connectors-dir")
+	cobra.CheckErr(err)
+
+	clusterTimeout, err := cmd.Flags().GetDuration("cluster-timeout")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	skipped := map[string]bool{}
+	for _, s := range skip {
+		if s != "topics" && s != "schemas" && s != "role-bindings" && s != "connectors" {
+			return fmt.Errorf(`unsupported resource "%s", supported resources: topics, schemas, role-bindings, connectors`, s)
+		}
+		skipped[s] = true
+	}
+
+	if name == "" && target == "" {
+		return exitcode.Invalid("either --name or --target-environment must be set")
+	}
+
+	if target == "" {
+		args := []string{"environment", "create", name}
+		if governancePackage != "" {
+			args = append(args, "--governance-package", governancePackage)
+		}
+		var env struct {
+			ID string `json:"id"`
+		}
+		if err := cli.JSON(&env, args...); err != nil {
+			return err
+		}
+		target = env.ID
+	}
+
+	c := newCloner(source, target)
+	c.connectorsDir = connectorsDir
+	c.progress = cmd.ErrOrStderr()
+	c.cloned("environment", source, target)
+
+	// The report is printed even if the clone fails partway, or is interrupted, so that the resources which were created
+	// can be found.
+	err = c.clone(clusterTimeout, skipped)
+	if err := printReport(cmd.OutOrStdout(), format, c.mapping); err != nil {
+		return err
+	}
+	return err
+}
+
+// clone creates the resources in dependency order: topics and connectors need their cluster, and role bindings need
+// every resource they may be bound to.
+func (c *cloner) clone(clusterTimeout time.Duration, skipped map[string]bool) error {
+	clusters, err := c.cloneClusters(clusterTimeout)
+	if err != nil {
+		return err
+	}
+
+	if !skipped["topics"] {
+		for _, cluster := range clusters {
+			if err := c.cloneTopics(cluster); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !skipped["schemas"] {
+		if err := c.cloneSchemas(); err != nil {
+			return err
+		}
+	}
+
+	if !skipped["connectors"] {
+		for _, cluster := range clusters {
+			if err := c.cloneConnectors(cluster); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !skipped["role-bindings"] {
+		if err := c.cloneBindings(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printReport(w io.Writer, format string, mapping []mapping) error {
+	if format != output.Table {
+		return output.Print(w, format, mapping, nil)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Kind\tSource\tTarget")
+	for _, m := range mapping {
+		target := m.Target
+		if target == "" {
+			target = "not cloned: " + m.Note
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.Kind, m.Source, target)
+	}
+	return tw.Flush()
+}
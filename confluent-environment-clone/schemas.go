@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// schemaVersion is the output of "confluent schema-registry schema describe".
+type schemaVersion struct {
+	Schema     string      `json:"schema"`
+	Type       string      `json:"type"`
+	References []reference `json:"references"`
+}
+
+type reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// registryCluster returns the ID of the environment's Schema Registry cluster, or "" if it doesn't have one.
+func registryCluster(environment string) string {
+	var cluster struct {
+		ID string `json:"cluster"`
+	}
+	if err := cli.JSON(&cluster, "schema-registry", "cluster", "describe", "--environment", environment); err != nil {
+		return ""
+	}
+	return cluster.ID
+}
+
+// versions returns the versions of a subject, which older versions of the CLI print as a plain list.
+func (c *cloner) versions(subject string) ([]int, error) {
+	out, err := cli.Run("schema-registry", "subject", "describe", subject, "--environment", c.source, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	if err := json.Unmarshal(out, &versions); err == nil {
+		return versions, nil
+	}
+	var described struct {
+		Versions []int `json:"versions"`
+	}
+	if err := json.Unmarshal(out, &described); err != nil {
+		return nil, fmt.Errorf(`failed to parse the versions of subject "%s": %w`, subject, err)
+	}
+	return described.Versions, nil
+}
+
+// cloneSchemas registers every version of every subject in the target's Schema Registry, in order, so that their
+// compatibility is checked as it was in the source. Versions with references are registered once the versions they
+// reference are, with the references pointing to the clones' version numbers, which differ if versions were deleted.
+func (c *cloner) cloneSchemas() error {
+	source := registryCluster(c.source)
+	if source == "" {
+		return nil
+	}
+	target := registryCluster(c.target)
+	if target == "" {
+		c.skipped("Schema Registry cluster", source, "the target environment has no Schema Registry, enable Stream Governance in it and clone again")
+		return nil
+	}
+	c.cloned("Schema Registry cluster", source, target)
+
+	var subjects []struct {
+		Subject string `json:"subject"`
+	}
+	if err := cli.JSON(&subjects, "schema-registry", "subject", "list", "--environment", c.source); err != nil {
+		return err
+	}
+
+	pending := map[string][]int{}
+	for _, s := range subjects {
+		versions, err := c.versions(s.Subject)
+		if err != nil {
+			return err
+		}
+		pending[s.Subject] = versions
+	}
+
+	dir, err := os.MkdirTemp("", "confluent-environment-clone")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// clones maps the versions of each subject in the source to the versions of their clones. Subjects in the target
+	// are new, so their versions are numbered from 1.
+	clones := map[string]map[int]int{}
+	for len(pending) > 0 {
+		progress := false
+		for _, subject := range sortedKeys(pending) {
+			for len(pending[subject]) > 0 {
+				version := pending[subject][0]
+
+				var schema schemaVersion
+				if err := cli.JSON(&schema, "schema-registry", "schema", "describe", "--subject", subject, "--version", strconv.Itoa(version), "--environment", c.source); err != nil {
+					return err
+				}
+
+				refs, ok := cloneReferences(schema.References, clones)
+				if !ok {
+					break
+				}
+				if err := c.register(dir, subject, schema, refs); err != nil {
+					return err
+				}
+
+				if clones[subject] == nil {
+					clones[subject] = map[int]int{}
+				}
+				clones[subject][version] = len(clones[subject]) + 1
+				c.cloned("schema", fmt.Sprintf("%s/%d", subject, version), fmt.Sprintf("%s/%d", subject, clones[subject][version]))
+
+				pending[subject] = pending[subject][1:]
+				progress = true
+			}
+			if len(pending[subject]) == 0 {
+				delete(pending, subject)
+			}
+		}
+
+		if !progress {
+			for _, subject := range sortedKeys(pending) {
+				c.skipped("schema", fmt.Sprintf("%s/%d", subject, pending[subject][0]), "references a schema which doesn't exist, or was deleted")
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// cloneReferences returns the references of a schema, pointing to the clones of the versions they reference, if every
+// one was cloned.
+func cloneReferences(refs []reference, clones map[string]map[int]int) ([]reference, bool) {
+	cloned := make([]reference, len(refs))
+	for i, r := range refs {
+		version, ok := clones[r.Subject][r.Version]
+		if !ok {
+			return nil, false
+		}
+		cloned[i] = reference{Name: r.Name, Subject: r.Subject, Version: version}
+	}
+	return cloned, true
+}
+
+func (c *cloner) register(dir, subject string, schema schemaVersion, refs []reference) error {
+	schemaFile := filepath.Join(dir, "schema")
+	if err := os.WriteFile(schemaFile, []byte(schema.Schema), 0600); err != nil {
+		return err
+	}
+
+	schemaType := strings.ToLower(schema.Type)
+	if schemaType == "" {
+		schemaType = "avro"
+	}
+
+	args := []string{"schema-registry", "schema", "create", "--subject", subject, "--schema", schemaFile, "--type", schemaType, "--environment", c.target}
+	if len(refs) > 0 {
+		b, err := json.Marshal(refs)
+		if err != nil {
+			return err
+		}
+		refsFile := filepath.Join(dir, "references.json")
+		if err := os.WriteFile(refsFile, b, 0600); err != nil {
+			return err
+		}
+		args = append(args, "--references", refsFile)
+	}
+
+	if _, err := cli.Run(args...); err != nil {
+		return fmt.Errorf(`failed to clone a version of subject "%s": %w`, subject, err)
+	}
+	return nil
+}
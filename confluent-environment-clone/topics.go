@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// cloneTopics creates the topics of a cluster in its clone, with the same partition count and non-default configs. The
+// replication factor is always the clone's default, which is fixed in Confluent Cloud.
+func (c *cloner) cloneTopics(cluster string) error {
+	source := []string{"--cluster", cluster, "--environment", c.source}
+	target := []string{"--cluster", c.ids[cluster], "--environment", c.target}
+
+	var topics []cli.Topic
+	if err := cli.JSON(&topics, append([]string{"kafka", "topic", "list"}, source...)...); err != nil {
+		return err
+	}
+
+	for _, t := range topics {
+		if t.IsInternal {
+			continue
+		}
+
+		var configs []cli.TopicConfig
+		if err := cli.JSON(&configs, append([]string{"kafka", "topic", "configuration", "list", t.Name}, source...)...); err != nil {
+			return fmt.Errorf(`failed to read the configs of topic "%s": %w`, t.Name, err)
+		}
+
+		m := map[string]string{}
+		for _, cfg := range configs {
+			if !cfg.IsDefaultValue && !cfg.IsReadOnly && !cfg.IsSensitive {
+				m[cfg.Name] = cfg.Value
+			}
+		}
+
+		args := append([]string{"kafka", "topic", "create", t.Name, "--partitions", strconv.Itoa(t.PartitionCount)}, target...)
+		if len(m) > 0 {
+			var pairs []string
+			for _, name := range sortedKeys(m) {
+				pairs = append(pairs, name+"="+m[name])
+			}
+			args = append(args, "--config", strings.Join(pairs, ","))
+		}
+		if _, err := cli.Run(args...); err != nil {
+			return fmt.Errorf(`failed to clone topic "%s": %w`, t.Name, err)
+		}
+		c.cloned("topic", cluster+"/"+t.Name, c.ids[cluster]+"/"+t.Name)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := newCommand()
+
+	completion.Add(cmd, "confluent-environment-teardown")
+	cobra.CheckErr(plugin.ApplyConfig(cmd, "confluent-environment-teardown"))
+
+	interrupt.Trap()
+	exitcode.Execute(cmd)
+}
+
+func newCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teardown",
+		Short: "Delete everything inside an environment.",
+		Long:  "Delete every Flink statement and compute pool, connector, ksqlDB cluster, schema subject, API key, and Kafka cluster in an environment, in dependency order, after listing them and asking for confirmation.",
+		Args:  cobra.NoArgs,
+		RunE:  teardown,
+		Example: `confluent environment teardown --environment env-123456
+confluent environment teardown --environment env-123456 --yes --delete-environment`,
+	}
+
+	cmd.Flags().String("environment", "", "ID of the environment to tear down. Defaults to picking one in a terminal.")
+	dryrun.AddFlag(cmd, "List the resources which would be deleted without deleting them.")
+	cmd.Flags().Bool("delete-environment", false, "Delete the environment itself too.")
+	output.AddFlag(cmd, "Format of the deleted resources")
+	cli.AddCacheFlag(cmd)
+	prompt.AddFlags(cmd)
+	logging.AddFlags(cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("yes", "dry-run")
+
+	return cmd
+}
+
+func teardown(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	deleteEnvironment, err := cmd.Flags().GetBool("delete-environment")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	p := prompt.New(cmd)
+	if err := p.Select(&environment, "environment", "Environment to tear down", prompt.EnvironmentOptions); err != nil {
+		return err
+	}
+
+	var env cli.Environment
+	if err := cli.JSON(&env, "environment", "describe", environment); err != nil {
+		return err
+	}
+
+	// Every resource is listed up front, so that the confirmation shows everything which will be deleted.
+	resources := make([][]resource, len(stages))
+	total := 0
+	for i, s := range stages {
+		listed, err := s.list(environment)
+		if err != nil {
+			return err
+		}
+		for j := range listed {
+			listed[j].Kind = s.kind
+		}
+		resources[i] = listed
+		total += len(listed)
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, listed := range resources {
+			for _, r := range listed {
+				plan.Actions = append(plan.Actions, r.action())
+			}
+		}
+		if deleteEnvironment {
+			plan.AddDetail("delete", "environment", env.Name, "("+env.ID+")")
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := output.Progress(cmd)
+	fmt.Fprintf(out, "Environment %s (\"%s\") contains %d resources:\n", env.ID, env.Name, total)
+	for _, listed := range resources {
+		for _, r := range listed {
+			fmt.Fprintf(out, "  %s\n", r)
+		}
+	}
+	if deleteEnvironment {
+		fmt.Fprintln(out, "The environment itself will be deleted too.")
+	}
+
+	var deleted dryrun.Plan
+	if total == 0 && !deleteEnvironment {
+		return dryrun.PrintApplied(cmd, deleted)
+	}
+
+	ok, err := p.Type(fmt.Sprintf("Type the environment ID to delete these resources (%s)", env.ID), env.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Not deleting anything.")
+		return nil
+	}
+
+	// A stage which fails to delete stops the teardown, since later stages may depend on it. What was deleted is
+	// printed with --output json or yaml even then.
+	for i, s := range stages {
+		var errs []error
+		for _, r := range resources[i] {
+			if err := interrupt.Err(); err != nil {
+				return errors.Join(fmt.Errorf("the teardown was interrupted, re-run it to delete the rest: %w", err), dryrun.PrintApplied(cmd, deleted))
+			}
+			if err := r.remove(); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Fprintf(out, "Deleted %s.\n", r)
+			deleted.Actions = append(deleted.Actions, r.action())
+		}
+		if err := errors.Join(errs...); err != nil {
+			return errors.Join(fmt.Errorf("failed to delete every %s, so the teardown stopped: %w", s.kind, err), dryrun.PrintApplied(cmd, deleted))
+		}
+	}
+
+	if deleteEnvironment {
+		if _, err := cli.Run("environment", "delete", env.ID, "--force"); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, deleted))
+		}
+		fmt.Fprintf(out, "Deleted environment %s.\n", env.ID)
+		deleted.AddDetail("delete", "environment", env.Name, "("+env.ID+")")
+	}
+	return dryrun.PrintApplied(cmd, deleted)
+}
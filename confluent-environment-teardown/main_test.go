@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/clitest"
+)
+
+func TestMain(m *testing.M) {
+	clitest.Main(m)
+}
+
+// onListed scripts an environment with one resource of every stage, and a Cloud API key, which isn't the
+// environment's to delete.
+func onListed(fake *clitest.Fake) {
+	fake.On("environment", "describe", "env-123456").Return(cli.Environment{ID: "env-123456", Name: "orders"})
+	fake.On("flink", "compute-pool", "list").Return([]computePool{{ID: "lfcp-123456", Name: "orders-pool", Cloud: "aws", Region: "us-east-1"}})
+	fake.On("flink", "statement", "list", "--compute-pool", "lfcp-123456").Return([]map[string]string{{"name": "orders-count"}})
+	fake.On("kafka", "cluster", "list").Return([]cli.KafkaCluster{{ID: "lkc-123456", Name: "orders-cluster"}})
+	fake.On("connect", "cluster", "list", "--cluster", "lkc-123456").Return([]map[string]string{{"id": "lcc-123456", "name": "orders-sink"}})
+	fake.On("ksql", "cluster", "list").Return([]map[string]string{{"id": "lksqlc-123456", "name": "orders-ksql"}})
+	fake.On("schema-registry", "cluster", "describe").Return(map[string]string{"cluster": "lsrc-123456"})
+	fake.On("schema-registry", "subject", "list").Return([]map[string]string{{"subject": "orders-value"}})
+	fake.On("api-key", "list").Return([]cli.APIKey{
+		{Key: "KAFKAKEY", Description: "orders producer", ResourceType: "kafka", ResourceID: "lkc-123456"},
+		{Key: "CLOUDKEY", Description: "admin", ResourceType: "cloud"},
+	})
+}
+
+// deletes returns the deleting commands which the plugin ran, up to the ID of what they deleted.
+func deletes(fake *clitest.Fake) []string {
+	var ran []string
+	for _, c := range fake.Calls() {
+		if slices.Contains(c.Args[:3], "delete") {
+			ran = append(ran, strings.Join(c.Args[:min(len(c.Args), 4)], " "))
+		}
+	}
+	return ran
+}
+
+func TestTeardownOrder(t *testing.T) {
+	fake := clitest.New(t)
+	onListed(fake)
+	fake.On("flink", "statement", "delete")
+	fake.On("flink", "compute-pool", "delete")
+	fake.On("connect", "cluster", "delete")
+	fake.On("ksql", "cluster", "delete")
+	fake.On("schema-registry", "schema", "delete")
+	fake.On("api-key", "delete")
+	fake.On("kafka", "cluster", "delete")
+	fake.On("environment", "delete")
+
+	cmd := newCommand()
+	cmd.SetIn(strings.NewReader("env-123456\n"))
+	if _, _, err := clitest.Execute(cmd, "--environment", "env-123456", "--delete-environment"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every resource is deleted before what it depends on, the subject is deleted permanently after it's
+	// soft-deleted, and the environment is deleted last. The Cloud API key is kept.
+	want := []string{
+		"flink statement delete orders-count",
+		"flink compute-pool delete lfcp-123456",
+		"connect cluster delete lcc-123456",
+		"ksql cluster delete lksqlc-123456",
+		"schema-registry schema delete --subject",
+		"schema-registry schema delete --subject",
+		"api-key delete KAFKAKEY --force",
+		"kafka cluster delete lkc-123456",
+		"environment delete env-123456 --force",
+	}
+	if ran := deletes(fake); !slices.Equal(ran, want) {
+		t.Errorf("ran:\n  %s\nwant:\n  %s", strings.Join(ran, "\n  "), strings.Join(want, "\n  "))
+	}
+	if fake.Called("schema-registry", "schema", "delete", "--subject", "orders-value", "--version", "all", "--environment", "env-123456", "--force", "--permanent") != 1 {
+		t.Error("the subject wasn't deleted permanently")
+	}
+}
+
+func TestTeardownStopsAfterFailure(t *testing.T) {
+	fake := clitest.New(t)
+	onListed(fake)
+	fake.On("flink", "statement", "delete")
+	fake.On("flink", "compute-pool", "delete")
+	fake.On("connect", "cluster", "delete")
+	fake.On("ksql", "cluster", "delete").Fail(`Error: ksqlDB cluster "lksqlc-123456" is protected`)
+
+	cmd := newCommand()
+	cmd.SetIn(strings.NewReader("env-123456\n"))
+	stdout, _, err := clitest.Execute(cmd, "--environment", "env-123456", "--delete-environment", "--output", "json")
+	if err == nil {
+		t.Fatal("the teardown succeeded, although the ksqlDB cluster failed to delete")
+	}
+
+	// The stages after the failed one aren't deleted, since they may still be in use by it, and neither is the
+	// environment.
+	for _, args := range [][]string{{"schema-registry", "schema", "delete"}, {"api-key", "delete"}, {"kafka", "cluster", "delete"}, {"environment", "delete"}} {
+		if n := fake.Called(args...); n != 0 {
+			t.Errorf("ran confluent %s %d times after the ksqlDB cluster failed to delete", strings.Join(args, " "), n)
+		}
+	}
+
+	// --output json prints what was torn down, despite the failure.
+	var printed struct {
+		Actions []struct {
+			Action   string `json:"action"`
+			Resource string `json:"resource"`
+			Name     string `json:"name"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &printed); err != nil {
+		t.Fatalf("stdout isn't JSON: %v\n%s", err, stdout)
+	}
+	var deleted []string
+	for _, a := range printed.Actions {
+		deleted = append(deleted, a.Action+" "+a.Resource+" "+a.Name)
+	}
+	want := []string{"delete Flink statement orders-count", "delete Flink compute pool orders-pool", "delete connector orders-sink"}
+	if !slices.Equal(deleted, want) {
+		t.Errorf("printed %v, want %v", deleted, want)
+	}
+}
+
+func TestTeardownWrongID(t *testing.T) {
+	fake := clitest.New(t)
+	onListed(fake)
+
+	cmd := newCommand()
+	cmd.SetIn(strings.NewReader("env-654321\n"))
+	if _, _, err := clitest.Execute(cmd, "--environment", "env-123456"); err != nil {
+		t.Fatal(err)
+	}
+	if ran := deletes(fake); len(ran) != 0 {
+		t.Errorf("ran %v, although the typed environment ID doesn't match", ran)
+	}
+}
+
+func TestTeardownDryRun(t *testing.T) {
+	fake := clitest.New(t)
+	onListed(fake)
+
+	stdout, _, err := clitest.Execute(newCommand(), "--environment", "env-123456", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ran := deletes(fake); len(ran) != 0 {
+		t.Errorf("ran %v in a dry run", ran)
+	}
+	for _, name := range []string{"orders-count", "orders-pool", "orders-sink", "orders-ksql", "orders-value", "orders producer", "orders-cluster"} {
+		if !strings.Contains(stdout, name) {
+			t.Errorf("the plan doesn't delete %s:\n%s", name, stdout)
+		}
+	}
+	if strings.Contains(stdout, "CLOUDKEY") || strings.Contains(stdout, "admin") {
+		t.Errorf("the plan deletes the Cloud API key:\n%s", stdout)
+	}
+}
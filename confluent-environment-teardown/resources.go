@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+)
+
+// A resource is something in the environment to delete, along with the confluent CLI arguments which delete it.
+type resource struct {
+	Kind   string
+	ID     string
+	Name   string
+	delete []string
+}
+
+// A stage is a kind of resource. Stages are deleted in order, so that nothing is deleted while another resource still
+// depends on it, such as a compute pool with running statements, or a Kafka cluster with connectors.
+type stage struct {
+	kind string
+	list func(environment string) ([]resource, error)
+}
+
+var stages = []stage{
+	{"Flink statement", listStatements},
+	{"Flink compute pool", listComputePools},
+	{"connector", listConnectors},
+	{"ksqlDB cluster", listKsqlClusters},
+	{"schema subject", listSubjects},
+	{"API key", listAPIKeys},
+	{"Kafka cluster", listKafkaClusters},
+}
+
+type computePool struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+}
+
+func listComputePools(environment string) ([]resource, error) {
+	var pools []computePool
+	if err := cli.JSON(&pools, "flink", "compute-pool", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource, len(pools))
+	for i, p := range pools {
+		resources[i] = resource{
+			ID:     p.ID,
+			Name:   p.Name,
+			delete: []string{"flink", "compute-pool", "delete", p.ID, "--environment", environment, "--force"},
+		}
+	}
+	return resources, nil
+}
+
+// listStatements lists the statements of every compute pool. Statements are regional, so they're deleted in their
+// pool's cloud and region.
+func listStatements(environment string) ([]resource, error) {
+	var pools []computePool
+	if err := cli.JSON(&pools, "flink", "compute-pool", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	var resources []resource
+	for _, p := range pools {
+		var statements []struct {
+			Name string `json:"name"`
+		}
+		if err := cli.JSON(&statements, "flink", "statement", "list", "--compute-pool", p.ID, "--environment", environment); err != nil {
+			return nil, err
+		}
+		for _, s := range statements {
+			resources = append(resources, resource{
+				ID:     s.Name,
+				delete: []string{"flink", "statement", "delete", s.Name, "--environment", environment, "--cloud", p.Cloud, "--region", p.Region, "--force"},
+			})
+		}
+	}
+	return resources, nil
+}
+
+func listKafkaClusters(environment string) ([]resource, error) {
+	var clusters []cli.KafkaCluster
+	if err := cli.JSON(&clusters, "kafka", "cluster", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource, len(clusters))
+	for i, c := range clusters {
+		resources[i] = resource{
+			ID:     c.ID,
+			Name:   c.Name,
+			delete: []string{"kafka", "cluster", "delete", c.ID, "--environment", environment, "--force"},
+		}
+	}
+	return resources, nil
+}
+
+func listConnectors(environment string) ([]resource, error) {
+	var clusters []cli.KafkaCluster
+	if err := cli.JSON(&clusters, "kafka", "cluster", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	var resources []resource
+	for _, c := range clusters {
+		var connectors []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := cli.JSON(&connectors, "connect", "cluster", "list", "--cluster", c.ID, "--environment", environment); err != nil {
+			return nil, err
+		}
+		for _, conn := range connectors {
+			resources = append(resources, resource{
+				ID:     conn.ID,
+				Name:   conn.Name,
+				delete: []string{"connect", "cluster", "delete", conn.ID, "--cluster", c.ID, "--environment", environment, "--force"},
+			})
+		}
+	}
+	return resources, nil
+}
+
+func listKsqlClusters(environment string) ([]resource, error) {
+	var clusters []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := cli.JSON(&clusters, "ksql", "cluster", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource, len(clusters))
+	for i, c := range clusters {
+		resources[i] = resource{
+			ID:     c.ID,
+			Name:   c.Name,
+			delete: []string{"ksql", "cluster", "delete", c.ID, "--environment", environment, "--force"},
+		}
+	}
+	return resources, nil
+}
+
+// listSubjects lists the subjects in the environment's Schema Registry, if it has one.
+func listSubjects(environment string) ([]resource, error) {
+	var cluster struct {
+		ID string `json:"cluster"`
+	}
+	if err := cli.JSON(&cluster, "schema-registry", "cluster", "describe", "--environment", environment); err != nil {
+		// Environments without Stream Governance don't have a Schema Registry cluster.
+		return nil, nil
+	}
+
+	var subjects []struct {
+		Subject string `json:"subject"`
+	}
+	if err := cli.JSON(&subjects, "schema-registry", "subject", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource, len(subjects))
+	for i, s := range subjects {
+		resources[i] = resource{
+			ID: s.Subject,
+			// Subjects are deleted permanently, so that their names can be reused, which requires soft-deleting them
+			// first.
+			delete: []string{"schema-registry", "schema", "delete", "--subject", s.Subject, "--version", "all", "--environment", environment, "--force"},
+		}
+	}
+	return resources, nil
+}
+
+func listAPIKeys(environment string) ([]resource, error) {
+	var keys []cli.APIKey
+	if err := cli.JSON(&keys, "api-key", "list", "--environment", environment); err != nil {
+		return nil, err
+	}
+
+	var resources []resource
+	for _, k := range keys {
+		// Cloud API keys aren't scoped to the environment.
+		if k.ResourceType == "cloud" {
+			continue
+		}
+		resources = append(resources, resource{
+			ID:     k.Key,
+			Name:   k.Description,
+			delete: []string{"api-key", "delete", k.Key, "--force"},
+		})
+	}
+	return resources, nil
+}
+
+// remove deletes the resource, and for a subject, deletes it permanently once it's soft-deleted.
+func (r resource) remove() error {
+	if _, err := cli.Run(r.delete...); err != nil {
+		return err
+	}
+	if r.Kind == "schema subject" {
+		if _, err := cli.Run(append(r.delete, "--permanent")...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// action is deleting the resource, for --dry-run.
+func (r resource) action() dryrun.Action {
+	if r.Name == "" {
+		return dryrun.Action{Verb: "delete", Resource: r.Kind, Name: r.ID}
+	}
+	return dryrun.Action{Verb: "delete", Resource: r.Kind, Name: r.Name, Detail: "(" + r.ID + ")"}
+}
+
+func (r resource) String() string {
+	if r.Name == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.ID)
+	}
+	return fmt.Sprintf(`%s %s ("%s")`, r.Kind, r.ID, r.Name)
+}
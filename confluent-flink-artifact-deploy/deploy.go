@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// artifact is an artifact in the output of "confluent flink artifact list" and "confluent flink artifact create".
+type artifact struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// A deployment is the artifact which a JAR is uploaded as, the version of the artifact before it, and the running
+// statements which call its functions. Each deployment is a new artifact named <name>-v<version>, so that the previous
+// one is left to roll back to.
+type deployment struct {
+	artifact   string
+	version    int
+	previous   *artifact
+	statements []listedStatement
+}
+
+// A deployer deploys a JAR as an artifact, registers its functions, and restarts the statements which call them,
+// undoing every step which was done if a later one fails.
+type deployer struct {
+	environment    string
+	pool           computePool
+	database       string
+	serviceAccount string
+	timeout        time.Duration
+	out            io.Writer
+
+	name      string
+	functions map[string]string
+
+	// undo are the steps which undo what was done so far, which are run in reverse order.
+	undo []step
+}
+
+type step struct {
+	description string
+	run         func() error
+}
+
+var versionSuffix = regexp.MustCompile(`-v(\d+)$`)
+
+// plan finds the latest version of the artifact, and the running statements on the compute pool which call its
+// functions.
+func (d *deployer) plan() (deployment, error) {
+	var artifacts []artifact
+	if err := cli.JSON(&artifacts, append([]string{"flink", "artifact", "list"}, d.scope()...)...); err != nil {
+		return deployment{}, err
+	}
+
+	p := deployment{version: 1}
+	for _, a := range artifacts {
+		m := versionSuffix.FindStringSubmatch(a.Name)
+		if m == nil || a.Name[:len(a.Name)-len(m[0])] != d.name {
+			continue
+		}
+		if version, _ := strconv.Atoi(m[1]); version >= p.version {
+			p.version = version + 1
+			p.previous = &artifact{ID: a.ID, Name: a.Name}
+		}
+	}
+	p.artifact = fmt.Sprintf("%s-v%d", d.name, p.version)
+
+	args := []string{"flink", "statement", "list", "--compute-pool", d.pool.ID}
+	if d.environment != "" {
+		args = append(args, "--environment", d.environment)
+	}
+	var statements []listedStatement
+	if err := cli.JSON(&statements, args...); err != nil {
+		return deployment{}, err
+	}
+	for _, s := range statements {
+		if s.Status == "RUNNING" && callsAny(s.Statement, d.functions) {
+			p.statements = append(p.statements, s)
+		}
+	}
+	sort.Slice(p.statements, func(i, j int) bool { return p.statements[i].Name < p.statements[j].Name })
+	return p, nil
+}
+
+// restartedName is the name of a statement once it's restarted with a version of the artifact, which replaces the
+// version it was restarted with before, if any.
+func restartedName(name string, version int) string {
+	return fmt.Sprintf("%s-v%d", versionSuffix.ReplaceAllString(name, ""), version)
+}
+
+// deploy runs the deployment. If a step fails, the steps before it are undone: the statements which were stopped are
+// resumed, the functions are registered with the previous artifact again, and the new artifact is deleted.
+func (d *deployer) deploy(p deployment, jar string) error {
+	if err := d.steps(p, jar); err != nil {
+		fmt.Fprintf(d.out, "Deployment failed, rolling back: %v\n", err)
+		return errors.Join(err, d.rollBack())
+	}
+
+	fmt.Fprintf(d.out, "Deployed %s with %d functions, and restarted %d statements.\n", p.artifact, len(d.functions), len(p.statements))
+	return nil
+}
+
+func (d *deployer) steps(p deployment, jar string) error {
+	var created artifact
+	args := append([]string{"flink", "artifact", "create", p.artifact, "--artifact-file", jar, "--description", "Deployed by confluent flink artifact deploy."}, d.scope()...)
+	if err := cli.JSON(&created, args...); err != nil {
+		return err
+	}
+	fmt.Fprintf(d.out, "Created artifact %s (%s).\n", p.artifact, created.ID)
+	d.done(fmt.Sprintf("delete artifact %s", created.ID), func() error {
+		_, err := cli.Run(append([]string{"flink", "artifact", "delete", created.ID, "--force"}, d.scope()...)...)
+		return err
+	})
+
+	// How to restore a function is recorded before it's replaced, since replacing it drops it first, which may be all
+	// that succeeds.
+	for _, name := range sortedKeys(d.functions) {
+		name := name
+		if p.previous != nil {
+			d.done(fmt.Sprintf("register function %s with %s", name, p.previous.ID), func() error {
+				return d.register(name, p.previous.ID)
+			})
+		} else {
+			d.done(fmt.Sprintf("drop function %s", name), func() error {
+				return d.execute(fmt.Sprintf("DROP FUNCTION IF EXISTS `%s`", name))
+			})
+		}
+
+		if err := d.register(name, created.ID); err != nil {
+			return fmt.Errorf(`failed to register function "%s": %w`, name, err)
+		}
+		fmt.Fprintf(d.out, "Registered function %s with %s.\n", name, created.ID)
+	}
+
+	// Each statement is stopped before its replacement starts, so that they don't both write to the same tables.
+	for _, s := range p.statements {
+		s := s
+		if _, err := cli.Run(append([]string{"flink", "statement", "stop", s.Name}, d.scope()...)...); err != nil {
+			return err
+		}
+		fmt.Fprintf(d.out, "Stopped statement %s.\n", s.Name)
+		d.done(fmt.Sprintf("resume statement %s", s.Name), func() error {
+			_, err := cli.Run(append([]string{"flink", "statement", "resume", s.Name}, d.scope()...)...)
+			return err
+		})
+
+		name, err := d.create(restartedName(s.Name, p.version), s.Statement)
+		if err != nil {
+			return fmt.Errorf("failed to restart statement %s: %w", s.Name, err)
+		}
+		d.done(fmt.Sprintf("delete statement %s", name), func() error {
+			_, err := cli.Run(append([]string{"flink", "statement", "delete", name, "--force"}, d.scope()...)...)
+			return err
+		})
+		if err := d.wait(name); err != nil {
+			return fmt.Errorf("failed to restart statement %s as %s: %w", s.Name, name, err)
+		}
+		fmt.Fprintf(d.out, "Restarted statement %s as %s.\n", s.Name, name)
+	}
+	return nil
+}
+
+// register replaces the function with the class of the artifact.
+func (d *deployer) register(name, artifactID string) error {
+	if err := d.execute(fmt.Sprintf("DROP FUNCTION IF EXISTS `%s`", name)); err != nil {
+		return err
+	}
+	return d.execute(fmt.Sprintf("CREATE FUNCTION `%s` AS '%s' USING JAR 'confluent-artifact://%s'", name, d.functions[name], artifactID))
+}
+
+func (d *deployer) done(description string, undo func() error) {
+	d.undo = append(d.undo, step{description, undo})
+}
+
+// rollBack undoes the steps which were done in reverse order, and carries on past the ones which fail, so that as much
+// as possible is undone.
+func (d *deployer) rollBack() error {
+	var errs []error
+	for i := len(d.undo) - 1; i >= 0; i-- {
+		s := d.undo[i]
+		if err := s.run(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to %s: %w", s.description, err))
+			continue
+		}
+		fmt.Fprintf(d.out, "Rolled back: %s.\n", s.description)
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
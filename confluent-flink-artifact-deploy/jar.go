@@ -0,0 +1,31 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+)
+
+// checkJAR checks that the file is a JAR which contains the class of each function, so that a wrong file or class
+// name is found before anything is uploaded.
+func checkJAR(path string, functions map[string]string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("%s isn't a JAR: %w", path, err)
+	}
+	defer r.Close()
+
+	classes := map[string]bool{}
+	for _, f := range r.File {
+		if name, ok := strings.CutSuffix(f.Name, ".class"); ok {
+			classes[strings.ReplaceAll(name, "/", ".")] = true
+		}
+	}
+
+	for _, name := range sortedKeys(functions) {
+		if !classes[functions[name]] {
+			return fmt.Errorf(`%s doesn't contain class "%s" of function "%s"`, path, functions[name], name)
+		}
+	}
+	return nil
+}
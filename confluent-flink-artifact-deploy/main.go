@@ -0,0 +1,148 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "deploy <jar>",
+		Short: "Deploy a JAR of Flink UDFs and restart the statements which use them.",
+		Long:  "Upload a JAR as a new version of a Flink artifact, register its user-defined functions with it, and restart the running statements on the compute pool which call them, so that they use the new version. If any step fails, the steps before it are rolled back: the statements are resumed, the functions are registered with the previous version again, and the new version is deleted.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  deploy,
+		Example: `confluent flink artifact deploy target/udfs.jar --compute-pool lfcp-123456 --function tax=com.example.udf.Tax --database orders-cluster
+confluent flink artifact deploy udfs.jar --name orders-udfs --compute-pool lfcp-123456 --function tax=com.example.udf.Tax,fx=com.example.udf.Fx --dry-run`,
+	}
+
+	cmd.Flags().String("name", "", "Name of the artifact, whose versions are named <name>-v<version>. Defaults to the name of the JAR.")
+	cmd.Flags().StringToString("function", nil, `Functions of the JAR, as "<function>=<class>" pairs.`)
+	cmd.Flags().String("compute-pool", "", "Flink compute pool ID.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("database", "", "Kafka cluster to register the functions in, and run the statements with as the default database. Defaults to the compute pool's.")
+	cmd.Flags().String("service-account", "", "Service account to run the statements as. Defaults to the current user.")
+	cmd.Flags().Bool("no-restart", false, "Only register the functions, without restarting the statements which call them.")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for each statement to be running or to complete.")
+	dryrun.AddFlag(&cmd, "Print what would be deployed and restarted without changing anything.")
+	output.AddFlag(&cmd, "Format of what was deployed and restarted")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("function"))
+	cobra.CheckErr(cmd.MarkFlagRequired("compute-pool"))
+
+	completion.Add(&cmd, "confluent-flink-artifact-deploy")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-flink-artifact-deploy"))
+
+	exitcode.Execute(&cmd)
+}
+
+func deploy(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	name, err := cmd.Flags().GetString("name")
+	cobra.CheckErr(err)
+
+	functions, err := cmd.Flags().GetStringToString("function")
+	cobra.CheckErr(err)
+
+	poolID, err := cmd.Flags().GetString("compute-pool")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	database, err := cmd.Flags().GetString("database")
+	cobra.CheckErr(err)
+
+	serviceAccount, err := cmd.Flags().GetString("service-account")
+	cobra.CheckErr(err)
+
+	noRestart, err := cmd.Flags().GetBool("no-restart")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	jar := args[0]
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(jar), filepath.Ext(jar))
+	}
+	for function, class := range functions {
+		if function == "" || class == "" {
+			return fmt.Errorf(`invalid function "%s=%s", expected "<function>=<class>"`, function, class)
+		}
+	}
+	if err := checkJAR(jar, functions); err != nil {
+		return err
+	}
+
+	var pool computePool
+	describeArgs := []string{"flink", "compute-pool", "describe", poolID}
+	if environment != "" {
+		describeArgs = append(describeArgs, "--environment", environment)
+	}
+	if err := cli.JSON(&pool, describeArgs...); err != nil {
+		return err
+	}
+
+	d := &deployer{
+		environment:    environment,
+		pool:           pool,
+		database:       database,
+		serviceAccount: serviceAccount,
+		timeout:        timeout,
+		out:            output.Progress(cmd),
+		name:           name,
+		functions:      functions,
+	}
+	p, err := d.plan()
+	if err != nil {
+		return err
+	}
+	if noRestart {
+		p.statements = nil
+	}
+
+	var plan dryrun.Plan
+	plan.AddDetail("create", "artifact", p.artifact, "from "+jar)
+	for _, function := range sortedKeys(functions) {
+		plan.AddDetail("register", "function", function, "as "+functions[function])
+	}
+	for _, s := range p.statements {
+		plan.AddDetail("restart", "statement", s.Name, fmt.Sprintf(`as "%s"`, restartedName(s.Name, p.version)))
+	}
+	if dryRun {
+		return dryrun.Print(cmd, plan)
+	}
+
+	// A deployment which fails is rolled back, so there is nothing which was deployed to print.
+	if err := d.deploy(p, jar); err != nil {
+		return err
+	}
+	return dryrun.PrintApplied(cmd, plan)
+}
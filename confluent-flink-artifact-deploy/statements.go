@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// pollInterval is how often a statement's status is checked while waiting for it.
+const pollInterval = 5 * time.Second
+
+type computePool struct {
+	ID     string `json:"id"`
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+}
+
+// listedStatement is a statement in the output of "confluent flink statement list".
+type listedStatement struct {
+	Name      string `json:"name"`
+	Statement string `json:"statement"`
+	Status    string `json:"status"`
+}
+
+// statementStatus is the part of "confluent flink statement describe" which the deployer waits on.
+type statementStatus struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	StatusDetail string `json:"status_detail"`
+}
+
+// callsAny returns whether the SQL calls any of the functions, which are case-insensitive in Flink SQL.
+func callsAny(sql string, functions map[string]string) bool {
+	for name := range functions {
+		if regexp.MustCompile(`(?i)(^|[^\w.])` + "`?" + regexp.QuoteMeta(name) + "`?" + `\s*\(`).MatchString(sql) {
+			return true
+		}
+	}
+	return false
+}
+
+// create creates a statement, which is named by the CLI unless it's given a name, and returns its name.
+func (d *deployer) create(name, sql string) (string, error) {
+	args := []string{"flink", "statement", "create"}
+	if name != "" {
+		args = append(args, name)
+	}
+	args = append(args, "--sql", sql, "--compute-pool", d.pool.ID)
+	if d.environment != "" {
+		args = append(args, "--environment", d.environment)
+	}
+	if d.database != "" {
+		args = append(args, "--database", d.database)
+	}
+	if d.serviceAccount != "" {
+		args = append(args, "--service-account", d.serviceAccount)
+	}
+
+	var created statementStatus
+	if err := cli.JSON(&created, args...); err != nil {
+		return "", err
+	}
+	return created.Name, nil
+}
+
+// execute runs a DDL statement and waits for it to complete.
+func (d *deployer) execute(sql string) error {
+	name, err := d.create("", sql)
+	if err != nil {
+		return err
+	}
+	return d.wait(name)
+}
+
+func (d *deployer) wait(name string) error {
+	deadline := time.Now().Add(d.timeout)
+	for {
+		var status statementStatus
+		if err := cli.JSON(&status, append([]string{"flink", "statement", "describe", name}, d.scope()...)...); err != nil {
+			return err
+		}
+
+		switch status.Status {
+		case "RUNNING", "COMPLETED":
+			return nil
+		case "FAILED", "FAILING":
+			if status.StatusDetail != "" {
+				return fmt.Errorf("statement %s failed: %s", name, status.StatusDetail)
+			}
+			return fmt.Errorf("statement %s failed", name)
+		case "STOPPED", "DELETING":
+			return fmt.Errorf("statement %s was stopped", name)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("statement %s is still %s after %s", name, strings.ToLower(status.Status), d.timeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// scope is the environment, cloud, and region of the statements and artifacts, which regional commands need.
+func (d *deployer) scope() []string {
+	args := []string{"--cloud", d.pool.Cloud, "--region", d.pool.Region}
+	if d.environment != "" {
+		args = append(args, "--environment", d.environment)
+	}
+	return args
+}
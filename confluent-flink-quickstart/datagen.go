@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// seed creates the topic of each table, unless it exists, and a Datagen connector which writes records to it, and
+// waits for the connectors to run. The connectors produce with an API key of the current user.
+func (q *quickstart) seed(tables []table) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	var existing []cli.Topic
+	if err := cli.JSON(&existing, "kafka", "topic", "list"); err != nil {
+		return err
+	}
+
+	topics := map[string]bool{}
+	for _, t := range existing {
+		topics[t.Name] = true
+	}
+
+	for _, t := range tables {
+		topic := t.Name
+		q.topics = append(q.topics, topic)
+		if topics[topic] {
+			continue
+		}
+		args := []string{"kafka", "topic", "create", topic}
+		if t.Partitions > 0 {
+			args = append(args, "--partitions", fmt.Sprint(t.Partitions))
+		}
+		if _, err := cli.Run(args...); err != nil {
+			return err
+		}
+		fmt.Fprintf(q.out, "Created topic \"%s\".\n", topic)
+		q.created.Add("topic", topic, "", "kafka", "topic", "delete", topic, "--cluster", q.cluster, "--environment", q.environment, "--force")
+	}
+
+	key, err := createKey(q.cluster, fmt.Sprintf(`Datagen connectors of Flink quickstart "%s"`, q.name))
+	if err != nil {
+		return err
+	}
+	q.created.Add("API key", "", key.Key, "api-key", "delete", key.Key, "--force")
+
+	var ids []string
+	for i, t := range tables {
+		id, err := createConnector("datagen-"+q.topics[i], t, key)
+		if err != nil {
+			return fmt.Errorf(`failed to create the Datagen connector of table "%s": %w`, t.Name, err)
+		}
+		fmt.Fprintf(q.out, "Created Datagen connector \"%s\" (%s).\n", "datagen-"+q.topics[i], id)
+		q.created.Add("Datagen connector", "datagen-"+q.topics[i], id, "connect", "cluster", "delete", id, "--cluster", q.cluster, "--environment", q.environment, "--force")
+		ids = append(ids, id)
+	}
+
+	s := progress.Spin(q.out, "Waiting for the Datagen connectors to run")
+	defer s.Stop()
+	for i, id := range ids {
+		s.Status(fmt.Sprintf("%d of %d running", i, len(ids)))
+		if err := q.waitForConnector(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createConnector creates a Datagen connector which writes the records of the table's quickstart, or of its schema, to
+// its topic.
+func createConnector(name string, t table, key cli.CreatedAPIKey) (string, error) {
+	configs := map[string]string{
+		"name":               name,
+		"connector.class":    "DatagenSource",
+		"kafka.auth.mode":    "KAFKA_API_KEY",
+		"kafka.api.key":      key.Key,
+		"kafka.api.secret":   key.Secret,
+		"kafka.topic":        t.Name,
+		"output.data.format": t.Format,
+		"tasks.max":          "1",
+	}
+	if t.Quickstart != "" {
+		configs["quickstart"] = t.Quickstart
+	} else {
+		configs["schema.string"] = t.schema
+		if t.KeyField != "" {
+			configs["schema.keyfield"] = t.KeyField
+		}
+	}
+	if t.Interval > 0 {
+		configs["max.interval"] = fmt.Sprint(t.Interval)
+	}
+	b, err := json.Marshal(configs)
+	if err != nil {
+		return "", err
+	}
+	// The file is only readable by the current user, since it contains the API secret.
+	path, err := platform.WriteTemp("flink-quickstart-*.json", b)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&created, "connect", "cluster", "create", "--config-file", path); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// waitForConnector waits for the connector to run. Connectors which fail are resumed, since a new API key can take a
+// while to be usable.
+func (q *quickstart) waitForConnector(id string) error {
+	for {
+		var described struct {
+			Connector struct {
+				Status string `json:"status"`
+			} `json:"connector"`
+		}
+		if err := cli.JSON(&described, "connect", "cluster", "describe", id); err != nil {
+			return err
+		}
+
+		switch described.Connector.Status {
+		case "RUNNING":
+			return nil
+		case "FAILED":
+			if _, err := cli.Run("connect", "cluster", "resume", id); err != nil {
+				return err
+			}
+		}
+
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for Datagen connector "%s" to run, its status is %s`, id, described.Connector.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+	}
+}
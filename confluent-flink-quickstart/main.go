@@ -0,0 +1,235 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+// regions are the AWS regions which Flink compute pools of the quickstart can be created in.
+var regions = []string{"us-east-1", "us-east-2", "eu-central-1", "eu-west-1"}
+
+// maxCFUs are the sizes which a Flink compute pool can be limited to.
+var maxCFUs = []int{5, 10, 20, 30, 40, 50}
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "quickstart",
+		Short: "Create a Flink compute pool and start a Flink SQL shell.",
+		Long:  "Create a Flink compute pool, in an environment which is reused or created, with a Kafka cluster of the region as its database, which is picked from the existing ones or created along with Schema Registry. Datagen connectors can seed the database with tables to query, from quickstarts or a file of tables with schemas of their own, and once the compute pool is provisioned, seed statements are run on it, and a Flink SQL shell is started, with the tables already queryable.",
+		Args:  quickstartArgs,
+		RunE:  start,
+		Example: `confluent flink quickstart --name orders-pool --datagen-quickstarts shoe_orders,shoe_customers,shoes
+confluent flink quickstart --name orders-pool --environment-name workshop --cluster create --max-cfu 10 --no-shell
+confluent flink quickstart --name payments-demo --compute-pool-name payments --max-cfu 20 --tables demo-tables.yml --sql-file demo.sql
+confluent flink quickstart --name orders-pool --environment env-123456 --cluster lkc-123456
+confluent flink quickstart --name lakehouse --datagen-quickstarts shoe_orders --enable-tableflow
+confluent flink quickstart list-quickstarts
+confluent flink quickstart teardown --name orders-pool`,
+	}
+
+	cmd.Flags().String("name", "", "Name of the Flink compute pool, and the prefix of the names of the environment and Kafka cluster if they're created.")
+	cmd.Flags().Int("max-cfu", 5, "Maximum number of Confluent Flink Units of the compute pool: 5, 10, 20, 30, 40, or 50.")
+	cmd.Flags().String("compute-pool-name", "", "Name of the compute pool. Defaults to --name.")
+	cmd.Flags().String("environment-name", "", "Name of the environment to use, which is created if it doesn't exist. Defaults to <name>_environment.")
+	cmd.Flags().String("environment", "", "ID of an existing environment to use, rather than one named by --environment-name.")
+	cmd.Flags().String("region", "us-east-1", "Cloud region of the compute pool and Kafka cluster: "+strings.Join(regions, ", ")+".")
+	cmd.Flags().String("cloud", "aws", "Cloud provider of the compute pool and Kafka cluster: aws.")
+	cmd.Flags().StringSlice("datagen-quickstarts", nil, "Datagen quickstarts to seed tables with, such as shoe_orders or shoes, which list-quickstarts lists.")
+	cmd.Flags().String("tables", "", "YAML file of the tables to seed with Datagen connectors, from quickstarts or schemas of their own, along with --datagen-quickstarts.")
+	cmd.Flags().String("sql-file", "", "File of SQL statements, separated by semicolons, to run on the compute pool before the Flink SQL shell starts. Defaults to a query of each seeded table, which waits for its first record.")
+	cmd.Flags().String("cluster", "", `ID of the Kafka cluster to use as the database, or "create" to create one. Defaults to picking one of the clusters in the region in a terminal.`)
+	cmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for the resources to be provisioned.")
+	cmd.Flags().Bool("enable-tableflow", false, "Enable Tableflow on the seeded topics, and print the locations of their Iceberg tables. A Kafka cluster which is created for it is a standard one.")
+	cmd.Flags().Bool("no-shell", false, "Don't start the Flink SQL shell.")
+	output.AddFlag(&cmd, "Format of the resources of the quickstart, which needs --no-shell with json or yaml")
+	cmd.Flags().Bool("debug", false, "Log the confluent CLI commands which the plugin runs, and their output.")
+	cli.AddCacheFlag(&cmd)
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+	cmd.MarkFlagsMutuallyExclusive("environment", "environment-name")
+	cobra.CheckErr(cmd.Flags().MarkDeprecated("debug", "use -vv instead"))
+
+	cmd.AddCommand(newListQuickstartsCommand())
+	cmd.AddCommand(newTeardownCommand())
+
+	completion.Add(&cmd, "confluent-flink-quickstart")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-flink-quickstart"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+// quickstartArgs accepts the Datagen quickstarts as arguments after --datagen-quickstarts, separated by spaces, as the
+// Python version of the plugin did, such as --datagen-quickstarts shoe_orders shoes.
+func quickstartArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && !cmd.Flags().Changed("datagen-quickstarts") {
+		return cobra.NoArgs(cmd, args)
+	}
+	return nil
+}
+
+func start(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	name, err := cmd.Flags().GetString("name")
+	cobra.CheckErr(err)
+
+	maxCFU, err := cmd.Flags().GetInt("max-cfu")
+	cobra.CheckErr(err)
+
+	poolName, err := cmd.Flags().GetString("compute-pool-name")
+	cobra.CheckErr(err)
+
+	environmentName, err := cmd.Flags().GetString("environment-name")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	region, err := cmd.Flags().GetString("region")
+	cobra.CheckErr(err)
+
+	cloud, err := cmd.Flags().GetString("cloud")
+	cobra.CheckErr(err)
+
+	datagenQuickstarts, err := cmd.Flags().GetStringSlice("datagen-quickstarts")
+	cobra.CheckErr(err)
+
+	tablesFile, err := cmd.Flags().GetString("tables")
+	cobra.CheckErr(err)
+
+	sqlFile, err := cmd.Flags().GetString("sql-file")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	noShell, err := cmd.Flags().GetBool("no-shell")
+	cobra.CheckErr(err)
+
+	enableTableflow, err := cmd.Flags().GetBool("enable-tableflow")
+	cobra.CheckErr(err)
+
+	debug, err := cmd.Flags().GetBool("debug")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	// The Flink SQL shell writes to stdout too, so only the result can be printed on it.
+	if format != output.Table && !noShell {
+		return exitcode.Invalid("--output %s requires --no-shell", format)
+	}
+
+	if debug {
+		cobra.CheckErr(cmd.Flags().Set("verbose", "2"))
+		if err := logging.Setup(cmd); err != nil {
+			return err
+		}
+	}
+	if cloud != "aws" {
+		return exitcode.Invalid(`unsupported cloud "%s", supported clouds: aws`, cloud)
+	}
+	if !slices.Contains(regions, region) {
+		return exitcode.Invalid(`unsupported region "%s", supported regions: %s`, region, strings.Join(regions, ", "))
+	}
+	if !slices.Contains(maxCFUs, maxCFU) {
+		return exitcode.Invalid("--max-cfu must be 5, 10, 20, 30, 40, or 50")
+	}
+	datagenQuickstarts = append(datagenQuickstarts, args...)
+	for i, quickstart := range datagenQuickstarts {
+		datagenQuickstarts[i] = strings.ToUpper(quickstart)
+		if !slices.Contains(quickstarts, datagenQuickstarts[i]) {
+			return exitcode.Invalid(`unsupported quickstart "%s", supported quickstarts: %s, see "confluent flink quickstart list-quickstarts"`, quickstart, strings.ToLower(strings.Join(quickstarts, ", ")))
+		}
+	}
+	tables := quickstartTables(datagenQuickstarts)
+	if tablesFile != "" {
+		fromFile, err := readTables(tablesFile)
+		if err != nil {
+			return exitcode.With(exitcode.Validation, err)
+		}
+		tables = append(tables, fromFile...)
+	}
+	names := map[string]bool{}
+	for _, t := range tables {
+		if names[t.Name] {
+			return exitcode.Invalid(`table "%s" is seeded more than once`, t.Name)
+		}
+		names[t.Name] = true
+	}
+	statements := tableStatements(tables)
+	if sqlFile != "" {
+		statements, err = sqlStatements(sqlFile)
+		if err != nil {
+			return exitcode.With(exitcode.Validation, err)
+		}
+	}
+	if enableTableflow && len(tables) == 0 {
+		return exitcode.Invalid("--enable-tableflow needs tables to materialize, pass --datagen-quickstarts or --tables")
+	}
+	if environmentName == "" {
+		environmentName = name + "_environment"
+	}
+	if poolName == "" {
+		poolName = name
+	}
+
+	q := &quickstart{
+		name:          name,
+		cloud:         cloud,
+		region:        region,
+		maxCFU:        maxCFU,
+		poolName:      poolName,
+		withTableflow: enableTableflow,
+		deadline:      time.Now().Add(timeout),
+		environment:   environment,
+		out:           output.Progress(cmd),
+		prompter:      prompt.New(cmd),
+	}
+
+	err = q.provision(environmentName, cluster, tables, statements)
+	if recordErr := q.record(); recordErr != nil {
+		slog.Warn("Failed to record what the quickstart created, which teardown deletes", "error", recordErr)
+	}
+	if err := q.created.Report(cmd.ErrOrStderr(), err); err != nil {
+		return err
+	}
+
+	if format != output.Table {
+		return output.Print(cmd.OutOrStdout(), format, q.result(), nil)
+	}
+	fmt.Fprintf(q.out, "Flink compute pool \"%s\" (%s) is provisioned, with Kafka cluster %s as its database.\n", q.poolName, q.pool.ID, q.cluster)
+	for _, t := range q.tableflow {
+		fmt.Fprintf(q.out, "The Iceberg table of topic \"%s\" is at %s.\n", t.Topic, t.Location)
+	}
+	if noShell {
+		return nil
+	}
+	return q.shell()
+}
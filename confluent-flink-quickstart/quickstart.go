@@ -0,0 +1,368 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+)
+
+// pollInterval is how often the status of the resources being provisioned is checked.
+const pollInterval = 10 * time.Second
+
+// A quickstart is the resources which are created, or reused, for a Flink compute pool.
+type quickstart struct {
+	name     string
+	cloud    string
+	region   string
+	maxCFU   int
+	poolName string
+	// withTableflow is whether Tableflow is enabled on the seeded topics, which it only supports on Standard clusters
+	// and up.
+	withTableflow bool
+	deadline      time.Time
+
+	out      io.Writer
+	prompter *prompt.Prompter
+
+	environment string
+	// cluster is the Kafka cluster which is the database of the Flink SQL shell.
+	cluster string
+	pool    computePool
+	topics  []string
+	// tableflow is the Iceberg tables of the topics with --enable-tableflow.
+	tableflow []tableflowTable
+
+	// created is the resources which the quickstart created, which are reported if it fails or is interrupted.
+	created interrupt.Ledger
+}
+
+// computePool is the part of "confluent flink compute-pool describe" which the quickstart needs.
+type computePool struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+	Status string `json:"status"`
+}
+
+// result is what --output json or yaml prints once the compute pool is provisioned.
+type result struct {
+	Environment string           `json:"environment"`
+	Cluster     string           `json:"cluster"`
+	ComputePool string           `json:"compute_pool"`
+	Topics      []string         `json:"topics"`
+	Tableflow   []tableflowTable `json:"tableflow,omitempty"`
+}
+
+func (q *quickstart) result() result {
+	topics := q.topics
+	if topics == nil {
+		topics = []string{}
+	}
+	return result{Environment: q.environment, Cluster: q.cluster, ComputePool: q.pool.ID, Topics: topics, Tableflow: q.tableflow}
+}
+
+func createKey(resource, description string) (cli.CreatedAPIKey, error) {
+	var key cli.CreatedAPIKey
+	if err := cli.JSON(&key, "api-key", "create", "--resource", resource, "--description", description); err != nil {
+		return cli.CreatedAPIKey{}, err
+	}
+	if key.Key == "" || key.Secret == "" {
+		return cli.CreatedAPIKey{}, fmt.Errorf("confluent api-key create didn't return a key and secret")
+	}
+	return key, nil
+}
+
+// provision creates, or reuses, the environment, Kafka cluster, Flink compute pool and Datagen connectors, waits for
+// them to be ready, runs the seed statements, and enables Tableflow if it's asked for. An environment which was passed
+// by its ID is only used, never created.
+func (q *quickstart) provision(environmentName, cluster string, tables []table, statements []seedStatement) error {
+	if err := q.useEnvironment(environmentName); err != nil {
+		return err
+	}
+	if err := q.useCluster(cluster); err != nil {
+		return err
+	}
+	// Schema Registry is enabled while the cluster is provisioned, and both are waited on before anything is created
+	// with them, rather than for a fixed time, since how long they take varies.
+	if err := q.useSchemaRegistry(); err != nil {
+		return err
+	}
+	if err := q.waitForCluster(); err != nil {
+		return err
+	}
+	if err := q.createPool(); err != nil {
+		return err
+	}
+
+	// The connectors are created while the compute pool is provisioned, so that every resource is spun up as early as
+	// possible.
+	if err := q.seed(tables); err != nil {
+		return err
+	}
+	if err := q.waitForPool(); err != nil {
+		return err
+	}
+	if err := q.runStatements(statements); err != nil {
+		return err
+	}
+	if !q.withTableflow {
+		return nil
+	}
+	return q.enableTableflow()
+}
+
+// useEnvironment makes the environment which was passed with --environment the current one, or else the environment
+// with the name, creating it if it doesn't exist.
+func (q *quickstart) useEnvironment(name string) error {
+	var environments []cli.Environment
+	if err := cli.JSON(&environments, "environment", "list"); err != nil {
+		return err
+	}
+	if q.environment != "" {
+		for _, e := range environments {
+			if e.ID == q.environment {
+				_, err := cli.Run("environment", "use", q.environment)
+				return err
+			}
+		}
+		return exitcode.With(exitcode.NotFound, fmt.Errorf(`environment "%s" doesn't exist`, q.environment))
+	}
+	for _, e := range environments {
+		if e.Name == name {
+			q.environment = e.ID
+		}
+	}
+
+	if q.environment == "" {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := cli.JSON(&created, "environment", "create", name); err != nil {
+			return err
+		}
+		q.environment = created.ID
+		fmt.Fprintf(q.out, "Created environment \"%s\" (%s).\n", name, q.environment)
+		q.created.Add("environment", name, q.environment, "environment", "delete", q.environment, "--force")
+	}
+
+	_, err := cli.Run("environment", "use", q.environment)
+	return err
+}
+
+// useCluster makes a Kafka cluster in the region the current one, as the database of the compute pool. An existing
+// cluster may be passed, or "create" to create one, and otherwise the user picks one of the existing clusters, or a
+// cluster is created if there are none.
+func (q *quickstart) useCluster(choice string) error {
+	var clusters []cli.KafkaCluster
+	if err := cli.JSON(&clusters, "kafka", "cluster", "list"); err != nil {
+		return err
+	}
+	var ids []string
+	var candidates []string
+	for _, c := range clusters {
+		ids = append(ids, c.ID)
+		if strings.EqualFold(c.Cloud, q.cloud) && c.Region == q.region {
+			candidates = append(candidates, c.ID)
+		}
+	}
+
+	// Without a terminal to pick in, the cluster has to be passed.
+	if choice == "" && len(candidates) > 0 && prompt.NonInteractive() {
+		return exitcode.Invalid(`--cluster is required with --non-interactive, pass the ID of one of the Kafka clusters in the region, %s, or "create"`, strings.Join(candidates, ", "))
+	}
+
+	if choice == "" && len(candidates) > 0 {
+		// The clusters are listed with their topics, which are the tables of the database, so that the one to use can
+		// be told apart by what's in it.
+		list := func() ([]prompt.Option, error) {
+			var options []prompt.Option
+			for _, c := range clusters {
+				if !slices.Contains(candidates, c.ID) {
+					continue
+				}
+				var topics []cli.Topic
+				if err := cli.JSON(&topics, "kafka", "topic", "list", "--cluster", c.ID); err != nil {
+					return nil, err
+				}
+				names := make([]string, len(topics))
+				for i, t := range topics {
+					names[i] = t.Name
+				}
+				options = append(options, prompt.Option{ID: c.ID, Name: c.Name, Detail: strings.Join(names, ", ")})
+			}
+			return append(options, prompt.Option{ID: "create", Name: "Create a new Kafka cluster"}), nil
+		}
+		if err := q.prompter.Select(&choice, "cluster", "Kafka cluster to use as the database", list); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case choice == "" || choice == "create":
+		if err := q.createCluster(); err != nil {
+			return err
+		}
+	case !slices.Contains(ids, choice):
+		return exitcode.With(exitcode.NotFound, fmt.Errorf(`Kafka cluster "%s" isn't in the environment`, choice))
+	case !slices.Contains(candidates, choice):
+		// A compute pool only reads and writes the tables of a database in its own region.
+		c := clusters[slices.Index(ids, choice)]
+		return exitcode.Invalid(`Kafka cluster "%s" is in %s %s, pass --cloud %s --region %s to create the compute pool there`, choice, strings.ToLower(c.Cloud), c.Region, strings.ToLower(c.Cloud), c.Region)
+	default:
+		q.cluster = choice
+	}
+
+	_, err := cli.Run("kafka", "cluster", "use", q.cluster)
+	return err
+}
+
+// createCluster creates a basic Kafka cluster, or a standard one for Tableflow, which is waited on by waitForCluster.
+func (q *quickstart) createCluster() error {
+	var created struct {
+		ID string `json:"id"`
+	}
+	args := []string{"kafka", "cluster", "create", q.name + "_kafka-cluster", "--cloud", q.cloud, "--region", q.region}
+	if q.withTableflow {
+		args = append(args, "--type", "standard")
+	}
+	if err := cli.JSON(&created, args...); err != nil {
+		return err
+	}
+	q.cluster = created.ID
+	fmt.Fprintf(q.out, "Created Kafka cluster \"%s\" (%s).\n", q.name+"_kafka-cluster", q.cluster)
+	q.created.Add("Kafka cluster", q.name+"_kafka-cluster", q.cluster, "kafka", "cluster", "delete", q.cluster, "--environment", q.environment, "--force")
+	return nil
+}
+
+// waitForCluster waits for the Kafka cluster to be up, which one which was picked may not be yet either, such as one
+// which an earlier quickstart created.
+func (q *quickstart) waitForCluster() error {
+	var described cli.KafkaCluster
+	if err := cli.JSON(&described, "kafka", "cluster", "describe", q.cluster); err != nil {
+		return err
+	}
+	if described.Status == "UP" {
+		return nil
+	}
+
+	s := progress.Spin(q.out, "Waiting for the Kafka cluster to be up")
+	defer s.Stop()
+	for {
+		s.Status(described.Status)
+		if described.Status == "FAILED" {
+			return fmt.Errorf(`Kafka cluster "%s" failed to be provisioned`, q.cluster)
+		}
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for Kafka cluster "%s" to be up, its status is %s, pass a longer --timeout to wait longer`, q.cluster, described.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+		if err := cli.JSON(&described, "kafka", "cluster", "describe", q.cluster); err != nil {
+			return err
+		}
+	}
+}
+
+// useSchemaRegistry enables Schema Registry in the environment if it isn't yet, since Flink and the Datagen connectors
+// use it for the schemas of the tables, and waits for it to have an endpoint, which it only has a while after it's
+// enabled.
+func (q *quickstart) useSchemaRegistry() error {
+	var described struct {
+		Endpoint string `json:"endpoint_url"`
+	}
+	err := cli.JSON(&described, "schema-registry", "cluster", "describe")
+	if err == nil && described.Endpoint != "" {
+		return nil
+	}
+	if err != nil {
+		if _, err := cli.Run("schema-registry", "cluster", "enable", "--cloud", q.cloud, "--geo", geo(q.region)); err != nil {
+			return err
+		}
+		fmt.Fprintln(q.out, "Enabled Schema Registry.")
+	}
+
+	s := progress.Spin(q.out, "Waiting for Schema Registry to be ready")
+	defer s.Stop()
+	for {
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf("timed out waiting for Schema Registry to be ready, pass a longer --timeout to wait longer")
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+		// Schema Registry isn't found for a while after it's enabled.
+		err := cli.JSON(&described, "schema-registry", "cluster", "describe")
+		if err != nil && exitcode.Of(err) != exitcode.NotFound {
+			return err
+		}
+		if err == nil && described.Endpoint != "" {
+			return nil
+		}
+	}
+}
+
+// geo returns the Schema Registry geography of a region of the quickstart.
+func geo(region string) string {
+	if strings.HasPrefix(region, "eu") {
+		return "eu"
+	}
+	return "us"
+}
+
+// createPool creates the Flink compute pool with its name, or reuses it if it's in the region.
+func (q *quickstart) createPool() error {
+	var pools []computePool
+	if err := cli.JSON(&pools, "flink", "compute-pool", "list"); err != nil {
+		return err
+	}
+	for _, p := range pools {
+		if p.Name == q.poolName && strings.EqualFold(p.Cloud, q.cloud) && p.Region == q.region {
+			q.pool = p
+			fmt.Fprintf(q.out, "Using Flink compute pool \"%s\" (%s).\n", p.Name, p.ID)
+			return nil
+		}
+	}
+
+	if err := cli.JSON(&q.pool, "flink", "compute-pool", "create", q.poolName, "--cloud", q.cloud, "--region", q.region, "--max-cfu", fmt.Sprint(q.maxCFU)); err != nil {
+		return err
+	}
+	fmt.Fprintf(q.out, "Created Flink compute pool \"%s\" (%s).\n", q.poolName, q.pool.ID)
+	q.created.Add("Flink compute pool", q.poolName, q.pool.ID, "flink", "compute-pool", "delete", q.pool.ID, "--environment", q.environment, "--force")
+	return nil
+}
+
+// waitForPool waits for the compute pool to be provisioned.
+func (q *quickstart) waitForPool() error {
+	if q.pool.Status == "PROVISIONED" {
+		return nil
+	}
+
+	s := progress.Spin(q.out, "Waiting for the Flink compute pool to be provisioned")
+	defer s.Stop()
+	for q.pool.Status != "PROVISIONED" {
+		s.Status(q.pool.Status)
+		if q.pool.Status == "FAILED" {
+			return fmt.Errorf(`Flink compute pool "%s" failed to be provisioned`, q.pool.ID)
+		}
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for Flink compute pool "%s" to be provisioned, its status is %s, pass a longer --timeout to wait longer`, q.pool.ID, q.pool.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+		if err := cli.JSON(&q.pool, "flink", "compute-pool", "describe", q.pool.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// A template is a quickstart of the fully-managed Datagen source connector, which --datagen-quickstarts seeds a table
+// with.
+type template struct {
+	Name        string
+	Description string
+	// KeyField is the field whose value is the key of the records.
+	KeyField string
+	// Fields are the top-level fields of the records, in the order of the schema.
+	Fields []string
+}
+
+// templates are the quickstarts of the fully-managed Datagen source connector.
+var templates = []template{
+	{"CAMPAIGN_FINANCE", "Contributions to election campaigns", "candidate_id", []string{"candidate_id", "party_affiliation", "contribution"}},
+	{"CLICKSTREAM", "Web server log events", "ip", []string{"ip", "userid", "remote_user", "time", "_time", "request", "status", "bytes", "referrer", "agent"}},
+	{"CLICKSTREAM_CODES", "HTTP status codes of the clickstream", "code", []string{"code", "definition"}},
+	{"CLICKSTREAM_USERS", "Users of the clickstream", "user_id", []string{"user_id", "username", "registered_at", "first_name", "last_name", "city", "level"}},
+	{"CREDIT_CARDS", "Credit cards", "card_id", []string{"card_id", "card_number", "cvv", "expiration_date"}},
+	{"DEVICE_INFORMATION", "Network devices", "device_ip", []string{"device_ip", "mac_address", "timestamp", "device_type", "os_version"}},
+	{"FLEET_MGMT_DESCRIPTION", "Vehicles of a fleet and their drivers", "vehicle_id", []string{"vehicle_id", "driver_name", "license_plate"}},
+	{"FLEET_MGMT_LOCATION", "Locations of the vehicles of a fleet", "vehicle_id", []string{"vehicle_id", "location", "ts"}},
+	{"FLEET_MGMT_SENSORS", "Engine sensors of the vehicles of a fleet", "vehicle_id", []string{"vehicle_id", "engine_temperature", "average_rpm"}},
+	{"GAMING_GAMES", "Games of an online game platform", "id", []string{"id", "name", "type"}},
+	{"GAMING_PLAYER_ACTIVITY", "Points scored by players in game rooms", "player_id", []string{"player_id", "game_room_id", "points", "coordinates"}},
+	{"GAMING_PLAYERS", "Players of an online game platform", "player_id", []string{"player_id", "player_name", "ip"}},
+	{"INSURANCE_CUSTOMER_ACTIVITY", "Interactions of insurance customers with their agents", "activity_id", []string{"activity_id", "customer_id", "activity_type", "propensity_to_churn", "ip_address"}},
+	{"INSURANCE_CUSTOMERS", "Customers of an insurer", "customer_id", []string{"customer_id", "first_name", "last_name", "email", "gender", "income", "fico"}},
+	{"INSURANCE_OFFERS", "Insurance offers made to customers", "offer_id", []string{"offer_id", "customer_id", "insurance_type", "premium", "offered_at"}},
+	{"INVENTORY", "Stock levels of products", "id", []string{"id", "quantity", "productid"}},
+	{"ORDERS", "Orders of items, with their shipping address", "orderid", []string{"ordertime", "orderid", "itemid", "orderunits", "address"}},
+	{"PAGEVIEWS", "Pages viewed by users", "viewtime", []string{"viewtime", "userid", "pageid"}},
+	{"PAYROLL_BONUS", "Bonuses paid to employees", "employee_id", []string{"employee_id", "bonus"}},
+	{"PAYROLL_EMPLOYEE", "Employees, with their pay", "employee_id", []string{"employee_id", "first_name", "last_name", "age", "ssn", "hourly_rate", "gender", "email"}},
+	{"PAYROLL_EMPLOYEE_LOCATION", "Labs and departments of employees", "employee_id", []string{"employee_id", "lab", "department_id", "arrival_date"}},
+	{"PIZZA_ORDERS", "Orders of pizza stores, with their order lines", "store_id", []string{"store_id", "store_order_id", "coupon_code", "date", "status", "order_lines"}},
+	{"PIZZA_ORDERS_CANCELLED", "Pizza orders which were cancelled", "store_id", []string{"store_id", "store_order_id", "date", "status"}},
+	{"PIZZA_ORDERS_COMPLETED", "Pizza orders which were delivered", "store_id", []string{"store_id", "store_order_id", "date", "status", "rack_time_secs", "order_delivery_time_secs"}},
+	{"PRODUCT", "Products, with their prices", "id", []string{"id", "name", "description", "price"}},
+	{"PURCHASES", "Purchases of products by customers", "id", []string{"id", "product_id", "customer_id", "quantity", "purchased_at"}},
+	{"RATINGS", "Ratings of routes by users", "rating_id", []string{"rating_id", "user_id", "stars", "route_id", "rating_time", "channel", "message"}},
+	{"SHOE_CLICKSTREAM", "Views of the pages of a shoe store", "product_id", []string{"product_id", "user_id", "view_time", "page_url", "ip", "ts"}},
+	{"SHOE_CUSTOMERS", "Customers of a shoe store", "id", []string{"id", "first_name", "last_name", "email", "phone", "street_address", "state", "zip_code", "country", "country_code"}},
+	{"SHOE_ORDERS", "Orders of a shoe store", "order_id", []string{"order_id", "product_id", "customer_id", "ts"}},
+	{"SHOES", "Shoes of a shoe store, with their prices", "id", []string{"id", "brand", "name", "sale_price", "rating"}},
+	{"SIEM_LOGS", "Security events of network hosts", "hostname", []string{"hostname", "timestamp", "severity", "event_type", "source_ip", "destination_ip"}},
+	{"STOCK_TRADES", "Trades of stocks", "symbol", []string{"side", "quantity", "symbol", "price", "account", "userid"}},
+	{"STORES", "Stores and their locations", "store_id", []string{"store_id", "city", "state"}},
+	{"SYSLOG_LOGS", "Syslog messages of network hosts", "host", []string{"ts", "host", "tag", "message"}},
+	{"TRANSACTIONS", "Card transactions of purchases in stores", "transaction_id", []string{"transaction_id", "card_id", "user_id", "purchase_id", "store_id"}},
+	{"USERS", "Users and their regions", "userid", []string{"registertime", "userid", "regionid", "gender"}},
+	{"USERS_ARRAY", "Users and their regions, with arrays and maps of their interests and contact details", "userid", []string{"registertime", "userid", "regionid", "gender", "interests", "contactinfo"}},
+}
+
+// quickstarts are the names of the templates, which --datagen-quickstarts and the tables of --tables take.
+var quickstarts = templateNames()
+
+func templateNames() []string {
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// listedQuickstart is a template as it's listed, with the topic and the schema subject of the table which
+// --datagen-quickstarts seeds with it.
+type listedQuickstart struct {
+	Quickstart    string   `json:"quickstart"`
+	Description   string   `json:"description"`
+	Topic         string   `json:"topic"`
+	SchemaSubject string   `json:"schema_subject"`
+	KeyField      string   `json:"key_field"`
+	Fields        []string `json:"fields"`
+}
+
+func newListQuickstartsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-quickstarts",
+		Short: "List the Datagen quickstarts which tables can be seeded with.",
+		Long:  "List the quickstarts of the Datagen connector which --datagen-quickstarts and the tables of --tables take, with the topic and schema subject of the table which each of them seeds, the field of its keys, and the fields of its records.",
+		Args:  cobra.NoArgs,
+		RunE:  listQuickstarts,
+		Example: `confluent flink quickstart list-quickstarts
+confluent flink quickstart list-quickstarts --output json`,
+	}
+
+	output.AddFlag(cmd, "Format of the output")
+
+	return cmd
+}
+
+func listQuickstarts(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	listed := make([]listedQuickstart, len(templates))
+	for i, t := range templates {
+		table := quickstartTables([]string{t.Name})[0]
+		listed[i] = listedQuickstart{
+			Quickstart:    strings.ToLower(t.Name),
+			Description:   t.Description,
+			Topic:         table.Name,
+			SchemaSubject: table.Name + "-value",
+			KeyField:      t.KeyField,
+			Fields:        t.Fields,
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	return output.Print(out, format, listed, func() {
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Quickstart\tTopic\tSchema Subject\tKey\tFields")
+		for _, l := range listed {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", l.Quickstart, l.Topic, l.SchemaSubject, l.KeyField, strings.Join(l.Fields, ", "))
+		}
+		_ = tw.Flush()
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+)
+
+// A runManifest is the manifest of what the quickstarts with a name created, which is added to by each of them, so that
+// "confluent flink quickstart teardown" deletes it all later.
+type runManifest struct {
+	path string
+
+	Name        string `json:"name"`
+	Environment string `json:"environment"`
+	Cloud       string `json:"cloud"`
+	Region      string `json:"region"`
+	// Resources are what the quickstarts created, in order, with the confluent CLI arguments which delete them.
+	Resources []resource `json:"resources"`
+}
+
+type resource struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name,omitempty"`
+	ID     string   `json:"id,omitempty"`
+	Delete []string `json:"delete"`
+}
+
+func (r resource) String() string {
+	return interrupt.Resource{Kind: r.Kind, Name: r.Name, ID: r.ID}.String()
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// manifestFile returns the file of the manifest of the runs with the name, in ~/.confluent/flink-quickstart.
+func manifestFile(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".confluent", "flink-quickstart", unsafeName.ReplaceAllString(name, "_")+".json"), nil
+}
+
+// readManifest reads the manifest of the runs with the name, which has no resources if none were recorded.
+func readManifest(name string) (*runManifest, error) {
+	path, err := manifestFile(name)
+	if err != nil {
+		return nil, err
+	}
+	r := &runManifest{path: path, Name: name}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	r.path = path
+	return r, nil
+}
+
+// write writes the manifest, or removes its file once it has no resources left.
+func (r *runManifest) write() error {
+	if len(r.Resources) == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The file is written whole and then renamed, so that a quickstart which is killed doesn't leave half of it.
+	if err := os.WriteFile(r.path+".new", b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(r.path+".new", r.path)
+}
+
+// record adds what the quickstart created to the manifest of the runs with its name. It's recorded even if the
+// quickstart failed or was interrupted, so that what it created before then is torn down too.
+func (q *quickstart) record() error {
+	created := q.created.Resources()
+	if len(created) == 0 {
+		return nil
+	}
+	r, err := readManifest(q.name)
+	if err != nil {
+		return err
+	}
+	r.Environment, r.Cloud, r.Region = q.environment, q.cloud, q.region
+	for _, c := range created {
+		r.Resources = append(r.Resources, resource{Kind: c.Kind, Name: c.Name, ID: c.ID, Delete: c.Delete})
+	}
+	return r.write()
+}
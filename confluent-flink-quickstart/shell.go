@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+)
+
+// shell starts the Flink SQL shell of the confluent CLI, on the compute pool, with the Kafka cluster as its database.
+func (q *quickstart) shell() error {
+	fmt.Fprintln(q.out, "Starting the Flink SQL shell.")
+	command := exec.Command("confluent", "flink", "shell", "--compute-pool", q.pool.ID, "--database", q.cluster)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return interrupt.Run(command)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// A seedStatement is a SQL statement which is run on the compute pool before the Flink SQL shell starts.
+type seedStatement struct {
+	sql string
+	// complete is whether the statement is waited on until it completes, rather than until it runs, such as a query of a
+	// table which only completes once the table has a record.
+	complete bool
+}
+
+// sqlStatements reads the statements of a file, which are separated by semicolons. Semicolons in quotes and comments
+// don't separate statements.
+func sqlStatements(path string) ([]seedStatement, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the SQL statements: %w", err)
+	}
+
+	var statements []seedStatement
+	var quote rune
+	var comment string
+	var current strings.Builder
+	add := func() {
+		if sql := strings.TrimSpace(current.String()); sql != "" {
+			statements = append(statements, seedStatement{sql: sql})
+		}
+		current.Reset()
+	}
+	runes := []rune(string(b))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case comment == "--":
+			if r == '\n' {
+				comment = ""
+				current.WriteRune(r)
+			}
+			continue
+		case comment == "/*":
+			if r == '*' && next == '/' {
+				comment = ""
+				i++
+			}
+			continue
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && next == '-', r == '/' && next == '*':
+			comment = string([]rune{r, next})
+			i++
+			continue
+		case r == ';':
+			add()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf(`"%s" has a quote which isn't closed`, path)
+	}
+	add()
+	if len(statements) == 0 {
+		return nil, fmt.Errorf(`"%s" has no SQL statements`, path)
+	}
+	return statements, nil
+}
+
+// tableStatements returns a query of each table which completes once the table has a record, so that every table can be
+// queried once the Flink SQL shell starts.
+func tableStatements(tables []table) []seedStatement {
+	statements := make([]seedStatement, len(tables))
+	for i, t := range tables {
+		statements[i] = seedStatement{sql: fmt.Sprintf("SELECT * FROM `%s` LIMIT 1", t.Name), complete: true}
+	}
+	return statements
+}
+
+var unsafeStatementName = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// runStatements runs the seed statements on the compute pool, in order, each once the one before it runs.
+func (q *quickstart) runStatements(statements []seedStatement) error {
+	if len(statements) == 0 {
+		return nil
+	}
+
+	// Statement names are unique in the environment, so they have the time of the run in them.
+	prefix := strings.Trim(unsafeStatementName.ReplaceAllString(strings.ToLower(q.poolName), "-"), "-")
+	if len(prefix) > 50 {
+		prefix = prefix[:50]
+	}
+	prefix = fmt.Sprintf("%s-seed-%s", prefix, time.Now().Format("20060102-150405"))
+
+	s := progress.Spin(q.out, "Running the seed statements")
+	defer s.Stop()
+	for i, statement := range statements {
+		s.Status(fmt.Sprintf("%d of %d", i+1, len(statements)))
+		name := fmt.Sprintf("%s-%d", prefix, i+1)
+		var created flinkStatement
+		if err := cli.JSON(&created, "flink", "statement", "create", name, "--sql", statement.sql, "--compute-pool", q.pool.ID, "--database", q.cluster, "--environment", q.environment, "--wait"); err != nil {
+			return fmt.Errorf(`failed to run "%s": %w`, statement.sql, err)
+		}
+		q.created.Add("Flink statement", name, "", "flink", "statement", "delete", name, "--environment", q.environment, "--cloud", q.cloud, "--region", q.region, "--force")
+		if err := q.waitForStatement(name, statement, created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flinkStatement is the part of "confluent flink statement describe" which the quickstart needs.
+type flinkStatement struct {
+	Status       string `json:"status"`
+	StatusDetail string `json:"status_detail"`
+}
+
+// waitForStatement waits for a statement to run, or to complete if it has to.
+func (q *quickstart) waitForStatement(name string, statement seedStatement, described flinkStatement) error {
+	for {
+		switch described.Status {
+		case "FAILED", "STOPPED":
+			return fmt.Errorf(`statement "%s" is %s: %s`, statement.sql, strings.ToLower(described.Status), described.StatusDetail)
+		case "COMPLETED":
+			return nil
+		case "RUNNING":
+			if !statement.complete {
+				return nil
+			}
+		}
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for statement "%s", its status is %s`, statement.sql, described.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+		if err := cli.JSON(&described, "flink", "statement", "describe", name, "--environment", q.environment, "--cloud", q.cloud, "--region", q.region); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// A tableflowTable is the Iceberg table which Tableflow materializes a topic as.
+type tableflowTable struct {
+	Topic    string `json:"topic"`
+	Location string `json:"location"`
+}
+
+// tableflowTopic is the part of "confluent tableflow topic describe" which the quickstart needs.
+type tableflowTopic struct {
+	Phase     string `json:"phase"`
+	TablePath string `json:"table_path"`
+}
+
+// enableTableflow enables Tableflow on the topics which the Datagen connectors seed, as Iceberg tables in storage which
+// Confluent manages, and waits for every table to have a location. Topics which Tableflow is already enabled on are
+// only waited on.
+func (q *quickstart) enableTableflow() error {
+	for _, topic := range q.topics {
+		args := []string{"--cluster", q.cluster, "--environment", q.environment}
+		var described tableflowTopic
+		err := cli.JSON(&described, append([]string{"tableflow", "topic", "describe", topic}, args...)...)
+		if err != nil && exitcode.Of(err) != exitcode.NotFound {
+			return err
+		}
+		if err != nil {
+			if _, err := cli.Run(append([]string{"tableflow", "topic", "enable", topic, "--storage-type", "MANAGED", "--table-formats", "ICEBERG"}, args...)...); err != nil {
+				return fmt.Errorf(`failed to enable Tableflow on topic "%s": %w`, topic, err)
+			}
+			fmt.Fprintf(q.out, "Enabled Tableflow on topic \"%s\".\n", topic)
+			q.created.Add("Tableflow topic", topic, "", append([]string{"tableflow", "topic", "disable", topic}, append(args, "--force")...)...)
+		}
+	}
+
+	s := progress.Spin(q.out, "Waiting for the Iceberg tables of Tableflow")
+	defer s.Stop()
+	for i, topic := range q.topics {
+		s.Status(fmt.Sprintf("%d of %d ready", i, len(q.topics)))
+		location, err := q.waitForTableflow(topic)
+		if err != nil {
+			return err
+		}
+		q.tableflow = append(q.tableflow, tableflowTable{Topic: topic, Location: location})
+	}
+	return nil
+}
+
+// waitForTableflow waits for the Iceberg table of the topic to be materialized, and returns its location.
+func (q *quickstart) waitForTableflow(topic string) (string, error) {
+	for {
+		var described tableflowTopic
+		if err := cli.JSON(&described, "tableflow", "topic", "describe", topic, "--cluster", q.cluster, "--environment", q.environment); err != nil {
+			return "", err
+		}
+		switch {
+		case described.Phase == "FAILED":
+			return "", fmt.Errorf(`Tableflow failed to materialize topic "%s"`, topic)
+		case described.Phase == "RUNNING" && described.TablePath != "":
+			return described.TablePath, nil
+		}
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return "", fmt.Errorf(`timed out waiting for the Iceberg table of topic "%s", its phase is %s, pass a longer --timeout to wait longer`, topic, described.Phase)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return "", err
+		}
+	}
+}
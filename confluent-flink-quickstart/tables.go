@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A table is a topic which a Datagen connector seeds, which Flink reads as a table, from --datagen-quickstarts or a
+// file of --tables.
+type table struct {
+	// Name is the name of the topic, and of the table.
+	Name string `yaml:"name"`
+	// Quickstart is the template of the Datagen connector, such as SHOE_ORDERS, unless it has a schema of its own.
+	Quickstart string `yaml:"quickstart"`
+	// Schema is the file of an Avro schema which the Datagen connector generates records of, rather than a quickstart,
+	// relative to the file of the tables. Its fields can have Datagen's arg.properties, such as the options of a field.
+	Schema string `yaml:"schema"`
+	// KeyField is the field of the schema whose value is the key of the records.
+	KeyField string `yaml:"key_field"`
+	// Format is the format of the records: AVRO, JSON_SR, PROTOBUF, or JSON. Defaults to AVRO.
+	Format string `yaml:"format"`
+	// Partitions is the number of partitions of a new topic, which defaults to that of the cluster.
+	Partitions int `yaml:"partitions"`
+	// Interval is the longest time between records, in milliseconds, which defaults to that of the connector.
+	Interval int `yaml:"interval_ms"`
+
+	// schema is the contents of the file of Schema.
+	schema string
+}
+
+// formats are the formats which a Datagen connector writes records in.
+var formats = []string{"AVRO", "JSON_SR", "PROTOBUF", "JSON"}
+
+// quickstartTables returns a table for each quickstart, named after it.
+func quickstartTables(names []string) []table {
+	tables := make([]table, len(names))
+	for i, name := range names {
+		tables[i] = table{Name: strings.ToLower(name), Quickstart: name, Format: "AVRO"}
+	}
+	return tables
+}
+
+// readTables reads the tables of a file, such as one which every demo of a team starts from:
+//
+//	tables:
+//	  - name: shoe_orders
+//	    quickstart: shoe_orders
+//	  - name: payments
+//	    schema: schemas/payments.avsc
+//	    key_field: payment_id
+//	    partitions: 6
+func readTables(path string) ([]table, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the tables: %w", err)
+	}
+
+	var file struct {
+		Tables []table `yaml:"tables"`
+	}
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf(`failed to parse the tables "%s": %w`, path, err)
+	}
+	if len(file.Tables) == 0 {
+		return nil, fmt.Errorf(`"%s" has no tables`, path)
+	}
+
+	for i, t := range file.Tables {
+		if t.Name == "" {
+			return nil, fmt.Errorf("table %d needs a name", i+1)
+		}
+		if (t.Quickstart == "") == (t.Schema == "") {
+			return nil, fmt.Errorf(`table "%s" needs either a quickstart or a schema`, t.Name)
+		}
+		if t.Quickstart != "" {
+			file.Tables[i].Quickstart = strings.ToUpper(t.Quickstart)
+			if !slices.Contains(quickstarts, file.Tables[i].Quickstart) {
+				return nil, fmt.Errorf(`unsupported quickstart "%s" of table "%s", supported quickstarts: %s`, t.Quickstart, t.Name, strings.ToLower(strings.Join(quickstarts, ", ")))
+			}
+		}
+		if t.Schema != "" {
+			if !filepath.IsAbs(t.Schema) {
+				file.Tables[i].Schema = filepath.Join(filepath.Dir(path), t.Schema)
+			}
+			schema, err := os.ReadFile(file.Tables[i].Schema)
+			if err != nil {
+				return nil, fmt.Errorf(`failed to read the schema of table "%s": %w`, t.Name, err)
+			}
+			file.Tables[i].schema = string(schema)
+		}
+		if t.KeyField != "" && t.Schema == "" {
+			return nil, fmt.Errorf(`table "%s" has a key_field but no schema, the keys of a quickstart are its own`, t.Name)
+		}
+		file.Tables[i].Format = strings.ToUpper(t.Format)
+		if file.Tables[i].Format == "" {
+			file.Tables[i].Format = "AVRO"
+		}
+		if !slices.Contains(formats, file.Tables[i].Format) {
+			return nil, fmt.Errorf(`unsupported format "%s" of table "%s", supported formats: %s`, t.Format, t.Name, strings.Join(formats, ", "))
+		}
+		if t.Partitions < 0 || t.Interval < 0 {
+			return nil, fmt.Errorf(`the partitions and interval_ms of table "%s" can't be negative`, t.Name)
+		}
+	}
+	return file.Tables, nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newTeardownCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teardown",
+		Short: "Delete what the quickstarts with a name created.",
+		Long:  "Stop the running statements of the Flink compute pools which the quickstarts with the name created, and delete the pools, the seed statements, the Tableflow of the topics, the Datagen connectors, their API key, and their topics, after prompting for confirmation. The Kafka cluster and the environment which they created are only deleted with --delete-cluster and --delete-environment. What was created is read from the manifest of the runs, in ~/.confluent/flink-quickstart.",
+		Args:  cobra.NoArgs,
+		RunE:  teardown,
+		Example: `confluent flink quickstart teardown --name orders-pool --dry-run
+confluent flink quickstart teardown --name orders-pool --delete-environment --yes`,
+	}
+
+	cmd.Flags().String("name", "", "Name of the quickstarts to tear down, which is the --name they were run with.")
+	cmd.Flags().Bool("delete-cluster", false, "Also delete the Kafka cluster, if the quickstart created it.")
+	cmd.Flags().Bool("delete-environment", false, "Also delete the environment and the Kafka cluster, if the quickstart created them.")
+	dryrun.AddFlag(cmd, "Print what would be stopped and deleted without changing anything.")
+	output.AddFlag(cmd, "Format of what was stopped and deleted")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	return cmd
+}
+
+func teardown(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	name, err := cmd.Flags().GetString("name")
+	cobra.CheckErr(err)
+
+	deleteCluster, err := cmd.Flags().GetBool("delete-cluster")
+	cobra.CheckErr(err)
+
+	deleteEnvironment, err := cmd.Flags().GetBool("delete-environment")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	r, err := readManifest(name)
+	if err != nil {
+		return err
+	}
+	if len(r.Resources) == 0 {
+		return exitcode.With(exitcode.NotFound, fmt.Errorf(`no quickstart named "%s" is recorded in %s`, name, r.path))
+	}
+
+	// The resources are deleted last first, since later ones depend on earlier ones, such as the connectors on the
+	// API key.
+	var deleting, keeping []resource
+	for i := len(r.Resources) - 1; i >= 0; i-- {
+		res := r.Resources[i]
+		switch {
+		case res.Kind == "environment" && !deleteEnvironment,
+			res.Kind == "Kafka cluster" && !deleteCluster && !deleteEnvironment:
+			keeping = append(keeping, res)
+		default:
+			deleting = append(deleting, res)
+		}
+	}
+
+	// A compute pool is only deleted once its statements are stopped, since they'd fail with it. The seed statements
+	// which the quickstarts ran are deleted before it, rather than stopped.
+	deleted := map[string]bool{}
+	for _, res := range deleting {
+		if res.Kind == "Flink statement" {
+			deleted[res.Name] = true
+		}
+	}
+	statements := map[string][]string{}
+	for _, res := range deleting {
+		if res.Kind != "Flink compute pool" {
+			continue
+		}
+		running, err := r.running(res.ID)
+		if err != nil {
+			return err
+		}
+		statements[res.ID] = slices.DeleteFunc(running, func(s string) bool { return deleted[s] })
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, res := range deleting {
+			addDeleted(&plan, res, statements[res.ID])
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := output.Progress(cmd)
+	fmt.Fprintf(out, "The quickstart \"%s\" created:\n", name)
+	stopping := 0
+	for _, res := range deleting {
+		fmt.Fprintf(out, "  %s\n", res)
+		for _, s := range statements[res.ID] {
+			fmt.Fprintf(out, "    running statement %s\n", s)
+		}
+		stopping += len(statements[res.ID])
+	}
+	if len(keeping) > 0 {
+		fmt.Fprintln(out, "It created these too, which are kept:")
+		for _, res := range keeping {
+			fmt.Fprintf(out, "  %s\n", res)
+		}
+	}
+
+	summary := fmt.Sprintf("delete %d resources", len(deleting))
+	if stopping > 0 {
+		summary = fmt.Sprintf("stop %d statements and %s", stopping, summary)
+	}
+	if len(deleting) == 0 {
+		fmt.Fprintln(out, "Nothing to delete without --delete-cluster or --delete-environment.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+	ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to %s?", summary))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Not tearing anything down.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	// Every resource is attempted. The ones which failed to be deleted stay in the manifest, along with the ones which
+	// are kept, so that tearing down again retries them.
+	var errs []error
+	var tornDown dryrun.Plan
+	remaining := keeping
+	for i, res := range deleting {
+		if err := interrupt.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("the teardown was interrupted, re-run it to delete the rest: %w", err))
+			remaining = append(remaining, deleting[i:]...)
+			break
+		}
+		if err := r.remove(res, statements[res.ID]); err != nil {
+			errs = append(errs, err)
+			remaining = append(remaining, res)
+			continue
+		}
+		fmt.Fprintf(out, "Deleted %s.\n", res)
+		addDeleted(&tornDown, res, statements[res.ID])
+	}
+
+	// The manifest keeps the resources in the order in which they were created.
+	slices.Reverse(remaining)
+	r.Resources = remaining
+	if err := r.write(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(append(errs, dryrun.PrintApplied(cmd, tornDown))...)
+}
+
+// addDeleted adds the statements of a resource which are stopped, and the resource, to the plan.
+func addDeleted(plan *dryrun.Plan, res resource, statements []string) {
+	for _, s := range statements {
+		plan.AddDetail("stop", "statement", s, "in compute pool "+res.ID)
+	}
+	switch {
+	case res.Name == "":
+		plan.Add("delete", res.Kind, res.ID)
+	case res.ID == "":
+		plan.Add("delete", res.Kind, res.Name)
+	default:
+		plan.AddDetail("delete", res.Kind, res.Name, "("+res.ID+")")
+	}
+}
+
+// running returns the names of the statements of the compute pool which are still running.
+func (r *runManifest) running(pool string) ([]string, error) {
+	var statements []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	err := cli.JSON(&statements, "flink", "statement", "list", "--compute-pool", pool, "--environment", r.Environment)
+	if exitcode.Of(err) == exitcode.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var running []string
+	for _, s := range statements {
+		if s.Status == "PENDING" || s.Status == "RUNNING" {
+			running = append(running, s.Name)
+		}
+	}
+	return running, nil
+}
+
+// remove stops the statements of a compute pool, and deletes the resource. A resource which was already deleted, such
+// as by hand, is skipped.
+func (r *runManifest) remove(res resource, statements []string) error {
+	for _, s := range statements {
+		if _, err := cli.Run("flink", "statement", "stop", s, "--environment", r.Environment, "--cloud", r.Cloud, "--region", r.Region); err != nil {
+			return fmt.Errorf("not deleting %s, since its statement %s failed to stop: %w", res, s, err)
+		}
+	}
+	_, err := cli.Run(res.Delete...)
+	if exitcode.Of(err) == exitcode.NotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/cli-plugins/internal/clitest"
+)
+
+func TestMain(m *testing.M) {
+	clitest.Main(m)
+}
+
+// writeTestManifest records the resources of a quickstart named orders-pool in a new home directory, in the order in
+// which the quickstart creates them.
+func writeTestManifest(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	// os.UserHomeDir reads $USERPROFILE on Windows.
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	r, err := readManifest("orders-pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Environment, r.Cloud, r.Region = "env-123456", "aws", "us-east-1"
+	r.Resources = []resource{
+		{Kind: "Kafka cluster", Name: "orders-pool_kafka-cluster", ID: "lkc-123456", Delete: []string{"kafka", "cluster", "delete", "lkc-123456", "--environment", "env-123456", "--force"}},
+		{Kind: "Flink compute pool", Name: "orders-pool", ID: "lfcp-123456", Delete: []string{"flink", "compute-pool", "delete", "lfcp-123456", "--environment", "env-123456", "--force"}},
+		{Kind: "topic", Name: "orders", Delete: []string{"kafka", "topic", "delete", "orders", "--cluster", "lkc-123456", "--environment", "env-123456", "--force"}},
+		{Kind: "Flink statement", Name: "seed-orders", Delete: []string{"flink", "statement", "delete", "seed-orders", "--environment", "env-123456", "--cloud", "aws", "--region", "us-east-1", "--force"}},
+	}
+	if err := r.write(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// onRunning scripts the statements of the compute pool: the seed statement, which is deleted rather than stopped, a
+// statement which was run by hand, which has to be stopped before the pool is deleted, and one which already completed.
+func onRunning(cli *clitest.Fake) {
+	cli.On("flink", "statement", "list", "--compute-pool", "lfcp-123456").Return([]map[string]string{
+		{"name": "seed-orders", "status": "RUNNING"},
+		{"name": "orders-by-region", "status": "RUNNING"},
+		{"name": "orders-count", "status": "COMPLETED"},
+	})
+}
+
+func TestTeardownOrder(t *testing.T) {
+	writeTestManifest(t)
+	cli := clitest.New(t)
+	onRunning(cli)
+	cli.On("flink", "statement", "delete")
+	cli.On("flink", "statement", "stop")
+	cli.On("flink", "compute-pool", "delete")
+	cli.On("kafka", "topic", "delete")
+
+	cmd := newTeardownCommand()
+	cmd.SetIn(strings.NewReader("y\n"))
+	if _, _, err := clitest.Execute(cmd, "--name", "orders-pool"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The resources are deleted last first, and the statement which still runs in the pool is stopped right before
+	// the pool is deleted. The cluster is kept without --delete-cluster.
+	var ran []string
+	for _, c := range cli.Calls() {
+		ran = append(ran, strings.Join(c.Args[:4], " "))
+	}
+	want := []string{
+		"flink statement list --compute-pool",
+		"flink statement delete seed-orders",
+		"kafka topic delete orders",
+		"flink statement stop orders-by-region",
+		"flink compute-pool delete lfcp-123456",
+	}
+	if !slices.Equal(ran, want) {
+		t.Errorf("ran:\n  %s\nwant:\n  %s", strings.Join(ran, "\n  "), strings.Join(want, "\n  "))
+	}
+
+	r, err := readManifest("orders-pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Resources) != 1 || r.Resources[0].ID != "lkc-123456" {
+		t.Errorf("the manifest has %v, want only the Kafka cluster which is kept", r.Resources)
+	}
+}
+
+func TestTeardownKeepsFailed(t *testing.T) {
+	writeTestManifest(t)
+	cli := clitest.New(t)
+	onRunning(cli)
+	cli.On("flink", "statement", "delete")
+	cli.On("flink", "statement", "stop").Fail(`Error: statement "orders-by-region" can't be stopped`)
+	cli.On("kafka", "topic", "delete")
+	cli.On("kafka", "cluster", "delete")
+
+	cmd := newTeardownCommand()
+	cmd.SetIn(strings.NewReader("y\n"))
+	stdout, _, err := clitest.Execute(cmd, "--name", "orders-pool", "--delete-cluster", "--output", "json")
+	if err == nil {
+		t.Fatal("the teardown succeeded, although a statement failed to stop")
+	}
+
+	// A pool whose statement failed to stop isn't deleted, but the cluster after it still is, and the pool stays in
+	// the manifest to retry.
+	if cli.Called("flink", "compute-pool", "delete") != 0 {
+		t.Error("the compute pool was deleted, although its statement is still running")
+	}
+	if cli.Called("kafka", "cluster", "delete", "lkc-123456") != 1 {
+		t.Error("the Kafka cluster wasn't deleted")
+	}
+	r, err := readManifest("orders-pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Resources) != 1 || r.Resources[0].ID != "lfcp-123456" {
+		t.Errorf("the manifest has %v, want only the compute pool which failed", r.Resources)
+	}
+
+	// --output json prints what was torn down, despite the failure.
+	var printed struct {
+		Actions []struct {
+			Action   string `json:"action"`
+			Resource string `json:"resource"`
+			Name     string `json:"name"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &printed); err != nil {
+		t.Fatalf("stdout isn't JSON: %v\n%s", err, stdout)
+	}
+	var deleted []string
+	for _, a := range printed.Actions {
+		deleted = append(deleted, a.Action+" "+a.Resource+" "+a.Name)
+	}
+	want := []string{"delete Flink statement seed-orders", "delete topic orders", "delete Kafka cluster orders-pool_kafka-cluster"}
+	if !slices.Equal(deleted, want) {
+		t.Errorf("printed %v, want %v", deleted, want)
+	}
+}
+
+func TestTeardownDryRun(t *testing.T) {
+	writeTestManifest(t)
+	cli := clitest.New(t)
+	onRunning(cli)
+
+	stdout, _, err := clitest.Execute(newTeardownCommand(), "--name", "orders-pool", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cli.Calls()); n != 1 {
+		t.Errorf("ran %d commands, want only the list of statements", n)
+	}
+	if !strings.Contains(stdout, "orders-by-region") {
+		t.Errorf("the plan doesn't stop the running statement:\n%s", stdout)
+	}
+	path, err := manifestFile("orders-pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the manifest is gone after a dry run: %v", err)
+	}
+}
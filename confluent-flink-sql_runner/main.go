@@ -0,0 +1,150 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "sql-runner <file or directory>...",
+		Short: "Run SQL files as Flink statements.",
+		Long:  "Run the statements of SQL files as Flink statements on a compute pool, in order, waiting for each to be running or to complete before the next. Directories are searched for .sql files, which are run in the order of their names. ${name} variables are replaced with the values passed with --var, and SET statements set properties of the statements which follow them. The run stops at the first statement which fails, with its exceptions.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runFiles,
+		Example: `confluent flink sql-runner sql/ --compute-pool lfcp-123456 --database orders-cluster --var env=prod
+confluent flink sql-runner 01-tables.sql 02-jobs.sql --compute-pool lfcp-123456 --service-account sa-123456 --dry-run`,
+	}
+
+	cmd.Flags().String("compute-pool", "", "Flink compute pool ID. Defaults to picking one in a terminal.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("database", "", "Kafka cluster to use as the default database. Defaults to the compute pool's.")
+	cmd.Flags().String("service-account", "", "Service account to run the statements as. Defaults to the current user.")
+	cmd.Flags().StringToString("var", nil, `Variables to replace in the statements, as "<name>=<value>" pairs.`)
+	cmd.Flags().Duration("timeout", 10*time.Minute, "How long to wait for each statement to be running or to complete.")
+	dryrun.AddFlag(&cmd, "Print the statements with their variables replaced, without running them.")
+	output.AddFlag(&cmd, "Format of the statements which were run")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-flink-sql_runner")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-flink-sql_runner"))
+
+	exitcode.Execute(&cmd)
+}
+
+func runFiles(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	poolID, err := cmd.Flags().GetString("compute-pool")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	database, err := cmd.Flags().GetString("database")
+	cobra.CheckErr(err)
+
+	serviceAccount, err := cmd.Flags().GetString("service-account")
+	cobra.CheckErr(err)
+
+	vars, err := cmd.Flags().GetStringToString("var")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	files, err := sqlFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .sql files found")
+	}
+
+	// Every statement is read and has its variables replaced first, so that a typo doesn't leave a run half-applied.
+	var statements []statement
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		for _, s := range split(file, platform.Text(b)) {
+			s, err := substitute(s, vars)
+			if err != nil {
+				return err
+			}
+			statements = append(statements, s)
+		}
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, s := range statements {
+			plan.AddDetail("run", "statement", fmt.Sprintf("%s:%d", s.file, s.line), s.sql)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	list := func() ([]prompt.Option, error) { return prompt.ComputePoolOptions(environment) }
+	if err := prompt.New(cmd).Select(&poolID, "compute-pool", "Compute pool to run the statements on", list); err != nil {
+		return err
+	}
+
+	var pool computePool
+	describeArgs := []string{"flink", "compute-pool", "describe", poolID}
+	if environment != "" {
+		describeArgs = append(describeArgs, "--environment", environment)
+	}
+	if err := cli.JSON(&pool, describeArgs...); err != nil {
+		return err
+	}
+
+	r := &runner{
+		environment:    environment,
+		pool:           pool,
+		database:       database,
+		serviceAccount: serviceAccount,
+		timeout:        timeout,
+		out:            output.Progress(cmd),
+		properties:     map[string]string{},
+		ran:            []ranStatement{},
+	}
+	out := cmd.OutOrStdout()
+	for i, s := range statements {
+		if err := r.run(s); err != nil {
+			err = fmt.Errorf("%w\n%d of %d statements were run before the failure", err, i, len(statements))
+			return errors.Join(err, output.Print(out, format, r.ran, nil))
+		}
+	}
+
+	return output.Print(out, format, r.ran, func() {
+		fmt.Fprintf(out, "Ran %d statements from %d files.\n", len(statements), len(files))
+	})
+}
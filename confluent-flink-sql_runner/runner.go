@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// pollInterval is how often a statement's status is checked while waiting for it.
+const pollInterval = 5 * time.Second
+
+type computePool struct {
+	ID     string `json:"id"`
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+}
+
+// statementStatus is the part of "confluent flink statement describe" which the runner waits on.
+type statementStatus struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	StatusDetail string `json:"status_detail"`
+}
+
+type runner struct {
+	environment    string
+	pool           computePool
+	database       string
+	serviceAccount string
+	timeout        time.Duration
+	out            io.Writer
+
+	// properties are set by the SET statements so far, and passed to the statements which follow them.
+	properties map[string]string
+	// ran are the statements which were run so far, which --output json or yaml prints.
+	ran []ranStatement
+}
+
+// A ranStatement is a statement which was run, or a SET statement, which has no name or status.
+type ranStatement struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	SQL    string `json:"sql"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// run creates the statement, and waits for it to be running, for streaming statements, or to complete, for DDL and
+// bounded queries.
+func (r *runner) run(s statement) error {
+	if key, value, ok := property(s); ok {
+		r.properties[key] = value
+		fmt.Fprintf(r.out, "%s:%d: set %s to %s\n", s.file, s.line, key, value)
+		r.ran = append(r.ran, ranStatement{File: s.file, Line: s.line, SQL: s.sql})
+		return nil
+	}
+
+	args := []string{"flink", "statement", "create", "--sql", s.sql, "--compute-pool", r.pool.ID}
+	if r.environment != "" {
+		args = append(args, "--environment", r.environment)
+	}
+	if r.database != "" {
+		args = append(args, "--database", r.database)
+	}
+	if r.serviceAccount != "" {
+		args = append(args, "--service-account", r.serviceAccount)
+	}
+	keys := make([]string, 0, len(r.properties))
+	for key := range r.properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--property", fmt.Sprintf("%s=%s", key, r.properties[key]))
+	}
+
+	var created statementStatus
+	if err := cli.JSON(&created, args...); err != nil {
+		return r.diagnose(s, nil, err)
+	}
+
+	status, err := r.wait(created.Name)
+	if err != nil {
+		return r.diagnose(s, status, err)
+	}
+
+	fmt.Fprintf(r.out, "%s:%d: %s %s (%s)\n", s.file, s.line, summary(s.sql), strings.ToLower(status.Status), status.Name)
+	r.ran = append(r.ran, ranStatement{File: s.file, Line: s.line, SQL: s.sql, Name: status.Name, Status: status.Status})
+	return nil
+}
+
+func (r *runner) wait(name string) (*statementStatus, error) {
+	scope := r.scope()
+	deadline := time.Now().Add(r.timeout)
+	for {
+		var status statementStatus
+		if err := cli.JSON(&status, append([]string{"flink", "statement", "describe", name}, scope...)...); err != nil {
+			return nil, err
+		}
+
+		switch status.Status {
+		case "RUNNING", "COMPLETED":
+			return &status, nil
+		case "FAILED", "FAILING":
+			return &status, fmt.Errorf("statement %s failed", name)
+		case "STOPPED", "DELETING":
+			return &status, fmt.Errorf("statement %s was stopped", name)
+		}
+
+		if time.Now().After(deadline) {
+			return &status, fmt.Errorf("statement %s is still %s after %s", name, strings.ToLower(status.Status), r.timeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// diagnose describes why a statement failed: where it is, its SQL, its status detail, and its latest exceptions.
+func (r *runner) diagnose(s statement, status *statementStatus, err error) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d: %v\n", s.file, s.line, err)
+	for _, line := range strings.Split(s.sql, "\n") {
+		fmt.Fprintf(&b, "    %s\n", line)
+	}
+
+	if status != nil {
+		if status.StatusDetail != "" {
+			fmt.Fprintf(&b, "Status detail: %s\n", status.StatusDetail)
+		}
+
+		var exceptions []struct {
+			Timestamp string `json:"timestamp"`
+			Message   string `json:"message"`
+		}
+		args := append([]string{"flink", "statement", "exception", "list", status.Name}, r.scope()...)
+		if cli.JSON(&exceptions, args...) == nil {
+			for _, e := range exceptions {
+				fmt.Fprintf(&b, "Exception at %s: %s\n", e.Timestamp, e.Message)
+			}
+		}
+	}
+
+	return fmt.Errorf("%s", strings.TrimSpace(b.String()))
+}
+
+// scope is the environment, cloud, and region of the statements, which regional commands need.
+func (r *runner) scope() []string {
+	args := []string{"--cloud", r.pool.Cloud, "--region", r.pool.Region}
+	if r.environment != "" {
+		args = append(args, "--environment", r.environment)
+	}
+	return args
+}
+
+// summary shortens a statement to its first line, for progress messages.
+func summary(sql string) string {
+	line, _, multiline := strings.Cut(sql, "\n")
+	if len(line) > 60 {
+		return line[:57] + "..."
+	}
+	if multiline {
+		return line + " ..."
+	}
+	return line
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A statement of a SQL file, and the line it starts on, for diagnostics.
+type statement struct {
+	file string
+	line int
+	sql  string
+}
+
+// sqlFiles returns the files, and the .sql files in the directories, in order. Files in a directory are sorted by
+// name, so that they can be numbered in the order they're run.
+func sqlFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(arg, "*.sql"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// split splits a SQL file into its statements, at semicolons outside of quotes and comments. Comments before a statement
+// are removed, and those within it are kept, since Flink ignores them.
+func split(file, sql string) []statement {
+	var statements []statement
+	start, line, startLine := 0, 1, 1
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\n':
+			line++
+		case c == '\'' || c == '"' || c == '`':
+			// Quotes are escaped by doubling them, which reads as two quoted strings in a row.
+			for i++; i < len(sql) && sql[i] != c; i++ {
+				if sql[i] == '\n' {
+					line++
+				}
+			}
+		case strings.HasPrefix(sql[i:], "--"):
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			line++
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i - 2
+			}
+			line += strings.Count(sql[i:i+2+end], "\n")
+			i += end + 3
+		case c == ';':
+			if s, lines := trim(sql[start:i]); s != "" {
+				statements = append(statements, statement{file: file, line: startLine + lines, sql: s})
+			}
+			start, startLine = i+1, line
+		}
+	}
+	if s, lines := trim(sql[start:]); s != "" {
+		statements = append(statements, statement{file: file, line: startLine + lines, sql: s})
+	}
+	return statements
+}
+
+// trim removes the whitespace and comments before a statement, and the whitespace after it, and returns how many lines
+// it removed from the start, so that the statement's line is where its text starts.
+func trim(sql string) (string, int) {
+	lines := 0
+	for {
+		rest := strings.TrimLeft(sql, " \t\r\n")
+		lines += strings.Count(sql[:len(sql)-len(rest)], "\n")
+		sql = rest
+
+		comment := leadingComment.FindString(sql)
+		if comment == "" {
+			return strings.TrimSpace(sql), lines
+		}
+		lines += strings.Count(comment, "\n")
+		sql = sql[len(comment):]
+	}
+}
+
+var leadingComment = regexp.MustCompile(`^(?s:--[^\n]*|/\*.*?\*/)`)
+
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// substitute replaces ${name} with the value of the variable, and fails on variables which aren't set, so that a
+// statement isn't run with a placeholder in it.
+func substitute(s statement, vars map[string]string) (statement, error) {
+	var missing []string
+	s.sql = variablePattern.ReplaceAllStringFunc(s.sql, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return s, fmt.Errorf("%s:%d: variables %s aren't set, pass them with --var", s.file, s.line, strings.Join(missing, ", "))
+	}
+	return s, nil
+}
+
+var setPattern = regexp.MustCompile(`(?is)^SET\s+'([^']+)'\s*=\s*'([^']*)'$`)
+
+// property returns the key and value of a SET statement, which the Statements API doesn't run, but takes as a property
+// of the statements which follow it.
+func property(s statement) (string, string, bool) {
+	m := setPattern.FindStringSubmatch(s.sql)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// A bulkAction is a "confluent flink statement" command which is run on every selected statement in the status which it
+// applies to.
+type bulkAction struct {
+	name   string
+	status string
+	done   string
+}
+
+func newBulkCommand(a bulkAction, short, long string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   a.name,
+		Short: short,
+		Long:  long,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return bulk(cmd, a)
+		},
+		Example: fmt.Sprintf("confluent flink statement-monitor %s --compute-pool lfcp-123456 --prefix orders- --dry-run", a.name),
+	}
+
+	dryrun.AddFlag(cmd, fmt.Sprintf("Print the statements which would be %s without changing them.", a.done))
+	cmd.Flags().Bool("force", false, "Skip the confirmation prompt.")
+	output.AddFlag(cmd, fmt.Sprintf("Format of the statements which were %s", a.done))
+
+	return cmd
+}
+
+func bulk(cmd *cobra.Command, a bulkAction) error {
+	cmd.SilenceUsage = true
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	s, err := newSelector(cmd)
+	if err != nil {
+		return err
+	}
+
+	listed, err := s.list()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, l := range listed {
+		if l.Status == a.status {
+			names = append(names, l.Name)
+		}
+	}
+
+	out := output.Progress(cmd)
+	if len(names) == 0 {
+		fmt.Fprintf(out, "No %s statements selected.\n", strings.ToLower(a.status))
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+	if dryRun {
+		var plan dryrun.Plan
+		for _, name := range names {
+			plan.Add(a.name, "statement", name)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+	for _, name := range names {
+		fmt.Fprintln(out, name)
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to %s %d statements?", a.name, len(names)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(cmd.ErrOrStderr(), "No statements were %s.\n", a.done)
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+	}
+
+	// Every statement is attempted, so that one failure doesn't leave the rest as they were.
+	var errs []error
+	var done dryrun.Plan
+	bar := progress.NewBar(cmd.ErrOrStderr(), "Statements "+a.done, len(names))
+	for _, name := range names {
+		if _, err := cli.Run(append([]string{"flink", "statement", a.name, name}, s.scope()...)...); err != nil {
+			errs = append(errs, err)
+		} else {
+			done.Add(a.name, "statement", name)
+		}
+		bar.Add(1)
+	}
+	bar.Done()
+	for _, err := range errs {
+		fmt.Fprintln(cmd.ErrOrStderr(), err)
+	}
+	failed := len(errs)
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "%d statements were %s.\n", len(names)-failed, a.done)
+	if failed > 0 {
+		return errors.Join(fmt.Errorf("failed to %s %d statements", a.name, failed), dryrun.PrintApplied(cmd, done))
+	}
+	return dryrun.PrintApplied(cmd, done)
+}
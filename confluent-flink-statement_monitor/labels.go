@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// labeledStatements returns the names of the statements of the compute pool which have every label, from the Flink
+// REST API, since the CLI doesn't show the labels of statements. The API is authenticated with a Flink API key.
+func labeledStatements(organization, environment string, pool computePool, labels map[string]string, key, secret string) (map[string]bool, error) {
+	client := retry.Client(30 * time.Second)
+	url := fmt.Sprintf("https://flink.%s.%s.confluent.cloud/sql/v1/organizations/%s/environments/%s/statements?page_size=100", pool.Region, pool.Cloud, organization, environment)
+
+	statements, err := paging.All(func(token string) ([]labeledStatement, string, error) {
+		req, err := http.NewRequest(http.MethodGet, paging.WithToken(url, token), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(key, secret)
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var page paging.CloudPage[labeledStatement]
+		err = json.NewDecoder(res.Body).Decode(&page)
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("failed to list the labels of statements: %s", res.Status)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse the labels of statements: %w", err)
+		}
+		return page.Data, page.Next(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, s := range statements {
+		if s.Spec.ComputePoolID != pool.ID {
+			continue
+		}
+		matches := true
+		for k, v := range labels {
+			if s.Metadata.Labels[k] != v {
+				matches = false
+			}
+		}
+		if matches {
+			names[s.Name] = true
+		}
+	}
+	return names, nil
+}
+
+// labeledStatement is a statement in the Flink REST API, with its labels.
+type labeledStatement struct {
+	Name     string `json:"name"`
+	Metadata struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		ComputePoolID string `json:"compute_pool_id"`
+	} `json:"spec"`
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := &cobra.Command{
+		Use:   "statement-monitor",
+		Short: "Monitor the Flink statements in a compute pool.",
+		Long:  "Report the status, uptime, CFUs, and latest exception of every Flink statement in a compute pool, or of those with a name prefix or labels, and stop or resume them in bulk.",
+		Args:  cobra.NoArgs,
+		RunE:  monitor,
+		Example: `confluent flink statement-monitor --compute-pool lfcp-123456 --watch 30s
+confluent flink statement-monitor --compute-pool lfcp-123456 --prefix orders- --output json
+confluent flink statement-monitor stop --compute-pool lfcp-123456 --label team=payments --flink-api-key ABCDEFGHIJKLMNOP`,
+	}
+
+	cmd.PersistentFlags().String("compute-pool", "", "Flink compute pool ID.")
+	cmd.PersistentFlags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.PersistentFlags().String("prefix", "", "Only select statements whose names start with this prefix.")
+	cmd.PersistentFlags().StringToString("label", nil, `Only select statements with these labels, as "<key>=<value>" pairs. Requires a Flink API key.`)
+	cmd.PersistentFlags().String("flink-api-key", "", "Flink API key to read the labels of statements with. Defaults to $CONFLUENT_FLINK_API_KEY.")
+	cmd.PersistentFlags().String("flink-api-secret", "", "Secret of the Flink API key. Defaults to $CONFLUENT_FLINK_API_SECRET.")
+
+	cmd.Flags().Duration("watch", 0, "Refresh the report at this interval until interrupted.")
+	output.AddFlag(cmd, "Format of the report")
+	cmd.Flags().String("metrics-api-key", "", "Cloud API key to read the CFUs of statements from the Metrics API with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("metrics-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	cmd.Flags().Int("parallelism", 8, "How many statements to read the exceptions of at once.")
+	prompt.AddFlags(cmd)
+	logging.AddFlags(cmd)
+
+	cobra.CheckErr(cmd.MarkPersistentFlagRequired("compute-pool"))
+
+	cmd.AddCommand(
+		newBulkCommand(bulkAction{name: "stop", status: "RUNNING", done: "stopped"}, "Stop the selected Flink statements.", "Stop the running Flink statements which are selected by --prefix and --label. They can be resumed with \"confluent flink statement-monitor resume\"."),
+		newBulkCommand(bulkAction{name: "resume", status: "STOPPED", done: "resumed"}, "Resume the selected Flink statements.", "Resume the stopped Flink statements which are selected by --prefix and --label."),
+	)
+
+	completion.Add(cmd, "confluent-flink-statement_monitor")
+	cobra.CheckErr(plugin.ApplyConfig(cmd, "confluent-flink-statement_monitor"))
+
+	exitcode.Execute(cmd)
+}
+
+// newSelector reads the flags which select statements, and finds the statements with the labels, if any were passed.
+func newSelector(cmd *cobra.Command) (selector, error) {
+	poolID, err := cmd.Flags().GetString("compute-pool")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	labels, err := cmd.Flags().GetStringToString("label")
+	cobra.CheckErr(err)
+
+	flinkKey, err := cmd.Flags().GetString("flink-api-key")
+	cobra.CheckErr(err)
+
+	flinkSecret, err := cmd.Flags().GetString("flink-api-secret")
+	cobra.CheckErr(err)
+
+	s := selector{environment: environment, prefix: prefix}
+
+	describeArgs := []string{"flink", "compute-pool", "describe", poolID}
+	if environment != "" {
+		describeArgs = append(describeArgs, "--environment", environment)
+	}
+	if err := cli.JSON(&s.pool, describeArgs...); err != nil {
+		return s, err
+	}
+
+	if len(labels) == 0 {
+		return s, nil
+	}
+
+	if flinkKey == "" {
+		flinkKey = os.Getenv("CONFLUENT_FLINK_API_KEY")
+	}
+	if flinkSecret == "" {
+		flinkSecret = os.Getenv("CONFLUENT_FLINK_API_SECRET")
+	}
+	if flinkKey == "" || flinkSecret == "" {
+		return s, exitcode.Invalid("a Flink API key is required to select statements by label, pass --flink-api-key and --flink-api-secret or set CONFLUENT_FLINK_API_KEY and CONFLUENT_FLINK_API_SECRET")
+	}
+
+	var organization, env struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&organization, "organization", "describe"); err != nil {
+		return s, err
+	}
+	if err := cli.JSON(&env, append([]string{"environment", "describe"}, environment)...); err != nil {
+		return s, err
+	}
+
+	s.labeled, err = labeledStatements(organization.ID, env.ID, s.pool, labels, flinkKey, flinkSecret)
+	return s, err
+}
+
+func monitor(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	watch, err := cmd.Flags().GetDuration("watch")
+	cobra.CheckErr(err)
+
+	metricsKey, err := cmd.Flags().GetString("metrics-api-key")
+	cobra.CheckErr(err)
+
+	metricsSecret, err := cmd.Flags().GetString("metrics-api-secret")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	if watch < 0 {
+		return exitcode.Invalid("--watch must not be negative")
+	}
+	if metricsKey == "" {
+		metricsKey = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if metricsSecret == "" {
+		metricsSecret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	s, err := newSelector(cmd)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	report := func() error {
+		// CFUs are only reported with a Cloud API key, which not every user of the plugin has.
+		var cfus map[string]float64
+		if metricsKey != "" && metricsSecret != "" {
+			if cfus, err = statementCFUs(s.pool.ID, metricsKey, metricsSecret); err != nil {
+				return err
+			}
+		}
+
+		statements, err := s.report(cfus, time.Now(), parallelism)
+		if err != nil {
+			return err
+		}
+
+		if format != output.Table {
+			if statements == nil {
+				statements = []statement{}
+			}
+			return output.Print(out, format, statements, nil)
+		}
+
+		if watch > 0 {
+			// Clear the terminal, so that the report is redrawn in place, unless its output is piped.
+			if platform.Terminal(out) {
+				fmt.Fprint(out, "\033[H\033[2J")
+			}
+			fmt.Fprintf(out, "Every %s: %s\n\n", watch, time.Now().Format(time.TimeOnly))
+		}
+		print(out, statements)
+		return nil
+	}
+
+	if watch == 0 {
+		return report()
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(watch)
+	defer ticker.Stop()
+	for {
+		if err := report(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func print(w io.Writer, statements []statement) {
+	if len(statements) == 0 {
+		fmt.Fprintln(w, "No statements found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Name\tStatus\tUptime\tCFUs\tLast Exception")
+	for _, s := range statements {
+		uptime, cfus := "-", "-"
+		if s.Uptime != "" {
+			uptime = s.Uptime
+		}
+		if s.CFUs != nil {
+			cfus = strconv.FormatFloat(*s.CFUs, 'f', -1, 64)
+		}
+		// Statements which failed before running may have no exception, but a status detail of why.
+		exception := s.LastException
+		if exception == "" {
+			exception = s.StatusDetail
+		}
+		exception, _, _ = strings.Cut(exception, "\n")
+		if len(exception) > 80 {
+			exception = exception[:77] + "..."
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.Status, uptime, cfus, exception)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+const metricsURL = "https://api.telemetry.confluent.cloud/v2/metrics/cloud/query"
+
+// cfuPoint is how many CFUs a statement used in a minute.
+type cfuPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Statement string    `json:"resource.flink_statement.name"`
+	Value     float64   `json:"value"`
+}
+
+// statementCFUs returns how many CFUs each statement of the compute pool is using, from the latest minute of the Metrics
+// API, which is authenticated with a Cloud API key.
+func statementCFUs(pool, key, secret string) (map[string]float64, error) {
+	query := map[string]any{
+		"aggregations": []map[string]string{{"metric": "io.confluent.flink/statement_utilization/current_cfus"}},
+		"filter":       map[string]string{"field": "resource.compute_pool.id", "op": "EQ", "value": pool},
+		"granularity":  "PT1M",
+		"intervals":    []string{"now-10m/now"},
+		"group_by":     []string{"resource.flink_statement.name"},
+		"limit":        1000,
+	}
+	b, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	client := retry.Client(30 * time.Second)
+	points, err := paging.All(func(token string) ([]cfuPoint, string, error) {
+		req, err := http.NewRequest(http.MethodPost, paging.WithToken(metricsURL, token), bytes.NewReader(b))
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(key, secret)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("failed to query the CFUs of %s: %s", pool, res.Status)
+		}
+
+		var page paging.MetricsPage[cfuPoint]
+		if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+			return nil, "", fmt.Errorf("failed to parse the CFUs of %s: %w", pool, err)
+		}
+		return page.Data, page.Next(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfus := map[string]float64{}
+	latest := map[string]time.Time{}
+	for _, d := range points {
+		if d.Timestamp.After(latest[d.Statement]) {
+			cfus[d.Statement], latest[d.Statement] = d.Value, d.Timestamp
+		}
+	}
+	return cfus, nil
+}
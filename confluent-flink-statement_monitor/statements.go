@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+type computePool struct {
+	ID     string `json:"id"`
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+}
+
+// listedStatement is a statement in the output of "confluent flink statement list".
+type listedStatement struct {
+	Name         string    `json:"name"`
+	Statement    string    `json:"statement"`
+	Status       string    `json:"status"`
+	StatusDetail string    `json:"status_detail"`
+	CreationDate time.Time `json:"creation_date"`
+}
+
+// statement is a row of the report. Uptime is only set for running statements, and CFUs only with a Cloud API key.
+type statement struct {
+	Name          string   `json:"name"`
+	Status        string   `json:"status"`
+	StatusDetail  string   `json:"status_detail,omitempty"`
+	Uptime        string   `json:"uptime,omitempty"`
+	CFUs          *float64 `json:"cfus,omitempty"`
+	LastException string   `json:"last_exception,omitempty"`
+	SQL           string   `json:"sql"`
+}
+
+// selector picks the statements of a compute pool which a report or bulk operation applies to.
+type selector struct {
+	environment string
+	pool        computePool
+	prefix      string
+	// labeled are the names of the statements with the labels, or nil if no labels were passed.
+	labeled map[string]bool
+}
+
+// scope is the environment, cloud, and region of the statements, which regional commands need.
+func (s selector) scope() []string {
+	args := []string{"--cloud", s.pool.Cloud, "--region", s.pool.Region}
+	if s.environment != "" {
+		args = append(args, "--environment", s.environment)
+	}
+	return args
+}
+
+func (s selector) list() ([]listedStatement, error) {
+	args := []string{"flink", "statement", "list", "--compute-pool", s.pool.ID}
+	if s.environment != "" {
+		args = append(args, "--environment", s.environment)
+	}
+	var listed []listedStatement
+	if err := cli.JSON(&listed, args...); err != nil {
+		return nil, err
+	}
+
+	var selected []listedStatement
+	for _, l := range listed {
+		if !strings.HasPrefix(l.Name, s.prefix) || s.labeled != nil && !s.labeled[l.Name] {
+			continue
+		}
+		selected = append(selected, l)
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	return selected, nil
+}
+
+// report lists the statements with their uptimes and latest exceptions, and their CFUs if they were queried.
+func (s selector) report(cfus map[string]float64, now time.Time, parallelism int) ([]statement, error) {
+	listed, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]statement, len(listed))
+	errs := make([]error, len(listed))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for i, l := range listed {
+		wg.Add(1)
+		go func(i int, l listedStatement) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			st := statement{Name: l.Name, Status: l.Status, StatusDetail: l.StatusDetail, SQL: l.Statement}
+			if l.Status == "RUNNING" && !l.CreationDate.IsZero() {
+				st.Uptime = formatUptime(now.Sub(l.CreationDate))
+			}
+			if c, ok := cfus[l.Name]; ok {
+				st.CFUs = &c
+			}
+			st.LastException, errs[i] = s.lastException(l.Name)
+			statements[i] = st
+		}(i, l)
+	}
+	wg.Wait()
+
+	return statements, errors.Join(errs...)
+}
+
+func (s selector) lastException(name string) (string, error) {
+	var exceptions []struct {
+		Timestamp time.Time `json:"timestamp"`
+		Message   string    `json:"message"`
+	}
+	if err := cli.JSON(&exceptions, append([]string{"flink", "statement", "exception", "list", name}, s.scope()...)...); err != nil {
+		return "", err
+	}
+
+	last := ""
+	var at time.Time
+	for _, e := range exceptions {
+		if last == "" || e.Timestamp.After(at) {
+			last, at = e.Message, e.Timestamp
+		}
+	}
+	return last, nil
+}
+
+// formatUptime formats a duration in days, hours, and minutes, such as "3d 4h 12m".
+func formatUptime(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	days, hours := minutes/(24*60), minutes/60%24
+	minutes %= 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "teardown",
+		Short: "Stop Flink statements and delete compute pools.",
+		Long:  "Stop every running Flink statement and delete every compute pool in the organization, or in the given environments, such as those left behind by quickstarts and workshops, after listing them and asking for confirmation.",
+		Args:  cobra.NoArgs,
+		RunE:  teardown,
+		Example: `confluent flink teardown --dry-run
+confluent flink teardown --environment env-123456 --force
+confluent flink teardown --compute-pool lfcp-123456 --keep-pools`,
+	}
+
+	cmd.Flags().StringSlice("environment", nil, "IDs of the environments to tear down. Defaults to every environment.")
+	cmd.Flags().StringSlice("compute-pool", nil, "IDs of the compute pools to tear down. Defaults to every compute pool.")
+	cmd.Flags().Bool("keep-pools", false, "Only stop the running statements, and keep the compute pools.")
+	dryrun.AddFlag(&cmd, "List the statements and compute pools which would be torn down without changing them.")
+	cmd.Flags().Bool("force", false, "Skip the confirmation prompt.")
+	output.AddFlag(&cmd, "Format of the statements and compute pools which were torn down")
+	cli.AddCacheFlag(&cmd)
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	completion.Add(&cmd, "confluent-flink-teardown")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-flink-teardown"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+func teardown(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	environmentIDs, err := cmd.Flags().GetStringSlice("environment")
+	cobra.CheckErr(err)
+
+	poolIDs, err := cmd.Flags().GetStringSlice("compute-pool")
+	cobra.CheckErr(err)
+
+	keepPools, err := cmd.Flags().GetBool("keep-pools")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	environments, err := listEnvironments(environmentIDs)
+	if err != nil {
+		return err
+	}
+
+	ids := map[string]bool{}
+	for _, id := range poolIDs {
+		ids[id] = true
+	}
+
+	// Every compute pool is listed up front, so that the confirmation shows everything which will be torn down.
+	var pools []computePool
+	for _, env := range environments {
+		listed, err := listComputePools(env, ids)
+		if err != nil {
+			return err
+		}
+		pools = append(pools, listed...)
+	}
+
+	found := map[string]bool{}
+	for _, p := range pools {
+		found[p.ID] = true
+	}
+	for _, id := range poolIDs {
+		if !found[id] {
+			return fmt.Errorf(`compute pool "%s" not found`, id)
+		}
+	}
+
+	out := output.Progress(cmd)
+	if len(pools) == 0 {
+		fmt.Fprintln(out, "No compute pools found.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, p := range pools {
+			for _, name := range p.running {
+				plan.AddDetail("stop", "statement", name, "in compute pool "+p.ID)
+			}
+			if !keepPools {
+				plan.AddDetail("delete", "compute pool", p.Name, "("+p.ID+")")
+			}
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	statements := 0
+	for i, p := range pools {
+		if i == 0 || p.environment != pools[i-1].environment {
+			fmt.Fprintf(out, "Environment %s (\"%s\"):\n", p.environment.ID, p.environment.Name)
+		}
+		fmt.Fprintf(out, "  %s, %d running statements\n", p, len(p.running))
+		for _, name := range p.running {
+			fmt.Fprintf(out, "    %s\n", name)
+		}
+		statements += len(p.running)
+	}
+
+	summary := fmt.Sprintf("stop %d statements and delete %d compute pools", statements, len(pools))
+	if keepPools {
+		if statements == 0 {
+			fmt.Fprintln(out, "No running statements found.")
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+		summary = fmt.Sprintf("stop %d statements", statements)
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to %s?", summary))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not tearing anything down.")
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+	}
+
+	// Every pool is attempted, but a pool is only deleted once all of its statements have stopped.
+	var errs []error
+	var tornDown dryrun.Plan
+	for _, p := range pools {
+		stopped := true
+		for _, name := range p.running {
+			if err := interrupt.Err(); err != nil {
+				errs = append(errs, fmt.Errorf("the teardown was interrupted, re-run it to stop the rest: %w", err))
+				return errors.Join(append(errs, dryrun.PrintApplied(cmd, tornDown))...)
+			}
+			if err := p.stop(name); err != nil {
+				errs = append(errs, err)
+				stopped = false
+				continue
+			}
+			fmt.Fprintf(out, "Stopped statement %s.\n", name)
+			tornDown.AddDetail("stop", "statement", name, "in compute pool "+p.ID)
+		}
+
+		if keepPools {
+			continue
+		}
+		if !stopped {
+			errs = append(errs, fmt.Errorf("not deleting %s, since some of its statements failed to stop", p))
+			continue
+		}
+		if err := interrupt.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("the teardown was interrupted, re-run it to delete the rest: %w", err))
+			return errors.Join(append(errs, dryrun.PrintApplied(cmd, tornDown))...)
+		}
+		if err := p.remove(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(out, "Deleted %s.\n", p)
+		tornDown.AddDetail("delete", "compute pool", p.Name, "("+p.ID+")")
+	}
+	return errors.Join(append(errs, dryrun.PrintApplied(cmd, tornDown))...)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+type computePool struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Cloud  string `json:"cloud"`
+	Region string `json:"region"`
+
+	environment cli.Environment
+	// running are the names of the pool's statements which are still running, and consuming CFUs.
+	running []string
+}
+
+// activeStatuses are the statement statuses which hold on to a compute pool's CFUs, and which need to be stopped.
+var activeStatuses = map[string]bool{"PENDING": true, "RUNNING": true}
+
+// listEnvironments lists the environments with the IDs, or every environment in the organization.
+func listEnvironments(ids []string) ([]cli.Environment, error) {
+	if len(ids) > 0 {
+		environments := make([]cli.Environment, len(ids))
+		for i, id := range ids {
+			if err := cli.JSON(&environments[i], "environment", "describe", id); err != nil {
+				return nil, err
+			}
+		}
+		return environments, nil
+	}
+
+	var environments []cli.Environment
+	if err := cli.JSON(&environments, "environment", "list"); err != nil {
+		return nil, err
+	}
+	sort.Slice(environments, func(i, j int) bool { return environments[i].ID < environments[j].ID })
+	return environments, nil
+}
+
+// listComputePools lists the environment's compute pools, or only those with the IDs, along with their running
+// statements.
+func listComputePools(env cli.Environment, ids map[string]bool) ([]computePool, error) {
+	var pools []computePool
+	if err := cli.JSON(&pools, "flink", "compute-pool", "list", "--environment", env.ID); err != nil {
+		return nil, err
+	}
+
+	var selected []computePool
+	for _, p := range pools {
+		if len(ids) > 0 && !ids[p.ID] {
+			continue
+		}
+
+		var statements []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		}
+		if err := cli.JSON(&statements, "flink", "statement", "list", "--compute-pool", p.ID, "--environment", env.ID); err != nil {
+			return nil, err
+		}
+		for _, s := range statements {
+			if activeStatuses[s.Status] {
+				p.running = append(p.running, s.Name)
+			}
+		}
+		sort.Strings(p.running)
+
+		p.environment = env
+		selected = append(selected, p)
+	}
+	return selected, nil
+}
+
+// stop stops the pool's running statements. Statements are regional, so they're stopped in the pool's cloud and
+// region.
+func (p computePool) stop(name string) error {
+	_, err := cli.Run("flink", "statement", "stop", name, "--environment", p.environment.ID, "--cloud", p.Cloud, "--region", p.Region)
+	return err
+}
+
+func (p computePool) remove() error {
+	_, err := cli.Run("flink", "compute-pool", "delete", p.ID, "--environment", p.environment.ID, "--force")
+	return err
+}
+
+func (p computePool) String() string {
+	return fmt.Sprintf(`compute pool %s ("%s")`, p.ID, p.Name)
+}
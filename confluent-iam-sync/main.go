@@ -0,0 +1,96 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "sync <spec>",
+		Short:   "Reconcile service accounts, identity pools, and group mappings with a spec.",
+		Long:    "Reconcile the organization's service accounts, the identity pools of its identity providers, and its group mappings with a YAML spec: create the principals which are missing, update their descriptions, identity claims, and filters, and with --prune, delete those of the kinds in the spec which aren't in it. The diff is printed before anything is changed.",
+		Args:    cobra.ExactArgs(1),
+		RunE:    sync,
+		Example: "confluent iam sync iam.yaml --prune --dry-run",
+	}
+
+	dryrun.AddFlag(&cmd, "Print the changes without changing any principals.")
+	cmd.Flags().Bool("prune", false, "Delete the service accounts, identity pools of the identity providers, and group mappings which aren't in the spec, for each of them which is in it.")
+	output.AddFlag(&cmd, "Format of the changes")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-iam-sync")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-iam-sync"))
+
+	exitcode.Execute(&cmd)
+}
+
+func sync(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	prune, err := cmd.Flags().GetBool("prune")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	s, err := readSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	changes, err := plan(s, prune)
+	if err != nil {
+		return err
+	}
+
+	out := output.Progress(cmd)
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "The principals match the spec.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	if dryRun {
+		var p dryrun.Plan
+		for _, c := range changes {
+			p.Actions = append(p.Actions, c.action())
+		}
+		return dryrun.Print(cmd, p)
+	}
+	for _, c := range changes {
+		c.print(out)
+	}
+
+	counts := map[string]int{}
+	var applied dryrun.Plan
+	for _, c := range changes {
+		if _, err := cli.Run(c.args...); err != nil {
+			return errors.Join(fmt.Errorf("failed to sync %s \"%s\": %w", c.kind, c.name, err), dryrun.PrintApplied(cmd, applied))
+		}
+		counts[c.op]++
+		applied.Actions = append(applied.Actions, c.action())
+	}
+	fmt.Fprintf(out, "Created %d principals, updated %d, and deleted %d.\n", counts["+"], counts["~"], counts["-"])
+	return dryrun.PrintApplied(cmd, applied)
+}
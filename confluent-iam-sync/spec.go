@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIdentityClaim is the claim which identifies the principal of a token, unless a pool's spec sets another.
+const defaultIdentityClaim = "claims.sub"
+
+// A spec declares the organization's service accounts, identity pools, and group mappings by name, e.g.:
+//
+//	service_accounts:
+//	  orders-app:
+//	    description: Produces orders.
+//	identity_providers:
+//	  okta:
+//	    identity_pools:
+//	      orders-readers:
+//	        description: Reads orders.
+//	        filter: claims.aud == "orders"
+//	group_mappings:
+//	  platform-admins:
+//	    description: Administers the platform.
+//	    filter: '"platform-admins" in groups'
+//
+// Identity providers are referred to by name or ID, and must already exist.
+type spec struct {
+	ServiceAccounts   map[string]serviceAccountSpec   `yaml:"service_accounts"`
+	IdentityProviders map[string]identityProviderSpec `yaml:"identity_providers"`
+	GroupMappings     map[string]groupMappingSpec     `yaml:"group_mappings"`
+}
+
+type serviceAccountSpec struct {
+	Description string `yaml:"description"`
+}
+
+type identityProviderSpec struct {
+	IdentityPools map[string]identityPoolSpec `yaml:"identity_pools"`
+}
+
+type identityPoolSpec struct {
+	Description   string `yaml:"description"`
+	IdentityClaim string `yaml:"identity_claim"`
+	Filter        string `yaml:"filter"`
+}
+
+type groupMappingSpec struct {
+	Description string `yaml:"description"`
+	Filter      string `yaml:"filter"`
+}
+
+func readSpec(path string) (spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return spec{}, fmt.Errorf("failed to read the spec: %w", err)
+	}
+
+	var s spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return spec{}, fmt.Errorf(`failed to parse the spec "%s": %w`, path, err)
+	}
+
+	for name, sa := range s.ServiceAccounts {
+		if sa.Description == "" {
+			return spec{}, fmt.Errorf(`service account "%s" needs a description`, name)
+		}
+	}
+	for provider, p := range s.IdentityProviders {
+		for name, pool := range p.IdentityPools {
+			if pool.Filter == "" {
+				return spec{}, fmt.Errorf(`identity pool "%s" of identity provider "%s" needs a filter`, name, provider)
+			}
+			if pool.IdentityClaim == "" {
+				pool.IdentityClaim = defaultIdentityClaim
+				p.IdentityPools[name] = pool
+			}
+		}
+	}
+	for name, m := range s.GroupMappings {
+		if m.Filter == "" {
+			return spec{}, fmt.Errorf(`group mapping "%s" needs a filter`, name)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+)
+
+// serviceAccount is a service account in the output of "confluent iam service-account list".
+type serviceAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// identityProvider is a provider in the output of "confluent iam provider list".
+type identityProvider struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// identityPool is a pool in the output of "confluent iam pool list".
+type identityPool struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	IdentityClaim string `json:"identity_claim"`
+	Filter        string `json:"filter"`
+}
+
+// groupMapping is a mapping in the output of "confluent iam group-mapping list".
+type groupMapping struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Filter      string `json:"filter"`
+}
+
+// A change creates, updates, or deletes a principal, with the confluent CLI command which makes it.
+type change struct {
+	op     string
+	kind   string
+	name   string
+	fields []field
+	args   []string
+}
+
+// A field is a setting of a principal which is changed by an update.
+type field struct {
+	name, from, to string
+}
+
+// plan lists the changes which reconcile the organization with the spec: creates first, then updates, then, with
+// prune, deletes of the principals of the kinds in the spec which aren't in it.
+func plan(s spec, prune bool) ([]change, error) {
+	var creates, updates, deletes []change
+
+	if s.ServiceAccounts != nil {
+		var existing []serviceAccount
+		if err := cli.JSON(&existing, "iam", "service-account", "list"); err != nil {
+			return nil, err
+		}
+		byName := map[string]serviceAccount{}
+		for _, sa := range existing {
+			byName[sa.Name] = sa
+		}
+
+		for _, name := range sortedKeys(s.ServiceAccounts) {
+			want := s.ServiceAccounts[name]
+			sa, ok := byName[name]
+			if !ok {
+				creates = append(creates, change{op: "+", kind: "service account", name: name, args: []string{"iam", "service-account", "create", name, "--description", want.Description}})
+				continue
+			}
+			if sa.Description != want.Description {
+				updates = append(updates, change{
+					op: "~", kind: "service account", name: name,
+					fields: []field{{"description", sa.Description, want.Description}},
+					args:   []string{"iam", "service-account", "update", sa.ID, "--description", want.Description},
+				})
+			}
+		}
+		if prune {
+			for _, sa := range existing {
+				if _, ok := s.ServiceAccounts[sa.Name]; !ok {
+					deletes = append(deletes, change{op: "-", kind: "service account", name: sa.Name, args: []string{"iam", "service-account", "delete", sa.ID, "--force"}})
+				}
+			}
+		}
+	}
+
+	if s.IdentityProviders != nil {
+		var providers []identityProvider
+		if err := cli.JSON(&providers, "iam", "provider", "list"); err != nil {
+			return nil, err
+		}
+
+		for _, ref := range sortedKeys(s.IdentityProviders) {
+			var provider *identityProvider
+			for i, p := range providers {
+				if p.ID == ref || p.Name == ref {
+					provider = &providers[i]
+				}
+			}
+			if provider == nil {
+				return nil, fmt.Errorf(`identity provider "%s" not found, identity providers must be created before their pools are synced`, ref)
+			}
+
+			var existing []identityPool
+			if err := cli.JSON(&existing, "iam", "pool", "list", "--provider", provider.ID); err != nil {
+				return nil, err
+			}
+			byName := map[string]identityPool{}
+			for _, p := range existing {
+				byName[p.Name] = p
+			}
+
+			pools := s.IdentityProviders[ref].IdentityPools
+			for _, name := range sortedKeys(pools) {
+				want := pools[name]
+				display := provider.Name + "/" + name
+				p, ok := byName[name]
+				if !ok {
+					creates = append(creates, change{op: "+", kind: "identity pool", name: display, args: []string{"iam", "pool", "create", name, "--provider", provider.ID, "--description", want.Description, "--identity-claim", want.IdentityClaim, "--filter", want.Filter}})
+					continue
+				}
+
+				c := change{op: "~", kind: "identity pool", name: display, args: []string{"iam", "pool", "update", p.ID, "--provider", provider.ID}}
+				if p.Description != want.Description {
+					c.fields = append(c.fields, field{"description", p.Description, want.Description})
+					c.args = append(c.args, "--description", want.Description)
+				}
+				if p.IdentityClaim != want.IdentityClaim {
+					c.fields = append(c.fields, field{"identity_claim", p.IdentityClaim, want.IdentityClaim})
+					c.args = append(c.args, "--identity-claim", want.IdentityClaim)
+				}
+				if p.Filter != want.Filter {
+					c.fields = append(c.fields, field{"filter", p.Filter, want.Filter})
+					c.args = append(c.args, "--filter", want.Filter)
+				}
+				if len(c.fields) > 0 {
+					updates = append(updates, c)
+				}
+			}
+			if prune {
+				for _, p := range existing {
+					if _, ok := pools[p.Name]; !ok {
+						deletes = append(deletes, change{op: "-", kind: "identity pool", name: provider.Name + "/" + p.Name, args: []string{"iam", "pool", "delete", p.ID, "--provider", provider.ID, "--force"}})
+					}
+				}
+			}
+		}
+	}
+
+	if s.GroupMappings != nil {
+		var existing []groupMapping
+		if err := cli.JSON(&existing, "iam", "group-mapping", "list"); err != nil {
+			return nil, err
+		}
+		byName := map[string]groupMapping{}
+		for _, m := range existing {
+			byName[m.Name] = m
+		}
+
+		for _, name := range sortedKeys(s.GroupMappings) {
+			want := s.GroupMappings[name]
+			m, ok := byName[name]
+			if !ok {
+				creates = append(creates, change{op: "+", kind: "group mapping", name: name, args: []string{"iam", "group-mapping", "create", name, "--description", want.Description, "--filter", want.Filter}})
+				continue
+			}
+
+			c := change{op: "~", kind: "group mapping", name: name, args: []string{"iam", "group-mapping", "update", m.ID}}
+			if m.Description != want.Description {
+				c.fields = append(c.fields, field{"description", m.Description, want.Description})
+				c.args = append(c.args, "--description", want.Description)
+			}
+			if m.Filter != want.Filter {
+				c.fields = append(c.fields, field{"filter", m.Filter, want.Filter})
+				c.args = append(c.args, "--filter", want.Filter)
+			}
+			if len(c.fields) > 0 {
+				updates = append(updates, c)
+			}
+		}
+		if prune {
+			for _, m := range existing {
+				if _, ok := s.GroupMappings[m.Name]; !ok {
+					deletes = append(deletes, change{op: "-", kind: "group mapping", name: m.Name, args: []string{"iam", "group-mapping", "delete", m.ID, "--force"}})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(deletes, func(i, j int) bool {
+		return deletes[i].kind < deletes[j].kind || deletes[i].kind == deletes[j].kind && deletes[i].name < deletes[j].name
+	})
+	return append(append(creates, updates...), deletes...), nil
+}
+
+func (c change) print(w io.Writer) {
+	fmt.Fprintf(w, "%s %s \"%s\"\n", c.op, c.kind, c.name)
+	for _, f := range c.fields {
+		fmt.Fprintf(w, "    %s: %q -> %q\n", f.name, f.from, f.to)
+	}
+}
+
+// verbs are the verbs of the ops of changes.
+var verbs = map[string]string{"+": "create", "~": "update", "-": "delete"}
+
+// action is the change, for --dry-run, with the fields which an update changes.
+func (c change) action() dryrun.Action {
+	a := dryrun.Action{Verb: verbs[c.op], Resource: c.kind, Name: c.name}
+	var fields []string
+	for _, f := range c.fields {
+		fields = append(fields, fmt.Sprintf("%s from %q to %q", f.name, f.from, f.to))
+	}
+	if len(fields) > 0 {
+		a.Detail = "changing " + strings.Join(fields, ", ")
+	}
+	return a
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "wizard [pool]",
+		Short: "Set up workload identity with an identity pool.",
+		Long:  "Set up workload identity for OAuth clients: create the OIDC identity provider, unless it exists, with the keys found from its issuer's discovery document, an identity pool whose filter only accepts tokens with the given claims, and the pool's role bindings, and then print the Kafka client properties to authenticate as the pool with client credentials. Values which aren't passed as flags are asked for when run interactively, and the plan is printed before anything is changed.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runWizard,
+		Example: `confluent identity-pool wizard
+confluent identity-pool wizard orders-app --provider okta --issuer-uri https://example.okta.com/oauth2/default --claim aud=kafka --claim sub=0oa1b2c3d4 --role DeveloperWrite --environment env-123456 --cluster lkc-123456 --resource Topic:orders --yes`,
+	}
+
+	cmd.Flags().String("provider", "", "Name or ID of the identity provider. It's created if no provider has the name.")
+	cmd.Flags().String("issuer-uri", "", "Issuer URI of the identity provider, to create it or to find its token endpoint.")
+	cmd.Flags().String("jwks-uri", "", "JWKS URI of the identity provider. Defaults to the one in the issuer's discovery document.")
+	cmd.Flags().String("identity-claim", "claims.sub", "Claim which identifies the principal of a token.")
+	cmd.Flags().StringToString("claim", nil, `Claims which tokens must have to be accepted by the pool, as "<claim>=<value>" pairs, such as aud=kafka.`)
+	cmd.Flags().String("filter", "", "CEL filter of the pool, instead of building one from --claim.")
+	cmd.Flags().StringSlice("role", nil, "Roles to bind to the pool, such as DeveloperRead.")
+	cmd.Flags().String("environment", "", "Environment to bind the roles in. Defaults to the organization.")
+	cmd.Flags().String("cluster", "", "Kafka cluster to bind the roles in, which is also used in the client properties.")
+	cmd.Flags().String("resource", "", `Resource in the Kafka cluster to bind the roles to, such as "Topic:orders".`)
+	cmd.Flags().Bool("prefix", false, "Bind the roles to every resource whose name starts with the name of --resource.")
+	dryrun.AddFlag(&cmd, "Print the plan without changing anything.")
+	cmd.Flags().Bool("force", false, "Skip the confirmation prompt.")
+	cobra.CheckErr(cmd.Flags().MarkDeprecated("force", "use --yes instead"))
+	output.AddFlag(&cmd, "Format of the steps which were done and the client properties")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("claim", "filter")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "yes")
+
+	completion.Add(&cmd, "confluent-identity_pool-wizard")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-identity_pool-wizard"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+func runWizard(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	w := &wizard{}
+	if len(args) > 0 {
+		w.pool = args[0]
+	}
+
+	var err error
+	w.provider, err = cmd.Flags().GetString("provider")
+	cobra.CheckErr(err)
+
+	w.issuer, err = cmd.Flags().GetString("issuer-uri")
+	cobra.CheckErr(err)
+
+	w.jwksURI, err = cmd.Flags().GetString("jwks-uri")
+	cobra.CheckErr(err)
+
+	w.identityClaim, err = cmd.Flags().GetString("identity-claim")
+	cobra.CheckErr(err)
+
+	claims, err := cmd.Flags().GetStringToString("claim")
+	cobra.CheckErr(err)
+
+	w.filter, err = cmd.Flags().GetString("filter")
+	cobra.CheckErr(err)
+
+	w.roles, err = cmd.Flags().GetStringSlice("role")
+	cobra.CheckErr(err)
+
+	w.environment, err = cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	w.cluster, err = cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	w.resource, err = cmd.Flags().GetString("resource")
+	cobra.CheckErr(err)
+
+	w.prefix, err = cmd.Flags().GetBool("prefix")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	p := prompt.New(cmd)
+	if err := p.Value(&w.provider, "provider", "Name or ID of the identity provider (a new one is created if none has the name)", true); err != nil {
+		return err
+	}
+	provider, err := w.findProvider()
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		if err := p.Value(&w.issuer, "issuer-uri", "Issuer URI of the new identity provider, e.g. https://login.microsoftonline.com/<tenant>/v2.0", true); err != nil {
+			return err
+		}
+	}
+	if err := p.Value(&w.pool, "pool", "Name of the identity pool", true); err != nil {
+		return err
+	}
+	if w.filter == "" && len(claims) == 0 {
+		var answer string
+		if err := p.Value(&answer, "claim", `Claims which tokens must have, as "<claim>=<value>" pairs separated by commas, e.g. aud=kafka,sub=<client id>`, true); err != nil {
+			return fmt.Errorf(`%w, pass either "claim" or "filter"`, err)
+		}
+		if claims, err = parseClaims(answer); err != nil {
+			return err
+		}
+	}
+	if len(w.roles) == 0 {
+		var answer string
+		if err := p.Value(&answer, "role", "Roles to bind to the pool, separated by commas (leave empty to bind none)", false); err != nil {
+			return err
+		}
+		for _, role := range strings.Split(answer, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				w.roles = append(w.roles, role)
+			}
+		}
+	}
+	if len(w.roles) > 0 {
+		for _, q := range []struct {
+			value    *string
+			flag     string
+			question string
+		}{
+			{&w.environment, "environment", "Environment to bind the roles in (leave empty for the organization)"},
+			{&w.cluster, "cluster", "Kafka cluster to bind the roles in (leave empty for the environment)"},
+			{&w.resource, "resource", "Resource to bind the roles to, e.g. Topic:orders (leave empty for the cluster)"},
+		} {
+			if err := p.Value(q.value, q.flag, q.question, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if w.filter == "" {
+		w.filter = filter(claims)
+	}
+	if w.resource != "" && w.cluster == "" {
+		return exitcode.Invalid("--resource requires --cluster")
+	}
+	if w.cluster != "" && w.environment == "" {
+		return exitcode.Invalid("--cluster requires --environment")
+	}
+
+	if w.issuer != "" {
+		// The token endpoint is only used in the example, so it's left out if the issuer can't be discovered, unless
+		// the provider is created, which needs its keys.
+		w.oidc, err = discover(w.issuer)
+		if err != nil && provider == nil && w.jwksURI == "" {
+			return fmt.Errorf("%w, pass --jwks-uri", err)
+		}
+		if w.jwksURI == "" {
+			w.jwksURI = w.oidc.JWKSURI
+		}
+	}
+
+	out := output.Progress(cmd)
+	steps, err := w.plan(out, provider)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, s := range steps {
+			plan.Actions = append(plan.Actions, s.action)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	var done dryrun.Plan
+	if len(steps) == 0 {
+		fmt.Fprintf(out, "The identity pool \"%s\" is already set up.\n", w.pool)
+	} else {
+		fmt.Fprintln(out, "Plan:")
+		for i, s := range steps {
+			fmt.Fprintf(out, "  %d. %s\n", i+1, s.description())
+		}
+
+		if !force {
+			ok, err := p.Confirm("Set up the identity pool?")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Not setting up the identity pool.")
+				return dryrun.PrintApplied(cmd, dryrun.Plan{})
+			}
+		}
+
+		// Each step depends on the ones before it, so the setup stops at the first failure. Re-running it skips the
+		// provider and pool which were created.
+		for i, s := range steps {
+			if err := s.run(); err != nil {
+				if errors.Is(err, interrupt.ErrInterrupted) {
+					err = fmt.Errorf("the setup was interrupted at step %d, and can be re-run to finish it: %w", i+1, err)
+				} else {
+					err = fmt.Errorf("step %d failed, so the setup stopped: %w", i+1, err)
+				}
+				return errors.Join(err, dryrun.PrintApplied(cmd, done))
+			}
+			fmt.Fprintf(out, "Done: %s\n", s.description())
+			done.Actions = append(done.Actions, s.action)
+		}
+	}
+
+	var bootstrap string
+	if w.cluster != "" {
+		var cluster cli.KafkaCluster
+		if err := cli.JSON(&cluster, append([]string{"kafka", "cluster", "describe", w.cluster}, cli.ClusterFlags("", w.environment)...)...); err != nil {
+			return err
+		}
+		bootstrap = strings.TrimPrefix(cluster.Endpoint, "SASL_SSL://")
+	}
+
+	actions := done.Actions
+	if actions == nil {
+		actions = []dryrun.Action{}
+	}
+	properties := w.clientProperties(bootstrap)
+	r := result{Actions: actions, IdentityPoolID: w.poolID, TokenEndpoint: w.oidc.TokenEndpoint, ClientProperties: map[string]string{}}
+	for _, p := range properties {
+		r.ClientProperties[p.key] = p.value
+	}
+	return output.Print(cmd.OutOrStdout(), format, r, func() { w.printExample(out, properties) })
+}
+
+// result is what --output json or yaml prints: the steps which were done, and what the clients authenticate with.
+type result struct {
+	Actions          []dryrun.Action   `json:"actions"`
+	IdentityPoolID   string            `json:"identity_pool_id"`
+	TokenEndpoint    string            `json:"token_endpoint,omitempty"`
+	ClientProperties map[string]string `json:"client_properties"`
+}
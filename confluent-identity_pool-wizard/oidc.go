@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// claimPath matches a claim which can be written as a field of "claims" in a filter, such as "aud" or "ext.team".
+var claimPath = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// openIDConfiguration is the part of an OIDC issuer's discovery document which the wizard needs.
+type openIDConfiguration struct {
+	JWKSURI       string `json:"jwks_uri"`
+	TokenEndpoint string `json:"token_endpoint"`
+}
+
+// discover reads the discovery document of an OIDC issuer, which has the URLs of its keys and of its token endpoint.
+func discover(issuer string) (openIDConfiguration, error) {
+	url := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Get(url)
+	if err != nil {
+		return openIDConfiguration{}, fmt.Errorf("failed to read the OIDC discovery document of %s: %w", issuer, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return openIDConfiguration{}, fmt.Errorf("failed to read the OIDC discovery document %s: %s", url, res.Status)
+	}
+
+	var c openIDConfiguration
+	if err := json.NewDecoder(res.Body).Decode(&c); err != nil {
+		return openIDConfiguration{}, fmt.Errorf("failed to decode the OIDC discovery document %s: %w", url, err)
+	}
+	return c, nil
+}
+
+// filter builds the CEL expression of an identity pool which only accepts tokens with every one of the claims, e.g.
+// aud=kafka and ext.team=orders give:
+//
+//	claims.aud == "kafka" && claims.ext.team == "orders"
+//
+// Claims whose names can't be written as fields of "claims", such as those with dashes, are indexed instead.
+func filter(claims map[string]string) string {
+	keys := make([]string, 0, len(claims))
+	for key := range claims {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		claim := "claims." + key
+		if !claimPath.MatchString(key) {
+			claim = "claims[" + strconv.Quote(key) + "]"
+		}
+		conditions[i] = claim + " == " + strconv.Quote(claims[key])
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// parseClaims parses claims typed in at the prompt, as "<claim>=<value>" pairs separated by commas.
+func parseClaims(s string) (map[string]string, error) {
+	claims := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf(`invalid claim "%s", expected a "<claim>=<value>" pair`, pair)
+		}
+		claims[strings.TrimPrefix(key, "claims.")] = value
+	}
+	return claims, nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+)
+
+// A step of the wizard, which is printed as part of the plan before anything is changed.
+type step struct {
+	action dryrun.Action
+	run    func() error
+}
+
+// description describes the step as a sentence, such as `Create identity pool "orders-app", identified by sub, ...`.
+func (s step) description() string {
+	a := s.action.String()
+	return strings.ToUpper(a[:1]) + a[1:] + "."
+}
+
+// A wizard sets up an identity provider, if it doesn't exist yet, an identity pool of it, and the pool's role bindings.
+type wizard struct {
+	// provider is the name or ID of the identity provider, and providerID its ID, once it's found or created.
+	provider    string
+	providerID  string
+	issuer      string
+	jwksURI     string
+	oidc        openIDConfiguration
+	newProvider bool
+
+	pool          string
+	poolID        string
+	identityClaim string
+	filter        string
+
+	roles       []string
+	environment string
+	cluster     string
+	resource    string
+	prefix      bool
+}
+
+// identityProvider is a provider in the output of "confluent iam provider list".
+type identityProvider struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Issuer string `json:"issuer"`
+}
+
+// identityPool is a pool in the output of "confluent iam pool list".
+type identityPool struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Filter string `json:"filter"`
+}
+
+// findProvider looks up the identity provider by name or ID.
+func (w *wizard) findProvider() (*identityProvider, error) {
+	var providers []identityProvider
+	if err := cli.JSON(&providers, "iam", "provider", "list"); err != nil {
+		return nil, err
+	}
+	for i, p := range providers {
+		if p.ID == w.provider || p.Name == w.provider {
+			return &providers[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// plan returns the steps of the setup, skipping the provider, if it was found, and the pool if it already exists, so
+// that the wizard can be re-run to add role bindings.
+func (w *wizard) plan(out io.Writer, provider *identityProvider) ([]step, error) {
+	var steps []step
+	if provider == nil {
+		if w.issuer == "" {
+			return nil, fmt.Errorf(`identity provider "%s" not found, pass --issuer-uri to create it`, w.provider)
+		}
+		w.newProvider = true
+		steps = append(steps, step{
+			action: dryrun.Action{Verb: "create", Resource: "identity provider", Name: w.provider, Detail: fmt.Sprintf("for the issuer %s, with the keys at %s", w.issuer, w.jwksURI)},
+			run:    w.createProvider,
+		})
+	} else {
+		w.providerID = provider.ID
+		if w.issuer == "" {
+			w.issuer = provider.Issuer
+		}
+	}
+
+	poolExists := false
+	if !w.newProvider {
+		var pools []identityPool
+		if err := cli.JSON(&pools, "iam", "pool", "list", "--provider", w.providerID); err != nil {
+			return nil, err
+		}
+		for _, p := range pools {
+			if p.Name == w.pool {
+				poolExists = true
+				w.poolID = p.ID
+				if p.Filter != w.filter {
+					fmt.Fprintf(out, "The identity pool \"%s\" already exists with the filter %s, which isn't changed.\n", w.pool, p.Filter)
+				}
+			}
+		}
+	}
+	if !poolExists {
+		steps = append(steps, step{
+			action: dryrun.Action{Verb: "create", Resource: "identity pool", Name: w.pool, Detail: fmt.Sprintf("identified by %s, for tokens matching %s", w.identityClaim, w.filter)},
+			run:    w.createPool,
+		})
+	}
+
+	for _, role := range w.roles {
+		role := role
+		steps = append(steps, step{
+			action: dryrun.Action{Verb: "bind", Resource: "role", Name: role, Detail: fmt.Sprintf(`to identity pool "%s" on %s`, w.pool, w.scope())},
+			run: func() error {
+				_, err := cli.Run(append([]string{"iam", "rbac", "role-binding", "create", "--principal", "User:" + w.poolID, "--role", role}, w.bindingFlags()...)...)
+				return err
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+func (w *wizard) createProvider() error {
+	var created struct {
+		ID string `json:"id"`
+	}
+	args := []string{"iam", "provider", "create", w.provider, "--issuer-uri", w.issuer, "--jwks-uri", w.jwksURI, "--description", "Created by confluent identity-pool wizard."}
+	if err := cli.JSON(&created, args...); err != nil {
+		return err
+	}
+	w.providerID = created.ID
+	return nil
+}
+
+func (w *wizard) createPool() error {
+	var created struct {
+		ID string `json:"id"`
+	}
+	args := []string{"iam", "pool", "create", w.pool, "--provider", w.providerID, "--identity-claim", w.identityClaim, "--filter", w.filter, "--description", "Created by confluent identity-pool wizard."}
+	if err := cli.JSON(&created, args...); err != nil {
+		return err
+	}
+	w.poolID = created.ID
+	return nil
+}
+
+// bindingFlags scopes a role binding to the environment, cluster, and resource, where an empty scope is the organization.
+func (w *wizard) bindingFlags() []string {
+	var args []string
+	if w.environment != "" {
+		args = append(args, "--environment", w.environment)
+	}
+	if w.cluster != "" {
+		args = append(args, "--cloud-cluster", w.cluster)
+		if w.resource != "" {
+			args = append(args, "--kafka-cluster", w.cluster)
+		}
+	}
+	if w.resource != "" {
+		args = append(args, "--resource", w.resource)
+		if w.prefix {
+			args = append(args, "--prefix")
+		}
+	}
+	return args
+}
+
+func (w *wizard) scope() string {
+	if w.environment == "" {
+		return "the organization"
+	}
+	parts := []string{w.environment}
+	if w.cluster != "" {
+		parts = append(parts, w.cluster)
+	}
+	if w.resource != "" {
+		resource := w.resource
+		if w.prefix {
+			resource += "*"
+		}
+		parts = append(parts, resource)
+	}
+	return strings.Join(parts, "/")
+}
+
+// A clientProperty is a Kafka client property to authenticate as the pool with.
+type clientProperty struct {
+	key   string
+	value string
+}
+
+// clientProperties are the Kafka client properties to authenticate as the pool with client credentials, in the order
+// in which they're printed, with placeholders for what isn't known.
+func (w *wizard) clientProperties(bootstrap string) []clientProperty {
+	tokenEndpoint := w.oidc.TokenEndpoint
+	if tokenEndpoint == "" {
+		tokenEndpoint = "<token endpoint>"
+	}
+	cluster := w.cluster
+	if cluster == "" {
+		cluster = "<lkc-id>"
+	}
+	if bootstrap == "" {
+		bootstrap = "<bootstrap server>"
+	}
+
+	return []clientProperty{
+		{"bootstrap.servers", bootstrap},
+		{"security.protocol", "SASL_SSL"},
+		{"sasl.mechanism", "OAUTHBEARER"},
+		{"sasl.login.callback.handler.class", "org.apache.kafka.common.security.oauthbearer.secured.OAuthBearerLoginCallbackHandler"},
+		{"sasl.oauthbearer.token.endpoint.url", tokenEndpoint},
+		{"sasl.jaas.config", fmt.Sprintf("org.apache.kafka.common.security.oauthbearer.OAuthBearerLoginModule required clientId='<client id>' clientSecret='<client secret>' scope='<scope>' extension_logicalCluster='%s' extension_identityPoolId='%s';", cluster, w.poolID)},
+	}
+}
+
+// printExample prints the Kafka client properties, and a curl command to check that the IdP issues tokens which the
+// pool's filter accepts.
+func (w *wizard) printExample(out io.Writer, properties []clientProperty) {
+	tokenEndpoint := w.oidc.TokenEndpoint
+	if tokenEndpoint == "" {
+		tokenEndpoint = "<token endpoint>"
+	}
+
+	fmt.Fprintln(out, "\nKafka client properties:")
+	for _, p := range properties {
+		fmt.Fprintf(out, "%s=%s\n", p.key, p.value)
+	}
+
+	fmt.Fprintln(out, "\nTo fetch a token and check its claims against the filter:")
+	fmt.Fprintf(out, "curl -s -X POST %s -d grant_type=client_credentials -d client_id='<client id>' -d client_secret='<client secret>' -d scope='<scope>'\n", tokenEndpoint)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// binding is a role binding of a user, normalized so that bindings from the CSV file and the organization compare
+// equal. The principal is left out, since the users of the CSV file don't have one until they're invited.
+type binding struct {
+	role           string
+	environment    string
+	cloudCluster   string
+	logicalCluster string
+	resource       string
+	prefix         bool
+}
+
+// listedBinding returns the binding of a role binding which the confluent CLI listed.
+func listedBinding(b cli.RoleBinding) binding {
+	n := binding{
+		role:           b.Role,
+		environment:    b.Environment,
+		cloudCluster:   b.CloudCluster,
+		logicalCluster: b.LogicalCluster,
+		prefix:         strings.EqualFold(b.PatternType, "PREFIXED"),
+	}
+	if b.ResourceType != "" {
+		n.resource = b.ResourceType + ":" + b.Name
+	}
+	return n.normalize()
+}
+
+// Bindings to resources in a cluster list it as both the cloud and logical cluster.
+func (b binding) normalize() binding {
+	if b.logicalCluster == b.cloudCluster {
+		b.logicalCluster = ""
+	}
+	return b
+}
+
+// parseScope parses a scope of the CSV file, which is "organization", or the path of an environment, optionally
+// followed by a cluster, and a resource of the cluster, which ends with * to match its prefix, e.g.
+// "env-123456/lkc-123456/Topic:orders*".
+func parseScope(role, s string) (binding, error) {
+	b := binding{role: role}
+	s = strings.TrimSpace(s)
+	if s == "" || s == "organization" {
+		return b, nil
+	}
+
+	parts := strings.Split(s, "/")
+	if !strings.HasPrefix(parts[0], "env-") {
+		return binding{}, fmt.Errorf(`invalid scope "%s", expected "organization" or a path starting with an environment ID, e.g. "env-123456/lkc-123456/Topic:orders"`, s)
+	}
+	b.environment = parts[0]
+	parts = parts[1:]
+
+	if n := len(parts); n > 0 && strings.Contains(parts[n-1], ":") {
+		b.resource, b.prefix = strings.CutSuffix(parts[n-1], "*")
+		parts = parts[:n-1]
+		if len(parts) == 0 {
+			return binding{}, fmt.Errorf(`invalid scope "%s", the resource must be in a cluster`, s)
+		}
+	}
+	switch {
+	case len(parts) > 1:
+		return binding{}, fmt.Errorf(`invalid scope "%s", expected at most one cluster`, s)
+	case len(parts) == 1 && (parts[0] == "" || strings.Contains(parts[0], ":")):
+		return binding{}, fmt.Errorf(`invalid scope "%s"`, s)
+	case len(parts) == 1:
+		b.cloudCluster = parts[0]
+	}
+	return b, nil
+}
+
+func (b binding) String() string {
+	parts := []string{"organization"}
+	if b.environment != "" {
+		parts = []string{b.environment}
+	}
+	for _, p := range []string{b.cloudCluster, b.logicalCluster} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if b.resource != "" {
+		resource := b.resource
+		if b.prefix {
+			resource += "*"
+		}
+		parts = append(parts, resource)
+	}
+	return fmt.Sprintf("%s %s", b.role, strings.Join(parts, "/"))
+}
+
+// args returns the flags of "confluent iam rbac role-binding create" for the binding of the principal.
+func (b binding) args(principal string) []string {
+	args := []string{"--principal", principal, "--role", b.role}
+	if b.environment != "" {
+		args = append(args, "--environment", b.environment)
+	}
+	if b.cloudCluster != "" {
+		args = append(args, "--cloud-cluster", b.cloudCluster)
+	}
+
+	logical := b.logicalCluster
+	if logical == "" && b.resource != "" {
+		logical = b.cloudCluster
+	}
+	if strings.HasPrefix(logical, "lsrc-") {
+		args = append(args, "--schema-registry-cluster", logical)
+	} else if logical != "" {
+		args = append(args, "--kafka-cluster", logical)
+	}
+
+	if b.resource != "" {
+		args = append(args, "--resource", b.resource)
+		if b.prefix {
+			args = append(args, "--prefix")
+		}
+	}
+	return args
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// An invitee is a user of the CSV file, with the role bindings of all of their rows.
+type invitee struct {
+	email    string
+	bindings []binding
+}
+
+// The statuses of an invitee.
+const (
+	statusInvited        = "invited"
+	statusAlreadyInvited = "already invited"
+	statusAlreadyUser    = "already a user"
+	statusFailed         = "failed"
+)
+
+// An outcome is what was done for an invitee: whether they were invited, and how many of their role bindings were
+// created, or already existed.
+type outcome struct {
+	Email    string `json:"email"`
+	User     string `json:"user,omitempty"`
+	Status   string `json:"status"`
+	Created  int    `json:"created_role_bindings"`
+	Existing int    `json:"existing_role_bindings"`
+	Error    string `json:"error,omitempty"`
+}
+
+// listedUser is a user in the output of "confluent iam user list", or an invitation in the output of
+// "confluent iam user invitation list" and "create", whose user ID is the user which the invitation creates.
+type listedUser struct {
+	ID     string `json:"id"`
+	Email  string `json:"email"`
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+}
+
+// An inviter invites the users of the CSV file who aren't users or invited yet, and creates their role bindings which
+// don't exist yet, so that running it again only does what's left.
+type inviter struct {
+	// plan is what would be done, with --dry-run, in which case nothing is done.
+	plan *dryrun.Plan
+	// progress is where how many invitees are done is shown.
+	progress io.Writer
+
+	// users and invited are the IDs of the users of the organization, and of the users who were invited, by email.
+	users   map[string]string
+	invited map[string]string
+}
+
+func newInviter(plan *dryrun.Plan) (*inviter, error) {
+	in := &inviter{plan: plan, users: map[string]string{}, invited: map[string]string{}}
+
+	var users []listedUser
+	if err := cli.JSON(&users, "iam", "user", "list"); err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		in.users[strings.ToLower(u.Email)] = u.ID
+	}
+
+	var invitations []listedUser
+	if err := cli.JSON(&invitations, "iam", "user", "invitation", "list"); err != nil {
+		return nil, err
+	}
+	for _, i := range invitations {
+		// An expired invitation can't be accepted anymore, so the user is invited again.
+		if strings.Contains(strings.ToUpper(i.Status), "EXPIRED") || i.UserID == "" {
+			continue
+		}
+		in.invited[strings.ToLower(i.Email)] = i.UserID
+	}
+	return in, nil
+}
+
+func (in *inviter) invite(i invitee) (outcome, error) {
+	o := outcome{Email: i.email}
+	key := strings.ToLower(i.email)
+	switch {
+	case in.users[key] != "":
+		o.User, o.Status = in.users[key], statusAlreadyUser
+	case in.invited[key] != "":
+		o.User, o.Status = in.invited[key], statusAlreadyInvited
+	case in.plan != nil:
+		// A user who isn't invited yet has no role bindings.
+		in.plan.Add("invite", "user", i.email)
+		for _, b := range i.bindings {
+			in.plan.AddDetail("bind", "user", i.email, "to "+b.String())
+		}
+		return o, nil
+	default:
+		var created listedUser
+		if err := cli.JSON(&created, "iam", "user", "invitation", "create", i.email); err != nil {
+			return in.fail(o, fmt.Errorf("failed to invite %s: %w", i.email, err))
+		}
+		if created.UserID == "" {
+			return in.fail(o, fmt.Errorf("the invitation of %s has no user ID", i.email))
+		}
+		o.User, o.Status = created.UserID, statusInvited
+		in.invited[key] = created.UserID
+	}
+
+	if len(i.bindings) == 0 {
+		return o, nil
+	}
+
+	principal := "User:" + o.User
+	var listed []cli.RoleBinding
+	if err := cli.JSON(&listed, "iam", "rbac", "role-binding", "list", "--principal", principal, "--inclusive"); err != nil {
+		return in.fail(o, fmt.Errorf("failed to list the role bindings of %s: %w", i.email, err))
+	}
+	existing := map[binding]bool{}
+	for _, b := range listed {
+		existing[listedBinding(b)] = true
+	}
+
+	for _, b := range i.bindings {
+		if existing[b] {
+			o.Existing++
+			continue
+		}
+		if in.plan != nil {
+			in.plan.AddDetail("bind", "user", i.email, "to "+b.String())
+			continue
+		}
+		if _, err := cli.Run(append([]string{"iam", "rbac", "role-binding", "create"}, b.args(principal)...)...); err != nil {
+			return in.fail(o, fmt.Errorf("failed to bind %s to %s: %w", i.email, b, err))
+		}
+		o.Created++
+	}
+	return o, nil
+}
+
+// fail marks the outcome as failed, keeping what was done before the failure, such as the invitation.
+func (in *inviter) fail(o outcome, err error) (outcome, error) {
+	if o.Status != statusInvited {
+		o.Status = statusFailed
+	}
+	o.Error = err.Error()
+	return o, err
+}
+
+// inviteAll invites each invitee, and carries on past the ones which fail, so that running it again retries only them.
+func (in *inviter) inviteAll(invitees []invitee) ([]outcome, error) {
+	var outcomes []outcome
+	var errs []error
+	bar := progress.NewBar(in.progress, "Users invited", len(invitees))
+	defer bar.Done()
+	for _, i := range invitees {
+		o, err := in.invite(i)
+		outcomes = append(outcomes, o)
+		errs = append(errs, err)
+		bar.Add(1)
+	}
+	return outcomes, errors.Join(errs...)
+}
+
+// readInvitees reads the CSV file of invitees, whose rows with the same email are merged.
+func readInvitees(path string) ([]invitee, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	invitees, err := readCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(invitees) == 0 {
+		return nil, fmt.Errorf("%s has no users", path)
+	}
+	return invitees, nil
+}
+
+// readCSV reads a CSV file with a header, and a row per role binding of a user, with their email in the "email" column,
+// and optionally the role and the scope to bind them to in the "role" and "scope" columns. A row without a role only
+// invites the user.
+func readCSV(r io.Reader) ([]invitee, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	emailColumn, roleColumn, scopeColumn := -1, -1, -1
+	for i, column := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "email":
+			emailColumn = i
+		case "role":
+			roleColumn = i
+		case "scope":
+			scopeColumn = i
+		}
+	}
+	if emailColumn < 0 {
+		return nil, fmt.Errorf(`the header has no "email" column`)
+	}
+
+	field := func(record []string, column int) string {
+		if column < 0 || column >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[column])
+	}
+
+	var invitees []invitee
+	index := map[string]int{}
+	for line, record := range records[1:] {
+		email := field(record, emailColumn)
+		if email == "" {
+			return nil, fmt.Errorf("line %d: the email is empty", line+2)
+		}
+		address, err := mail.ParseAddress(email)
+		if err != nil {
+			return nil, fmt.Errorf(`line %d: invalid email "%s"`, line+2, email)
+		}
+
+		i, ok := index[strings.ToLower(address.Address)]
+		if !ok {
+			i = len(invitees)
+			index[strings.ToLower(address.Address)] = i
+			invitees = append(invitees, invitee{email: address.Address})
+		}
+
+		role, scope := field(record, roleColumn), field(record, scopeColumn)
+		if role == "" {
+			if scope != "" {
+				return nil, fmt.Errorf("line %d: the scope has no role", line+2)
+			}
+			continue
+		}
+		b, err := parseScope(role, scope)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		if !slices.Contains(invitees[i].bindings, b) {
+			invitees[i].bindings = append(invitees[i].bindings, b)
+		}
+	}
+	return invitees, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "bulk <csv>",
+		Short: "Invite users and bind their roles in bulk.",
+		Long:  "Invite the users of a CSV file to the organization, and create their role bindings, from rows of an email, a role, and a scope. Users who are already in the organization or invited aren't invited again, and role bindings which already exist aren't created again, so the file can be applied again after a failure, or as the team grows.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  bulk,
+		Example: `confluent invite bulk team.csv --dry-run
+confluent invite bulk team.csv --output json > invited.json`,
+	}
+
+	dryrun.AddFlag(&cmd, "Print who would be invited and which role bindings would be created without changing anything.")
+	output.AddFlag(&cmd, "Format of the summary")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-invite-bulk")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-invite-bulk"))
+
+	exitcode.Execute(&cmd)
+}
+
+func bulk(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	invitees, err := readInvitees(args[0])
+	if err != nil {
+		return err
+	}
+
+	var plan *dryrun.Plan
+	if dryRun {
+		plan = &dryrun.Plan{}
+	}
+	in, err := newInviter(plan)
+	if err != nil {
+		return err
+	}
+	in.progress = cmd.ErrOrStderr()
+	outcomes, err := in.inviteAll(invitees)
+	if dryRun {
+		if err != nil {
+			return err
+		}
+		return dryrun.Print(cmd, *plan)
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, outcomes, func() { print(out, outcomes) }); err != nil {
+		return err
+	}
+	return err
+}
+
+func print(w io.Writer, outcomes []outcome) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Email\tUser\tStatus\tRole Bindings")
+	statuses := map[string]int{}
+	created, existing, failed := 0, 0, 0
+	for _, o := range outcomes {
+		user := o.User
+		if user == "" {
+			user = "-"
+		}
+		bindings := "-"
+		if o.Created > 0 || o.Existing > 0 {
+			bindings = fmt.Sprintf("%d created, %d existing", o.Created, o.Existing)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.Email, user, o.Status, bindings)
+		statuses[o.Status]++
+		created += o.Created
+		existing += o.Existing
+		if o.Error != "" {
+			failed++
+		}
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Invited %d users, and created %d role bindings. %d users were already invited, %d were already users, and %d role bindings already existed.\n", statuses[statusInvited], created, statuses[statusAlreadyInvited], statuses[statusAlreadyUser], existing)
+	if failed > 0 {
+		fmt.Fprintf(w, "%d users failed, run the command again to retry them.\n", failed)
+	}
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// avroSchema is a parsed Avro schema, with just enough to decode the records of Avro files, and to convert records
+// into the JSON encoding of Avro which the CLI produces from.
+type avroSchema struct {
+	// kind is a primitive type, or record, enum, array, map, fixed, or union.
+	kind     string
+	name     string
+	fields   []avroField
+	items    *avroSchema
+	values   *avroSchema
+	branches []*avroSchema
+	symbols  []string
+	size     int
+}
+
+type avroField struct {
+	name       string
+	schema     *avroSchema
+	def        any
+	hasDefault bool
+}
+
+var avroPrimitives = map[string]bool{"null": true, "boolean": true, "int": true, "long": true, "float": true, "double": true, "bytes": true, "string": true}
+
+func parseAvroSchema(s string) (*avroSchema, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	return avroParser{names: map[string]*avroSchema{}}.parse(v, "")
+}
+
+// avroParser parses schemas, and keeps the named types which were defined, so that later ones can refer to them.
+type avroParser struct {
+	names map[string]*avroSchema
+}
+
+func (p avroParser) parse(v any, namespace string) (*avroSchema, error) {
+	switch v := v.(type) {
+	case string:
+		if avroPrimitives[v] {
+			return &avroSchema{kind: v}, nil
+		}
+		if s, ok := p.names[fullName(v, namespace)]; ok {
+			return s, nil
+		}
+		if s, ok := p.names[v]; ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf(`unknown Avro type "%s"`, v)
+	case []any:
+		s := &avroSchema{kind: "union"}
+		for _, b := range v {
+			branch, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.branches = append(s.branches, branch)
+		}
+		return s, nil
+	case map[string]any:
+		kind, _ := v["type"].(string)
+		if ns, ok := v["namespace"].(string); ok {
+			namespace = ns
+		}
+		switch kind {
+		case "record", "error", "enum", "fixed":
+			name, _ := v["name"].(string)
+			if name == "" {
+				return nil, fmt.Errorf("an Avro %s has no name", kind)
+			}
+			s := &avroSchema{kind: kind, name: fullName(name, namespace)}
+			if kind == "error" {
+				s.kind = "record"
+			}
+			// The type is named before its fields are parsed, so that they can refer to it.
+			p.names[s.name] = s
+			if i := strings.LastIndex(s.name, "."); i >= 0 {
+				namespace = s.name[:i]
+			}
+			return s, p.parseNamed(s, v, namespace)
+		case "array":
+			items, err := p.parse(v["items"], namespace)
+			return &avroSchema{kind: kind, items: items}, err
+		case "map":
+			values, err := p.parse(v["values"], namespace)
+			return &avroSchema{kind: kind, values: values}, err
+		default:
+			// A primitive type, which may have a logical type, such as {"type": "long", "logicalType": "timestamp-millis"}.
+			return p.parse(v["type"], namespace)
+		}
+	default:
+		return nil, fmt.Errorf("invalid Avro schema %v", v)
+	}
+}
+
+func (p avroParser) parseNamed(s *avroSchema, v map[string]any, namespace string) error {
+	switch s.kind {
+	case "record":
+		fields, _ := v["fields"].([]any)
+		for _, f := range fields {
+			f, ok := f.(map[string]any)
+			if !ok {
+				return fmt.Errorf(`invalid field of Avro record "%s"`, s.name)
+			}
+			name, _ := f["name"].(string)
+			schema, err := p.parse(f["type"], namespace)
+			if err != nil {
+				return fmt.Errorf(`invalid type of field "%s" of Avro record "%s": %w`, name, s.name, err)
+			}
+			def, hasDefault := f["default"]
+			s.fields = append(s.fields, avroField{name: name, schema: schema, def: def, hasDefault: hasDefault})
+		}
+	case "enum":
+		symbols, _ := v["symbols"].([]any)
+		for _, symbol := range symbols {
+			symbol, _ := symbol.(string)
+			s.symbols = append(s.symbols, symbol)
+		}
+	case "fixed":
+		size, _ := v["size"].(json.Number)
+		n, err := size.Int64()
+		if err != nil {
+			return fmt.Errorf(`invalid size of Avro fixed "%s"`, s.name)
+		}
+		s.size = int(n)
+	}
+	return nil
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// typeName is the name of the schema as a branch of a union in the JSON encoding.
+func (s *avroSchema) typeName() string {
+	if s.name != "" {
+		return s.name
+	}
+	return s.kind
+}
+
+// avroJSON converts a value into the JSON encoding of Avro for the schema, in which the value of a union is wrapped in
+// an object keyed by its branch, such as {"string": "a"}, unless it's null. Strings are converted into the numbers and
+// booleans which the schema has, so that the values of CSV files can be produced, and records use the defaults of
+// fields which they don't have.
+func avroJSON(s *avroSchema, v any) (any, error) {
+	switch s.kind {
+	case "null":
+		if v == nil || v == "" {
+			return nil, nil
+		}
+	case "boolean":
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+	case "int", "long":
+		if n, ok := toNumber(v); ok {
+			if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case "float", "double":
+		if n, ok := toNumber(v); ok {
+			if f, err := strconv.ParseFloat(n, 64); err == nil {
+				return f, nil
+			}
+		}
+	case "string", "enum":
+		if v, ok := v.(string); ok && (s.kind == "string" || containsString(s.symbols, v)) {
+			return v, nil
+		}
+	case "bytes", "fixed":
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			// Bytes are encoded as a string of the code points of each byte.
+			runes := make([]rune, len(v))
+			for i, b := range v {
+				runes[i] = rune(b)
+			}
+			return string(runes), nil
+		}
+	case "array":
+		if items, ok := v.([]any); ok {
+			converted := make([]any, len(items))
+			for i, item := range items {
+				c, err := avroJSON(s.items, item)
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", i, err)
+				}
+				converted[i] = c
+			}
+			return converted, nil
+		}
+	case "map":
+		if values, ok := v.(map[string]any); ok {
+			converted := map[string]any{}
+			for key, value := range values {
+				c, err := avroJSON(s.values, value)
+				if err != nil {
+					return nil, fmt.Errorf("value %s: %w", key, err)
+				}
+				converted[key] = c
+			}
+			return converted, nil
+		}
+	case "record":
+		if values, ok := v.(map[string]any); ok {
+			return s.recordJSON(values)
+		}
+	case "union":
+		return s.unionJSON(v)
+	}
+	return nil, fmt.Errorf("%s isn't a valid %s", formatValue(v), s.typeName())
+}
+
+func (s *avroSchema) recordJSON(values map[string]any) (any, error) {
+	converted := map[string]any{}
+	for _, f := range s.fields {
+		value, ok := values[f.name]
+		if !ok {
+			if !f.hasDefault {
+				return nil, fmt.Errorf(`field "%s" of %s is missing`, f.name, s.name)
+			}
+			// The default of a union is of its first branch.
+			value = f.def
+			if f.schema.kind == "union" && len(f.schema.branches) > 0 {
+				c, err := avroJSON(f.schema.branches[0], value)
+				if err != nil {
+					return nil, fmt.Errorf(`invalid default of field "%s": %w`, f.name, err)
+				}
+				if f.schema.branches[0].kind != "null" {
+					c = map[string]any{f.schema.branches[0].typeName(): c}
+				}
+				converted[f.name] = c
+				continue
+			}
+		}
+		c, err := avroJSON(f.schema, value)
+		if err != nil {
+			return nil, fmt.Errorf(`field "%s": %w`, f.name, err)
+		}
+		converted[f.name] = c
+	}
+	return converted, nil
+}
+
+// unionJSON wraps the value in the first branch of the union which it's valid for. A value which is already wrapped in
+// one of the branches is kept.
+func (s *avroSchema) unionJSON(v any) (any, error) {
+	if wrapped, ok := v.(map[string]any); ok && len(wrapped) == 1 {
+		for _, b := range s.branches {
+			if inner, ok := wrapped[b.typeName()]; ok {
+				c, err := avroJSON(b, inner)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{b.typeName(): c}, nil
+			}
+		}
+	}
+
+	for _, b := range s.branches {
+		if b.kind == "null" {
+			if v == nil || v == "" {
+				return nil, nil
+			}
+			continue
+		}
+		if c, err := avroJSON(b, v); err == nil {
+			return map[string]any{b.typeName(): c}, nil
+		}
+	}
+	names := make([]string, len(s.branches))
+	for i, b := range s.branches {
+		names[i] = b.typeName()
+	}
+	return nil, fmt.Errorf("%s isn't a valid %s", formatValue(v), strings.Join(names, " or "))
+}
+
+func toNumber(v any) (string, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String(), true
+	case string:
+		return strings.TrimSpace(v), v != ""
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	}
+	return "", false
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValue(v any) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// avroMagic starts every Avro object container file.
+var avroMagic = []byte("Obj\x01")
+
+// readAvroFile decodes the records of an Avro object container file with the schema which the file was written with.
+func readAvroFile(r io.Reader) ([]any, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(b, avroMagic) {
+		return nil, fmt.Errorf("not an Avro object container file")
+	}
+	d := &avroDecoder{b: b, off: len(avroMagic)}
+
+	metadata := map[string]string{}
+	for {
+		count := d.blockCount()
+		if count == 0 || d.err != nil {
+			break
+		}
+		for ; count > 0; count-- {
+			key := string(d.bytes())
+			metadata[key] = string(d.bytes())
+		}
+	}
+	sync := d.next(16)
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid header: %w", d.err)
+	}
+
+	schema, err := parseAvroSchema(metadata["avro.schema"])
+	if err != nil {
+		return nil, err
+	}
+	codec := metadata["avro.codec"]
+	if codec != "" && codec != "null" && codec != "deflate" {
+		return nil, fmt.Errorf(`unsupported codec "%s", supported codecs: null, deflate`, codec)
+	}
+
+	var records []any
+	for d.off < len(d.b) {
+		count, size := d.long(), d.long()
+		block := d.next(int(size))
+		if !bytes.Equal(d.next(16), sync) && d.err == nil {
+			d.err = fmt.Errorf("invalid sync marker")
+		}
+		if d.err != nil {
+			return nil, fmt.Errorf("invalid block: %w", d.err)
+		}
+
+		if codec == "deflate" {
+			if block, err = io.ReadAll(flate.NewReader(bytes.NewReader(block))); err != nil {
+				return nil, fmt.Errorf("invalid block: %w", err)
+			}
+		}
+		bd := &avroDecoder{b: block}
+		for ; count > 0; count-- {
+			records = append(records, bd.value(schema))
+		}
+		if bd.err != nil {
+			return nil, fmt.Errorf("invalid record: %w", bd.err)
+		}
+	}
+	return records, nil
+}
+
+// avroDecoder reads the binary encoding of Avro. Like the decoder of the Kafka protocol, the first error is kept, and
+// later reads return zero values.
+type avroDecoder struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (d *avroDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.b) {
+		d.err = fmt.Errorf("truncated data")
+		return nil
+	}
+	b := d.b[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+// long reads a zigzag varint, which ints and longs are encoded as.
+func (d *avroDecoder) long() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b[d.off:])
+	if n <= 0 {
+		d.err = fmt.Errorf("invalid varint")
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+func (d *avroDecoder) bytes() []byte {
+	return d.next(int(d.long()))
+}
+
+// blockCount reads the count of a block of an array or map, which is negative if it's followed by the block's size.
+func (d *avroDecoder) blockCount() int64 {
+	count := d.long()
+	if count < 0 {
+		d.long()
+		count = -count
+	}
+	return count
+}
+
+func (d *avroDecoder) value(s *avroSchema) any {
+	switch s.kind {
+	case "null":
+		return nil
+	case "boolean":
+		b := d.next(1)
+		return b != nil && b[0] == 1
+	case "int", "long":
+		return d.long()
+	case "float":
+		if b := d.next(4); b != nil {
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		}
+	case "double":
+		if b := d.next(8); b != nil {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+	case "bytes":
+		return append([]byte(nil), d.bytes()...)
+	case "string":
+		return string(d.bytes())
+	case "fixed":
+		return append([]byte(nil), d.next(s.size)...)
+	case "enum":
+		if i := d.long(); i >= 0 && int(i) < len(s.symbols) {
+			return s.symbols[i]
+		}
+		d.fail("invalid enum index")
+	case "array":
+		items := []any{}
+		for count := d.blockCount(); count > 0 && d.err == nil; count = d.blockCount() {
+			for ; count > 0; count-- {
+				items = append(items, d.value(s.items))
+			}
+		}
+		return items
+	case "map":
+		values := map[string]any{}
+		for count := d.blockCount(); count > 0 && d.err == nil; count = d.blockCount() {
+			for ; count > 0; count-- {
+				key := string(d.bytes())
+				values[key] = d.value(s.values)
+			}
+		}
+		return values
+	case "record":
+		values := map[string]any{}
+		for _, f := range s.fields {
+			values[f.name] = d.value(f.schema)
+		}
+		return values
+	case "union":
+		if i := d.long(); i >= 0 && int(i) < len(s.branches) {
+			return d.value(s.branches[i])
+		}
+		d.fail("invalid union index")
+	}
+	return nil
+}
+
+func (d *avroDecoder) fail(msg string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%s", msg)
+	}
+}
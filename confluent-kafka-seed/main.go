@@ -0,0 +1,201 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "seed <file>...",
+		Short: "Produce fixture data to a topic from JSON, CSV, or Avro files.",
+		Long:  "Produce the records of local JSON, CSV, or Avro files to a topic, serialized with the latest schema registered for the topic's values, if any, with keys and headers rendered from templates of each record's fields, such as to seed a development or test environment.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  seed,
+		Example: `confluent kafka seed fixtures/orders.json --topic orders --key '{{.order_id}}'
+confluent kafka seed customers.csv --topic customers --key '{{.id}}' --header source=fixtures --header trace-id='{{uuid}}' --dry-run`,
+	}
+
+	cmd.Flags().String("topic", "", "Topic to produce the records to.")
+	cmd.Flags().String("key", "", `Template of the key of each record, such as "{{.id}}". Records have no key by default.`)
+	cmd.Flags().StringArray("header", nil, `Header of each record, as a "<name>=<template>" pair. May be passed more than once.`)
+	cmd.Flags().String("value-subject", "", `Subject of the schema of the values. Defaults to "<topic>-value".`)
+	cmd.Flags().String("input-format", "", "Format of the files: json, csv, or avro. Defaults to the format of each file's extension.")
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key. Defaults to the CLI's stored API key for the cluster.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret.")
+	cmd.Flags().String("schema-registry-api-key", "", "Schema Registry API key. Defaults to the CLI's stored API key for Schema Registry.")
+	cmd.Flags().String("schema-registry-api-secret", "", "Schema Registry API secret.")
+	dryrun.AddFlag(&cmd, "Print the records which would be produced without producing them.")
+	output.AddFlag(&cmd, "Format of the records which were produced")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("topic"))
+
+	completion.Add(&cmd, "confluent-kafka-seed")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-kafka-seed"))
+
+	exitcode.Execute(&cmd)
+}
+
+func seed(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	topic, err := cmd.Flags().GetString("topic")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("key")
+	cobra.CheckErr(err)
+
+	headers, err := cmd.Flags().GetStringArray("header")
+	cobra.CheckErr(err)
+
+	subject, err := cmd.Flags().GetString("value-subject")
+	cobra.CheckErr(err)
+
+	inputFormat, err := cmd.Flags().GetString("input-format")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	srAPIKey, err := cmd.Flags().GetString("schema-registry-api-key")
+	cobra.CheckErr(err)
+
+	srAPISecret, err := cmd.Flags().GetString("schema-registry-api-secret")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	if inputFormat != "" && inputFormat != "json" && inputFormat != "csv" && inputFormat != "avro" {
+		return fmt.Errorf(`unsupported input format "%s", supported formats: json, csv, avro`, inputFormat)
+	}
+	if subject == "" {
+		subject = topic + "-value"
+	}
+
+	var records []any
+	for _, path := range args {
+		r, err := readRecords(path, inputFormat)
+		if err != nil {
+			return fmt.Errorf(`failed to read "%s": %w`, path, err)
+		}
+		records = append(records, r...)
+	}
+
+	// Schema Registry commands take its API key as --api-key, unlike "confluent kafka topic produce".
+	var srArgs []string
+	if environment != "" {
+		srArgs = append(srArgs, "--environment", environment)
+	}
+	if srAPIKey != "" {
+		srArgs = append(srArgs, "--api-key", srAPIKey, "--api-secret", srAPISecret)
+	}
+	schema, err := lookUpSchema(subject, srArgs)
+	if err != nil {
+		return err
+	}
+
+	s := &seeder{topic: topic, schema: schema, args: cli.ClusterFlags(cluster, environment)}
+	if apiKey != "" {
+		s.args = append(s.args, "--api-key", apiKey, "--api-secret", apiSecret)
+	}
+	if schema != nil && srAPIKey != "" {
+		s.args = append(s.args, "--schema-registry-api-key", srAPIKey, "--schema-registry-api-secret", srAPISecret)
+	}
+	if err := s.parse(key, headers); err != nil {
+		return err
+	}
+
+	rendered, err := s.renderAll(records)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		// The records go to stderr, so that the plan on stdout is the same as every other plugin's.
+		print(cmd.ErrOrStderr(), rendered)
+		var plan dryrun.Plan
+		plan.AddDetail("produce", fmt.Sprintf("%d records", len(rendered)), "", fmt.Sprintf(`to topic "%s"%s`, topic, describeSchema(schema)))
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := cmd.OutOrStdout()
+	if err := s.produce(rendered); err != nil {
+		return err
+	}
+	p := produced{Topic: topic, Records: len(rendered)}
+	if schema != nil {
+		p.SchemaSubject, p.SchemaID = schema.subject, schema.id
+	}
+	return output.Print(out, format, p, func() {
+		fmt.Fprintf(out, "Produced %d records to topic \"%s\"%s.\n", len(rendered), topic, describeSchema(schema))
+	})
+}
+
+// produced is what --output json or yaml prints once the records are produced.
+type produced struct {
+	Topic         string `json:"topic"`
+	Records       int    `json:"records"`
+	SchemaSubject string `json:"schema_subject,omitempty"`
+	SchemaID      int    `json:"schema_id,omitempty"`
+}
+
+func describeSchema(s *valueSchema) string {
+	if s == nil {
+		return " as JSON, since no schema is registered"
+	}
+	return fmt.Sprintf(" with schema ID %d of subject \"%s\"", s.id, s.subject)
+}
+
+func print(w io.Writer, records []rendered) {
+	if len(records) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Key\tHeaders\tValue")
+	for _, r := range records {
+		key, headers := r.Key, r.Headers
+		if key == "" {
+			key = "-"
+		}
+		if headers == "" {
+			headers = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, headers, r.Value)
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// inputFormats are the formats of the files which records are read from, by extension.
+var inputFormats = map[string]string{
+	".json":   "json",
+	".jsonl":  "json",
+	".ndjson": "json",
+	".csv":    "csv",
+	".avro":   "avro",
+}
+
+// readRecords reads the records of a file, in the format of its extension unless one is passed. A JSON file has an
+// array of records, or a record per line. A CSV file has a header, and a record per row, whose values are strings. An
+// Avro file is an object container file.
+func readRecords(path, format string) ([]any, error) {
+	if format == "" {
+		var ok bool
+		if format, ok = inputFormats[strings.ToLower(filepath.Ext(path))]; !ok {
+			return nil, fmt.Errorf(`unknown format of "%s", pass --input-format json, csv, or avro`, path)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []any
+	switch format {
+	case "json":
+		records, err = readJSON(f)
+	case "csv":
+		records, err = readCSV(f)
+	case "avro":
+		records, err = readAvroFile(f)
+	default:
+		return nil, fmt.Errorf(`unsupported input format "%s", supported formats: json, csv, avro`, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return records, nil
+}
+
+func readJSON(r io.Reader) ([]any, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []any
+		if err := decoder.Decode(&records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	var records []any
+	for {
+		var record any
+		if err := decoder.Decode(&record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func readCSV(r io.Reader) ([]any, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	records := make([]any, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := map[string]any{}
+		for j, column := range header {
+			record[column] = row[j]
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// jsonSchemaTypes returns the types of the properties of a JSON schema, so that the strings of CSV files can be
+// converted into the numbers and booleans which the schema expects. Only the properties of the top level are converted.
+func jsonSchemaTypes(schema string) (map[string][]string, error) {
+	var s struct {
+		Properties map[string]struct {
+			Type any `json:"type"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	types := map[string][]string{}
+	for name, p := range s.Properties {
+		switch t := p.Type.(type) {
+		case string:
+			types[name] = []string{t}
+		case []any:
+			for _, t := range t {
+				if t, ok := t.(string); ok {
+					types[name] = append(types[name], t)
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+// convertJSONSchema converts the strings of the record's properties into the types of the schema, which are kept as
+// strings if they can't be converted, so that the CLI reports which record doesn't match the schema.
+func convertJSONSchema(types map[string][]string, record any) any {
+	values, ok := record.(map[string]any)
+	if !ok {
+		return record
+	}
+
+	converted := map[string]any{}
+	for name, value := range values {
+		converted[name] = value
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		for _, t := range types[name] {
+			if c, ok := convertString(t, s); ok {
+				converted[name] = c
+				break
+			}
+		}
+	}
+	return converted
+}
+
+func convertString(jsonType, s string) (any, bool) {
+	switch jsonType {
+	case "string":
+		return s, true
+	case "null":
+		return nil, s == ""
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	case "integer":
+		i, err := strconv.ParseInt(s, 10, 64)
+		return i, err == nil
+	case "number":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(s), true
+	}
+	return nil, false
+}
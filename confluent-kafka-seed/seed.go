@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// keyDelimiter separates the key of a record from its value for "confluent kafka topic produce". Values are JSON,
+// which escapes tabs, so only keys can't have one.
+const keyDelimiter = "\t"
+
+// valueSchema is the latest schema of the subject of the topic's values, which the records are serialized with.
+type valueSchema struct {
+	subject string
+	id      int
+	kind    string
+	avro    *avroSchema
+	types   map[string][]string
+}
+
+// valueFormats are the formats of "confluent kafka topic produce" by schema type.
+var valueFormats = map[string]string{"AVRO": "avro", "JSON": "jsonschema", "PROTOBUF": "protobuf"}
+
+// lookUpSchema returns the latest schema of the subject, or nil if the subject doesn't exist.
+func lookUpSchema(subject string, args []string) (*valueSchema, error) {
+	var sv struct {
+		ID     int    `json:"schema_id"`
+		Schema string `json:"schema"`
+		Type   string `json:"type"`
+	}
+	if err := cli.JSON(&sv, append([]string{"schema-registry", "schema", "describe", "--subject", subject, "--version", "latest"}, args...)...); err != nil {
+		if strings.Contains(err.Error(), "40401") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(`failed to look up the schema of subject "%s": %w`, subject, err)
+	}
+
+	s := &valueSchema{subject: subject, id: sv.ID, kind: strings.ToUpper(sv.Type)}
+	if s.kind == "" {
+		s.kind = "AVRO"
+	}
+	if _, ok := valueFormats[s.kind]; !ok {
+		return nil, fmt.Errorf(`unsupported type "%s" of the schema of subject "%s"`, sv.Type, subject)
+	}
+
+	var err error
+	switch s.kind {
+	case "AVRO":
+		s.avro, err = parseAvroSchema(sv.Schema)
+	case "JSON":
+		s.types, err = jsonSchemaTypes(sv.Schema)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse the schema of subject "%s": %w`, subject, err)
+	}
+	return s, nil
+}
+
+// A seeder produces records to a topic, with keys and headers rendered from templates of each record's fields.
+type seeder struct {
+	topic   string
+	args    []string
+	schema  *valueSchema
+	key     *template.Template
+	headers []header
+
+	// seq is the number of the record which is rendered, from 1.
+	seq int
+}
+
+type header struct {
+	name     string
+	template *template.Template
+}
+
+// A rendered record is a line which "confluent kafka topic produce" reads, and the headers which it's produced with.
+type rendered struct {
+	Key     string
+	Headers string
+	Value   string
+}
+
+func (s *seeder) funcs() template.FuncMap {
+	return template.FuncMap{
+		"seq":  func() int { return s.seq },
+		"uuid": uuid,
+		"now":  func() string { return time.Now().UTC().Format(time.RFC3339) },
+	}
+}
+
+// parse parses the templates of the key, and of the headers, which are "<name>=<template>" pairs.
+func (s *seeder) parse(key string, headers []string) error {
+	if key != "" {
+		t, err := template.New("key").Funcs(s.funcs()).Option("missingkey=error").Parse(key)
+		if err != nil {
+			return fmt.Errorf("invalid key template: %w", err)
+		}
+		s.key = t
+	}
+	for _, h := range headers {
+		name, text, ok := strings.Cut(h, "=")
+		if !ok || name == "" {
+			return fmt.Errorf(`invalid header "%s", expected "<name>=<template>"`, h)
+		}
+		if strings.ContainsAny(name, ",:") {
+			return fmt.Errorf(`invalid header name "%s", which can't have a comma or colon`, name)
+		}
+		t, err := template.New(name).Funcs(s.funcs()).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return fmt.Errorf(`invalid template of header "%s": %w`, name, err)
+		}
+		s.headers = append(s.headers, header{name: name, template: t})
+	}
+	return nil
+}
+
+func (s *seeder) render(record any, seq int) (rendered, error) {
+	s.seq = seq
+	var r rendered
+
+	if s.key != nil {
+		key, err := execute(s.key, record)
+		if err != nil {
+			return rendered{}, fmt.Errorf("failed to render the key: %w", err)
+		}
+		if strings.ContainsAny(key, keyDelimiter+"\n") {
+			return rendered{}, fmt.Errorf("the key %q has a tab or a newline", key)
+		}
+		r.Key = key
+	}
+
+	var headers []string
+	for _, h := range s.headers {
+		value, err := execute(h.template, record)
+		if err != nil {
+			return rendered{}, fmt.Errorf(`failed to render header "%s": %w`, h.name, err)
+		}
+		if strings.ContainsAny(value, ",\n") {
+			return rendered{}, fmt.Errorf(`the value %q of header "%s" has a comma or a newline`, value, h.name)
+		}
+		headers = append(headers, h.name+":"+value)
+	}
+	r.Headers = strings.Join(headers, ",")
+
+	value := record
+	if s.schema != nil {
+		switch s.schema.kind {
+		case "AVRO":
+			converted, err := avroJSON(s.schema.avro, record)
+			if err != nil {
+				return rendered{}, fmt.Errorf(`doesn't match the schema of subject "%s": %w`, s.schema.subject, err)
+			}
+			value = converted
+		case "JSON":
+			value = convertJSONSchema(s.schema.types, record)
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return rendered{}, err
+	}
+	r.Value = string(b)
+	return r, nil
+}
+
+func execute(t *template.Template, record any) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, record); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// renderAll renders every record before any is produced, so that a record which doesn't match the schema doesn't leave
+// the topic half seeded.
+func (s *seeder) renderAll(records []any) ([]rendered, error) {
+	all := make([]rendered, len(records))
+	for i, record := range records {
+		r, err := s.render(record, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		all[i] = r
+	}
+	return all, nil
+}
+
+// produce produces the records in order with "confluent kafka topic produce", which reads them from stdin. The CLI
+// produces every record which it reads with the same headers, so each run of records with the same headers is
+// produced by a command of its own.
+func (s *seeder) produce(records []rendered) error {
+	for start := 0; start < len(records); {
+		end := start + 1
+		for end < len(records) && records[end].Headers == records[start].Headers {
+			end++
+		}
+
+		var lines bytes.Buffer
+		for _, r := range records[start:end] {
+			if s.key != nil {
+				lines.WriteString(r.Key + keyDelimiter)
+			}
+			lines.WriteString(r.Value + "\n")
+		}
+		if err := s.run(records[start].Headers, &lines); err != nil {
+			return fmt.Errorf("failed to produce records %d to %d: %w", start+1, end, err)
+		}
+		start = end
+	}
+	return nil
+}
+
+func (s *seeder) run(headers string, stdin io.Reader) error {
+	args := []string{"kafka", "topic", "produce", s.topic}
+	if s.key != nil {
+		args = append(args, "--parse-key", "--delimiter", keyDelimiter)
+	}
+	if s.schema != nil {
+		args = append(args, "--value-format", valueFormats[s.schema.kind], "--schema-id", strconv.Itoa(s.schema.id))
+	}
+	if headers != "" {
+		args = append(args, "--headers", headers)
+	}
+	args = append(args, s.args...)
+
+	_, err := cli.Command{Args: args, Stdin: stdin}.Output()
+	return err
+}
+
+// uuid returns a random UUID, such as for the keys of records which don't have an ID.
+func uuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// quickstarts are the templates of the fully-managed Datagen source connector.
+var quickstarts = []string{
+	"CAMPAIGN_FINANCE",
+	"CLICKSTREAM",
+	"CLICKSTREAM_CODES",
+	"CLICKSTREAM_USERS",
+	"CREDIT_CARDS",
+	"DEVICE_INFORMATION",
+	"FLEET_MGMT_DESCRIPTION",
+	"FLEET_MGMT_LOCATION",
+	"FLEET_MGMT_SENSORS",
+	"GAMING_GAMES",
+	"GAMING_PLAYER_ACTIVITY",
+	"GAMING_PLAYERS",
+	"INSURANCE_CUSTOMER_ACTIVITY",
+	"INSURANCE_CUSTOMERS",
+	"INSURANCE_OFFERS",
+	"INVENTORY",
+	"ORDERS",
+	"PAGEVIEWS",
+	"PAYROLL_BONUS",
+	"PAYROLL_EMPLOYEE",
+	"PAYROLL_EMPLOYEE_LOCATION",
+	"PIZZA_ORDERS",
+	"PIZZA_ORDERS_CANCELLED",
+	"PIZZA_ORDERS_COMPLETED",
+	"PRODUCT",
+	"PURCHASES",
+	"RATINGS",
+	"SHOE_CLICKSTREAM",
+	"SHOE_CUSTOMERS",
+	"SHOE_ORDERS",
+	"SHOES",
+	"SIEM_LOGS",
+	"STOCK_TRADES",
+	"STORES",
+	"SYSLOG_LOGS",
+	"TRANSACTIONS",
+	"USERS",
+	"USERS_ARRAY",
+}
+
+// seed creates a topic for each quickstart, named after it, and a Datagen connector which writes AVRO records to it,
+// and waits for the connectors to run. The connectors produce with an API key of the current user.
+func (q *quickstart) seed(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	var existing []cli.Topic
+	if err := cli.JSON(&existing, "kafka", "topic", "list"); err != nil {
+		return err
+	}
+
+	topics := map[string]bool{}
+	for _, t := range existing {
+		topics[t.Name] = true
+	}
+
+	for _, quickstart := range names {
+		topic := strings.ToLower(quickstart)
+		q.topics = append(q.topics, topic)
+		if topics[topic] {
+			continue
+		}
+		if _, err := cli.Run("kafka", "topic", "create", topic); err != nil {
+			return err
+		}
+		fmt.Fprintf(q.out, "Created topic \"%s\".\n", topic)
+		q.created.Add("topic", topic, "", "kafka", "topic", "delete", topic, "--cluster", q.cluster, "--force")
+	}
+
+	key, err := createKey(q.cluster, fmt.Sprintf(`Datagen connectors of ksqlDB quickstart "%s"`, q.name))
+	if err != nil {
+		return err
+	}
+	q.created.Add("API key", "", key.Key, "api-key", "delete", key.Key, "--force")
+
+	var ids []string
+	for i, quickstart := range names {
+		id, err := createConnector("datagen-"+q.topics[i], quickstart, q.topics[i], key)
+		if err != nil {
+			return fmt.Errorf(`failed to create the Datagen connector for quickstart %s: %w`, quickstart, err)
+		}
+		fmt.Fprintf(q.out, "Created Datagen connector \"%s\" (%s).\n", "datagen-"+q.topics[i], id)
+		q.created.Add("Datagen connector", "datagen-"+q.topics[i], id, "connect", "cluster", "delete", id, "--cluster", q.cluster, "--force")
+		ids = append(ids, id)
+	}
+
+	s := progress.Spin(q.out, "Waiting for the Datagen connectors to run")
+	defer s.Stop()
+	for i, id := range ids {
+		s.Status(fmt.Sprintf("%d of %d running", i, len(ids)))
+		if err := q.waitForConnector(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createConnector(name, quickstart, topic string, key cli.CreatedAPIKey) (string, error) {
+	configs := map[string]string{
+		"name":               name,
+		"connector.class":    "DatagenSource",
+		"kafka.auth.mode":    "KAFKA_API_KEY",
+		"kafka.api.key":      key.Key,
+		"kafka.api.secret":   key.Secret,
+		"kafka.topic":        topic,
+		"quickstart":         quickstart,
+		"output.data.format": "AVRO",
+		"tasks.max":          "1",
+	}
+	b, err := json.Marshal(configs)
+	if err != nil {
+		return "", err
+	}
+	// The file is only readable by the current user, since it contains the API secret.
+	path, err := platform.WriteTemp("ksql-quickstart-*.json", b)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&created, "connect", "cluster", "create", "--config-file", path); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// waitForConnector waits for the connector to run. Connectors which fail are resumed, since a new API key can take a
+// while to be usable.
+func (q *quickstart) waitForConnector(id string) error {
+	for {
+		var described struct {
+			Connector struct {
+				Status string `json:"status"`
+			} `json:"connector"`
+		}
+		if err := cli.JSON(&described, "connect", "cluster", "describe", id); err != nil {
+			return err
+		}
+
+		switch described.Connector.Status {
+		case "RUNNING":
+			return nil
+		case "FAILED":
+			if _, err := cli.Run("connect", "cluster", "resume", id); err != nil {
+				return err
+			}
+		}
+
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for Datagen connector "%s" to run, its status is %s`, id, described.Connector.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "quickstart",
+		Short: "Create a ksqlDB application and start the ksqlDB CLI.",
+		Long:  "Create a ksqlDB application, in an environment and Kafka cluster which are reused or created, running as a service account with the ACLs it needs. Datagen connectors can seed topics for the application to query, and once it's provisioned, the ksqlDB CLI is started.",
+		Args:  cobra.NoArgs,
+		RunE:  start,
+		Example: `confluent ksql quickstart --name orders-app --datagen-quickstarts orders,users
+confluent ksql quickstart --name orders-app --environment-name workshop --cluster create --no-shell`,
+	}
+
+	cmd.Flags().String("name", "", "Name of the ksqlDB application, and the prefix of the names of the environment and Kafka cluster if they're created.")
+	cmd.Flags().String("environment-name", "", "Name of the environment to use, which is created if it doesn't exist. Defaults to <name>_environment.")
+	cmd.Flags().String("cluster", "", `ID of the Kafka cluster to use, or "create" to create one. Defaults to picking one of the clusters in the region in a terminal.`)
+	cmd.Flags().String("cloud", "aws", "Cloud provider of the Kafka cluster: aws, gcp, or azure.")
+	cmd.Flags().String("region", "us-east-1", "Cloud region of the Kafka cluster.")
+	cmd.Flags().Int("csu", 1, "Number of Confluent Streaming Units of the ksqlDB application: 1, 2, 4, 8, or 12.")
+	cmd.Flags().StringSlice("datagen-quickstarts", nil, "Datagen quickstarts to seed topics with, such as orders or users.")
+	cmd.Flags().Duration("timeout", 20*time.Minute, "How long to wait for the resources to be provisioned.")
+	cmd.Flags().Bool("no-shell", false, "Don't start the ksqlDB CLI.")
+	output.AddFlag(&cmd, "Format of the resources of the ksqlDB application, which needs --no-shell with json or yaml")
+	cli.AddCacheFlag(&cmd)
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("name"))
+
+	completion.Add(&cmd, "confluent-ksql-quickstart")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-ksql-quickstart"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+func start(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	name, err := cmd.Flags().GetString("name")
+	cobra.CheckErr(err)
+
+	environmentName, err := cmd.Flags().GetString("environment-name")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	cloud, err := cmd.Flags().GetString("cloud")
+	cobra.CheckErr(err)
+
+	region, err := cmd.Flags().GetString("region")
+	cobra.CheckErr(err)
+
+	csu, err := cmd.Flags().GetInt("csu")
+	cobra.CheckErr(err)
+
+	datagenQuickstarts, err := cmd.Flags().GetStringSlice("datagen-quickstarts")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	noShell, err := cmd.Flags().GetBool("no-shell")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	// The ksqlDB CLI writes to stdout too, so only the result can be printed on it.
+	if format != output.Table && !noShell {
+		return exitcode.Invalid("--output %s requires --no-shell", format)
+	}
+
+	if cloud != "aws" && cloud != "gcp" && cloud != "azure" {
+		return fmt.Errorf(`unsupported cloud "%s", supported clouds: aws, gcp, azure`, cloud)
+	}
+	if !slices.Contains([]int{1, 2, 4, 8, 12}, csu) {
+		return exitcode.Invalid("--csu must be 1, 2, 4, 8, or 12")
+	}
+	for i, quickstart := range datagenQuickstarts {
+		datagenQuickstarts[i] = strings.ToUpper(quickstart)
+		if !slices.Contains(quickstarts, datagenQuickstarts[i]) {
+			return fmt.Errorf(`unsupported quickstart "%s", supported quickstarts: %s`, quickstart, strings.Join(quickstarts, ", "))
+		}
+	}
+	if environmentName == "" {
+		environmentName = name + "_environment"
+	}
+
+	q := &quickstart{
+		name:     name,
+		cloud:    cloud,
+		region:   region,
+		csu:      csu,
+		deadline: time.Now().Add(timeout),
+		out:      output.Progress(cmd),
+		prompter: prompt.New(cmd),
+	}
+
+	if err := q.created.Report(cmd.ErrOrStderr(), q.provision(environmentName, cluster, datagenQuickstarts)); err != nil {
+		return err
+	}
+
+	if format != output.Table {
+		return output.Print(cmd.OutOrStdout(), format, q.result(), nil)
+	}
+	fmt.Fprintf(q.out, "ksqlDB application \"%s\" (%s) is ready at %s.\n", q.name, q.ksql.ID, q.ksql.Endpoint)
+	if noShell {
+		return nil
+	}
+	return q.shell()
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+)
+
+// pollInterval is how often the status of the resources being provisioned is checked.
+const pollInterval = 10 * time.Second
+
+// A quickstart is the resources which are created, or reused, for a ksqlDB application.
+type quickstart struct {
+	name     string
+	cloud    string
+	region   string
+	csu      int
+	deadline time.Time
+
+	out      io.Writer
+	prompter *prompt.Prompter
+
+	environment    string
+	cluster        string
+	serviceAccount string
+	ksql           ksqlCluster
+	topics         []string
+
+	// created is the resources which the quickstart created, which are reported if it fails or is interrupted.
+	created interrupt.Ledger
+}
+
+// ksqlCluster is the part of "confluent ksql cluster describe" which the quickstart needs.
+type ksqlCluster struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Endpoint string `json:"endpoint"`
+	Kafka    string `json:"kafka"`
+}
+
+// result is what --output json or yaml prints once the ksqlDB application is ready.
+type result struct {
+	Environment    string   `json:"environment"`
+	Cluster        string   `json:"cluster"`
+	ServiceAccount string   `json:"service_account"`
+	KSQL           string   `json:"ksql_cluster"`
+	Endpoint       string   `json:"endpoint"`
+	Topics         []string `json:"topics"`
+}
+
+func (q *quickstart) result() result {
+	topics := q.topics
+	if topics == nil {
+		topics = []string{}
+	}
+	return result{
+		Environment:    q.environment,
+		Cluster:        q.cluster,
+		ServiceAccount: q.serviceAccount,
+		KSQL:           q.ksql.ID,
+		Endpoint:       q.ksql.Endpoint,
+		Topics:         topics,
+	}
+}
+
+func createKey(resource, description string) (cli.CreatedAPIKey, error) {
+	var key cli.CreatedAPIKey
+	if err := cli.JSON(&key, "api-key", "create", "--resource", resource, "--description", description); err != nil {
+		return cli.CreatedAPIKey{}, err
+	}
+	if key.Key == "" || key.Secret == "" {
+		return cli.CreatedAPIKey{}, fmt.Errorf("confluent api-key create didn't return a key and secret")
+	}
+	return key, nil
+}
+
+// provision creates, or reuses, the environment, Kafka cluster, ksqlDB application and Datagen connectors, and waits for
+// them to be ready.
+func (q *quickstart) provision(environmentName, cluster string, datagenQuickstarts []string) error {
+	if err := q.useEnvironment(environmentName); err != nil {
+		return err
+	}
+	if err := q.useCluster(cluster); err != nil {
+		return err
+	}
+	if err := q.createKSQL(); err != nil {
+		return err
+	}
+
+	// The connectors are created while the ksqlDB application is provisioned, since both take minutes.
+	if err := q.seed(datagenQuickstarts); err != nil {
+		return err
+	}
+	if err := q.waitForKSQL(); err != nil {
+		return err
+	}
+	return q.configureACLs()
+}
+
+// useEnvironment makes the environment with the name the current one, creating it if it doesn't exist.
+func (q *quickstart) useEnvironment(name string) error {
+	var environments []cli.Environment
+	if err := cli.JSON(&environments, "environment", "list"); err != nil {
+		return err
+	}
+	for _, e := range environments {
+		if e.Name == name {
+			q.environment = e.ID
+		}
+	}
+
+	if q.environment == "" {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := cli.JSON(&created, "environment", "create", name); err != nil {
+			return err
+		}
+		q.environment = created.ID
+		fmt.Fprintf(q.out, "Created environment \"%s\" (%s).\n", name, q.environment)
+		q.created.Add("environment", name, q.environment, "environment", "delete", q.environment, "--force")
+	}
+
+	_, err := cli.Run("environment", "use", q.environment)
+	return err
+}
+
+// useCluster makes a Kafka cluster in the region the current one. The cluster may be passed, or "create" to create
+// one, and otherwise the user picks one of the existing clusters, or a cluster is created if there are none.
+func (q *quickstart) useCluster(choice string) error {
+	var clusters []cli.KafkaCluster
+	if err := cli.JSON(&clusters, "kafka", "cluster", "list"); err != nil {
+		return err
+	}
+	var ids []string
+	var candidates []string
+	for _, c := range clusters {
+		ids = append(ids, c.ID)
+		if strings.EqualFold(c.Cloud, q.cloud) && c.Region == q.region {
+			candidates = append(candidates, c.ID)
+		}
+	}
+
+	// Without a terminal to pick in, the cluster has to be passed.
+	if choice == "" && len(candidates) > 0 && prompt.NonInteractive() {
+		return exitcode.Invalid(`--cluster is required with --non-interactive, pass the ID of one of the Kafka clusters in the region, %s, or "create"`, strings.Join(candidates, ", "))
+	}
+
+	if choice == "" && len(candidates) > 0 {
+		// The clusters are listed with their topics, so that the one to use can be told apart by what's in it.
+		list := func() ([]prompt.Option, error) {
+			var options []prompt.Option
+			for _, c := range clusters {
+				if !slices.Contains(candidates, c.ID) {
+					continue
+				}
+				var topics []cli.Topic
+				if err := cli.JSON(&topics, "kafka", "topic", "list", "--cluster", c.ID); err != nil {
+					return nil, err
+				}
+				names := make([]string, len(topics))
+				for i, t := range topics {
+					names[i] = t.Name
+				}
+				options = append(options, prompt.Option{ID: c.ID, Name: c.Name, Detail: strings.Join(names, ", ")})
+			}
+			return append(options, prompt.Option{ID: "create", Name: "Create a new Kafka cluster"}), nil
+		}
+		if err := q.prompter.Select(&choice, "cluster", "Kafka cluster to use", list); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case choice == "" || choice == "create":
+		if err := q.createCluster(); err != nil {
+			return err
+		}
+	case !slices.Contains(ids, choice):
+		return fmt.Errorf(`Kafka cluster "%s" isn't in the environment`, choice)
+	default:
+		q.cluster = choice
+	}
+
+	_, err := cli.Run("kafka", "cluster", "use", q.cluster)
+	return err
+}
+
+// createCluster creates a basic Kafka cluster, waits for it to be up, and enables Schema Registry in the environment if
+// it isn't yet, since ksqlDB and the Datagen connectors use it for their schemas.
+func (q *quickstart) createCluster() error {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&created, "kafka", "cluster", "create", q.name+"_kafka-cluster", "--cloud", q.cloud, "--region", q.region); err != nil {
+		return err
+	}
+	q.cluster = created.ID
+	fmt.Fprintf(q.out, "Created Kafka cluster \"%s\" (%s).\n", q.name+"_kafka-cluster", q.cluster)
+	q.created.Add("Kafka cluster", q.name+"_kafka-cluster", q.cluster, "kafka", "cluster", "delete", q.cluster, "--force")
+
+	if _, err := cli.Run("schema-registry", "cluster", "describe"); err != nil {
+		if _, err := cli.Run("schema-registry", "cluster", "enable", "--cloud", q.cloud, "--geo", geo(q.region)); err != nil {
+			return err
+		}
+		fmt.Fprintln(q.out, "Enabled Schema Registry.")
+	}
+
+	s := progress.Spin(q.out, "Waiting for the Kafka cluster to be up")
+	defer s.Stop()
+	for {
+		var described cli.KafkaCluster
+		if err := cli.JSON(&described, "kafka", "cluster", "describe", q.cluster); err != nil {
+			return err
+		}
+		s.Status(described.Status)
+		if described.Status == "UP" {
+			return nil
+		}
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for Kafka cluster "%s" to be up, its status is %s`, q.cluster, described.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// geo returns the Schema Registry geography of a cloud region.
+func geo(region string) string {
+	switch {
+	case strings.HasPrefix(region, "eu") || strings.HasPrefix(region, "europe") || strings.HasPrefix(region, "westeurope") || strings.HasPrefix(region, "northeurope"):
+		return "eu"
+	case strings.HasPrefix(region, "ap") || strings.HasPrefix(region, "asia") || strings.HasPrefix(region, "australia"):
+		return "apac"
+	default:
+		return "us"
+	}
+}
+
+// createKSQL creates the ksqlDB application with the quickstart's name, or reuses it if it exists. A new application
+// runs as a service account of its own, so that its ACLs can be limited to its topics.
+func (q *quickstart) createKSQL() error {
+	var clusters []ksqlCluster
+	if err := cli.JSON(&clusters, "ksql", "cluster", "list"); err != nil {
+		return err
+	}
+	for _, c := range clusters {
+		if c.Name == q.name && c.Kafka == q.cluster {
+			q.ksql = c
+			fmt.Fprintf(q.out, "Using ksqlDB application \"%s\" (%s).\n", c.Name, c.ID)
+			return nil
+		}
+	}
+
+	serviceAccountName := q.name + "-ksql"
+	var serviceAccounts []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := cli.JSON(&serviceAccounts, "iam", "service-account", "list"); err != nil {
+		return err
+	}
+	for _, sa := range serviceAccounts {
+		if sa.Name == serviceAccountName {
+			q.serviceAccount = sa.ID
+		}
+	}
+	if q.serviceAccount == "" {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := cli.JSON(&created, "iam", "service-account", "create", serviceAccountName, "--description", fmt.Sprintf(`ksqlDB application "%s"`, q.name)); err != nil {
+			return err
+		}
+		q.serviceAccount = created.ID
+		fmt.Fprintf(q.out, "Created service account \"%s\" (%s).\n", serviceAccountName, q.serviceAccount)
+		q.created.Add("service account", serviceAccountName, q.serviceAccount, "iam", "service-account", "delete", q.serviceAccount, "--force")
+	}
+
+	if err := cli.JSON(&q.ksql, "ksql", "cluster", "create", q.name, "--cluster", q.cluster, "--csu", fmt.Sprint(q.csu), "--credential-identity", q.serviceAccount); err != nil {
+		return err
+	}
+	fmt.Fprintf(q.out, "Created ksqlDB application \"%s\" (%s).\n", q.name, q.ksql.ID)
+	q.created.Add("ksqlDB application", q.name, q.ksql.ID, "ksql", "cluster", "delete", q.ksql.ID, "--force")
+	return nil
+}
+
+// waitForKSQL waits for the ksqlDB application to be provisioned, which takes several minutes.
+func (q *quickstart) waitForKSQL() error {
+	if q.ksql.Status == "PROVISIONED" {
+		return nil
+	}
+
+	s := progress.Spin(q.out, "Waiting for the ksqlDB application to be provisioned")
+	defer s.Stop()
+	for q.ksql.Status != "PROVISIONED" {
+		s.Status(q.ksql.Status)
+		if q.ksql.Status == "FAILED" {
+			return fmt.Errorf(`ksqlDB application "%s" failed to be provisioned`, q.ksql.ID)
+		}
+		if time.Now().Add(pollInterval).After(q.deadline) {
+			return fmt.Errorf(`timed out waiting for ksqlDB application "%s" to be provisioned, its status is %s`, q.ksql.ID, q.ksql.Status)
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return err
+		}
+		if err := cli.JSON(&q.ksql, "ksql", "cluster", "describe", q.ksql.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureACLs lets the application's service account use its internal topics and groups, and read and write the
+// Datagen topics. Applications which were reused, or which run as a user, keep their ACLs.
+func (q *quickstart) configureACLs() error {
+	if q.serviceAccount == "" {
+		return nil
+	}
+	if _, err := cli.Run(append([]string{"ksql", "cluster", "configure-acls", q.ksql.ID, "--cluster", q.cluster}, q.topics...)...); err != nil {
+		return err
+	}
+	fmt.Fprintf(q.out, "Configured the ACLs of service account %s.\n", q.serviceAccount)
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+)
+
+// shell starts the ksqlDB CLI, connected to the application with a new API key of the current user. The ksqlDB CLI
+// isn't part of the confluent CLI, so if it isn't installed, how to start it is printed instead.
+func (q *quickstart) shell() error {
+	key, err := createKey(q.ksql.ID, fmt.Sprintf(`ksqlDB CLI of ksqlDB quickstart "%s"`, q.name))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(q.out, "Created API key %s for ksqlDB application %s.\n", key.Key, q.ksql.ID)
+
+	path, err := exec.LookPath("ksql")
+	if err != nil {
+		fmt.Fprintf(q.out, "The ksqlDB CLI isn't installed, start it with Docker instead:\n\n")
+		fmt.Fprintf(q.out, "  docker run -it confluentinc/ksqldb-cli:latest ksql -u %s -p %s %s\n", key.Key, key.Secret, q.ksql.Endpoint)
+		return nil
+	}
+
+	fmt.Fprintln(q.out, "Starting the ksqlDB CLI.")
+	command := exec.Command(path, "-u", key.Key, "-p", key.Secret, q.ksql.Endpoint)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return interrupt.Run(command)
+}
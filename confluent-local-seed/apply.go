@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <spec>",
+		Short: "Start the local Kafka cluster and seed it.",
+		Long:  "Start the local Kafka cluster unless it's running, register the schemas of the spec, create its topics which don't exist, and produce the fixtures of the topics which were created, so that re-running it doesn't produce them twice.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  apply,
+		Example: `confluent local seed apply seed.yml
+confluent local seed apply seed.yml --reset --dry-run`,
+	}
+
+	cmd.Flags().Bool("reset", false, "Delete and recreate the topics of the spec, and produce their fixtures again.")
+	dryrun.AddFlag(cmd, "Print what would be started, registered, created, and produced without changing anything.")
+	output.AddFlag(cmd, "Format of what was started, registered, created, and produced")
+
+	return cmd
+}
+
+func apply(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	reset, err := cmd.Flags().GetBool("reset")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	s, err := readSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return dryrun.Print(cmd, planApply(s, reset))
+	}
+
+	var done dryrun.Plan
+	err = seed(output.Progress(cmd), s, reset, &done)
+	return errors.Join(err, dryrun.PrintApplied(cmd, done))
+}
+
+// seed does what planApply plans, and adds what it did to done.
+func seed(out io.Writer, s spec, reset bool, done *dryrun.Plan) error {
+	existing, err := localTopics()
+	if err != nil {
+		fmt.Fprintln(out, "Starting the local Kafka cluster...")
+		if _, err := cli.Run("local", "kafka", "start"); err != nil {
+			return err
+		}
+		if existing, err = localTopics(); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Started the local Kafka cluster.")
+		done.Add("start", "the local Kafka cluster", "")
+	}
+
+	// The schemas are registered first, so that if the Schema Registry isn't running, no topic is created yet, and
+	// re-running the seeding produces their fixtures.
+	if len(s.Schemas) > 0 {
+		r := newRegistry(s.SchemaRegistryURL)
+		for _, sc := range s.Schemas {
+			b, err := os.ReadFile(sc.File)
+			if err != nil {
+				return fmt.Errorf(`failed to read the schema of subject "%s": %w`, sc.Subject, err)
+			}
+			id, err := r.register(sc.Subject, sc.Type, platform.Text(b))
+			if err != nil {
+				return fmt.Errorf(`failed to register the schema of subject "%s": %w`, sc.Subject, err)
+			}
+			fmt.Fprintf(out, "Registered the schema of subject \"%s\" with ID %d.\n", sc.Subject, id)
+			done.AddDetail("register", "the schema of subject", sc.Subject, fmt.Sprintf("from %s with ID %d", sc.File, id))
+		}
+	}
+
+	var created []topic
+	for _, t := range s.Topics {
+		if existing[t.Name] {
+			if !reset {
+				fmt.Fprintf(out, "Topic \"%s\" already exists, not producing its fixtures.\n", t.Name)
+				continue
+			}
+			if _, err := cli.Run("local", "kafka", "topic", "delete", t.Name, "--force"); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Deleted topic \"%s\".\n", t.Name)
+			done.Add("delete", "topic", t.Name)
+		}
+
+		createArgs := []string{"local", "kafka", "topic", "create", t.Name}
+		if t.Partitions > 0 {
+			createArgs = append(createArgs, "--partitions", strconv.Itoa(t.Partitions))
+		}
+		if len(t.Configs) > 0 {
+			createArgs = append(createArgs, "--config", formatConfigs(t.Configs))
+		}
+		if _, err := cli.Run(createArgs...); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created topic \"%s\".\n", t.Name)
+		done.Add("create", "topic", t.Name)
+		created = append(created, t)
+	}
+
+	var errs []error
+	for _, t := range created {
+		if t.Fixtures == "" {
+			continue
+		}
+		n, err := produce(t)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`failed to produce the fixtures of topic "%s": %w`, t.Name, err))
+			continue
+		}
+		fmt.Fprintf(out, "Produced %d records to topic \"%s\".\n", n, t.Name)
+		done.AddDetail("produce", "the fixtures of topic", t.Name, fmt.Sprintf("from %s, %d records", t.Fixtures, n))
+	}
+	return errors.Join(errs...)
+}
+
+// planApply is what apply would do, for --dry-run. When the local Kafka cluster isn't running, none of the topics
+// exist yet.
+func planApply(s spec, reset bool) dryrun.Plan {
+	var plan dryrun.Plan
+	existing, err := localTopics()
+	if err != nil {
+		existing = map[string]bool{}
+		plan.Add("start", "the local Kafka cluster", "")
+	}
+	for _, sc := range s.Schemas {
+		plan.AddDetail("register", "the schema of subject", sc.Subject, "from "+sc.File)
+	}
+	for _, t := range s.Topics {
+		if existing[t.Name] {
+			if !reset {
+				continue
+			}
+			plan.Add("delete", "topic", t.Name)
+		}
+		plan.Add("create", "topic", t.Name)
+		if t.Fixtures != "" {
+			plan.AddDetail("produce", "the fixtures of topic", t.Name, "from "+t.Fixtures)
+		}
+	}
+	return plan
+}
+
+// localTopics returns the names of the topics of the local Kafka cluster, and fails if it isn't running.
+func localTopics() (map[string]bool, error) {
+	var listed []struct {
+		Name string `json:"name"`
+	}
+	if err := cli.JSON(&listed, "local", "kafka", "topic", "list"); err != nil {
+		return nil, err
+	}
+
+	topics := map[string]bool{}
+	for _, t := range listed {
+		topics[t.Name] = true
+	}
+	return topics, nil
+}
+
+// produce produces the records of the topic's fixtures with "confluent local kafka topic produce", which reads them
+// from stdin, and returns how many were produced. Blank lines are skipped.
+func produce(t topic) (int, error) {
+	f, err := os.Open(t.Fixtures)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var records bytes.Buffer
+	n := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if t.KeyDelimiter != "" && !strings.Contains(line, t.KeyDelimiter) {
+			return 0, fmt.Errorf(`record %d doesn't have a key before the delimiter "%s"`, n+1, t.KeyDelimiter)
+		}
+		records.WriteString(line + "\n")
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	args := []string{"local", "kafka", "topic", "produce", t.Name}
+	if t.KeyDelimiter != "" {
+		args = append(args, "--parse-key", "--delimiter", t.KeyDelimiter)
+	}
+
+	if _, err := (cli.Command{Args: args, Stdin: &records}).Output(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func formatConfigs(configs map[string]string) string {
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", key, configs[key])
+	}
+	return strings.Join(pairs, ",")
+}
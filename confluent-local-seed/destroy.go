@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newDestroyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "destroy [spec]",
+		Short: "Stop the local Kafka cluster and delete what was seeded.",
+		Long:  "Delete the subjects of the spec's schemas for good, and stop the local Kafka cluster with \"confluent local kafka stop\", which deletes its topics and records, after prompting for confirmation. Without a spec, only the cluster is stopped.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  destroy,
+		Example: `confluent local seed destroy seed.yml --dry-run
+confluent local seed destroy --force`,
+	}
+
+	cmd.Flags().Bool("keep-kafka", false, "Only delete the subjects, and leave the local Kafka cluster running.")
+	dryrun.AddFlag(cmd, "Print what would be destroyed without destroying it.")
+	cmd.Flags().Bool("force", false, "Destroy without prompting for confirmation.")
+	output.AddFlag(cmd, "Format of what was destroyed")
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	return cmd
+}
+
+func destroy(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	keepKafka, err := cmd.Flags().GetBool("keep-kafka")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	var s spec
+	if len(args) == 1 {
+		if s, err = readSpec(args[0]); err != nil {
+			return err
+		}
+	}
+	if keepKafka && len(s.Schemas) == 0 {
+		return fmt.Errorf("nothing to destroy, the spec has no schemas and --keep-kafka is set")
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, sc := range s.Schemas {
+			plan.Add("delete", "subject", sc.Subject)
+		}
+		if !keepKafka {
+			plan.AddDetail("stop", "the local Kafka cluster", "", "and delete its topics")
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	var descriptions []string
+	if len(s.Schemas) > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("delete %d subjects", len(s.Schemas)))
+	}
+	if !keepKafka {
+		descriptions = append(descriptions, "stop the local Kafka cluster and delete its topics")
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to %s?", strings.Join(descriptions, " and ")))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not destroying anything.")
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+	}
+
+	out := output.Progress(cmd)
+	var destroyed dryrun.Plan
+	if len(s.Schemas) > 0 {
+		r := newRegistry(s.SchemaRegistryURL)
+		for _, sc := range s.Schemas {
+			deleted, err := r.deleteSubject(sc.Subject)
+			if err != nil {
+				err = fmt.Errorf(`failed to delete subject "%s": %w`, sc.Subject, err)
+				return errors.Join(err, dryrun.PrintApplied(cmd, destroyed))
+			}
+			if deleted {
+				fmt.Fprintf(out, "Deleted subject \"%s\".\n", sc.Subject)
+				destroyed.Add("delete", "subject", sc.Subject)
+			}
+		}
+	}
+
+	if !keepKafka {
+		if _, err := cli.Run("local", "kafka", "stop"); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, destroyed))
+		}
+		fmt.Fprintln(out, "Stopped the local Kafka cluster.")
+		destroyed.AddDetail("stop", "the local Kafka cluster", "", "and delete its topics")
+	}
+	return dryrun.PrintApplied(cmd, destroyed)
+}
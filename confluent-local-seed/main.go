@@ -0,0 +1,40 @@
+package main
+
+import (
+	_ "embed"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "seed",
+		Short: "Seed a local Kafka cluster for development.",
+		Long:  "Start a local Kafka cluster with \"confluent local kafka start\", and create the topics, register the schemas, and produce the fixtures declared in a spec with \"confluent local seed apply\", then throw it all away with \"confluent local seed destroy\", instead of hand-rolling a setup script.",
+		Args:  cobra.NoArgs,
+		Example: `confluent local seed apply seed.yml
+confluent local seed apply seed.yml --reset
+confluent local seed destroy seed.yml --force`,
+	}
+
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.AddCommand(newApplyCommand())
+	cmd.AddCommand(newDestroyCommand())
+
+	completion.Add(&cmd, "confluent-local-seed")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-local-seed"))
+
+	exitcode.Execute(&cmd)
+}
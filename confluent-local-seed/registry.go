@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+)
+
+// registry is a client of the REST API of the local Schema Registry, since the CLI's local commands can't register
+// schemas.
+type registry struct {
+	url    string
+	client *http.Client
+}
+
+func newRegistry(u string) *registry {
+	return &registry{url: strings.TrimRight(u, "/"), client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (r *registry) do(method, path string, body, v any) (err error) {
+	defer func() { audit.Request(method, path, err) }()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, r.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the Schema Registry at %s, is it running? %w", r.url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&e); err == nil && e.Message != "" {
+			return &registryError{code: e.ErrorCode, message: e.Message}
+		}
+		return &registryError{code: res.StatusCode, message: res.Status}
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type registryError struct {
+	code    int
+	message string
+}
+
+func (e *registryError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.message, e.code)
+}
+
+// register registers the schema under the subject, and returns its ID. Registering a schema which is already the
+// subject's returns its existing ID.
+func (r *registry) register(subject, schemaType, schema string) (int, error) {
+	body := map[string]string{"schema": schema}
+	// Avro is the default type.
+	if schemaType != "AVRO" {
+		body["schemaType"] = schemaType
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	if err := r.do(http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &registered); err != nil {
+		return 0, err
+	}
+	return registered.ID, nil
+}
+
+// deleteSubject deletes the subject and its schemas for good, and reports whether it existed.
+func (r *registry) deleteSubject(subject string) (bool, error) {
+	path := "/subjects/" + url.PathEscape(subject)
+	if err := r.do(http.MethodDelete, path, nil, nil); err != nil {
+		if e, ok := err.(*registryError); ok && e.code == 40401 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, r.do(http.MethodDelete, path+"?permanent=true", nil, nil)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultSchemaRegistryURL = "http://localhost:8081"
+
+// A spec declares the topics, schemas, and fixtures to seed the local Kafka cluster with, e.g.:
+//
+//	schema_registry_url: http://localhost:8081
+//	topics:
+//	  - name: orders
+//	    partitions: 3
+//	    configs:
+//	      cleanup.policy: compact
+//	    fixtures: fixtures/orders.jsonl
+//	    key_delimiter: "|"
+//	schemas:
+//	  - subject: orders-value
+//	    file: schemas/orders.avsc
+//
+// Paths are relative to the spec. Fixtures have a record per line, with the key before the delimiter if one is set.
+type spec struct {
+	SchemaRegistryURL string   `yaml:"schema_registry_url"`
+	Topics            []topic  `yaml:"topics"`
+	Schemas           []schema `yaml:"schemas"`
+}
+
+type topic struct {
+	Name         string            `yaml:"name"`
+	Partitions   int               `yaml:"partitions"`
+	Configs      map[string]string `yaml:"configs"`
+	Fixtures     string            `yaml:"fixtures"`
+	KeyDelimiter string            `yaml:"key_delimiter"`
+}
+
+type schema struct {
+	Subject string `yaml:"subject"`
+	File    string `yaml:"file"`
+	// Type is AVRO, PROTOBUF, or JSON, and defaults to the one of the file's extension.
+	Type string `yaml:"type"`
+}
+
+// schemaTypes are the schema types of file extensions.
+var schemaTypes = map[string]string{
+	".avsc":  "AVRO",
+	".proto": "PROTOBUF",
+	".json":  "JSON",
+}
+
+func readSpec(path string) (spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return spec{}, fmt.Errorf("failed to read the spec: %w", err)
+	}
+
+	var s spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return spec{}, fmt.Errorf(`failed to parse the spec "%s": %w`, path, err)
+	}
+
+	if s.SchemaRegistryURL == "" {
+		s.SchemaRegistryURL = defaultSchemaRegistryURL
+	}
+	dir := filepath.Dir(path)
+
+	names := map[string]bool{}
+	for i, t := range s.Topics {
+		if t.Name == "" {
+			return spec{}, fmt.Errorf("topic %d needs a name", i+1)
+		}
+		if names[t.Name] {
+			return spec{}, fmt.Errorf(`topic "%s" is declared more than once`, t.Name)
+		}
+		names[t.Name] = true
+		if t.Partitions < 0 {
+			return spec{}, fmt.Errorf(`the partitions of topic "%s" must be at least 1`, t.Name)
+		}
+		if t.Fixtures != "" && !filepath.IsAbs(t.Fixtures) {
+			s.Topics[i].Fixtures = filepath.Join(dir, t.Fixtures)
+		}
+	}
+
+	for i, sc := range s.Schemas {
+		if sc.Subject == "" || sc.File == "" {
+			return spec{}, fmt.Errorf("schema %d needs a subject and a file", i+1)
+		}
+		if !filepath.IsAbs(sc.File) {
+			s.Schemas[i].File = filepath.Join(dir, sc.File)
+		}
+		if sc.Type == "" {
+			t, ok := schemaTypes[strings.ToLower(filepath.Ext(sc.File))]
+			if !ok {
+				return spec{}, fmt.Errorf(`the type of the schema of subject "%s" can't be told from its file, set it to AVRO, PROTOBUF, or JSON`, sc.Subject)
+			}
+			s.Schemas[i].Type = t
+		}
+		s.Schemas[i].Type = strings.ToUpper(s.Schemas[i].Type)
+	}
+
+	return s, nil
+}
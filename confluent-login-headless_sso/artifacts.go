@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// saveArtifacts saves a screenshot, the URL, and the HTML of the browser's current page, to diagnose a failed login
+// without having to reproduce it. The browser context must not have expired, even if the login timed out.
+func saveArtifacts(browser context.Context, dir string) error {
+	ctx, cancel := context.WithTimeout(browser, 5*time.Second)
+	defer cancel()
+
+	var (
+		location   string
+		html       string
+		screenshot []byte
+	)
+
+	if err := chromedp.Run(ctx,
+		chromedp.Location(&location),
+		chromedp.OuterHTML("html", &html, chromedp.ByQuery),
+		chromedp.FullScreenshot(&screenshot, 100),
+	); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	prefix := filepath.Join(dir, "headless-sso-"+time.Now().Format("20060102-150405.000"))
+
+	files := map[string][]byte{
+		prefix + ".png":  screenshot,
+		prefix + ".url":  []byte(location + "\n"),
+		prefix + ".html": []byte(html),
+	}
+	for name, b := range files {
+		if err := os.WriteFile(name, b, 0o600); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Saved debug artifacts of %s to %s.*\n", location, prefix)
+	return nil
+}
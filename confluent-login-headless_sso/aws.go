@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// readAWS reads credentials from an AWS Secrets Manager secret or an SSM parameter, using the default AWS credential
+// chain. The region is taken from the ARN.
+func readAWS(ctx context.Context, secretARN string) (storedCredentials, error) {
+	parsed, err := arn.Parse(secretARN)
+	if err != nil {
+		return storedCredentials{}, fmt.Errorf(`invalid AWS ARN "%s": %w`, secretARN, err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(parsed.Region))
+	if err != nil {
+		return storedCredentials{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	var value string
+	switch parsed.Service {
+	case "secretsmanager":
+		out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretARN),
+		})
+		if err != nil {
+			return storedCredentials{}, fmt.Errorf("failed to read credentials from AWS Secrets Manager: %w", err)
+		}
+		value = aws.ToString(out.SecretString)
+	case "ssm":
+		out, err := ssm.NewFromConfig(cfg).GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(secretARN),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return storedCredentials{}, fmt.Errorf("failed to read credentials from AWS SSM Parameter Store: %w", err)
+		}
+		value = aws.ToString(out.Parameter.Value)
+	default:
+		return storedCredentials{}, fmt.Errorf(`unsupported AWS service "%s", the ARN must be a Secrets Manager secret or an SSM parameter`, parsed.Service)
+	}
+
+	var secret storedCredentials
+	if err := json.Unmarshal([]byte(value), &secret); err != nil {
+		return storedCredentials{}, fmt.Errorf("failed to decode AWS secret, which must be a JSON object: %w", err)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os/exec"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+)
+
+const (
+	confluentRoot  = `//div[@id="cc-root"]`
+	confluentToken = `//div[@id="token"]`
+)
+
+// browserConfig configures how the browser is started.
+type browserConfig struct {
+	path    string
+	proxy   *url.URL
+	headful bool
+}
+
+// newBrowser starts a browser, which is headless unless configured otherwise, and is closed when the returned function
+// is called.
+func newBrowser(cfg browserConfig) (context.Context, context.CancelFunc, error) {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		// Keep cross-origin iframes, such as the Duo Prompt, in the same process so that they can be queried.
+		chromedp.Flag("disable-site-isolation-trials", true),
+		chromedp.Flag("disable-features", "IsolateOrigins,site-per-process"),
+	)
+	opts = append(opts, chromedp.ExecPath(cfg.path))
+	if cfg.proxy != nil {
+		opts = append(opts, proxyOptions(cfg.proxy)...)
+	}
+	if cfg.headful {
+		opts = append(opts, chromedp.Flag("headless", false))
+	}
+
+	ctx, cancelAllocator := chromedp.NewExecAllocator(context.Background(), opts...)
+	browserOpts := []chromedp.ContextOption{chromedp.WithLogf(logfAt(slog.LevelInfo)), chromedp.WithErrorf(logfAt(slog.LevelError))}
+	if slog.Default().Enabled(context.Background(), logging.LevelTrace) {
+		browserOpts = append(browserOpts, chromedp.WithDebugf(logfAt(logging.LevelTrace)))
+	}
+	ctx, cancelBrowser := chromedp.NewContext(ctx, browserOpts...)
+
+	cancel := func() {
+		cancelBrowser()
+		cancelAllocator()
+	}
+
+	// The browser lives as long as the context of the first action run in it, so it's started here without a timeout.
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, nil, exitcode.With(exitBrowserLaunch, fmt.Errorf(`failed to start the browser "%s": %w`, cfg.path, err))
+	}
+
+	return ctx, cancel, nil
+}
+
+// findBrowser returns the browser at the path, or if no path is given, the first browser installed in one of the
+// platform's usual locations.
+func findBrowser(path string) (string, error) {
+	if path != "" {
+		found, err := exec.LookPath(path)
+		if err != nil {
+			return "", fmt.Errorf(`no browser was found at "%s": %w`, path, err)
+		}
+		return found, nil
+	}
+
+	for _, path := range browserLocations() {
+		if found, err := exec.LookPath(path); err == nil {
+			return found, nil
+		}
+	}
+	return "", fmt.Errorf("no Chrome, Chromium, or Edge browser was found, install one or pass its path with --browser-path")
+}
+
+// navigate opens the login URL, waiting up to the navigation timeout for the first page to load.
+func navigate(ctx context.Context, c credentials, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.navigationTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := chromedp.Run(ctx, chromedp.Navigate(url)); errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+		return exitcode.With(exitNetwork, fmt.Errorf("the login page did not load within %s: %w", c.navigationTimeout, err))
+	} else if err != nil {
+		return exitcode.With(exitNetwork, fmt.Errorf("failed to load the login page: %w", err))
+	}
+	slog.Debug("Loaded the login page", "elapsed", time.Since(start))
+	return nil
+}
+
+// token reads the authentication code from the Confluent Cloud page which the IdP redirects to.
+func token(ctx context.Context) (string, error) {
+	var code string
+	if err := chromedp.Run(ctx, chromedp.Text(confluentToken, &code)); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// sendPasscode answers an MFA challenge by entering a one-time passcode into the field and submitting it.
+func sendPasscode(ctx context.Context, c credentials, provider, field, submit string) error {
+	passcode, err := c.passcode()
+	if err != nil {
+		return err
+	}
+	if passcode == "" {
+		return errNoPasscode(provider)
+	}
+	logging.AddSecrets(passcode)
+
+	return chromedp.Run(ctx, chromedp.SendKeys(field, passcode), chromedp.Click(submit))
+}
+
+// findVisible returns the index of the first selector which matches a visible element, or -1. Selectors are XPath if
+// they start with "/" or "(", and CSS otherwise.
+const findVisible = `(%s).findIndex(sel => {
+	const node = /^[\/(]/.test(sel)
+		? document.evaluate(sel, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue
+		: document.querySelector(sel);
+	return node !== null && node.getClientRects().length > 0;
+})`
+
+// isVisible reports whether the selector currently matches a visible element.
+func isVisible(ctx context.Context, sel string) bool {
+	b, err := json.Marshal([]string{sel})
+	if err != nil {
+		return false
+	}
+
+	i := -1
+	return chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(findVisible, b), &i)) == nil && i == 0
+}
+
+// waitAny waits up to the timeout for one of the selectors to become visible and returns its index. Polling happens
+// from Go, rather than in the page, so that the wait survives the redirects between IdP pages. Intermediate pages, such
+// as an organization picker, are passed as they appear, and restart the timeout.
+func waitAny(ctx context.Context, timeout time.Duration, sels ...string) (int, error) {
+	steps := stepperFrom(ctx)
+
+	for {
+		indexes, matches := steps.pending()
+
+		start := time.Now()
+		i, err := pollVisible(ctx, timeout, append(matches, sels...))
+		if err != nil {
+			return -1, err
+		}
+
+		if i < len(matches) {
+			if err := steps.take(ctx, indexes[i], timeout); err != nil {
+				return -1, err
+			}
+			continue
+		}
+
+		i -= len(matches)
+		slog.Debug("Found an element", "selector", sels[i], "elapsed", time.Since(start))
+		return i, nil
+	}
+}
+
+func pollVisible(ctx context.Context, timeout time.Duration, sels []string) (int, error) {
+	b, err := json.Marshal(sels)
+	if err != nil {
+		return -1, err
+	}
+	expression := fmt.Sprintf(findVisible, b)
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		i := -1
+		if err := chromedp.Run(ctx, chromedp.Evaluate(expression, &i)); err == nil && i >= 0 {
+			return i, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if parent.Err() == nil {
+				return -1, exitcode.With(exitcode.NotFound, fmt.Errorf("no expected element appeared within %s: %w", timeout, ctx.Err()))
+			}
+			return -1, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// browserLocations are where Chrome, Chromium, and Edge are installed on macOS.
+func browserLocations() []string {
+	var locations []string
+	for _, dir := range []string{"/Applications", filepath.Join(os.Getenv("HOME"), "Applications")} {
+		locations = append(locations,
+			filepath.Join(dir, "Google Chrome.app/Contents/MacOS/Google Chrome"),
+			filepath.Join(dir, "Chromium.app/Contents/MacOS/Chromium"),
+			filepath.Join(dir, "Microsoft Edge.app/Contents/MacOS/Microsoft Edge"),
+		)
+	}
+	return locations
+}
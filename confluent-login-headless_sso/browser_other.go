@@ -0,0 +1,20 @@
+//go:build !windows && !darwin
+
+package main
+
+// browserLocations are the names of Chrome, Chromium, and Edge on the PATH, as installed by Linux distributions.
+func browserLocations() []string {
+	return []string{
+		"headless_shell",
+		"headless-shell",
+		"chromium",
+		"chromium-browser",
+		"google-chrome",
+		"google-chrome-stable",
+		"microsoft-edge",
+		"microsoft-edge-stable",
+		"/usr/bin/google-chrome",
+		"/snap/bin/chromium",
+		"chrome",
+	}
+}
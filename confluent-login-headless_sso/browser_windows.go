@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// browserLocations are where Chrome, Chromium, and Edge are installed on Windows.
+func browserLocations() []string {
+	var locations []string
+	for _, dir := range []string{os.Getenv("ProgramFiles"), os.Getenv("ProgramFiles(x86)"), os.Getenv("LocalAppData")} {
+		if dir == "" {
+			continue
+		}
+		locations = append(locations,
+			filepath.Join(dir, `Google\Chrome\Application\chrome.exe`),
+			filepath.Join(dir, `Chromium\Application\chrome.exe`),
+			filepath.Join(dir, `Microsoft\Edge\Application\msedge.exe`),
+		)
+	}
+	return append(locations, "chrome.exe", "msedge.exe")
+}
@@ -2,40 +2,119 @@ package main
 
 import (
 	"bufio"
-	"os"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"os/exec"
+	"regexp"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
 )
 
-// capture the stdout and stderr streams to the command, and interactively pass data in to stdin.
-func capture(command *exec.Cmd, stdin, stdout, stderr chan string) {
-	stdinPipe, _ := command.StdinPipe()
-	stdoutPipe, _ := command.StdoutPipe()
-	stderrPipe, _ := command.StderrPipe()
+// promptPattern matches output which ends in a colon without a newline, which is how the CLI prompts for input.
+var promptPattern = regexp.MustCompile(`\S: ?$`)
+
+// A frame is a line of output, or a prompt which the CLI is waiting for an answer to. Output which ends without a
+// newline is neither, and is framed as it is.
+type frame struct {
+	text   string
+	prompt bool
+}
+
+// capture the stdout and stderr streams to the command a frame at a time, and interactively pass data in to stdin.
+// Answers are echoed to stdout, as they would be in a terminal.
+func capture(ctx context.Context, command *exec.Cmd, stdin chan string, stdout, stderr chan frame) error {
+	stdinPipe, err := command.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdoutPipe, err := command.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderrPipe, err := command.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := command.Start(); err != nil {
+		return exitcode.With(exitCLIFailure, fmt.Errorf("failed to run confluent login: %w", err))
+	}
 
 	go func() {
 		for in := range stdin {
-			_, _ = stdinPipe.Write([]byte(in))
-			stdout <- in
+			_, _ = io.WriteString(stdinPipe, in)
+			send(ctx, stdout, frame{text: in})
 		}
 	}()
 
+	// The pipes must be read to the end before waiting for the command, which closes them.
+	var wg sync.WaitGroup
+	wg.Add(2)
 	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
-		scanner.Split(bufio.ScanBytes)
-		for scanner.Scan() {
-			stdout <- scanner.Text()
-		}
+		defer wg.Done()
+		frames(ctx, stdoutPipe, stdout)
 	}()
-
 	go func() {
-		scanner := bufio.NewScanner(stderrPipe)
-		scanner.Split(bufio.ScanBytes)
-		for scanner.Scan() {
-			stderr <- scanner.Text()
-		}
+		defer wg.Done()
+		frames(ctx, stderrPipe, stderr)
 	}()
+	wg.Wait()
+
+	if err := command.Wait(); err != nil {
+		return exitcode.With(exitCLIFailure, fmt.Errorf("confluent login failed: %w", err))
+	}
+	return nil
+}
+
+// frames splits the output into lines, and prompts as soon as the CLI stops writing after one.
+func frames(ctx context.Context, r io.Reader, out chan frame) {
+	// Reads are smaller than the reader's buffer, so whether the rest of a write has arrived can be told from it.
+	br := bufio.NewReader(r)
+	buf := make([]byte, 512)
+
+	var pending []byte
+	for {
+		n, err := br.Read(buf)
+		pending = append(pending, buf[:n]...)
+
+		for {
+			i := bytes.IndexByte(pending, '\n')
+			if i < 0 {
+				break
+			}
+			if !send(ctx, out, frame{text: string(pending[:i+1])}) {
+				return
+			}
+			pending = pending[i+1:]
+		}
+
+		// A partial line is only a prompt once nothing more is buffered, so that a line which happens to be split after
+		// a colon isn't mistaken for one.
+		if len(pending) > 0 && br.Buffered() == 0 && promptPattern.Match(pending) {
+			if !send(ctx, out, frame{text: string(pending), prompt: true}) {
+				return
+			}
+			pending = nil
+		}
+
+		if err != nil {
+			if len(pending) > 0 {
+				send(ctx, out, frame{text: string(pending)})
+			}
+			return
+		}
+	}
+}
 
-	if err, ok := command.Run().(*exec.ExitError); err != nil && ok {
-		os.Exit(err.ExitCode())
+// send a frame, unless the login has already ended and nothing is receiving.
+func send(ctx context.Context, out chan frame, f frame) bool {
+	select {
+	case out <- f:
+		return true
+	case <-ctx.Done():
+		return false
 	}
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// credentials used to sign in to the SSO provider, and to answer its MFA challenges, along with how long to wait for
+// its pages.
+type credentials struct {
+	username   string
+	password   string
+	otp        string
+	totpSecret string
+	duoTimeout time.Duration
+
+	webauthnFallback bool
+	magicLink        *magicLink
+
+	navigationTimeout time.Duration
+	selectorTimeout   time.Duration
+}
+
+// storedCredentials are read from a secret in Vault or AWS, which is a JSON object with the same keys in both.
+type storedCredentials struct {
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	TOTPSecret string `json:"totp_secret"`
+}
+
+// hasPasscode reports whether an MFA challenge can be answered with a one-time passcode.
+func (c credentials) hasPasscode() bool {
+	return c.otp != "" || c.totpSecret != ""
+}
+
+// passcode returns a one-time passcode to answer an MFA challenge with, or an empty string if none is available.
+// Passcodes are generated on demand, so that they are still valid when the challenge is answered.
+func (c credentials) passcode() (string, error) {
+	if c.totpSecret != "" {
+		return totp(c.totpSecret, time.Now())
+	}
+	return c.otp, nil
+}
+
+func errNoPasscode(provider string) error {
+	return mfaRequired(fmt.Errorf("%s requires a one-time passcode, which can be generated with --totp-secret or provided with --otp", provider))
+}
+
+// readPassword reads a password from the first line of r, like "docker login --password-stdin".
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"gopkg.in/yaml.v3"
+)
+
+// never is used in place of optional selectors which aren't configured, and matches no element.
+const never = `//*[false()]`
+
+// customSelectors locate the elements of a bespoke IdP's login pages for --provider custom. Each selector is XPath if
+// it starts with "/" or "(", and CSS otherwise, e.g.:
+//
+//	username: "#username"
+//	next: "#next"
+//	password: "#password"
+//	submit: "button[type=submit]"
+//	mfa: "input[name=otp]"
+//	error: ".alert-error"
+type customSelectors struct {
+	Username string `yaml:"username"`
+	// Next submits the username, if it's asked for on its own page. Defaults to Submit.
+	Next     string `yaml:"next"`
+	Password string `yaml:"password"`
+	Submit   string `yaml:"submit"`
+	// MFA is the field which a one-time passcode is entered into, and is submitted with MFASubmit, which defaults to
+	// Submit.
+	MFA       string `yaml:"mfa"`
+	MFASubmit string `yaml:"mfa_submit"`
+	// MagicLinkSend requests a magic link, for passwordless accounts, and MagicLink is shown once it has been emailed.
+	MagicLinkSend string `yaml:"magic_link_send"`
+	MagicLink     string `yaml:"magic_link"`
+	Error         string `yaml:"error"`
+}
+
+func readSelectors(path string) (*customSelectors, error) {
+	if path == "" {
+		return nil, fmt.Errorf(`--selectors-file is required with --provider custom`)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read selectors: %w", err)
+	}
+
+	sel := new(customSelectors)
+	if err := yaml.Unmarshal(b, sel); err != nil {
+		return nil, fmt.Errorf(`failed to parse selectors from "%s": %w`, path, err)
+	}
+
+	for key, value := range map[string]string{"username": sel.Username, "submit": sel.Submit} {
+		if value == "" {
+			return nil, fmt.Errorf(`"%s" must set the "%s" selector`, path, key)
+		}
+	}
+
+	if sel.Password == "" && sel.MagicLink == "" {
+		return nil, fmt.Errorf(`"%s" must set the "password" or "magic_link" selector`, path)
+	}
+
+	if sel.Next == "" {
+		sel.Next = sel.Submit
+	}
+	if sel.Password == "" {
+		sel.Password = never
+	}
+	if sel.MFA == "" {
+		sel.MFA = never
+	}
+	if sel.MFASubmit == "" {
+		sel.MFASubmit = sel.Submit
+	}
+	if sel.MagicLinkSend == "" {
+		sel.MagicLinkSend = never
+	}
+	if sel.MagicLink == "" {
+		sel.MagicLink = never
+	}
+	if sel.Error == "" {
+		sel.Error = never
+	}
+
+	return sel, nil
+}
+
+// signIn fills in each configured field as it appears, until the IdP redirects to Confluent Cloud. The username and
+// password may be asked for on the same page or on separate pages, and passwordless accounts may be sent a magic link.
+func (sel *customSelectors) signIn(ctx context.Context, url string, c credentials) (string, error) {
+	started := time.Now()
+
+	if err := navigate(ctx, c, url); err != nil {
+		return "", err
+	}
+
+	sentUsername, sentPassword, sentPasscode, sentLinkRequest, openedLink := false, false, false, false, false
+
+	for {
+		i, err := waitAny(ctx, c.selectorTimeout, confluentRoot, sel.Error, webauthnRequested, sel.MFA, sel.MagicLink, sel.MagicLinkSend, sel.Password, sel.Username)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect from the IdP to Confluent Cloud: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return token(ctx)
+		case 1:
+			var message string
+			if err := chromedp.Run(ctx, chromedp.Text(sel.Error, &message)); err != nil {
+				return "", err
+			}
+			return "", badCredentials(fmt.Errorf("failed to sign in to the IdP: %s", strings.TrimSpace(message)))
+		case 2:
+			err = webauthn(ctx, c, "The IdP", "")
+		case 3:
+			if sentPasscode {
+				continue
+			}
+			sentPasscode = true
+			err = sendPasscode(ctx, c, "The IdP", sel.MFA, sel.MFASubmit)
+		case 4:
+			if openedLink {
+				continue
+			}
+			openedLink = true
+			if c.magicLink == nil {
+				return "", mfaRequired(fmt.Errorf("the IdP emailed a magic link, which can be retrieved with --magic-link"))
+			}
+
+			var link string
+			link, err = c.magicLink.wait(ctx, started)
+			if err == nil {
+				err = navigate(ctx, c, link)
+			}
+		case 5:
+			if sentLinkRequest {
+				continue
+			}
+			sentLinkRequest = true
+			err = chromedp.Run(ctx, chromedp.Click(sel.MagicLinkSend))
+		case 6:
+			if sentPassword {
+				continue
+			}
+			sentPassword = true
+
+			var actions []chromedp.Action
+			if !sentUsername && isVisible(ctx, sel.Username) {
+				sentUsername = true
+				actions = append(actions, chromedp.SendKeys(sel.Username, c.username))
+			}
+			actions = append(actions, chromedp.SendKeys(sel.Password, c.password), chromedp.Click(sel.Submit))
+			err = chromedp.Run(ctx, actions...)
+		case 7:
+			if sentUsername {
+				continue
+			}
+			sentUsername = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(sel.Username, c.username), chromedp.Click(sel.Next))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// refreshRetryInterval is how long to wait before logging in again after a login fails, or when the expiry of the
+// session can't be read.
+const refreshRetryInterval = time.Minute
+
+// refresh stays resident and logs in with every profile again before its session expires, until interrupted.
+func (s state) refresh(cmd *cobra.Command, profiles []profile, before time.Duration) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for {
+		// The next login is scheduled for the session which expires first.
+		var next time.Time
+		for _, p := range profiles {
+			if err := s.loginAs(cmd, p); err != nil {
+				slog.Error("Login failed", "profile", p.Name, "error", err)
+				next = time.Now().Add(refreshRetryInterval)
+				continue
+			}
+
+			expiry, err := sessionExpiry()
+			if err != nil {
+				slog.Warn("Failed to read the session expiry", "error", err)
+				expiry = time.Now().Add(before + refreshRetryInterval)
+			}
+
+			if at := expiry.Add(-before); next.IsZero() || at.Before(next) {
+				next = at
+			}
+		}
+
+		if wait := time.Until(next); wait < refreshRetryInterval {
+			next = time.Now().Add(refreshRetryInterval)
+		}
+		slog.Info("Scheduled the next login", "at", next.Format(time.RFC3339))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// The traditional Duo Prompt is embedded in the provider's page, while the Universal Prompt is a page on
+	// duosecurity.com which the provider redirects to.
+	duoFrame     = `//iframe[@id="duo_iframe" or contains(@src,"duosecurity.com")]`
+	duoUniversal = `//*[@id="auth-view-wrapper"]`
+	duoPrompt    = duoFrame + " | " + duoUniversal
+
+	// Queried from the iframe's document, which only supports CSS selectors.
+	duoPushButton = `button.positive.auth-button`
+
+	duoTrustBrowser = `//button[@id="dont-trust-browser-button"]`
+	duoGone         = `//body[not(.//iframe[@id="duo_iframe" or contains(@src,"duosecurity.com")]) and not(.//*[@id="auth-view-wrapper"])]`
+)
+
+// duoPush sends a Duo push, if the prompt doesn't send one automatically, and waits for a human to approve it.
+func duoPush(ctx context.Context, c credentials) error {
+	if c.duoTimeout == 0 {
+		return mfaRequired(fmt.Errorf("Duo requires a push to be approved, which can be waited for with --duo-timeout"))
+	}
+
+	var frames []*cdp.Node
+	if err := chromedp.Run(ctx, chromedp.Nodes(duoFrame, &frames, chromedp.AtLeast(0))); err != nil {
+		return err
+	}
+	if len(frames) > 0 {
+		if err := chromedp.Run(ctx, chromedp.Click(duoPushButton, chromedp.ByQuery, chromedp.FromNode(frames[0]))); err != nil {
+			return fmt.Errorf("failed to send a Duo push: %w", err)
+		}
+	}
+
+	slog.Info("Waiting for the Duo push to be approved", "timeout", c.duoTimeout)
+
+	ctx, cancel := context.WithTimeout(ctx, c.duoTimeout)
+	defer cancel()
+
+	for {
+		i, err := waitAny(ctx, c.duoTimeout, duoGone, duoTrustBrowser)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return mfaRequired(fmt.Errorf("the Duo push was not approved within %s", c.duoTimeout))
+		} else if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			return nil
+		}
+
+		// "Is this your device?" is declined, since the browser profile is discarded after login.
+		if err := chromedp.Run(ctx, chromedp.Click(duoTrustBrowser)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	entraEmail        = `//input[@name="loginfmt"]`
+	entraPassword     = `//input[@name="passwd"]`
+	entraSubmit       = `//input[@id="idSIButton9"]`
+	entraStaySignedIn = `//input[@id="KmsiCheckboxField"]`
+	entraDecline      = `//input[@id="idBtn_Back"]`
+	entraOtherAccount = `//div[@id="otherTile"]`
+	entraAskLater     = `//a[@id="btnAskLater"]`
+	entraProofUp      = `//input[@id="idSubmit_ProofUp_Redirect"]`
+	entraCode         = `//input[@name="otc"]`
+	entraCodeSubmit   = `//input[@id="idSubmit_SAOTCC_Continue"]`
+	entraChooseCode   = `//div[@data-value="PhoneAppOTP"]`
+	entraAnotherWay   = `//a[@id="signInAnotherWay"]`
+	entraMFA          = `//div[@id="idDiv_SAOTCAS_Title" or @id="idDiv_SAOTCS_Title"]`
+	entraError        = `//div[@id="usernameError" or @id="passwordError" or @id="service_exception_message"]`
+)
+
+// entra signs in to Microsoft Entra ID (formerly Azure AD). Unlike other providers, the Microsoft login pages are a
+// single-page app which may show any number of interstitials, so each page is handled as it appears until Entra ID
+// redirects to Confluent Cloud.
+func entra(ctx context.Context, url string, c credentials) (string, error) {
+	if err := navigate(ctx, c, url); err != nil {
+		return "", err
+	}
+
+	// The email and password pages reuse the same submit button, so only submit each of them once.
+	sentEmail, sentPassword, sentPasscode := false, false, false
+
+	for {
+		i, err := waitAny(ctx, c.selectorTimeout, confluentRoot, entraError, webauthnRequested, entraCode, entraChooseCode, entraAnotherWay, duoPrompt, entraMFA, entraProofUp, entraOtherAccount, entraStaySignedIn, entraAskLater, entraPassword, entraEmail)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect from Entra ID to Confluent Cloud: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return token(ctx)
+		case 1:
+			var message string
+			if err := chromedp.Run(ctx, chromedp.Text(entraError, &message)); err != nil {
+				return "", err
+			}
+			return "", badCredentials(fmt.Errorf("failed to sign in to Entra ID: %s", strings.TrimSpace(message)))
+		case 2:
+			err = webauthn(ctx, c, "Entra ID", entraAnotherWay)
+		case 3:
+			if sentPasscode {
+				continue
+			}
+			sentPasscode = true
+			err = sendPasscode(ctx, c, "Entra ID", entraCode, entraCodeSubmit)
+		case 4:
+			// "Verify your identity" lists the registered methods, of which a verification code from an app is used.
+			if !c.hasPasscode() {
+				return "", errNoPasscode("Entra ID")
+			}
+			err = chromedp.Run(ctx, chromedp.Click(entraChooseCode))
+		case 5:
+			// "Approve sign in request" defaults to a push notification, so switch to a verification code instead.
+			if !c.hasPasscode() {
+				return "", errNoPasscode("Entra ID")
+			}
+			err = chromedp.Run(ctx, chromedp.Click(entraAnotherWay))
+		case 6:
+			err = duoPush(ctx, c)
+		case 7:
+			return "", mfaRequired(fmt.Errorf("Entra ID requires a second factor which is not supported, only verification codes from an authenticator app and Duo pushes are"))
+		case 8:
+			return "", mfaRequired(fmt.Errorf("Entra ID requires additional security information to be registered for this account"))
+		case 9:
+			// "Pick an account"
+			err = chromedp.Run(ctx, chromedp.Click(entraOtherAccount))
+		case 10:
+			// "Stay signed in?" is declined, since the browser profile is discarded after login.
+			err = chromedp.Run(ctx, chromedp.Click(entraDecline))
+		case 11:
+			// Conditional access policies may ask to register additional methods, which can be postponed.
+			err = chromedp.Run(ctx, chromedp.Click(entraAskLater))
+		case 12:
+			if sentPassword {
+				continue
+			}
+			sentPassword = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(entraPassword, c.password), chromedp.Click(entraSubmit))
+		case 13:
+			if sentEmail {
+				continue
+			}
+			sentEmail = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(entraEmail, c.username), chromedp.Click(entraSubmit))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+)
+
+// Exit codes of the failures which only a browser login has, so that automation can branch on them, e.g. to retry on
+// another machine rather than rotate a password. They're above the codes of internal/exitcode, which the other failures
+// exit with, such as exitcode.Auth for rejected credentials. They're documented in the README.
+const (
+	exitBrowserLaunch = 10
+	exitNetwork       = 11
+	exitCLIFailure    = 12
+	exitLocked        = 13
+)
+
+// rejectedError marks failures which the provider reported, such as invalid credentials or a second factor which can't
+// be answered, and which won't succeed if the login is retried.
+type rejectedError struct {
+	err error
+}
+
+func (e rejectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e rejectedError) Unwrap() error {
+	return e.err
+}
+
+func rejected(err error) error {
+	return rejectedError{err: err}
+}
+
+func isRejected(err error) bool {
+	var r rejectedError
+	return errors.As(err, &r)
+}
+
+// badCredentials is a rejection of the username or password by the provider.
+func badCredentials(err error) error {
+	return rejected(exitcode.With(exitcode.Auth, err))
+}
+
+// mfaRequired is a second factor which can't be answered with the credentials which were provided.
+func mfaRequired(err error) error {
+	return rejected(exitcode.With(exitcode.Auth, err))
+}
+
+// classified returns whether the error has the exit code of a class of failure.
+func classified(err error) bool {
+	var e *exitcode.Error
+	return errors.As(err, &e)
+}
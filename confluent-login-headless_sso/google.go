@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	googleEmail        = `//input[@name="identifier"]`
+	googleEmailNext    = `//div[@id="identifierNext"]//button`
+	googlePassword     = `//input[@name="Passwd"]`
+	googlePasswordNext = `//div[@id="passwordNext"]//button`
+	googleOtherAccount = `//div[@data-identifier=""]`
+	googleSpeedbump    = `//input[@id="confirm"]`
+	googlePasscode     = `//input[@name="totpPin"]`
+	googlePasscodeNext = `//div[@id="totpNext"]//button`
+	googleChooseCode   = `//*[@data-challengetype="6"]`
+	googleChallenge    = `//*[@data-challengetype] | //input[@name="idvPin" or @name="knowledgePreregisteredEmailResponse"]`
+	googleError        = `//div[@aria-live="assertive" and normalize-space()!=""]`
+)
+
+// google signs in to Google Workspace. Google uses an identifier-first flow, and may show an account chooser or
+// speedbump pages before redirecting to Confluent Cloud, so each page is handled as it appears.
+func google(ctx context.Context, url string, c credentials) (string, error) {
+	if err := navigate(ctx, c, url); err != nil {
+		return "", err
+	}
+
+	sentEmail, sentPassword, sentPasscode := false, false, false
+
+	for {
+		i, err := waitAny(ctx, c.selectorTimeout, confluentRoot, googleError, webauthnRequested, googlePasscode, googleChooseCode, googleChallenge, googleOtherAccount, googleSpeedbump, googlePassword, googleEmail)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect from Google to Confluent Cloud: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return token(ctx)
+		case 1:
+			var message string
+			if err := chromedp.Run(ctx, chromedp.Text(googleError, &message)); err != nil {
+				return "", err
+			}
+			return "", badCredentials(fmt.Errorf("failed to sign in to Google: %s", strings.TrimSpace(message)))
+		case 2:
+			err = webauthn(ctx, c, "Google", "")
+		case 3:
+			if sentPasscode {
+				continue
+			}
+			sentPasscode = true
+			err = sendPasscode(ctx, c, "Google", googlePasscode, googlePasscodeNext)
+		case 4:
+			// "Verify it's you" lists the available challenges, of which a code from Google Authenticator is used.
+			if !c.hasPasscode() {
+				return "", errNoPasscode("Google")
+			}
+			err = chromedp.Run(ctx, chromedp.Click(googleChooseCode))
+		case 5:
+			// Other challenges ask for a phone, a security key, or account recovery information.
+			return "", mfaRequired(fmt.Errorf("Google requires the account to be verified, which is not supported"))
+		case 6:
+			// "Choose an account"
+			err = chromedp.Run(ctx, chromedp.Click(googleOtherAccount))
+		case 7:
+			// New Workspace accounts are asked to accept the terms of service.
+			err = chromedp.Run(ctx, chromedp.Click(googleSpeedbump))
+		case 8:
+			if sentPassword {
+				continue
+			}
+			sentPassword = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(googlePassword, c.password), chromedp.Click(googlePasswordNext))
+		case 9:
+			if sentEmail {
+				continue
+			}
+			sentEmail = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(googleEmail, c.username), chromedp.Click(googleEmailNext))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
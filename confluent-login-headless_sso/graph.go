@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const graphURL = "https://graph.microsoft.com/v1.0"
+
+// graphMailbox reads a Microsoft 365 mailbox with the Microsoft Graph API, authenticating as an app registration with
+// the Mail.Read application permission, using the same environment variables as the Azure SDKs.
+type graphMailbox struct {
+	user string
+}
+
+func (m graphMailbox) findLink(ctx context.Context, since time.Time, pattern *regexp.Regexp) (string, error) {
+	token, err := graphToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"$filter":  {fmt.Sprintf("receivedDateTime ge %s", since.UTC().Format(time.RFC3339))},
+		"$orderby": {"receivedDateTime desc"},
+		"$select":  {"body"},
+		"$top":     {"10"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/users/%s/messages?%s", graphURL, url.PathEscape(m.user), query.Encode()), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var body struct {
+		Value []struct {
+			Body struct {
+				Content string `json:"content"`
+			} `json:"body"`
+		} `json:"value"`
+	}
+	if err := doGraph(req, &body); err != nil {
+		return "", fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	for _, message := range body.Value {
+		if link := findLinkIn(message.Body.Content, pattern); link != "" {
+			return link, nil
+		}
+	}
+	return "", nil
+}
+
+// graphToken gets an access token with the client credentials of an app registration.
+func graphToken(ctx context.Context) (string, error) {
+	tenant, clientID, secret := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_CLIENT_SECRET")
+	if tenant == "" || clientID == "" || secret == "" {
+		return "", fmt.Errorf("AZURE_TENANT_ID, AZURE_CLIENT_ID, and AZURE_CLIENT_SECRET must be set to read a mailbox with Microsoft Graph")
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {secret},
+		"scope":         {"https://graph.microsoft.com/.default"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(tenant)), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doGraph(req, &body); err != nil {
+		return "", fmt.Errorf("failed to get a Microsoft Graph token: %w", err)
+	}
+	return body.AccessToken, nil
+}
+
+func doGraph(req *http.Request, v any) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var body struct {
+			Error            any    `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&body)
+		if body.ErrorDescription != "" {
+			return fmt.Errorf("%s: %s", res.Status, body.ErrorDescription)
+		}
+		if e, ok := body.Error.(map[string]any); ok {
+			return fmt.Errorf("%s: %v", res.Status, e["message"])
+		}
+		return fmt.Errorf("%s", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// waitForUser lets a human complete the login in the visible browser window, such as by solving a CAPTCHA or verifying
+// a new device, and then reads the authentication code.
+func waitForUser(browser context.Context, timeout time.Duration) (string, error) {
+	slog.Info("Waiting for the login to be completed in the browser window", "timeout", timeout)
+
+	if _, err := waitAny(browser, timeout, confluentRoot); err != nil {
+		return "", rejected(fmt.Errorf("the login was not completed in the browser window within %s", timeout))
+	}
+
+	return token(browser)
+}
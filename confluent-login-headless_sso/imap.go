@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	imapLiteral      = regexp.MustCompile(`\{(\d+)\}$`)
+	imapInternalDate = regexp.MustCompile(`INTERNALDATE "([^"]+)"`)
+)
+
+// imapMailbox reads the inbox over IMAP with TLS, which most mail providers support with an app password.
+type imapMailbox struct {
+	server   string
+	username string
+	password string
+}
+
+// imapResponse is an untagged response, with the literals it contains, such as the body of a message.
+type imapResponse struct {
+	text     string
+	literals [][]byte
+}
+
+type imapConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+func (m imapMailbox) findLink(ctx context.Context, since time.Time, pattern *regexp.Regexp) (string, error) {
+	server := m.server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "993")
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: 10 * time.Second}}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c := &imapConn{conn: conn, r: bufio.NewReader(conn)}
+	if _, err := c.r.ReadString('\n'); err != nil {
+		return "", err
+	}
+
+	if _, err := c.command("LOGIN %s %s", imapQuote(m.username), imapQuote(m.password)); err != nil {
+		return "", fmt.Errorf("failed to log in to the mailbox: %w", err)
+	}
+	defer func() { _, _ = c.command("LOGOUT") }()
+
+	if _, err := c.command("EXAMINE INBOX"); err != nil {
+		return "", err
+	}
+
+	// SEARCH only supports dates, so messages are filtered by the time they were received after they're fetched.
+	res, err := c.command("SEARCH SINCE %s", since.UTC().Format("2-Jan-2006"))
+	if err != nil {
+		return "", err
+	}
+
+	var ids []string
+	for _, r := range res {
+		if fields := strings.Fields(r.text); len(fields) > 1 && fields[0] == "SEARCH" {
+			ids = append(ids, fields[1:]...)
+		}
+	}
+
+	// The newest messages are checked first.
+	for i := len(ids) - 1; i >= 0; i-- {
+		res, err := c.command("FETCH %s (INTERNALDATE BODY.PEEK[])", ids[i])
+		if err != nil {
+			return "", err
+		}
+
+		for _, r := range res {
+			m := imapInternalDate.FindStringSubmatch(r.text)
+			if m == nil || len(r.literals) == 0 {
+				continue
+			}
+			if received, err := time.Parse("_2-Jan-2006 15:04:05 -0700", m[1]); err != nil || received.Before(since) {
+				continue
+			}
+
+			text, err := messageText(r.literals[0])
+			if err != nil {
+				continue
+			}
+			if link := findLinkIn(text, pattern); link != "" {
+				return link, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// command sends a tagged command and returns the untagged responses, or an error if the command doesn't complete
+// with OK.
+func (c *imapConn) command(format string, args ...any) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, err
+	}
+
+	var res []imapResponse
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if strings.HasPrefix(line, tag+" ") {
+			if status := strings.TrimPrefix(line, tag+" "); !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("IMAP: %s", status)
+			}
+			return res, nil
+		}
+
+		if !strings.HasPrefix(line, "* ") {
+			continue
+		}
+
+		// Literals are sent as "{size}" at the end of a line, followed by that many bytes and the rest of the line.
+		r := imapResponse{text: strings.TrimPrefix(line, "* ")}
+		for m := imapLiteral.FindStringSubmatch(line); m != nil; m = imapLiteral.FindStringSubmatch(line) {
+			size, err := strconv.Atoi(m[1])
+			if err != nil {
+				return nil, err
+			}
+
+			literal := make([]byte, size)
+			if _, err := io.ReadFull(c.r, literal); err != nil {
+				return nil, err
+			}
+			r.literals = append(r.literals, literal)
+
+			line, err = c.r.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			line = strings.TrimRight(line, "\r\n")
+			r.text += line
+		}
+		res = append(res, r)
+	}
+}
+
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+// keychainService identifies the plugin's entries in the macOS Keychain, Windows Credential Manager, or libsecret.
+const keychainService = "confluent-login-headless_sso"
+
+func newCredentialsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Manage SSO passwords stored in the OS keychain.",
+		Long:  "Manage SSO passwords stored in the macOS Keychain, Windows Credential Manager, or libsecret. Stored passwords are used when logging in without --password.",
+	}
+
+	set := &cobra.Command{
+		Use:     "set",
+		Short:   "Store an SSO password in the OS keychain.",
+		RunE:    setCredentials,
+		Example: "confluent login headless-sso credentials set --email example@confluent.io < password.txt",
+	}
+	set.Flags().String("email", "", "Confluent Cloud SSO email.")
+	cobra.CheckErr(set.MarkFlagRequired("email"))
+
+	get := &cobra.Command{
+		Use:   "get",
+		Short: "Print an SSO password stored in the OS keychain.",
+		RunE:  getCredentials,
+	}
+	get.Flags().String("email", "", "Confluent Cloud SSO email.")
+	cobra.CheckErr(get.MarkFlagRequired("email"))
+
+	del := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete an SSO password stored in the OS keychain.",
+		RunE:  deleteCredentials,
+	}
+	del.Flags().String("email", "", "Confluent Cloud SSO email.")
+	cobra.CheckErr(del.MarkFlagRequired("email"))
+
+	cmd.AddCommand(set, get, del)
+
+	return cmd
+}
+
+func setCredentials(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	email, err := cmd.Flags().GetString("email")
+	cobra.CheckErr(err)
+
+	// Prompt without echoing when run interactively, and otherwise read the password from stdin.
+	var password string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return err
+		}
+		password = string(b)
+	} else {
+		password, err = readPassword(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+	}
+
+	if password == "" {
+		return fmt.Errorf("the password must not be empty")
+	}
+
+	return keyring.Set(keychainService, email, password)
+}
+
+func getCredentials(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	email, err := cmd.Flags().GetString("email")
+	cobra.CheckErr(err)
+
+	password, err := keychainPassword(email)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(password)
+	return nil
+}
+
+func deleteCredentials(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	email, err := cmd.Flags().GetString("email")
+	cobra.CheckErr(err)
+
+	if err := keyring.Delete(keychainService, email); errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf(`no password is stored for "%s"`, email)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// keychainPassword looks up the password stored for an email in the OS keychain.
+func keychainPassword(email string) (string, error) {
+	password, err := keyring.Get(keychainService, email)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf(`no password is stored for "%s", provide one with --password or store one with "confluent login headless-sso credentials set"`, email)
+	}
+	return password, err
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+)
+
+// lockPollInterval is how often a lock held by another login is tried again.
+const lockPollInterval = 500 * time.Millisecond
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("locked")
+
+// lockConfig takes an advisory lock next to the confluent CLI's config, so that concurrent logins on the same machine,
+// such as from two pipelines on one build agent, take turns writing their sessions instead of overwriting each other's.
+// A lock held by another login is waited for up to the timeout, or not at all without wait. The lock is released when
+// the process exits, even if it crashes.
+func lockConfig(wait bool, timeout time.Duration) (func(), error) {
+	config, err := confluentConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	path := config + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the lock: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock: %w", err)
+	}
+
+	start := time.Now()
+	for logged := false; ; logged = true {
+		err := tryLock(file)
+		if err == nil {
+			slog.Debug("Locked the confluent CLI config", "path", path, "elapsed", time.Since(start))
+			return func() {
+				_ = unlock(file)
+				_ = file.Close()
+			}, nil
+		}
+
+		if !errors.Is(err, errLocked) {
+			_ = file.Close()
+			return nil, fmt.Errorf(`failed to lock "%s": %w`, path, err)
+		}
+		if !wait || time.Since(start) >= timeout {
+			_ = file.Close()
+			return nil, exitcode.With(exitLocked, fmt.Errorf(`another login holds the lock on "%s"`, path))
+		}
+		if !logged {
+			slog.Info("Waiting for another login to finish", "lock", path, "timeout", timeout)
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+}
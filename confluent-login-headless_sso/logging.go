@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/logging"
+)
+
+// logfAt adapts the logger to the printf-style logging of chromedp.
+func logfAt(level slog.Level) func(string, ...any) {
+	return func(format string, args ...any) {
+		slog.Log(context.Background(), level, fmt.Sprintf(format, args...))
+	}
+}
+
+// transcript relays the output of the confluent CLI line by line, with secrets redacted, either as text or, with
+// --log-format json, as log records so that the output can be parsed.
+type transcript struct {
+	stdout io.Writer
+	stderr io.Writer
+	json   bool
+}
+
+func (t transcript) relay(stream, line string) {
+	if line == "" {
+		return
+	}
+	line = logging.Redact(line)
+
+	if t.json {
+		slog.Info("confluent CLI output", "stream", stream, "line", strings.TrimRight(line, "\r\n"))
+		return
+	}
+
+	if stream == "stderr" {
+		fmt.Fprint(t.stderr, line)
+	} else {
+		fmt.Fprint(t.stdout, line)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/logging"
+)
+
+// defaultMagicLinkPattern matches links which look like they sign in, rather than every link in the email.
+const defaultMagicLinkPattern = `https://[^\s"'<>]*(?:magic|login|signin|sign-in|verify|auth|token)[^\s"'<>]*`
+
+// mailboxPollInterval is how often the mailbox is checked for the magic link.
+const mailboxPollInterval = 5 * time.Second
+
+// A mailbox finds the newest link which matches the pattern in the messages received since a time.
+type mailbox interface {
+	findLink(ctx context.Context, since time.Time, pattern *regexp.Regexp) (string, error)
+}
+
+// magicLink configures how the one-time link which passwordless IdPs email is retrieved.
+type magicLink struct {
+	mailbox mailbox
+	pattern *regexp.Regexp
+	timeout time.Duration
+}
+
+// wait polls the mailbox until the magic link arrives. Messages received shortly before the link was requested are
+// included, in case the mail server's clock is behind.
+func (m *magicLink) wait(ctx context.Context, requested time.Time) (string, error) {
+	slog.Info("Waiting for the magic link to be emailed", "timeout", m.timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	since := requested.Add(-time.Minute)
+	for {
+		link, err := m.mailbox.findLink(ctx, since, m.pattern)
+		if err != nil {
+			slog.Warn("Failed to check the mailbox", "error", err)
+		} else if link != "" {
+			logging.AddSecrets(link)
+			return link, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", mfaRequired(fmt.Errorf("no magic link was emailed within %s", m.timeout))
+		case <-time.After(mailboxPollInterval):
+		}
+	}
+}
+
+// findLinkIn returns the first link in the text, which may be HTML with escaped ampersands.
+func findLinkIn(text string, pattern *regexp.Regexp) string {
+	return html.UnescapeString(pattern.FindString(text))
+}
+
+// messageText decodes the text and HTML parts of an email, so that links aren't broken by quoted-printable line wraps.
+func messageText(raw []byte) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	if err := appendPart(&text, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body); err != nil {
+		return "", err
+	}
+	return text.String(), nil
+}
+
+func appendPart(text *strings.Builder, contentType, encoding string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := r.NextRawPart()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := appendPart(text, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !strings.HasPrefix(mediaType, "text/") {
+		return nil
+	}
+
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	text.Write(b)
+	text.WriteString("\n")
+	return nil
+}
+
+// imapPasswordEnv holds the password of the IMAP mailbox, which is usually an app password.
+const imapPasswordEnv = "HEADLESS_SSO_IMAP_PASSWORD"
+
+// magicLinkOptions configure the mailbox which magic links are read from. The mailbox's username defaults to the SSO
+// email, so that the same options apply to every profile.
+type magicLinkOptions struct {
+	mailbox    string
+	imapServer string
+	username   string
+	pattern    *regexp.Regexp
+	timeout    time.Duration
+}
+
+func (o magicLinkOptions) validate() error {
+	switch o.mailbox {
+	case "", "graph":
+		return nil
+	case "imap":
+		if o.imapServer == "" {
+			return fmt.Errorf("--imap-server is required with --magic-link imap")
+		}
+		if os.Getenv(imapPasswordEnv) == "" {
+			return fmt.Errorf("$%s must be set with --magic-link imap", imapPasswordEnv)
+		}
+		return nil
+	default:
+		return fmt.Errorf(`unsupported mailbox "%s", supported mailboxes: imap, graph`, o.mailbox)
+	}
+}
+
+// forEmail returns how to retrieve magic links sent to the email, or nil if magic links aren't enabled.
+func (o magicLinkOptions) forEmail(email string) *magicLink {
+	username := o.username
+	if username == "" {
+		username = email
+	}
+
+	m := &magicLink{pattern: o.pattern, timeout: o.timeout}
+	switch o.mailbox {
+	case "imap":
+		m.mailbox = imapMailbox{server: o.imapServer, username: username, password: os.Getenv(imapPasswordEnv)}
+	case "graph":
+		m.mailbox = graphMailbox{user: username}
+	default:
+		return nil
+	}
+	return m
+}
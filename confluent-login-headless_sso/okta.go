@@ -2,32 +2,115 @@ package main
 
 import (
 	"context"
-	"log"
-	"time"
+	"fmt"
+	"strings"
 
 	"github.com/chromedp/chromedp"
 )
 
-func okta(url, username, password string) string {
-	ctx, cancel := chromedp.NewContext(context.Background(), chromedp.WithLogf(log.Printf))
-	defer cancel()
+// oktaSelectors locate the elements of an Okta sign-in widget.
+type oktaSelectors struct {
+	username string
+	password string
+	submit   string
+}
+
+var (
+	// The classic sign-in widget asks for the username and password in a single form.
+	oktaClassic = oktaSelectors{
+		username: `//input[@id="okta-signin-username"]`,
+		password: `//input[@id="okta-signin-password"]`,
+		submit:   `//input[@id="okta-signin-submit"]`,
+	}
+
+	// Okta Identity Engine asks for the username first, and then for the password on a second page.
+	oktaIdentityEngine = oktaSelectors{
+		username: `//input[@name="identifier"]`,
+		password: `//input[@name="credentials.passcode"]`,
+		submit:   `//input[@type="submit"]`,
+	}
+)
+
+const (
+	oktaError = `//div[contains(@class,"infobox-error")]`
 
-	ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
+	// Passcodes from an authenticator app are entered into the "answer" field of the classic widget, or into the
+	// challenge form of the matching authenticator in Okta Identity Engine.
+	oktaPasscode            = `//form[contains(@class,"mfa-verify")]//input[@name="answer"] | //form[contains(@class,"challenge-authenticator--google_otp") or contains(@class,"challenge-authenticator--okta_verify")]//input[@name="credentials.passcode" or @name="credentials.totp"]`
+	oktaVerify              = `//input[@type="submit"]`
+	oktaSelectAuthenticator = `//*[@data-se="google_otp" or @data-se="okta_verify-totp"]//a`
+	oktaOtherFactor         = `//a[@data-se="switchAuthenticator"]`
+	oktaMFA                 = `//form[contains(@class,"mfa-verify") or (contains(@class,"authenticator") and not(contains(@class,"okta_password")))]`
+)
 
-	var token string
+func okta(ctx context.Context, url string, c credentials) (string, error) {
+	if err := navigate(ctx, c, url); err != nil {
+		return "", err
+	}
 
-	actions := []chromedp.Action{
-		chromedp.Navigate(url),
-		chromedp.WaitVisible(`//input[@name="username"]`),
-		chromedp.SendKeys(`//input[@id="okta-signin-username"]`, username),
-		chromedp.SendKeys(`//input[@id="okta-signin-password"]`, password),
-		chromedp.Click(`//input[@id="okta-signin-submit"]`),
-		chromedp.WaitVisible(`//div[@id="cc-root"]`),
-		chromedp.Text(`//div[@id="token"]`, &token),
+	i, err := waitAny(ctx, c.selectorTimeout, oktaClassic.username, oktaIdentityEngine.username)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the Okta sign-in form: %w", err)
 	}
 
-	_ = chromedp.Run(ctx, actions...)
+	if i == 0 {
+		err = chromedp.Run(ctx,
+			chromedp.SendKeys(oktaClassic.username, c.username),
+			chromedp.SendKeys(oktaClassic.password, c.password),
+			chromedp.Click(oktaClassic.submit),
+		)
+	} else {
+		err = chromedp.Run(ctx,
+			chromedp.SendKeys(oktaIdentityEngine.username, c.username),
+			chromedp.Click(oktaIdentityEngine.submit),
+			chromedp.WaitVisible(oktaIdentityEngine.password),
+			chromedp.SendKeys(oktaIdentityEngine.password, c.password),
+			chromedp.Click(oktaIdentityEngine.submit),
+		)
+	}
+	if err != nil {
+		return "", err
+	}
 
-	return token
+	// After the credentials are submitted, Okta either redirects back to Confluent Cloud, challenges for a second
+	// factor, or shows an error on the same page.
+	sentPasscode := false
+
+	for {
+		i, err = waitAny(ctx, c.selectorTimeout, confluentRoot, oktaError, webauthnRequested, oktaPasscode, oktaSelectAuthenticator, duoPrompt, oktaMFA)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect from Okta to Confluent Cloud: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return token(ctx)
+		case 1:
+			var message string
+			if err := chromedp.Run(ctx, chromedp.Text(oktaError, &message)); err != nil {
+				return "", err
+			}
+			return "", badCredentials(fmt.Errorf("failed to sign in to Okta: %s", strings.TrimSpace(message)))
+		case 2:
+			err = webauthn(ctx, c, "Okta", oktaOtherFactor)
+		case 3:
+			if sentPasscode {
+				continue
+			}
+			sentPasscode = true
+			err = sendPasscode(ctx, c, "Okta", oktaPasscode, oktaVerify)
+		case 4:
+			if !c.hasPasscode() {
+				return "", errNoPasscode("Okta")
+			}
+			err = chromedp.Run(ctx, chromedp.Click(oktaSelectAuthenticator))
+		case 5:
+			err = duoPush(ctx, c)
+		case 6:
+			return "", mfaRequired(fmt.Errorf("Okta requires a second factor which is not supported, only passcodes from an authenticator app and Duo pushes are"))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	oneloginUsername  = `//input[@name="username"]`
+	oneloginPassword  = `//input[@name="password"]`
+	oneloginSubmit    = `//button[@type="submit"]`
+	oneloginOTP       = `//input[@name="otp" or @name="otp_token" or @id="security-code"]`
+	oneloginEnterCode = `//*[self::a or self::button][contains(normalize-space(.), "Enter code manually")]`
+	oneloginError     = `//*[@role="alert"][normalize-space()!=""]`
+)
+
+// onelogin signs in to OneLogin. The username and password are asked for on separate pages, followed by an OTP page
+// when the account is enrolled in MFA.
+func onelogin(ctx context.Context, url string, c credentials) (string, error) {
+	if err := navigate(ctx, c, url); err != nil {
+		return "", err
+	}
+
+	sentUsername, sentPassword, sentOTP := false, false, false
+
+	for {
+		i, err := waitAny(ctx, c.selectorTimeout, confluentRoot, oneloginError, webauthnRequested, oneloginOTP, oneloginEnterCode, duoPrompt, oneloginPassword, oneloginUsername)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect from OneLogin to Confluent Cloud: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return token(ctx)
+		case 1:
+			var message string
+			if err := chromedp.Run(ctx, chromedp.Text(oneloginError, &message)); err != nil {
+				return "", err
+			}
+			return "", badCredentials(fmt.Errorf("failed to sign in to OneLogin: %s", strings.TrimSpace(message)))
+		case 2:
+			err = webauthn(ctx, c, "OneLogin", "")
+		case 3:
+			if sentOTP {
+				continue
+			}
+			sentOTP = true
+			err = sendPasscode(ctx, c, "OneLogin", oneloginOTP, oneloginSubmit)
+		case 4:
+			// OneLogin Protect sends a push notification by default, but a code can be entered instead.
+			if !c.hasPasscode() {
+				return "", errNoPasscode("OneLogin")
+			}
+			err = chromedp.Run(ctx, chromedp.Click(oneloginEnterCode))
+		case 5:
+			err = duoPush(ctx, c)
+		case 6:
+			if sentPassword {
+				continue
+			}
+			sentPassword = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(oneloginPassword, c.password), chromedp.Click(oneloginSubmit))
+		case 7:
+			if sentUsername {
+				continue
+			}
+			sentUsername = true
+			err = chromedp.Run(ctx, chromedp.SendKeys(oneloginUsername, c.username), chromedp.Click(oneloginSubmit))
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	pingUsername         = `//input[@id="username"]`
+	pingPassword         = `//input[@id="password"]`
+	pingSubmit           = `//*[@id="signOnButton"] | //button[@type="submit"]`
+	pingAdapterSelection = `//form[@id="adapterSelectionForm" or @name="adapterSelectionForm"]`
+	pingPasswordAdapter  = pingAdapterSelection + `//*[(self::button or self::a) and contains(translate(normalize-space(.), "PASWORD", "pasword"), "password")]`
+	pingResume           = `//form[.//input[@name="REF" or @name="SAMLResponse" or @name="RelayState"]]//input[@type="submit"]`
+	pingPasscode         = `//input[@id="otp" or @name="otp"]`
+	pingMFA              = `//*[@id="pingid-iframe"]`
+	pingError            = `//*[contains(@class,"ping-error") or @id="error-message"][normalize-space()!=""]`
+)
+
+// ping signs in to PingFederate or PingOne. Authentication policies may first ask which adapter to sign in with, and
+// agentless integrations redirect through intermediate forms, so each page is handled as it appears.
+func ping(ctx context.Context, url string, c credentials) (string, error) {
+	if err := navigate(ctx, c, url); err != nil {
+		return "", err
+	}
+
+	sentCredentials, sentPasscode := false, false
+
+	for {
+		i, err := waitAny(ctx, c.selectorTimeout, confluentRoot, pingError, webauthnRequested, pingPasscode, duoPrompt, pingMFA, pingPasswordAdapter, pingResume, pingUsername)
+		if err != nil {
+			return "", fmt.Errorf("failed to redirect from Ping to Confluent Cloud: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return token(ctx)
+		case 1:
+			var message string
+			if err := chromedp.Run(ctx, chromedp.Text(pingError, &message)); err != nil {
+				return "", err
+			}
+			return "", badCredentials(fmt.Errorf("failed to sign in to Ping: %s", strings.TrimSpace(message)))
+		case 2:
+			err = webauthn(ctx, c, "Ping", "")
+		case 3:
+			if sentPasscode {
+				continue
+			}
+			sentPasscode = true
+			err = sendPasscode(ctx, c, "Ping", pingPasscode, pingSubmit)
+		case 4:
+			err = duoPush(ctx, c)
+		case 5:
+			return "", mfaRequired(fmt.Errorf("Ping requires a second factor which is not supported, only passcodes from an authenticator app and Duo pushes are"))
+		case 6:
+			err = chromedp.Run(ctx, chromedp.Click(pingPasswordAdapter))
+		case 7:
+			// Agentless integrations post back to PingFederate with an auto-submitting form, which is submitted
+			// manually in case the page's script doesn't run.
+			err = chromedp.Run(ctx, chromedp.Click(pingResume))
+		case 8:
+			if sentCredentials {
+				continue
+			}
+			sentCredentials = true
+			err = chromedp.Run(ctx,
+				chromedp.SendKeys(pingUsername, c.username),
+				chromedp.SendKeys(pingPassword, c.password),
+				chromedp.Click(pingSubmit),
+			)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profile holds the credentials of one login, for logging in to several organizations or with several IdPs. The
+// profiles file has the same keys as the flags, e.g.:
+//
+//	profiles:
+//	  - name: production
+//	    provider: okta
+//	    email: example@confluent.io
+//	    vault_path: secret/data/headless-sso/production
+//	  - name: staging
+//	    provider: entra
+//	    email: example+staging@confluent.io
+type profile struct {
+	Name           string `yaml:"name"`
+	Provider       string `yaml:"provider"`
+	SelectorsFile  string `yaml:"selectors_file"`
+	StepsFile      string `yaml:"steps_file"`
+	Email          string `yaml:"email"`
+	Password       string `yaml:"password"`
+	TOTPSecret     string `yaml:"totp_secret"`
+	VaultPath      string `yaml:"vault_path"`
+	AWSSecretARN   string `yaml:"aws_secret_arn"`
+	URL            string `yaml:"url"`
+	OrganizationID string `yaml:"organization_id"`
+}
+
+func defaultProfilesFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".confluent", "headless-sso.yaml"), nil
+}
+
+func readProfiles(path string) ([]profile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var file struct {
+		Profiles []profile `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf(`failed to parse profiles from "%s": %w`, path, err)
+	}
+
+	names := map[string]bool{}
+	for _, p := range file.Profiles {
+		if p.Name == "" {
+			return nil, fmt.Errorf(`every profile in "%s" must have a name`, path)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf(`profile "%s" is defined more than once in "%s"`, p.Name, path)
+		}
+		names[p.Name] = true
+	}
+
+	return file.Profiles, nil
+}
+
+// selectProfiles returns every profile, or the named profiles in the order they were named.
+func selectProfiles(profiles []profile, names []string, all bool) ([]profile, error) {
+	if all {
+		if len(profiles) == 0 {
+			return nil, fmt.Errorf("no profiles are defined")
+		}
+		return profiles, nil
+	}
+
+	var selected []profile
+	for _, name := range names {
+		i := -1
+		for j, p := range profiles {
+			if p.Name == name {
+				i = j
+			}
+		}
+		if i == -1 {
+			available := make([]string, len(profiles))
+			for j, p := range profiles {
+				available[j] = p.Name
+			}
+			return nil, fmt.Errorf(`unknown profile "%s", available profiles: %s`, name, strings.Join(available, ", "))
+		}
+		selected = append(selected, profiles[i])
+	}
+
+	return selected, nil
+}
+
+// login returns the function which signs in to the profile's provider.
+func (p profile) login() (providerLogin, error) {
+	switch p.Provider {
+	case "":
+		return nil, fmt.Errorf(`required flag "provider" not set`)
+	case "custom":
+		selectors, err := readSelectors(p.SelectorsFile)
+		if err != nil {
+			return nil, err
+		}
+		return selectors.signIn, nil
+	}
+
+	provider, ok := providers[p.Provider]
+	if !ok {
+		return nil, fmt.Errorf(`unsupported provider "%s"`, p.Provider)
+	}
+	return provider, nil
+}
+
+// steps returns the profile's intermediate pages, if any.
+func (p profile) steps() ([]step, error) {
+	if p.StepsFile == "" {
+		return nil, nil
+	}
+	return readSteps(p.StepsFile)
+}
+
+// override replaces the profile's values with those which are set in another profile.
+func (p profile) override(q profile) profile {
+	for _, f := range []struct{ dst, src *string }{
+		{&p.Provider, &q.Provider},
+		{&p.SelectorsFile, &q.SelectorsFile},
+		{&p.StepsFile, &q.StepsFile},
+		{&p.Email, &q.Email},
+		{&p.Password, &q.Password},
+		{&p.TOTPSecret, &q.TOTPSecret},
+		{&p.VaultPath, &q.VaultPath},
+		{&p.AWSSecretARN, &q.AWSSecretARN},
+		{&p.URL, &q.URL},
+		{&p.OrganizationID, &q.OrganizationID},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	return p
+}
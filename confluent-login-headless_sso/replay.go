@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// fixture is a recording of every response the browser received during a login, which can be replayed to test the
+// automation, such as a custom selectors file, without network access.
+type fixture struct {
+	URL       string              `json:"url"`
+	Responses []*recordedResponse `json:"responses"`
+}
+
+type recordedResponse struct {
+	Method  string               `json:"method"`
+	URL     string               `json:"url"`
+	Status  int64                `json:"status"`
+	Headers []*fetch.HeaderEntry `json:"headers"`
+	Body    []byte               `json:"body,omitempty"`
+}
+
+func readFixture(path string) (*fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	f := new(fixture)
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf(`failed to parse fixture "%s": %w`, path, err)
+	}
+	if f.URL == "" {
+		return nil, fmt.Errorf(`fixture "%s" has no login URL`, path)
+	}
+
+	return f, nil
+}
+
+func (f *fixture) save(path string) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// record adds every response the browser receives to the fixture. The responses are intercepted, so request bodies,
+// such as submitted passwords, aren't recorded, but the pages and cookies which the IdP responds with are.
+func (f *fixture) record(ctx context.Context) error {
+	var mu sync.Mutex
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		paused, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+
+		go func() {
+			res := &recordedResponse{
+				Method: paused.Request.Method,
+				URL:    paused.Request.URL,
+				Status: paused.ResponseStatusCode,
+			}
+
+			// The body is decoded, so the headers which describe its encoding no longer apply.
+			for _, h := range paused.ResponseHeaders {
+				if name := strings.ToLower(h.Name); name != "content-encoding" && name != "content-length" {
+					res.Headers = append(res.Headers, h)
+				}
+			}
+
+			// Redirects have no body.
+			if body, err := fetch.GetResponseBody(paused.RequestID).Do(executor(ctx)); err == nil {
+				res.Body = body
+			}
+
+			if paused.ResponseErrorReason == "" {
+				mu.Lock()
+				f.Responses = append(f.Responses, res)
+				mu.Unlock()
+			}
+
+			if err := fetch.ContinueRequest(paused.RequestID).Do(executor(ctx)); err != nil && ctx.Err() == nil {
+				slog.Warn("Failed to continue request", "url", paused.Request.URL, "error", err)
+			}
+		}()
+	})
+
+	patterns := []*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageResponse}}
+	return chromedp.Run(ctx, fetch.Enable().WithPatterns(patterns))
+}
+
+// replay answers the browser's requests with the fixture's responses, in the order they were recorded, and fails
+// requests which weren't recorded as if the network were disconnected.
+func (f *fixture) replay(ctx context.Context) error {
+	var (
+		mu      sync.Mutex
+		replies = map[string][]*recordedResponse{}
+	)
+	for _, res := range f.Responses {
+		key := res.Method + " " + res.URL
+		replies[key] = append(replies[key], res)
+	}
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		paused, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+
+		// The last response to a request is repeated if it's made more often than it was recorded.
+		key := paused.Request.Method + " " + paused.Request.URL
+		mu.Lock()
+		var res *recordedResponse
+		if queue := replies[key]; len(queue) > 0 {
+			res = queue[0]
+			if len(queue) > 1 {
+				replies[key] = queue[1:]
+			}
+		}
+		mu.Unlock()
+
+		go func() {
+			var err error
+			if res == nil {
+				slog.Debug("No recorded response", "request", key)
+				err = fetch.FailRequest(paused.RequestID, network.ErrorReasonInternetDisconnected).Do(executor(ctx))
+			} else {
+				err = fetch.FulfillRequest(paused.RequestID, res.Status).
+					WithResponseHeaders(res.Headers).
+					WithBody(base64.StdEncoding.EncodeToString(res.Body)).
+					Do(executor(ctx))
+			}
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("Failed to replay response", "request", key, "error", err)
+			}
+		}()
+	})
+
+	return chromedp.Run(ctx, fetch.Enable())
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// confluentConfig is the part of the confluent CLI's config which holds the session of the current context.
+type confluentConfig struct {
+	CurrentContext string `json:"current_context"`
+	ContextStates  map[string]struct {
+		AuthToken string `json:"auth_token"`
+	} `json:"context_states"`
+}
+
+// confluentConfigPath returns the path of the confluent CLI's config, which "confluent login" writes the session to.
+func confluentConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".confluent", "config.json"), nil
+}
+
+// sessionExpiry returns when the auth token of the confluent CLI's current context expires.
+func sessionExpiry() (time.Time, error) {
+	path, err := confluentConfigPath()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read the confluent CLI config: %w", err)
+	}
+
+	var config confluentConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse the confluent CLI config: %w", err)
+	}
+
+	if config.CurrentContext == "" {
+		return time.Time{}, fmt.Errorf("not logged in")
+	}
+
+	state, ok := config.ContextStates[config.CurrentContext]
+	if !ok || state.AuthToken == "" {
+		return time.Time{}, fmt.Errorf(`context "%s" has no session`, config.CurrentContext)
+	}
+
+	return tokenExpiry(state.AuthToken)
+}
+
+// tokenExpiry reads the expiry of a JWT, without verifying its signature.
+func tokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("the auth token is not a JWT")
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode the auth token: %w", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode the auth token: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("the auth token has no expiry")
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
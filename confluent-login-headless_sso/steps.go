@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"gopkg.in/yaml.v3"
+)
+
+// maxStepRepeats bounds how often a step is taken, in case its page doesn't go away.
+const maxStepRepeats = 3
+
+// A step is an intermediate page which may appear anywhere during the login, such as an organization picker or terms
+// to accept, along with the elements to click to move past it. Steps are read from a YAML list, as in the README.
+type step struct {
+	Name  string   `yaml:"name"`
+	Match string   `yaml:"match"`
+	Click []string `yaml:"click"`
+}
+
+// samlHandoff is the form which posts the SAML response and RelayState between the IdP and Confluent Cloud. It's
+// usually submitted by a script, and only shows its button if it wasn't.
+var samlHandoff = step{
+	Name:  "SAML handoff",
+	Match: `//form[.//input[@name="SAMLResponse" or @name="SAMLRequest" or @name="RelayState"]]//*[(self::input or self::button) and @type="submit"]`,
+	Click: []string{`//form[.//input[@name="SAMLResponse" or @name="SAMLRequest" or @name="RelayState"]]//*[(self::input or self::button) and @type="submit"]`},
+}
+
+func readSteps(path string) ([]step, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read steps: %w", err)
+	}
+
+	var steps []step
+	if err := yaml.Unmarshal(b, &steps); err != nil {
+		return nil, fmt.Errorf(`failed to parse steps from "%s": %w`, path, err)
+	}
+
+	for i, s := range steps {
+		if s.Match == "" || len(s.Click) == 0 {
+			return nil, fmt.Errorf(`step %d in "%s" must set "match" and "click"`, i+1, path)
+		}
+		if s.Name == "" {
+			steps[i].Name = fmt.Sprintf("step %d", i+1)
+		}
+	}
+
+	return steps, nil
+}
+
+// stepper takes steps as their pages appear. It's shared by every wait during a login, through the context.
+type stepper struct {
+	mu    sync.Mutex
+	steps []step
+	taken map[int]int
+}
+
+type stepperKey struct{}
+
+func withSteps(ctx context.Context, steps []step) context.Context {
+	return context.WithValue(ctx, stepperKey{}, &stepper{steps: append([]step{samlHandoff}, steps...), taken: map[int]int{}})
+}
+
+func stepperFrom(ctx context.Context) *stepper {
+	s, _ := ctx.Value(stepperKey{}).(*stepper)
+	return s
+}
+
+// pending returns the indexes and selectors of the steps which may still be taken.
+func (s *stepper) pending() ([]int, []string) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var indexes []int
+	var matches []string
+	for i, step := range s.steps {
+		if s.taken[i] < maxStepRepeats {
+			indexes = append(indexes, i)
+			matches = append(matches, step.Match)
+		}
+	}
+	return indexes, matches
+}
+
+// take clicks through a step's page, and then waits for the page to go away.
+func (s *stepper) take(ctx context.Context, i int, timeout time.Duration) error {
+	s.mu.Lock()
+	step := s.steps[i]
+	s.taken[i]++
+	s.mu.Unlock()
+
+	slog.Info("Passing an intermediate page", "step", step.Name)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, sel := range step.Click {
+		if err := chromedp.Run(ctx, chromedp.Click(sel)); err != nil {
+			return fmt.Errorf(`failed to pass the "%s" page: %w`, step.Name, err)
+		}
+	}
+
+	for isVisible(ctx, step.Match) {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// readVault reads credentials from a KV secret, using the same environment variables as the Vault CLI. The path is the
+// secret's API path, which includes "data/" for version 2 of the KV secrets engine, e.g. "secret/data/headless-sso".
+func readVault(path string) (storedCredentials, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return storedCredentials{}, fmt.Errorf("VAULT_ADDR must be set to read credentials from Vault")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return storedCredentials{}, fmt.Errorf("VAULT_TOKEN must be set to read credentials from Vault")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return storedCredentials{}, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return storedCredentials{}, fmt.Errorf("failed to read credentials from Vault: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil && res.StatusCode == http.StatusOK {
+		return storedCredentials{}, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		if len(body.Errors) > 0 {
+			return storedCredentials{}, fmt.Errorf(`failed to read "%s" from Vault: %s`, path, strings.Join(body.Errors, ", "))
+		}
+		return storedCredentials{}, fmt.Errorf(`failed to read "%s" from Vault: %s`, path, res.Status)
+	}
+
+	// Version 2 of the KV secrets engine nests the secret's data alongside its metadata.
+	var kv2 struct {
+		Data     *storedCredentials `json:"data"`
+		Metadata json.RawMessage    `json:"metadata"`
+	}
+	if err := json.Unmarshal(body.Data, &kv2); err == nil && kv2.Data != nil && kv2.Metadata != nil {
+		return *kv2.Data, nil
+	}
+
+	var secret storedCredentials
+	if err := json.Unmarshal(body.Data, &secret); err != nil {
+		return storedCredentials{}, fmt.Errorf("failed to decode Vault secret: %w", err)
+	}
+
+	return secret, nil
+}
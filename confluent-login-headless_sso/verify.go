@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "verify",
+		Short:   "Check whether the current session is still valid.",
+		Long:    "Check whether the auth token of the confluent CLI's current context has expired, and print how long until it does. Exits non-zero if it has, so that pipelines can skip logging in while the session is still valid. The token's signature isn't verified, so a session which was revoked is still reported as valid.",
+		RunE:    verify,
+		Example: "confluent login headless-sso verify --min-remaining 10m || confluent login headless-sso --provider okta --email example@confluent.io",
+	}
+	cmd.Flags().Duration("min-remaining", 0, "Fail if the session expires within this long, such as the length of the job which needs it.")
+	output.AddFlag(cmd, "Format of the session's validity")
+
+	return cmd
+}
+
+func verify(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	minRemaining, err := cmd.Flags().GetDuration("min-remaining")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	expiry, err := sessionExpiry()
+	if err != nil {
+		return exitcode.With(exitcode.Auth, err)
+	}
+
+	remaining := time.Until(expiry).Truncate(time.Second)
+	if remaining <= 0 {
+		return exitcode.With(exitcode.Auth, fmt.Errorf("the session expired at %s", expiry.Format(time.RFC3339)))
+	}
+	if remaining < minRemaining {
+		return exitcode.With(exitcode.Auth, fmt.Errorf("the session expires in %s, at %s", remaining, expiry.Format(time.RFC3339)))
+	}
+
+	out := cmd.OutOrStdout()
+	v := validity{Remaining: remaining.String(), RemainingSeconds: int64(remaining.Seconds()), Expiry: expiry}
+	return output.Print(out, format, v, func() {
+		fmt.Fprintf(out, "The session is valid for %s, until %s.\n", remaining, expiry.Format(time.RFC3339))
+	})
+}
+
+// validity is what --output json or yaml prints for a session which is valid.
+type validity struct {
+	Remaining        string    `json:"remaining"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+	Expiry           time.Time `json:"expiry"`
+}
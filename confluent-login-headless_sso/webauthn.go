@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+)
+
+var errWebAuthn = errors.New("a security key or platform authenticator (WebAuthn) is required, which cannot be used from a headless browser")
+
+// rejectWebAuthn makes WebAuthn requests fail immediately instead of waiting for a key to be touched, and marks the
+// page so that the request can be detected.
+const rejectWebAuthn = `(() => {
+	if (!navigator.credentials) return;
+	const reject = () => {
+		document.documentElement.setAttribute("data-headless-sso-webauthn", "");
+		return Promise.reject(new DOMException("WebAuthn is not supported by headless-sso", "NotAllowedError"));
+	};
+	navigator.credentials.get = reject;
+	navigator.credentials.create = reject;
+})();`
+
+const webauthnRequested = `//html[@data-headless-sso-webauthn]`
+
+// detectWebAuthn installs the WebAuthn rejection into every page the browser navigates to.
+func detectWebAuthn(ctx context.Context) error {
+	return chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, err := page.AddScriptToEvaluateOnNewDocument(rejectWebAuthn).Do(ctx)
+		return err
+	}))
+}
+
+// webauthn handles a WebAuthn challenge by switching to another factor offered by the provider, if allowed and
+// possible, or otherwise fails.
+func webauthn(ctx context.Context, c credentials, provider, otherFactor string) error {
+	if !c.webauthnFallback || otherFactor == "" {
+		return rejected(exitcode.With(exitcode.Auth, fmt.Errorf("%s: %w", provider, errWebAuthn)))
+	}
+
+	return chromedp.Run(ctx,
+		chromedp.Evaluate(`document.documentElement.removeAttribute("data-headless-sso-webauthn")`, nil),
+		chromedp.Click(otherFactor),
+	)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+// keychainService identifies the plugin's entries in the macOS Keychain, Windows Credential Manager, or libsecret.
+const keychainService = "confluent-login-keychain"
+
+func newSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "set",
+		Short:   "Store a Confluent Cloud password in the OS keychain.",
+		Long:    "Store the password of a Confluent Cloud account in the macOS Keychain, Windows Credential Manager, or libsecret. The password is prompted for without echoing it, or read from the first line of stdin.",
+		Args:    cobra.NoArgs,
+		RunE:    setPassword,
+		Example: "confluent login keychain set --email example@confluent.io",
+	}
+
+	cmd.Flags().String("email", "", "Confluent Cloud email. Defaults to $CONFLUENT_CLOUD_EMAIL.")
+
+	return cmd
+}
+
+func newDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete a Confluent Cloud password from the OS keychain.",
+		Args:    cobra.NoArgs,
+		RunE:    deletePassword,
+		Example: "confluent login keychain delete --email example@confluent.io",
+	}
+
+	cmd.Flags().String("email", "", "Confluent Cloud email. Defaults to $CONFLUENT_CLOUD_EMAIL.")
+
+	return cmd
+}
+
+func setPassword(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	email, err := emailFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Prompt without echoing when run interactively, and otherwise read the password from stdin.
+	var password string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return err
+		}
+		password = string(b)
+	} else {
+		password, err = readPassword(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+	}
+
+	if password == "" {
+		return fmt.Errorf("the password must not be empty")
+	}
+
+	if err := keyring.Set(keychainService, email, password); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Stored the password of \"%s\" in the OS keychain.\n", email)
+	return nil
+}
+
+func deletePassword(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	email, err := emailFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := keyring.Delete(keychainService, email); errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf(`no password is stored for "%s"`, email)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted the password of \"%s\" from the OS keychain.\n", email)
+	return nil
+}
+
+// keychainPassword looks up the password stored for an email in the OS keychain.
+func keychainPassword(email string) (string, error) {
+	password, err := keyring.Get(keychainService, email)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf(`no password is stored for "%s", store one with "confluent login keychain set --email %s"`, email, email)
+	}
+	return password, err
+}
+
+// emailFlag reads --email, which defaults to the same environment variable as "confluent login".
+func emailFlag(cmd *cobra.Command) (string, error) {
+	email, err := cmd.Flags().GetString("email")
+	cobra.CheckErr(err)
+
+	if email == "" {
+		email = os.Getenv("CONFLUENT_CLOUD_EMAIL")
+	}
+	if email == "" {
+		return "", exitcode.Invalid("--email or $CONFLUENT_CLOUD_EMAIL is required")
+	}
+	return email, nil
+}
+
+// readPassword reads a password from the first line of r, like "docker login --password-stdin".
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "keychain",
+		Short: "Log in to Confluent Cloud with a password stored in the OS keychain.",
+		Long:  "Log in to Confluent Cloud non-interactively with the email and password of an account which doesn't use SSO, reading the password from the macOS Keychain, Windows Credential Manager, or libsecret, so that scripts never prompt for it or keep it in a plaintext file. Passwords are stored with \"confluent login keychain set\".",
+		Args:  cobra.NoArgs,
+		RunE:  login,
+		Example: `confluent login keychain set --email example@confluent.io
+confluent login keychain --email example@confluent.io --if-needed`,
+	}
+
+	cmd.Flags().String("email", "", "Confluent Cloud email. Defaults to $CONFLUENT_CLOUD_EMAIL.")
+	cmd.Flags().String("organization-id", "", "ID of the Confluent Cloud organization to log in to, for accounts which belong to several.")
+	cmd.Flags().String("url", "", "Confluent Cloud URL.")
+	cmd.Flags().Bool("if-needed", false, "Only log in if the confluent CLI isn't logged in already.")
+	logging.AddFlags(&cmd)
+
+	cmd.AddCommand(newSetCommand(), newDeleteCommand())
+
+	completion.Add(&cmd, "confluent-login-keychain")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-login-keychain"))
+
+	exitcode.Execute(&cmd)
+}
+
+func login(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	email, err := emailFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	organizationID, err := cmd.Flags().GetString("organization-id")
+	cobra.CheckErr(err)
+
+	url, err := cmd.Flags().GetString("url")
+	cobra.CheckErr(err)
+
+	ifNeeded, err := cmd.Flags().GetBool("if-needed")
+	cobra.CheckErr(err)
+
+	if ifNeeded && exec.Command("confluent", "organization", "describe").Run() == nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Already logged in.")
+		return nil
+	}
+
+	password, err := keychainPassword(email)
+	if err != nil {
+		return err
+	}
+
+	// The CLI reads the credentials from its environment variables instead of prompting for them. Its stdin is empty,
+	// so that it fails rather than waits if it prompts anyway.
+	args := []string{"login"}
+	if url != "" {
+		args = append(args, "--url", url)
+	}
+	command := exec.Command("confluent", args...)
+	command.Env = append(os.Environ(), "CONFLUENT_CLOUD_EMAIL="+email, "CONFLUENT_CLOUD_PASSWORD="+password)
+	if organizationID != "" {
+		command.Env = append(command.Env, "CONFLUENT_CLOUD_ORGANIZATION_ID="+organizationID)
+	}
+	command.Stdout = cmd.OutOrStdout()
+	command.Stderr = cmd.ErrOrStderr()
+
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("confluent login failed: %w", err)
+	}
+	return nil
+}
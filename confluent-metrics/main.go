@@ -0,0 +1,270 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "metrics",
+		Short: "Print the metrics of a Kafka cluster or topic.",
+		Long:  "Query the Metrics API for the throughput, storage, partition count, and requests of a Kafka cluster, or of one of its topics, over a time window, and print them as a table, CSV, or JSON, without writing the query by hand.",
+		Args:  cobra.NoArgs,
+		RunE:  query,
+		Example: `confluent metrics --cluster lkc-123456 --window 6h --granularity PT15M
+confluent metrics --topic orders --metric received_bytes,sent_bytes --window 168h --granularity P1D --output csv`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("topic", "", "Only query the metrics of this topic.")
+	cmd.Flags().StringSlice("metric", nil, fmt.Sprintf("Metrics to query: %s. Defaults to every metric.", strings.Join(metricNames(), ", ")))
+	cmd.Flags().Duration("window", 24*time.Hour, "Length of the time window to query.")
+	cmd.Flags().String("end", "", "RFC 3339 timestamp of the end of the time window. Defaults to now.")
+	cmd.Flags().String("granularity", "PT1H", fmt.Sprintf("Size of the time buckets: %s.", strings.Join(granularities, ", ")))
+	output.AddFlag(&cmd, "Format of the output", "csv")
+	cmd.Flags().String("metrics-api-key", "", "Cloud API key to query the Metrics API with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("metrics-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-metrics")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-metrics"))
+
+	exitcode.Execute(&cmd)
+}
+
+func query(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	topic, err := cmd.Flags().GetString("topic")
+	cobra.CheckErr(err)
+
+	names, err := cmd.Flags().GetStringSlice("metric")
+	cobra.CheckErr(err)
+
+	window, err := cmd.Flags().GetDuration("window")
+	cobra.CheckErr(err)
+
+	endFlag, err := cmd.Flags().GetString("end")
+	cobra.CheckErr(err)
+
+	granularity, err := cmd.Flags().GetString("granularity")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("metrics-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("metrics-api-secret")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(granularities, granularity) {
+		return fmt.Errorf(`unsupported granularity "%s", supported granularities: %s`, granularity, strings.Join(granularities, ", "))
+	}
+	if window <= 0 {
+		return exitcode.Invalid("--window must be positive")
+	}
+
+	end := time.Now().UTC().Truncate(time.Minute)
+	if endFlag != "" {
+		if end, err = time.Parse(time.RFC3339, endFlag); err != nil {
+			return fmt.Errorf(`invalid end "%s", expected an RFC 3339 timestamp such as 2024-05-01T00:00:00Z`, endFlag)
+		}
+	}
+
+	var queried []metric
+	for _, m := range metrics {
+		if len(names) == 0 && (topic == "" || m.topic) || slices.Contains(names, m.name) {
+			queried = append(queried, m)
+		}
+	}
+	for _, name := range names {
+		i := slices.IndexFunc(metrics, func(m metric) bool { return m.name == name })
+		if i == -1 {
+			return fmt.Errorf(`unsupported metric "%s", supported metrics: %s`, name, strings.Join(metricNames(), ", "))
+		}
+		if topic != "" && !metrics[i].topic {
+			return fmt.Errorf(`metric "%s" can't be queried for a topic`, name)
+		}
+	}
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if key == "" || secret == "" {
+		return exitcode.Invalid("a Cloud API key is required to query the Metrics API, pass --metrics-api-key and --metrics-api-secret or set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	if cluster == "" {
+		described, err := cli.DescribeKafkaCluster("", environment)
+		if err != nil {
+			return err
+		}
+		cluster = described.ID
+	}
+
+	q := querier{
+		cluster:     cluster,
+		topic:       topic,
+		interval:    fmt.Sprintf("%s/%s", end.Add(-window).Format(time.RFC3339), end.Format(time.RFC3339)),
+		granularity: granularity,
+		key:         key,
+		secret:      secret,
+		client:      retry.Client(30 * time.Second),
+	}
+
+	values := map[time.Time]map[string]float64{}
+	for _, m := range queried {
+		points, err := q.query(m)
+		if err != nil {
+			return err
+		}
+		for _, p := range points {
+			if values[p.Timestamp] == nil {
+				values[p.Timestamp] = map[string]float64{}
+			}
+			values[p.Timestamp][m.name] += p.Value
+		}
+	}
+
+	var rows []row
+	for t, v := range values {
+		rows = append(rows, row{Timestamp: t, Metrics: v})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Timestamp.Before(rows[j].Timestamp) })
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		if rows == nil {
+			rows = []row{}
+		}
+		return output.Print(out, format, rows, nil)
+	case "csv":
+		return writeCSV(out, queried, rows)
+	default:
+		print(out, queried, rows)
+		return nil
+	}
+}
+
+// A row is the value of each metric in one time bucket. Metrics without a value in the bucket are left out.
+type row struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Metrics   map[string]float64 `json:"metrics"`
+}
+
+func metricNames() []string {
+	names := make([]string, len(metrics))
+	for i, m := range metrics {
+		names[i] = m.name
+	}
+	return names
+}
+
+func writeCSV(w io.Writer, queried []metric, rows []row) error {
+	cw := csv.NewWriter(w)
+	header := []string{"timestamp"}
+	for _, m := range queried {
+		header = append(header, m.name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{r.Timestamp.UTC().Format(time.RFC3339)}
+		for _, m := range queried {
+			v, ok := r.Metrics[m.name]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func print(w io.Writer, queried []metric, rows []row) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No metrics in the time window.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "Timestamp\t")
+	for _, m := range queried {
+		fmt.Fprintf(tw, "%s\t", m.column)
+	}
+	fmt.Fprintln(tw)
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t", r.Timestamp.UTC().Format(time.RFC3339))
+		for _, m := range queried {
+			v, ok := r.Metrics[m.name]
+			switch {
+			case !ok:
+				fmt.Fprint(tw, "-\t")
+			case m.bytes:
+				fmt.Fprintf(tw, "%s\t", formatBytes(v))
+			default:
+				fmt.Fprintf(tw, "%s\t", strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	_ = tw.Flush()
+}
+
+// formatBytes formats a size in decimal units, as Confluent Cloud bills them.
+func formatBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for b >= 1000 && i < len(units)-1 {
+		b /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
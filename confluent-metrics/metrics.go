@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+)
+
+const metricsURL = "https://api.telemetry.confluent.cloud/v2/metrics/cloud/query"
+
+// A metric of a Kafka cluster which can be queried, under the name which --metric takes.
+type metric struct {
+	name   string
+	column string
+	api    string
+	// topic is whether the metric can be filtered by topic.
+	topic bool
+	bytes bool
+}
+
+var metrics = []metric{
+	{name: "received_bytes", column: "Received", api: "io.confluent.kafka.server/received_bytes", topic: true, bytes: true},
+	{name: "sent_bytes", column: "Sent", api: "io.confluent.kafka.server/sent_bytes", topic: true, bytes: true},
+	{name: "received_records", column: "Received Records", api: "io.confluent.kafka.server/received_records", topic: true},
+	{name: "sent_records", column: "Sent Records", api: "io.confluent.kafka.server/sent_records", topic: true},
+	{name: "retained_bytes", column: "Retained", api: "io.confluent.kafka.server/retained_bytes", topic: true, bytes: true},
+	{name: "partition_count", column: "Partitions", api: "io.confluent.kafka.server/partition_count"},
+	{name: "request_count", column: "Requests", api: "io.confluent.kafka.server/request_count"},
+}
+
+// granularities are the sizes of the time buckets which the Metrics API supports.
+var granularities = []string{"PT1M", "PT5M", "PT15M", "PT30M", "PT1H", "PT4H", "PT6H", "PT12H", "P1D", "ALL"}
+
+type point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+type querier struct {
+	cluster     string
+	topic       string
+	interval    string
+	granularity string
+	key         string
+	secret      string
+	client      *http.Client
+}
+
+// query returns the metric's value in each time bucket, following the pages of the response.
+func (q querier) query(m metric) ([]point, error) {
+	filter := map[string]any{"field": "resource.kafka.id", "op": "EQ", "value": q.cluster}
+	if q.topic != "" {
+		filter = map[string]any{
+			"op": "AND",
+			"filters": []map[string]string{
+				{"field": "resource.kafka.id", "op": "EQ", "value": q.cluster},
+				{"field": "metric.topic", "op": "EQ", "value": q.topic},
+			},
+		}
+	}
+
+	return paging.All(func(token string) ([]point, string, error) {
+		body := map[string]any{
+			"aggregations": []map[string]string{{"metric": m.api}},
+			"filter":       filter,
+			"granularity":  q.granularity,
+			"intervals":    []string{q.interval},
+			"limit":        1000,
+		}
+		url := paging.WithToken(metricsURL, token)
+
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(q.key, q.secret)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := q.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var page struct {
+			paging.MetricsPage[point]
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			var details []string
+			for _, e := range page.Errors {
+				details = append(details, e.Detail)
+			}
+			if len(details) > 0 {
+				return nil, "", fmt.Errorf("failed to query %s: %s: %s", m.name, res.Status, strings.Join(details, "; "))
+			}
+			return nil, "", fmt.Errorf("failed to query %s: %s", m.name, res.Status)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", m.name, err)
+		}
+
+		return page.Data, page.Next(), nil
+	})
+}
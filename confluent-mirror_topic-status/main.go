@@ -0,0 +1,177 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errUnhealthy fails the plugin with exitcode.Findings when a mirror topic isn't replicating, or lags more than the
+// threshold, so that alerting scripts can act on it.
+var errUnhealthy = exitcode.New(exitcode.Findings, "mirror topics are unhealthy")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "status",
+		Short: "Report the replication status of mirror topics.",
+		Long:  "Report the status and lag of the mirror topics of every cluster link in a destination cluster, or of the links passed with --link, and flag those which are paused or failed. Exits with code 2 if any mirror topic is paused or failed, or with --threshold, lags more than it.",
+		Args:  cobra.NoArgs,
+		RunE:  status,
+		Example: `confluent mirror-topic status --cluster lkc-123456
+confluent mirror-topic status --link dr-link --threshold 10000 --output json
+confluent mirror-topic status --watch 10s`,
+	}
+
+	cmd.Flags().String("cluster", "", "ID of the destination cluster. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().StringSlice("link", nil, "Cluster links to report the mirror topics of. Defaults to every link.")
+	cmd.Flags().Int64("threshold", 0, "Exit with code 2 if the lag of any partition of a mirror topic exceeds this.")
+	cmd.Flags().Duration("watch", 0, "Refresh the report at this interval until interrupted.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-mirror_topic-status")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-mirror_topic-status"))
+
+	exitcode.Execute(&cmd)
+}
+
+func status(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	links, err := cmd.Flags().GetStringSlice("link")
+	cobra.CheckErr(err)
+
+	threshold, err := cmd.Flags().GetInt64("threshold")
+	cobra.CheckErr(err)
+
+	watch, err := cmd.Flags().GetDuration("watch")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if watch < 0 {
+		return exitcode.Invalid("--watch must not be negative")
+	}
+	// A threshold of 0 is meaningful, so whether it's set is told from whether the flag was passed.
+	checkThreshold := cmd.Flags().Changed("threshold")
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	out := cmd.OutOrStdout()
+	report := func() (bool, error) {
+		mirrors, err := readMirrors(links, scope)
+		if err != nil {
+			return false, err
+		}
+
+		var unhealthy, lagging []mirror
+		for _, m := range mirrors {
+			switch {
+			case m.unhealthy():
+				unhealthy = append(unhealthy, m)
+			case checkThreshold && m.Lag > threshold:
+				lagging = append(lagging, m)
+			}
+		}
+		flagged := len(unhealthy) > 0 || len(lagging) > 0
+
+		if format != output.Table {
+			if mirrors == nil {
+				mirrors = []mirror{}
+			}
+			return flagged, output.Print(out, format, mirrors, nil)
+		}
+
+		if watch > 0 {
+			// Clear the terminal, so that the report is redrawn in place, unless its output is piped.
+			if platform.Terminal(out) {
+				fmt.Fprint(out, "\033[H\033[2J")
+			}
+			fmt.Fprintf(out, "Every %s: %s\n\n", watch, time.Now().Format(time.TimeOnly))
+		}
+		print(out, mirrors)
+		if len(unhealthy) > 0 {
+			fmt.Fprintf(out, "\n%d mirror topics aren't replicating:\n", len(unhealthy))
+			for _, m := range unhealthy {
+				fmt.Fprintf(out, "  %s on %s: %s since %s\n", m.Topic, m.Link, m.Status, m.Since.Format(time.RFC3339))
+			}
+		}
+		if len(lagging) > 0 {
+			fmt.Fprintf(out, "\n%d mirror topics have a lag over %d:\n", len(lagging), threshold)
+			for _, m := range lagging {
+				fmt.Fprintf(out, "  %s on %s: %d\n", m.Topic, m.Link, m.Lag)
+			}
+		}
+		return flagged, nil
+	}
+
+	if watch == 0 {
+		flagged, err := report()
+		if err != nil {
+			return err
+		}
+		if flagged {
+			return errUnhealthy
+		}
+		return nil
+	}
+
+	// A watch runs until it's interrupted, so unhealthy mirror topics are only reported, and don't end it.
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(watch)
+	defer ticker.Stop()
+	for {
+		if _, err := report(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func print(w io.Writer, mirrors []mirror) {
+	if len(mirrors) == 0 {
+		fmt.Fprintln(w, "No mirror topics found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Link\tMirror Topic\tSource Topic\tStatus\tPartitions\tMax Lag")
+	for _, m := range mirrors {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n", m.Link, m.Topic, m.SourceTopic, m.Status, m.Partitions, m.Lag)
+	}
+	_ = tw.Flush()
+}
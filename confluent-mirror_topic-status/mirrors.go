@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// unhealthyStatuses are the mirror statuses in which a mirror topic has stopped replicating without being promoted or
+// failed over, which stops it on purpose.
+var unhealthyStatuses = map[string]bool{
+	"FAILED":             true,
+	"PAUSED":             true,
+	"LINK_FAILED":        true,
+	"LINK_PAUSED":        true,
+	"SOURCE_UNAVAILABLE": true,
+}
+
+// listedMirror is a mirror topic in the output of "confluent kafka mirror list".
+type listedMirror struct {
+	LinkName                 string `json:"link_name"`
+	MirrorTopicName          string `json:"mirror_topic_name"`
+	SourceTopicName          string `json:"source_topic_name"`
+	MirrorStatus             string `json:"mirror_status"`
+	StatusTimeMs             int64  `json:"status_time_ms"`
+	NumPartition             int    `json:"num_partition"`
+	MaxPerPartitionMirrorLag int64  `json:"max_per_partition_mirror_lag"`
+}
+
+// A mirror is the replication status of one mirror topic. The lag is that of its most lagging partition, in messages.
+type mirror struct {
+	Link        string    `json:"link"`
+	Topic       string    `json:"topic"`
+	SourceTopic string    `json:"source_topic"`
+	Status      string    `json:"status"`
+	Since       time.Time `json:"since"`
+	Partitions  int       `json:"partitions"`
+	Lag         int64     `json:"lag"`
+}
+
+func (m mirror) unhealthy() bool {
+	return unhealthyStatuses[m.Status]
+}
+
+// readMirrors returns the mirror topics of the links in the cluster, or of every link, sorted by link and topic.
+func readMirrors(links []string, cluster []string) ([]mirror, error) {
+	if len(links) == 0 {
+		var listed []struct {
+			LinkName string `json:"link_name"`
+		}
+		if err := cli.JSON(&listed, append([]string{"kafka", "link", "list"}, cluster...)...); err != nil {
+			return nil, err
+		}
+		for _, l := range listed {
+			links = append(links, l.LinkName)
+		}
+	}
+
+	var mirrors []mirror
+	for _, link := range links {
+		var listed []listedMirror
+		if err := cli.JSON(&listed, append([]string{"kafka", "mirror", "list", "--link", link}, cluster...)...); err != nil {
+			return nil, fmt.Errorf(`failed to list the mirror topics of cluster link "%s": %w`, link, err)
+		}
+		for _, l := range listed {
+			mirrors = append(mirrors, mirror{
+				Link:        link,
+				Topic:       l.MirrorTopicName,
+				SourceTopic: l.SourceTopicName,
+				Status:      l.MirrorStatus,
+				Since:       time.UnixMilli(l.StatusTimeMs).UTC(),
+				Partitions:  l.NumPartition,
+				Lag:         l.MaxPerPartitionMirrorLag,
+			})
+		}
+	}
+
+	sort.Slice(mirrors, func(i, j int) bool {
+		if mirrors[i].Link != mirrors[j].Link {
+			return mirrors[i].Link < mirrors[j].Link
+		}
+		return mirrors[i].Topic < mirrors[j].Topic
+	})
+	return mirrors, nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+const (
+	pass = "pass"
+	fail = "fail"
+	skip = "skip"
+)
+
+// A check is the result of one diagnostic of one endpoint.
+type check struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// A checker checks the endpoints of a cluster, in the order which a client connects to them, so that the first check
+// which fails is the cause, and the checks which depend on it are skipped.
+type checker struct {
+	timeout      time.Duration
+	apiKey       string
+	apiSecret    string
+	maxClockSkew time.Duration
+}
+
+// endpoint checks resolving, connecting to, and handshaking TLS with a host and port, and returns the TLS connection,
+// or nil if a check failed.
+func (c checker) endpoint(addr string, checks *[]check) *tls.Conn {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		*checks = append(*checks, check{"DNS", addr, fail, err.Error()})
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		*checks = append(*checks, check{"DNS", host, fail, err.Error()}, check{"TCP", addr, skip, ""}, check{"TLS", addr, skip, ""})
+		return nil
+	}
+	ips := make([]string, len(addrs))
+	private := true
+	for i, a := range addrs {
+		ips[i] = a.IP.String()
+		private = private && a.IP.IsPrivate()
+	}
+	detail := strings.Join(ips, ", ")
+	if private {
+		// Clusters with private networking only resolve to addresses in their peered or private linked networks.
+		detail += " (private, reachable only from the cluster's network)"
+	}
+	*checks = append(*checks, check{"DNS", host, pass, detail})
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, c.timeout)
+	if err != nil {
+		*checks = append(*checks, check{"TCP", addr, fail, err.Error()}, check{"TLS", addr, skip, ""})
+		return nil
+	}
+	*checks = append(*checks, check{"TCP", addr, pass, fmt.Sprintf("connected in %s", time.Since(start).Round(time.Millisecond))})
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+	_ = tlsConn.SetDeadline(time.Now().Add(c.timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		*checks = append(*checks, check{"TLS", addr, fail, err.Error()})
+		return nil
+	}
+	state := tlsConn.ConnectionState()
+	cert := state.PeerCertificates[0]
+	*checks = append(*checks, check{"TLS", addr, pass, fmt.Sprintf("%s, certificate valid until %s", tls.VersionName(state.Version), cert.NotAfter.UTC().Format(time.DateOnly))})
+	return tlsConn
+}
+
+// kafka checks the bootstrap server, authenticating with the API key if there is one, and then each broker which it
+// advertises.
+func (c checker) kafka(bootstrap string) []check {
+	var checks []check
+	conn := c.endpoint(bootstrap, &checks)
+	if conn == nil {
+		checks = append(checks, check{"SASL", bootstrap, skip, ""})
+		return checks
+	}
+	defer conn.Close()
+
+	if c.apiKey == "" {
+		checks = append(checks, check{"SASL", bootstrap, skip, "pass --api-key to check authentication and the brokers"})
+		return checks
+	}
+	kc := kafka.NewConn(conn, "confluent-network-check", c.timeout)
+	if err := kc.Authenticate(c.apiKey, c.apiSecret); err != nil {
+		checks = append(checks, check{"SASL", bootstrap, fail, err.Error()})
+		return checks
+	}
+	brokers, err := kc.Brokers()
+	if err != nil {
+		checks = append(checks, check{"SASL", bootstrap, fail, fmt.Sprintf("failed to list the brokers: %s", err)})
+		return checks
+	}
+	checks = append(checks, check{"SASL", bootstrap, pass, fmt.Sprintf("authenticated as %s, %d brokers", c.apiKey, len(brokers))})
+
+	// Clients connect to every broker which leads a partition, so each is checked, at once, since a firewall which drops
+	// the connections would otherwise take the timeout for each of them.
+	perBroker := make([][]check, len(brokers))
+	var wg sync.WaitGroup
+	for i, b := range brokers {
+		wg.Add(1)
+		go func(i int, b kafka.Broker) {
+			defer wg.Done()
+			if conn := c.endpoint(b.Addr, &perBroker[i]); conn != nil {
+				_ = conn.Close()
+			}
+		}(i, b)
+	}
+	wg.Wait()
+	for _, b := range perBroker {
+		checks = append(checks, b...)
+	}
+	return checks
+}
+
+// rest checks the REST endpoint, and the clock against the endpoint's, since a skewed clock breaks the validity of
+// certificates and tokens.
+func (c checker) rest(endpoint string) []check {
+	var checks []check
+	addr := strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+	addr = strings.TrimSuffix(addr, "/")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "443")
+	}
+
+	conn := c.endpoint(addr, &checks)
+	if conn == nil {
+		checks = append(checks, check{"Clock", endpoint, skip, ""})
+		return checks
+	}
+	_ = conn.Close()
+
+	client := http.Client{Timeout: c.timeout}
+	before := time.Now()
+	res, err := client.Get("https://" + addr)
+	if err != nil {
+		checks = append(checks, check{"Clock", endpoint, fail, err.Error()})
+		return checks
+	}
+	_ = res.Body.Close()
+	after := time.Now()
+
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		checks = append(checks, check{"Clock", endpoint, skip, "the endpoint didn't send its time"})
+		return checks
+	}
+	// The endpoint's time is of when it handled the request, somewhere during the round trip, and only to the second.
+	local := before.Add(after.Sub(before) / 2)
+	skew := local.Sub(date).Round(time.Second)
+	status := pass
+	if skew.Abs() > c.maxClockSkew {
+		status = fail
+	}
+	detail := "local clock is in sync"
+	switch {
+	case skew > 0:
+		detail = fmt.Sprintf("local clock is %s ahead", skew)
+	case skew < 0:
+		detail = fmt.Sprintf("local clock is %s behind", -skew)
+	}
+	checks = append(checks, check{"Clock", endpoint, status, detail})
+	return checks
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errFailed fails the plugin with exitcode.Findings when a check fails, as opposed to when the checks can't be run.
+var errFailed = exitcode.New(exitcode.Findings, "checks failed")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "check",
+		Short: "Diagnose connectivity to a Kafka cluster.",
+		Long:  "Check that the bootstrap server and REST endpoint of a Kafka cluster resolve, and are reachable over TCP and TLS, and that the local clock agrees with Confluent Cloud's. With --api-key, also check that the API key authenticates, and that every broker of the cluster is reachable. Exits with code 2 if any check fails.",
+		Args:  cobra.NoArgs,
+		RunE:  networkCheck,
+		Example: `confluent network check --cluster lkc-123456
+confluent network check --cluster lkc-123456 --api-key ABCDEFGHIJKLMNOP
+confluent network check --bootstrap pkc-12345.us-west-2.aws.confluent.cloud:9092 --rest-endpoint https://pkc-12345.us-west-2.aws.confluent.cloud:443`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster, to check authentication and the brokers with.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().String("rest-endpoint", "", "REST endpoint of the cluster. Defaults to the cluster's REST endpoint.")
+	cmd.Flags().Duration("timeout", 10*time.Second, "How long each check may take.")
+	cmd.Flags().Duration("max-clock-skew", 5*time.Second, "How far off the local clock may be.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-network-check")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-network-check"))
+
+	exitcode.Execute(&cmd)
+}
+
+func networkCheck(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	restEndpoint, err := cmd.Flags().GetString("rest-endpoint")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	maxClockSkew, err := cmd.Flags().GetDuration("max-clock-skew")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return exitcode.Invalid("--timeout must be positive")
+	}
+	if apiKey != "" && apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+		if apiSecret == "" {
+			return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required with --api-key")
+		}
+	}
+
+	// The endpoints are only looked up if they weren't passed, so that the network can be checked without the CLI being
+	// able to reach Confluent Cloud.
+	if bootstrap == "" || restEndpoint == "" {
+		described, err := cli.DescribeKafkaCluster(clusterID, environment)
+		if err != nil {
+			return err
+		}
+		if bootstrap == "" {
+			bootstrap = described.Endpoint
+		}
+		if restEndpoint == "" {
+			restEndpoint = described.RestEndpoint
+		}
+	}
+	bootstrap = strings.TrimPrefix(bootstrap, "SASL_SSL://")
+
+	c := checker{timeout: timeout, apiKey: apiKey, apiSecret: apiSecret, maxClockSkew: maxClockSkew}
+	checks := c.kafka(bootstrap)
+	if restEndpoint != "" {
+		checks = append(checks, c.rest(restEndpoint)...)
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, checks, func() { print(out, checks) }); err != nil {
+		return err
+	}
+
+	for _, c := range checks {
+		if c.Status == fail {
+			return errFailed
+		}
+	}
+	return nil
+}
+
+func print(w io.Writer, checks []check) {
+	counts := map[string]int{}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Check\tTarget\tResult\tDetail")
+	for _, c := range checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Check, c.Target, strings.ToUpper(c.Status), c.Detail)
+		counts[c.Status]++
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped.\n", counts[pass], counts[fail], counts[skip])
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// resourceID matches the IDs of the resources which long-running commands create, such as Kafka clusters, connectors,
+// and Flink compute pools.
+var resourceID = regexp.MustCompile(`\b(?:lkc|lcc|lsrc|lksqlc|lfcp|env|sa|pool|n|pla|dlz|op|u)-[0-9a-z]+\b`)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "notify -- <command>",
+		Short: "Run a confluent command and notify when it finishes.",
+		Long:  "Run a long-running confluent command, such as creating a cluster, provisioning a connector, or running a Flink statement, and send a Slack message, a webhook, or a desktop notification when it succeeds or fails, with the ID of the resource and how long the command took. Exits with the command's exit code.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  notify,
+		Example: `confluent notify --slack-webhook https://hooks.slack.com/services/... -- kafka cluster create orders --cloud aws --region us-west-2 --type dedicated --cku 2
+confluent notify --desktop -- flink statement create backfill --sql "INSERT INTO orders SELECT * FROM orders_archive" --wait`,
+	}
+
+	// Flags after the wrapped command belong to it.
+	cmd.Flags().SetInterspersed(false)
+
+	cmd.Flags().String("slack-webhook", os.Getenv("CONFLUENT_NOTIFY_SLACK_WEBHOOK"), "URL of a Slack incoming webhook to post to. Defaults to $CONFLUENT_NOTIFY_SLACK_WEBHOOK.")
+	cmd.Flags().String("webhook", os.Getenv("CONFLUENT_NOTIFY_WEBHOOK"), "URL to post the outcome to as JSON. Defaults to $CONFLUENT_NOTIFY_WEBHOOK.")
+	cmd.Flags().Bool("desktop", false, "Show a desktop notification.")
+	cmd.Flags().Bool("failure-only", false, "Only notify if the command fails.")
+	cmd.Flags().String("resource", "", "ID of the resource to include in the notification. Defaults to the first ID in the command's output.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-notify")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-notify"))
+
+	exitcode.Execute(&cmd)
+}
+
+func notify(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	slackWebhook, err := cmd.Flags().GetString("slack-webhook")
+	cobra.CheckErr(err)
+
+	webhookURL, err := cmd.Flags().GetString("webhook")
+	cobra.CheckErr(err)
+
+	showDesktop, err := cmd.Flags().GetBool("desktop")
+	cobra.CheckErr(err)
+
+	failureOnly, err := cmd.Flags().GetBool("failure-only")
+	cobra.CheckErr(err)
+
+	resource, err := cmd.Flags().GetString("resource")
+	cobra.CheckErr(err)
+
+	var notifiers []notifier
+	if slackWebhook != "" {
+		notifiers = append(notifiers, slack{url: slackWebhook, client: newHTTPClient()})
+	}
+	if webhookURL != "" {
+		notifiers = append(notifiers, webhook{url: webhookURL, client: newHTTPClient()})
+	}
+	if showDesktop {
+		notifiers = append(notifiers, desktop{})
+	}
+	if len(notifiers) == 0 {
+		return exitcode.Invalid("nothing to notify, pass --slack-webhook, --webhook, or --desktop")
+	}
+
+	// The command's output is passed through as it's written, and kept to find the resource ID and error in.
+	var stdout, stderr bytes.Buffer
+	command := exec.Command("confluent", args...)
+	command.Stdin = os.Stdin
+	command.Stdout = io.MultiWriter(cmd.OutOrStdout(), &stdout)
+	command.Stderr = io.MultiWriter(cmd.ErrOrStderr(), &stderr)
+
+	start := time.Now()
+	runErr := command.Run()
+
+	// The error is only printed by the wrapped command.
+	var exit *exec.ExitError
+	if errors.As(runErr, &exit) {
+		cmd.SilenceErrors = true
+	}
+
+	e := event{
+		Command:   strings.Join(redact(args), " "),
+		Succeeded: runErr == nil,
+		Resource:  resource,
+		StartedAt: start.UTC(),
+		Duration:  time.Since(start).Round(time.Second).String(),
+	}
+	if e.Resource == "" {
+		e.Resource = findResource(stdout.Bytes())
+	}
+	if runErr != nil {
+		e.ExitCode = 1
+		e.Error = lastLine(stderr.String())
+		if exit != nil {
+			e.ExitCode = exit.ExitCode()
+		} else {
+			e.Error = runErr.Error()
+		}
+	}
+
+	if !failureOnly || !e.Succeeded {
+		for _, n := range notifiers {
+			if err := n.notify(e); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Failed to notify %s: %v\n", n.name(), err)
+			}
+		}
+	}
+
+	// The plugin exits with the command's exit code, which is what scripts which wrapped the command expect.
+	if exit != nil {
+		return exitcode.With(exit.ExitCode(), runErr)
+	}
+	return runErr
+}
+
+// findResource returns the ID of the resource which the command printed, such as in the "id" field of its JSON output,
+// or "" if there's none.
+func findResource(out []byte) string {
+	var v struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(out, &v); err == nil {
+		if v.ID != "" {
+			return v.ID
+		}
+		if v.Name != "" {
+			return v.Name
+		}
+	}
+	return resourceID.FindString(string(out))
+}
+
+// redact masks the values of the command's flags which are secrets, such as --api-secret, which shouldn't be posted
+// to a chat.
+func redact(args []string) []string {
+	redacted := make([]string, len(args))
+	secret := false
+	for i, arg := range args {
+		switch {
+		case secret:
+			redacted[i] = "***"
+			secret = false
+		case strings.HasPrefix(arg, "--") && isSecret(arg):
+			if name, _, ok := strings.Cut(arg, "="); ok {
+				redacted[i] = name + "=***"
+			} else {
+				redacted[i] = arg
+				secret = true
+			}
+		default:
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
+
+func isSecret(flag string) bool {
+	name, _, _ := strings.Cut(flag, "=")
+	return strings.Contains(name, "secret") || strings.Contains(name, "password")
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// An event is the outcome of a wrapped command, which is sent to every notifier.
+type event struct {
+	Command   string    `json:"command"`
+	Succeeded bool      `json:"succeeded"`
+	ExitCode  int       `json:"exit_code"`
+	Resource  string    `json:"resource,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// summary is a one-line description of the event, for chat messages and desktop notifications.
+func (e event) summary() string {
+	status := "succeeded"
+	if !e.Succeeded {
+		status = fmt.Sprintf("failed with exit code %d", e.ExitCode)
+	}
+	s := fmt.Sprintf("confluent %s %s after %s", e.Command, status, e.Duration)
+	if e.Resource != "" {
+		s += fmt.Sprintf(" (%s)", e.Resource)
+	}
+	if e.Error != "" {
+		s += ": " + e.Error
+	}
+	return s
+}
+
+// A notifier sends the outcome of a command somewhere, such as to Slack.
+type notifier interface {
+	name() string
+	notify(e event) error
+}
+
+// slack posts a message to a Slack incoming webhook.
+type slack struct {
+	url    string
+	client *http.Client
+}
+
+func (s slack) name() string {
+	return "Slack"
+}
+
+func (s slack) notify(e event) error {
+	icon := ":white_check_mark:"
+	if !e.Succeeded {
+		icon = ":x:"
+	}
+	return post(s.client, s.url, map[string]string{"text": fmt.Sprintf("%s `%s`", icon, e.summary())})
+}
+
+// webhook posts the event as JSON to any URL, such as an incident management tool's.
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+func (w webhook) name() string {
+	return "the webhook"
+}
+
+func (w webhook) notify(e event) error {
+	return post(w.client, w.url, e)
+}
+
+func post(client *http.Client, url string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s: %s", res.Status, bytes.TrimSpace(b))
+	}
+	return nil
+}
+
+// desktop shows a notification with osascript on macOS, notify-send on Linux, or a toast of PowerShell on Windows.
+type desktop struct{}
+
+func (desktop) name() string {
+	return "the desktop"
+}
+
+func (desktop) notify(e event) error {
+	title := "Confluent CLI"
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(e.summary()), appleScriptString(title))
+		command = exec.Command("osascript", "-e", script)
+	case "linux":
+		urgency := "normal"
+		if !e.Succeeded {
+			urgency = "critical"
+		}
+		command = exec.Command("notify-send", "--urgency", urgency, title, e.summary())
+	case "windows":
+		// A toast is shown as PowerShell's, since Windows only shows those of apps which it knows.
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$toast = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $toast.GetElementsByTagName('text')
+$text.Item(0).AppendChild($toast.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($toast.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe').Show([Windows.UI.Notifications.ToastNotification]::new($toast))`, powerShellString(title), powerShellString(e.summary()))
+		command = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	default:
+		return fmt.Errorf("desktop notifications aren't supported on %s", runtime.GOOS)
+	}
+
+	if out, err := command.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", command.Path, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
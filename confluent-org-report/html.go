@@ -0,0 +1,130 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+// reportTemplate renders the report as a self-contained HTML page, which can be attached to an architecture review.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Organization.Name}} inventory</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+.errors { color: #b00; }
+</style>
+</head>
+<body>
+<h1>{{.Organization.Name}} ({{.Organization.ID}})</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}.</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Environments</th><td>{{len .Environments}}</td></tr>
+<tr><th>Kafka clusters</th><td>{{.Summary.KafkaClusters}}</td></tr>
+<tr><th>Topics</th><td>{{.Summary.Topics}}</td></tr>
+<tr><th>Connectors</th><td>{{.Summary.Connectors}}</td></tr>
+<tr><th>Flink compute pools</th><td>{{.Summary.ComputePools}}</td></tr>
+<tr><th>ksqlDB clusters</th><td>{{.Summary.KsqlClusters}}</td></tr>
+<tr><th>Schema subjects</th><td>{{.Summary.Subjects}}</td></tr>
+<tr><th>Service accounts</th><td>{{len .ServiceAccounts}}</td></tr>
+<tr><th>Users</th><td>{{len .Users}}</td></tr>
+<tr><th>API keys</th><td>{{len .APIKeys}}</td></tr>
+</table>
+{{if .Errors}}
+<h2>Errors</h2>
+<ul class="errors">
+{{range .Errors}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}
+{{range .Environments}}
+<h2>Environment {{.Name}} ({{.ID}})</h2>
+{{if .KafkaClusters}}
+<h3>Kafka clusters</h3>
+<table>
+<tr><th>ID</th><th>Name</th><th>Type</th><th>Cloud</th><th>Region</th><th>Availability</th><th>Status</th><th>Topics</th><th>Connectors</th></tr>
+{{range .KafkaClusters}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Cloud}}</td><td>{{.Region}}</td><td>{{.Availability}}</td><td>{{.Status}}</td><td>{{.Topics}}</td><td>{{len .Connectors}}</td></tr>
+{{end}}</table>
+{{end}}
+{{range .KafkaClusters}}{{if .Connectors}}
+<h3>Connectors of {{.Name}} ({{.ID}})</h3>
+<table>
+<tr><th>ID</th><th>Name</th><th>Type</th><th>Status</th></tr>
+{{range .Connectors}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+{{if .ComputePools}}
+<h3>Flink compute pools</h3>
+<table>
+<tr><th>ID</th><th>Name</th><th>Cloud</th><th>Region</th><th>CFUs</th><th>Max CFUs</th><th>Status</th></tr>
+{{range .ComputePools}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Cloud}}</td><td>{{.Region}}</td><td>{{.CurrentCFU}}</td><td>{{.MaxCFU}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .KsqlClusters}}
+<h3>ksqlDB clusters</h3>
+<table>
+<tr><th>ID</th><th>Name</th><th>Kafka Cluster</th><th>Status</th></tr>
+{{range .KsqlClusters}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Kafka}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .SchemaRegistry}}
+<h3>Schema Registry</h3>
+<p>{{.ID}} at {{.Endpoint}}, with {{.Subjects}} subjects.</p>
+{{end}}
+{{end}}
+<h2>Service accounts</h2>
+<table>
+<tr><th>ID</th><th>Name</th><th>Description</th></tr>
+{{range .ServiceAccounts}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+
+<h2>Users</h2>
+<table>
+<tr><th>ID</th><th>Email</th><th>Name</th></tr>
+{{range .Users}}<tr><td>{{.ID}}</td><td>{{.Email}}</td><td>{{.FullName}}</td></tr>
+{{end}}</table>
+
+<h2>API keys</h2>
+<table>
+<tr><th>Key</th><th>Description</th><th>Owner</th><th>Resource Type</th><th>Resource</th><th>Created</th></tr>
+{{range .APIKeys}}<tr><td>{{.Key}}</td><td>{{.Description}}</td><td>{{.OwnerResourceID}}{{if .OwnerEmail}} ({{.OwnerEmail}}){{end}}</td><td>{{.ResourceType}}</td><td>{{.ResourceID}}</td><td>{{.Created}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// summary counts the resources of every environment.
+type summary struct {
+	KafkaClusters int
+	Topics        int
+	Connectors    int
+	ComputePools  int
+	KsqlClusters  int
+	Subjects      int
+}
+
+func (r *report) Summary() summary {
+	var s summary
+	for _, e := range r.Environments {
+		s.KafkaClusters += len(e.KafkaClusters)
+		for _, k := range e.KafkaClusters {
+			s.Topics += k.Topics
+			s.Connectors += len(k.Connectors)
+		}
+		s.ComputePools += len(e.ComputePools)
+		s.KsqlClusters += len(e.KsqlClusters)
+		if e.SchemaRegistry != nil {
+			s.Subjects += e.SchemaRegistry.Subjects
+		}
+	}
+	return s
+}
+
+func writeHTML(w io.Writer, r *report) error {
+	return reportTemplate.Execute(w, r)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// A report is the inventory of an organization.
+type report struct {
+	Organization    organization     `json:"organization"`
+	Generated       time.Time        `json:"generated"`
+	Environments    []*environment   `json:"environments"`
+	ServiceAccounts []serviceAccount `json:"service_accounts"`
+	Users           []user           `json:"users"`
+	APIKeys         []cli.APIKey     `json:"api_keys"`
+	// Errors are the parts of the inventory which couldn't be listed, such as for lack of permissions.
+	Errors []string `json:"errors,omitempty"`
+
+	mu sync.Mutex
+}
+
+type organization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type environment struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	KafkaClusters  []*kafkaCluster `json:"kafka_clusters"`
+	ComputePools   []computePool   `json:"flink_compute_pools"`
+	KsqlClusters   []ksqlCluster   `json:"ksql_clusters"`
+	SchemaRegistry *schemaRegistry `json:"schema_registry,omitempty"`
+}
+
+type kafkaCluster struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Type         string      `json:"type"`
+	Cloud        string      `json:"cloud"`
+	Region       string      `json:"region"`
+	Availability string      `json:"availability"`
+	Status       string      `json:"status"`
+	Topics       int         `json:"topics"`
+	Connectors   []connector `json:"connectors"`
+}
+
+type connector struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+type computePool struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Cloud      string `json:"cloud"`
+	Region     string `json:"region"`
+	MaxCFU     int    `json:"max_cfu"`
+	CurrentCFU int    `json:"current_cfu"`
+	Status     string `json:"status"`
+}
+
+type ksqlCluster struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Kafka  string `json:"kafka"`
+	Status string `json:"status"`
+}
+
+type schemaRegistry struct {
+	ID       string `json:"cluster_id"`
+	Endpoint string `json:"endpoint_url"`
+	Subjects int    `json:"subjects"`
+}
+
+type serviceAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type user struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+// collector lists the resources of the organization, running up to parallelism CLI commands at once.
+type collector struct {
+	r   *report
+	wg  sync.WaitGroup
+	sem chan struct{}
+}
+
+// collect runs the function in the background, recording its error in the report.
+func (c *collector) collect(what string, f func() error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.sem <- struct{}{}
+		err := f()
+		<-c.sem
+		if err != nil {
+			c.r.mu.Lock()
+			c.r.Errors = append(c.r.Errors, fmt.Sprintf("%s: %v", what, err))
+			c.r.mu.Unlock()
+		}
+	}()
+}
+
+// inventory lists every resource of the organization. Listing the resources of an environment or cluster only starts
+// once it's been listed itself, so the slices of the report are only appended to before then.
+func inventory(parallelism int) *report {
+	r := &report{Generated: time.Now().UTC()}
+	c := &collector{r: r, sem: make(chan struct{}, parallelism)}
+
+	c.collect("organization", func() error {
+		return cli.JSON(&r.Organization, "organization", "describe")
+	})
+	c.collect("service accounts", func() error {
+		return cli.JSON(&r.ServiceAccounts, "iam", "service-account", "list")
+	})
+	c.collect("users", func() error {
+		return cli.JSON(&r.Users, "iam", "user", "list")
+	})
+	c.collect("API keys", func() error {
+		return cli.JSON(&r.APIKeys, "api-key", "list")
+	})
+
+	var environments []*environment
+	if err := cli.JSON(&environments, "environment", "list"); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("environments: %v", err))
+	}
+	r.Environments = environments
+	for _, e := range environments {
+		c.environment(e)
+	}
+
+	c.wg.Wait()
+	return r
+}
+
+func (c *collector) environment(e *environment) {
+	c.collect(fmt.Sprintf("Kafka clusters of %s", e.ID), func() error {
+		if err := cli.JSON(&e.KafkaClusters, "kafka", "cluster", "list", "--environment", e.ID); err != nil {
+			return err
+		}
+		for _, k := range e.KafkaClusters {
+			c.cluster(e, k)
+		}
+		return nil
+	})
+	c.collect(fmt.Sprintf("Flink compute pools of %s", e.ID), func() error {
+		return cli.JSON(&e.ComputePools, "flink", "compute-pool", "list", "--environment", e.ID)
+	})
+	c.collect(fmt.Sprintf("ksqlDB clusters of %s", e.ID), func() error {
+		return cli.JSON(&e.KsqlClusters, "ksql", "cluster", "list", "--environment", e.ID)
+	})
+	c.collect(fmt.Sprintf("Schema Registry of %s", e.ID), func() error {
+		var sr schemaRegistry
+		if err := cli.JSON(&sr, "schema-registry", "cluster", "describe", "--environment", e.ID); err != nil {
+			// Environments without Stream Governance don't have a Schema Registry cluster.
+			return nil
+		}
+		var subjects []struct {
+			Subject string `json:"subject"`
+		}
+		if err := cli.JSON(&subjects, "schema-registry", "subject", "list", "--environment", e.ID); err != nil {
+			return err
+		}
+		sr.Subjects = len(subjects)
+		e.SchemaRegistry = &sr
+		return nil
+	})
+}
+
+func (c *collector) cluster(e *environment, k *kafkaCluster) {
+	c.collect(fmt.Sprintf("topics of %s", k.ID), func() error {
+		var topics []cli.Topic
+		if err := cli.JSON(&topics, "kafka", "topic", "list", "--cluster", k.ID, "--environment", e.ID); err != nil {
+			return err
+		}
+		k.Topics = len(topics)
+		return nil
+	})
+	c.collect(fmt.Sprintf("connectors of %s", k.ID), func() error {
+		return cli.JSON(&k.Connectors, "connect", "cluster", "list", "--cluster", k.ID, "--environment", e.ID)
+	})
+}
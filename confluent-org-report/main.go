@@ -0,0 +1,156 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "report",
+		Short: "Report an inventory of the organization.",
+		Long:  "Report an inventory of every environment of the organization, with their Kafka clusters, topic counts, connectors, Flink compute pools, ksqlDB clusters, and Schema Registry, and of the organization's service accounts, users, and API keys, as JSON, YAML, a table of the Kafka clusters, or an HTML page.",
+		Args:  cobra.NoArgs,
+		RunE:  orgReport,
+		Example: `confluent org report > inventory.json
+confluent org report --output html --file inventory.html`,
+	}
+
+	output.AddFlag(&cmd, "Format of the report", "html")
+	output.SetDefault(&cmd, output.JSON)
+	cmd.Flags().String("file", "", "File to write the report to. Defaults to stdout.")
+	cmd.Flags().Int("parallelism", 8, "How many resources to list at once.")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-org-report")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-org-report"))
+
+	exitcode.Execute(&cmd)
+}
+
+func orgReport(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	r := inventory(parallelism)
+	r.sort()
+
+	var w io.Writer = cmd.OutOrStdout()
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "html" {
+		err = writeHTML(w, r)
+	} else {
+		err = output.Print(w, format, r, func() { r.print(w) })
+	}
+	if err != nil {
+		return err
+	}
+
+	if file != "" {
+		s := r.Summary()
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote the inventory of %d environments, %d Kafka clusters, %d service accounts, %d users, and %d API keys to %s.\n", len(r.Environments), s.KafkaClusters, len(r.ServiceAccounts), len(r.Users), len(r.APIKeys), file)
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Failed to list %s\n", e)
+	}
+	if len(r.Errors) > 0 {
+		return fmt.Errorf("failed to list %d parts of the inventory", len(r.Errors))
+	}
+	return nil
+}
+
+// print prints the Kafka clusters of every environment as a table, and the counts of the rest of the inventory.
+func (r *report) print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Environment\tKafka Cluster\tName\tType\tCloud\tRegion\tStatus\tTopics\tConnectors")
+	for _, e := range r.Environments {
+		for _, k := range e.KafkaClusters {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\n", e.ID, k.ID, k.Name, k.Type, k.Cloud, k.Region, k.Status, k.Topics, len(k.Connectors))
+		}
+	}
+	_ = tw.Flush()
+
+	s := r.Summary()
+	fmt.Fprintf(w, "\n%d environments, %d Kafka clusters, %d Flink compute pools, %d ksqlDB clusters, %d service accounts, %d users, and %d API keys.\n", len(r.Environments), s.KafkaClusters, s.ComputePools, s.KsqlClusters, len(r.ServiceAccounts), len(r.Users), len(r.APIKeys))
+}
+
+// sort orders everything by ID, since the resources are listed in parallel, and replaces missing lists with empty
+// ones, so that the JSON has the same shape for every organization.
+func (r *report) sort() {
+	sort.Strings(r.Errors)
+	if r.Environments == nil {
+		r.Environments = []*environment{}
+	}
+	sort.Slice(r.Environments, func(i, j int) bool { return r.Environments[i].ID < r.Environments[j].ID })
+	for _, e := range r.Environments {
+		if e.KafkaClusters == nil {
+			e.KafkaClusters = []*kafkaCluster{}
+		}
+		sort.Slice(e.KafkaClusters, func(i, j int) bool { return e.KafkaClusters[i].ID < e.KafkaClusters[j].ID })
+		for _, k := range e.KafkaClusters {
+			if k.Connectors == nil {
+				k.Connectors = []connector{}
+			}
+			sort.Slice(k.Connectors, func(i, j int) bool { return k.Connectors[i].ID < k.Connectors[j].ID })
+		}
+		if e.ComputePools == nil {
+			e.ComputePools = []computePool{}
+		}
+		sort.Slice(e.ComputePools, func(i, j int) bool { return e.ComputePools[i].ID < e.ComputePools[j].ID })
+		if e.KsqlClusters == nil {
+			e.KsqlClusters = []ksqlCluster{}
+		}
+		sort.Slice(e.KsqlClusters, func(i, j int) bool { return e.KsqlClusters[i].ID < e.KsqlClusters[j].ID })
+	}
+	if r.ServiceAccounts == nil {
+		r.ServiceAccounts = []serviceAccount{}
+	}
+	sort.Slice(r.ServiceAccounts, func(i, j int) bool { return r.ServiceAccounts[i].ID < r.ServiceAccounts[j].ID })
+	if r.Users == nil {
+		r.Users = []user{}
+	}
+	sort.Slice(r.Users, func(i, j int) bool { return r.Users[i].ID < r.Users[j].ID })
+	if r.APIKeys == nil {
+		r.APIKeys = []cli.APIKey{}
+	}
+	sort.Slice(r.APIKeys, func(i, j int) bool { return r.APIKeys[i].Key < r.APIKeys[j].Key })
+}
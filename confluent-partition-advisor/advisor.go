@@ -0,0 +1,118 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// hoursPerMonth is the average number of hours in a month, which Confluent Cloud bills partition-hours by.
+const hoursPerMonth = 730
+
+// maxPartitionsPerCKU is the limit of partitions per CKU of Dedicated clusters, which don't bill per partition.
+const maxPartitionsPerCKU = 4500
+
+// includedPartitions are the partitions of each type of cluster which aren't billed, before each partition-hour is.
+var includedPartitions = map[string]int{
+	"BASIC":    10,
+	"STANDARD": 500,
+}
+
+// advisor sizes the partitions of a topic for its peak throughput, with each partition handling at most 1 - headroom
+// of its capacity, and for its consumer parallelism.
+type advisor struct {
+	partitionIngress float64
+	partitionEgress  float64
+	headroom         float64
+	tolerance        float64
+}
+
+// An advice is how many partitions a topic has, how many it needs, and what to do about the difference: increase
+// them, decrease them, which can only be done by recreating the topic, or keep them.
+type advice struct {
+	Topic       string  `json:"topic"`
+	Partitions  int     `json:"partitions"`
+	PeakIngress float64 `json:"peak_ingress_bytes_per_second"`
+	PeakEgress  float64 `json:"peak_egress_bytes_per_second"`
+	Consumers   int     `json:"consumers"`
+	Recommended int     `json:"recommended_partitions"`
+	Action      string  `json:"action"`
+	// MonthlyCost is the change in the monthly cost of the topic's partitions if they're changed to the recommended
+	// number, which is only known for clusters that bill per partition-hour.
+	MonthlyCost *float64 `json:"monthly_cost_change,omitempty"`
+}
+
+// A report of the partitions of a cluster's topics, and the change in cost if every recommendation is followed.
+type report struct {
+	Cluster     string   `json:"cluster"`
+	Type        string   `json:"type"`
+	CKUs        int      `json:"ckus,omitempty"`
+	Days        int      `json:"days"`
+	Partitions  int      `json:"partitions"`
+	Recommended int      `json:"recommended_partitions"`
+	MonthlyCost *float64 `json:"monthly_cost_change,omitempty"`
+	// IncludedPartitions are the partitions which the cluster doesn't bill, which its monthly cost excludes.
+	IncludedPartitions int `json:"included_partitions,omitempty"`
+	// PartitionLimit is the most partitions of a Dedicated cluster, which the recommendations shouldn't exceed.
+	PartitionLimit int      `json:"partition_limit,omitempty"`
+	Topics         []advice `json:"topics"`
+}
+
+func (a advisor) advise(t cli.Topic, ingress, egress float64, consumers int) advice {
+	needed := max(
+		int(math.Ceil(ingress/(a.partitionIngress*(1-a.headroom)))),
+		int(math.Ceil(egress/(a.partitionEgress*(1-a.headroom)))),
+		consumers,
+		1,
+	)
+
+	ad := advice{
+		Topic:       t.Name,
+		Partitions:  t.PartitionCount,
+		PeakIngress: ingress,
+		PeakEgress:  egress,
+		Consumers:   consumers,
+		Recommended: t.PartitionCount,
+		Action:      "keep",
+	}
+	switch {
+	case t.PartitionCount < needed:
+		ad.Recommended, ad.Action = needed, "increase"
+	case float64(t.PartitionCount) > a.tolerance*float64(needed):
+		ad.Recommended, ad.Action = needed, "decrease"
+	}
+	return ad
+}
+
+// price sets the change in monthly cost of each topic and of the cluster, if the cluster bills per partition-hour.
+// The cluster's change only counts the partitions beyond the included ones, so it may be less than the sum of its
+// topics'.
+func (r *report) price(clusterType string, pricePerHour float64, included int) {
+	sort.Slice(r.Topics, func(i, j int) bool { return r.Topics[i].Topic < r.Topics[j].Topic })
+	for _, t := range r.Topics {
+		r.Partitions += t.Partitions
+		r.Recommended += t.Recommended
+	}
+
+	if strings.EqualFold(clusterType, "DEDICATED") {
+		r.PartitionLimit = r.CKUs * maxPartitionsPerCKU
+		return
+	}
+	if included < 0 {
+		var ok bool
+		if included, ok = includedPartitions[strings.ToUpper(clusterType)]; !ok {
+			return
+		}
+	}
+
+	r.IncludedPartitions = included
+	for i := range r.Topics {
+		cost := float64(r.Topics[i].Recommended-r.Topics[i].Partitions) * pricePerHour * hoursPerMonth
+		r.Topics[i].MonthlyCost = &cost
+	}
+	billed := func(partitions int) float64 { return float64(max(partitions-included, 0)) }
+	cost := (billed(r.Recommended) - billed(r.Partitions)) * pricePerHour * hoursPerMonth
+	r.MonthlyCost = &cost
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// consumerParallelism returns the most consumers which any consumer group has on each topic, which are the consumers
+// that are assigned its partitions, read in parallel from the lag of each group.
+func consumerParallelism(cluster []string, parallelism int) (map[string]int, error) {
+	var list []struct {
+		ConsumerGroup string `json:"consumer_group"`
+	}
+	if err := cli.JSON(&list, append([]string{"kafka", "consumer", "group", "list"}, cluster...)...); err != nil {
+		return nil, err
+	}
+
+	consumers := make([]map[string]int, len(list))
+	errs := make([]error, len(list))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for i, g := range list {
+		wg.Add(1)
+		go func(i int, group string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			consumers[i], errs[i] = groupConsumers(cluster, group)
+		}(i, g.ConsumerGroup)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	most := map[string]int{}
+	for _, c := range consumers {
+		for topic, n := range c {
+			most[topic] = max(most[topic], n)
+		}
+	}
+	return most, nil
+}
+
+// groupConsumers returns how many distinct consumers of the group are assigned partitions of each topic.
+func groupConsumers(cluster []string, group string) (map[string]int, error) {
+	var partitions []struct {
+		Topic      string `json:"topic"`
+		ConsumerID string `json:"consumer_id"`
+	}
+	if err := cli.JSON(&partitions, append([]string{"kafka", "consumer", "group", "lag", "list", group}, cluster...)...); err != nil {
+		return nil, fmt.Errorf(`failed to read the consumers of consumer group "%s": %w`, group, err)
+	}
+
+	ids := map[string]map[string]bool{}
+	for _, p := range partitions {
+		if p.ConsumerID == "" {
+			continue
+		}
+		if ids[p.Topic] == nil {
+			ids[p.Topic] = map[string]bool{}
+		}
+		ids[p.Topic][p.ConsumerID] = true
+	}
+
+	consumers := map[string]int{}
+	for topic, c := range ids {
+		consumers[topic] = len(c)
+	}
+	return consumers, nil
+}
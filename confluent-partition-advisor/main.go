@@ -0,0 +1,224 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "advisor",
+		Short: "Recommend how many partitions each topic needs.",
+		Long:  "Query the Metrics API for the peak ingress and egress of each topic of a Kafka cluster, read how many consumers each consumer group has on it, and recommend how many partitions it needs, flagging the topics which have too few or too many, with the change in the monthly cost of partitions on Basic and Standard clusters.",
+		Args:  cobra.NoArgs,
+		RunE:  advise,
+		Example: `confluent partition advisor --cluster lkc-123456
+confluent partition advisor --cluster lkc-123456 --days 30 --partition-ingress 5 --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().Int("days", 7, "Number of days to find the peak hourly throughput of each topic in.")
+	cmd.Flags().Float64("partition-ingress", 10, "Ingress in MB/s which a partition can handle.")
+	cmd.Flags().Float64("partition-egress", 20, "Egress in MB/s which a partition can handle.")
+	cmd.Flags().Float64("headroom", 0.3, "Fraction of each partition's throughput to keep free, such as for spikes.")
+	cmd.Flags().Float64("tolerance", 2, "How many times more partitions than it needs a topic can have before it's flagged as over-partitioned.")
+	cmd.Flags().Float64("partition-price", 0.0015, "Price in USD of a partition-hour on Basic and Standard clusters.")
+	cmd.Flags().Int("included-partitions", 0, "Partitions of the cluster which aren't billed. Defaults to 10 on Basic clusters and 500 on Standard clusters.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Int("parallelism", 8, "How many consumer groups to read at once.")
+	cmd.Flags().String("metrics-api-key", "", "Cloud API key to query the Metrics API with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("metrics-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-partition-advisor")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-partition-advisor"))
+
+	exitcode.Execute(&cmd)
+}
+
+func advise(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	days, err := cmd.Flags().GetInt("days")
+	cobra.CheckErr(err)
+
+	partitionIngress, err := cmd.Flags().GetFloat64("partition-ingress")
+	cobra.CheckErr(err)
+
+	partitionEgress, err := cmd.Flags().GetFloat64("partition-egress")
+	cobra.CheckErr(err)
+
+	headroom, err := cmd.Flags().GetFloat64("headroom")
+	cobra.CheckErr(err)
+
+	tolerance, err := cmd.Flags().GetFloat64("tolerance")
+	cobra.CheckErr(err)
+
+	price, err := cmd.Flags().GetFloat64("partition-price")
+	cobra.CheckErr(err)
+
+	included, err := cmd.Flags().GetInt("included-partitions")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("metrics-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("metrics-api-secret")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if days < 1 {
+		return exitcode.Invalid("--days must be at least 1")
+	}
+	if partitionIngress <= 0 || partitionEgress <= 0 {
+		return exitcode.Invalid("--partition-ingress and --partition-egress must be more than 0")
+	}
+	if headroom < 0 || headroom >= 1 {
+		return exitcode.Invalid("--headroom must be at least 0 and less than 1")
+	}
+	if tolerance < 1 {
+		return exitcode.Invalid("--tolerance must be at least 1")
+	}
+	if price < 0 {
+		return exitcode.Invalid("--partition-price must not be negative")
+	}
+	if included < 0 {
+		return exitcode.Invalid("--included-partitions must not be negative")
+	}
+	if !cmd.Flags().Changed("included-partitions") {
+		included = -1
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if key == "" || secret == "" {
+		return exitcode.Invalid("a Cloud API key is required to query the Metrics API, pass --metrics-api-key and --metrics-api-secret or set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	cluster, err := cli.DescribeKafkaCluster(clusterID, environment)
+	if err != nil {
+		return err
+	}
+	scope := cli.ClusterFlags(cluster.ID, environment)
+
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	q := querier{key: key, secret: secret, client: retry.Client(30 * time.Second)}
+	ingress, err := q.peakThroughput(cluster.ID, "io.confluent.kafka.server/received_bytes", days)
+	if err != nil {
+		return err
+	}
+	egress, err := q.peakThroughput(cluster.ID, "io.confluent.kafka.server/sent_bytes", days)
+	if err != nil {
+		return err
+	}
+	consumers, err := consumerParallelism(scope, parallelism)
+	if err != nil {
+		return err
+	}
+
+	a := advisor{partitionIngress: partitionIngress * 1e6, partitionEgress: partitionEgress * 1e6, headroom: headroom, tolerance: tolerance}
+	r := report{Cluster: cluster.ID, Type: cluster.Type, CKUs: cluster.ClusterSize, Days: days, Topics: []advice{}}
+	for _, t := range listed {
+		if t.IsInternal {
+			continue
+		}
+		r.Topics = append(r.Topics, a.advise(t, ingress[t.Name], egress[t.Name], consumers[t.Name]))
+	}
+	r.price(cluster.Type, price, included)
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		return output.Print(out, format, r, nil)
+	}
+	print(out, r)
+	return nil
+}
+
+func print(w io.Writer, r report) {
+	fmt.Fprintf(w, "Cluster %s (%s), peak hourly throughput of the last %d days:\n\n", r.Cluster, strings.ToLower(r.Type), r.Days)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Topic\tPartitions\tPeak Ingress\tPeak Egress\tConsumers\tRecommended\tAction\tMonthly Cost")
+	counts := map[string]int{}
+	for _, t := range r.Topics {
+		cost := "-"
+		if t.MonthlyCost != nil {
+			cost = formatCost(*t.MonthlyCost)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.1f MB/s\t%.1f MB/s\t%d\t%d\t%s\t%s\n", t.Topic, t.Partitions, t.PeakIngress/1e6, t.PeakEgress/1e6, t.Consumers, t.Recommended, t.Action, cost)
+		counts[t.Action]++
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Under-partitioned topics: %d, over-partitioned: %d, sized right: %d.\n", counts["increase"], counts["decrease"], counts["keep"])
+	if counts["decrease"] > 0 {
+		fmt.Fprintln(w, "Partitions can't be removed from a topic, so an over-partitioned topic has to be recreated with fewer.")
+	}
+	if counts["increase"] > 0 {
+		fmt.Fprintln(w, "Adding partitions to a topic changes which partition each key is produced to, which breaks the order of keyed records.")
+	}
+
+	fmt.Fprintf(w, "Following every recommendation changes the cluster from %d to %d partitions", r.Partitions, r.Recommended)
+	switch {
+	case r.MonthlyCost != nil:
+		fmt.Fprintf(w, ", which changes its monthly cost by %s, with %d partitions included.\n", formatCost(*r.MonthlyCost), r.IncludedPartitions)
+	case r.PartitionLimit > 0:
+		fmt.Fprintf(w, ", out of the %d which its %d CKUs allow. Dedicated clusters don't bill per partition.\n", r.PartitionLimit, r.CKUs)
+	default:
+		fmt.Fprintln(w, ".")
+	}
+}
+
+func formatCost(cost float64) string {
+	if cost == 0 {
+		return "$0.00"
+	}
+	if cost < 0 {
+		return fmt.Sprintf("-$%.2f", -cost)
+	}
+	return fmt.Sprintf("+$%.2f", cost)
+}
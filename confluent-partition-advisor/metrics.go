@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+)
+
+const metricsURL = "https://api.telemetry.confluent.cloud/v2/metrics/cloud/query"
+
+// querier queries the metrics of the topics of Kafka clusters with the Metrics API, which is authenticated with a
+// Cloud API key.
+type querier struct {
+	key    string
+	secret string
+	client *http.Client
+}
+
+type topicPoint struct {
+	Topic     string    `json:"metric.topic"`
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// query returns the points of a metric of the cluster, grouped by topic, following the pages of the response.
+func (q querier) query(cluster, metric, granularity, interval string) ([]topicPoint, error) {
+	return paging.All(func(token string) ([]topicPoint, string, error) {
+		body := map[string]any{
+			"aggregations": []map[string]string{{"metric": metric}},
+			"filter":       map[string]string{"field": "resource.kafka.id", "op": "EQ", "value": cluster},
+			"granularity":  granularity,
+			"intervals":    []string{interval},
+			"group_by":     []string{"metric.topic"},
+			"limit":        1000,
+		}
+		url := paging.WithToken(metricsURL, token)
+
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(q.key, q.secret)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := q.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var page struct {
+			paging.MetricsPage[topicPoint]
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("failed to query the metrics of %s: %s", cluster, res.Status)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse the metrics of %s: %w", cluster, err)
+		}
+
+		return page.Data, page.Next(), nil
+	})
+}
+
+// peakThroughput returns the highest bytes per second of each topic of the cluster in any hour of the last days, of
+// a metric which counts bytes, such as received_bytes.
+func (q querier) peakThroughput(cluster, metric string, days int) (map[string]float64, error) {
+	points, err := q.query(cluster, metric, "PT1H", fmt.Sprintf("now-%dd/now", days))
+	if err != nil {
+		return nil, err
+	}
+
+	peaks := map[string]float64{}
+	for _, p := range points {
+		peaks[p.Topic] = max(peaks[p.Topic], p.Value/time.Hour.Seconds())
+	}
+	return peaks, nil
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// drainTimeout is how long the consumers may take to read the last records once the producers stop.
+const drainTimeout = 30 * time.Second
+
+// A bench produces records to every partition of a topic, and consumes them back, for a duration.
+type bench struct {
+	bootstrap string
+	apiKey    string
+	apiSecret string
+
+	topic       string
+	partitions  []kafka.Partition
+	messageSize int
+	// rate is how many records to produce per second across the partitions, or 0 to produce as fast as possible.
+	rate        int
+	batchSize   int
+	compression int16
+	acks        int16
+	duration    time.Duration
+}
+
+// partitionRun is what the producer and consumer of one partition measured. Every record of a batch is produced with
+// the latency of its batch.
+type partitionRun struct {
+	produced    int64
+	consumed    int64
+	produce     []time.Duration
+	endToEnd    []time.Duration
+	start       int64
+	produceEnd  atomic.Int64
+	consumeDone time.Time
+}
+
+type throughput struct {
+	Records          int64   `json:"records"`
+	Bytes            int64   `json:"bytes"`
+	RecordsPerSecond float64 `json:"records_per_second"`
+	BytesPerSecond   float64 `json:"bytes_per_second"`
+}
+
+// percentiles of a latency, in milliseconds.
+type percentiles struct {
+	P50  float64 `json:"p50_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	P999 float64 `json:"p99_9_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+type result struct {
+	Topic           string      `json:"topic"`
+	Partitions      int         `json:"partitions"`
+	MessageSize     int         `json:"message_size"`
+	Compression     string      `json:"compression"`
+	Seconds         float64     `json:"seconds"`
+	Produced        throughput  `json:"produced"`
+	Consumed        throughput  `json:"consumed"`
+	ProduceLatency  percentiles `json:"produce_latency"`
+	EndToEndLatency percentiles `json:"end_to_end_latency"`
+}
+
+// run runs the benchmark until its duration is up or the context is done, and measures it.
+func (b *bench) run(ctx context.Context) (result, error) {
+	runs := make([]*partitionRun, len(b.partitions))
+	for i := range runs {
+		runs[i] = &partitionRun{}
+		runs[i].produceEnd.Store(-1)
+	}
+
+	// The consumers start from the end of each partition, so that records which were in the topic aren't measured.
+	client, err := b.client()
+	if err != nil {
+		return result{}, err
+	}
+	for i, p := range b.partitions {
+		end, err := client.Offset(b.topic, p, kafka.LatestOffset)
+		if err != nil {
+			client.Close()
+			return result{}, err
+		}
+		runs[i].start = end
+	}
+	client.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	payload := make([]byte, b.messageSize)
+	for i := range payload {
+		// Uppercase letters, like kafka-producer-perf-test's payloads, so that compression has something to do.
+		payload[i] = byte('A' + rand.Intn(26))
+	}
+
+	start := time.Now()
+	deadline := start.Add(b.duration)
+	produceErrs := make([]error, len(b.partitions))
+	consumeErrs := make([]error, len(b.partitions))
+
+	var producers, consumers sync.WaitGroup
+	for i, p := range b.partitions {
+		producers.Add(1)
+		go func(i int, p kafka.Partition) {
+			defer producers.Done()
+			produceErrs[i] = b.producePartition(ctx, p, runs[i], payload, deadline)
+			if produceErrs[i] != nil {
+				cancel()
+			}
+			runs[i].produceEnd.Store(runs[i].start + runs[i].produced)
+		}(i, p)
+
+		consumers.Add(1)
+		go func(i int, p kafka.Partition) {
+			defer consumers.Done()
+			consumeErrs[i] = b.consumePartition(ctx, p, runs[i])
+			if consumeErrs[i] != nil {
+				cancel()
+			}
+		}(i, p)
+	}
+	producers.Wait()
+	produced := time.Now()
+
+	// Once the producers stop, the consumers are given a while to read the last of their records.
+	drain := time.AfterFunc(drainTimeout, cancel)
+	consumers.Wait()
+	drain.Stop()
+
+	if err := errors.Join(append(produceErrs, consumeErrs...)...); err != nil {
+		return result{}, err
+	}
+
+	r := result{Topic: b.topic, Partitions: len(b.partitions), MessageSize: b.messageSize, Compression: kafka.CompressionNames[b.compression]}
+	var produceLatency, endToEndLatency []time.Duration
+	consumed := start
+	for _, run := range runs {
+		r.Produced.Records += run.produced
+		r.Consumed.Records += run.consumed
+		produceLatency = append(produceLatency, run.produce...)
+		endToEndLatency = append(endToEndLatency, run.endToEnd...)
+		if run.consumeDone.After(consumed) {
+			consumed = run.consumeDone
+		}
+	}
+	r.Produced.Bytes = r.Produced.Records * int64(b.messageSize)
+	r.Consumed.Bytes = r.Consumed.Records * int64(b.messageSize)
+	r.Seconds = produced.Sub(start).Seconds()
+	r.Produced.rates(produced.Sub(start))
+	r.Consumed.rates(consumed.Sub(start))
+	r.ProduceLatency = percentilesOf(produceLatency)
+	r.EndToEndLatency = percentilesOf(endToEndLatency)
+	return r, nil
+}
+
+func (b *bench) producePartition(ctx context.Context, p kafka.Partition, run *partitionRun, payload []byte, deadline time.Time) error {
+	client, err := b.client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// With a rate, batches are sent at even intervals, at least 10 times a second, so that the rate holds on average.
+	batchSize := b.batchSize
+	var interval time.Duration
+	if b.rate > 0 {
+		perPartition := float64(b.rate) / float64(len(b.partitions))
+		batchSize = max(1, min(batchSize, int(perPartition/10)))
+		interval = time.Duration(float64(batchSize) / perPartition * float64(time.Second))
+	}
+
+	records := make([]kafka.Record, batchSize)
+	next := time.Now()
+	for time.Now().Before(deadline) && ctx.Err() == nil {
+		if interval > 0 {
+			if wait := time.Until(next); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(wait):
+				}
+			}
+			next = next.Add(interval)
+		}
+
+		now := time.Now().UnixMilli()
+		for i := range records {
+			records[i] = kafka.Record{Timestamp: now, Value: payload}
+		}
+		batch, err := kafka.EncodeBatch(records, b.compression)
+		if err != nil {
+			return err
+		}
+
+		sent := time.Now()
+		if err := client.Produce(b.topic, p, batch, b.acks); err != nil {
+			return err
+		}
+		latency := time.Since(sent)
+		for range records {
+			run.produce = append(run.produce, latency)
+		}
+		run.produced += int64(len(records))
+	}
+	return nil
+}
+
+// consumePartition reads the records of the partition from where the benchmark started, until it has read every record
+// which the producer produced, or the context is done.
+func (b *bench) consumePartition(ctx context.Context, p kafka.Partition, run *partitionRun) error {
+	client, err := b.client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	offset := run.start
+	for ctx.Err() == nil {
+		if end := run.produceEnd.Load(); end >= 0 && offset >= end {
+			break
+		}
+
+		records, next, err := client.Fetch(b.topic, p, offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		now := time.Now()
+		for _, r := range records {
+			run.endToEnd = append(run.endToEnd, now.Sub(time.UnixMilli(r.Timestamp)))
+		}
+		if len(records) > 0 {
+			run.consumed += int64(len(records))
+			run.consumeDone = now
+		}
+		offset = next
+	}
+	return nil
+}
+
+// client returns a new client, which knows the addresses of the partitions' leaders.
+func (b *bench) client() (*kafka.Client, error) {
+	client := kafka.NewClient("confluent-perf-test", b.bootstrap, b.apiKey, b.apiSecret)
+	if _, err := client.Metadata(b.topic); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (t *throughput) rates(elapsed time.Duration) {
+	if elapsed <= 0 {
+		return
+	}
+	t.RecordsPerSecond = float64(t.Records) / elapsed.Seconds()
+	t.BytesPerSecond = float64(t.Bytes) / elapsed.Seconds()
+}
+
+func percentilesOf(latencies []time.Duration) percentiles {
+	if len(latencies) == 0 {
+		return percentiles{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	at := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(latencies)))) - 1
+		return float64(latencies[max(i, 0)]) / float64(time.Millisecond)
+	}
+	return percentiles{P50: at(0.5), P95: at(0.95), P99: at(0.99), P999: at(0.999), Max: at(1)}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "test",
+		Short: "Benchmark producing to and consuming from a Kafka cluster.",
+		Long:  "Produce records to a topic for a duration, at a rate or as fast as possible, while consuming them back, and report the throughput and the percentiles of the produce and end-to-end latencies. Unless --topic and --api-key are passed, a topic and an API key are created for the benchmark, and deleted once it's done.",
+		Args:  cobra.NoArgs,
+		RunE:  test,
+		Example: `confluent perf test --cluster lkc-123456
+confluent perf test --message-size 4096 --rate 5000 --duration 2m --compression gzip
+confluent perf test --topic perf --api-key ABCDEFGHIJKLMNOP --acks 1 --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster. Defaults to a new API key, which is deleted once the benchmark is done.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().String("topic", "", "Topic to benchmark. Defaults to a new topic, which is deleted once the benchmark is done.")
+	cmd.Flags().Int("partitions", 6, "How many partitions the new topic has.")
+	cmd.Flags().Int("message-size", 1024, "Size of the records' values, in bytes.")
+	cmd.Flags().Int("rate", 0, "How many records to produce per second. Defaults to as many as possible.")
+	cmd.Flags().Int("batch-size", 100, "How many records to produce in each request.")
+	cmd.Flags().String("compression", "none", "Compression of the records: none or gzip.")
+	cmd.Flags().String("acks", "all", "Acks which producing waits for: all or 1.")
+	cmd.Flags().Duration("duration", 30*time.Second, "How long to produce records for.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-perf-test")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-perf-test"))
+
+	exitcode.Execute(&cmd)
+}
+
+func test(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	topic, err := cmd.Flags().GetString("topic")
+	cobra.CheckErr(err)
+
+	partitions, err := cmd.Flags().GetInt("partitions")
+	cobra.CheckErr(err)
+
+	messageSize, err := cmd.Flags().GetInt("message-size")
+	cobra.CheckErr(err)
+
+	rate, err := cmd.Flags().GetInt("rate")
+	cobra.CheckErr(err)
+
+	batchSize, err := cmd.Flags().GetInt("batch-size")
+	cobra.CheckErr(err)
+
+	compression, err := cmd.Flags().GetString("compression")
+	cobra.CheckErr(err)
+
+	acks, err := cmd.Flags().GetString("acks")
+	cobra.CheckErr(err)
+
+	duration, err := cmd.Flags().GetDuration("duration")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	b := &bench{messageSize: messageSize, rate: rate, batchSize: batchSize, duration: duration}
+	switch compression {
+	case "none":
+	case "gzip":
+		b.compression = kafka.CompressionGzip
+	default:
+		return fmt.Errorf(`unsupported compression "%s", supported compressions: none, gzip`, compression)
+	}
+	switch acks {
+	case "all", "-1":
+		b.acks = -1
+	case "1":
+		b.acks = 1
+	default:
+		return fmt.Errorf(`unsupported acks "%s", supported acks: all, 1`, acks)
+	}
+	if partitions < 1 {
+		return exitcode.Invalid("--partitions must be at least 1")
+	}
+	if messageSize < 0 {
+		return exitcode.Invalid("--message-size must not be negative")
+	}
+	if rate < 0 {
+		return exitcode.Invalid("--rate must not be negative")
+	}
+	if batchSize < 1 {
+		return exitcode.Invalid("--batch-size must be at least 1")
+	}
+	if duration <= 0 {
+		return exitcode.Invalid("--duration must be positive")
+	}
+	if apiKey != "" && apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+		if apiSecret == "" {
+			return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required with --api-key")
+		}
+	}
+
+	s := &setup{environment: environment, scope: cli.ClusterFlags(clusterID, environment), apiKey: apiKey, apiSecret: apiSecret, topic: topic}
+	if s.cluster, err = cli.DescribeKafkaCluster(clusterID, environment); err != nil {
+		return err
+	}
+	if bootstrap == "" {
+		bootstrap = s.cluster.Endpoint
+	}
+
+	// The topic and key are deleted even if the benchmark fails or is interrupted, so that it leaves nothing behind. Ctrl-C
+	// is handled from here on, so that it stops the setup as well as the benchmark, rather than killing the plugin with
+	// the key or topic already created.
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	stderr := cmd.ErrOrStderr()
+	defer func() {
+		for _, err := range s.cleanUp() {
+			fmt.Fprintf(stderr, "Warning: %v\n", err)
+		}
+	}()
+	if s.apiKey == "" {
+		if err := s.createKey(); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Created API key \"%s\".\n", s.apiKey)
+	}
+	if s.topic == "" {
+		if err := s.createTopic(partitions); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Created topic \"%s\" with %d partitions.\n", s.topic, partitions)
+	}
+
+	b.bootstrap, b.apiKey, b.apiSecret, b.topic = bootstrap, s.apiKey, s.apiSecret, s.topic
+	if b.partitions, err = s.partitions(ctx, bootstrap); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stderr, "Producing to topic \"%s\" for %s.\n", b.topic, duration)
+	r, err := b.run(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, r, func() { print(out, r) }); err != nil {
+		return err
+	}
+	if r.Consumed.Records < r.Produced.Records {
+		return errors.New("not every record which was produced was consumed")
+	}
+	return nil
+}
+
+func print(w io.Writer, r result) {
+	fmt.Fprintf(w, "Topic \"%s\" with %d partitions, %d byte records, compression %s, for %.1fs:\n\n", r.Topic, r.Partitions, r.MessageSize, r.Compression, r.Seconds)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tRecords\tRecords/s\tMB/s")
+	fmt.Fprintf(tw, "Produced\t%d\t%.1f\t%.2f\n", r.Produced.Records, r.Produced.RecordsPerSecond, r.Produced.BytesPerSecond/1e6)
+	fmt.Fprintf(tw, "Consumed\t%d\t%.1f\t%.2f\n", r.Consumed.Records, r.Consumed.RecordsPerSecond, r.Consumed.BytesPerSecond/1e6)
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Latency (ms)\tp50\tp95\tp99\tp99.9\tMax")
+	for _, l := range []struct {
+		name string
+		p    percentiles
+	}{{"Produce", r.ProduceLatency}, {"End to end", r.EndToEndLatency}} {
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\n", l.name, l.p.P50, l.p.P95, l.p.P99, l.p.P999, l.p.Max)
+	}
+	_ = tw.Flush()
+}
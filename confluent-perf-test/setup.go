@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// setupRetries is how many times reading the topic is retried when its API key or the topic itself is new, since both
+// take a while to be usable.
+const setupRetries = 12
+
+// A setup is the API key and topic of a benchmark, and which of them it created, and so deletes once it's done.
+type setup struct {
+	cluster     cli.KafkaCluster
+	environment string
+	scope       []string
+
+	apiKey    string
+	apiSecret string
+	topic     string
+	newKey    bool
+	newTopic  bool
+}
+
+func (s *setup) createKey() error {
+	args := []string{"api-key", "create", "--resource", s.cluster.ID, "--description", "confluent perf test"}
+	if s.environment != "" {
+		args = append(args, "--environment", s.environment)
+	}
+
+	var key cli.CreatedAPIKey
+	if err := cli.JSON(&key, args...); err != nil {
+		return err
+	}
+	if key.Key == "" || key.Secret == "" {
+		return fmt.Errorf("confluent api-key create didn't return a key and secret")
+	}
+	s.apiKey, s.apiSecret, s.newKey = key.Key, key.Secret, true
+	return nil
+}
+
+func (s *setup) createTopic(partitions int) error {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	topic := "perf-test-" + hex.EncodeToString(suffix)
+
+	if _, err := cli.Run(append([]string{"kafka", "topic", "create", topic, "--partitions", fmt.Sprint(partitions)}, s.scope...)...); err != nil {
+		return err
+	}
+	s.topic, s.newTopic = topic, true
+	return nil
+}
+
+// partitions returns the partitions of the topic, once every one of them has a leader. A connection which failed to
+// authenticate is closed, so each attempt has its own client. Waiting between attempts stops when ctx is done.
+func (s *setup) partitions(ctx context.Context, bootstrap string) ([]kafka.Partition, error) {
+	for i := 0; ; i++ {
+		client := kafka.NewClient("confluent-perf-test", bootstrap, s.apiKey, s.apiSecret)
+		partitions, err := client.Metadata(s.topic)
+		client.Close()
+		if err == nil {
+			for _, p := range partitions {
+				if p.Leader < 0 {
+					err = fmt.Errorf(`partition %d of topic "%s" has no leader`, p.ID, s.topic)
+				}
+			}
+		}
+		if err == nil && len(partitions) == 0 {
+			err = fmt.Errorf(`topic "%s" has no partitions`, s.topic)
+		}
+		if err == nil || !(s.newKey || s.newTopic) || i == setupRetries {
+			return partitions, err
+		}
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// cleanUp deletes the topic and API key which the setup created, and returns the errors of deleting them.
+func (s *setup) cleanUp() []error {
+	var errs []error
+	if s.newTopic {
+		if _, err := cli.Run(append([]string{"kafka", "topic", "delete", s.topic, "--force"}, s.scope...)...); err != nil {
+			errs = append(errs, fmt.Errorf(`failed to delete topic "%s": %w`, s.topic, err))
+		}
+	}
+	if s.newKey {
+		if _, err := cli.Run("api-key", "delete", s.apiKey, "--force"); err != nil {
+			errs = append(errs, fmt.Errorf(`failed to delete API key "%s": %w`, s.apiKey, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/github"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "bundle [plugin]...",
+		Short: "Install or update every plugin of this repository.",
+		Long:  "Install or update every plugin of this repository, or only the plugins which are passed, from its latest release: the binaries of the Go plugins for this platform, and the scripts of the Python plugins, which run in a virtualenv which this plugin manages, behind a shim for each. Plugins which are up to date are skipped, and every file's SHA-256 checksum is verified before it's installed.",
+		RunE:  bundle,
+		Example: `confluent plugin bundle
+confluent plugin bundle --dir ~/.local/bin
+confluent plugin bundle confluent-flink-quickstart confluent-topic-lint --release v1.4.0`,
+	}
+
+	cmd.Flags().String("dir", "", "Directory to install the Go plugins and the shims of the Python plugins to, which has to be on $PATH. Defaults to the directory of this plugin.")
+	cmd.Flags().String("python", defaultPython(), "Python 3 interpreter to create the virtualenv of the Python plugins with.")
+	cmd.Flags().Bool("skip-python", false, "Only install the Go plugins, such as where Python 3 isn't installed.")
+	cmd.Flags().String("release", "", "Tag of the release to install, such as v1.4.0. Defaults to the latest release.")
+	cmd.Flags().String("repository", plugin.Repository, "GitHub repository whose releases to install from, such as a fork.")
+	cmd.Flags().String("github-api-url", github.DefaultAPIURL, "URL of the GitHub API, such as of GitHub Enterprise Server.")
+	output.AddFlag(&cmd, "Format of the installed plugins")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-plugin-bundle")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-plugin-bundle"))
+
+	exitcode.Execute(&cmd)
+}
+
+func bundle(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	dir, err := cmd.Flags().GetString("dir")
+	cobra.CheckErr(err)
+
+	python, err := cmd.Flags().GetString("python")
+	cobra.CheckErr(err)
+
+	skipPython, err := cmd.Flags().GetBool("skip-python")
+	cobra.CheckErr(err)
+
+	tag, err := cmd.Flags().GetString("release")
+	cobra.CheckErr(err)
+
+	repository, err := cmd.Flags().GetString("repository")
+	cobra.CheckErr(err)
+
+	apiURL, err := cmd.Flags().GetString("github-api-url")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	if dir == "" {
+		if dir, err = defaultDir(); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	g := github.New(apiURL, repository)
+	r, err := g.Release(tag)
+	if err != nil {
+		return err
+	}
+	entries, err := g.Index(r)
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if !slices.ContainsFunc(entries, func(e plugin.Entry) bool { return e.Name == name }) {
+			return exitcode.Invalid(`"%s" isn't a plugin of release %s`, name, r.Tag)
+		}
+	}
+
+	var selected []plugin.Entry
+	for _, e := range entries {
+		if len(args) > 0 && !slices.Contains(args, e.Name) {
+			continue
+		}
+		switch {
+		case e.Runtime.Name == "Go":
+		case e.Runtime.Name == "Python" && !skipPython:
+		default:
+			continue
+		}
+		selected = append(selected, e)
+	}
+
+	checksums, err := g.Checksums(r)
+	if err != nil {
+		return err
+	}
+
+	// The virtualenv is only created once a Python plugin needs to be installed.
+	var env *virtualenv
+	bar := progress.NewBar(cmd.ErrOrStderr(), "Plugins installed", len(selected))
+	var errs []error
+	var installed []string
+	upToDate := 0
+	results := []result{}
+	for _, e := range selected {
+		var from string
+		var ok bool
+		var err error
+		switch e.Runtime.Name {
+		case "Go":
+			from, ok, err = installGo(g, r, checksums, dir, e)
+		case "Python":
+			if env == nil {
+				env, err = newVirtualenv(python)
+			}
+			if err == nil {
+				from, ok, err = env.install(g, r, checksums, dir, e)
+			}
+		}
+		res := result{Name: e.Name, Version: e.Version}
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("failed to install %s: %w", e.Name, err))
+			res.Status, res.Error = "failed", err.Error()
+		case !ok:
+			upToDate++
+			res.Status = "up-to-date"
+		case from == "":
+			installed = append(installed, fmt.Sprintf("Installed %s %s.", e.Name, e.Version))
+			res.Status = "installed"
+		default:
+			installed = append(installed, fmt.Sprintf("Updated %s from %s to %s.", e.Name, from, e.Version))
+			res.Status, res.From = "updated", from
+		}
+		results = append(results, res)
+		bar.Add(1)
+	}
+	bar.Done()
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		return errors.Join(append(errs, output.Print(out, format, results, nil))...)
+	}
+	for _, line := range installed {
+		fmt.Fprintln(out, line)
+	}
+	switch upToDate {
+	case 0:
+	case 1:
+		fmt.Fprintf(out, "1 plugin is up to date with release %s.\n", r.Tag)
+	default:
+		fmt.Fprintf(out, "%d plugins are up to date with release %s.\n", upToDate, r.Tag)
+	}
+	if len(installed) > 0 && !onPath(dir) {
+		fmt.Fprintf(out, "Add %s to $PATH, so that the confluent CLI finds the plugins.\n", dir)
+	}
+	return errors.Join(errs...)
+}
+
+// A result is what was done to a plugin, with --output json or yaml: "installed", "updated", "up-to-date", or "failed".
+type result struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	// From is the version which an update replaced.
+	From  string `json:"from,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// installGo installs the binary of the Go plugin for this platform in the directory, unless it's up to date. It returns
+// the version which it replaced, which is "an unknown version" for a plugin which doesn't print its version, and
+// whether it installed it.
+func installGo(g *github.Client, r github.Release, checksums map[string]string, dir string, e plugin.Entry) (string, bool, error) {
+	path := filepath.Join(dir, platform.Executable(e.Name))
+
+	var from string
+	if _, err := os.Stat(path); err == nil {
+		from = plugin.InstalledVersion(path)
+		if from != "" && !plugin.Newer(e.Version, from) {
+			return "", false, nil
+		}
+		if from == "" {
+			from = "an unknown version"
+		}
+	}
+
+	binary, err := g.DownloadVerified(r, checksums, plugin.Asset(e.Name, runtime.GOOS, runtime.GOARCH))
+	if err != nil {
+		return "", false, err
+	}
+	return from, true, plugin.WriteExecutable(path, binary)
+}
+
+// defaultDir returns the directory of this plugin's binary, which is on $PATH, since the confluent CLI runs it.
+func defaultDir() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
+// onPath returns whether the directory is on $PATH.
+func onPath(dir string) bool {
+	for _, d := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(d) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/github"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+)
+
+// versionsFile is the file of the versions of the Python plugins which are installed, in the directory of the
+// virtualenv, since their scripts don't print their manifest.
+const versionsFile = "versions.json"
+
+// A virtualenv is the Python virtualenv which the Python plugins run in, in ~/.confluent/plugins/python, with their
+// scripts and the requirements which they need, so that they never depend on the packages of the system's Python.
+type virtualenv struct {
+	dir string
+	// python is the interpreter of the virtualenv.
+	python   string
+	versions map[string]string
+}
+
+func defaultPython() string {
+	if runtime.GOOS == "windows" {
+		return "python"
+	}
+	return "python3"
+}
+
+// newVirtualenv returns the virtualenv of the Python plugins, and creates it with the interpreter if it doesn't exist.
+func newVirtualenv(python string) (*virtualenv, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	v := &virtualenv{dir: filepath.Join(home, ".confluent", "plugins", "python"), versions: map[string]string{}}
+	v.python = filepath.Join(v.dir, "bin", "python")
+	if runtime.GOOS == "windows" {
+		v.python = filepath.Join(v.dir, "Scripts", "python.exe")
+	}
+
+	if _, err := os.Stat(v.python); os.IsNotExist(err) {
+		slog.Info("Creating the virtualenv of the Python plugins", "dir", v.dir)
+		if err := run(python, "-m", "venv", v.dir); err != nil {
+			return nil, fmt.Errorf("failed to create the virtualenv of the Python plugins with %s, pass another Python 3 interpreter with --python, or --skip-python: %w", python, err)
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(v.dir, versionsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &v.versions); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(v.dir, versionsFile), err)
+		}
+	}
+	return v, nil
+}
+
+// install installs the script of the Python plugin in the virtualenv, with its requirements, and its shim in the
+// directory, unless it's up to date. It returns the version which it replaced, and whether it installed it.
+func (v *virtualenv) install(g *github.Client, r github.Release, checksums map[string]string, dir string, e plugin.Entry) (string, bool, error) {
+	script := filepath.Join(v.dir, "scripts", plugin.Script(e.Name))
+	shim := filepath.Join(dir, e.Name)
+	if runtime.GOOS == "windows" {
+		shim += ".cmd"
+	}
+
+	from := v.versions[e.Name]
+	if from != "" && !plugin.Newer(e.Version, from) && exists(script) && exists(shim) {
+		return "", false, nil
+	}
+
+	b, err := g.DownloadVerified(r, checksums, plugin.Script(e.Name))
+	if err != nil {
+		return "", false, err
+	}
+	if r.Has(plugin.Requirements(e.Name)) {
+		requirements, err := g.DownloadVerified(r, checksums, plugin.Requirements(e.Name))
+		if err != nil {
+			return "", false, err
+		}
+		path := filepath.Join(v.dir, "requirements", plugin.Requirements(e.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", false, err
+		}
+		if err := os.WriteFile(path, requirements, 0o644); err != nil {
+			return "", false, err
+		}
+		if err := run(v.python, "-m", "pip", "install", "--quiet", "--requirement", path); err != nil {
+			return "", false, fmt.Errorf("failed to install its requirements: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(script), 0o755); err != nil {
+		return "", false, err
+	}
+	if err := plugin.WriteExecutable(script, b); err != nil {
+		return "", false, err
+	}
+	if err := plugin.WriteExecutable(shim, v.shim(script)); err != nil {
+		return "", false, err
+	}
+
+	v.versions[e.Name] = e.Version
+	versions, err := json.MarshalIndent(v.versions, "", "  ")
+	if err != nil {
+		return "", false, err
+	}
+	if err := os.WriteFile(filepath.Join(v.dir, versionsFile), versions, 0o644); err != nil {
+		return "", false, err
+	}
+	return from, true, nil
+}
+
+// shim returns the executable which the confluent CLI runs as the plugin, which runs its script with the interpreter of
+// the virtualenv, with the plugin's arguments.
+func (v *virtualenv) shim(script string) []byte {
+	if runtime.GOOS == "windows" {
+		return []byte(fmt.Sprintf("@echo off\r\nrem Installed by confluent plugin bundle.\r\n\"%s\" \"%s\" %%*\r\n", v.python, script))
+	}
+	return []byte(fmt.Sprintf("#!/bin/sh\n# Installed by confluent plugin bundle.\nexec %s %s \"$@\"\n", quote(v.python), quote(script)))
+}
+
+// quote quotes the path for sh, such as a home directory with a space.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// run runs the command, with its output logged with --verbose, and in its error if it fails.
+func run(name string, args ...string) error {
+	slog.Debug("Running", "command", name, "args", args)
+	out, err := exec.Command(name, args...).CombinedOutput()
+	slog.Debug("Ran", "command", name, "output", string(out))
+	if err != nil && len(out) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return err
+}
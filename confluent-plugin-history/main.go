@@ -0,0 +1,156 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "history",
+		Short: "Show what the plugins changed.",
+		Long:  "Show the local audit log of the confluent CLI commands and API requests with which the plugins created, changed, or deleted resources, grouped by the run of the plugin which made them, with whether each succeeded, newest last.",
+		Args:  cobra.NoArgs,
+		RunE:  history,
+		Example: `confluent plugin history
+confluent plugin history --plugin confluent-environment-teardown --since 24h
+confluent plugin history --resource lkc-123456 --failed --output json`,
+	}
+
+	cmd.Flags().String("plugin", "", "Only show the changes of the plugin, such as confluent-topic-purge.")
+	cmd.Flags().String("since", "", "Only show the changes since a time, as a duration such as 24h, or a date such as 2024-05-01.")
+	cmd.Flags().String("resource", "", "Only show the changes of a resource, by its ID or name.")
+	cmd.Flags().String("run", "", "Only show the changes of a run of a plugin, by its ID.")
+	cmd.Flags().Bool("failed", false, "Only show the changes which failed.")
+	cmd.Flags().Int("limit", 100, "Maximum number of changes to show, the most recent ones. 0 shows every change.")
+	output.AddFlag(&cmd, "Format of the changes")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-plugin-history")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-plugin-history"))
+
+	exitcode.Execute(&cmd)
+}
+
+func history(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	name, err := cmd.Flags().GetString("plugin")
+	cobra.CheckErr(err)
+
+	sinceFlag, err := cmd.Flags().GetString("since")
+	cobra.CheckErr(err)
+
+	resource, err := cmd.Flags().GetString("resource")
+	cobra.CheckErr(err)
+
+	run, err := cmd.Flags().GetString("run")
+	cobra.CheckErr(err)
+
+	failed, err := cmd.Flags().GetBool("failed")
+	cobra.CheckErr(err)
+
+	limit, err := cmd.Flags().GetInt("limit")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if limit < 0 {
+		return exitcode.Invalid("--limit must be 0 or more")
+	}
+	since, err := parseSince(sinceFlag, time.Now())
+	if err != nil {
+		return err
+	}
+
+	path, err := audit.Path()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return exitcode.Invalid("the audit log is turned off with $%s", audit.Env)
+	}
+	records, err := audit.Read(path)
+	if err != nil {
+		return err
+	}
+
+	filtered := []audit.Record{}
+	for _, r := range records {
+		switch {
+		case name != "" && r.Plugin != name:
+		case !since.IsZero() && r.Time.Before(since):
+		case resource != "" && !slices.Contains(r.Resources, resource):
+		case run != "" && r.Run != run:
+		case failed && r.Outcome != audit.Failed:
+		default:
+			filtered = append(filtered, r)
+		}
+	}
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[len(filtered)-limit:]
+	}
+
+	out := cmd.OutOrStdout()
+	return output.Print(out, format, filtered, func() { printRecords(out, filtered, path) })
+}
+
+// parseSince parses --since, which is a duration before now, or a date or time, in local time if it has no zone.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, exitcode.Invalid(`invalid --since "%s", expected a duration such as 24h, or a date such as 2024-05-01`, s)
+}
+
+// printRecords prints the records grouped by run, each run with the command of the plugin, and each change with its
+// outcome.
+func printRecords(w io.Writer, records []audit.Record, path string) {
+	if len(records) == 0 {
+		fmt.Fprintf(w, "No changes were found in the audit log, %s.\n", path)
+		return
+	}
+
+	var run string
+	for _, r := range records {
+		if r.Run != run {
+			if run != "" {
+				fmt.Fprintln(w)
+			}
+			run = r.Run
+			fmt.Fprintf(w, "%s  %s  (run %s)\n", r.Time.Local().Format("2006-01-02 15:04:05"), r.Command, r.Run)
+		}
+		line := fmt.Sprintf("  %-9s  %s", r.Outcome, r.Action)
+		if r.Error != "" {
+			line += ": " + strings.ReplaceAll(r.Error, "\n", " ")
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/github"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "update [plugin]...",
+		Short: "Update the installed Go plugins to their latest release.",
+		Long:  "Update the Go plugins of this repository which are installed on $PATH, or only the plugins which are passed, to the binaries of their latest release for this platform, after verifying their SHA-256 checksums. With --check, the installed plugins are listed with their latest versions instead.",
+		RunE:  update,
+		Example: `confluent plugin update --check
+confluent plugin update
+confluent plugin update confluent-topic-lint --release v1.4.0`,
+	}
+
+	cmd.Flags().Bool("check", false, "List the installed plugins and whether they're out of date, without updating them.")
+	cmd.Flags().String("release", "", "Tag of the release to update to, such as v1.4.0. Defaults to the latest release.")
+	cmd.Flags().String("repository", plugin.Repository, "GitHub repository whose releases to update from, such as a fork.")
+	cmd.Flags().String("github-api-url", github.DefaultAPIURL, "URL of the GitHub API, such as of GitHub Enterprise Server.")
+	output.AddFlag(&cmd, "Format of the list of plugins with --check")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-plugin-update")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-plugin-update"))
+
+	exitcode.Execute(&cmd)
+}
+
+// A status is whether an installed plugin is out of date.
+type status struct {
+	Name             string `json:"name"`
+	Path             string `json:"path"`
+	InstalledVersion string `json:"installed_version"`
+	LatestVersion    string `json:"latest_version"`
+	OutOfDate        bool   `json:"out_of_date"`
+}
+
+func update(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	check, err := cmd.Flags().GetBool("check")
+	cobra.CheckErr(err)
+
+	tag, err := cmd.Flags().GetString("release")
+	cobra.CheckErr(err)
+
+	repository, err := cmd.Flags().GetString("repository")
+	cobra.CheckErr(err)
+
+	apiURL, err := cmd.Flags().GetString("github-api-url")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	g := github.New(apiURL, repository)
+	r, err := g.Release(tag)
+	if err != nil {
+		return err
+	}
+	entries, err := g.Index(r)
+	if err != nil {
+		return err
+	}
+
+	// Only the Go plugins have binaries, since the Python plugins are scripts.
+	latest := map[string]string{}
+	for _, e := range entries {
+		if e.Runtime.Name == "Go" {
+			latest[e.Name] = e.Version
+		}
+	}
+	names := map[string]bool{}
+	for _, name := range args {
+		if _, ok := latest[name]; !ok {
+			return fmt.Errorf(`"%s" isn't a Go plugin of release %s`, name, r.Tag)
+		}
+		names[name] = true
+	}
+	if len(args) == 0 {
+		for name := range latest {
+			names[name] = true
+		}
+	}
+
+	plugins := plugin.FindInstalled(names)
+	for _, name := range args {
+		if !slices.ContainsFunc(plugins, func(p plugin.Installed) bool { return p.Name == name }) {
+			return fmt.Errorf(`plugin "%s" isn't installed on $PATH`, name)
+		}
+	}
+
+	statuses := make([]status, len(plugins))
+	var outOfDate []int
+	for i, p := range plugins {
+		statuses[i] = status{Name: p.Name, Path: p.Path, InstalledVersion: p.Version, LatestVersion: latest[p.Name]}
+		// Plugins which don't print their version were built before the manifests, so they're out of date.
+		if p.Version == "" || plugin.Newer(latest[p.Name], p.Version) {
+			statuses[i].OutOfDate = true
+			outOfDate = append(outOfDate, i)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if check {
+		return output.Print(out, format, statuses, func() { printStatuses(out, statuses, len(outOfDate)) })
+	}
+	if len(plugins) == 0 {
+		fmt.Fprintln(out, "No plugins of this repository are installed on $PATH.")
+		return nil
+	}
+	if len(outOfDate) == 0 {
+		fmt.Fprintf(out, "Every plugin is up to date with release %s.\n", r.Tag)
+		return nil
+	}
+
+	checksums, err := g.Checksums(r)
+	if err != nil {
+		return err
+	}
+
+	bar := progress.NewBar(cmd.ErrOrStderr(), "Plugins updated", len(outOfDate))
+	var errs []error
+	var updated []string
+	for _, i := range outOfDate {
+		s := statuses[i]
+		if err := install(g, r, checksums, s); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update %s: %w", s.Name, err))
+		} else {
+			updated = append(updated, fmt.Sprintf("Updated %s from %s to %s.", s.Name, versionOrUnknown(s.InstalledVersion), s.LatestVersion))
+		}
+		bar.Add(1)
+	}
+	bar.Done()
+	for _, line := range updated {
+		fmt.Fprintln(out, line)
+	}
+	return errors.Join(errs...)
+}
+
+// install downloads the plugin's binary for this platform, checks it against its checksum, and replaces the installed
+// binary with it.
+func install(g *github.Client, r github.Release, checksums map[string]string, s status) error {
+	binary, err := g.DownloadVerified(r, checksums, plugin.Asset(s.Name, runtime.GOOS, runtime.GOARCH))
+	if err != nil {
+		return err
+	}
+	return plugin.WriteExecutable(s.Path, binary)
+}
+
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "an unknown version"
+	}
+	return version
+}
+
+func printStatuses(w io.Writer, statuses []status, outOfDate int) {
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No plugins of this repository are installed on $PATH.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Plugin\tInstalled\tLatest\tPath")
+	for _, s := range statuses {
+		current := s.InstalledVersion
+		if current == "" {
+			current = "unknown"
+		}
+		latest := s.LatestVersion
+		if s.OutOfDate {
+			latest += " (out of date)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Name, current, latest, s.Path)
+	}
+	_ = tw.Flush()
+
+	switch outOfDate {
+	case 0:
+		fmt.Fprintln(w, "\nEvery plugin is up to date.")
+	case 1:
+		fmt.Fprintln(w, "\n1 plugin is out of date, update it with: confluent plugin update")
+	default:
+		fmt.Fprintf(w, "\n%d plugins are out of date, update them with: confluent plugin update\n", outOfDate)
+	}
+}
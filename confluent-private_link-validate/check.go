@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	pass = "pass"
+	fail = "fail"
+	skip = "skip"
+)
+
+// A check is the result of one prerequisite of Private Link, with how to fix it if it failed.
+type check struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// A validator checks the prerequisites of connecting to a cluster over Private Link, from the network's side in
+// Confluent Cloud, and then from the client's, in the order which a client depends on them.
+type validator struct {
+	network   network
+	accesses  []access
+	bootstrap string
+	timeout   time.Duration
+	checks    []check
+}
+
+func (v *validator) add(c check) bool {
+	v.checks = append(v.checks, c)
+	return c.Status == pass
+}
+
+func (v *validator) validate() []check {
+	if !v.validateNetwork() {
+		return v.checks
+	}
+
+	host, port, err := net.SplitHostPort(v.bootstrap)
+	if err != nil {
+		host, port = v.bootstrap, "9092"
+	}
+	addr := net.JoinHostPort(host, port)
+	// Clients bootstrap on 9092, and the CLI and REST clients use 443 on the same endpoint.
+	rest := net.JoinHostPort(host, "443")
+
+	if !v.validateDNS(host) {
+		v.add(check{"Port", addr, skip, "the bootstrap server doesn't resolve to the private endpoint", ""})
+		v.add(check{"Port", rest, skip, "the bootstrap server doesn't resolve to the private endpoint", ""})
+		v.add(check{"TLS", addr, skip, "the bootstrap server doesn't resolve to the private endpoint", ""})
+		return v.checks
+	}
+
+	reachable := v.validatePort(addr)
+	v.validatePort(rest)
+	if reachable {
+		v.validateTLS(addr, host)
+	} else {
+		v.add(check{"TLS", addr, skip, "the port isn't reachable", ""})
+	}
+	return v.checks
+}
+
+func (v *validator) validateNetwork() bool {
+	n := v.network
+	if !slices.Contains(n.ConnectionTypes, "PRIVATELINK") {
+		v.add(check{"Network", n.ID, fail, fmt.Sprintf("the network's connection types are %s", strings.Join(n.ConnectionTypes, ", ")), "The network doesn't use Private Link, so its prerequisites don't apply. Pass --network if the cluster is in another network."})
+		return false
+	}
+	if n.Phase != "READY" {
+		v.add(check{"Network", n.ID, fail, fmt.Sprintf("the network is %s", n.Phase), "Wait for the network to be READY. Networks take a while to be provisioned, and a FAILED network must be recreated."})
+		return false
+	}
+	v.add(check{"Network", n.ID, pass, fmt.Sprintf("Private Link network in %s %s", n.Cloud, n.Region), ""})
+
+	var ready, pending []string
+	for _, a := range v.accesses {
+		if a.Phase == "READY" {
+			ready = append(ready, a.account())
+		} else {
+			pending = append(pending, fmt.Sprintf("%s is %s", a.account(), a.Phase))
+		}
+	}
+	switch {
+	case len(ready) > 0:
+		v.add(check{"Access", n.ID, pass, "allows " + strings.Join(ready, ", "), ""})
+	case len(pending) > 0:
+		v.add(check{"Access", n.ID, fail, strings.Join(pending, ", "), "Wait for the Private Link access to be READY, or recreate it if it FAILED."})
+		return false
+	default:
+		v.add(check{"Access", n.ID, fail, "no cloud accounts are allowed to connect", fmt.Sprintf("Allow the client's cloud account to create a %s to the network with \"confluent network private-link access create --network %s\".", cloudName(endpointNames, n.Cloud), n.ID)})
+		return false
+	}
+	return true
+}
+
+// validateDNS checks that the names of the cluster resolve to the private endpoint, and reports whether the bootstrap
+// server's does. Every name under the network's domain, and under each zone's subdomain, must resolve, which wildcard
+// records do, so they're checked with a name which no broker has.
+func (v *validator) validateDNS(host string) bool {
+	n := v.network
+	resolved := v.validateName(host, "", "")
+	if n.DnsDomain != "" {
+		v.validateName("dns-check."+n.DnsDomain, "*."+n.DnsDomain, "")
+	}
+	for _, zone := range n.zones() {
+		v.validateName("dns-check."+n.ZonalSubdomains[zone], "*."+n.ZonalSubdomains[zone], zone)
+	}
+	return resolved
+}
+
+// validateName checks that a name resolves to private addresses. The record is the wildcard which the name is checked
+// for, if any, and its zone the zone it's of, if it's a zonal record, which resolves to the endpoint's address in it.
+func (v *validator) validateName(name, record, recordZone string) bool {
+	n := v.network
+	target := name
+	if record != "" {
+		target = record
+	}
+	zone := cloudName(dnsZoneNames, n.Cloud)
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			addresses := "IP addresses of the " + cloudName(endpointNames, n.Cloud)
+			if recordZone != "" {
+				addresses = fmt.Sprintf("IP address of the %s in zone %s", cloudName(endpointNames, n.Cloud), recordZone)
+			}
+			return v.add(check{"DNS", target, fail, "doesn't resolve", fmt.Sprintf("Create a %s for %s, with a record of %s to the %s.", zone, n.DnsDomain, target, addresses)})
+		}
+		return v.add(check{"DNS", target, fail, err.Error(), "Check that the client's DNS resolver is reachable."})
+	}
+
+	var public []string
+	ips := make([]string, len(addrs))
+	for i, a := range addrs {
+		ips[i] = a.IP.String()
+		if !a.IP.IsPrivate() {
+			public = append(public, a.IP.String())
+		}
+	}
+	if len(public) > 0 {
+		return v.add(check{"DNS", target, fail, "resolves to public addresses " + strings.Join(public, ", "), fmt.Sprintf("Resolve %s with the %s, instead of public DNS. The client must use its network's resolver, such as the VPC's, rather than a public one like 8.8.8.8.", target, zone)})
+	}
+	return v.add(check{"DNS", target, pass, strings.Join(ips, ", "), ""})
+}
+
+func (v *validator) validatePort(addr string) bool {
+	n := v.network
+	_, port, _ := net.SplitHostPort(addr)
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, v.timeout)
+	if err != nil {
+		fix := fmt.Sprintf("Allow inbound TCP on port %s from the client's addresses in the %s.", port, cloudName(firewallNames, n.Cloud))
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			fix = fmt.Sprintf("Check that the %s is in an available state, and that it was created for the network's service.", cloudName(endpointNames, n.Cloud))
+		}
+		return v.add(check{"Port", addr, fail, err.Error(), fix})
+	}
+	_ = conn.Close()
+	return v.add(check{"Port", addr, pass, fmt.Sprintf("connected in %s", time.Since(start).Round(time.Millisecond)), ""})
+}
+
+func (v *validator) validateTLS(addr, host string) {
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: v.timeout}, Config: &tls.Config{ServerName: host}}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		v.add(check{"TLS", addr, fail, err.Error(), "Connect with the cluster's bootstrap server name rather than an IP address, and check that no proxy terminates TLS on the way to the private endpoint."})
+		return
+	}
+	_ = conn.Close()
+	v.add(check{"TLS", addr, pass, "the certificate verifies", ""})
+}
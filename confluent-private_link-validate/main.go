@@ -0,0 +1,141 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errFailed fails the plugin with exitcode.Findings when a prerequisite is missing, as opposed to when the checks can't
+// be run.
+var errFailed = exitcode.New(exitcode.Findings, "prerequisites of Private Link are missing")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "validate",
+		Short: "Validate the Private Link prerequisites of a cluster from the client side.",
+		Long:  "Validate that a cluster in a Private Link or Private Service Connect network can be reached from where the plugin runs: that the network and its Private Link access are ready, that the DNS records of the network's domain and zones resolve to the private endpoint, that the ports are open, and that TLS verifies. Each missing prerequisite is explained, with what to fix. Exits with code 2 if any is missing.",
+		Args:  cobra.NoArgs,
+		RunE:  validate,
+		Example: `confluent private-link validate --cluster lkc-123456
+confluent private-link validate --cluster lkc-123456 --network n-abc123 --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("network", "", "ID of the cluster's network. Defaults to the cluster's.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().Duration("timeout", 10*time.Second, "How long each check may take.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-private_link-validate")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-private_link-validate"))
+
+	exitcode.Execute(&cmd)
+}
+
+func validate(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	networkID, err := cmd.Flags().GetString("network")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return exitcode.Invalid("--timeout must be positive")
+	}
+
+	if bootstrap == "" || networkID == "" {
+		described, err := cli.DescribeKafkaCluster(clusterID, environment)
+		if err != nil {
+			return err
+		}
+		if bootstrap == "" {
+			bootstrap = described.Endpoint
+		}
+		if networkID == "" {
+			networkID = described.Network
+		}
+	}
+	if networkID == "" {
+		return fmt.Errorf("the cluster isn't in a network, since it uses public networking, or pass its network with --network")
+	}
+
+	n, err := describeNetwork(networkID, environment)
+	if err != nil {
+		return err
+	}
+	accesses, err := listAccess(networkID, environment)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{network: n, accesses: accesses, bootstrap: strings.TrimPrefix(bootstrap, "SASL_SSL://"), timeout: timeout}
+	checks := v.validate()
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, checks, func() { print(out, checks) }); err != nil {
+		return err
+	}
+
+	for _, c := range checks {
+		if c.Status == fail {
+			return errFailed
+		}
+	}
+	return nil
+}
+
+func print(w io.Writer, checks []check) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Check\tTarget\tResult\tDetail")
+	var fixes []string
+	for _, c := range checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Check, c.Target, strings.ToUpper(c.Status), c.Detail)
+		if c.Status == fail {
+			fixes = append(fixes, fmt.Sprintf("%s %s: %s", c.Check, c.Target, c.Fix))
+		}
+	}
+	_ = tw.Flush()
+
+	if len(fixes) == 0 {
+		fmt.Fprintln(w, "\nEvery prerequisite of Private Link is in place.")
+		return
+	}
+	fmt.Fprintf(w, "\n%d prerequisites are missing:\n", len(fixes))
+	for i, f := range fixes {
+		fmt.Fprintf(w, "%d. %s\n", i+1, f)
+	}
+}
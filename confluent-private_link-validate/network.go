@@ -0,0 +1,106 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// network is the part of "confluent network describe" which the checks need.
+type network struct {
+	ID              string            `json:"id"`
+	Cloud           string            `json:"cloud"`
+	Region          string            `json:"region"`
+	Phase           string            `json:"phase"`
+	ConnectionTypes []string          `json:"connection_types"`
+	DnsResolution   string            `json:"dns_resolution"`
+	DnsDomain       string            `json:"dns_domain"`
+	ZonalSubdomains map[string]string `json:"zonal_subdomains"`
+}
+
+// access is a Private Link access in the output of "confluent network private-link access list", which allows a
+// cloud account to connect to the network.
+type access struct {
+	ID                string `json:"id"`
+	AwsAccount        string `json:"aws_account"`
+	GcpProject        string `json:"gcp_project"`
+	AzureSubscription string `json:"azure_subscription"`
+	Phase             string `json:"phase"`
+}
+
+func (a access) account() string {
+	switch {
+	case a.AwsAccount != "":
+		return "AWS account " + a.AwsAccount
+	case a.GcpProject != "":
+		return "GCP project " + a.GcpProject
+	case a.AzureSubscription != "":
+		return "Azure subscription " + a.AzureSubscription
+	default:
+		return a.ID
+	}
+}
+
+func describeNetwork(id, environment string) (network, error) {
+	args := []string{"network", "describe", id}
+	if environment != "" {
+		args = append(args, "--environment", environment)
+	}
+	var n network
+	if err := cli.JSON(&n, args...); err != nil {
+		return network{}, err
+	}
+	return n, nil
+}
+
+func listAccess(id, environment string) ([]access, error) {
+	args := []string{"network", "private-link", "access", "list", "--network", id}
+	if environment != "" {
+		args = append(args, "--environment", environment)
+	}
+	var accesses []access
+	if err := cli.JSON(&accesses, args...); err != nil {
+		return nil, err
+	}
+	return accesses, nil
+}
+
+// zones returns the network's zones, sorted, which each have a subdomain of the network's DNS domain.
+func (n network) zones() []string {
+	zones := make([]string, 0, len(n.ZonalSubdomains))
+	for zone := range n.ZonalSubdomains {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// Names of what the client side of Private Link is made of in each cloud, for the explanations of failed checks.
+var (
+	endpointNames = map[string]string{
+		"AWS":   "VPC endpoint",
+		"AZURE": "private endpoint",
+		"GCP":   "Private Service Connect endpoint",
+		"":      "private endpoint",
+	}
+	dnsZoneNames = map[string]string{
+		"AWS":   "Route 53 private hosted zone associated with the VPC",
+		"AZURE": "private DNS zone linked to the VNet",
+		"GCP":   "Cloud DNS private zone visible to the VPC network",
+		"":      "private DNS zone of the client's network",
+	}
+	firewallNames = map[string]string{
+		"AWS":   "security group of the VPC endpoint",
+		"AZURE": "network security group of the private endpoint's subnet",
+		"GCP":   "VPC firewall rules",
+		"":      "firewall rules of the private endpoint",
+	}
+)
+
+func cloudName(names map[string]string, cloud string) string {
+	if name, ok := names[strings.ToUpper(cloud)]; ok {
+		return name
+	}
+	return names[""]
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errNearLimit fails the plugin with exitcode.Findings when a quota's usage reaches the threshold, so that provisioning
+// can be held off.
+var errNearLimit = exitcode.New(exitcode.Findings, "quota usage reaches the threshold")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "report",
+		Short: "Report the usage of service quotas against their limits.",
+		Long:  "Report the usage of the service quotas of every environment, network, Kafka cluster, and account in a Confluent Cloud organization against their limits, from the closest to its limit to the furthest. With --threshold, exits with code 2 if the usage of any quota reaches that percentage of its limit.",
+		Args:  cobra.NoArgs,
+		RunE:  report,
+		Example: `confluent quota report
+confluent quota report --scope environment,kafka_cluster --threshold 80 --output json`,
+	}
+
+	cmd.Flags().StringSlice("scope", scopes, fmt.Sprintf("Scopes of the quotas to report: %s.", strings.Join(scopes, ", ")))
+	cmd.Flags().Float64("threshold", 0, "Exit with code 2 if the usage of any quota reaches this percentage of its limit.")
+	cmd.Flags().Bool("all", false, "Also report the quotas whose usage isn't reported by Confluent Cloud.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-quota-report")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-quota-report"))
+
+	exitcode.Execute(&cmd)
+}
+
+func report(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	reported, err := cmd.Flags().GetStringSlice("scope")
+	cobra.CheckErr(err)
+
+	threshold, err := cmd.Flags().GetFloat64("threshold")
+	cobra.CheckErr(err)
+
+	all, err := cmd.Flags().GetBool("all")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	for _, s := range reported {
+		if !slices.Contains(scopes, s) {
+			return fmt.Errorf(`unsupported scope "%s", supported scopes: %s`, s, strings.Join(scopes, ", "))
+		}
+	}
+	checkThreshold := cmd.Flags().Changed("threshold")
+
+	quotas, err := listQuotas(reported)
+	if err != nil {
+		return err
+	}
+	us := usages(quotas, all)
+
+	var nearLimit []usage
+	if checkThreshold {
+		for _, u := range us {
+			if u.Percent != nil && *u.Percent >= threshold {
+				nearLimit = append(nearLimit, u)
+			}
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if us == nil {
+			us = []usage{}
+		}
+		if err := output.Print(out, format, us, nil); err != nil {
+			return err
+		}
+	} else {
+		print(out, us)
+		if len(nearLimit) > 0 {
+			fmt.Fprintf(out, "\n%d quotas are at %s%% or more of their limits.\n", len(nearLimit), strconv.FormatFloat(threshold, 'f', -1, 64))
+		}
+	}
+
+	if len(nearLimit) > 0 {
+		return errNearLimit
+	}
+	return nil
+}
+
+func print(w io.Writer, us []usage) {
+	if len(us) == 0 {
+		fmt.Fprintln(w, "No quotas have a reported usage.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Quota\tName\tScope\tResource\tUsage\tLimit\tUsed (%)")
+	for _, u := range us {
+		usage, percent := "-", "-"
+		if u.Usage != nil {
+			usage = strconv.Itoa(*u.Usage)
+		}
+		if u.Percent != nil {
+			percent = strconv.FormatFloat(*u.Percent, 'f', 1, 64)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", u.Quota, u.Name, u.Scope, u.Resource, usage, u.Limit, percent)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// scopes are the scopes of the quotas which "confluent service-quota list" takes.
+var scopes = []string{"organization", "environment", "network", "kafka_cluster", "service_account", "user_account"}
+
+// quota is an applied quota in the output of "confluent service-quota list". The usage is only reported for some quotas.
+type quota struct {
+	ID           string `json:"id"`
+	DisplayName  string `json:"display_name"`
+	Scope        string `json:"scope"`
+	AppliedLimit int    `json:"applied_limit"`
+	Usage        *int   `json:"usage"`
+	Organization string `json:"organization"`
+	Environment  string `json:"environment"`
+	Network      string `json:"network"`
+	KafkaCluster string `json:"kafka_cluster"`
+	User         string `json:"user"`
+}
+
+// usage is a quota's usage of its limit, in the resource which it applies to.
+type usage struct {
+	Quota    string   `json:"quota"`
+	Name     string   `json:"name"`
+	Scope    string   `json:"scope"`
+	Resource string   `json:"resource"`
+	Usage    *int     `json:"usage"`
+	Limit    int      `json:"limit"`
+	Percent  *float64 `json:"percent"`
+}
+
+// resource returns the ID of the most specific resource which the quota applies to.
+func (q quota) resource() string {
+	for _, id := range []string{q.KafkaCluster, q.Network, q.User, q.Environment, q.Organization} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func listQuotas(scopes []string) ([]quota, error) {
+	var quotas []quota
+	for _, scope := range scopes {
+		var list []quota
+		if err := cli.JSON(&list, "service-quota", "list", scope); err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, list...)
+	}
+	return quotas, nil
+}
+
+// usages returns the usage of each quota, from the closest to its limit to the furthest. Quotas whose usage isn't
+// reported are left out, unless all is set, in which case they're last.
+func usages(quotas []quota, all bool) []usage {
+	var us []usage
+	for _, q := range quotas {
+		if q.Usage == nil && !all {
+			continue
+		}
+		u := usage{Quota: q.ID, Name: q.DisplayName, Scope: q.Scope, Resource: q.resource(), Usage: q.Usage, Limit: q.AppliedLimit}
+		if q.Usage != nil && q.AppliedLimit > 0 {
+			percent := float64(*q.Usage) / float64(q.AppliedLimit) * 100
+			u.Percent = &percent
+		}
+		us = append(us, u)
+	}
+
+	sort.SliceStable(us, func(i, j int) bool {
+		if (us[i].Percent == nil) != (us[j].Percent == nil) {
+			return us[i].Percent != nil
+		}
+		if us[i].Percent != nil && *us[i].Percent != *us[j].Percent {
+			return *us[i].Percent > *us[j].Percent
+		}
+		if us[i].Quota != us[j].Quota {
+			return us[i].Quota < us[j].Quota
+		}
+		return us[i].Resource < us[j].Resource
+	})
+	return us
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "apply <spec>",
+		Short:   "Reconcile role bindings with a spec.",
+		Long:    "Reconcile the role bindings of the principals in a YAML spec of principals, roles, and scopes: create the bindings which are missing, and with --prune, delete the principals' bindings which aren't in the spec. The changes are printed as a diff.",
+		Args:    cobra.ExactArgs(1),
+		RunE:    apply,
+		Example: "confluent rbac apply role-bindings.yaml --prune --dry-run",
+	}
+
+	dryrun.AddFlag(&cmd, "Print the changes without changing any role bindings.")
+	cmd.Flags().Bool("prune", false, "Delete role bindings of the principals in the spec which aren't in it.")
+	cmd.Flags().Int("parallelism", 8, "How many principals to list the role bindings of at once.")
+	output.AddFlag(&cmd, "Format of the changes")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-rbac-apply")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-rbac-apply"))
+
+	exitcode.Execute(&cmd)
+}
+
+func apply(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	prune, err := cmd.Flags().GetBool("prune")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	wanted, err := readSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	var principals []string
+	inSpec := map[binding]bool{}
+	for _, b := range wanted {
+		if len(principals) == 0 || principals[len(principals)-1] != b.principal {
+			principals = append(principals, b.principal)
+		}
+		inSpec[b] = true
+	}
+
+	existing, err := listBindings(principals, parallelism, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	var create, remove []binding
+	for _, b := range wanted {
+		if !existing[b] {
+			create = append(create, b)
+		}
+	}
+	if prune {
+		for b := range existing {
+			if !inSpec[b] {
+				remove = append(remove, b)
+			}
+		}
+		sort.Slice(remove, func(i, j int) bool { return remove[i].String() < remove[j].String() })
+	}
+
+	out := output.Progress(cmd)
+	if len(create) == 0 && len(remove) == 0 {
+		fmt.Fprintln(out, "The role bindings match the spec.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, b := range create {
+			plan.Add("create", "role binding", b.String())
+		}
+		for _, b := range remove {
+			plan.Add("delete", "role binding", b.String())
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	var applied dryrun.Plan
+	for _, b := range create {
+		fmt.Fprintf(out, "+ %s\n", b)
+		if _, err := cli.Run(append([]string{"iam", "rbac", "role-binding", "create"}, b.args()...)...); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, applied))
+		}
+		applied.Add("create", "role binding", b.String())
+	}
+	for _, b := range remove {
+		fmt.Fprintf(out, "- %s\n", b)
+		if _, err := cli.Run(append([]string{"iam", "rbac", "role-binding", "delete", "--force"}, b.args()...)...); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, applied))
+		}
+		applied.Add("delete", "role binding", b.String())
+	}
+
+	fmt.Fprintf(out, "Created %d role bindings and deleted %d.\n", len(create), len(remove))
+	return dryrun.PrintApplied(cmd, applied)
+}
+
+// listBindings lists the role bindings of the principals at every scope, in parallel, with a bar on w.
+func listBindings(principals []string, parallelism int, w io.Writer) (map[binding]bool, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sem      = make(chan struct{}, parallelism)
+		errs     = make([]error, len(principals))
+		bindings = map[binding]bool{}
+		bar      = progress.NewBar(w, "Principals listed", len(principals))
+	)
+	defer bar.Done()
+	for i, principal := range principals {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, principal string) {
+			defer func() { <-sem; bar.Add(1); wg.Done() }()
+
+			var listed []cli.RoleBinding
+			if err := cli.JSON(&listed, "iam", "rbac", "role-binding", "list", "--principal", principal, "--inclusive"); err != nil {
+				errs[i] = fmt.Errorf(`failed to list the role bindings of "%s": %w`, principal, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, b := range listed {
+				if b.Principal == "" {
+					b.Principal = principal
+				}
+				bindings[listedBinding(b)] = true
+			}
+		}(i, principal)
+	}
+	wg.Wait()
+
+	return bindings, errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"gopkg.in/yaml.v3"
+)
+
+// A spec maps principals to their roles, and each role to the scopes it's bound at, e.g.:
+//
+//	principals:
+//	  User:sa-123456:
+//	    DeveloperRead:
+//	      - environment: env-123456
+//	        cloud_cluster: lkc-123456
+//	        resource: Topic:orders
+//	        prefix: true
+//	    MetricsViewer:
+//	      - {}
+//
+// An empty scope is the organization.
+type spec struct {
+	Principals map[string]map[string][]scope `yaml:"principals"`
+}
+
+type scope struct {
+	Environment           string `yaml:"environment"`
+	CloudCluster          string `yaml:"cloud_cluster"`
+	KafkaCluster          string `yaml:"kafka_cluster"`
+	SchemaRegistryCluster string `yaml:"schema_registry_cluster"`
+	Resource              string `yaml:"resource"`
+	Prefix                bool   `yaml:"prefix"`
+}
+
+// binding is a role binding, normalized so that bindings from the spec and the organization compare equal.
+type binding struct {
+	principal      string
+	role           string
+	environment    string
+	cloudCluster   string
+	logicalCluster string
+	resource       string
+	prefix         bool
+}
+
+// listedBinding returns the binding of a role binding which the confluent CLI listed.
+func listedBinding(b cli.RoleBinding) binding {
+	n := binding{
+		principal:      b.Principal,
+		role:           b.Role,
+		environment:    b.Environment,
+		cloudCluster:   b.CloudCluster,
+		logicalCluster: b.LogicalCluster,
+		prefix:         strings.EqualFold(b.PatternType, "PREFIXED"),
+	}
+	if b.ResourceType != "" {
+		n.resource = b.ResourceType + ":" + b.Name
+	}
+	return n.normalize()
+}
+
+// Bindings to resources in a Kafka cluster list it as both the cloud and logical cluster.
+func (b binding) normalize() binding {
+	if b.logicalCluster == b.cloudCluster {
+		b.logicalCluster = ""
+	}
+	return b
+}
+
+func (b binding) String() string {
+	parts := []string{"organization"}
+	if b.environment != "" {
+		parts = []string{b.environment}
+	}
+	for _, p := range []string{b.cloudCluster, b.logicalCluster} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if b.resource != "" {
+		resource := b.resource
+		if b.prefix {
+			resource += "*"
+		}
+		parts = append(parts, resource)
+	}
+	return fmt.Sprintf("%s %s %s", b.principal, b.role, strings.Join(parts, "/"))
+}
+
+// args returns the flags of "confluent iam rbac role-binding create" or "delete" for the binding.
+func (b binding) args() []string {
+	args := []string{"--principal", b.principal, "--role", b.role}
+	if b.environment != "" {
+		args = append(args, "--environment", b.environment)
+	}
+	if b.cloudCluster != "" {
+		args = append(args, "--cloud-cluster", b.cloudCluster)
+	}
+
+	logical := b.logicalCluster
+	if logical == "" && b.resource != "" && strings.HasPrefix(b.cloudCluster, "lkc-") {
+		logical = b.cloudCluster
+	}
+	if strings.HasPrefix(logical, "lsrc-") {
+		args = append(args, "--schema-registry-cluster", logical)
+	} else if logical != "" {
+		args = append(args, "--kafka-cluster", logical)
+	}
+
+	if b.resource != "" {
+		args = append(args, "--resource", b.resource)
+		if b.prefix {
+			args = append(args, "--prefix")
+		}
+	}
+	return args
+}
+
+func readSpec(path string) ([]binding, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the spec: %w", err)
+	}
+
+	var s spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf(`failed to parse the spec "%s": %w`, path, err)
+	}
+
+	var bindings []binding
+	for principal, roles := range s.Principals {
+		if !strings.Contains(principal, ":") {
+			return nil, fmt.Errorf(`invalid principal "%s", expected e.g. "User:sa-123456"`, principal)
+		}
+		for role, scopes := range roles {
+			for _, sc := range scopes {
+				if sc.KafkaCluster != "" && sc.SchemaRegistryCluster != "" {
+					return nil, fmt.Errorf("a scope of %s %s sets both kafka_cluster and schema_registry_cluster", principal, role)
+				}
+				if sc.Resource != "" && !strings.Contains(sc.Resource, ":") {
+					return nil, fmt.Errorf(`invalid resource "%s" of %s %s, expected e.g. "Topic:orders"`, sc.Resource, principal, role)
+				}
+				bindings = append(bindings, binding{
+					principal:      principal,
+					role:           role,
+					environment:    sc.Environment,
+					cloudCluster:   sc.CloudCluster,
+					logicalCluster: sc.KafkaCluster + sc.SchemaRegistryCluster,
+					resource:       sc.Resource,
+					prefix:         sc.Prefix,
+				}.normalize())
+			}
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].String() < bindings[j].String() })
+	return bindings, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// overBroadRoles grant control of a whole organization or environment, and should be rare.
+var overBroadRoles = map[string]bool{
+	"OrganizationAdmin": true,
+	"EnvironmentAdmin":  true,
+	"AccountAdmin":      true,
+}
+
+// scope describes the resource which the role is bound to, from the organization down to a single resource.
+func scope(b cli.RoleBinding) string {
+	parts := []string{"organization"}
+	if b.Environment != "" {
+		parts = []string{b.Environment}
+	}
+	if b.CloudCluster != "" {
+		parts = append(parts, b.CloudCluster)
+	}
+	if b.LogicalCluster != "" && b.LogicalCluster != b.CloudCluster {
+		parts = append(parts, b.LogicalCluster)
+	}
+	if b.ResourceType != "" {
+		resource := b.ResourceType + ":" + b.Name
+		if strings.EqualFold(b.PatternType, "PREFIXED") {
+			resource += "*"
+		}
+		parts = append(parts, resource)
+	}
+	return strings.Join(parts, "/")
+}
+
+type role struct {
+	Name string `json:"name"`
+}
+
+type user struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+type serviceAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// listBindings lists the bindings of every role, at every scope of the organization, in parallel.
+func listBindings(parallelism int) ([]cli.RoleBinding, error) {
+	var roles []role
+	if err := cli.JSON(&roles, "iam", "rbac", "role", "list"); err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sem      = make(chan struct{}, parallelism)
+		errs     = make([]error, len(roles))
+		bindings []cli.RoleBinding
+		seen     = map[cli.RoleBinding]bool{}
+	)
+	for i, r := range roles {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() { <-sem; wg.Done() }()
+
+			var listed []cli.RoleBinding
+			if err := cli.JSON(&listed, "iam", "rbac", "role-binding", "list", "--role", name, "--inclusive"); err != nil {
+				errs[i] = fmt.Errorf(`failed to list the bindings of role "%s": %w`, name, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, b := range listed {
+				if b.Role == "" {
+					b.Role = name
+				}
+				if !seen[b] {
+					seen[b] = true
+					bindings = append(bindings, b)
+				}
+			}
+		}(i, r.Name)
+	}
+	wg.Wait()
+
+	return bindings, errors.Join(errs...)
+}
+
+// A principal's bindings, with a description of the principal and whether it still exists.
+type principalReport struct {
+	Principal   string          `json:"principal"`
+	Description string          `json:"description,omitempty"`
+	Deleted     bool            `json:"deleted"`
+	Bindings    []bindingReport `json:"bindings"`
+}
+
+type bindingReport struct {
+	Role      string `json:"role"`
+	Scope     string `json:"scope"`
+	OverBroad bool   `json:"over_broad"`
+}
+
+type report struct {
+	Principals               []principalReport `json:"principals"`
+	OverBroad                int               `json:"over_broad"`
+	DeletedPrincipals        int               `json:"deleted_principals"`
+	DeletedPrincipalBindings int               `json:"deleted_principal_bindings"`
+}
+
+func (r report) findings() bool {
+	return r.OverBroad > 0 || r.DeletedPrincipals > 0
+}
+
+// audit groups the bindings by principal and resource. Principals of users and service accounts which aren't in the
+// organization anymore are marked as deleted. Other principals, such as identity pools and groups, aren't checked.
+func audit(bindings []cli.RoleBinding, users []user, serviceAccounts []serviceAccount) report {
+	descriptions := map[string]string{}
+	for _, u := range users {
+		descriptions["User:"+u.ID] = u.Email
+	}
+	for _, sa := range serviceAccounts {
+		descriptions["User:"+sa.ID] = sa.Name
+	}
+
+	byPrincipal := map[string][]bindingReport{}
+	for _, b := range bindings {
+		byPrincipal[b.Principal] = append(byPrincipal[b.Principal], bindingReport{Role: b.Role, Scope: scope(b), OverBroad: overBroadRoles[b.Role]})
+	}
+
+	var r report
+	for principal, bindings := range byPrincipal {
+		sort.Slice(bindings, func(i, j int) bool {
+			if bindings[i].Scope != bindings[j].Scope {
+				return bindings[i].Scope < bindings[j].Scope
+			}
+			return bindings[i].Role < bindings[j].Role
+		})
+
+		p := principalReport{Principal: principal, Description: descriptions[principal], Bindings: bindings}
+		if _, ok := descriptions[principal]; !ok && (strings.HasPrefix(principal, "User:u-") || strings.HasPrefix(principal, "User:sa-")) {
+			p.Deleted = true
+			r.DeletedPrincipals++
+			r.DeletedPrincipalBindings += len(bindings)
+		}
+		for _, b := range bindings {
+			if b.OverBroad {
+				r.OverBroad++
+			}
+		}
+		r.Principals = append(r.Principals, p)
+	}
+	sort.Slice(r.Principals, func(i, j int) bool { return r.Principals[i].Principal < r.Principals[j].Principal })
+
+	return r
+}
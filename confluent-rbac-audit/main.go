@@ -0,0 +1,144 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errFindings fails the plugin with exitcode.Findings with --fail-on-findings when there are over-broad grants or
+// bindings to deleted accounts.
+var errFindings = exitcode.New(exitcode.Findings, "the audit found over-broad grants or bindings to deleted accounts")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "audit",
+		Short:   "Report every role binding in the organization.",
+		Long:    "Report every role binding in the organization, grouped by principal and resource, and highlight over-broad grants, such as OrganizationAdmin and EnvironmentAdmin, and bindings to users and service accounts which were deleted.",
+		RunE:    auditBindings,
+		Example: "confluent rbac audit --output json > role-bindings.json",
+	}
+
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Bool("findings-only", false, "Only report over-broad grants and bindings to deleted accounts.")
+	cmd.Flags().Bool("fail-on-findings", false, "Exit with code 2 if there are over-broad grants or bindings to deleted accounts.")
+	cmd.Flags().Int("parallelism", 8, "How many roles to list the bindings of at once.")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-rbac-audit")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-rbac-audit"))
+
+	exitcode.Execute(&cmd)
+}
+
+func auditBindings(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	findingsOnly, err := cmd.Flags().GetBool("findings-only")
+	cobra.CheckErr(err)
+
+	failOnFindings, err := cmd.Flags().GetBool("fail-on-findings")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	var users []user
+	if err := cli.JSON(&users, "iam", "user", "list"); err != nil {
+		return err
+	}
+
+	var serviceAccounts []serviceAccount
+	if err := cli.JSON(&serviceAccounts, "iam", "service-account", "list"); err != nil {
+		return err
+	}
+
+	bindings, err := listBindings(parallelism)
+	if err != nil {
+		return err
+	}
+
+	r := audit(bindings, users, serviceAccounts)
+	if findingsOnly {
+		r.Principals = findings(r.Principals)
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, r, func() { r.print(out) }); err != nil {
+		return err
+	}
+
+	if failOnFindings && r.findings() {
+		return errFindings
+	}
+	return nil
+}
+
+// findings filters the report to the bindings of deleted principals, and over-broad bindings.
+func findings(principals []principalReport) []principalReport {
+	var filtered []principalReport
+	for _, p := range principals {
+		if !p.Deleted {
+			var overBroad []bindingReport
+			for _, b := range p.Bindings {
+				if b.OverBroad {
+					overBroad = append(overBroad, b)
+				}
+			}
+			if len(overBroad) == 0 {
+				continue
+			}
+			p.Bindings = overBroad
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+func (r report) print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, p := range r.Principals {
+		name := p.Principal
+		if p.Description != "" {
+			name += " (" + p.Description + ")"
+		}
+		if p.Deleted {
+			name += " [deleted]"
+		}
+		fmt.Fprintln(tw, name)
+
+		for _, b := range p.Bindings {
+			flag := ""
+			if b.OverBroad {
+				flag = "[over-broad]"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", b.Role, b.Scope, flag)
+		}
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%d principals, %d over-broad grants, %d bindings of %d deleted principals.\n", len(r.Principals), r.OverBroad, r.DeletedPrincipalBindings, r.DeletedPrincipals)
+}
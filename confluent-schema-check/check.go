@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// types are the schema types of each file extension.
+var types = map[string]string{
+	".avsc":  "avro",
+	".proto": "protobuf",
+	".json":  "json",
+}
+
+// A result of checking a schema file against its subject.
+type result struct {
+	File       string `json:"file"`
+	Subject    string `json:"subject"`
+	Compatible bool   `json:"compatible"`
+	// NewSubject is set if the subject isn't registered yet, so any schema is compatible.
+	NewSubject bool     `json:"new_subject"`
+	Messages   []string `json:"messages,omitempty"`
+}
+
+// schemaFiles returns the schema files among the paths, and in the directories among them.
+func schemaFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		if err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if _, ok := types[filepath.Ext(p)]; ok && !d.IsDir() {
+				files = append(files, p)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// subjectName returns the subject of a file, by replacing {name} in the format with the file's name without its
+// extension, and {dir} with the name of its directory. Directory names are unescaped, so that the subjects exported
+// by confluent schema export can be checked with "{dir}".
+func subjectName(format, file string) string {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	dir := filepath.Base(filepath.Dir(file))
+	if unescaped, err := url.PathUnescape(dir); err == nil {
+		dir = unescaped
+	}
+	return strings.NewReplacer("{name}", name, "{dir}", dir).Replace(format)
+}
+
+// check validates the file against the compatibility setting of its subject, with the subject's latest version.
+func check(file, subject string, environment []string) (result, error) {
+	r := result{File: file, Subject: subject}
+
+	schemaType, ok := types[filepath.Ext(file)]
+	if !ok {
+		return result{}, fmt.Errorf(`unsupported schema file "%s", supported extensions: .avsc, .proto, .json`, file)
+	}
+
+	args := []string{"schema-registry", "compatibility", "validate", "--subject", subject, "--version", "latest", "--schema", file, "--type", schemaType}
+	var validation struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+	if err := cli.JSON(&validation, append(args, environment...)...); err != nil {
+		// Subjects which aren't registered yet have nothing to be incompatible with.
+		if strings.Contains(err.Error(), "40401") {
+			r.Compatible = true
+			r.NewSubject = true
+			return r, nil
+		}
+		return result{}, fmt.Errorf(`failed to check "%s": %w`, file, err)
+	}
+
+	r.Compatible = validation.IsCompatible
+	r.Messages = validation.Messages
+	return r, nil
+}
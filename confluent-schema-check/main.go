@@ -0,0 +1,121 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errIncompatible fails the plugin with exitcode.Findings when a schema is incompatible with its subject, so that CI
+// fails the build.
+var errIncompatible = exitcode.New(exitcode.Findings, "some schemas are incompatible with their subjects")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "check <file or directory>...",
+		Short: "Check schema files against the compatibility settings of their subjects.",
+		Long:  "Check local .avsc, .proto, and .json schema files against the compatibility settings of the subjects they'll be registered to, such as in a pull request, and exit with code 2 if any are incompatible.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  checkSchemas,
+		Example: `confluent schema check schemas/ --subject-format "{name}-value"
+confluent schema check orders.avsc --subject orders-value --environment env-123456`,
+	}
+
+	cmd.Flags().String("environment", "", "Environment ID of the Schema Registry. Defaults to the current environment.")
+	cmd.Flags().String("subject", "", "Subject to check a single file against.")
+	cmd.Flags().String("subject-format", "{name}", "Subject of each file, where {name} is the file's name without its extension, and {dir} is the name of its directory.")
+	output.AddFlag(&cmd, "Format of the results")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-schema-check")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-schema-check"))
+
+	exitcode.Execute(&cmd)
+}
+
+func checkSchemas(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	subject, err := cmd.Flags().GetString("subject")
+	cobra.CheckErr(err)
+
+	subjectFormat, err := cmd.Flags().GetString("subject-format")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	files, err := schemaFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no schema files were found")
+	}
+	if subject != "" && len(files) > 1 {
+		return exitcode.Invalid("--subject can only be used with a single file, use --subject-format for several")
+	}
+
+	var scope []string
+	if environment != "" {
+		scope = []string{"--environment", environment}
+	}
+
+	results := []result{}
+	incompatible := false
+	for _, file := range files {
+		s := subject
+		if s == "" {
+			s = subjectName(subjectFormat, file)
+		}
+		r, err := check(file, s, scope)
+		if err != nil {
+			return err
+		}
+		results = append(results, r)
+		incompatible = incompatible || !r.Compatible
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, results, func() { print(out, results) }); err != nil {
+		return err
+	}
+
+	if incompatible {
+		return errIncompatible
+	}
+	return nil
+}
+
+func print(w io.Writer, results []result) {
+	for _, r := range results {
+		switch {
+		case r.NewSubject:
+			fmt.Fprintf(w, "NEW   %s: %s isn't registered yet\n", r.File, r.Subject)
+		case r.Compatible:
+			fmt.Fprintf(w, "OK    %s: compatible with %s\n", r.File, r.Subject)
+		default:
+			fmt.Fprintf(w, "FAIL  %s: incompatible with %s\n", r.File, r.Subject)
+			for _, m := range r.Messages {
+				fmt.Fprintf(w, "        %s\n", m)
+			}
+		}
+	}
+}
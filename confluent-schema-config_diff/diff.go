@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The settings which are compared, in the order in which they're reported.
+const (
+	compatibilitySetting      = "compatibility"
+	compatibilityGroupSetting = "compatibility_group"
+	modeSetting               = "mode"
+)
+
+// A side is the Schema Registry of one of the environments, and the settings which were read from it. The settings are
+// keyed by scope: "" for the registry, ":.name:" for a context, or the name of a subject.
+type side struct {
+	name     string
+	registry *registry
+
+	contexts map[string]bool
+	subjects []string
+	configs  map[string]*config
+	modes    map[string]string
+}
+
+// context returns the scope of the subject's context, which is "" for the default context.
+func context(subject string) string {
+	if !strings.HasPrefix(subject, ":.") {
+		return ""
+	}
+	if i := strings.Index(subject[1:], ":"); i >= 0 {
+		return subject[:i+2]
+	}
+	return ""
+}
+
+// unqualified returns the name of the subject without its context.
+func unqualified(subject string) string {
+	return strings.TrimPrefix(subject, context(subject))
+}
+
+// read reads the contexts of the registry, and the compatibility and mode of the registry, its contexts, and the
+// subjects whose names start with the prefix, in parallel.
+func (s *side) read(prefix string, parallelism int) error {
+	contexts, err := s.registry.contexts()
+	if err != nil {
+		return err
+	}
+	s.contexts = map[string]bool{}
+	scopes := []string{""}
+	for _, c := range contexts {
+		s.contexts[c] = true
+		if c != "." {
+			scopes = append(scopes, ":"+c+":")
+		}
+	}
+
+	subjects, err := s.registry.subjects()
+	if err != nil {
+		return err
+	}
+	for _, subject := range subjects {
+		if strings.HasPrefix(unqualified(subject), prefix) {
+			s.subjects = append(s.subjects, subject)
+			scopes = append(scopes, subject)
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, parallelism)
+		errs = make([]error, len(scopes))
+	)
+	s.configs = map[string]*config{}
+	s.modes = map[string]string{}
+	for i, scope := range scopes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, scope string) {
+			defer func() { <-sem; wg.Done() }()
+
+			c, err := s.registry.config(scope)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to read the compatibility of %s: %w", describeScope(scope), err)
+				return
+			}
+			mode, err := s.registry.mode(scope)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to read the mode of %s: %w", describeScope(scope), err)
+				return
+			}
+
+			mu.Lock()
+			s.configs[scope] = c
+			s.modes[scope] = mode
+			mu.Unlock()
+		}(i, scope)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// parents returns the scopes which the scope inherits its settings from, nearest first.
+func parents(scope string) []string {
+	if scope == "" {
+		return nil
+	}
+	if c := context(scope); c != "" && c != scope {
+		return []string{c, ""}
+	}
+	return []string{""}
+}
+
+// setting returns the value of the setting in the scope, which is inherited from its context or the registry if the
+// scope doesn't have its own.
+func (s *side) setting(name, scope string) (string, bool) {
+	for i, sc := range append([]string{scope}, parents(scope)...) {
+		if name == modeSetting {
+			if mode := s.modes[sc]; mode != "" {
+				return mode, i > 0
+			}
+			continue
+		}
+		if c := s.configs[sc]; c != nil {
+			if name == compatibilityGroupSetting {
+				return c.Group, i > 0
+			}
+			return c.Level, i > 0
+		}
+	}
+	return "", scope != ""
+}
+
+// A diff of the settings of two Schema Registries.
+type diff struct {
+	OnlyInSource []string     `json:"contexts_only_in_source"`
+	OnlyInTarget []string     `json:"contexts_only_in_target"`
+	Differences  []difference `json:"differences"`
+}
+
+// A difference is a setting whose value differs between the registries, once inherited values are resolved, since a
+// subject without its own compatibility behaves like one with its context's or registry's. Inherited values are marked
+// as such. The subject is empty for the registry's own settings.
+type difference struct {
+	Setting         string `json:"setting"`
+	Subject         string `json:"subject,omitempty"`
+	Source          string `json:"source"`
+	SourceInherited bool   `json:"source_inherited"`
+	Target          string `json:"target"`
+	TargetInherited bool   `json:"target_inherited"`
+}
+
+func (d diff) empty() bool {
+	return len(d.OnlyInSource) == 0 && len(d.OnlyInTarget) == 0 && len(d.Differences) == 0
+}
+
+func compare(source, target *side) diff {
+	d := diff{OnlyInSource: []string{}, OnlyInTarget: []string{}, Differences: []difference{}}
+
+	scopes := map[string]bool{}
+	for c := range source.contexts {
+		if !target.contexts[c] {
+			d.OnlyInSource = append(d.OnlyInSource, c)
+		}
+	}
+	for c := range target.contexts {
+		if !source.contexts[c] {
+			d.OnlyInTarget = append(d.OnlyInTarget, c)
+		}
+	}
+	sort.Strings(d.OnlyInSource)
+	sort.Strings(d.OnlyInTarget)
+
+	for _, s := range []*side{source, target} {
+		for scope := range s.configs {
+			scopes[scope] = true
+		}
+	}
+	sorted := make([]string, 0, len(scopes))
+	for scope := range scopes {
+		sorted = append(sorted, scope)
+	}
+	sort.Strings(sorted)
+
+	for _, scope := range sorted {
+		for _, name := range []string{compatibilitySetting, compatibilityGroupSetting, modeSetting} {
+			s, sInherited := source.setting(name, scope)
+			t, tInherited := target.setting(name, scope)
+			if !strings.EqualFold(s, t) {
+				d.Differences = append(d.Differences, difference{
+					Setting:         name,
+					Subject:         scope,
+					Source:          s,
+					SourceInherited: sInherited,
+					Target:          t,
+					TargetInherited: tInherited,
+				})
+			}
+		}
+	}
+
+	return d
+}
+
+func describeScope(scope string) string {
+	switch {
+	case scope == "":
+		return "the registry"
+	case context(scope) == scope:
+		return fmt.Sprintf(`context "%s"`, strings.Trim(scope, ":"))
+	default:
+		return fmt.Sprintf(`subject "%s"`, scope)
+	}
+}
+
+func (d diff) print(w io.Writer, source, target *side) {
+	if d.empty() {
+		fmt.Fprintln(w, "No drift.")
+		return
+	}
+
+	if len(d.OnlyInSource) > 0 {
+		fmt.Fprintf(w, "Contexts only in %s:\n", source.name)
+		for _, c := range d.OnlyInSource {
+			fmt.Fprintf(w, "  %s\n", c)
+		}
+	}
+	if len(d.OnlyInTarget) > 0 {
+		fmt.Fprintf(w, "Contexts only in %s:\n", target.name)
+		for _, c := range d.OnlyInTarget {
+			fmt.Fprintf(w, "  %s\n", c)
+		}
+	}
+	if len(d.Differences) > 0 {
+		fmt.Fprintln(w, "Settings:")
+		for _, diff := range d.Differences {
+			scope := diff.Subject
+			if scope == "" {
+				scope = "registry"
+			}
+			fmt.Fprintf(w, "  %s %s: %s -> %s\n", scope, diff.Setting, formatValue(diff.Source, diff.SourceInherited), formatValue(diff.Target, diff.TargetInherited))
+		}
+	}
+}
+
+func formatValue(value string, inherited bool) string {
+	if value == "" {
+		value = "none"
+	}
+	if inherited {
+		return value + " (inherited)"
+	}
+	return value
+}
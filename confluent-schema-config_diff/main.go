@@ -0,0 +1,141 @@
+package main
+
+import (
+	_ "embed"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errDrift fails the plugin with exitcode.Findings when the Schema Registries' settings differ, so that a promotion
+// pipeline can stop on it.
+var errDrift = exitcode.New(exitcode.Findings, "the Schema Registries' settings differ")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "config-diff",
+		Short:   "Compare the settings of two Schema Registries.",
+		Long:    "Compare the compatibility and mode of the Schema Registries of two environments, their contexts, and their subjects, and report the drift between them, such as before promoting schemas from staging to production. Exits with code 2 if there is drift.",
+		Args:    cobra.NoArgs,
+		RunE:    diffConfigs,
+		Example: "confluent schema config-diff --source-environment env-123456 --source-api-key ABCDEFGHIJKLMNOP --source-api-secret ... --target-environment env-654321 --target-api-key QRSTUVWXYZABCDEF --target-api-secret ...",
+	}
+
+	cmd.Flags().String("source-environment", "", "Environment ID of the source. Defaults to the current environment.")
+	cmd.Flags().String("source-api-key", "", "Schema Registry API key of the source.")
+	cmd.Flags().String("source-api-secret", "", "Schema Registry API secret of the source.")
+	cmd.Flags().String("source-schema-registry-endpoint", "", "URL of the source Schema Registry. Defaults to the source environment's.")
+	cmd.Flags().String("target-environment", "", "Environment ID of the target.")
+	cmd.Flags().String("target-api-key", "", "Schema Registry API key of the target.")
+	cmd.Flags().String("target-api-secret", "", "Schema Registry API secret of the target.")
+	cmd.Flags().String("target-schema-registry-endpoint", "", "URL of the target Schema Registry. Defaults to the target environment's.")
+	cmd.Flags().String("prefix", "", "Only compare subjects whose names, without their context, start with this prefix.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Int("parallelism", 8, "How many subjects' settings to read at once.")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("target-environment"))
+
+	completion.Add(&cmd, "confluent-schema-config_diff")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-schema-config_diff"))
+
+	exitcode.Execute(&cmd)
+}
+
+func diffConfigs(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	sourceEnvironment, err := cmd.Flags().GetString("source-environment")
+	cobra.CheckErr(err)
+
+	sourceKey, err := cmd.Flags().GetString("source-api-key")
+	cobra.CheckErr(err)
+
+	sourceSecret, err := cmd.Flags().GetString("source-api-secret")
+	cobra.CheckErr(err)
+
+	sourceEndpoint, err := cmd.Flags().GetString("source-schema-registry-endpoint")
+	cobra.CheckErr(err)
+
+	targetEnvironment, err := cmd.Flags().GetString("target-environment")
+	cobra.CheckErr(err)
+
+	targetKey, err := cmd.Flags().GetString("target-api-key")
+	cobra.CheckErr(err)
+
+	targetSecret, err := cmd.Flags().GetString("target-api-secret")
+	cobra.CheckErr(err)
+
+	targetEndpoint, err := cmd.Flags().GetString("target-schema-registry-endpoint")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	if sourceKey == "" || sourceSecret == "" || targetKey == "" || targetSecret == "" {
+		return exitcode.Invalid("the Schema Registry API keys and secrets of both environments are required, pass --source-api-key, --source-api-secret, --target-api-key, and --target-api-secret")
+	}
+
+	source, err := newSide(sourceEnvironment, sourceEndpoint, sourceKey, sourceSecret)
+	if err != nil {
+		return err
+	}
+	target, err := newSide(targetEnvironment, targetEndpoint, targetKey, targetSecret)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range []*side{source, target} {
+		if err := s.read(prefix, parallelism); err != nil {
+			return err
+		}
+	}
+	d := compare(source, target)
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, d, func() { d.print(out, source, target) }); err != nil {
+		return err
+	}
+
+	if !d.empty() {
+		return errDrift
+	}
+	return nil
+}
+
+func newSide(environment, endpoint, key, secret string) (*side, error) {
+	var environmentFlags []string
+	name := "the current environment"
+	if environment != "" {
+		environmentFlags = []string{"--environment", environment}
+		name = environment
+	}
+
+	if endpoint == "" {
+		var err error
+		endpoint, err = registryEndpoint(environmentFlags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &side{name: name, registry: newRegistry(endpoint, key, secret)}, nil
+}
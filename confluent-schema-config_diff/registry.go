@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// Error codes of the Schema Registry for a subject or context without its own compatibility level or mode.
+const (
+	codeConfigNotFound = 40408
+	codeModeNotFound   = 40409
+)
+
+// registry is a client of the Schema Registry REST API, which is needed to read modes and contexts, since the CLI
+// can't.
+type registry struct {
+	endpoint    string
+	key, secret string
+	client      *http.Client
+}
+
+func newRegistry(endpoint, key, secret string) *registry {
+	return &registry{endpoint: strings.TrimRight(endpoint, "/"), key: key, secret: secret, client: retry.Client(30 * time.Second)}
+}
+
+// registryEndpoint returns the URL of the environment's Schema Registry.
+func registryEndpoint(environment []string) (string, error) {
+	var cluster struct {
+		EndpointURL string `json:"endpoint_url"`
+	}
+	if err := cli.JSON(&cluster, append([]string{"schema-registry", "cluster", "describe"}, environment...)...); err != nil {
+		return "", err
+	}
+	if cluster.EndpointURL == "" {
+		return "", fmt.Errorf("the environment has no Schema Registry")
+	}
+	return cluster.EndpointURL, nil
+}
+
+func (r *registry) get(path string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, r.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(r.key, r.secret)
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&e); err == nil && e.Message != "" {
+			return &registryError{code: e.ErrorCode, message: e.Message}
+		}
+		return &registryError{code: res.StatusCode, message: res.Status}
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type registryError struct {
+	code    int
+	message string
+}
+
+func (e *registryError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.message, e.code)
+}
+
+// A config is the compatibility of the registry, a context, or a subject.
+type config struct {
+	Level string `json:"compatibilityLevel"`
+	Group string `json:"compatibilityGroup"`
+}
+
+// contexts returns the names of the registry's contexts, such as "." for the default context and ".staging".
+func (r *registry) contexts() ([]string, error) {
+	var contexts []string
+	if err := r.get("/contexts", &contexts); err != nil {
+		return nil, err
+	}
+	return contexts, nil
+}
+
+// subjects returns the subjects in every context, qualified with their context unless it's the default one.
+func (r *registry) subjects() ([]string, error) {
+	var subjects []string
+	if err := r.get("/subjects?subjectPrefix="+url.QueryEscape(":*:"), &subjects); err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
+
+// config returns the compatibility of the registry, or with a subject, the subject's or context's own compatibility,
+// which is nil if it has none.
+func (r *registry) config(subject string) (*config, error) {
+	path := "/config"
+	if subject != "" {
+		path += "/" + url.PathEscape(subject) + "?defaultToGlobal=false"
+	}
+
+	var c config
+	if err := r.get(path, &c); err != nil {
+		if e, ok := err.(*registryError); ok && e.code == codeConfigNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// mode returns the mode of the registry, or with a subject, the subject's or context's own mode, which is "" if it has
+// none.
+func (r *registry) mode(subject string) (string, error) {
+	path := "/mode"
+	if subject != "" {
+		path += "/" + url.PathEscape(subject) + "?defaultToGlobal=false"
+	}
+
+	var m struct {
+		Mode string `json:"mode"`
+	}
+	if err := r.get(path, &m); err != nil {
+		if e, ok := err.(*registryError); ok && e.code == codeModeNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return m.Mode, nil
+}
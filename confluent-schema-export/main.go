@@ -0,0 +1,95 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "export",
+		Short: "Export Schema Registry subjects to a directory.",
+		Long:  "Export every version of the Schema Registry subjects, with their schema IDs, references, and compatibility levels, to a directory laid out for source control, which can be applied to another Schema Registry with confluent schema import.",
+		Args:  cobra.NoArgs,
+		RunE:  export,
+		Example: `confluent schema export --environment env-123456 --dir schemas
+confluent schema export --context staging --prefix orders-`,
+	}
+
+	cmd.Flags().String("environment", "", "Environment ID of the Schema Registry. Defaults to the current environment.")
+	cmd.Flags().String("dir", "schemas", "Directory to export the subjects to.")
+	cmd.Flags().String("prefix", "", "Only export subjects whose names start with this prefix.")
+	cmd.Flags().String("context", "", `Only export subjects in this schema context, or "." for the default context. Defaults to every context.`)
+	cmd.Flags().Int("parallelism", 8, "How many subjects to export at once.")
+	output.AddFlag(&cmd, "Format of the list of exported subjects")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-schema-export")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-schema-export"))
+
+	exitcode.Execute(&cmd)
+}
+
+func export(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dir, err := cmd.Flags().GetString("dir")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	context, err := cmd.Flags().GetString("context")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	e := exporter{
+		dir:         dir,
+		prefix:      prefix,
+		context:     context,
+		parallelism: parallelism,
+	}
+	if environment != "" {
+		e.environment = []string{"--environment", environment}
+	}
+
+	// The subjects which were exported are printed even if others failed to.
+	subjects, exportErr := e.export()
+	if subjects == nil {
+		return exportErr
+	}
+
+	if format != output.Table {
+		if err := output.Print(cmd.OutOrStdout(), format, subjects, nil); err != nil {
+			return err
+		}
+	} else if exportErr == nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d subjects to %s.\n", len(subjects), dir)
+	}
+	return exportErr
+}
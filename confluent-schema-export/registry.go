@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// schemaVersion is the output of "confluent schema-registry schema describe".
+type schemaVersion struct {
+	ID         int         `json:"schema_id"`
+	Schema     string      `json:"schema"`
+	Type       string      `json:"type"`
+	References []reference `json:"references"`
+}
+
+type exporter struct {
+	environment []string
+	dir         string
+	prefix      string
+	context     string
+	parallelism int
+}
+
+// subjects lists the qualified names of the subjects in the context, or in every context, which match the prefix.
+func (e exporter) subjects() ([]string, error) {
+	listPrefix := ":*:"
+	switch e.context {
+	case "":
+	case ".":
+		listPrefix = ""
+	default:
+		listPrefix = ":." + e.context + ":"
+	}
+
+	args := []string{"schema-registry", "subject", "list"}
+	if listPrefix != "" {
+		args = append(args, "--prefix", listPrefix)
+	}
+
+	var listed []struct {
+		Subject string `json:"subject"`
+	}
+	if err := cli.JSON(&listed, append(args, e.environment...)...); err != nil {
+		return nil, err
+	}
+
+	var subjects []string
+	for _, s := range listed {
+		context, name := splitContext(s.Subject)
+		if e.context != "" && context != e.context || !strings.HasPrefix(name, e.prefix) {
+			continue
+		}
+		subjects = append(subjects, s.Subject)
+	}
+	return subjects, nil
+}
+
+// An exported subject is where a subject was written to, and how many versions it has.
+type exported struct {
+	Subject  string `json:"subject"`
+	Versions int    `json:"versions"`
+	Dir      string `json:"dir"`
+}
+
+// export reads every version of each subject and writes them to the tree, a subject at a time in parallel, and returns
+// the subjects which were exported.
+func (e exporter) export() ([]exported, error) {
+	subjects, err := e.subjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var global registryConfig
+	global.Compatibility, err = e.compatibility("")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// A full export replaces the tree, so that subjects which were deleted from the registry are deleted from it too.
+	if e.prefix == "" && e.context == "" {
+		for _, d := range []string{"subjects", "contexts"} {
+			if err := os.RemoveAll(filepath.Join(e.dir, d)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := writeYAML(filepath.Join(e.dir, "config.yaml"), global); err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, e.parallelism)
+		errs     = make([]error, len(subjects))
+		versions = make([]int, len(subjects))
+	)
+	for i, name := range subjects {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() { <-sem; wg.Done() }()
+			n, err := e.exportSubject(name, global.Compatibility)
+			if err != nil {
+				errs[i] = fmt.Errorf(`failed to export subject "%s": %w`, name, err)
+			}
+			versions[i] = n
+		}(i, name)
+	}
+	wg.Wait()
+
+	results := []exported{}
+	for i, name := range subjects {
+		if errs[i] == nil {
+			results = append(results, exported{Subject: name, Versions: versions[i], Dir: subjectDir(e.dir, name)})
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// exportSubject writes a subject to the tree, and returns how many versions it has. Its compatibility level is only
+// written if it differs from the registry's, since the CLI may report the registry's level for subjects which don't set
+// their own.
+func (e exporter) exportSubject(name, globalCompatibility string) (int, error) {
+	versions, err := e.versions(name)
+	if err != nil {
+		return 0, err
+	}
+
+	s := subject{Name: name}
+	s.Compatibility, err = e.compatibility(name)
+	if err != nil {
+		return 0, err
+	}
+	if s.Compatibility == globalCompatibility {
+		s.Compatibility = ""
+	}
+
+	schemas := map[int]string{}
+	for _, v := range versions {
+		var sv schemaVersion
+		if err := cli.JSON(&sv, append([]string{"schema-registry", "schema", "describe", "--subject", name, "--version", strconv.Itoa(v)}, e.environment...)...); err != nil {
+			return 0, err
+		}
+
+		schemaType := strings.ToUpper(sv.Type)
+		if schemaType == "" {
+			schemaType = "AVRO"
+		}
+		s.Versions = append(s.Versions, version{Version: v, ID: sv.ID, Type: schemaType, References: sv.References})
+		schemas[v] = sv.Schema
+	}
+
+	return len(versions), writeSubject(e.dir, s, schemas)
+}
+
+// versions returns the versions of a subject, which older versions of the CLI print as a plain list.
+func (e exporter) versions(name string) ([]int, error) {
+	out, err := cli.Run(append([]string{"schema-registry", "subject", "describe", name, "--output", "json"}, e.environment...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	if err := json.Unmarshal(out, &versions); err == nil {
+		return versions, nil
+	}
+	var described struct {
+		Versions []int `json:"versions"`
+	}
+	if err := json.Unmarshal(out, &described); err != nil {
+		return nil, fmt.Errorf("failed to parse the versions: %w", err)
+	}
+	return described.Versions, nil
+}
+
+// compatibility returns the compatibility level of a subject, or of the registry if the subject is "". Subjects which
+// don't set their own level inherit the registry's, and have none.
+func (e exporter) compatibility(name string) (string, error) {
+	args := []string{"schema-registry", "configuration", "describe"}
+	if name != "" {
+		args = append(args, "--subject", name)
+	}
+
+	var config struct {
+		CompatibilityLevel string `json:"compatibility_level"`
+	}
+	if err := cli.JSON(&config, append(args, e.environment...)...); err != nil {
+		if name != "" && strings.Contains(err.Error(), "40408") {
+			return "", nil
+		}
+		return "", err
+	}
+	return config.CompatibilityLevel, nil
+}
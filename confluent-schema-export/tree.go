@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A subject in the exported tree, which is written to the subject.yaml file of its directory, next to a file for each
+// version's schema. This is the format read by confluent schema import.
+type subject struct {
+	Name          string    `yaml:"name"`
+	Compatibility string    `yaml:"compatibility,omitempty"`
+	Versions      []version `yaml:"versions"`
+}
+
+type version struct {
+	Version    int         `yaml:"version"`
+	ID         int         `yaml:"id"`
+	Type       string      `yaml:"type"`
+	File       string      `yaml:"file"`
+	References []reference `yaml:"references,omitempty"`
+}
+
+type reference struct {
+	Name    string `yaml:"name" json:"name"`
+	Subject string `yaml:"subject" json:"subject"`
+	Version int    `yaml:"version" json:"version"`
+}
+
+// registryConfig is written to config.yaml at the root of the tree.
+type registryConfig struct {
+	Compatibility string `yaml:"compatibility,omitempty"`
+}
+
+// extensions are the file extensions of each schema type.
+var extensions = map[string]string{
+	"AVRO":     ".avsc",
+	"JSON":     ".json",
+	"PROTOBUF": ".proto",
+}
+
+// splitContext splits a qualified subject name, such as ":.staging:orders-value", into its context and name. Subjects
+// in the default context have no prefix, and their context is ".".
+func splitContext(qualified string) (string, string) {
+	if strings.HasPrefix(qualified, ":.") {
+		if i := strings.Index(qualified[2:], ":"); i >= 0 {
+			return qualified[2 : 2+i], qualified[3+i:]
+		}
+	}
+	return ".", qualified
+}
+
+// subjectDir returns the directory of a subject in the tree. Subjects in the default context are in subjects/, and
+// others in contexts/<context>/subjects/. Names are escaped, since subjects may contain characters which aren't allowed
+// in file names.
+func subjectDir(root, qualified string) string {
+	context, name := splitContext(qualified)
+	if context == "." {
+		return filepath.Join(root, "subjects", url.PathEscape(name))
+	}
+	return filepath.Join(root, "contexts", url.PathEscape(context), "subjects", url.PathEscape(name))
+}
+
+// writeSubject writes a subject's versions and their schemas to its directory, replacing what was there, so that
+// versions which were deleted from the registry are deleted from the tree too.
+func writeSubject(root string, s subject, schemas map[int]string) error {
+	dir := subjectDir(root, s.Name)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for i, v := range s.Versions {
+		schema := schemas[v.Version]
+		// JSON schemas are indented so that changes to them diff well. They're compacted again when they're imported.
+		if v.Type != "PROTOBUF" {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, []byte(schema), "", "  "); err == nil {
+				schema = buf.String() + "\n"
+			}
+		}
+
+		file := fmt.Sprintf("v%d%s", v.Version, extensions[v.Type])
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(schema), 0644); err != nil {
+			return err
+		}
+		s.Versions[i].File = file
+	}
+
+	return writeYAML(filepath.Join(dir, "subject.yaml"), s)
+}
+
+func writeYAML(path string, v any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := yaml.NewEncoder(f)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+)
+
+type importer struct {
+	environment []string
+	// registry is set when preserving schema IDs and versions, which is done with the REST API in IMPORT mode.
+	registry *registry
+	// plan is what would be imported, with --dry-run, in which case nothing is imported.
+	plan *dryrun.Plan
+	// imported is what was imported, which --output json or yaml prints.
+	imported dryrun.Plan
+	out      io.Writer
+
+	// clones maps the versions of each subject in the tree to the versions they were registered as.
+	clones map[string]map[int]int
+}
+
+// run registers every version of each subject in order, and the versions which others reference before them. References
+// to subjects which aren't in the tree must already be in the registry.
+func (im *importer) run(subjects []subject) error {
+	im.clones = map[string]map[int]int{}
+	inTree := map[string]bool{}
+	pending := map[string][]version{}
+	for _, s := range subjects {
+		inTree[s.Name] = true
+		pending[s.Name] = s.Versions
+	}
+
+	dir, err := os.MkdirTemp("", "confluent-schema-import")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for _, s := range subjects {
+		if err := im.begin(s.Name); err != nil {
+			return err
+		}
+	}
+
+	for len(pending) > 0 {
+		progress := false
+		for _, s := range subjects {
+			for len(pending[s.Name]) > 0 {
+				v := pending[s.Name][0]
+				refs, ok := im.references(v.References, inTree)
+				if !ok {
+					break
+				}
+
+				if err := im.register(dir, s.Name, v, refs); err != nil {
+					return fmt.Errorf(`failed to import version %d of subject "%s": %w`, v.Version, s.Name, err)
+				}
+
+				pending[s.Name] = pending[s.Name][1:]
+				progress = true
+			}
+			if len(pending[s.Name]) == 0 {
+				delete(pending, s.Name)
+			}
+		}
+
+		if !progress {
+			var blocked []string
+			for _, s := range subjects {
+				if vs := pending[s.Name]; len(vs) > 0 {
+					blocked = append(blocked, fmt.Sprintf("%s version %d", s.Name, vs[0].Version))
+				}
+			}
+			return fmt.Errorf("references can't be resolved, since they're missing from the tree or circular: %s", strings.Join(blocked, ", "))
+		}
+	}
+
+	for _, s := range subjects {
+		if err := im.end(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// references returns the references of a version, pointing to the versions they were registered as, if every
+// referenced version in the tree was registered.
+func (im *importer) references(refs []reference, inTree map[string]bool) ([]reference, bool) {
+	resolved := make([]reference, len(refs))
+	for i, r := range refs {
+		resolved[i] = r
+		if !inTree[r.Subject] {
+			continue
+		}
+		v, ok := im.clones[r.Subject][r.Version]
+		if !ok {
+			return nil, false
+		}
+		resolved[i].Version = v
+	}
+	return resolved, true
+}
+
+// begin puts a subject in IMPORT mode when preserving IDs, which also disables compatibility checks.
+func (im *importer) begin(subject string) error {
+	if im.registry == nil || im.plan != nil {
+		return nil
+	}
+	return im.registry.setMode(subject, "IMPORT")
+}
+
+// end reverts the subject's mode, and then sets its compatibility level, so that the history isn't checked against
+// it.
+func (im *importer) end(s subject) error {
+	if im.plan != nil {
+		if s.Compatibility != "" {
+			im.plan.AddDetail("set the compatibility of", "subject", s.Name, "to "+s.Compatibility)
+		}
+		return nil
+	}
+
+	if im.registry != nil {
+		if err := im.registry.setMode(s.Name, ""); err != nil {
+			return err
+		}
+	}
+	if s.Compatibility == "" {
+		return nil
+	}
+	return im.setCompatibility(s.Name, s.Compatibility)
+}
+
+func (im *importer) setCompatibility(subject, level string) error {
+	if im.registry != nil {
+		if err := im.registry.setCompatibility(subject, level); err != nil {
+			return err
+		}
+	} else {
+		args := []string{"schema-registry", "configuration", "update", "--compatibility", strings.ToLower(level)}
+		if subject != "" {
+			args = append(args, "--subject", subject)
+		}
+		if _, err := cli.Run(append(args, im.environment...)...); err != nil {
+			return err
+		}
+	}
+
+	if subject == "" {
+		im.imported.AddDetail("set the compatibility of", "registry", "", "to "+level)
+	} else {
+		im.imported.AddDetail("set the compatibility of", "subject", subject, "to "+level)
+	}
+	return nil
+}
+
+func (im *importer) register(dir, subject string, v version, refs []reference) error {
+	if im.clones[subject] == nil {
+		im.clones[subject] = map[int]int{}
+	}
+
+	if im.registry != nil {
+		im.clones[subject][v.Version] = v.Version
+
+		existing, err := im.registry.versions(subject)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			if e == v.Version {
+				return nil
+			}
+		}
+
+		if im.plan != nil {
+			im.plan.AddDetail("register", "subject", subject, fmt.Sprintf("version %d with ID %d", v.Version, v.ID))
+			return nil
+		}
+		if err := im.registry.register(subject, v); err != nil {
+			return err
+		}
+		fmt.Fprintf(im.out, "Registered %s version %d with ID %d.\n", subject, v.Version, v.ID)
+		im.imported.AddDetail("register", "subject", subject, fmt.Sprintf("version %d with ID %d", v.Version, v.ID))
+		return nil
+	}
+
+	// Without IMPORT mode, subjects are numbered from 1, so the versions which reference them are renumbered too.
+	im.clones[subject][v.Version] = len(im.clones[subject]) + 1
+	if im.plan != nil {
+		im.plan.AddDetail("register", "subject", subject, fmt.Sprintf("version %d", v.Version))
+		return nil
+	}
+
+	schemaFile := filepath.Join(dir, "schema")
+	if err := os.WriteFile(schemaFile, []byte(v.schema), 0600); err != nil {
+		return err
+	}
+	args := []string{"schema-registry", "schema", "create", "--subject", subject, "--schema", schemaFile, "--type", strings.ToLower(v.Type)}
+	if len(refs) > 0 {
+		b, err := json.Marshal(refs)
+		if err != nil {
+			return err
+		}
+		refsFile := filepath.Join(dir, "references.json")
+		if err := os.WriteFile(refsFile, b, 0600); err != nil {
+			return err
+		}
+		args = append(args, "--references", refsFile)
+	}
+	if _, err := cli.Run(append(args, im.environment...)...); err != nil {
+		return err
+	}
+	fmt.Fprintf(im.out, "Registered version %d of %s.\n", v.Version, subject)
+	im.imported.AddDetail("register", "subject", subject, fmt.Sprintf("version %d", v.Version))
+	return nil
+}
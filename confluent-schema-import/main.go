@@ -0,0 +1,142 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "import",
+		Short: "Import an exported schema tree into a Schema Registry.",
+		Long:  "Import a tree exported by confluent schema export into a Schema Registry, registering referenced schemas first. With --preserve-ids, subjects are imported in IMPORT mode, which keeps their schema IDs and version numbers.",
+		Args:  cobra.NoArgs,
+		RunE:  importTree,
+		Example: `confluent schema import --environment env-654321 --dir schemas --dry-run
+confluent schema import --environment env-654321 --dir schemas --preserve-ids --api-key ABCDEFGHIJKLMNOP --api-secret ...`,
+	}
+
+	cmd.Flags().String("environment", "", "Environment ID of the Schema Registry. Defaults to the current environment.")
+	cmd.Flags().String("dir", "schemas", "Directory of the exported tree.")
+	cmd.Flags().String("prefix", "", "Only import subjects whose names start with this prefix.")
+	cmd.Flags().String("context", "", `Only import subjects in this schema context, or "." for the default context. Defaults to every context.`)
+	cmd.Flags().Bool("preserve-ids", false, "Import in IMPORT mode, keeping the schema IDs and version numbers.")
+	cmd.Flags().String("api-key", "", "Schema Registry API key, which is needed with --preserve-ids. Defaults to $CONFLUENT_SCHEMA_REGISTRY_API_KEY.")
+	cmd.Flags().String("api-secret", "", "Schema Registry API secret. Defaults to $CONFLUENT_SCHEMA_REGISTRY_API_SECRET.")
+	cmd.Flags().String("schema-registry-endpoint", "", "URL of the Schema Registry. Defaults to the environment's.")
+	cmd.Flags().Bool("global-config", false, "Set the registry's compatibility level to the one in the tree too.")
+	dryrun.AddFlag(&cmd, "Print what would be imported without importing it.")
+	output.AddFlag(&cmd, "Format of what was imported")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-schema-import")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-schema-import"))
+
+	exitcode.Execute(&cmd)
+}
+
+func importTree(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dir, err := cmd.Flags().GetString("dir")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	context, err := cmd.Flags().GetString("context")
+	cobra.CheckErr(err)
+
+	preserveIDs, err := cmd.Flags().GetBool("preserve-ids")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	endpoint, err := cmd.Flags().GetString("schema-registry-endpoint")
+	cobra.CheckErr(err)
+
+	globalConfig, err := cmd.Flags().GetBool("global-config")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	subjects, config, err := readTree(dir, context, prefix)
+	if err != nil {
+		return err
+	}
+
+	im := &importer{out: output.Progress(cmd)}
+	if dryRun {
+		im.plan = &dryrun.Plan{}
+	}
+	if environment != "" {
+		im.environment = []string{"--environment", environment}
+	}
+
+	if preserveIDs {
+		if apiKey == "" {
+			apiKey = os.Getenv("CONFLUENT_SCHEMA_REGISTRY_API_KEY")
+		}
+		if apiSecret == "" {
+			apiSecret = os.Getenv("CONFLUENT_SCHEMA_REGISTRY_API_SECRET")
+		}
+		if apiKey == "" || apiSecret == "" {
+			return exitcode.Invalid("--preserve-ids needs a Schema Registry API key and secret")
+		}
+		if endpoint == "" {
+			endpoint, err = registryEndpoint(im.environment)
+			if err != nil {
+				return err
+			}
+		}
+		im.registry = newRegistry(endpoint, apiKey, apiSecret)
+	}
+
+	if err := im.run(subjects); err != nil {
+		if dryRun {
+			return err
+		}
+		return errors.Join(err, dryrun.PrintApplied(cmd, im.imported))
+	}
+
+	if globalConfig && config.Compatibility != "" {
+		if dryRun {
+			im.plan.AddDetail("set the compatibility of", "registry", "", "to "+config.Compatibility)
+		} else if err := im.setCompatibility("", config.Compatibility); err != nil {
+			return errors.Join(err, dryrun.PrintApplied(cmd, im.imported))
+		}
+	}
+
+	if dryRun {
+		return dryrun.Print(cmd, *im.plan)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Imported %d subjects from %s.\n", len(subjects), dir)
+	return dryrun.PrintApplied(cmd, im.imported)
+}
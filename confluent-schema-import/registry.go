@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// registry is a client of the Schema Registry REST API, which is needed to register schemas with their IDs, since the
+// CLI can't.
+type registry struct {
+	endpoint    string
+	key, secret string
+	client      *http.Client
+}
+
+func newRegistry(endpoint, key, secret string) *registry {
+	return &registry{endpoint: strings.TrimRight(endpoint, "/"), key: key, secret: secret, client: retry.Client(30 * time.Second)}
+}
+
+// registryEndpoint returns the URL of the environment's Schema Registry.
+func registryEndpoint(environment []string) (string, error) {
+	var cluster struct {
+		EndpointURL string `json:"endpoint_url"`
+	}
+	if err := cli.JSON(&cluster, append([]string{"schema-registry", "cluster", "describe"}, environment...)...); err != nil {
+		return "", err
+	}
+	if cluster.EndpointURL == "" {
+		return "", fmt.Errorf("the environment has no Schema Registry")
+	}
+	return cluster.EndpointURL, nil
+}
+
+func (r *registry) do(method, path string, body, v any) (err error) {
+	defer func() { audit.Request(method, path, err) }()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, r.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(r.key, r.secret)
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&e); err == nil && e.Message != "" {
+			return &registryError{code: e.ErrorCode, message: e.Message}
+		}
+		return &registryError{code: res.StatusCode, message: res.Status}
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type registryError struct {
+	code    int
+	message string
+}
+
+func (e *registryError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.message, e.code)
+}
+
+// subjectPath returns the path of a subject's resource, for subjects in any context.
+func subjectPath(resource, subject string) string {
+	return "/" + resource + "/" + url.PathEscape(subject)
+}
+
+// versions returns the versions of a subject, which are none if the subject doesn't exist.
+func (r *registry) versions(subject string) ([]int, error) {
+	var versions []int
+	if err := r.do(http.MethodGet, subjectPath("subjects", subject)+"/versions", nil, &versions); err != nil {
+		if e, ok := err.(*registryError); ok && e.code == 40401 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return versions, nil
+}
+
+// setMode sets the mode of a subject, or with "" reverts it to the registry's mode.
+func (r *registry) setMode(subject, mode string) error {
+	if mode == "" {
+		err := r.do(http.MethodDelete, subjectPath("mode", subject), nil, nil)
+		if e, ok := err.(*registryError); ok && e.code == 40401 {
+			return nil
+		}
+		return err
+	}
+	return r.do(http.MethodPut, subjectPath("mode", subject)+"?force=true", map[string]string{"mode": mode}, nil)
+}
+
+// register registers a schema with its ID and version, which is only allowed in IMPORT mode.
+func (r *registry) register(subject string, v version) error {
+	body := map[string]any{
+		"schema":     v.schema,
+		"schemaType": v.Type,
+		"id":         v.ID,
+		"version":    v.Version,
+	}
+	if len(v.References) > 0 {
+		body["references"] = v.References
+	}
+	return r.do(http.MethodPost, subjectPath("subjects", subject)+"/versions", body, nil)
+}
+
+func (r *registry) setCompatibility(subject, level string) error {
+	path := "/config"
+	if subject != "" {
+		path = subjectPath("config", subject)
+	}
+	return r.do(http.MethodPut, path, map[string]string{"compatibility": level}, nil)
+}
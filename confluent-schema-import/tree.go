@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"gopkg.in/yaml.v3"
+)
+
+// A subject in the exported tree, read from the subject.yaml file of its directory, which is the format written by
+// confluent schema export.
+type subject struct {
+	Name          string    `yaml:"name"`
+	Compatibility string    `yaml:"compatibility,omitempty"`
+	Versions      []version `yaml:"versions"`
+}
+
+type version struct {
+	Version    int         `yaml:"version"`
+	ID         int         `yaml:"id"`
+	Type       string      `yaml:"type"`
+	File       string      `yaml:"file"`
+	References []reference `yaml:"references,omitempty"`
+
+	schema string
+}
+
+type reference struct {
+	Name    string `yaml:"name" json:"name"`
+	Subject string `yaml:"subject" json:"subject"`
+	Version int    `yaml:"version" json:"version"`
+}
+
+// registryConfig is read from config.yaml at the root of the tree.
+type registryConfig struct {
+	Compatibility string `yaml:"compatibility,omitempty"`
+}
+
+// splitContext splits a qualified subject name, such as ":.staging:orders-value", into its context and name. Subjects
+// in the default context have no prefix, and their context is ".".
+func splitContext(qualified string) (string, string) {
+	if strings.HasPrefix(qualified, ":.") {
+		if i := strings.Index(qualified[2:], ":"); i >= 0 {
+			return qualified[2 : 2+i], qualified[3+i:]
+		}
+	}
+	return ".", qualified
+}
+
+// readTree reads the subjects in the tree which match the context and prefix, along with their schemas.
+func readTree(root, context, prefix string) ([]subject, registryConfig, error) {
+	var config registryConfig
+	if err := readYAML(filepath.Join(root, "config.yaml"), &config); err != nil && !os.IsNotExist(err) {
+		return nil, registryConfig{}, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(root, "subjects", "*", "subject.yaml"))
+	if err != nil {
+		return nil, registryConfig{}, err
+	}
+	contexts, err := filepath.Glob(filepath.Join(root, "contexts", "*", "subjects", "*", "subject.yaml"))
+	if err != nil {
+		return nil, registryConfig{}, err
+	}
+	files = append(files, contexts...)
+	if len(files) == 0 {
+		return nil, registryConfig{}, fmt.Errorf(`no subjects were found in "%s"`, root)
+	}
+
+	var subjects []subject
+	for _, file := range files {
+		var s subject
+		if err := readYAML(file, &s); err != nil {
+			return nil, registryConfig{}, err
+		}
+		c, name := splitContext(s.Name)
+		if context != "" && c != context || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		dir := filepath.Dir(file)
+		for i, v := range s.Versions {
+			b, err := os.ReadFile(filepath.Join(dir, v.File))
+			if err != nil {
+				return nil, registryConfig{}, fmt.Errorf(`failed to read version %d of subject "%s": %w`, v.Version, s.Name, err)
+			}
+			// Avro and JSON schemas are indented in the tree, and registered compactly, as they were exported.
+			if v.Type != "PROTOBUF" {
+				var buf bytes.Buffer
+				if err := json.Compact(&buf, b); err == nil {
+					b = buf.Bytes()
+				}
+			}
+			s.Versions[i].schema = platform.Text(b)
+		}
+		sort.Slice(s.Versions, func(i, j int) bool { return s.Versions[i].Version < s.Versions[j].Version })
+		subjects = append(subjects, s)
+	}
+
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].Name < subjects[j].Name })
+	return subjects, config, nil
+}
+
+func readYAML(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(b, v); err != nil {
+		return fmt.Errorf(`failed to parse "%s": %w`, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "prune",
+		Short: "Delete stale schema versions and subjects.",
+		Long:  "Delete old schema versions which have been superseded and aren't referenced, and with --cluster, the subjects of topics which were deleted. Versions are soft-deleted, or hard-deleted with --permanent.",
+		Args:  cobra.NoArgs,
+		RunE:  prune,
+		Example: `confluent schema prune --environment env-123456 --cluster lkc-123456 --dry-run
+confluent schema prune --keep-versions 3 --min-age 2160h --permanent --force`,
+	}
+
+	cmd.Flags().String("environment", "", "Environment ID of the Schema Registry. Defaults to the current environment.")
+	cmd.Flags().String("prefix", "", "Only prune subjects whose names start with this prefix.")
+	cmd.Flags().StringSlice("cluster", nil, "Kafka clusters whose topics the subjects are named after. Subjects of topics which aren't in any of them are deleted.")
+	cmd.Flags().Int("keep-versions", 1, "How many of the newest versions of each subject to keep.")
+	cmd.Flags().Duration("min-age", 30*24*time.Hour, "Only delete versions which were first seen at least this long ago.")
+	cmd.Flags().String("state-file", "", "File which records when each version was first seen. Defaults to ~/.confluent/schema-prune.json.")
+	cmd.Flags().Bool("permanent", false, "Hard-delete the versions after soft-deleting them.")
+	dryrun.AddFlag(&cmd, "List what would be deleted without deleting it.")
+	cmd.Flags().Bool("force", false, "Delete without prompting for confirmation.")
+	output.AddFlag(&cmd, "Format of the output")
+	cmd.Flags().Int("parallelism", 8, "How many subjects to read at once.")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-schema-prune")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-schema-prune"))
+
+	exitcode.Execute(&cmd)
+}
+
+func prune(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	clusters, err := cmd.Flags().GetStringSlice("cluster")
+	cobra.CheckErr(err)
+
+	keepVersions, err := cmd.Flags().GetInt("keep-versions")
+	cobra.CheckErr(err)
+
+	minAge, err := cmd.Flags().GetDuration("min-age")
+	cobra.CheckErr(err)
+
+	stateFile, err := cmd.Flags().GetString("state-file")
+	cobra.CheckErr(err)
+
+	permanent, err := cmd.Flags().GetBool("permanent")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if keepVersions < 1 {
+		return exitcode.Invalid("--keep-versions must be at least 1")
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	if stateFile == "" {
+		stateFile, err = defaultStateFile()
+		if err != nil {
+			return err
+		}
+	}
+
+	r := reader{parallelism: parallelism}
+	if environment != "" {
+		r.environment = []string{"--environment", environment}
+	}
+
+	// The state is kept per environment, and the current environment is resolved, so that it's the same whether or
+	// not --environment is passed.
+	var env struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&env, append([]string{"environment", "describe"}, environment)...); err != nil {
+		return err
+	}
+
+	versions, err := r.read(prefix)
+	if err != nil {
+		return err
+	}
+
+	p := pruner{keepVersions: keepVersions, minAge: minAge}
+	if len(clusters) > 0 {
+		p.topics, err = r.topics(clusters)
+		if err != nil {
+			return err
+		}
+	}
+
+	s, err := readState(stateFile)
+	if err != nil {
+		return fmt.Errorf(`failed to read the state file "%s": %w`, stateFile, err)
+	}
+	now := time.Now()
+	ages := map[string]time.Duration{}
+	seen := map[string]bool{}
+	for _, vs := range versions {
+		for _, v := range vs {
+			key := env.ID + "/" + v.key()
+			ages[v.key()] = s.see(key, now)
+			seen[key] = true
+		}
+	}
+	s.forget(env.ID+"/"+prefix, seen)
+	if err := s.write(); err != nil {
+		return fmt.Errorf(`failed to write the state file "%s": %w`, stateFile, err)
+	}
+
+	candidates := p.plan(versions, ages)
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, c := range candidates {
+			if c.Version > 0 {
+				plan.AddDetail("delete", "subject", c.Subject, fmt.Sprintf("version %d, which is %s", c.Version, c.Reason))
+			} else {
+				plan.AddDetail("delete", "subject", c.Subject, "since "+c.Reason)
+			}
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if candidates == nil {
+			candidates = []candidate{}
+		}
+		if err := output.Print(out, format, candidates, nil); err != nil {
+			return err
+		}
+	} else {
+		print(out, candidates)
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Delete %d schema versions and subjects?", len(candidates)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not deleting anything.")
+			return nil
+		}
+	}
+
+	for _, c := range candidates {
+		version := "all"
+		if c.Version > 0 {
+			version = strconv.Itoa(c.Version)
+		}
+		args := append([]string{"schema-registry", "schema", "delete", "--subject", c.Subject, "--version", version, "--force"}, r.environment...)
+		if _, err := cli.Run(args...); err != nil {
+			return err
+		}
+		// Versions can only be hard-deleted once they're soft-deleted.
+		if permanent {
+			if _, err := cli.Run(append(args, "--permanent")...); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %d schema versions and subjects.\n", len(candidates))
+	return nil
+}
+
+func print(w io.Writer, candidates []candidate) {
+	if len(candidates) == 0 {
+		fmt.Fprintln(w, "Nothing to prune.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Subject\tVersion\tReason")
+	for _, c := range candidates {
+		version := "all"
+		if c.Version > 0 {
+			version = strconv.Itoa(c.Version)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Subject, version, c.Reason)
+	}
+	_ = tw.Flush()
+}
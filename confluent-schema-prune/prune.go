@@ -0,0 +1,73 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// A candidate is a version to delete, or with version 0, a whole subject.
+type candidate struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+type pruner struct {
+	keepVersions int
+	minAge       time.Duration
+	// topics is nil if the subjects of deleted topics aren't pruned.
+	topics map[string]bool
+}
+
+// plan finds the versions and subjects to delete:
+//   - Versions older than minAge which aren't among the newest keepVersions of their subject, and which no other version
+//     references.
+//   - Subjects named after topics which were deleted, whose versions are all older than minAge, and which no other
+//     subject references.
+func (p pruner) plan(versions map[string][]schemaVersion, ages map[string]time.Duration) []candidate {
+	referenced := map[string]bool{}
+	referencedSubjects := map[string]bool{}
+	for subject, vs := range versions {
+		for _, v := range vs {
+			for _, r := range v.references {
+				if r.Subject == subject {
+					continue
+				}
+				referenced[schemaVersion{subject: r.Subject, version: r.Version}.key()] = true
+				referencedSubjects[r.Subject] = true
+			}
+		}
+	}
+
+	var candidates []candidate
+	for subject, vs := range versions {
+		if topic, ok := topicOf(subject); ok && p.topics != nil && !p.topics[topic] && !referencedSubjects[subject] && p.allOld(vs, ages) {
+			candidates = append(candidates, candidate{Subject: subject, Reason: "topic " + topic + " was deleted"})
+			continue
+		}
+
+		for i, v := range vs {
+			if i >= len(vs)-p.keepVersions || referenced[v.key()] || ages[v.key()] < p.minAge {
+				continue
+			}
+			candidates = append(candidates, candidate{Subject: subject, Version: v.version, Reason: "superseded"})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Subject != candidates[j].Subject {
+			return candidates[i].Subject < candidates[j].Subject
+		}
+		return candidates[i].Version < candidates[j].Version
+	})
+	return candidates
+}
+
+func (p pruner) allOld(vs []schemaVersion, ages map[string]time.Duration) bool {
+	for _, v := range vs {
+		if ages[v.key()] < p.minAge {
+			return false
+		}
+	}
+	return true
+}
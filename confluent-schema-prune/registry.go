@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+type reference struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// A schemaVersion is a version of a subject, and the versions it references.
+type schemaVersion struct {
+	subject    string
+	version    int
+	references []reference
+}
+
+func (v schemaVersion) key() string {
+	return fmt.Sprintf("%s/%d", v.subject, v.version)
+}
+
+type reader struct {
+	environment []string
+	parallelism int
+}
+
+// read reads every version of the subjects which match the prefix, and their references, a subject at a time in
+// parallel. Versions are in order within each subject.
+func (r reader) read(prefix string) (map[string][]schemaVersion, error) {
+	args := []string{"schema-registry", "subject", "list"}
+	if prefix != "" {
+		args = append(args, "--prefix", prefix)
+	}
+
+	var subjects []struct {
+		Subject string `json:"subject"`
+	}
+	if err := cli.JSON(&subjects, append(args, r.environment...)...); err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sem      = make(chan struct{}, r.parallelism)
+		errs     = make([]error, len(subjects))
+		versions = map[string][]schemaVersion{}
+	)
+	for i, s := range subjects {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, subject string) {
+			defer func() { <-sem; wg.Done() }()
+
+			vs, err := r.readSubject(subject)
+			if err != nil {
+				errs[i] = fmt.Errorf(`failed to read subject "%s": %w`, subject, err)
+				return
+			}
+
+			mu.Lock()
+			versions[subject] = vs
+			mu.Unlock()
+		}(i, s.Subject)
+	}
+	wg.Wait()
+
+	return versions, errors.Join(errs...)
+}
+
+func (r reader) readSubject(subject string) ([]schemaVersion, error) {
+	out, err := cli.Run(append([]string{"schema-registry", "subject", "describe", subject, "--output", "json"}, r.environment...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older versions of the CLI print the versions as a plain list.
+	var numbers []int
+	if err := json.Unmarshal(out, &numbers); err != nil {
+		var described struct {
+			Versions []int `json:"versions"`
+		}
+		if err := json.Unmarshal(out, &described); err != nil {
+			return nil, fmt.Errorf("failed to parse the versions: %w", err)
+		}
+		numbers = described.Versions
+	}
+
+	versions := make([]schemaVersion, len(numbers))
+	for i, n := range numbers {
+		var described struct {
+			References []reference `json:"references"`
+		}
+		if err := cli.JSON(&described, append([]string{"schema-registry", "schema", "describe", "--subject", subject, "--version", strconv.Itoa(n)}, r.environment...)...); err != nil {
+			return nil, err
+		}
+		versions[i] = schemaVersion{subject: subject, version: n, references: described.References}
+	}
+	return versions, nil
+}
+
+// topics lists the topics of the Kafka clusters.
+func (r reader) topics(clusters []string) (map[string]bool, error) {
+	topics := map[string]bool{}
+	for _, cluster := range clusters {
+		var listed []cli.Topic
+		if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list", "--cluster", cluster}, r.environment...)...); err != nil {
+			return nil, err
+		}
+		for _, t := range listed {
+			topics[t.Name] = true
+		}
+	}
+	return topics, nil
+}
+
+// topicOf returns the topic a subject is named after with the default topic name strategy, if any.
+func topicOf(subject string) (string, bool) {
+	for _, suffix := range []string{"-key", "-value"} {
+		if topic, ok := strings.CutSuffix(subject, suffix); ok {
+			return topic, true
+		}
+	}
+	return "", false
+}
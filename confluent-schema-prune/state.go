@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The registry doesn't record when versions were registered, so the age of a version is how long ago it was first seen
+// by a run of the plugin, which is kept in a state file between runs.
+type state struct {
+	path string
+	// FirstSeen maps each version, as "<environment>/<subject>/<version>", to when it was first seen.
+	FirstSeen map[string]time.Time `json:"first_seen"`
+}
+
+func defaultStateFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".confluent", "schema-prune.json"), nil
+}
+
+func readState(path string) (*state, error) {
+	s := &state{path: path, FirstSeen: map[string]time.Time{}}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	if s.FirstSeen == nil {
+		s.FirstSeen = map[string]time.Time{}
+	}
+	return s, nil
+}
+
+// see records when the version was first seen, and returns how long ago that was.
+func (s *state) see(key string, now time.Time) time.Duration {
+	seen, ok := s.FirstSeen[key]
+	if !ok {
+		s.FirstSeen[key] = now
+		return 0
+	}
+	return now.Sub(seen)
+}
+
+// forget stops tracking versions which weren't seen during this run, since they were deleted.
+func (s *state) forget(prefix string, seen map[string]bool) {
+	for key := range s.FirstSeen {
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			delete(s.FirstSeen, key)
+		}
+	}
+}
+
+func (s *state) write() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0600)
+}
@@ -15,13 +15,44 @@
 import argparse
 import subprocess
 import json
+import os
+import re
+import sys
+
+
+# The exit codes of the Go plugins for the failures of the confluent CLI, found in its error, so that scripts can react
+# to the failures of this plugin as to theirs. They're documented in the README.
+EXIT_CODES = [
+    (6, re.compile(r'too many requests|rate.?limit|throttl|\b429\b', re.I)),
+    (3, re.compile(r'not logged in|must be logged in|log in|unauthori[sz]ed|forbidden|\b40[13]\b|permission denied|'
+                   r'not authorized|authentication failed|invalid (api key|credentials)|token (is )?expired', re.I)),
+    (5, re.compile(r'quota|exceeds? (the |your )?(limit|maximum)|limit (exceeded|reached)', re.I)),
+    (4, re.compile(r'not found|\b404\b|does not exist|doesn\'t exist', re.I)),
+]
+EXIT_VALIDATION = 7
+
+
+def exit_code(stderr):
+    for code, pattern in EXIT_CODES:
+        if pattern.search(stderr):
+            return code
+    return 1
+
+
+class ArgumentParser(argparse.ArgumentParser):
+    # Invalid arguments exit with the exit code of the Go plugins, rather than with argparse's 2, which theirs use for
+    # checks which found problems.
+    def error(self, message):
+        self.print_usage(sys.stderr)
+        self.exit(EXIT_VALIDATION, f'{self.prog}: error: {message}\n')
 
 
 def cli(cmd_args, print_output=False, fmt_json=True):
     results = subprocess.run(cmd_args, capture_output=True)
     if results.returncode != 0:
         print(str(results.stderr, 'UTF-8'))
-        exit(results.returncode)
+        print_deleted()
+        exit(exit_code(str(results.stderr, 'UTF-8')))
     if fmt_json:
         final_result = json.loads(results.stdout)
     else:
@@ -33,16 +64,85 @@ def cli(cmd_args, print_output=False, fmt_json=True):
     return final_result
 
 
-usage_message = 'confluent schema-registry schema purge [-h] [--subject-prefix SUBJECT_PREFIX] [--context CONTEXT] [--env ENV]'
-
-parser = argparse.ArgumentParser(description='Deletes all schemas permanently.  This plugin assumes confluent CLI v3.25.0 or greater',
-                                 usage=usage_message)
+def print_deleted():
+    """Prints the subjects which were permanently deleted with -o json or yaml, as the Go plugins print what they did,
+    as the only output on stdout, and nothing with -o table."""
+    document = {'dry_run': False, 'actions': deleted}
+    if args.output == 'json':
+        json.dump(document, result_out, indent=2)
+        print(file=result_out, flush=True)
+    elif args.output == 'yaml':
+        import yaml
+        yaml.safe_dump(document, result_out, sort_keys=False)
+        result_out.flush()
+
+
+def apply_plugin_config(parser, plugin):
+    """Sets the defaults of the arguments from ~/.confluent/plugins.yaml, or $CONFLUENT_PLUGINS_CONFIG, which the
+    Go plugins read too: first from its defaults, then from the settings of the plugin. Arguments which are passed
+    override them. Reading it requires PyYAML, without which it's ignored with a warning."""
+    path = os.environ.get('CONFLUENT_PLUGINS_CONFIG') or os.path.join(os.path.expanduser('~'), '.confluent', 'plugins.yaml')
+    if not os.path.exists(path):
+        return
+    try:
+        import yaml
+    except ImportError:
+        print(f'Warning: ignoring {path}, which requires PyYAML to read (pip install pyyaml)', file=sys.stderr)
+        return
+
+    with open(path, encoding='utf-8') as config_file:
+        config = yaml.safe_load(config_file) or {}
+    actions = {action.dest: action for action in parser._actions}
+    settings = {name: value for name, value in (config.get('defaults') or {}).items()
+                if name.replace('-', '_') in actions}
+    for name, value in ((config.get('plugins') or {}).get(plugin) or {}).items():
+        if name.replace('-', '_') not in actions:
+            parser.error(f'{plugin} has no flag --{name}, which is set for it in {path}')
+        settings[name] = value
+
+    for name, value in settings.items():
+        action = actions[name.replace('-', '_')]
+        if isinstance(action, argparse._StoreTrueAction):
+            action.default = bool(value)
+        else:
+            values = [str(v) for v in value] if isinstance(value, list) else [str(value)]
+            for v in values:
+                if action.choices and v not in action.choices:
+                    parser.error(f'invalid {name} "{v}" in {path}, expected one of {", ".join(action.choices)}')
+            action.default = values if action.nargs in ('*', '+') else values[0]
+        # A required argument which has a default in the config file doesn't have to be passed.
+        action.required = False
+
+
+usage_message = 'confluent schema-registry schema purge [-h] [--subject-prefix SUBJECT_PREFIX] [--context CONTEXT] [--env ENV] [--yes] [--non-interactive] [--output {table,json,yaml}]'
+
+parser = ArgumentParser(description='Deletes all schemas permanently.  This plugin assumes confluent CLI v3.25.0 or greater',
+                        usage=usage_message)
 
 parser.add_argument('--subject-prefix', help='List schemas for subjects matching the prefix')
 parser.add_argument('--context', help='The CLI context name')
 parser.add_argument('--env', help='The environment ID')
-
+parser.add_argument('--yes', '-y', action='store_true', help='Delete the schemas without asking for confirmation, such as in CI')
+parser.add_argument('--non-interactive', action='store_true',
+                    help='Fail rather than ask for confirmation, unless --yes is passed, such as in CI')
+parser.add_argument('--output', '-o', choices=['table', 'json', 'yaml'], default='table',
+                    help='With json or yaml, print the subjects which were permanently deleted, and the progress to '
+                         'stderr. Defaults to table')
+
+apply_plugin_config(parser, 'confluent-schema_registry-schema-purge')
 args = parser.parse_args()
+non_interactive = args.non_interactive or bool(os.environ.get('CONFLUENT_PLUGINS_NON_INTERACTIVE'))
+if args.output == 'yaml':
+    try:
+        import yaml
+    except ImportError:
+        parser.error('--output yaml requires PyYAML (pip install pyyaml), or pass --output json')
+
+# With -o json or yaml, stdout only has the subjects which were deleted, and the progress goes to stderr.
+result_out = sys.stdout
+if args.output != 'table':
+    sys.stdout = sys.stderr
+deleted = []
 
 list_schema_cmd = ['confluent', 'schema-registry', 'schema', 'list', '--output', 'json']
 delete_schema_cmd = ['confluent', 'schema-registry', 'schema', 'delete', '--force', '--version', 'all']
@@ -99,9 +199,15 @@ for json_schema in schema_list_json:
     schema_ids.append(json_schema['schema_id'])
     subjects_versions[json_schema['subject']] = json_schema['version']
 
-do_delete = input("Are you sure you want to delete all schemas? y|n  ")
+if args.yes:
+    do_delete = 'y'
+elif non_interactive:
+    parser.error('not asking whether to delete all schemas with --non-interactive, pass --yes to confirm')
+else:
+    do_delete = input("Are you sure you want to delete all schemas? y|n  ")
 if do_delete != 'y':
     print('Quitting and leaving all schemas in-place')
+    print_deleted()
     exit(0)
 else:
     schemas_with_refs_to_delete_first = get_schemas_with_references(schema_subjects, schema_ids)
@@ -120,3 +226,5 @@ else:
     for schema_subject in schema_subjects:
         print(f'Hard delete for {schema_subject}')
         do_delete_schema(schema_subject, subjects_versions[schema_subject])
+        deleted.append({'action': 'delete', 'resource': 'subject', 'name': schema_subject, 'detail': 'permanently'})
+    print_deleted()
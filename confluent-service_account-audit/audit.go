@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+type serviceAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// A finding is a service account, with the evidence of whether it's still used.
+type finding struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	APIKeys      int    `json:"api_keys"`
+	RoleBindings int    `json:"role_bindings"`
+	// Requests is nil if usage wasn't checked.
+	Requests *float64 `json:"requests,omitempty"`
+	Unused   bool     `json:"unused"`
+}
+
+// evaluate marks service accounts as unused which can't authenticate, since they have no API keys, and aren't
+// authorized to do anything, since they have no role bindings. If their requests were counted, they must not have made
+// any either.
+func (f *finding) evaluate() {
+	f.Unused = f.APIKeys == 0 && f.RoleBindings == 0 && (f.Requests == nil || *f.Requests == 0)
+}
+
+// countAPIKeys counts the API keys owned by each service account, for any resource.
+func countAPIKeys() (map[string]int, error) {
+	var keys []cli.APIKey
+	if err := cli.JSON(&keys, "api-key", "list"); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, k := range keys {
+		counts[k.OwnerResourceID]++
+	}
+	return counts, nil
+}
+
+// countRoleBindings counts the role bindings of each service account at every scope, in parallel.
+func countRoleBindings(serviceAccounts []serviceAccount, parallelism int) (map[string]int, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		sem    = make(chan struct{}, parallelism)
+		errs   = make([]error, len(serviceAccounts))
+		counts = map[string]int{}
+	)
+	for i, sa := range serviceAccounts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer func() { <-sem; wg.Done() }()
+
+			var bindings []struct{}
+			if err := cli.JSON(&bindings, "iam", "rbac", "role-binding", "list", "--principal", "User:"+id, "--inclusive"); err != nil {
+				errs[i] = fmt.Errorf(`failed to list the role bindings of "%s": %w`, id, err)
+				return
+			}
+
+			mu.Lock()
+			counts[id] = len(bindings)
+			mu.Unlock()
+		}(i, sa.ID)
+	}
+	wg.Wait()
+
+	return counts, errors.Join(errs...)
+}
+
+// countRequests counts each principal's requests to every Kafka cluster in the organization.
+func countRequests(days int, key, secret string) (map[string]float64, error) {
+	var environments []cli.Environment
+	if err := cli.JSON(&environments, "environment", "list"); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]float64{}
+	for _, e := range environments {
+		var clusters []cli.KafkaCluster
+		if err := cli.JSON(&clusters, "kafka", "cluster", "list", "--environment", e.ID); err != nil {
+			return nil, err
+		}
+		for _, c := range clusters {
+			requests, err := requestCounts(c.ID, days, key, secret)
+			if err != nil {
+				return nil, err
+			}
+			for principal, n := range requests {
+				counts[principal] += n
+			}
+		}
+	}
+	return counts, nil
+}
+
+func audit(serviceAccounts []serviceAccount, keys, bindings map[string]int, requests map[string]float64) []finding {
+	findings := make([]finding, len(serviceAccounts))
+	for i, sa := range serviceAccounts {
+		f := finding{ID: sa.ID, Name: sa.Name, APIKeys: keys[sa.ID], RoleBindings: bindings[sa.ID]}
+		if requests != nil {
+			n := requests[sa.ID]
+			f.Requests = &n
+		}
+		f.evaluate()
+		findings[i] = f
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].ID < findings[j].ID })
+	return findings
+}
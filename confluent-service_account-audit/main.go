@@ -0,0 +1,177 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "audit",
+		Short: "Find unused service accounts.",
+		Long:  "Find service accounts with no API keys and no role bindings, and with a Cloud API key for the Metrics API, which made no requests to any Kafka cluster recently. Unused service accounts can be deleted one by one with --interactive, or all at once with --delete.",
+		RunE:  auditServiceAccounts,
+		Example: `confluent service-account audit --unused-only
+confluent service-account audit --metrics-api-key ABCDEFGHIJKLMNOP --days 90 --interactive`,
+	}
+
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Bool("unused-only", false, "Only report unused service accounts.")
+	cmd.Flags().String("metrics-api-key", "", "Cloud API key to count each service account's requests with the Metrics API. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("metrics-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	cmd.Flags().Int("days", 30, "How many days of requests to count with the Metrics API.")
+	cmd.Flags().Bool("interactive", false, "Prompt to delete each unused service account.")
+	cmd.Flags().Bool("delete", false, "Delete every unused service account without prompting.")
+	cmd.Flags().Int("parallelism", 8, "How many service accounts to list the role bindings of at once.")
+	cli.AddCacheFlag(&cmd)
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("interactive", "delete")
+
+	completion.Add(&cmd, "confluent-service_account-audit")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-service_account-audit"))
+
+	exitcode.Execute(&cmd)
+}
+
+func auditServiceAccounts(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	unusedOnly, err := cmd.Flags().GetBool("unused-only")
+	cobra.CheckErr(err)
+
+	metricsKey, err := cmd.Flags().GetString("metrics-api-key")
+	cobra.CheckErr(err)
+
+	metricsSecret, err := cmd.Flags().GetString("metrics-api-secret")
+	cobra.CheckErr(err)
+
+	days, err := cmd.Flags().GetInt("days")
+	cobra.CheckErr(err)
+
+	interactive, err := cmd.Flags().GetBool("interactive")
+	cobra.CheckErr(err)
+
+	del, err := cmd.Flags().GetBool("delete")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+	if metricsKey == "" {
+		metricsKey = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if metricsSecret == "" {
+		metricsSecret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	var serviceAccounts []serviceAccount
+	if err := cli.JSON(&serviceAccounts, "iam", "service-account", "list"); err != nil {
+		return err
+	}
+
+	keys, err := countAPIKeys()
+	if err != nil {
+		return err
+	}
+
+	bindings, err := countRoleBindings(serviceAccounts, parallelism)
+	if err != nil {
+		return err
+	}
+
+	// Usage is only checked with a Cloud API key, so a service account without keys or bindings is reported as unused
+	// even if it made requests before they were removed.
+	var requests map[string]float64
+	if metricsKey != "" && metricsSecret != "" {
+		requests, err = countRequests(days, metricsKey, metricsSecret)
+		if err != nil {
+			return err
+		}
+	}
+
+	findings := audit(serviceAccounts, keys, bindings, requests)
+
+	var unused []finding
+	for _, f := range findings {
+		if f.Unused {
+			unused = append(unused, f)
+		}
+	}
+	if unusedOnly {
+		findings = unused
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, findings, func() { print(out, findings, days) }); err != nil {
+		return err
+	}
+
+	if !interactive && !del {
+		return nil
+	}
+
+	p := prompt.New(cmd)
+	deleted := 0
+	for _, f := range unused {
+		if interactive {
+			ok, err := p.Confirm(fmt.Sprintf("Delete %s (%s)?", f.ID, f.Name))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if _, err := cli.Run("iam", "service-account", "delete", f.ID, "--force"); err != nil {
+			return err
+		}
+		deleted++
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %d service accounts.\n", deleted)
+	return nil
+}
+
+func print(w io.Writer, findings []finding, days int) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tName\tAPI Keys\tRole Bindings\tRequests (%dd)\tUnused\n", days)
+	for _, f := range findings {
+		requests := "-"
+		if f.Requests != nil {
+			requests = fmt.Sprintf("%.0f", *f.Requests)
+		}
+		unused := ""
+		if f.Unused {
+			unused = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\n", f.ID, f.Name, f.APIKeys, f.RoleBindings, requests, unused)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+const metricsURL = "https://api.telemetry.confluent.cloud/v2/metrics/cloud/query"
+
+// principalPoint is a metric of a principal.
+type principalPoint struct {
+	PrincipalID string  `json:"metric.principal_id"`
+	Value       float64 `json:"value"`
+}
+
+// requestCounts returns how many requests each principal made to the Kafka cluster in the last days, with the
+// Metrics API, which is authenticated with a Cloud API key.
+func requestCounts(cluster string, days int, key, secret string) (map[string]float64, error) {
+	query := map[string]any{
+		"aggregations": []map[string]string{{"metric": "io.confluent.kafka.server/request_count"}},
+		"filter":       map[string]string{"field": "resource.kafka.id", "op": "EQ", "value": cluster},
+		"granularity":  "ALL",
+		"intervals":    []string{fmt.Sprintf("now-%dd/now", days)},
+		"group_by":     []string{"metric.principal_id"},
+		"limit":        1000,
+	}
+	b, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	client := retry.Client(30 * time.Second)
+	points, err := paging.All(func(token string) ([]principalPoint, string, error) {
+		req, err := http.NewRequest(http.MethodPost, paging.WithToken(metricsURL, token), bytes.NewReader(b))
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(key, secret)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("failed to query the metrics of %s: %s", cluster, res.Status)
+		}
+
+		var page paging.MetricsPage[principalPoint]
+		if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+			return nil, "", fmt.Errorf("failed to parse the metrics of %s: %w", cluster, err)
+		}
+		return page.Data, page.Next(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]float64{}
+	for _, d := range points {
+		counts[d.PrincipalID] += d.Value
+	}
+	return counts, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errFailed fails the plugin with exitcode.Findings when a check fails, as opposed to when the smoke test can't be run.
+var errFailed = exitcode.New(exitcode.Findings, "checks failed")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "test",
+		Short: "Smoke test a Kafka cluster end to end.",
+		Long:  "Create a temporary topic, schema, and API key, produce records with them and consume them back, check the round-trip latency, and delete everything again, such as to probe a cluster and its credentials from monitoring. Exits with code 2 if any check fails, including cleaning up.",
+		Args:  cobra.NoArgs,
+		RunE:  smoke,
+		Example: `confluent smoke test --cluster lkc-123456
+confluent smoke test --cluster lkc-123456 --api-key ABCDEFGHIJKLMNOP --max-latency 2s --output json`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster. Defaults to a new API key, which is deleted once the smoke test is done.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	cmd.Flags().Int("records", 5, "How many records to produce and consume.")
+	cmd.Flags().Duration("max-latency", 5*time.Second, "Longest round-trip latency of a record which passes.")
+	cmd.Flags().Duration("timeout", 30*time.Second, "How long to wait to consume the records.")
+	cmd.Flags().Bool("no-schema", false, "Don't register a schema, such as for a cluster whose environment has no Schema Registry.")
+	output.AddFlag(&cmd, "Format of the report")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-smoke-test")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-smoke-test"))
+
+	exitcode.Execute(&cmd)
+}
+
+func smoke(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	records, err := cmd.Flags().GetInt("records")
+	cobra.CheckErr(err)
+
+	maxLatency, err := cmd.Flags().GetDuration("max-latency")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	noSchema, err := cmd.Flags().GetBool("no-schema")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if records < 1 {
+		return exitcode.Invalid("--records must be at least 1")
+	}
+	if timeout <= 0 {
+		return exitcode.Invalid("--timeout must be positive")
+	}
+	if apiKey != "" && apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+		if apiSecret == "" {
+			return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required with --api-key")
+		}
+	}
+
+	t := &smokeTest{
+		environment: environment,
+		scope:       cli.ClusterFlags(clusterID, environment),
+		apiKey:      apiKey,
+		apiSecret:   apiSecret,
+		records:     records,
+		timeout:     timeout,
+		maxLatency:  maxLatency,
+		noSchema:    noSchema,
+	}
+	if t.cluster, err = cli.DescribeKafkaCluster(clusterID, environment); err != nil {
+		return err
+	}
+	if bootstrap == "" {
+		bootstrap = t.cluster.Endpoint
+	}
+	t.bootstrap = strings.TrimPrefix(bootstrap, "SASL_SSL://")
+
+	t.run()
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, t.checks, func() { print(out, t.checks) }); err != nil {
+		return err
+	}
+
+	for _, c := range t.checks {
+		if c.Status == fail {
+			return errFailed
+		}
+	}
+	return nil
+}
+
+func print(w io.Writer, checks []check) {
+	counts := map[string]int{}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Check\tTarget\tResult\tDetail")
+	for _, c := range checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Check, c.Target, strings.ToUpper(c.Status), c.Detail)
+		counts[c.Status]++
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped.\n", counts[pass], counts[fail], counts[skip])
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+)
+
+const (
+	pass = "pass"
+	fail = "fail"
+	skip = "skip"
+)
+
+// valueSchema is the Avro schema of the records which are produced, in the subject of the topic's values.
+const valueSchema = `{"type":"record","name":"SmokeTest","namespace":"io.confluent.cli.plugins","fields":[{"name":"message","type":"string"}]}`
+
+// setupRetries is how many times connecting to the topic is retried when its API key or the topic itself is new, since
+// both take a while to be usable.
+const setupRetries = 12
+
+// A check is the result of one step of the smoke test, against one resource.
+type check struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// A smokeTest creates a topic, a schema, and an API key, produces and consumes records with them, and deletes them
+// again, in that order, so that the first check which fails is the cause, and the checks which depend on it are
+// skipped. Whatever was created is deleted even if a check fails.
+type smokeTest struct {
+	cluster     cli.KafkaCluster
+	environment string
+	scope       []string
+	bootstrap   string
+
+	apiKey    string
+	apiSecret string
+	topic     string
+	subject   string
+	schemaID  int
+	newKey    bool
+
+	records    int
+	timeout    time.Duration
+	maxLatency time.Duration
+	noSchema   bool
+
+	checks []check
+	failed bool
+}
+
+// step runs a check unless an earlier one failed, and records its result, with how long it took as its detail unless
+// the check returns one.
+func (t *smokeTest) step(name, target string, f func() (string, error)) {
+	if t.failed {
+		t.checks = append(t.checks, check{name, target, skip, "an earlier check failed"})
+		return
+	}
+
+	start := time.Now()
+	detail, err := f()
+	if err != nil {
+		t.checks = append(t.checks, check{name, target, fail, err.Error()})
+		t.failed = true
+		return
+	}
+	if detail == "" {
+		detail = fmt.Sprintf("took %s", time.Since(start).Round(time.Millisecond))
+	}
+	t.checks = append(t.checks, check{name, target, pass, detail})
+}
+
+// cleanUp records a check for deleting a resource, which is run even if an earlier check failed.
+func (t *smokeTest) cleanUp(name, target string, args ...string) {
+	if _, err := cli.Run(args...); err != nil {
+		t.checks = append(t.checks, check{name, target, fail, err.Error()})
+		return
+	}
+	t.checks = append(t.checks, check{name, target, pass, ""})
+}
+
+func (t *smokeTest) run() {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		t.checks = append(t.checks, check{"Create topic", "", fail, err.Error()})
+		return
+	}
+	topic := "smoke-test-" + hex.EncodeToString(suffix)
+
+	if t.apiKey == "" {
+		t.step("Create API key", t.cluster.ID, t.createKey)
+	}
+	// An API key which was passed is left alone.
+	if t.newKey {
+		defer func() { t.cleanUp("Delete API key", t.apiKey, "api-key", "delete", t.apiKey, "--force") }()
+	}
+
+	t.step("Create topic", topic, func() (string, error) {
+		_, err := cli.Run(append([]string{"kafka", "topic", "create", topic, "--partitions", "1"}, t.scope...)...)
+		return "", err
+	})
+	if !t.failed {
+		t.topic = topic
+		defer func() {
+			t.cleanUp("Delete topic", t.topic, append([]string{"kafka", "topic", "delete", t.topic, "--force"}, t.scope...)...)
+		}()
+	}
+
+	if !t.noSchema {
+		subject := topic + "-value"
+		t.step("Register schema", subject, func() (string, error) {
+			return t.register(subject)
+		})
+		if !t.failed {
+			t.subject = subject
+			defer func() {
+				args := append([]string{"schema-registry", "schema", "delete", "--subject", t.subject, "--version", "all", "--force"}, cli.ClusterFlags("", t.environment)...)
+				t.cleanUp("Delete schema", t.subject, args...)
+				t.cleanUp("Delete schema permanently", t.subject, append(args, "--permanent")...)
+			}()
+		}
+	}
+
+	var client *kafka.Client
+	var partition kafka.Partition
+	t.step("Connect", t.bootstrap, func() (string, error) {
+		var err error
+		client, partition, err = t.connect()
+		return "", err
+	})
+	if client != nil {
+		defer client.Close()
+	}
+
+	var offset int64
+	var produced []kafka.Record
+	t.step("Produce", t.topic, func() (string, error) {
+		var err error
+		offset, produced, err = t.produce(client, partition)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("produced %d records", len(produced)), nil
+	})
+
+	var latency time.Duration
+	t.step("Consume", t.topic, func() (string, error) {
+		var err error
+		latency, err = t.consume(client, partition, offset, produced)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("consumed %d records", len(produced)), nil
+	})
+
+	t.step("Round-trip latency", t.topic, func() (string, error) {
+		if latency > t.maxLatency {
+			return "", fmt.Errorf("%s is more than the maximum of %s", latency.Round(time.Millisecond), t.maxLatency)
+		}
+		return latency.Round(time.Millisecond).String(), nil
+	})
+}
+
+func (t *smokeTest) createKey() (string, error) {
+	args := append([]string{"api-key", "create", "--resource", t.cluster.ID, "--description", "confluent smoke test"}, cli.ClusterFlags("", t.environment)...)
+
+	var key cli.CreatedAPIKey
+	if err := cli.JSON(&key, args...); err != nil {
+		return "", err
+	}
+	if key.Key == "" || key.Secret == "" {
+		return "", fmt.Errorf("confluent api-key create didn't return a key and secret")
+	}
+	t.apiKey, t.apiSecret, t.newKey = key.Key, key.Secret, true
+	return fmt.Sprintf(`created "%s"`, key.Key), nil
+}
+
+func (t *smokeTest) register(subject string) (string, error) {
+	path, err := platform.WriteTemp("smoke-test-*.avsc", []byte(valueSchema))
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	args := append([]string{"schema-registry", "schema", "create", "--subject", subject, "--schema", path, "--type", "avro"}, cli.ClusterFlags("", t.environment)...)
+	if err := cli.JSON(&registered, args...); err != nil {
+		return "", err
+	}
+	if registered.ID == 0 {
+		return "", fmt.Errorf("confluent schema-registry schema create didn't return a schema ID")
+	}
+	t.schemaID = registered.ID
+	return fmt.Sprintf("schema ID %d", registered.ID), nil
+}
+
+// connect returns a client which knows the leader of the topic's partition, once it has one. A connection which failed
+// to authenticate is closed, so each attempt has its own client.
+func (t *smokeTest) connect() (*kafka.Client, kafka.Partition, error) {
+	for i := 0; ; i++ {
+		client := kafka.NewClient("confluent-smoke-test", t.bootstrap, t.apiKey, t.apiSecret)
+		partitions, err := client.Metadata(t.topic)
+		if err == nil && len(partitions) == 0 {
+			err = fmt.Errorf(`topic "%s" has no partitions`, t.topic)
+		}
+		if err == nil && partitions[0].Leader < 0 {
+			err = fmt.Errorf(`partition %d of topic "%s" has no leader`, partitions[0].ID, t.topic)
+		}
+		if err == nil {
+			return client, partitions[0], nil
+		}
+		client.Close()
+		if i == setupRetries {
+			return nil, kafka.Partition{}, err
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// produce produces the records in a batch, acknowledged by every in-sync replica, and returns the offset of the
+// partition before them. The values are in the wire format of Schema Registry serializers, unless there's no schema.
+func (t *smokeTest) produce(client *kafka.Client, p kafka.Partition) (int64, []kafka.Record, error) {
+	offset, err := client.Offset(t.topic, p, kafka.LatestOffset)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	records := make([]kafka.Record, t.records)
+	for i := range records {
+		message := fmt.Sprintf("smoke test %d of %d at %d", i+1, t.records, now)
+		records[i] = kafka.Record{Timestamp: now, Value: t.encode(message)}
+	}
+
+	batch, err := kafka.EncodeBatch(records, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := client.Produce(t.topic, p, batch, -1); err != nil {
+		return 0, nil, err
+	}
+	return offset, records, nil
+}
+
+// encode encodes the message as a record of the value schema: a magic byte and the schema ID, followed by the Avro
+// string, which is its length as a varint and its bytes.
+func (t *smokeTest) encode(message string) []byte {
+	if t.noSchema {
+		return []byte(message)
+	}
+
+	b := binary.BigEndian.AppendUint32([]byte{0}, uint32(t.schemaID))
+	b = binary.AppendVarint(b, int64(len(message)))
+	return append(b, message...)
+}
+
+// consume reads the partition from the offset until every record which was produced is read back, and checks that
+// they're unchanged. It returns the longest time between producing a record and reading it.
+func (t *smokeTest) consume(client *kafka.Client, p kafka.Partition, offset int64, produced []kafka.Record) (time.Duration, error) {
+	deadline := time.Now().Add(t.timeout)
+	var consumed []kafka.Record
+	var consumedAt []time.Time
+	for len(consumed) < len(produced) {
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("only consumed %d of %d records in %s", len(consumed), len(produced), t.timeout)
+		}
+		records, next, err := client.Fetch(t.topic, p, offset)
+		if err != nil {
+			return 0, err
+		}
+		now := time.Now()
+		for _, r := range records {
+			consumed = append(consumed, r)
+			consumedAt = append(consumedAt, now)
+		}
+		offset = next
+	}
+
+	var latency time.Duration
+	for i, r := range produced {
+		c := consumed[i]
+		if !bytes.Equal(c.Value, r.Value) {
+			return 0, fmt.Errorf("record %d was consumed as %q, not %q", i+1, c.Value, r.Value)
+		}
+		if !t.noSchema && (len(c.Value) < 5 || c.Value[0] != 0 || binary.BigEndian.Uint32(c.Value[1:5]) != uint32(t.schemaID)) {
+			return 0, fmt.Errorf("record %d doesn't have schema ID %d", i+1, t.schemaID)
+		}
+		latency = max(latency, consumedAt[i].Sub(time.UnixMilli(r.Timestamp)))
+	}
+	return latency, nil
+}
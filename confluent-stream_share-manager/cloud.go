@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+	"github.com/spf13/cobra"
+)
+
+// cloudURL is the URL of the Confluent Cloud API, which shows the topics of shared resources, since the CLI only shows
+// their IDs.
+var cloudURL = "https://api.confluent.cloud"
+
+const metricsURL = "https://api.telemetry.confluent.cloud/v2/metrics/cloud/query"
+
+// A cloud is a client of the Cloud and Metrics APIs, authenticated with a Cloud API key.
+type cloud struct {
+	key    string
+	secret string
+	client *http.Client
+
+	// topics caches the topics of each shared resource, since shares of the same topics share their resources.
+	topics map[string][]sharedTopic
+}
+
+// newCloud returns a client with the Cloud API key of the flags, or of the environment, which the purpose needs.
+func newCloud(cmd *cobra.Command, purpose string) (*cloud, error) {
+	key, err := cmd.Flags().GetString("cloud-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("cloud-api-secret")
+	cobra.CheckErr(err)
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if key == "" || secret == "" {
+		return nil, exitcode.Invalid("a Cloud API key is required to %s, pass --cloud-api-key and --cloud-api-secret or set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET", purpose)
+	}
+	return &cloud{key: key, secret: secret, client: retry.Client(30 * time.Second), topics: map[string][]sharedTopic{}}, nil
+}
+
+// A sharedTopic is a topic in a shared resource, from its CRN.
+type sharedTopic struct {
+	Environment string `json:"environment"`
+	Cluster     string `json:"cluster"`
+	Topic       string `json:"topic"`
+}
+
+func (t sharedTopic) String() string {
+	return t.Cluster + "/" + t.Topic
+}
+
+// sharedTopics returns the topics of a shared resource. Its other resources, such as schema subjects, are left out.
+func (c *cloud) sharedTopics(id string) ([]sharedTopic, error) {
+	if topics, ok := c.topics[id]; ok {
+		return topics, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, cloudURL+"/cdx/v1/provider-shared-resources/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.key, c.secret)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to describe shared resource %s: %s", id, res.Status)
+	}
+
+	var resource struct {
+		CRNs []string `json:"crns"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resource); err != nil {
+		return nil, fmt.Errorf("failed to parse shared resource %s: %w", id, err)
+	}
+
+	var topics []sharedTopic
+	for _, crn := range resource.CRNs {
+		var t sharedTopic
+		for _, part := range strings.Split(strings.TrimPrefix(crn, "crn://"), "/") {
+			key, value, _ := strings.Cut(part, "=")
+			switch key {
+			case "environment":
+				t.Environment = value
+			case "kafka":
+				t.Cluster = value
+			case "topic":
+				t.Topic = value
+			}
+		}
+		if t.Topic != "" {
+			topics = append(topics, t)
+		}
+	}
+	c.topics[id] = topics
+	return topics, nil
+}
+
+// principalPoint is a metric of a principal.
+type principalPoint struct {
+	PrincipalID string  `json:"metric.principal_id"`
+	Value       float64 `json:"value"`
+}
+
+// consumedBytes returns how many bytes each principal consumed from the topic in the last days, with the Metrics API.
+func (c *cloud) consumedBytes(t sharedTopic, days int) (map[string]float64, error) {
+	query := map[string]any{
+		"aggregations": []map[string]string{{"metric": "io.confluent.kafka.server/sent_bytes"}},
+		"filter": map[string]any{
+			"op": "AND",
+			"filters": []map[string]string{
+				{"field": "resource.kafka.id", "op": "EQ", "value": t.Cluster},
+				{"field": "metric.topic", "op": "EQ", "value": t.Topic},
+			},
+		},
+		"granularity": "ALL",
+		"intervals":   []string{fmt.Sprintf("now-%dd/now", days)},
+		"group_by":    []string{"metric.principal_id"},
+		"limit":       1000,
+	}
+	b, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := paging.All(func(token string) ([]principalPoint, string, error) {
+		req, err := http.NewRequest(http.MethodPost, paging.WithToken(metricsURL, token), bytes.NewReader(b))
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(c.key, c.secret)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("failed to query the metrics of %s: %s", t, res.Status)
+		}
+
+		var page paging.MetricsPage[principalPoint]
+		if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+			return nil, "", fmt.Errorf("failed to parse the metrics of %s: %w", t, err)
+		}
+		return page.Data, page.Next(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	consumed := map[string]float64{}
+	for _, d := range points {
+		consumed[d.PrincipalID] += d.Value
+	}
+	return consumed, nil
+}
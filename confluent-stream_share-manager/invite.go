@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newInviteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invite",
+		Short: "Share topics with the recipients of a CSV file.",
+		Long:  "Create a provider share of each topic for each recipient of a CSV file, which sends them an invitation by email. The file has a header, and a row per recipient with their email in the \"email\" column, and optionally the topics to share with them in a \"topics\" column, separated by semicolons, instead of --topic.",
+		Args:  cobra.NoArgs,
+		RunE:  invite,
+		Example: `confluent stream-share manager invite --recipients recipients.csv --topic orders,payments --share-schemas
+confluent stream-share manager invite --recipients recipients.csv --cluster lkc-123456 --environment env-123456 --dry-run`,
+	}
+
+	cmd.Flags().String("recipients", "", "CSV file of the recipients.")
+	cmd.Flags().StringSlice("topic", nil, "Topics to share with recipients whose row doesn't list any.")
+	cmd.Flags().String("cluster", "", "Kafka cluster ID of the topics. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().Bool("share-schemas", false, `Also share the "<topic>-key" and "<topic>-value" subjects of each topic which exist.`)
+	dryrun.AddFlag(cmd, "Print the invitations which would be sent without sending them.")
+	output.AddFlag(cmd, "Format of the invitations")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("recipients"))
+
+	return cmd
+}
+
+// A recipient is a row of the CSV file, with the topics to share with them.
+type recipient struct {
+	email  string
+	topics []string
+}
+
+// An invitation is the share of a topic with a recipient, and the result of creating it.
+type invitation struct {
+	Email  string `json:"email"`
+	Topic  string `json:"topic"`
+	Share  string `json:"share,omitempty"`
+	Result string `json:"result"`
+}
+
+func invite(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	path, err := cmd.Flags().GetString("recipients")
+	cobra.CheckErr(err)
+
+	topics, err := cmd.Flags().GetStringSlice("topic")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	shareSchemas, err := cmd.Flags().GetBool("share-schemas")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := readRecipients(path, topics)
+	if err != nil {
+		return err
+	}
+
+	// The topics are checked before any invitation is sent, so that a typo doesn't leave half of the recipients invited.
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, cli.ClusterFlags(cluster, environment)...)...); err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, t := range listed {
+		existing[t.Name] = true
+	}
+	for _, r := range recipients {
+		for _, topic := range r.topics {
+			if !existing[topic] {
+				return fmt.Errorf(`topic "%s" of %s doesn't exist`, topic, r.email)
+			}
+		}
+	}
+
+	var subjects []string
+	if shareSchemas {
+		if err := cli.JSON(&subjects, append([]string{"schema-registry", "subject", "list"}, cli.ClusterFlags("", environment)...)...); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, r := range recipients {
+			for _, topic := range r.topics {
+				plan.AddDetail("share", "topic", topic, "with "+r.email)
+			}
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	invitations := []invitation{}
+	var errs []error
+	for _, r := range recipients {
+		for _, topic := range r.topics {
+			i := invitation{Email: r.email, Topic: topic}
+
+			args := append([]string{"stream-share", "provider", "invite", "create", "--email", r.email, "--topic", topic}, cli.ClusterFlags(cluster, environment)...)
+			var shared []string
+			for _, subject := range []string{topic + "-key", topic + "-value"} {
+				if slices.Contains(subjects, subject) {
+					shared = append(shared, subject)
+				}
+			}
+			if len(shared) > 0 {
+				args = append(args, "--schema-registry-subjects", strings.Join(shared, ","))
+			}
+
+			var created struct {
+				ID string `json:"id"`
+			}
+			if err := cli.JSON(&created, args...); err != nil {
+				i.Result = "failed"
+				errs = append(errs, fmt.Errorf(`failed to share topic "%s" with %s: %w`, topic, r.email, err))
+			} else {
+				i.Share = created.ID
+				i.Result = "invited"
+			}
+			invitations = append(invitations, i)
+		}
+	}
+
+	err = output.Print(cmd.OutOrStdout(), format, invitations, func() {
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Email\tTopic\tShare\tResult")
+		for _, i := range invitations {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", i.Email, i.Topic, i.Share, i.Result)
+		}
+		_ = tw.Flush()
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// readRecipients reads the CSV file of recipients, whose rows without topics get the default topics.
+func readRecipients(path string, defaultTopics []string) ([]recipient, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	recipients, err := readCSV(f, defaultTopics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("%s has no recipients", path)
+	}
+	return recipients, nil
+}
+
+func readCSV(r io.Reader, defaultTopics []string) ([]recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	emailColumn, topicsColumn := -1, -1
+	for i, column := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "email":
+			emailColumn = i
+		case "topics":
+			topicsColumn = i
+		}
+	}
+	if emailColumn < 0 {
+		return nil, fmt.Errorf(`the header has no "email" column`)
+	}
+
+	var recipients []recipient
+	seen := map[string]bool{}
+	for line, record := range records[1:] {
+		if emailColumn >= len(record) || strings.TrimSpace(record[emailColumn]) == "" {
+			return nil, fmt.Errorf("line %d: the email is empty", line+2)
+		}
+		address, err := mail.ParseAddress(strings.TrimSpace(record[emailColumn]))
+		if err != nil {
+			return nil, fmt.Errorf(`line %d: invalid email "%s"`, line+2, record[emailColumn])
+		}
+		if seen[address.Address] {
+			return nil, fmt.Errorf("line %d: %s is listed more than once", line+2, address.Address)
+		}
+		seen[address.Address] = true
+
+		r := recipient{email: address.Address}
+		if topicsColumn >= 0 && topicsColumn < len(record) {
+			for _, topic := range strings.Split(record[topicsColumn], ";") {
+				if topic = strings.TrimSpace(topic); topic != "" {
+					r.topics = append(r.topics, topic)
+				}
+			}
+		}
+		if len(r.topics) == 0 {
+			r.topics = defaultTopics
+		}
+		if len(r.topics) == 0 {
+			return nil, fmt.Errorf("line %d: no topics to share with %s, list them in the topics column or pass --topic", line+2, r.email)
+		}
+		recipients = append(recipients, r)
+	}
+	return recipients, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the provider shares with their topics.",
+		Long:  "List the provider shares of the organization with their consumers, status, and the topics they share, which the CLI only shows the IDs of.",
+		Args:  cobra.NoArgs,
+		RunE:  list,
+		Example: `confluent stream-share manager list
+confluent stream-share manager list --topic orders --output json`,
+	}
+
+	cmd.Flags().String("topic", "", "Only list the shares of this topic, by its name or by <cluster>/<name>.")
+	cmd.Flags().String("status", "", "Only list the shares with this status.")
+	output.AddFlag(cmd, "Format of the list")
+
+	return cmd
+}
+
+func list(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	topic, err := cmd.Flags().GetString("topic")
+	cobra.CheckErr(err)
+
+	status, err := cmd.Flags().GetString("status")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	c, err := newCloud(cmd, "look up the topics of shares")
+	if err != nil {
+		return err
+	}
+	listed, err := shares(c)
+	if err != nil {
+		return err
+	}
+
+	matched := []share{}
+	for _, s := range listed {
+		if (topic == "" || s.sharesTopic(topic)) && (status == "" || strings.EqualFold(s.Status, status)) {
+			matched = append(matched, s)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		return output.Print(out, format, matched, nil)
+	}
+
+	if len(matched) == 0 {
+		fmt.Fprintln(out, "No shares found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Share\tConsumer\tStatus\tTopics\tInvited\tRedeemed")
+	for _, s := range matched {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.consumer(), s.Status, s.topicNames(), s.InvitedAt, s.RedeemedAt)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	_ "embed"
+
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "manager",
+		Short: "Manage stream shares in bulk.",
+		Long:  "Share topics with a list of recipients with \"confluent stream-share manager invite\", list the shares of the organization with their topics with \"confluent stream-share manager list\", revoke them with \"confluent stream-share manager revoke\", and find which shared topics are consumed with \"confluent stream-share manager report\".",
+		Args:  cobra.NoArgs,
+		Example: `confluent stream-share manager invite --recipients recipients.csv --topic orders,payments --cluster lkc-123456
+confluent stream-share manager report --days 30`,
+	}
+
+	cmd.PersistentFlags().String("cloud-api-key", "", "Cloud API key to look up the topics of shares with, and query the Metrics API with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.PersistentFlags().String("cloud-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cmd.AddCommand(newInviteCommand())
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newRevokeCommand())
+	cmd.AddCommand(newReportCommand())
+
+	completion.Add(&cmd, "confluent-stream_share-manager")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-stream_share-manager"))
+
+	exitcode.Execute(&cmd)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newReportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report which shared topics have active consumers.",
+		Long:  "Report, for each shared topic, how many shares it has, how many of them were redeemed, and which consumers read from it in the last days, from the bytes which the service account of each share consumed according to the Metrics API.",
+		Args:  cobra.NoArgs,
+		RunE:  report,
+		Example: `confluent stream-share manager report
+confluent stream-share manager report --days 30 --output json`,
+	}
+
+	cmd.Flags().Int("days", 7, "How many days back to look for consumption.")
+	output.AddFlag(cmd, "Format of the report")
+
+	return cmd
+}
+
+// topicUsage is how a shared topic is consumed through its shares.
+type topicUsage struct {
+	sharedTopic
+	Shares            int      `json:"shares"`
+	Redeemed          int      `json:"redeemed"`
+	ActiveConsumers   []string `json:"active_consumers"`
+	InactiveConsumers []string `json:"inactive_consumers"`
+	ConsumedBytes     float64  `json:"consumed_bytes"`
+}
+
+func report(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	days, err := cmd.Flags().GetInt("days")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if days < 1 {
+		return exitcode.Invalid("--days must be at least 1")
+	}
+
+	c, err := newCloud(cmd, "look up the topics of shares and query the Metrics API")
+	if err != nil {
+		return err
+	}
+	listed, err := shares(c)
+	if err != nil {
+		return err
+	}
+
+	usages := map[sharedTopic]*topicUsage{}
+	sharesOf := map[sharedTopic][]share{}
+	for _, s := range listed {
+		for _, t := range s.Topics {
+			if usages[t] == nil {
+				usages[t] = &topicUsage{sharedTopic: t, ActiveConsumers: []string{}, InactiveConsumers: []string{}}
+			}
+			sharesOf[t] = append(sharesOf[t], s)
+		}
+	}
+
+	var topics []*topicUsage
+	for t, u := range usages {
+		consumed, err := c.consumedBytes(t, days)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range sharesOf[t] {
+			u.Shares++
+			if !s.redeemed() {
+				continue
+			}
+			u.Redeemed++
+			if bytes := consumed[s.ServiceAccount]; bytes > 0 {
+				u.ActiveConsumers = append(u.ActiveConsumers, s.consumer())
+				u.ConsumedBytes += bytes
+			} else {
+				u.InactiveConsumers = append(u.InactiveConsumers, s.consumer())
+			}
+		}
+		topics = append(topics, u)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].String() < topics[j].String()
+	})
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if topics == nil {
+			topics = []*topicUsage{}
+		}
+		return output.Print(out, format, topics, nil)
+	}
+	return print(out, topics, days)
+}
+
+func print(w io.Writer, topics []*topicUsage, days int) error {
+	if len(topics) == 0 {
+		fmt.Fprintln(w, "No topics are shared.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Topic\tCluster\tShares\tRedeemed\tActive Consumers\tConsumed (%dd)\n", days)
+	for _, u := range topics {
+		active := "-"
+		if len(u.ActiveConsumers) > 0 {
+			active = strings.Join(u.ActiveConsumers, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%.1f MB\n", u.Topic, u.Cluster, u.Shares, u.Redeemed, active, u.ConsumedBytes/1e6)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newRevokeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke [share-id]...",
+		Short: "Revoke provider shares.",
+		Long:  "Delete the provider shares with the IDs, of a topic, or of a consumer, after prompting for confirmation, which revokes the consumers' access to the topics and expires invitations which weren't redeemed.",
+		Args:  cobra.ArbitraryArgs,
+		RunE:  revoke,
+		Example: `confluent stream-share manager revoke ss-123456 ss-654321
+confluent stream-share manager revoke --topic orders --not-redeemed --dry-run`,
+	}
+
+	cmd.Flags().String("topic", "", "Revoke the shares of this topic, by its name or by <cluster>/<name>.")
+	cmd.Flags().String("consumer", "", "Revoke the shares of this consumer, by its name or the name of its organization.")
+	cmd.Flags().Bool("not-redeemed", false, "Only revoke the shares whose invitation wasn't redeemed.")
+	dryrun.AddFlag(cmd, "Print what would be revoked without revoking it.")
+	cmd.Flags().Bool("force", false, "Revoke without prompting for confirmation.")
+	output.AddFlag(cmd, "Format of the shares which were revoked")
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	return cmd
+}
+
+func revoke(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	topic, err := cmd.Flags().GetString("topic")
+	cobra.CheckErr(err)
+
+	consumer, err := cmd.Flags().GetString("consumer")
+	cobra.CheckErr(err)
+
+	notRedeemed, err := cmd.Flags().GetBool("not-redeemed")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	if len(args) == 0 && topic == "" && consumer == "" && !notRedeemed {
+		return exitcode.Invalid("pass the IDs of the shares to revoke, or at least one of --topic, --consumer, or --not-redeemed")
+	}
+
+	// The topics of shares are only looked up when they're needed to select them.
+	var c *cloud
+	if topic != "" {
+		if c, err = newCloud(cmd, "look up the topics of shares"); err != nil {
+			return err
+		}
+	}
+	listed, err := shares(c)
+	if err != nil {
+		return err
+	}
+
+	var revoked []share
+	for _, s := range listed {
+		if len(args) > 0 && !slices.Contains(args, s.ID) {
+			continue
+		}
+		if topic != "" && !s.sharesTopic(topic) {
+			continue
+		}
+		if consumer != "" && !strings.EqualFold(s.ConsumerName, consumer) && !strings.EqualFold(s.ConsumerOrganizationName, consumer) {
+			continue
+		}
+		if notRedeemed && s.redeemed() {
+			continue
+		}
+		revoked = append(revoked, s)
+	}
+	for _, id := range args {
+		if !slices.ContainsFunc(listed, func(s share) bool { return s.ID == id }) {
+			return fmt.Errorf(`share "%s" not found`, id)
+		}
+	}
+
+	out := output.Progress(cmd)
+	if len(revoked) == 0 {
+		fmt.Fprintln(out, "No shares to revoke.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	descriptions := make([]string, len(revoked))
+	var plan dryrun.Plan
+	for i, s := range revoked {
+		if s.redeemed() {
+			descriptions[i] = fmt.Sprintf("share %s of %s", s.ID, s.consumer())
+			plan.AddDetail("revoke", "share", s.ID, "of "+s.consumer())
+		} else {
+			descriptions[i] = fmt.Sprintf("share %s, which wasn't redeemed", s.ID)
+			plan.AddDetail("revoke", "share", s.ID, "(not redeemed)")
+		}
+	}
+
+	if dryRun {
+		return dryrun.Print(cmd, plan)
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to revoke %s?", strings.Join(descriptions, ", ")))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not revoking anything.")
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+	}
+
+	var errs []error
+	var done dryrun.Plan
+	for i, s := range revoked {
+		if _, err := cli.Run("stream-share", "provider", "share", "delete", s.ID, "--force"); err != nil {
+			errs = append(errs, fmt.Errorf("failed to revoke %s: %w", descriptions[i], err))
+			continue
+		}
+		fmt.Fprintf(out, "Revoked %s.\n", descriptions[i])
+		done.Actions = append(done.Actions, plan.Actions[i])
+	}
+	return errors.Join(append(errs, dryrun.PrintApplied(cmd, done))...)
+}
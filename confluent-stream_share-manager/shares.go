@@ -0,0 +1,80 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// share is a provider share in the output of "confluent stream-share provider share list", with the topics of its
+// shared resources.
+type share struct {
+	ID                       string   `json:"id"`
+	ConsumerName             string   `json:"consumer_name"`
+	ConsumerOrganizationName string   `json:"consumer_organization_name"`
+	Status                   string   `json:"status"`
+	DeliveryMethod           string   `json:"delivery_method"`
+	ServiceAccount           string   `json:"service_account"`
+	SharedResources          []string `json:"shared_resources"`
+	InvitedAt                string   `json:"invited_at"`
+	RedeemedAt               string   `json:"redeemed_at"`
+	InviteExpiresAt          string   `json:"invite_expires_at"`
+
+	Topics []sharedTopic `json:"topics"`
+}
+
+// consumer returns the name of the share's consumer, or that it hasn't redeemed its invite yet.
+func (s share) consumer() string {
+	switch {
+	case s.ConsumerOrganizationName != "" && s.ConsumerName != "":
+		return s.ConsumerName + " (" + s.ConsumerOrganizationName + ")"
+	case s.ConsumerName != "":
+		return s.ConsumerName
+	case s.ConsumerOrganizationName != "":
+		return s.ConsumerOrganizationName
+	default:
+		return "(not redeemed)"
+	}
+}
+
+func (s share) redeemed() bool {
+	return s.RedeemedAt != ""
+}
+
+// shares returns the provider shares of the organization. Their topics are looked up with the Cloud API, unless c is
+// nil.
+func shares(c *cloud) ([]share, error) {
+	var listed []share
+	if err := cli.JSON(&listed, "stream-share", "provider", "share", "list"); err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		for i, s := range listed {
+			for _, id := range s.SharedResources {
+				topics, err := c.sharedTopics(id)
+				if err != nil {
+					return nil, err
+				}
+				listed[i].Topics = append(listed[i].Topics, topics...)
+			}
+		}
+	}
+	return listed, nil
+}
+
+// sharesTopic returns whether the share shares the topic, by its name or by <cluster>/<name>.
+func (s share) sharesTopic(topic string) bool {
+	return slices.ContainsFunc(s.Topics, func(t sharedTopic) bool {
+		return t.Topic == topic || t.String() == topic
+	})
+}
+
+func (s share) topicNames() string {
+	names := make([]string, len(s.Topics))
+	for i, t := range s.Topics {
+		names[i] = t.Topic
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// icebergCatalog is a client of the Iceberg REST catalog of Tableflow, which serves the tables of an environment, in a
+// namespace per Kafka cluster.
+type icebergCatalog struct {
+	endpoint    string
+	key, secret string
+	client      *http.Client
+	token       string
+}
+
+func icebergEndpoint(cloud, region, organization, environment string) string {
+	return fmt.Sprintf("https://tableflow.%s.%s.confluent.cloud/iceberg/catalog/organizations/%s/environments/%s", region, strings.ToLower(cloud), organization, environment)
+}
+
+func newIcebergCatalog(endpoint, key, secret string) *icebergCatalog {
+	return &icebergCatalog{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		key:      key,
+		secret:   secret,
+		client:   retry.Client(30 * time.Second),
+	}
+}
+
+// authenticate exchanges the Tableflow API key for an access token of the catalog.
+func (c *icebergCatalog) authenticate() error {
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {c.key}, "client_secret": {c.secret}, "scope": {"catalog"}}
+	res, err := c.client.PostForm(c.endpoint+"/v1/oauth/tokens", form)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to authenticate to the Iceberg catalog: %s", res.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to parse the access token of the Iceberg catalog: %w", err)
+	}
+	c.token = token.AccessToken
+	return nil
+}
+
+// table returns the number of snapshots of the table of a topic, and whether the table exists yet.
+func (c *icebergCatalog) table(cluster, topic string) (int, bool, error) {
+	if c.token == "" {
+		if err := c.authenticate(); err != nil {
+			return 0, false, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.endpoint+"/v1/namespaces/"+url.PathEscape(cluster)+"/tables/"+url.PathEscape(topic), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return 0, false, nil
+	default:
+		return 0, false, fmt.Errorf(`failed to load the table of topic "%s": %s`, topic, res.Status)
+	}
+
+	var table struct {
+		Metadata struct {
+			Snapshots []json.RawMessage `json:"snapshots"`
+		} `json:"metadata"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&table); err != nil {
+		return 0, false, fmt.Errorf(`failed to parse the table of topic "%s": %w`, topic, err)
+	}
+	return len(table.Metadata.Snapshots), true, nil
+}
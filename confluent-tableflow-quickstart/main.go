@@ -0,0 +1,359 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "quickstart",
+		Short: "Enable Tableflow on topics and check that their Iceberg tables materialize.",
+		Long:  "Enable Tableflow on topics of a Kafka cluster, with storage managed by Confluent or in an S3 bucket, optionally sync their tables to AWS Glue or Snowflake with a catalog integration, and wait until the Iceberg tables materialize. Everything which is set up can be removed with \"confluent tableflow quickstart teardown\".",
+		Args:  cobra.NoArgs,
+		RunE:  quickstart,
+		Example: `confluent tableflow quickstart --topic orders,users --tableflow-api-key ABCDEFGHIJKLMNOP --tableflow-api-secret ...
+confluent tableflow quickstart --topic orders --bucket my-tables --provider-integration cspi-123456 --catalog glue --glue-provider-integration cspi-234567`,
+	}
+
+	cmd.PersistentFlags().StringSlice("topic", nil, "Topics to enable or disable Tableflow on.")
+	cmd.PersistentFlags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.PersistentFlags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.PersistentFlags().String("catalog-name", "tableflow-quickstart", "Name of the catalog integration.")
+	cmd.PersistentFlags().String("cloud-api-key", "", "Cloud API key to manage Tableflow with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.PersistentFlags().String("cloud-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+
+	cmd.Flags().String("bucket", "", "S3 bucket to store the tables in. Defaults to storage managed by Confluent.")
+	cmd.Flags().String("provider-integration", "", "ID of the provider integration which gives Tableflow access to the bucket.")
+	cmd.Flags().String("catalog", "none", "Catalog to sync the tables to: none, glue, or snowflake.")
+	cmd.Flags().String("glue-provider-integration", "", "ID of the provider integration which gives Tableflow access to AWS Glue.")
+	cmd.Flags().String("snowflake-endpoint", "", "URL of the Snowflake Open Catalog.")
+	cmd.Flags().String("snowflake-warehouse", "", "Snowflake Open Catalog warehouse to sync the tables to.")
+	cmd.Flags().String("snowflake-scope", "", "Scope of the Snowflake Open Catalog principal role, such as PRINCIPAL_ROLE:tableflow.")
+	cmd.Flags().String("snowflake-client-id", "", "Client ID of the Snowflake Open Catalog service connection.")
+	cmd.Flags().String("snowflake-client-secret", "", "Client secret of the Snowflake Open Catalog service connection. Defaults to $SNOWFLAKE_CLIENT_SECRET.")
+	cmd.Flags().String("tableflow-api-key", "", "Tableflow API key to check the Iceberg tables with. Defaults to $CONFLUENT_TABLEFLOW_API_KEY.")
+	cmd.Flags().String("tableflow-api-secret", "", "Secret of the Tableflow API key. Defaults to $CONFLUENT_TABLEFLOW_API_SECRET.")
+	cmd.Flags().String("iceberg-endpoint", "", "URL of the Iceberg REST catalog of Tableflow. Defaults to the one of the cluster's region.")
+	cmd.Flags().Duration("timeout", 30*time.Minute, "How long to wait for the tables to materialize.")
+	cmd.Flags().Bool("no-wait", false, "Don't wait for the tables to materialize.")
+	output.AddFlag(&cmd, "Format of the topics and their tables")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkPersistentFlagRequired("topic"))
+	cmd.MarkFlagsRequiredTogether("bucket", "provider-integration")
+
+	cmd.AddCommand(newTeardownCommand())
+
+	completion.Add(&cmd, "confluent-tableflow-quickstart")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-tableflow-quickstart"))
+
+	interrupt.Trap()
+	exitcode.Execute(&cmd)
+}
+
+// A target is the topics of a cluster which Tableflow is set up for.
+type target struct {
+	environment  string
+	organization string
+	cloud        string
+	region       string
+	topics       []string
+	catalogName  string
+	tableflow    *tableflow
+}
+
+// newTarget reads the persistent flags, and looks up the cluster and environment.
+func newTarget(cmd *cobra.Command) (target, error) {
+	topics, err := cmd.Flags().GetStringSlice("topic")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	catalogName, err := cmd.Flags().GetString("catalog-name")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("cloud-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("cloud-api-secret")
+	cobra.CheckErr(err)
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if key == "" || secret == "" {
+		return target{}, exitcode.Invalid("a Cloud API key is required to manage Tableflow, pass --cloud-api-key and --cloud-api-secret or set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	environmentArgs := []string{"environment", "describe"}
+	if environment != "" {
+		environmentArgs = append(environmentArgs, environment)
+	}
+	var env, organization struct {
+		ID string `json:"id"`
+	}
+	if err := cli.JSON(&env, environmentArgs...); err != nil {
+		return target{}, err
+	}
+	if err := cli.JSON(&organization, "organization", "describe"); err != nil {
+		return target{}, err
+	}
+
+	described, err := cli.DescribeKafkaCluster(cluster, env.ID)
+	if err != nil {
+		return target{}, err
+	}
+
+	return target{
+		environment:  env.ID,
+		organization: organization.ID,
+		cloud:        described.Cloud,
+		region:       described.Region,
+		topics:       topics,
+		catalogName:  catalogName,
+		tableflow:    newTableflow(env.ID, described.ID, key, secret),
+	}, nil
+}
+
+func (t target) scope() []string {
+	return cli.ClusterFlags(t.tableflow.cluster, t.environment)
+}
+
+func quickstart(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	bucket, err := cmd.Flags().GetString("bucket")
+	cobra.CheckErr(err)
+
+	providerIntegration, err := cmd.Flags().GetString("provider-integration")
+	cobra.CheckErr(err)
+
+	catalog, err := cmd.Flags().GetString("catalog")
+	cobra.CheckErr(err)
+
+	tableflowKey, err := cmd.Flags().GetString("tableflow-api-key")
+	cobra.CheckErr(err)
+
+	tableflowSecret, err := cmd.Flags().GetString("tableflow-api-secret")
+	cobra.CheckErr(err)
+
+	icebergURL, err := cmd.Flags().GetString("iceberg-endpoint")
+	cobra.CheckErr(err)
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	cobra.CheckErr(err)
+
+	noWait, err := cmd.Flags().GetBool("no-wait")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	catalogConfig, err := catalogIntegrationConfig(cmd, catalog)
+	if err != nil {
+		return err
+	}
+
+	if tableflowKey == "" {
+		tableflowKey = os.Getenv("CONFLUENT_TABLEFLOW_API_KEY")
+	}
+	if tableflowSecret == "" {
+		tableflowSecret = os.Getenv("CONFLUENT_TABLEFLOW_API_SECRET")
+	}
+	if tableflowKey != "" && tableflowSecret == "" {
+		return exitcode.Invalid("--tableflow-api-secret or CONFLUENT_TABLEFLOW_API_SECRET is required with --tableflow-api-key")
+	}
+
+	t, err := newTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	var existing []cli.Topic
+	if err := cli.JSON(&existing, append([]string{"kafka", "topic", "list"}, t.scope()...)...); err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, e := range existing {
+		names[e.Name] = true
+	}
+	for _, topic := range t.topics {
+		if !names[topic] {
+			return fmt.Errorf(`topic "%s" doesn't exist in Kafka cluster "%s"`, topic, t.tableflow.cluster)
+		}
+	}
+
+	// The topics which Tableflow was enabled on, and the catalog integration, are reported if the quickstart fails or
+	// is interrupted before the tables materialize.
+	var ledger interrupt.Ledger
+	out := output.Progress(cmd)
+	s := storage{bucket: bucket, providerIntegration: providerIntegration}
+	for _, topic := range t.topics {
+		if err := interrupt.Err(); err != nil {
+			return ledger.Report(cmd.ErrOrStderr(), err)
+		}
+		_, created, err := t.tableflow.enable(topic, s)
+		if err != nil {
+			return ledger.Report(cmd.ErrOrStderr(), err)
+		}
+		if created {
+			fmt.Fprintf(out, "Enabled Tableflow on topic \"%s\".\n", topic)
+			ledger.Add("Tableflow topic", topic, "", append([]string{"tableflow", "topic", "disable", topic}, t.scope()...)...)
+		} else {
+			fmt.Fprintf(out, "Tableflow is already enabled on topic \"%s\".\n", topic)
+		}
+	}
+
+	var integration *catalogIntegration
+	if catalogConfig != nil {
+		if integration, err = t.tableflow.findCatalogIntegration(t.catalogName); err != nil {
+			return err
+		}
+		if integration == nil {
+			created, err := t.tableflow.createCatalogIntegration(t.catalogName, catalogConfig)
+			if err != nil {
+				return ledger.Report(cmd.ErrOrStderr(), err)
+			}
+			integration = &created
+			fmt.Fprintf(out, "Created %s catalog integration \"%s\" (%s).\n", catalog, t.catalogName, created.ID)
+			ledger.Add("catalog integration", t.catalogName, created.ID, append([]string{"tableflow", "catalog-integration", "delete", created.ID}, t.scope()...)...)
+		} else {
+			fmt.Fprintf(out, "Using catalog integration \"%s\" (%s).\n", t.catalogName, integration.ID)
+		}
+	}
+
+	r := report{Topics: t.topics}
+	if integration != nil {
+		r.CatalogIntegration = integration.ID
+	}
+	if noWait {
+		return output.Print(cmd.OutOrStdout(), format, r, nil)
+	}
+
+	var iceberg *icebergCatalog
+	if tableflowKey != "" {
+		if icebergURL == "" {
+			icebergURL = icebergEndpoint(t.cloud, t.region, t.organization, t.environment)
+		}
+		iceberg = newIcebergCatalog(icebergURL, tableflowKey, tableflowSecret)
+	}
+
+	spinner := progress.Spin(out, "Waiting for the tables to materialize")
+	v := validator{target: t, iceberg: iceberg, deadline: time.Now().Add(timeout), spinner: spinner}
+	results, err := v.validate(integration)
+	spinner.Stop()
+	if err != nil {
+		return ledger.Report(cmd.ErrOrStderr(), err)
+	}
+
+	failed := 0
+	for _, res := range results {
+		if res.err != nil {
+			failed++
+		}
+		r.Tables = append(r.Tables, res.report())
+	}
+	err = output.Print(cmd.OutOrStdout(), format, r, func() {
+		fmt.Fprintln(out)
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Topic\tPhase\tTable\tTable Path")
+		for _, res := range results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", res.topic, res.phase, res.table(), res.path)
+		}
+		_ = tw.Flush()
+	})
+
+	if iceberg == nil {
+		fmt.Fprintln(out, "\nThe Iceberg tables weren't checked, pass --tableflow-api-key and --tableflow-api-secret to check them.")
+	}
+	if failed > 0 {
+		return errors.Join(fmt.Errorf("%d of %d tables didn't materialize", failed, len(results)), err)
+	}
+	return err
+}
+
+// report is what --output json or yaml prints: the topics which Tableflow is enabled on, the catalog integration
+// which their tables are synced with, and, unless --no-wait, whether the tables materialized.
+type report struct {
+	Topics             []string      `json:"topics"`
+	CatalogIntegration string        `json:"catalog_integration,omitempty"`
+	Tables             []tableReport `json:"tables,omitempty"`
+}
+
+type tableReport struct {
+	Topic     string `json:"topic"`
+	Phase     string `json:"phase"`
+	TablePath string `json:"table_path"`
+	// Snapshots is only set if the Iceberg table was checked.
+	Snapshots *int   `json:"snapshots,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// catalogIntegrationConfig returns the config of the catalog integration, or nil if the tables aren't synced to a
+// catalog.
+func catalogIntegrationConfig(cmd *cobra.Command, catalog string) (map[string]string, error) {
+	switch catalog {
+	case "none":
+		return nil, nil
+	case "glue":
+		providerIntegration, err := cmd.Flags().GetString("glue-provider-integration")
+		cobra.CheckErr(err)
+		if providerIntegration == "" {
+			return nil, exitcode.Invalid("--glue-provider-integration is required with --catalog glue")
+		}
+		return map[string]string{"kind": "AwsGlue", "provider_integration_id": providerIntegration}, nil
+	case "snowflake":
+		config := map[string]string{"kind": "Snowflake"}
+		for _, f := range []struct{ flag, key string }{
+			{"snowflake-endpoint", "endpoint"},
+			{"snowflake-warehouse", "warehouse"},
+			{"snowflake-scope", "allowed_scope"},
+			{"snowflake-client-id", "client_id"},
+			{"snowflake-client-secret", "client_secret"},
+		} {
+			value, err := cmd.Flags().GetString(f.flag)
+			cobra.CheckErr(err)
+			if value == "" && f.flag == "snowflake-client-secret" {
+				value = os.Getenv("SNOWFLAKE_CLIENT_SECRET")
+			}
+			if value == "" {
+				return nil, exitcode.Invalid("--%s is required with --catalog snowflake", f.flag)
+			}
+			config[f.key] = value
+		}
+		return config, nil
+	default:
+		return nil, fmt.Errorf(`unsupported catalog "%s", supported catalogs: none, glue, snowflake`, catalog)
+	}
+}
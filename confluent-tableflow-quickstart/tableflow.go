@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// cloudURL is the URL of the Confluent Cloud API, which manages Tableflow, since the CLI can't.
+var cloudURL = "https://api.confluent.cloud"
+
+// tableflow is a client of the Tableflow API, scoped to the topics of a Kafka cluster.
+type tableflow struct {
+	environment string
+	cluster     string
+	key, secret string
+	client      *http.Client
+}
+
+func newTableflow(environment, cluster, key, secret string) *tableflow {
+	return &tableflow{
+		environment: environment,
+		cluster:     cluster,
+		key:         key,
+		secret:      secret,
+		client:      retry.Client(30 * time.Second),
+	}
+}
+
+// apiError is an error response of the Confluent Cloud API.
+type apiError struct {
+	status  int
+	details []string
+}
+
+func (e *apiError) Error() string {
+	if len(e.details) == 0 {
+		return http.StatusText(e.status)
+	}
+	return strings.Join(e.details, "; ")
+}
+
+func notFound(err error) bool {
+	e, ok := err.(*apiError)
+	return ok && e.status == http.StatusNotFound
+}
+
+func (t *tableflow) do(method, path string, body, v any) (err error) {
+	defer func() { audit.Request(method, "/tableflow/v1/"+path, err) }()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	// The path may have a query of its own, such as the token of a page.
+	path, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return err
+	}
+	query.Set("environment", t.environment)
+	query.Set("spec.kafka_cluster", t.cluster)
+	req, err := http.NewRequest(method, cloudURL+"/tableflow/v1/"+path+"?"+query.Encode(), reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.key, t.secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		var e struct {
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&e)
+		err := &apiError{status: res.StatusCode}
+		for _, d := range e.Errors {
+			err.details = append(err.details, d.Detail)
+		}
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func (t *tableflow) scope() map[string]any {
+	return map[string]any{
+		"environment":   map[string]string{"id": t.environment},
+		"kafka_cluster": map[string]string{"id": t.cluster},
+	}
+}
+
+// storage is where Tableflow writes the tables of a topic, either storage which Confluent manages, or an S3 bucket
+// which it's given access to by a provider integration.
+type storage struct {
+	bucket              string
+	providerIntegration string
+}
+
+func (s storage) spec() map[string]string {
+	if s.bucket == "" {
+		return map[string]string{"kind": "Managed"}
+	}
+	return map[string]string{"kind": "ByobAws", "bucket_name": s.bucket, "provider_integration_id": s.providerIntegration}
+}
+
+// tableflowTopic is a topic which Tableflow materializes as an Iceberg table.
+type tableflowTopic struct {
+	Spec struct {
+		DisplayName string `json:"display_name"`
+		Suspended   bool   `json:"suspended"`
+		Storage     struct {
+			Kind      string `json:"kind"`
+			TablePath string `json:"table_path"`
+		} `json:"storage"`
+	} `json:"spec"`
+	Status struct {
+		Phase        string `json:"phase"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"status"`
+}
+
+// enable enables Tableflow on the topic, or returns the Tableflow topic if it's already enabled.
+func (t *tableflow) enable(topic string, s storage) (tableflowTopic, bool, error) {
+	existing, err := t.describe(topic)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !notFound(err) {
+		return tableflowTopic{}, false, err
+	}
+
+	spec := t.scope()
+	spec["display_name"] = topic
+	spec["table_formats"] = []string{"ICEBERG"}
+	spec["storage"] = s.spec()
+
+	var created tableflowTopic
+	if err := t.do(http.MethodPost, "tableflow-topics", map[string]any{"spec": spec}, &created); err != nil {
+		return tableflowTopic{}, false, fmt.Errorf(`failed to enable Tableflow on topic "%s": %w`, topic, err)
+	}
+	return created, true, nil
+}
+
+func (t *tableflow) describe(topic string) (tableflowTopic, error) {
+	var described tableflowTopic
+	err := t.do(http.MethodGet, "tableflow-topics/"+url.PathEscape(topic), nil, &described)
+	return described, err
+}
+
+func (t *tableflow) disable(topic string) error {
+	return t.do(http.MethodDelete, "tableflow-topics/"+url.PathEscape(topic), nil, nil)
+}
+
+// catalogIntegration syncs the Iceberg tables of a cluster to an external catalog.
+type catalogIntegration struct {
+	ID   string `json:"id"`
+	Spec struct {
+		DisplayName string `json:"display_name"`
+		Config      struct {
+			Kind string `json:"kind"`
+		} `json:"config"`
+	} `json:"spec"`
+	Status struct {
+		Phase        string `json:"phase"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"status"`
+}
+
+func (t *tableflow) catalogIntegrations() ([]catalogIntegration, error) {
+	return paging.All(func(token string) ([]catalogIntegration, string, error) {
+		var page paging.CloudPage[catalogIntegration]
+		if err := t.do(http.MethodGet, paging.WithToken("catalog-integrations", token), nil, &page); err != nil {
+			return nil, "", fmt.Errorf("failed to list the catalog integrations: %w", err)
+		}
+		return page.Data, page.Next(), nil
+	})
+}
+
+// findCatalogIntegration returns the catalog integration with the name, or nil if there's none.
+func (t *tableflow) findCatalogIntegration(name string) (*catalogIntegration, error) {
+	integrations, err := t.catalogIntegrations()
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range integrations {
+		if c.Spec.DisplayName == name {
+			return &integrations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (t *tableflow) createCatalogIntegration(name string, config map[string]string) (catalogIntegration, error) {
+	spec := t.scope()
+	spec["display_name"] = name
+	spec["config"] = config
+
+	var created catalogIntegration
+	if err := t.do(http.MethodPost, "catalog-integrations", map[string]any{"spec": spec}, &created); err != nil {
+		return catalogIntegration{}, fmt.Errorf("failed to create the catalog integration: %w", err)
+	}
+	return created, nil
+}
+
+func (t *tableflow) describeCatalogIntegration(id string) (catalogIntegration, error) {
+	var described catalogIntegration
+	err := t.do(http.MethodGet, "catalog-integrations/"+url.PathEscape(id), nil, &described)
+	return described, err
+}
+
+func (t *tableflow) deleteCatalogIntegration(id string) error {
+	return t.do(http.MethodDelete, "catalog-integrations/"+url.PathEscape(id), nil, nil)
+}
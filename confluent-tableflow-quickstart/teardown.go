@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newTeardownCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teardown",
+		Short: "Disable Tableflow on topics and delete the catalog integration.",
+		Long:  "Disable Tableflow on the topics, and delete the catalog integration which the quickstart created, after prompting for confirmation. Tables in storage managed by Confluent are deleted along with them, while tables in an S3 bucket are kept.",
+		Args:  cobra.NoArgs,
+		RunE:  teardown,
+		Example: `confluent tableflow quickstart teardown --topic orders,users --dry-run
+confluent tableflow quickstart teardown --topic orders,users --force`,
+	}
+
+	cmd.Flags().Bool("keep-catalog", false, "Keep the catalog integration.")
+	dryrun.AddFlag(cmd, "Print what would be removed without removing it.")
+	cmd.Flags().Bool("force", false, "Remove without prompting for confirmation.")
+	output.AddFlag(cmd, "Format of what was removed")
+
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	return cmd
+}
+
+func teardown(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	keepCatalog, err := cmd.Flags().GetBool("keep-catalog")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	t, err := newTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	var topics []string
+	for _, topic := range t.topics {
+		if _, err := t.tableflow.describe(topic); err != nil {
+			if notFound(err) {
+				continue
+			}
+			return fmt.Errorf(`failed to describe Tableflow topic "%s": %w`, topic, err)
+		}
+		topics = append(topics, topic)
+	}
+
+	var integration *catalogIntegration
+	if !keepCatalog {
+		if integration, err = t.tableflow.findCatalogIntegration(t.catalogName); err != nil {
+			return err
+		}
+	}
+
+	var descriptions []string
+	for _, topic := range topics {
+		descriptions = append(descriptions, fmt.Sprintf(`Tableflow on topic "%s"`, topic))
+	}
+	if integration != nil {
+		descriptions = append(descriptions, fmt.Sprintf(`catalog integration "%s" (%s)`, t.catalogName, integration.ID))
+	}
+
+	out := output.Progress(cmd)
+	if len(descriptions) == 0 {
+		fmt.Fprintln(out, "Nothing to remove.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, topic := range topics {
+			plan.Add("disable", "Tableflow on topic", topic)
+		}
+		if integration != nil {
+			plan.AddDetail("delete", "catalog integration", t.catalogName, "("+integration.ID+")")
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to remove %s?", strings.Join(descriptions, ", ")))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not removing anything.")
+			return dryrun.PrintApplied(cmd, dryrun.Plan{})
+		}
+	}
+
+	var removed dryrun.Plan
+	var errs []error
+	for _, topic := range topics {
+		if err := t.tableflow.disable(topic); err != nil {
+			errs = append(errs, fmt.Errorf(`failed to disable Tableflow on topic "%s": %w`, topic, err))
+			continue
+		}
+		fmt.Fprintf(out, "Disabled Tableflow on topic \"%s\".\n", topic)
+		removed.Add("disable", "Tableflow on topic", topic)
+	}
+	if integration != nil {
+		if err := t.tableflow.deleteCatalogIntegration(integration.ID); err != nil {
+			errs = append(errs, fmt.Errorf(`failed to delete catalog integration "%s": %w`, integration.ID, err))
+		} else {
+			fmt.Fprintf(out, "Deleted catalog integration \"%s\" (%s).\n", t.catalogName, integration.ID)
+			removed.AddDetail("delete", "catalog integration", t.catalogName, "("+integration.ID+")")
+		}
+	}
+	return errors.Join(append(errs, dryrun.PrintApplied(cmd, removed))...)
+}
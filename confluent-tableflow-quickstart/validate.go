@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/progress"
+)
+
+// pollInterval is how often the tables and the catalog integration are checked while waiting for them.
+const pollInterval = 15 * time.Second
+
+// A result is whether the table of a topic materialized. Without a Tableflow API key, the table itself isn't checked,
+// only that Tableflow is running for the topic.
+type result struct {
+	topic     string
+	phase     string
+	path      string
+	checked   bool
+	snapshots int
+	done      bool
+	err       error
+}
+
+func (r result) report() tableReport {
+	t := tableReport{Topic: r.topic, Phase: r.phase, TablePath: r.path}
+	if r.checked {
+		t.Snapshots = &r.snapshots
+	}
+	if r.err != nil {
+		t.Error = r.err.Error()
+	}
+	return t
+}
+
+func (r result) table() string {
+	switch {
+	case r.err != nil:
+		return "failed: " + r.err.Error()
+	case !r.checked:
+		return "not checked"
+	default:
+		return fmt.Sprintf("%d snapshots", r.snapshots)
+	}
+}
+
+type validator struct {
+	target   target
+	iceberg  *icebergCatalog
+	deadline time.Time
+	// spinner shows how many tables have materialized.
+	spinner *progress.Spinner
+}
+
+// validate waits until Tableflow runs for every topic, and their tables have a snapshot, and until the catalog
+// integration, if any, is connected.
+func (v validator) validate(integration *catalogIntegration) ([]result, error) {
+	results := make([]result, len(v.target.topics))
+	for i, topic := range v.target.topics {
+		results[i].topic = topic
+	}
+	connected := integration == nil
+
+	for {
+		pending := 0
+		for i := range results {
+			r := &results[i]
+			if r.done {
+				continue
+			}
+			if err := v.check(r); err != nil {
+				return nil, err
+			}
+			if !r.done {
+				pending++
+			}
+		}
+
+		if !connected {
+			described, err := v.target.tableflow.describeCatalogIntegration(integration.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe the catalog integration: %w", err)
+			}
+			switch described.Status.Phase {
+			case "CONNECTED":
+				connected = true
+			case "FAILED":
+				return nil, fmt.Errorf(`catalog integration "%s" (%s) failed: %s`, described.Spec.DisplayName, integration.ID, described.Status.ErrorMessage)
+			}
+		}
+
+		v.spinner.Status(fmt.Sprintf("%d of %d tables materialized", len(results)-pending, len(results)))
+		if pending == 0 && connected {
+			return results, nil
+		}
+		if time.Now().Add(pollInterval).After(v.deadline) {
+			if !connected {
+				return nil, fmt.Errorf(`timed out waiting for catalog integration "%s" to connect`, integration.ID)
+			}
+			for i := range results {
+				switch {
+				case results[i].done:
+				case results[i].phase == "RUNNING":
+					results[i].err = fmt.Errorf("timed out waiting for a snapshot of the table")
+				default:
+					results[i].err = fmt.Errorf("timed out in phase %s", results[i].phase)
+				}
+			}
+			return results, nil
+		}
+		if err := interrupt.Sleep(pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// check updates the result of a topic, which is done once its table materialized, or Tableflow failed for it.
+func (v validator) check(r *result) error {
+	t, err := v.target.tableflow.describe(r.topic)
+	if err != nil {
+		return fmt.Errorf(`failed to describe Tableflow topic "%s": %w`, r.topic, err)
+	}
+	r.phase = t.Status.Phase
+	r.path = t.Spec.Storage.TablePath
+
+	switch {
+	case r.phase == "FAILED":
+		r.err = fmt.Errorf("%s", t.Status.ErrorMessage)
+		r.done = true
+		return nil
+	case r.phase != "RUNNING":
+		return nil
+	case v.iceberg == nil:
+		r.done = true
+		return nil
+	}
+
+	snapshots, exists, err := v.iceberg.table(v.target.tableflow.cluster, r.topic)
+	if err != nil {
+		return err
+	}
+	r.checked = true
+	r.snapshots = snapshots
+	r.done = exists && snapshots > 0
+	return nil
+}
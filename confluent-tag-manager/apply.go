@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <file>",
+		Short: "Apply tags and business metadata from a mapping file.",
+		Long:  "Apply the tags and business metadata of a YAML or CSV mapping file to topics and the latest versions of schema subjects. Assignments are added to the ones entities already have, unless --prune is passed, which also removes the tags and business metadata of the mapped entities which aren't in the file.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  apply,
+		Example: `confluent tag manager apply tags.yml --dry-run
+confluent tag manager apply tags.csv --create-tags --prune`,
+	}
+
+	cmd.Flags().Bool("create-tags", false, "Create the tags of the file which aren't defined yet.")
+	cmd.Flags().Bool("prune", false, "Remove the tags and business metadata of the mapped entities which aren't in the file.")
+	dryrun.AddFlag(cmd, "Print the changes without applying them.")
+	output.AddFlag(cmd, "Format of the report")
+
+	return cmd
+}
+
+// A change is a tag or business metadata to add to or remove from an entity.
+type change struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Change  string `json:"change"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+
+	entity     entity
+	remove     bool
+	tag        string
+	metadata   string
+	attributes map[string]string
+	exists     bool
+}
+
+func apply(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	createTags, err := cmd.Flags().GetBool("create-tags")
+	cobra.CheckErr(err)
+
+	prune, err := cmd.Flags().GetBool("prune")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	m, err := readMapping(args[0])
+	if err != nil {
+		return err
+	}
+
+	c, err := connect(cmd, len(m.Topics) > 0)
+	if err != nil {
+		return err
+	}
+
+	missing, err := validate(c, m)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 && !createTags {
+		return fmt.Errorf("tags %s aren't defined, pass --create-tags to create them", strings.Join(missing, ", "))
+	}
+
+	changes, err := plan(c, m, prune)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		var p dryrun.Plan
+		for _, tag := range missing {
+			p.Add("create", "tag", tag)
+		}
+		for _, ch := range changes {
+			p.Actions = append(p.Actions, ch.action())
+		}
+		return dryrun.Print(cmd, p)
+	}
+
+	if len(missing) > 0 {
+		if err := c.createTagDefs(missing); err != nil {
+			return fmt.Errorf("failed to create tags %s: %w", strings.Join(missing, ", "), err)
+		}
+	}
+	for i := range changes {
+		if err := c.change(changes[i]); err != nil {
+			changes[i].Error = err.Error()
+			continue
+		}
+		changes[i].Applied = true
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if changes == nil {
+			changes = []change{}
+		}
+		if err := output.Print(out, format, changes, nil); err != nil {
+			return err
+		}
+	} else {
+		print(out, changes, missing)
+	}
+
+	failed := 0
+	for _, ch := range changes {
+		if ch.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to apply %d changes", failed)
+	}
+	return nil
+}
+
+// validate checks that the business metadata of the mapping are defined with its attributes, and returns the tags
+// which aren't defined.
+func validate(c *catalog, m mapping) ([]string, error) {
+	tags, err := c.tagDefs()
+	if err != nil {
+		return nil, err
+	}
+	defs, err := c.businessMetadataDefs()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := map[string]bool{}
+	for _, e := range m.entities() {
+		a := m.get(e)
+		for _, tag := range a.Tags {
+			if !tags[tag] {
+				missing[tag] = true
+			}
+		}
+		for metadata, attributes := range a.BusinessMetadata {
+			def, ok := defs[metadata]
+			if !ok {
+				return nil, fmt.Errorf(`business metadata "%s" of %s isn't defined`, metadata, e)
+			}
+			for attribute := range attributes {
+				if !def[attribute] {
+					return nil, fmt.Errorf(`business metadata "%s" of %s has no attribute "%s"`, metadata, e, attribute)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for tag := range missing {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// plan compares the mapping with the current assignments of its entities, and returns the changes which make them
+// match. Business metadata attributes which aren't in the mapping are kept, unless pruning.
+func plan(c *catalog, m mapping, prune bool) ([]change, error) {
+	var changes []change
+	for _, e := range m.entities() {
+		desired := m.get(e)
+		current, err := c.read(e)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range desired.Tags {
+			if !slices.Contains(current.Tags, tag) {
+				changes = append(changes, change{entity: e, tag: tag, Change: "add tag " + tag})
+			}
+		}
+		if prune {
+			for _, tag := range current.Tags {
+				if !slices.Contains(desired.Tags, tag) {
+					changes = append(changes, change{entity: e, tag: tag, remove: true, Change: "remove tag " + tag})
+				}
+			}
+		}
+
+		for _, metadata := range sortedKeys(desired.BusinessMetadata) {
+			attributes := desired.BusinessMetadata[metadata]
+			existing, exists := current.BusinessMetadata[metadata]
+			merged := maps.Clone(attributes)
+			if !prune {
+				for attribute, value := range existing {
+					if _, ok := merged[attribute]; !ok {
+						merged[attribute] = value
+					}
+				}
+			}
+			if exists && maps.Equal(merged, existing) {
+				continue
+			}
+			changes = append(changes, change{entity: e, metadata: metadata, attributes: merged, exists: exists, Change: fmt.Sprintf("set %s %s", metadata, formatAttributes(merged))})
+		}
+		if prune {
+			for _, metadata := range sortedKeys(current.BusinessMetadata) {
+				if _, ok := desired.BusinessMetadata[metadata]; !ok {
+					changes = append(changes, change{entity: e, metadata: metadata, remove: true, Change: "remove " + metadata})
+				}
+			}
+		}
+	}
+
+	for i := range changes {
+		changes[i].Type = changes[i].entity.kind
+		changes[i].Name = changes[i].entity.name
+	}
+	return changes, nil
+}
+
+func (c *catalog) change(ch change) error {
+	switch {
+	case ch.tag != "" && ch.remove:
+		return c.removeTag(ch.entity, ch.tag)
+	case ch.tag != "":
+		return c.addTag(ch.entity, ch.tag)
+	case ch.remove:
+		return c.removeBusinessMetadata(ch.entity, ch.metadata)
+	default:
+		return c.setBusinessMetadata(ch.entity, ch.metadata, ch.attributes, ch.exists)
+	}
+}
+
+// action is the change, for --dry-run.
+func (ch change) action() dryrun.Action {
+	on := fmt.Sprintf(`%s "%s"`, ch.entity.kind, ch.entity.name)
+	switch {
+	case ch.tag != "" && ch.remove:
+		return dryrun.Action{Verb: "remove", Resource: "tag", Name: ch.tag, Detail: "from " + on}
+	case ch.tag != "":
+		return dryrun.Action{Verb: "add", Resource: "tag", Name: ch.tag, Detail: "to " + on}
+	case ch.remove:
+		return dryrun.Action{Verb: "remove", Resource: "business metadata", Name: ch.metadata, Detail: "from " + on}
+	default:
+		return dryrun.Action{Verb: "set", Resource: "business metadata", Name: ch.metadata, Detail: fmt.Sprintf("to %s on %s", formatAttributes(ch.attributes), on)}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatAttributes(attributes map[string]string) string {
+	pairs := make([]string, 0, len(attributes))
+	for _, attribute := range sortedKeys(attributes) {
+		pairs = append(pairs, attribute+"="+attributes[attribute])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func print(w io.Writer, changes []change, created []string) {
+	if len(created) > 0 {
+		fmt.Fprintf(w, "Created tags %s.\n\n", strings.Join(created, ", "))
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "The catalog already matches the mapping.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Type\tName\tChange\tResult")
+	applied := 0
+	entities := map[entity]bool{}
+	for _, ch := range changes {
+		result := "applied"
+		if ch.Error != "" {
+			result = "failed: " + ch.Error
+		} else {
+			applied++
+		}
+		entities[ch.entity] = true
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ch.Type, ch.Name, ch.Change, result)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nApplied %d of %d changes to %d entities.\n", applied, len(changes), len(entities))
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// Entity types of the Stream Catalog which tags and business metadata are assigned to.
+const (
+	topicEntity   = "kafka_topic"
+	subjectEntity = "sr_subject_version"
+)
+
+// catalog is a client of the Stream Catalog REST API, which is served by the environment's Schema Registry, since the
+// CLI can't assign business metadata.
+type catalog struct {
+	endpoint    string
+	key, secret string
+	client      *http.Client
+
+	// The IDs of the Schema Registry and Kafka cluster qualify the names of entities.
+	registryID string
+	clusterID  string
+	versions   map[string]int
+}
+
+func newCatalog(endpoint, key, secret, registryID, clusterID string) *catalog {
+	return &catalog{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		key:        key,
+		secret:     secret,
+		client:     retry.Client(30 * time.Second),
+		registryID: registryID,
+		clusterID:  clusterID,
+		versions:   map[string]int{},
+	}
+}
+
+func (c *catalog) do(method, path string, body, v any) (err error) {
+	defer func() { audit.Request(method, path, err) }()
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.key, c.secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		var e struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&e); err == nil && e.Message != "" {
+			return &catalogError{code: e.ErrorCode, message: e.Message}
+		}
+		return &catalogError{code: res.StatusCode, message: res.Status}
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+type catalogError struct {
+	code    int
+	message string
+}
+
+func (e *catalogError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.message, e.code)
+}
+
+// An entity is a topic or a subject, whose latest version is the one which is tagged.
+type entity struct {
+	kind string
+	name string
+}
+
+func (e entity) String() string {
+	return fmt.Sprintf(`%s "%s"`, e.kind, e.name)
+}
+
+// qualifiedName returns the type and the qualified name of the entity in the catalog.
+func (c *catalog) qualifiedName(e entity) (string, string, error) {
+	if e.kind == "topic" {
+		return topicEntity, fmt.Sprintf("%s:%s:%s", c.registryID, c.clusterID, e.name), nil
+	}
+
+	version, ok := c.versions[e.name]
+	if !ok {
+		var latest struct {
+			Version int `json:"version"`
+		}
+		if err := c.do(http.MethodGet, "/subjects/"+url.PathEscape(e.name)+"/versions/latest", nil, &latest); err != nil {
+			return "", "", fmt.Errorf("failed to read the latest version of %s: %w", e, err)
+		}
+		version = latest.Version
+		c.versions[e.name] = version
+	}
+	return subjectEntity, fmt.Sprintf("%s:.:%s:%d", c.registryID, e.name, version), nil
+}
+
+func (c *catalog) entityPath(e entity) (string, error) {
+	entityType, name, err := c.qualifiedName(e)
+	if err != nil {
+		return "", err
+	}
+	return "/catalog/v1/entity/type/" + entityType + "/name/" + url.PathEscape(name), nil
+}
+
+// tagDefs returns the names of the tags which are defined in the catalog.
+func (c *catalog) tagDefs() (map[string]bool, error) {
+	var defs []struct {
+		Name string `json:"name"`
+	}
+	if err := c.do(http.MethodGet, "/catalog/v1/types/tagdefs", nil, &defs); err != nil {
+		return nil, fmt.Errorf("failed to list the tags: %w", err)
+	}
+	names := map[string]bool{}
+	for _, d := range defs {
+		names[d.Name] = true
+	}
+	return names, nil
+}
+
+func (c *catalog) createTagDefs(names []string) error {
+	defs := make([]map[string]any, len(names))
+	for i, name := range names {
+		defs[i] = map[string]any{"name": name, "entityTypes": []string{"cf_entity"}}
+	}
+	return c.do(http.MethodPost, "/catalog/v1/types/tagdefs", defs, nil)
+}
+
+// businessMetadataDefs returns the attributes of each business metadata which is defined in the catalog.
+func (c *catalog) businessMetadataDefs() (map[string]map[string]bool, error) {
+	var defs []struct {
+		Name          string `json:"name"`
+		AttributeDefs []struct {
+			Name string `json:"name"`
+		} `json:"attributeDefs"`
+	}
+	if err := c.do(http.MethodGet, "/catalog/v1/types/businessmetadatadefs", nil, &defs); err != nil {
+		return nil, fmt.Errorf("failed to list the business metadata: %w", err)
+	}
+	m := map[string]map[string]bool{}
+	for _, d := range defs {
+		m[d.Name] = map[string]bool{}
+		for _, a := range d.AttributeDefs {
+			m[d.Name][a.Name] = true
+		}
+	}
+	return m, nil
+}
+
+// read returns the tags and business metadata of the entity. Entities which the catalog doesn't have yet, such as
+// topics which were just created, have none.
+func (c *catalog) read(e entity) (assignment, error) {
+	path, err := c.entityPath(e)
+	if err != nil {
+		return assignment{}, err
+	}
+
+	var a assignment
+	var tags []struct {
+		TypeName string `json:"typeName"`
+	}
+	if err := c.do(http.MethodGet, path+"/tags", nil, &tags); err != nil {
+		if notFound(err) {
+			return a, nil
+		}
+		return assignment{}, fmt.Errorf("failed to read the tags of %s: %w", e, err)
+	}
+	for _, t := range tags {
+		a.Tags = append(a.Tags, t.TypeName)
+	}
+	sort.Strings(a.Tags)
+
+	var metadata []struct {
+		TypeName   string         `json:"typeName"`
+		Attributes map[string]any `json:"attributes"`
+	}
+	if err := c.do(http.MethodGet, path+"/businessmetadata", nil, &metadata); err != nil {
+		if notFound(err) {
+			return a, nil
+		}
+		return assignment{}, fmt.Errorf("failed to read the business metadata of %s: %w", e, err)
+	}
+	for _, m := range metadata {
+		if a.BusinessMetadata == nil {
+			a.BusinessMetadata = map[string]map[string]string{}
+		}
+		a.BusinessMetadata[m.TypeName] = map[string]string{}
+		for name, value := range m.Attributes {
+			a.BusinessMetadata[m.TypeName][name] = fmt.Sprint(value)
+		}
+	}
+	return a, nil
+}
+
+func notFound(err error) bool {
+	e, ok := err.(*catalogError)
+	return ok && (e.code == http.StatusNotFound || e.code == 40403)
+}
+
+func (c *catalog) addTag(e entity, tag string) error {
+	entityType, name, err := c.qualifiedName(e)
+	if err != nil {
+		return err
+	}
+	body := []map[string]string{{"entityType": entityType, "entityName": name, "typeName": tag}}
+	return c.do(http.MethodPost, "/catalog/v1/entity/tags", body, nil)
+}
+
+func (c *catalog) removeTag(e entity, tag string) error {
+	path, err := c.entityPath(e)
+	if err != nil {
+		return err
+	}
+	return c.do(http.MethodDelete, path+"/tags/"+url.PathEscape(tag), nil, nil)
+}
+
+// setBusinessMetadata sets the attributes of a business metadata of the entity, creating it if the entity has none
+// yet.
+func (c *catalog) setBusinessMetadata(e entity, typeName string, attributes map[string]string, exists bool) error {
+	entityType, name, err := c.qualifiedName(e)
+	if err != nil {
+		return err
+	}
+	body := []map[string]any{{"entityType": entityType, "entityName": name, "typeName": typeName, "attributes": attributes}}
+	method := http.MethodPost
+	if exists {
+		method = http.MethodPut
+	}
+	return c.do(method, "/catalog/v1/entity/businessmetadata", body, nil)
+}
+
+func (c *catalog) removeBusinessMetadata(e entity, typeName string) error {
+	path, err := c.entityPath(e)
+	if err != nil {
+		return err
+	}
+	return c.do(http.MethodDelete, path+"/businessmetadata/"+url.PathEscape(typeName), nil, nil)
+}
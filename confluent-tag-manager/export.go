@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func newExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the current tag and business metadata assignments.",
+		Long:  "Export the tags and business metadata of the topics of a Kafka cluster and of the latest versions of the environment's schema subjects, as a mapping file which \"confluent tag manager apply\" reads. Entities without any are left out.",
+		Args:  cobra.NoArgs,
+		RunE:  export,
+		Example: `confluent tag manager export --file tags.yml
+confluent tag manager export --type subject --output csv > tags.csv`,
+	}
+
+	cmd.Flags().StringSlice("type", []string{"topic", "subject"}, "Types of the entities to export: topic, subject.")
+	cmd.Flags().String("file", "", "File to write the mapping to. Defaults to stdout.")
+	output.AddFlag(cmd, "Format of the mapping, which apply reads as yaml, json, or csv", "csv")
+	output.SetDefault(cmd, output.YAML)
+
+	return cmd
+}
+
+func export(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	types, err := cmd.Flags().GetStringSlice("type")
+	cobra.CheckErr(err)
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	for _, t := range types {
+		if t != "topic" && t != "subject" {
+			return fmt.Errorf(`unsupported type "%s", supported types: topic, subject`, t)
+		}
+	}
+
+	c, err := connect(cmd, slices.Contains(types, "topic"))
+	if err != nil {
+		return err
+	}
+
+	var entities []entity
+	if slices.Contains(types, "topic") {
+		var topics []cli.Topic
+		if err := cli.JSON(&topics, append([]string{"kafka", "topic", "list"}, cli.ClusterFlags(cluster, environment)...)...); err != nil {
+			return err
+		}
+		for _, t := range topics {
+			// Internal topics aren't in the catalog.
+			if !strings.HasPrefix(t.Name, "_") {
+				entities = append(entities, entity{kind: "topic", name: t.Name})
+			}
+		}
+	}
+	if slices.Contains(types, "subject") {
+		args := []string{"schema-registry", "subject", "list"}
+		if environment != "" {
+			args = append(args, "--environment", environment)
+		}
+		var subjects []struct {
+			Subject string `json:"subject"`
+		}
+		if err := cli.JSON(&subjects, args...); err != nil {
+			return err
+		}
+		for _, s := range subjects {
+			entities = append(entities, entity{kind: "subject", name: s.Subject})
+		}
+	}
+
+	var m mapping
+	for _, e := range entities {
+		a, err := c.read(e)
+		if err != nil {
+			return err
+		}
+		if len(a.Tags) > 0 || len(a.BusinessMetadata) > 0 {
+			m.set(e, a)
+		}
+	}
+
+	var w io.Writer = cmd.OutOrStdout()
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "csv":
+		err = writeCSV(w, m)
+	case output.YAML:
+		err = writeYAML(w, m)
+	default:
+		err = output.Print(w, format, m, func() { printMapping(w, m) })
+	}
+	if err != nil {
+		return err
+	}
+
+	if file != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported the assignments of %d entities to %s.\n", len(m.Topics)+len(m.Subjects), file)
+	}
+	return nil
+}
+
+// printMapping prints the assignments of the mapping as a table, which can't be applied.
+func printMapping(w io.Writer, m mapping) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Type\tName\tTags\tBusiness Metadata")
+	for _, e := range m.entities() {
+		a := m.get(e)
+		var metadata []string
+		for name, attributes := range a.BusinessMetadata {
+			for attribute, value := range attributes {
+				metadata = append(metadata, fmt.Sprintf("%s.%s=%s", name, attribute, value))
+			}
+		}
+		sort.Strings(metadata)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.kind, e.name, strings.Join(a.Tags, ", "), strings.Join(metadata, ", "))
+	}
+	_ = tw.Flush()
+}
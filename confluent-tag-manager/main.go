@@ -0,0 +1,108 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "manager",
+		Short: "Manage Stream Catalog tags and business metadata in bulk.",
+		Long:  "Apply Stream Catalog tags and business metadata to topics and schema subjects from a YAML or CSV mapping file with \"confluent tag manager apply\", and export the current assignments in the same format with \"confluent tag manager export\", to keep them in version control.",
+		Args:  cobra.NoArgs,
+		Example: `confluent tag manager export --file tags.yml
+confluent tag manager apply tags.yml --create-tags --dry-run`,
+	}
+
+	cmd.PersistentFlags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.PersistentFlags().String("cluster", "", "Kafka cluster ID of the topics. Defaults to the current cluster.")
+	cmd.PersistentFlags().String("api-key", "", "Schema Registry API key. Defaults to $CONFLUENT_SCHEMA_REGISTRY_API_KEY.")
+	cmd.PersistentFlags().String("api-secret", "", "Schema Registry API secret. Defaults to $CONFLUENT_SCHEMA_REGISTRY_API_SECRET.")
+	cmd.PersistentFlags().String("schema-registry-endpoint", "", "URL of the Schema Registry, which serves the Stream Catalog. Defaults to the environment's.")
+	logging.AddFlags(&cmd)
+
+	cmd.AddCommand(newApplyCommand())
+	cmd.AddCommand(newExportCommand())
+
+	completion.Add(&cmd, "confluent-tag-manager")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-tag-manager"))
+
+	exitcode.Execute(&cmd)
+}
+
+// connect creates a client of the environment's Stream Catalog from the persistent flags. The Kafka cluster is only
+// looked up if topics are managed, since their names are qualified by its ID.
+func connect(cmd *cobra.Command, topics bool) (*catalog, error) {
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	endpoint, err := cmd.Flags().GetString("schema-registry-endpoint")
+	cobra.CheckErr(err)
+
+	if apiKey == "" {
+		apiKey = os.Getenv("CONFLUENT_SCHEMA_REGISTRY_API_KEY")
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_SCHEMA_REGISTRY_API_SECRET")
+	}
+	if apiKey == "" || apiSecret == "" {
+		return nil, exitcode.Invalid("a Schema Registry API key is required, pass --api-key and --api-secret or set CONFLUENT_SCHEMA_REGISTRY_API_KEY and CONFLUENT_SCHEMA_REGISTRY_API_SECRET")
+	}
+
+	var environmentFlags []string
+	if environment != "" {
+		environmentFlags = []string{"--environment", environment}
+	}
+
+	var registry struct {
+		ClusterID   string `json:"cluster_id"`
+		EndpointURL string `json:"endpoint_url"`
+	}
+	if err := cli.JSON(&registry, append([]string{"schema-registry", "cluster", "describe"}, environmentFlags...)...); err != nil {
+		return nil, err
+	}
+	if registry.ClusterID == "" {
+		return nil, fmt.Errorf("the environment has no Schema Registry")
+	}
+	if endpoint == "" {
+		endpoint = registry.EndpointURL
+	}
+
+	var clusterID string
+	if topics {
+		args := []string{"kafka", "cluster", "describe"}
+		if cluster != "" {
+			args = append(args, cluster)
+		}
+		var described cli.KafkaCluster
+		if err := cli.JSON(&described, append(args, environmentFlags...)...); err != nil {
+			return nil, err
+		}
+		clusterID = described.ID
+	}
+
+	return newCatalog(endpoint, apiKey, apiSecret, registry.ClusterID, clusterID), nil
+}
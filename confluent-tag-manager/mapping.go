@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An assignment is the tags and business metadata of an entity. Business metadata are keyed by their name, and then by
+// the names of their attributes.
+type assignment struct {
+	Tags             []string                     `json:"tags,omitempty" yaml:"tags,omitempty"`
+	BusinessMetadata map[string]map[string]string `json:"business_metadata,omitempty" yaml:"business_metadata,omitempty"`
+}
+
+// A mapping is the assignments of topics and subjects, keyed by their names.
+type mapping struct {
+	Topics   map[string]assignment `json:"topics,omitempty" yaml:"topics,omitempty"`
+	Subjects map[string]assignment `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+}
+
+// entities returns the entities of the mapping, topics first, each sorted by name.
+func (m mapping) entities() []entity {
+	var es []entity
+	for _, kind := range []string{"topic", "subject"} {
+		assignments := m.assignments(kind)
+		names := make([]string, 0, len(assignments))
+		for name := range assignments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			es = append(es, entity{kind: kind, name: name})
+		}
+	}
+	return es
+}
+
+func (m mapping) assignments(kind string) map[string]assignment {
+	if kind == "topic" {
+		return m.Topics
+	}
+	return m.Subjects
+}
+
+func (m *mapping) set(e entity, a assignment) {
+	if e.kind == "topic" {
+		if m.Topics == nil {
+			m.Topics = map[string]assignment{}
+		}
+		m.Topics[e.name] = a
+		return
+	}
+	if m.Subjects == nil {
+		m.Subjects = map[string]assignment{}
+	}
+	m.Subjects[e.name] = a
+}
+
+func (m mapping) get(e entity) assignment {
+	return m.assignments(e.kind)[e.name]
+}
+
+// readMapping reads a mapping file, which is CSV if its name ends with .csv, and YAML, or JSON, otherwise.
+func readMapping(path string) (mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return mapping{}, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		m, err := readCSV(f)
+		if err != nil {
+			return mapping{}, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return m, nil
+	}
+
+	var m mapping
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil && err != io.EOF {
+		return mapping{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// readCSV reads a mapping with a row per entity. The columns are the type of the entity, topic or subject, its name,
+// its tags separated by semicolons, and then a column per business metadata attribute, named <metadata>.<attribute>.
+// Empty attributes aren't set.
+func readCSV(r io.Reader) (mapping, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return mapping{}, err
+	}
+	if len(records) == 0 {
+		return mapping{}, nil
+	}
+
+	header := records[0]
+	if len(header) < 3 || header[0] != "type" || header[1] != "name" || header[2] != "tags" {
+		return mapping{}, fmt.Errorf(`the header must start with "type,name,tags"`)
+	}
+	attributes := make([][2]string, len(header))
+	for i, column := range header[3:] {
+		metadata, attribute, ok := strings.Cut(column, ".")
+		if !ok || metadata == "" || attribute == "" {
+			return mapping{}, fmt.Errorf(`invalid column "%s", expected <metadata>.<attribute>`, column)
+		}
+		attributes[i+3] = [2]string{metadata, attribute}
+	}
+
+	var m mapping
+	for line, record := range records[1:] {
+		kind := record[0]
+		if kind != "topic" && kind != "subject" {
+			return mapping{}, fmt.Errorf(`line %d: unsupported type "%s", supported types: topic, subject`, line+2, kind)
+		}
+		if record[1] == "" {
+			return mapping{}, fmt.Errorf("line %d: the name is empty", line+2)
+		}
+
+		var a assignment
+		for _, tag := range strings.Split(record[2], ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				a.Tags = append(a.Tags, tag)
+			}
+		}
+		for i, value := range record[3:] {
+			if value == "" {
+				continue
+			}
+			metadata, attribute := attributes[i+3][0], attributes[i+3][1]
+			if a.BusinessMetadata == nil {
+				a.BusinessMetadata = map[string]map[string]string{}
+			}
+			if a.BusinessMetadata[metadata] == nil {
+				a.BusinessMetadata[metadata] = map[string]string{}
+			}
+			a.BusinessMetadata[metadata][attribute] = value
+		}
+		m.set(entity{kind: kind, name: record[1]}, a)
+	}
+	return m, nil
+}
+
+func writeYAML(w io.Writer, m mapping) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeCSV writes a mapping in the format which readCSV reads, with a column for each attribute any entity has.
+func writeCSV(w io.Writer, m mapping) error {
+	columns := map[string]bool{}
+	for _, e := range m.entities() {
+		for metadata, attributes := range m.get(e).BusinessMetadata {
+			for attribute := range attributes {
+				columns[metadata+"."+attribute] = true
+			}
+		}
+	}
+	var attributes []string
+	for c := range columns {
+		attributes = append(attributes, c)
+	}
+	sort.Strings(attributes)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"type", "name", "tags"}, attributes...)); err != nil {
+		return err
+	}
+	for _, e := range m.entities() {
+		a := m.get(e)
+		record := []string{e.kind, e.name, strings.Join(a.Tags, ";")}
+		for _, c := range attributes {
+			metadata, attribute, _ := strings.Cut(c, ".")
+			record = append(record, a.BusinessMetadata[metadata][attribute])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
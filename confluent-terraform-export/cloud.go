@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// cloudURL is the URL of the Confluent Cloud API, which lists the IDs of role bindings, since the CLI doesn't, and
+// Terraform needs them to import the bindings.
+var cloudURL = "https://api.confluent.cloud"
+
+// roleBinding is a role binding in the IAM API.
+type roleBinding struct {
+	ID         string `json:"id"`
+	Principal  string `json:"principal"`
+	RoleName   string `json:"role_name"`
+	CRNPattern string `json:"crn_pattern"`
+}
+
+type iam struct {
+	key    string
+	secret string
+	client *http.Client
+}
+
+func newIAM(key, secret string) *iam {
+	return &iam{key: key, secret: secret, client: retry.Client(30 * time.Second)}
+}
+
+// roleBindings returns the role bindings on the resource of the CRN and on the resources in it, following the pages of
+// the response.
+func (i *iam) roleBindings(crn string) ([]roleBinding, error) {
+	first := cloudURL + "/iam/v2/role-bindings?" + url.Values{"crn_pattern": {crn}, "page_size": {"100"}}.Encode()
+	listed, err := paging.All(func(token string) ([]roleBinding, string, error) {
+		req, err := http.NewRequest(http.MethodGet, paging.WithToken(first, token), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		req.SetBasicAuth(i.key, i.secret)
+
+		res, err := i.client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var page struct {
+			paging.CloudPage[roleBinding]
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		_ = res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			var details []string
+			for _, e := range page.Errors {
+				details = append(details, e.Detail)
+			}
+			if len(details) > 0 {
+				return nil, "", fmt.Errorf("failed to list the role bindings of %s: %s: %s", crn, res.Status, strings.Join(details, "; "))
+			}
+			return nil, "", fmt.Errorf("failed to list the role bindings of %s: %s", crn, res.Status)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse the role bindings of %s: %w", crn, err)
+		}
+		return page.Data, page.Next(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The CRN pattern filter matches part of the pattern, so bindings of other environments whose ID starts with the
+	// same characters are dropped.
+	var bindings []roleBinding
+	for _, b := range listed {
+		if b.CRNPattern == crn || strings.HasPrefix(b.CRNPattern, crn+"/") {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings, nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// header declares the provider, and the Terraform version which supports import blocks.
+const header = `terraform {
+  required_version = ">= 1.5"
+  required_providers {
+    confluent = {
+      source = "confluentinc/confluent"
+    }
+  }
+}
+
+# The provider reads its Cloud API key from $CONFLUENT_CLOUD_API_KEY and $CONFLUENT_CLOUD_API_SECRET.
+provider "confluent" {}
+`
+
+// serviceAccount is a service account in the output of "confluent iam service-account list".
+type serviceAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// An exporter writes a resource and an import block for each resource it reads, in dependency order, so that the
+// resources can refer to the ones they depend on.
+type exporter struct {
+	w       io.Writer
+	labels  labels
+	skipped map[string]bool
+	iam     *iam
+
+	// addresses is the address of the resource each ID was exported as, such as confluent_environment.prod.
+	addresses map[string]string
+	exported  []exportedResource
+}
+
+// An exportedResource is a resource which was exported, and the ID which it's imported from.
+type exportedResource struct {
+	Address  string `json:"address"`
+	ImportID string `json:"import_id"`
+}
+
+func newExporter(w io.Writer, skipped map[string]bool, iam *iam) *exporter {
+	return &exporter{w: w, labels: labels{}, skipped: skipped, iam: iam, addresses: map[string]string{}}
+}
+
+// resource writes a resource, and the block which imports it from its ID in Confluent Cloud.
+func (e *exporter) resource(resourceType, name, id, importID string, body ...entry) string {
+	address := resourceType + "." + e.labels.label(resourceType, name)
+	e.addresses[id] = address
+
+	fmt.Fprintln(e.w)
+	(&block{kind: "resource", labels: strings.SplitN(address, ".", 2), body: body}).write(e.w, "")
+	fmt.Fprintln(e.w)
+	(&block{kind: "import", body: []entry{attr("to", address), attr("id", quote(importID))}}).write(e.w, "")
+	e.exported = append(e.exported, exportedResource{Address: address, ImportID: importID})
+	return address
+}
+
+func (e *exporter) export(environments []cli.Environment) error {
+	fmt.Fprint(e.w, header)
+
+	// Service accounts are exported first, since they're in the organization rather than an environment, and role
+	// bindings refer to them.
+	if !e.skipped["service-accounts"] {
+		if err := e.exportServiceAccounts(); err != nil {
+			return err
+		}
+	}
+
+	var organization struct {
+		ID string `json:"id"`
+	}
+	if !e.skipped["role-bindings"] {
+		if err := cli.JSON(&organization, "organization", "describe"); err != nil {
+			return err
+		}
+	}
+
+	for _, env := range environments {
+		address := e.resource("confluent_environment", env.Name, env.ID, env.ID, attr("display_name", quote(env.Name)))
+
+		var clusters []cli.KafkaCluster
+		if err := cli.JSON(&clusters, "kafka", "cluster", "list", "--environment", env.ID); err != nil {
+			return err
+		}
+		for _, c := range clusters {
+			if err := e.exportCluster(env.ID, address, c.ID); err != nil {
+				return err
+			}
+		}
+
+		if !e.skipped["role-bindings"] {
+			crn := fmt.Sprintf("crn://confluent.cloud/organization=%s/environment=%s", organization.ID, env.ID)
+			if err := e.exportRoleBindings(crn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportServiceAccounts() error {
+	var serviceAccounts []serviceAccount
+	if err := cli.JSON(&serviceAccounts, "iam", "service-account", "list"); err != nil {
+		return err
+	}
+
+	for _, sa := range serviceAccounts {
+		e.resource("confluent_service_account", sa.Name, sa.ID, sa.ID,
+			attr("display_name", quote(sa.Name)),
+			attr("description", quote(sa.Description)),
+		)
+	}
+	return nil
+}
+
+// exportCluster exports a Kafka cluster and its topics. Networking isn't exported, so Dedicated clusters on a private
+// network need their network block to be added.
+func (e *exporter) exportCluster(env, envAddress, id string) error {
+	var cluster cli.KafkaCluster
+	if err := cli.JSON(&cluster, "kafka", "cluster", "describe", id, "--environment", env); err != nil {
+		return err
+	}
+
+	// The block of the cluster's type is empty, except for the CKUs of a Dedicated cluster.
+	var typeBlock entry
+	if strings.EqualFold(cluster.Type, "DEDICATED") {
+		typeBlock = nested("dedicated", attr("cku", fmt.Sprint(cluster.ClusterSize)))
+	} else {
+		typeBlock = nested(strings.ToLower(cluster.Type))
+	}
+
+	address := e.resource("confluent_kafka_cluster", cluster.Name, cluster.ID, env+"/"+cluster.ID,
+		attr("display_name", quote(cluster.Name)),
+		attr("availability", quote(strings.ToUpper(strings.ReplaceAll(cluster.Availability, "-", "_")))),
+		attr("cloud", quote(strings.ToUpper(cluster.Provider))),
+		attr("region", quote(cluster.Region)),
+		typeBlock,
+		nested("environment", attr("id", envAddress+".id")),
+	)
+
+	if e.skipped["topics"] {
+		return nil
+	}
+	return e.exportTopics(env, cluster, address)
+}
+
+// exportTopics exports the topics of a cluster with their non-default configs. Topics are managed with a Kafka API
+// key of their cluster, which is a variable of the cluster, so that it isn't written to the file.
+func (e *exporter) exportTopics(env string, cluster cli.KafkaCluster, address string) error {
+	scope := []string{"--cluster", cluster.ID, "--environment", env}
+
+	var topics []cli.Topic
+	if err := cli.JSON(&topics, append([]string{"kafka", "topic", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	var exported []cli.Topic
+	for _, t := range topics {
+		if !t.IsInternal {
+			exported = append(exported, t)
+		}
+	}
+	if len(exported) == 0 {
+		return nil
+	}
+
+	label := strings.TrimPrefix(address, "confluent_kafka_cluster.")
+	for _, v := range []struct{ suffix, description string }{
+		{"api_key", "Kafka API key of cluster " + cluster.ID + ", to manage its topics with."},
+		{"api_secret", "Secret of the Kafka API key of cluster " + cluster.ID + "."},
+	} {
+		fmt.Fprintln(e.w)
+		(&block{kind: "variable", labels: []string{label + "_" + v.suffix}, body: []entry{
+			attr("description", quote(v.description)),
+			attr("type", "string"),
+			attr("sensitive", "true"),
+		}}).write(e.w, "")
+	}
+
+	for _, t := range exported {
+		var configs []cli.TopicConfig
+		if err := cli.JSON(&configs, append([]string{"kafka", "topic", "configuration", "list", t.Name}, scope...)...); err != nil {
+			return fmt.Errorf(`failed to read the configs of topic "%s": %w`, t.Name, err)
+		}
+
+		m := map[string]string{}
+		for _, cfg := range configs {
+			if !cfg.IsDefaultValue && !cfg.IsReadOnly && !cfg.IsSensitive {
+				m[cfg.Name] = cfg.Value
+			}
+		}
+
+		body := []entry{
+			nested("kafka_cluster", attr("id", address+".id")),
+			attr("topic_name", quote(t.Name)),
+			attr("partitions_count", fmt.Sprint(t.PartitionCount)),
+			attr("rest_endpoint", address+".rest_endpoint"),
+		}
+		if len(m) > 0 {
+			body = append(body, mapAttr("config", m))
+		}
+		body = append(body, nested("credentials",
+			attr("key", "var."+label+"_api_key"),
+			attr("secret", "var."+label+"_api_secret"),
+		))
+		e.resource("confluent_kafka_topic", label+"_"+t.Name, cluster.ID+"/"+t.Name, cluster.ID+"/"+t.Name, body...)
+	}
+	return nil
+}
+
+// exportRoleBindings exports the role bindings on an environment and the resources in it. Principals which are
+// exported service accounts refer to them, and other principals, such as users, are kept as they are.
+func (e *exporter) exportRoleBindings(crn string) error {
+	bindings, err := e.iam.roleBindings(crn)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bindings {
+		principal, name := quote(b.Principal), strings.TrimPrefix(b.Principal, "User:")
+		if address, ok := e.addresses[name]; ok {
+			principal = fmt.Sprintf(`"User:${%s.id}"`, address)
+			name = strings.TrimPrefix(address, "confluent_service_account.")
+		}
+
+		name += "_" + b.RoleName
+		if i := strings.LastIndex(b.CRNPattern, "/"); i >= 0 {
+			name += "_" + b.CRNPattern[i+1:]
+		}
+		e.resource("confluent_role_binding", name, b.ID, b.ID,
+			attr("principal", principal),
+			attr("role_name", quote(b.RoleName)),
+			attr("crn_pattern", quote(b.CRNPattern)),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A block is an HCL block, such as a resource, with its labels and body.
+type block struct {
+	kind   string
+	labels []string
+	body   []entry
+}
+
+// An entry of a block's body is an attribute whose value is an HCL expression, an attribute whose value is a map of
+// strings, or a nested block.
+type entry struct {
+	name  string
+	value string
+	m     map[string]string
+	block *block
+}
+
+func attr(name, value string) entry {
+	return entry{name: name, value: value}
+}
+
+func mapAttr(name string, m map[string]string) entry {
+	return entry{name: name, m: m}
+}
+
+func nested(kind string, body ...entry) entry {
+	return entry{block: &block{kind: kind, body: body}}
+}
+
+// quote returns the HCL string literal of s, with template sequences escaped so that they aren't interpolated.
+func quote(s string) string {
+	q := strconv.Quote(s)
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
+
+// write writes the block the way "terraform fmt" formats it, with the equals signs of consecutive attributes aligned.
+func (b *block) write(w io.Writer, indent string) {
+	header := b.kind
+	for _, l := range b.labels {
+		header += " " + quote(l)
+	}
+	if len(b.body) == 0 {
+		fmt.Fprintf(w, "%s%s {}\n", indent, header)
+		return
+	}
+
+	fmt.Fprintf(w, "%s%s {\n", indent, header)
+	inner := indent + "  "
+	for i := 0; i < len(b.body); {
+		e := b.body[i]
+		switch {
+		case e.block != nil:
+			e.block.write(w, inner)
+			i++
+		case e.m != nil:
+			writeMap(w, inner, e.name, e.m)
+			i++
+		default:
+			j := i
+			width := 0
+			for ; j < len(b.body) && b.body[j].block == nil && b.body[j].m == nil; j++ {
+				width = max(width, len(b.body[j].name))
+			}
+			for _, a := range b.body[i:j] {
+				fmt.Fprintf(w, "%s%-*s = %s\n", inner, width, a.name, a.value)
+			}
+			i = j
+		}
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+}
+
+func writeMap(w io.Writer, indent, name string, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	width := 0
+	for k := range m {
+		keys = append(keys, k)
+		width = max(width, len(quote(k)))
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "%s%s = {\n", indent, name)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s  %-*s = %s\n", indent, width, quote(k), quote(m[k]))
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+}
+
+var invalidLabel = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// labels gives each resource a unique name of its type, from the display name of the resource it's imported from.
+type labels map[string]map[string]bool
+
+// label returns a name for a resource of the type, which is a valid identifier unique among the type's resources.
+func (l labels) label(resourceType, name string) string {
+	base := strings.Trim(invalidLabel.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "r_" + base
+	}
+
+	if l[resourceType] == nil {
+		l[resourceType] = map[string]bool{}
+	}
+	label := base
+	for i := 2; l[resourceType][label]; i++ {
+		label = fmt.Sprintf("%s_%d", base, i)
+	}
+	l[resourceType][label] = true
+	return label
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "export",
+		Short: "Export Confluent Cloud resources as Terraform.",
+		Long:  "Export environments, Kafka clusters, topics, service accounts, and role bindings as resources of the Confluent Terraform provider, each with an import block, so that resources which were created by hand or by other plugins can be brought under Terraform without being recreated.",
+		Args:  cobra.NoArgs,
+		RunE:  export,
+		Example: `confluent terraform export --file main.tf
+confluent terraform export --environment env-123456 --skip role-bindings`,
+	}
+
+	cmd.Flags().StringSlice("environment", nil, "IDs of the environments to export. Defaults to every environment.")
+	cmd.Flags().StringSlice("skip", nil, "Resources not to export: topics, service-accounts, or role-bindings.")
+	cmd.Flags().String("file", "", "File to write the configuration to. Defaults to stdout.")
+	output.AddFlag(&cmd, "Format of the export, the configuration with hcl or else the list of exported resources", "hcl")
+	output.SetDefault(&cmd, "hcl")
+	cmd.Flags().String("cloud-api-key", "", "Cloud API key to list role bindings with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("cloud-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	cli.AddCacheFlag(&cmd)
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-terraform-export")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-terraform-export"))
+
+	exitcode.Execute(&cmd)
+}
+
+func export(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	ids, err := cmd.Flags().GetStringSlice("environment")
+	cobra.CheckErr(err)
+
+	skip, err := cmd.Flags().GetStringSlice("skip")
+	cobra.CheckErr(err)
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("cloud-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("cloud-api-secret")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+
+	skipped := map[string]bool{}
+	for _, s := range skip {
+		if s != "topics" && s != "service-accounts" && s != "role-bindings" {
+			return fmt.Errorf(`unsupported resource "%s", supported resources: topics, service-accounts, role-bindings`, s)
+		}
+		skipped[s] = true
+	}
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if !skipped["role-bindings"] && (key == "" || secret == "") {
+		return exitcode.Invalid("a Cloud API key is required to export role bindings, pass --cloud-api-key and --cloud-api-secret, set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET, or pass --skip role-bindings")
+	}
+
+	var listed []cli.Environment
+	if err := cli.JSON(&listed, "environment", "list"); err != nil {
+		return err
+	}
+	environments := listed
+	if len(ids) > 0 {
+		byID := map[string]cli.Environment{}
+		for _, env := range listed {
+			byID[env.ID] = env
+		}
+		environments = nil
+		for _, id := range ids {
+			env, ok := byID[id]
+			if !ok {
+				return fmt.Errorf(`environment "%s" not found`, id)
+			}
+			environments = append(environments, env)
+		}
+	}
+
+	// The configuration is written to the file, or to stdout unless the list of exported resources is printed instead.
+	out := cmd.OutOrStdout()
+	switch {
+	case file != "":
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	case format != "hcl":
+		out = io.Discard
+	}
+
+	e := newExporter(out, skipped, newIAM(key, secret))
+	if err := e.export(environments); err != nil {
+		return err
+	}
+
+	if format != "hcl" {
+		exported := e.exported
+		if exported == nil {
+			exported = []exportedResource{}
+		}
+		stdout := cmd.OutOrStdout()
+		return output.Print(stdout, format, exported, func() { print(stdout, exported) })
+	}
+	if file != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d resources to %s.\n", len(e.exported), file)
+	}
+	return nil
+}
+
+func print(w io.Writer, exported []exportedResource) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Address\tImport ID")
+	for _, r := range exported {
+		fmt.Fprintf(tw, "%s\t%s\n", r.Address, r.ImportID)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// maxBatchBytes keeps produced batches under the default max.message.bytes of Confluent Cloud topics.
+const maxBatchBytes = 1 << 20
+
+// copier copies the records of a topic to another, preserving their keys, values, headers, and timestamps. Records
+// stay in the same partition if the topics have as many partitions, and are otherwise partitioned like the Java
+// producer does, by the hash of their key.
+type copier struct {
+	client *kafka.Client
+	source string
+	target string
+	out    io.Writer
+}
+
+// copy copies the records, and returns how many it copied.
+func (c copier) copy() (int, error) {
+	sources, err := c.client.Metadata(c.source)
+	if err != nil {
+		return 0, err
+	}
+
+	// A topic which was just created may take a moment to have leaders.
+	var targets []kafka.Partition
+	for attempt := 1; ; attempt++ {
+		targets, err = c.client.Metadata(c.target)
+		if err == nil && len(targets) > 0 {
+			break
+		}
+		if attempt == 10 {
+			return 0, fmt.Errorf(`topic "%s" isn't ready: %w`, c.target, err)
+		}
+		time.Sleep(time.Second)
+	}
+
+	byPartition := map[int32]kafka.Partition{}
+	for _, t := range targets {
+		byPartition[t.ID] = t
+	}
+
+	total := 0
+	for _, p := range sources {
+		n, err := c.copyPartition(p, int32(len(sources)), byPartition)
+		if err != nil {
+			return total, fmt.Errorf("failed to copy partition %d: %w", p.ID, err)
+		}
+		total += n
+	}
+
+	fmt.Fprintf(c.out, "Copied %d records.\n", total)
+	return total, nil
+}
+
+// copyPartition copies the records which are in the partition when the copy starts.
+func (c copier) copyPartition(p kafka.Partition, sourcePartitions int32, targets map[int32]kafka.Partition) (int, error) {
+	start, err := c.client.Offset(c.source, p, kafka.EarliestOffset)
+	if err != nil {
+		return 0, err
+	}
+	end, err := c.client.Offset(c.source, p, kafka.LatestOffset)
+	if err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	for offset := start; offset < end; {
+		records, next, err := c.client.Fetch(c.source, p, offset)
+		if err != nil {
+			return copied, err
+		}
+		if next <= offset {
+			fmt.Fprintf(c.out, "Partition %d: stopped at offset %d of %d, such as before a transaction which is still open.\n", p.ID, offset, end)
+			break
+		}
+		offset = next
+
+		batches := map[int32][]kafka.Record{}
+		sizes := map[int32]int{}
+		for _, r := range records {
+			if r.Offset >= end {
+				break
+			}
+
+			target := partitionFor(r, p.ID, sourcePartitions, int32(len(targets)))
+			if sizes[target]+r.Size() > maxBatchBytes && len(batches[target]) > 0 {
+				if err := c.produce(targets[target], batches[target]); err != nil {
+					return copied, err
+				}
+				copied += len(batches[target])
+				batches[target], sizes[target] = nil, 0
+			}
+			batches[target] = append(batches[target], r)
+			sizes[target] += r.Size()
+		}
+
+		for target, batch := range batches {
+			if len(batch) == 0 {
+				continue
+			}
+			if err := c.produce(targets[target], batch); err != nil {
+				return copied, err
+			}
+			copied += len(batch)
+		}
+	}
+
+	fmt.Fprintf(c.out, "Partition %d: copied %d records.\n", p.ID, copied)
+	return copied, nil
+}
+
+// produce appends the records to a partition of the target topic in one batch, with their keys, values, headers, and
+// timestamps.
+func (c copier) produce(p kafka.Partition, records []kafka.Record) error {
+	batch, err := kafka.EncodeBatch(records, kafka.CompressionNone)
+	if err != nil {
+		return err
+	}
+	return c.client.Produce(c.target, p, batch, kafka.AllReplicas)
+}
+
+// partitionFor returns the partition of the target topic to copy a record to.
+func partitionFor(r kafka.Record, source, sourcePartitions, partitions int32) int32 {
+	if partitions == sourcePartitions {
+		return source
+	}
+	if r.Key == nil {
+		return source % partitions
+	}
+	return (murmur2(r.Key) & 0x7fffffff) % partitions
+}
+
+// murmur2 is the hash which the Java producer partitions keyed records by.
+func murmur2(data []byte) int32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := length &^ 3
+	switch length % 4 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "clone <source> <destination>",
+		Short: "Clone a topic, and optionally copy its records.",
+		Long:  "Create a topic with the partition count and non-default configs of another topic in the same cluster. With --copy-data, the source topic's records are copied into the clone with their keys, headers, and timestamps, such as to make a test copy of a production topic.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  clone,
+		Example: `confluent topic clone orders orders-test
+confluent topic clone orders orders-test --copy-data --api-key ABCDEFGHIJKLMNOP --api-secret $API_SECRET`,
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().Int("partitions", 0, "Partition count of the clone. Defaults to the source topic's.")
+	cmd.Flags().StringSlice("config", nil, `Configs of the clone which override the source topic's, as "<name>=<value>" pairs.`)
+	cmd.Flags().Bool("copy-data", false, "Copy the source topic's records into the clone.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster, for --copy-data.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster, for --copy-data. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster, for --copy-data. Defaults to the cluster's endpoint.")
+	output.AddFlag(&cmd, "Format of the clone")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-topic-clone")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic-clone"))
+
+	exitcode.Execute(&cmd)
+}
+
+func clone(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	source, target := args[0], args[1]
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	partitions, err := cmd.Flags().GetInt("partitions")
+	cobra.CheckErr(err)
+
+	overrides, err := cmd.Flags().GetStringSlice("config")
+	cobra.CheckErr(err)
+
+	copyData, err := cmd.Flags().GetBool("copy-data")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if copyData && (apiKey == "" || apiSecret == "") {
+		return exitcode.Invalid("--api-key and --api-secret are required with --copy-data")
+	}
+
+	configOverrides, err := parseConfigs(overrides)
+	if err != nil {
+		return err
+	}
+
+	scope := cli.ClusterFlags(clusterID, environment)
+
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	var from *cli.Topic
+	for i, t := range listed {
+		if t.Name == target {
+			return fmt.Errorf(`topic "%s" already exists`, target)
+		}
+		if t.Name == source {
+			from = &listed[i]
+		}
+	}
+	if from == nil {
+		return fmt.Errorf(`topic "%s" not found`, source)
+	}
+
+	if partitions == 0 {
+		partitions = from.PartitionCount
+	}
+
+	c, err := configs(source, scope)
+	if err != nil {
+		return err
+	}
+	for name, value := range configOverrides {
+		c[name] = value
+	}
+
+	createArgs := []string{"kafka", "topic", "create", target, "--partitions", fmt.Sprint(partitions)}
+	if len(c) > 0 {
+		createArgs = append(createArgs, "--config", configFlag(c))
+	}
+	if _, err := cli.Run(append(createArgs, scope...)...); err != nil {
+		return err
+	}
+
+	out := output.Progress(cmd)
+	fmt.Fprintf(out, "Created topic \"%s\" with %d partitions and %d configs from \"%s\".\n", target, partitions, len(c), source)
+
+	cloned := clonedTopic{Source: source, Topic: target, Partitions: partitions, Configs: c}
+	if !copyData {
+		return output.Print(cmd.OutOrStdout(), format, cloned, nil)
+	}
+
+	if bootstrap == "" {
+		described, err := cli.DescribeKafkaCluster(clusterID, environment)
+		if err != nil {
+			return err
+		}
+		bootstrap = described.Endpoint
+	}
+
+	client := kafka.NewClient("confluent-topic-clone", bootstrap, apiKey, apiSecret)
+	defer client.Close()
+
+	copied, err := copier{client: client, source: source, target: target, out: out}.copy()
+	cloned.RecordsCopied = &copied
+	if err != nil {
+		return errors.Join(err, output.Print(cmd.OutOrStdout(), format, cloned, nil))
+	}
+	return output.Print(cmd.OutOrStdout(), format, cloned, nil)
+}
+
+// A clonedTopic is the clone with --output json or yaml.
+type clonedTopic struct {
+	Source     string            `json:"source"`
+	Topic      string            `json:"topic"`
+	Partitions int               `json:"partitions"`
+	Configs    map[string]string `json:"configs"`
+	// RecordsCopied is how many records --copy-data copied, which is left out without it.
+	RecordsCopied *int `json:"records_copied,omitempty"`
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// configs returns the configs of a topic which were changed from the cluster's defaults, and can be set on another
+// topic.
+func configs(name string, scope []string) (map[string]string, error) {
+	var configs []cli.TopicConfig
+	if err := cli.JSON(&configs, append([]string{"kafka", "topic", "configuration", "list", name}, scope...)...); err != nil {
+		return nil, fmt.Errorf(`failed to read the configs of topic "%s": %w`, name, err)
+	}
+
+	m := map[string]string{}
+	for _, c := range configs {
+		if !c.IsDefaultValue && !c.IsReadOnly && !c.IsSensitive {
+			m[c.Name] = c.Value
+		}
+	}
+	return m, nil
+}
+
+// parseConfigs parses key=value pairs, as passed to --config.
+func parseConfigs(pairs []string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf(`invalid config "%s", expected <name>=<value>`, pair)
+		}
+		m[name] = value
+	}
+	return m, nil
+}
+
+// configFlag formats configs as the value of --config.
+func configFlag(configs map[string]string) string {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + configs[name]
+	}
+	return strings.Join(pairs, ",")
+}
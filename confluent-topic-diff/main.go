@@ -0,0 +1,127 @@
+package main
+
+import (
+	_ "embed"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errDifferent fails the plugin with exitcode.Findings when the clusters' topics differ, so that CI can gate a
+// promotion on it.
+var errDifferent = exitcode.New(exitcode.Findings, "the topics differ")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "diff",
+		Short:   "Compare the topics in two Kafka clusters.",
+		Long:    "Compare the topics in two Kafka clusters, which may be in different environments, and print the topics which are missing from either, partition count and replication factor mismatches, and config differences. Exits with code 2 if there are differences.",
+		RunE:    diffTopics,
+		Example: "confluent topic diff --source-cluster lkc-123456 --target-cluster lkc-654321 --target-environment env-654321 --output json",
+	}
+
+	cmd.Flags().String("source-cluster", "", "Kafka cluster ID of the source. Defaults to the current cluster.")
+	cmd.Flags().String("source-environment", "", "Environment ID of the source. Defaults to the current environment.")
+	cmd.Flags().String("target-cluster", "", "Kafka cluster ID of the target.")
+	cmd.Flags().String("target-environment", "", "Environment ID of the target. Defaults to the current environment.")
+	output.AddFlag(&cmd, "Format of the diff")
+	cmd.Flags().String("prefix", "", "Only compare topics whose names start with this prefix.")
+	cmd.Flags().Bool("include-internal", false, "Compare internal topics too.")
+	cmd.Flags().StringSlice("ignore-config", nil, "Configs to ignore, such as those which are expected to differ between environments.")
+	cmd.Flags().Bool("skip-configs", false, "Only compare the topics and their partition counts, which is faster.")
+	cmd.Flags().Int("parallelism", 8, "How many topics to read the configs of at once.")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("target-cluster"))
+
+	completion.Add(&cmd, "confluent-topic-diff")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic-diff"))
+
+	exitcode.Execute(&cmd)
+}
+
+func diffTopics(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	sourceCluster, err := cmd.Flags().GetString("source-cluster")
+	cobra.CheckErr(err)
+
+	sourceEnvironment, err := cmd.Flags().GetString("source-environment")
+	cobra.CheckErr(err)
+
+	targetCluster, err := cmd.Flags().GetString("target-cluster")
+	cobra.CheckErr(err)
+
+	targetEnvironment, err := cmd.Flags().GetString("target-environment")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	internal, err := cmd.Flags().GetBool("include-internal")
+	cobra.CheckErr(err)
+
+	ignoreConfigs, err := cmd.Flags().GetStringSlice("ignore-config")
+	cobra.CheckErr(err)
+
+	skipConfigs, err := cmd.Flags().GetBool("skip-configs")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	source := &side{name: sourceCluster, scope: cli.ClusterFlags(sourceCluster, sourceEnvironment)}
+	if source.name == "" {
+		source.name = "the current cluster"
+	}
+	target := &side{name: targetCluster, scope: cli.ClusterFlags(targetCluster, targetEnvironment)}
+	for _, s := range []*side{source, target} {
+		if err := s.read(prefix, internal); err != nil {
+			return err
+		}
+	}
+
+	if !skipConfigs {
+		names := common(source, target)
+		for _, s := range []*side{source, target} {
+			if err := s.readConfigs(names, parallelism); err != nil {
+				return err
+			}
+		}
+	}
+
+	ignored := map[string]bool{}
+	for _, c := range ignoreConfigs {
+		ignored[c] = true
+	}
+
+	d := compare(source, target, ignored)
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, d, func() { d.print(out, source, target) }); err != nil {
+		return err
+	}
+
+	if !d.empty() {
+		return errDifferent
+	}
+	return nil
+}
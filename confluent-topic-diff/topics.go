@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// A side of the comparison: a cluster, and the topics and their configs which were read from it.
+type side struct {
+	name    string
+	scope   []string
+	topics  map[string]cli.Topic
+	configs map[string]map[string]cli.TopicConfig
+}
+
+// read lists the topics of the cluster which match the prefix.
+func (s *side) read(prefix string, internal bool) error {
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, s.scope...)...); err != nil {
+		return err
+	}
+
+	s.topics = map[string]cli.Topic{}
+	for _, t := range listed {
+		if t.IsInternal && !internal || !strings.HasPrefix(t.Name, prefix) {
+			continue
+		}
+		s.topics[t.Name] = t
+	}
+	return nil
+}
+
+// readConfigs reads the configs of the topics in parallel.
+func (s *side) readConfigs(names []string, parallelism int) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sem  = make(chan struct{}, parallelism)
+		errs = make([]error, len(names))
+	)
+	s.configs = map[string]map[string]cli.TopicConfig{}
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer func() { <-sem; wg.Done() }()
+
+			var configs []cli.TopicConfig
+			if err := cli.JSON(&configs, append([]string{"kafka", "topic", "configuration", "list", name}, s.scope...)...); err != nil {
+				errs[i] = fmt.Errorf(`failed to read the configs of topic "%s": %w`, name, err)
+				return
+			}
+
+			byName := map[string]cli.TopicConfig{}
+			for _, c := range configs {
+				byName[c.Name] = c
+			}
+
+			mu.Lock()
+			s.configs[name] = byName
+			mu.Unlock()
+		}(i, name)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "export",
+		Short:   "Export the topics in a Kafka cluster to a manifest.",
+		Long:    "Export the name, partition count, replication factor, and non-default configs of every topic in a Kafka cluster to a YAML or JSON manifest, which can be kept under version control and applied with confluent topic import.",
+		RunE:    export,
+		Example: "confluent topic export --cluster lkc-123456 --file topics.yaml",
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	output.AddFlag(&cmd, "Format of the manifest")
+	output.SetDefault(&cmd, output.YAML)
+	cmd.Flags().String("file", "", "File to write the manifest to. Defaults to stdout.")
+	cmd.Flags().String("prefix", "", "Only export topics whose names start with this prefix.")
+	cmd.Flags().Bool("include-internal", false, "Export internal topics too.")
+	cmd.Flags().Int("parallelism", 8, "How many topics to read the configs of at once.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-topic-export")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic-export"))
+
+	exitcode.Execute(&cmd)
+}
+
+func export(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	prefix, err := cmd.Flags().GetString("prefix")
+	cobra.CheckErr(err)
+
+	internal, err := cmd.Flags().GetBool("include-internal")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	e := exporter{
+		cluster:     cli.ClusterFlags(cluster, environment),
+		internal:    internal,
+		prefix:      prefix,
+		parallelism: parallelism,
+	}
+	m, err := e.export()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := output.Print(out, format, m, func() { print(out, m) }); err != nil {
+		return err
+	}
+
+	if file != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d topics to %s.\n", len(m.Topics), file)
+	}
+	return nil
+}
+
+// print prints the topics as a table, which can't be imported but is easier to read than the manifest.
+func print(w io.Writer, m manifest) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Name\tPartitions\tReplication Factor\tConfigs")
+	for _, t := range m.Topics {
+		configs := make([]string, 0, len(t.Configs))
+		for name, value := range t.Configs {
+			configs = append(configs, name+"="+value)
+		}
+		sort.Strings(configs)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", t.Name, t.Partitions, t.ReplicationFactor, strings.Join(configs, ", "))
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// A manifest of topics, which is the format read by confluent topic import.
+type manifest struct {
+	Topics []topic `yaml:"topics" json:"topics"`
+}
+
+type topic struct {
+	Name              string            `yaml:"name" json:"name"`
+	Partitions        int               `yaml:"partitions" json:"partitions"`
+	ReplicationFactor int               `yaml:"replication_factor,omitempty" json:"replication_factor,omitempty"`
+	Configs           map[string]string `yaml:"configs,omitempty" json:"configs,omitempty"`
+}
+
+type exporter struct {
+	cluster     []string
+	internal    bool
+	prefix      string
+	parallelism int
+}
+
+// export lists the topics, and then reads their configs in parallel, which is what makes it faster than describing each
+// topic in turn.
+func (e exporter) export() (manifest, error) {
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, e.cluster...)...); err != nil {
+		return manifest{}, err
+	}
+
+	var topics []topic
+	for _, t := range listed {
+		if t.IsInternal && !e.internal || !strings.HasPrefix(t.Name, e.prefix) {
+			continue
+		}
+		topics = append(topics, topic{Name: t.Name, Partitions: t.PartitionCount, ReplicationFactor: t.ReplicationFactor})
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, e.parallelism)
+		errs = make([]error, len(topics))
+	)
+	for i := range topics {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t *topic, err *error) {
+			defer func() { <-sem; wg.Done() }()
+			t.Configs, *err = e.configs(t.Name)
+		}(&topics[i], &errs[i])
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return manifest{}, err
+	}
+	return manifest{Topics: topics}, nil
+}
+
+// configs returns the configs of a topic which were changed from the cluster's defaults. Sensitive values can't be
+// read back, so they aren't exported.
+func (e exporter) configs(name string) (map[string]string, error) {
+	var configs []cli.TopicConfig
+	if err := cli.JSON(&configs, append([]string{"kafka", "topic", "configuration", "list", name}, e.cluster...)...); err != nil {
+		return nil, fmt.Errorf(`failed to read the configs of topic "%s": %w`, name, err)
+	}
+
+	m := map[string]string{}
+	for _, c := range configs {
+		if c.IsDefaultValue || c.IsReadOnly || c.IsSensitive {
+			continue
+		}
+		m[c.Name] = c.Value
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/parallel"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errDrift fails the plugin with exitcode.Findings when the cluster differs from the manifest in ways which weren't, or
+// can't be, applied.
+var errDrift = exitcode.New(exitcode.Findings, "the cluster has drifted from the manifest")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:     "import <manifest>",
+		Short:   "Reconcile the topics in a Kafka cluster with a manifest.",
+		Long:    "Reconcile the topics in a Kafka cluster with a YAML or JSON manifest, such as one written by confluent topic export: create missing topics, update their configs, and report drift which can't be reconciled, such as a different partition count.",
+		Args:    cobra.ExactArgs(1),
+		RunE:    importTopics,
+		Example: "confluent topic import topics.yaml --cluster lkc-123456 --dry-run",
+	}
+
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	dryrun.AddFlag(&cmd, "Print the changes which would be made, without making them.")
+	cmd.Flags().Bool("delete-extraneous", false, "Delete topics which aren't in the manifest. Internal topics are never deleted.")
+	cmd.Flags().Int("parallelism", 8, "How many topics to read the configs of, or to change, at once.")
+	output.AddFlag(&cmd, "Format of the changes which were made")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-topic-import")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic-import"))
+
+	exitcode.Execute(&cmd)
+}
+
+func importTopics(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	deleteExtraneous, err := cmd.Flags().GetBool("delete-extraneous")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	if _, err := output.Format(cmd); err != nil {
+		return err
+	}
+
+	m, err := readManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	scope := cli.ClusterFlags(cluster, environment)
+
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, scope...)...); err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+	for _, t := range listed {
+		existing[t.Name] = true
+	}
+	var names []string
+	for _, t := range m.Topics {
+		if existing[t.Name] {
+			names = append(names, t.Name)
+		}
+	}
+
+	configs, err := readConfigs(names, scope, parallelism)
+	if err != nil {
+		return err
+	}
+
+	changes := plan(m, listed, configs, deleteExtraneous)
+
+	out := output.Progress(cmd)
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "The cluster matches the manifest.")
+		return dryrun.PrintApplied(cmd, dryrun.Plan{})
+	}
+
+	// A dry run with changes to make is drift too, so that CI can fail before they're applied. Drift which can't be
+	// applied is reported on stderr, so that the plan on stdout is only what would be changed.
+	if dryRun {
+		var p dryrun.Plan
+		for _, c := range changes {
+			if c.action == actionDrift {
+				c.print(cmd.ErrOrStderr())
+				continue
+			}
+			p.Actions = append(p.Actions, c.dryRunAction())
+		}
+		if err := dryrun.Print(cmd, p); err != nil {
+			return err
+		}
+		return errDrift
+	}
+
+	drift := false
+	var apply []change
+	for _, c := range changes {
+		c.print(out)
+		if c.action == actionDrift {
+			drift = true
+			continue
+		}
+		apply = append(apply, c)
+	}
+	errs := parallel.Each(apply, parallelism, func(_ int, c change) error {
+		if err := c.apply(scope); err != nil {
+			return fmt.Errorf(`failed to %s topic "%s": %w`, c.action, c.topic.Name, err)
+		}
+		return nil
+	})
+	var applied dryrun.Plan
+	for i, c := range apply {
+		if errs[i] == nil {
+			applied.Actions = append(applied.Actions, c.dryRunAction())
+		}
+	}
+	if err := parallel.Summary(errs, "apply", "changes"); err != nil {
+		return errors.Join(err, dryrun.PrintApplied(cmd, applied))
+	}
+	if err := dryrun.PrintApplied(cmd, applied); err != nil {
+		return err
+	}
+	if drift {
+		return errDrift
+	}
+	return nil
+}
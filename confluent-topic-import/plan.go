@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+)
+
+type action string
+
+const (
+	actionCreate action = "create"
+	actionUpdate action = "update"
+	actionDelete action = "delete"
+	actionDrift  action = "drift"
+)
+
+// A change reconciles one topic with the manifest. Drift is a difference which can't be reconciled, such as fewer
+// partitions in the manifest than in the cluster, and is only reported.
+type change struct {
+	action  action
+	topic   topic
+	configs map[string]string
+	details []string
+}
+
+// plan compares the manifest with the topics in the cluster.
+func plan(m manifest, listed []cli.Topic, configs map[string][]cli.TopicConfig, deleteExtraneous bool) []change {
+	existing := map[string]cli.Topic{}
+	for _, t := range listed {
+		existing[t.Name] = t
+	}
+
+	var changes []change
+	wanted := map[string]bool{}
+	for _, t := range m.Topics {
+		wanted[t.Name] = true
+
+		current, ok := existing[t.Name]
+		if !ok {
+			changes = append(changes, change{action: actionCreate, topic: t, configs: t.Configs})
+			continue
+		}
+
+		var drift []string
+		if t.Partitions != current.PartitionCount {
+			drift = append(drift, fmt.Sprintf("%d partitions in the manifest, %d in the cluster", t.Partitions, current.PartitionCount))
+		}
+		if t.ReplicationFactor != 0 && t.ReplicationFactor != current.ReplicationFactor {
+			drift = append(drift, fmt.Sprintf("replication factor %d in the manifest, %d in the cluster", t.ReplicationFactor, current.ReplicationFactor))
+		}
+
+		update := map[string]string{}
+		var updates []string
+		byName := map[string]cli.TopicConfig{}
+		for _, c := range configs[t.Name] {
+			byName[c.Name] = c
+		}
+		for _, name := range sortedKeys(t.Configs) {
+			value := t.Configs[name]
+			c, ok := byName[name]
+			switch {
+			case ok && c.Value == value:
+			case ok && c.IsReadOnly:
+				drift = append(drift, fmt.Sprintf("%s is %s in the manifest, %s in the cluster, and is read-only", name, value, c.Value))
+			default:
+				update[name] = value
+				updates = append(updates, fmt.Sprintf("%s: %s -> %s", name, c.Value, value))
+			}
+		}
+
+		// Configs which were changed in the cluster but aren't in the manifest can't be reset to their defaults by the
+		// CLI, so they're reported.
+		for _, c := range configs[t.Name] {
+			if _, ok := t.Configs[c.Name]; !ok && !c.IsDefaultValue && !c.IsReadOnly && !c.IsSensitive {
+				drift = append(drift, fmt.Sprintf("%s is %s in the cluster, and not in the manifest", c.Name, c.Value))
+			}
+		}
+
+		if len(update) > 0 {
+			changes = append(changes, change{action: actionUpdate, topic: t, configs: update, details: updates})
+		}
+		if len(drift) > 0 {
+			changes = append(changes, change{action: actionDrift, topic: t, details: drift})
+		}
+	}
+
+	if deleteExtraneous {
+		for _, t := range listed {
+			if !wanted[t.Name] && !t.IsInternal {
+				changes = append(changes, change{action: actionDelete, topic: topic{Name: t.Name}})
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].topic.Name < changes[j].topic.Name })
+	return changes
+}
+
+func (c change) print(w io.Writer) {
+	switch c.action {
+	case actionCreate:
+		fmt.Fprintf(w, "+ create %s (%d partitions)\n", c.topic.Name, c.topic.Partitions)
+		for _, name := range sortedKeys(c.configs) {
+			fmt.Fprintf(w, "    %s: %s\n", name, c.configs[name])
+		}
+	case actionUpdate:
+		fmt.Fprintf(w, "~ update %s\n", c.topic.Name)
+	case actionDelete:
+		fmt.Fprintf(w, "- delete %s\n", c.topic.Name)
+	case actionDrift:
+		fmt.Fprintf(w, "! drift %s\n", c.topic.Name)
+	}
+	for _, d := range c.details {
+		fmt.Fprintf(w, "    %s\n", d)
+	}
+}
+
+// dryRunAction is the change, for --dry-run, and for --output once it's applied. Drift can't be applied, so it has
+// none.
+func (c change) dryRunAction() dryrun.Action {
+	a := dryrun.Action{Verb: string(c.action), Resource: "topic", Name: c.topic.Name}
+	switch c.action {
+	case actionCreate:
+		a.Detail = fmt.Sprintf("with %d partitions", c.topic.Partitions)
+		var configs []string
+		for _, name := range sortedKeys(c.configs) {
+			configs = append(configs, name+"="+c.configs[name])
+		}
+		if len(configs) > 0 {
+			a.Detail += " and " + strings.Join(configs, ", ")
+		}
+	case actionUpdate:
+		a.Detail = "changing " + strings.Join(c.details, ", ")
+	}
+	return a
+}
+
+// apply makes the change with the confluent CLI. Drift can't be applied, so it's skipped.
+func (c change) apply(cluster []string) error {
+	var args []string
+	switch c.action {
+	case actionCreate:
+		args = []string{"kafka", "topic", "create", c.topic.Name, "--partitions", fmt.Sprint(c.topic.Partitions)}
+		if len(c.configs) > 0 {
+			args = append(args, "--config", configFlag(c.configs))
+		}
+	case actionUpdate:
+		args = []string{"kafka", "topic", "update", c.topic.Name, "--config", configFlag(c.configs)}
+	case actionDelete:
+		args = []string{"kafka", "topic", "delete", c.topic.Name, "--force"}
+	default:
+		return nil
+	}
+
+	_, err := cli.Run(append(args, cluster...)...)
+	return err
+}
+
+func configFlag(configs map[string]string) string {
+	var pairs []string
+	for _, name := range sortedKeys(configs) {
+		pairs = append(pairs, name+"="+configs[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/parallel"
+	"gopkg.in/yaml.v3"
+)
+
+// A manifest of topics, in the format written by confluent topic export.
+type manifest struct {
+	Topics []topic `yaml:"topics" json:"topics"`
+}
+
+type topic struct {
+	Name              string            `yaml:"name" json:"name"`
+	Partitions        int               `yaml:"partitions" json:"partitions"`
+	ReplicationFactor int               `yaml:"replication_factor,omitempty" json:"replication_factor,omitempty"`
+	Configs           map[string]string `yaml:"configs,omitempty" json:"configs,omitempty"`
+}
+
+// readManifest reads a manifest, which may also be JSON, since YAML is a superset of it.
+func readManifest(path string) (manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+
+	var m manifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return manifest{}, fmt.Errorf(`failed to parse the manifest "%s": %w`, path, err)
+	}
+
+	seen := map[string]bool{}
+	for i, t := range m.Topics {
+		if t.Name == "" {
+			return manifest{}, fmt.Errorf("topic %d in the manifest has no name", i+1)
+		}
+		if t.Partitions < 1 {
+			return manifest{}, fmt.Errorf(`topic "%s" in the manifest must have at least 1 partition`, t.Name)
+		}
+		if seen[t.Name] {
+			return manifest{}, fmt.Errorf(`topic "%s" is in the manifest more than once`, t.Name)
+		}
+		seen[t.Name] = true
+	}
+
+	return m, nil
+}
+
+// readConfigs reads the configs of the topics in parallel.
+func readConfigs(names []string, cluster []string, parallelism int) (map[string][]cli.TopicConfig, error) {
+	var (
+		mu      sync.Mutex
+		configs = map[string][]cli.TopicConfig{}
+	)
+	errs := parallel.Each(names, parallelism, func(_ int, name string) error {
+		var c []cli.TopicConfig
+		if err := cli.JSON(&c, append([]string{"kafka", "topic", "configuration", "list", name}, cluster...)...); err != nil {
+			return fmt.Errorf(`failed to read the configs of topic "%s": %w`, name, err)
+		}
+
+		mu.Lock()
+		configs[name] = c
+		mu.Unlock()
+		return nil
+	})
+
+	return configs, errors.Join(errs...)
+}
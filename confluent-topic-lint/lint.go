@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// A violation is a rule of the policy which a topic doesn't follow.
+type violation struct {
+	Topic  string `json:"topic"`
+	Domain string `json:"domain,omitempty"`
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+// A result is how many topics were linted, and their violations, sorted by topic.
+type result struct {
+	Topics     int         `json:"topics"`
+	Excluded   int         `json:"excluded"`
+	Violations []violation `json:"violations"`
+}
+
+type linter struct {
+	policy      compiledPolicy
+	cluster     []string
+	parallelism int
+}
+
+// lint lists the topics, and then reads their configs in parallel, and checks each topic against the rules of its
+// domain. Internal topics aren't linted.
+func (l linter) lint() (result, error) {
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list"}, l.cluster...)...); err != nil {
+		return result{}, err
+	}
+
+	r := result{Violations: []violation{}}
+	var topics []cli.Topic
+	for _, t := range listed {
+		if t.IsInternal {
+			continue
+		}
+		if l.policy.excluded(t.Name) {
+			r.Excluded++
+			continue
+		}
+		topics = append(topics, t)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	r.Topics = len(topics)
+
+	var (
+		wg         sync.WaitGroup
+		sem        = make(chan struct{}, l.parallelism)
+		errs       = make([]error, len(topics))
+		violations = make([][]violation, len(topics))
+	)
+	for i := range topics {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			violations[i], errs[i] = l.check(topics[i])
+		}(i)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return result{}, err
+	}
+	for _, v := range violations {
+		r.Violations = append(r.Violations, v...)
+	}
+	return r, nil
+}
+
+func (l linter) check(t cli.Topic) ([]violation, error) {
+	rules := l.policy.rulesOf(t.Name)
+	var violations []violation
+	add := func(rule, format string, a ...any) {
+		violations = append(violations, violation{Topic: t.Name, Domain: rules.domain, Rule: rule, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	if prefixes := l.policy.requiredPrefixes; len(prefixes) > 0 && !hasAnyPrefix(t.Name, prefixes) {
+		add("prefix", "doesn't start with %s", strings.Join(prefixes, ", "))
+	}
+	if rules.naming != nil && !rules.naming.MatchString(t.Name) {
+		add("naming", "doesn't match %s", rules.naming)
+	}
+	if rules.minReplication > 0 && t.ReplicationFactor < rules.minReplication {
+		add("min-replication", "replication factor %d is less than %d", t.ReplicationFactor, rules.minReplication)
+	}
+	if rules.minPartitions > 0 && t.PartitionCount < rules.minPartitions {
+		add("min-partitions", "has %d partitions, fewer than %d", t.PartitionCount, rules.minPartitions)
+	}
+	if rules.maxPartitions > 0 && t.PartitionCount > rules.maxPartitions {
+		add("max-partitions", "has %d partitions, more than %d", t.PartitionCount, rules.maxPartitions)
+	}
+
+	if rules.maxRetention == 0 && len(rules.configs) == 0 {
+		return violations, nil
+	}
+
+	var configs []cli.TopicConfig
+	if err := cli.JSON(&configs, append([]string{"kafka", "topic", "configuration", "list", t.Name}, l.cluster...)...); err != nil {
+		return nil, fmt.Errorf(`failed to read the configs of topic "%s": %w`, t.Name, err)
+	}
+	values := map[string]string{}
+	for _, c := range configs {
+		values[c.Name] = c.Value
+	}
+
+	if rules.maxRetention > 0 {
+		if ms, err := strconv.ParseInt(values["retention.ms"], 10, 64); err != nil {
+			add("max-retention", `retention.ms "%s" isn't a number`, values["retention.ms"])
+		} else if ms < 0 {
+			add("max-retention", "retention is infinite, which is more than %s", formatRetention(rules.maxRetention))
+		} else if retention := time.Duration(ms) * time.Millisecond; retention > rules.maxRetention {
+			add("max-retention", "retention %s is more than %s", formatRetention(retention), formatRetention(rules.maxRetention))
+		}
+	}
+	for _, name := range sortedKeys(rules.configs) {
+		if value, ok := values[name]; !ok {
+			add("config", `%s isn't set, expected "%s"`, name, rules.configs[name])
+		} else if value != rules.configs[name] {
+			add("config", `%s is "%s", expected "%s"`, name, value, rules.configs[name])
+		}
+	}
+	return violations, nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRetention formats a retention in days, if it's a whole number of them.
+func formatRetention(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
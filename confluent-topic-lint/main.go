@@ -0,0 +1,115 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// errViolations fails the plugin with exitcode.Findings when a topic violates the policy, so that CI fails the build.
+var errViolations = exitcode.New(exitcode.Findings, "some topics violate the policy")
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "lint",
+		Short: "Check the topics of a Kafka cluster against a policy.",
+		Long:  "Check the names, replication, partitions, retention, and configs of the topics in a Kafka cluster against the conventions of a YAML policy file, with rules per domain of topics, and report every violation, exiting with code 2 if there are any, such as to gate CI or audit compliance.",
+		Args:  cobra.NoArgs,
+		RunE:  lint,
+		Example: `confluent topic lint --policy topic-policy.yaml --cluster lkc-123456
+confluent topic lint --policy topic-policy.yaml --output json > violations.json`,
+	}
+
+	cmd.Flags().String("policy", "", "YAML file of the policy.")
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	output.AddFlag(&cmd, "Format of the report")
+	cmd.Flags().Int("parallelism", 8, "How many topics to read the configs of at once.")
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("policy"))
+
+	completion.Add(&cmd, "confluent-topic-lint")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic-lint"))
+
+	exitcode.Execute(&cmd)
+}
+
+func lint(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	path, err := cmd.Flags().GetString("policy")
+	cobra.CheckErr(err)
+
+	cluster, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 {
+		return exitcode.Invalid("--parallelism must be at least 1")
+	}
+
+	p, err := readPolicy(path)
+	if err != nil {
+		return err
+	}
+
+	l := linter{policy: p, cluster: cli.ClusterFlags(cluster, environment), parallelism: parallelism}
+	r, err := l.lint()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if err := output.Print(out, format, r, func() { print(out, r) }); err != nil {
+		return err
+	}
+
+	if len(r.Violations) > 0 {
+		return errViolations
+	}
+	return nil
+}
+
+func print(w io.Writer, r result) {
+	violating := map[string]bool{}
+	if len(r.Violations) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Topic\tDomain\tRule\tDetail")
+		for _, v := range r.Violations {
+			domain := v.Domain
+			if domain == "" {
+				domain = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.Topic, domain, v.Rule, v.Detail)
+			violating[v.Topic] = true
+		}
+		_ = tw.Flush()
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d topics linted, %d excluded, %d violations in %d topics.\n", r.Topics, r.Excluded, len(r.Violations), len(violating))
+}
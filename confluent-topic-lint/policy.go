@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A policy is the conventions which the topics of a cluster must follow, e.g.:
+//
+//	exclude:
+//	  - "^_"
+//	required_prefixes:
+//	  - orders.
+//	  - payments.
+//	defaults:
+//	  min_replication: 3
+//	  max_retention: 7d
+//	domains:
+//	  - name: orders
+//	    match: '^orders\.'
+//	    naming: '^orders\.[a-z0-9-]+\.v[0-9]+$'
+//	    min_partitions: 6
+//	    configs:
+//	      cleanup.policy: delete
+//
+// Each topic is checked against the rules of the first domain which matches its name, on top of the defaults.
+type policy struct {
+	Exclude          []string `yaml:"exclude"`
+	RequiredPrefixes []string `yaml:"required_prefixes"`
+	Defaults         rules    `yaml:"defaults"`
+	Domains          []domain `yaml:"domains"`
+}
+
+type domain struct {
+	Name  string `yaml:"name"`
+	Match string `yaml:"match"`
+	rules `yaml:",inline"`
+}
+
+// rules are the conventions of a domain, each of which isn't checked if it's unset. max_retention is a duration such
+// as 168h or 7d.
+type rules struct {
+	Naming         string            `yaml:"naming"`
+	MinReplication int               `yaml:"min_replication"`
+	MaxRetention   string            `yaml:"max_retention"`
+	MinPartitions  int               `yaml:"min_partitions"`
+	MaxPartitions  int               `yaml:"max_partitions"`
+	Configs        map[string]string `yaml:"configs"`
+}
+
+// compiledRules are the rules of a domain, merged with the defaults, and parsed.
+type compiledRules struct {
+	domain         string
+	naming         *regexp.Regexp
+	minReplication int
+	maxRetention   time.Duration
+	minPartitions  int
+	maxPartitions  int
+	configs        map[string]string
+}
+
+type compiledPolicy struct {
+	exclude          []*regexp.Regexp
+	requiredPrefixes []string
+	defaults         compiledRules
+	domains          []*regexp.Regexp
+	rules            []compiledRules
+}
+
+func readPolicy(path string) (compiledPolicy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return compiledPolicy{}, fmt.Errorf("failed to read the policy: %w", err)
+	}
+
+	var p policy
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil && err != io.EOF {
+		return compiledPolicy{}, fmt.Errorf("failed to parse the policy: %w", err)
+	}
+	return p.compile()
+}
+
+func (p policy) compile() (compiledPolicy, error) {
+	c := compiledPolicy{requiredPrefixes: p.RequiredPrefixes}
+	for _, pattern := range p.Exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return compiledPolicy{}, fmt.Errorf(`invalid exclude pattern "%s": %w`, pattern, err)
+		}
+		c.exclude = append(c.exclude, re)
+	}
+
+	defaults, err := compiledRules{}.merge("", p.Defaults)
+	if err != nil {
+		return compiledPolicy{}, fmt.Errorf("invalid defaults: %w", err)
+	}
+	c.defaults = defaults
+
+	for i, d := range p.Domains {
+		if d.Name == "" {
+			return compiledPolicy{}, fmt.Errorf("domain %d has no name", i+1)
+		}
+		if d.Match == "" {
+			return compiledPolicy{}, fmt.Errorf(`domain "%s" has no match pattern`, d.Name)
+		}
+		match, err := regexp.Compile(d.Match)
+		if err != nil {
+			return compiledPolicy{}, fmt.Errorf(`invalid match pattern of domain "%s": %w`, d.Name, err)
+		}
+		r, err := defaults.merge(d.Name, d.rules)
+		if err != nil {
+			return compiledPolicy{}, fmt.Errorf(`invalid rules of domain "%s": %w`, d.Name, err)
+		}
+		c.domains = append(c.domains, match)
+		c.rules = append(c.rules, r)
+	}
+	return c, nil
+}
+
+// merge returns the rules, with the ones which are set in r replaced. Configs are merged, rather than replaced.
+func (c compiledRules) merge(domain string, r rules) (compiledRules, error) {
+	merged := c
+	merged.domain = domain
+
+	if r.Naming != "" {
+		naming, err := regexp.Compile(r.Naming)
+		if err != nil {
+			return compiledRules{}, fmt.Errorf(`invalid naming pattern "%s": %w`, r.Naming, err)
+		}
+		merged.naming = naming
+	}
+	if r.MaxRetention != "" {
+		retention, err := parseRetention(r.MaxRetention)
+		if err != nil {
+			return compiledRules{}, err
+		}
+		merged.maxRetention = retention
+	}
+	if r.MinReplication > 0 {
+		merged.minReplication = r.MinReplication
+	}
+	if r.MinPartitions > 0 {
+		merged.minPartitions = r.MinPartitions
+	}
+	if r.MaxPartitions > 0 {
+		merged.maxPartitions = r.MaxPartitions
+	}
+	if merged.minPartitions > 0 && merged.maxPartitions > 0 && merged.minPartitions > merged.maxPartitions {
+		return compiledRules{}, fmt.Errorf("min_partitions is more than max_partitions")
+	}
+
+	merged.configs = map[string]string{}
+	for k, v := range c.configs {
+		merged.configs[k] = v
+	}
+	for k, v := range r.Configs {
+		merged.configs[k] = v
+	}
+	return merged, nil
+}
+
+// parseRetention parses a duration, which may also be a number of days, such as 7d.
+func parseRetention(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf(`invalid max_retention "%s"`, s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf(`invalid max_retention "%s"`, s)
+	}
+	return d, nil
+}
+
+func (c compiledPolicy) excluded(topic string) bool {
+	for _, re := range c.exclude {
+		if re.MatchString(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesOf returns the rules of the first domain which matches the topic, or the defaults.
+func (c compiledPolicy) rulesOf(topic string) compiledRules {
+	for i, match := range c.domains {
+		if match.MatchString(topic) {
+			return c.rules[i]
+		}
+	}
+	return c.defaults
+}
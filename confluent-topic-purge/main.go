@@ -0,0 +1,252 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/dryrun"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "purge <topic>",
+		Short: "Delete the records of a topic up to a timestamp or offset.",
+		Long:  "Delete the records of a topic's partitions before a timestamp or an offset, or all of them, like kafka-delete-records. How many records and bytes would be deleted from each partition is previewed, and the records are only deleted once confirmed. The topic itself, and its configs, are kept.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  purgeTopic,
+		Example: `confluent topic purge orders --before 2024-05-01T00:00:00Z --api-key ABCDEFGHIJKLMNOP --dry-run
+confluent topic purge orders --partition-offsets 0=1500,3=2200 --api-key ABCDEFGHIJKLMNOP
+confluent topic purge demo-events --all --api-key ABCDEFGHIJKLMNOP --force`,
+	}
+
+	cmd.Flags().String("before", "", "RFC 3339 timestamp to delete the records before.")
+	cmd.Flags().Int64("offset", -1, "Offset to delete the records of every partition before.")
+	cmd.Flags().StringToInt64("partition-offsets", nil, `Offsets to delete the records of partitions before, as "<partition>=<offset>" pairs. Other partitions are kept.`)
+	cmd.Flags().Bool("all", false, "Delete every record of the topic.")
+	cmd.Flags().String("cluster", "", "Kafka cluster ID. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().String("api-key", "", "Kafka API key of the cluster.")
+	cmd.Flags().String("api-secret", "", "Kafka API secret of the cluster. Defaults to $CONFLUENT_KAFKA_API_SECRET.")
+	cmd.Flags().String("bootstrap", "", "Bootstrap server of the cluster. Defaults to the cluster's endpoint.")
+	dryrun.AddFlag(&cmd, "Preview the records which would be deleted, without deleting them.")
+	cmd.Flags().Bool("force", false, "Delete the records without prompting for confirmation.")
+	output.AddFlag(&cmd, "Format of the preview")
+	prompt.AddFlags(&cmd)
+	logging.AddFlags(&cmd)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("api-key"))
+	cmd.MarkFlagsMutuallyExclusive("before", "offset", "partition-offsets", "all")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "force")
+
+	completion.Add(&cmd, "confluent-topic-purge")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic-purge"))
+
+	exitcode.Execute(&cmd)
+}
+
+func purgeTopic(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	topic := args[0]
+
+	before, err := cmd.Flags().GetString("before")
+	cobra.CheckErr(err)
+
+	offset, err := cmd.Flags().GetInt64("offset")
+	cobra.CheckErr(err)
+
+	partitionOffsets, err := cmd.Flags().GetStringToInt64("partition-offsets")
+	cobra.CheckErr(err)
+
+	all, err := cmd.Flags().GetBool("all")
+	cobra.CheckErr(err)
+
+	clusterID, err := cmd.Flags().GetString("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	apiKey, err := cmd.Flags().GetString("api-key")
+	cobra.CheckErr(err)
+
+	apiSecret, err := cmd.Flags().GetString("api-secret")
+	cobra.CheckErr(err)
+
+	bootstrap, err := cmd.Flags().GetString("bootstrap")
+	cobra.CheckErr(err)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	cobra.CheckErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if apiSecret == "" {
+		apiSecret = os.Getenv("CONFLUENT_KAFKA_API_SECRET")
+	}
+	if apiSecret == "" {
+		return exitcode.Invalid("--api-secret or CONFLUENT_KAFKA_API_SECRET is required")
+	}
+
+	if before == "" && !cmd.Flags().Changed("offset") && len(partitionOffsets) == 0 && !all {
+		return exitcode.Invalid("one of --before, --offset, --partition-offsets, or --all is required")
+	}
+
+	t := target{all: all, offset: offset}
+	switch {
+	case before != "":
+		ts, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			return fmt.Errorf(`invalid timestamp "%s", expected an RFC 3339 timestamp such as 2024-05-01T00:00:00Z`, before)
+		}
+		t.timestamp = ts.UnixMilli()
+	case len(partitionOffsets) > 0:
+		t.offsets = map[int32]int64{}
+		for p, o := range partitionOffsets {
+			partition, err := strconv.ParseInt(p, 10, 32)
+			if err != nil || partition < 0 {
+				return exitcode.Invalid(`invalid partition "%s" in --partition-offsets`, p)
+			}
+			if o < 0 {
+				return exitcode.Invalid("invalid offset %d of partition %d in --partition-offsets", o, partition)
+			}
+			t.offsets[int32(partition)] = o
+		}
+	case cmd.Flags().Changed("offset") && offset < 0:
+		return exitcode.Invalid("--offset must not be negative")
+	}
+
+	if bootstrap == "" {
+		described, err := cli.DescribeKafkaCluster(clusterID, environment)
+		if err != nil {
+			return err
+		}
+		bootstrap = described.Endpoint
+	}
+
+	client := kafka.NewClient("confluent-topic-purge", bootstrap, apiKey, apiSecret)
+	defer client.Close()
+
+	purges, err := plan(client, topic, t)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		var plan dryrun.Plan
+		for _, p := range purges {
+			if p.Records == 0 {
+				continue
+			}
+			detail := fmt.Sprintf("from partition %d before offset %d, %d records", p.Partition, p.PurgeTo, p.Records)
+			if p.Bytes != nil {
+				detail += ", about " + formatBytes(float64(*p.Bytes))
+			}
+			plan.AddDetail("delete", "records of topic", topic, detail)
+		}
+		return dryrun.Print(cmd, plan)
+	}
+
+	out := cmd.OutOrStdout()
+	if format != output.Table {
+		if purges == nil {
+			purges = []partitionPurge{}
+		}
+		if err := output.Print(out, format, purges, nil); err != nil {
+			return err
+		}
+	} else {
+		print(out, topic, purges)
+	}
+
+	var records int64
+	for _, p := range purges {
+		records += p.Records
+	}
+	if records == 0 {
+		return nil
+	}
+
+	if !force {
+		ok, err := prompt.Confirm(cmd, fmt.Sprintf("Are you sure you want to delete %d records from topic \"%s\"? They can't be recovered.", records, topic))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Not deleting records.")
+			return nil
+		}
+	}
+
+	if err := purge(client, topic, purges); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %d records from topic \"%s\".\n", records, topic)
+	return nil
+}
+
+func print(w io.Writer, topic string, purges []partitionPurge) {
+	var records, bytes int64
+	known := true
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Partition\tLog Start Offset\tLog End Offset\tPurge To Offset\tRecords\tBytes")
+	for _, p := range purges {
+		size := "-"
+		if p.Bytes != nil {
+			size = "~" + formatBytes(float64(*p.Bytes))
+			bytes += *p.Bytes
+		} else if p.Records > 0 {
+			known = false
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%s\n", p.Partition, p.LogStart, p.LogEnd, p.PurgeTo, p.Records, size)
+		records += p.Records
+	}
+	_ = tw.Flush()
+
+	if records == 0 {
+		fmt.Fprintf(w, "\nNo records of topic \"%s\" to delete.\n", topic)
+		return
+	}
+	if known {
+		fmt.Fprintf(w, "\nPurging topic \"%s\" deletes %d records, about %s.\n", topic, records, formatBytes(float64(bytes)))
+	} else {
+		fmt.Fprintf(w, "\nPurging topic \"%s\" deletes %d records.\n", topic, records)
+	}
+}
+
+// formatBytes formats a size in decimal units, as Confluent Cloud bills them.
+func formatBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for b >= 1000 && i < len(units)-1 {
+		b /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/cli-plugins/internal/kafka"
+)
+
+// A target is where to purge the topic's partitions up to: all of their records, the records before a timestamp, or
+// the records before an offset.
+type target struct {
+	all       bool
+	timestamp int64
+	// offset applies to every partition, unless it's -1, and offsets to the partitions in it only.
+	offset  int64
+	offsets map[int32]int64
+}
+
+// partitionPurge is the effect of purging one partition of the topic. Bytes is estimated from the size of the
+// partition's log, assuming its records are of even size, and is nil if the broker doesn't report the size.
+type partitionPurge struct {
+	Partition int32  `json:"partition"`
+	LogStart  int64  `json:"log_start_offset"`
+	LogEnd    int64  `json:"log_end_offset"`
+	PurgeTo   int64  `json:"purge_to_offset"`
+	Records   int64  `json:"records"`
+	Bytes     *int64 `json:"bytes"`
+	leader    kafka.Partition
+}
+
+// plan works out how far to purge each of the topic's partitions, without deleting any records. Offsets are kept
+// within the partition's log, like kafka-delete-records does.
+func plan(client *kafka.Client, topic string, t target) ([]partitionPurge, error) {
+	partitions, err := client.Metadata(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[int32]bool{}
+	for _, p := range partitions {
+		known[p.ID] = true
+	}
+	for partition := range t.offsets {
+		if !known[partition] {
+			return nil, fmt.Errorf(`topic "%s" has no partition %d`, topic, partition)
+		}
+	}
+
+	var purges []partitionPurge
+	for _, p := range partitions {
+		to := t.offset
+		if t.offsets != nil {
+			var ok bool
+			if to, ok = t.offsets[p.ID]; !ok {
+				continue
+			}
+		}
+
+		start, err := client.Offset(topic, p, kafka.EarliestOffset)
+		if err != nil {
+			return nil, err
+		}
+		end, err := client.Offset(topic, p, kafka.LatestOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case t.all:
+			to = end
+		case t.timestamp > 0:
+			if to, err = client.Offset(topic, p, t.timestamp); err != nil {
+				return nil, err
+			}
+			// No record is at or after the timestamp, so every record is before it.
+			if to < 0 {
+				to = end
+			}
+		}
+		to = min(max(to, start), end)
+
+		purge := partitionPurge{Partition: p.ID, LogStart: start, LogEnd: end, PurgeTo: to, Records: to - start, leader: p}
+		if purge.Records > 0 {
+			size, err := client.LogSize(topic, p)
+			if err != nil {
+				return nil, err
+			}
+			if size >= 0 {
+				bytes := size * purge.Records / (end - start)
+				purge.Bytes = &bytes
+			}
+		}
+		purges = append(purges, purge)
+	}
+
+	sort.Slice(purges, func(i, j int) bool { return purges[i].Partition < purges[j].Partition })
+	return purges, nil
+}
+
+// purge deletes the records of the partitions which have records to delete.
+func purge(client *kafka.Client, topic string, purges []partitionPurge) error {
+	var partitions []kafka.Partition
+	offsets := map[int32]int64{}
+	for _, p := range purges {
+		if p.Records > 0 {
+			partitions = append(partitions, p.leader)
+			offsets[p.Partition] = p.PurgeTo
+		}
+	}
+	return client.DeleteRecords(topic, partitions, offsets)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/completion"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+	"github.com/spf13/cobra"
+)
+
+//go:embed manifest.yml
+var manifestYAML []byte
+
+func main() {
+	plugin.HandleManifest(manifestYAML)
+
+	cmd := cobra.Command{
+		Use:   "report",
+		Short: "Report the size of each topic.",
+		Long:  "Report the retained bytes of each topic of Kafka clusters, and how many records they received, with the Metrics API, sorted from the largest topic to the smallest, with a total per cluster, to see which topics dominate storage costs.",
+		Args:  cobra.NoArgs,
+		RunE:  report,
+		Example: `confluent topic-size report --top 10
+confluent topic-size report --cluster lkc-123456,lkc-654321 --days 30 --output csv > sizes.csv`,
+	}
+
+	cmd.Flags().StringSlice("cluster", nil, "Kafka cluster IDs. Defaults to the current cluster.")
+	cmd.Flags().String("environment", "", "Environment ID. Defaults to the current environment.")
+	cmd.Flags().Int("days", 7, "Number of days to count the received records of.")
+	cmd.Flags().Int("top", 0, "Only report the largest topics of each cluster. Defaults to every topic.")
+	output.AddFlag(&cmd, "Format of the report", "csv")
+	cmd.Flags().String("metrics-api-key", "", "Cloud API key to query the Metrics API with. Defaults to $CONFLUENT_CLOUD_API_KEY.")
+	cmd.Flags().String("metrics-api-secret", "", "Secret of the Cloud API key. Defaults to $CONFLUENT_CLOUD_API_SECRET.")
+	logging.AddFlags(&cmd)
+
+	completion.Add(&cmd, "confluent-topic_size-report")
+	cobra.CheckErr(plugin.ApplyConfig(&cmd, "confluent-topic_size-report"))
+
+	exitcode.Execute(&cmd)
+}
+
+type topicSize struct {
+	Topic         string  `json:"topic"`
+	RetainedBytes float64 `json:"retained_bytes"`
+	Records       float64 `json:"received_records"`
+}
+
+type clusterSize struct {
+	Cluster       string      `json:"cluster"`
+	RetainedBytes float64     `json:"retained_bytes"`
+	Records       float64     `json:"received_records"`
+	Topics        []topicSize `json:"topics"`
+	// Others is how many topics were left out by --top.
+	Others int `json:"others,omitempty"`
+}
+
+func report(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	clusters, err := cmd.Flags().GetStringSlice("cluster")
+	cobra.CheckErr(err)
+
+	environment, err := cmd.Flags().GetString("environment")
+	cobra.CheckErr(err)
+
+	days, err := cmd.Flags().GetInt("days")
+	cobra.CheckErr(err)
+
+	top, err := cmd.Flags().GetInt("top")
+	cobra.CheckErr(err)
+
+	key, err := cmd.Flags().GetString("metrics-api-key")
+	cobra.CheckErr(err)
+
+	secret, err := cmd.Flags().GetString("metrics-api-secret")
+	cobra.CheckErr(err)
+
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	if days < 1 {
+		return exitcode.Invalid("--days must be at least 1")
+	}
+	if top < 0 {
+		return exitcode.Invalid("--top must not be negative")
+	}
+
+	if key == "" {
+		key = os.Getenv("CONFLUENT_CLOUD_API_KEY")
+	}
+	if secret == "" {
+		secret = os.Getenv("CONFLUENT_CLOUD_API_SECRET")
+	}
+	if key == "" || secret == "" {
+		return exitcode.Invalid("a Cloud API key is required to query the Metrics API, pass --metrics-api-key and --metrics-api-secret or set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET")
+	}
+
+	var environmentFlags []string
+	if environment != "" {
+		environmentFlags = []string{"--environment", environment}
+	}
+
+	if len(clusters) == 0 {
+		var described cli.KafkaCluster
+		if err := cli.JSON(&described, append([]string{"kafka", "cluster", "describe"}, environmentFlags...)...); err != nil {
+			return err
+		}
+		clusters = []string{described.ID}
+	}
+
+	q := querier{key: key, secret: secret, client: retry.Client(30 * time.Second)}
+
+	var sizes []clusterSize
+	for _, cluster := range clusters {
+		size, err := measure(q, cluster, environmentFlags, days)
+		if err != nil {
+			return err
+		}
+		if top > 0 && len(size.Topics) > top {
+			size.Others = len(size.Topics) - top
+			size.Topics = size.Topics[:top]
+		}
+		sizes = append(sizes, size)
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case output.JSON, output.YAML:
+		return output.Print(out, format, sizes, nil)
+	case "csv":
+		return writeCSV(out, sizes)
+	default:
+		print(out, sizes, days)
+		return nil
+	}
+}
+
+// measure returns the sizes of the topics of the cluster, from the largest to the smallest. Topics without metrics,
+// such as empty ones, are included with a size of 0, and the totals include every topic.
+func measure(q querier, cluster string, environmentFlags []string, days int) (clusterSize, error) {
+	var listed []cli.Topic
+	if err := cli.JSON(&listed, append([]string{"kafka", "topic", "list", "--cluster", cluster}, environmentFlags...)...); err != nil {
+		return clusterSize{}, err
+	}
+
+	retained, err := q.retainedBytes(cluster)
+	if err != nil {
+		return clusterSize{}, err
+	}
+	records, err := q.receivedRecords(cluster, days)
+	if err != nil {
+		return clusterSize{}, err
+	}
+
+	size := clusterSize{Cluster: cluster, Topics: []topicSize{}}
+	for _, t := range listed {
+		s := topicSize{Topic: t.Name, RetainedBytes: retained[t.Name], Records: records[t.Name]}
+		size.Topics = append(size.Topics, s)
+		size.RetainedBytes += s.RetainedBytes
+		size.Records += s.Records
+	}
+	sort.Slice(size.Topics, func(i, j int) bool {
+		if size.Topics[i].RetainedBytes != size.Topics[j].RetainedBytes {
+			return size.Topics[i].RetainedBytes > size.Topics[j].RetainedBytes
+		}
+		return size.Topics[i].Topic < size.Topics[j].Topic
+	})
+	return size, nil
+}
+
+func writeCSV(w io.Writer, sizes []clusterSize) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"cluster", "topic", "retained_bytes", "received_records"}); err != nil {
+		return err
+	}
+	for _, c := range sizes {
+		for _, t := range c.Topics {
+			if err := cw.Write([]string{c.Cluster, t.Topic, formatNumber(t.RetainedBytes), formatNumber(t.Records)}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func print(w io.Writer, sizes []clusterSize, days int) {
+	for i, c := range sizes {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Kafka cluster %s:\n", c.Cluster)
+		if len(c.Topics) == 0 {
+			fmt.Fprintln(w, "No topics.")
+			continue
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "Topic\tRetained\tShare\tRecords (%dd)\n", days)
+		for _, t := range c.Topics {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Topic, formatBytes(t.RetainedBytes), share(t.RetainedBytes, c.RetainedBytes), formatNumber(t.Records))
+		}
+		if c.Others > 0 {
+			fmt.Fprintf(tw, "(%d other topics)\t\t\t\n", c.Others)
+		}
+		fmt.Fprintf(tw, "Total\t%s\t\t%s\n", formatBytes(c.RetainedBytes), formatNumber(c.Records))
+		_ = tw.Flush()
+	}
+}
+
+// share formats the part of the cluster's retained bytes which a topic retains as a percentage.
+func share(bytes, total float64) string {
+	if total == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(bytes/total*100, 'f', 1, 64) + "%"
+}
+
+// formatBytes formats a size in decimal units, as Confluent Cloud bills them.
+func formatBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for b >= 1000 && i < len(units)-1 {
+		b /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
@@ -0,0 +1,221 @@
+// Package audit appends a record of each change which the plugins make to a local log, so that when a purge or a
+// teardown goes wrong, there's a record of exactly what it deleted or changed, and what failed. The confluent CLI
+// commands of internal/cli and the requests of internal/cloud which change resources are recorded without the plugins
+// doing anything, and plugins which send requests to other APIs record them with Request.
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+)
+
+// Env is the environment variable of the file of the log, which defaults to ~/.confluent/plugins/audit.log. Setting it
+// to "off" turns the log off.
+const Env = "CONFLUENT_PLUGINS_AUDIT_LOG"
+
+// The outcomes of the changes.
+const (
+	Succeeded = "succeeded"
+	Failed    = "failed"
+)
+
+// A Record is a change which a plugin made, or tried to make, as a line of JSON in the log.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Plugin  string    `json:"plugin"`
+	Version string    `json:"version"`
+	// Run is the ID of the run of the plugin which made the change, which is the same for each change of the run.
+	Run string `json:"run"`
+	// Command is the plugin's command, with its arguments and flags, such as "confluent topic purge --prefix tmp-".
+	Command string `json:"command"`
+	// Action is the confluent CLI command, such as "confluent kafka topic delete tmp-1 --cluster lkc-123456", or the
+	// request, such as "DELETE /iam/v2/api-keys/ABCDEFGHIJKLMNOP", with their secrets masked.
+	Action string `json:"action"`
+	// Resources are the IDs and names of the resources which the action changed.
+	Resources []string `json:"resources,omitempty"`
+	Outcome   string   `json:"outcome"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Path returns the file of the log, or "" if it's turned off.
+func Path() (string, error) {
+	if path := os.Getenv(Env); path != "" {
+		if path == "off" {
+			return "", nil
+		}
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".confluent", "plugins", "audit.log"), nil
+}
+
+// verbs are the words of confluent CLI commands which change a resource, whose arguments are the resources.
+var verbs = []string{"create", "delete", "update", "start", "stop", "pause", "resume", "restart", "enable", "disable", "add", "remove", "bind", "unbind", "promote", "failover", "reverse-and-start", "reverse-and-pause", "accept", "redeem", "rotate", "import"}
+
+// resourceFlags are the flags of confluent CLI commands whose values are the resources which they change too.
+var resourceFlags = []string{"--cluster", "--environment", "--resource", "--service-account", "--compute-pool", "--principal", "--link"}
+
+// unaudited are the commands which don't change resources, but only the CLI's own state, such as its current
+// environment.
+var unaudited = []string{"use", "login", "logout", "context"}
+
+// Command records a confluent CLI command, which failed with err if it isn't nil, unless it only reads resources or
+// the CLI's state.
+func Command(args []string, err error) {
+	var resources []string
+	// The arguments of the verb are the resources, until the first flag, whose values aren't known to be resources.
+	verb, flags := false, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "-"):
+			flags = true
+			name, value, ok := strings.Cut(arg, "=")
+			if !slices.Contains(resourceFlags, name) {
+				continue
+			}
+			if !ok && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			resources = append(resources, value)
+		case slices.Contains(unaudited, arg) || arg == "list" || arg == "describe":
+			return
+		case verb && !flags:
+			resources = append(resources, arg)
+		case slices.Contains(verbs, arg):
+			verb = true
+		}
+	}
+	write("confluent "+strings.Join(args, " "), resources, err)
+}
+
+// Request records a request to an API, such as DELETE /iam/v2/api-keys/ABCDEFGHIJKLMNOP, which failed with err if it
+// isn't nil, unless it only reads resources. The resources which it changed are the segments of its path which follow
+// the name of a collection, such as the key of api-keys.
+func Request(method, path string, err error) {
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return
+	}
+	path, _, _ = strings.Cut(path, "?")
+	var resources []string
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if strings.HasSuffix(segments[i-1], "s") {
+			resources = append(resources, segments[i])
+		}
+	}
+	write(method+" "+path, resources, err)
+}
+
+var (
+	mu     sync.Mutex
+	runID  string
+	failed bool
+)
+
+// write appends the record of the action to the log. Failing to write it isn't an error of the plugin, but is warned
+// about once, since the change isn't on record.
+func write(action string, resources []string, err error) {
+	path, pathErr := Path()
+	if path == "" && pathErr == nil {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if runID == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		runID = hex.EncodeToString(b)
+	}
+
+	m := plugin.Current()
+	r := Record{
+		Time:      time.Now().UTC(),
+		Plugin:    m.Name,
+		Version:   m.Version,
+		Run:       runID,
+		Command:   logging.Redact(strings.Join(append([]string{command(m)}, os.Args[1:]...), " ")),
+		Action:    logging.Redact(action),
+		Resources: resources,
+		Outcome:   Succeeded,
+	}
+	if err != nil {
+		r.Outcome = Failed
+		r.Error = logging.Redact(err.Error())
+	}
+
+	if pathErr == nil {
+		pathErr = appendRecord(path, r)
+	}
+	if pathErr != nil && !failed {
+		failed = true
+		slog.Warn("Failed to write the audit log", "error", pathErr)
+	}
+}
+
+// command returns how the plugin is run, such as "confluent topic purge".
+func command(m plugin.Manifest) string {
+	if m.Name == "" {
+		return filepath.Base(os.Args[0])
+	}
+	return m.Entry().Command
+}
+
+func appendRecord(path string, r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// The log can name resources which are secret themselves, such as API keys, so only the user can read it.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	// The record is written at once, so that plugins which run at the same time don't interleave their records.
+	_, err = f.Write(append(b, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Read returns the records of the log, oldest first, skipping lines which aren't records, such as one which was cut
+// off.
+func Read(path string) ([]Record, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, line := range strings.Split(string(b), "\n") {
+		var r Record
+		if line == "" || json.Unmarshal([]byte(line), &r) != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NoCacheEnv is the environment variable which turns the cache off, as --no-cache does, when it's set to anything.
+const NoCacheEnv = "CONFLUENT_PLUGINS_NO_CACHE"
+
+// cacheTTL is how long the output of a cached command is used for. It's short, since resources which are created or
+// deleted by something other than the plugins aren't seen until it expires.
+const cacheTTL = 5 * time.Minute
+
+// cached are the commands whose output is cached, which plugins run to resolve names to IDs, often for each of many
+// resources, and whose output rarely changes.
+var cached = [][]string{
+	{"environment", "list"},
+	{"kafka", "cluster", "list"},
+	{"iam", "service-account", "list"},
+}
+
+// noCache is set by --no-cache.
+var noCache bool
+
+// AddCacheFlag adds --no-cache to the command and its subcommands, for plugins which list environments, Kafka clusters,
+// or service accounts. Their lists are cached on disk for a few minutes, so that plugins which look them up again, or
+// which are run again, don't run the same commands each time.
+func AddCacheFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, fmt.Sprintf("List environments, Kafka clusters, and service accounts again rather than reading them from the cache of the last %s.", cacheTTL))
+}
+
+// cacheable returns whether the output of the command is cached.
+func (c Command) cacheable() bool {
+	if noCache || os.Getenv(NoCacheEnv) != "" || c.Stdin != nil || len(c.Env) > 0 {
+		return false
+	}
+	return slices.ContainsFunc(cached, func(prefix []string) bool {
+		return len(c.Args) >= len(prefix) && slices.Equal(c.Args[:len(prefix)], prefix)
+	})
+}
+
+// cacheDir returns the directory of the cache, in the user's cache directory.
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "confluent-cli-plugins"), nil
+}
+
+// cachePath returns the file of the output of the command. Its name is a hash of the command and of the CLI's config,
+// so that the cache of one login or environment isn't used for another, since the config changes with them.
+func (c Command) cachePath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	config, err := os.ReadFile(filepath.Join(home, ".confluent", "config.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(strings.Join(c.Args, "\x00")))
+	h.Write([]byte{0})
+	h.Write(config)
+	return filepath.Join(dir, fmt.Sprintf("%x.json", h.Sum(nil))), nil
+}
+
+// readCache returns the cached output of the command, if it was cached in the last few minutes.
+func (c Command) readCache() ([]byte, bool) {
+	path, err := c.cachePath()
+	if err != nil {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > cacheTTL {
+		return nil, false
+	}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	slog.Debug("Read confluent output from the cache", "args", c.Args, "age", time.Since(info.ModTime()).Round(time.Second))
+	return out, true
+}
+
+// writeCache caches the output of the command. The cache is only an optimization, so failing to write it isn't an
+// error.
+func (c Command) writeCache(out []byte) {
+	path, err := c.cachePath()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	// The output is written next to its file and renamed, so that a plugin running at the same time never reads half
+	// of it.
+	f, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(out)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// clearCache deletes the cache, since a command which changes resources, such as creating an environment, can change
+// the lists in it.
+func clearCache() {
+	if dir, err := cacheDir(); err == nil {
+		_ = os.RemoveAll(dir)
+	}
+}
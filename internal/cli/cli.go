@@ -0,0 +1,166 @@
+// Package cli runs confluent CLI commands for the plugins, and decodes their JSON output.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/logging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// An Error is a confluent CLI command which failed, with what it wrote to stderr, which is the CLI's error message.
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+// Error masks secrets in the command and its message, such as the values of --api-secret and --password.
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return logging.Redact(fmt.Sprintf("confluent %s: %s", strings.Join(e.Args, " "), e.Stderr))
+	}
+	return logging.Redact(fmt.Sprintf("confluent %s: %v", strings.Join(e.Args, " "), e.Err))
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode is the exit code of the command, or -1 if it didn't run, such as when the CLI isn't installed.
+func (e *Error) ExitCode() int {
+	if exit, ok := e.Err.(*exec.ExitError); ok {
+		return exit.ExitCode()
+	}
+	return -1
+}
+
+// A Command is a confluent CLI command, with its input and environment.
+type Command struct {
+	Args []string
+	// Stdin is the input of the command, which is empty if it's nil, so that a command which prompts fails rather
+	// than waits.
+	Stdin io.Reader
+	// Env are environment variables, as "<name>=<value>" pairs, which are set on top of the plugin's.
+	Env []string
+}
+
+// Output runs the command and returns what it wrote to stdout. A command which is rate limited, or which only reads
+// and fails for a while, such as when the API is unavailable, is retried with retry.Default. Once the plugin is
+// interrupted, commands return interrupt.ErrInterrupted instead. The lists of environments, Kafka clusters, and
+// service accounts are read from the cache if they were listed in the last few minutes, and commands which change
+// resources clear it, and are recorded in the audit log.
+func (c Command) Output() ([]byte, error) {
+	cacheable := c.cacheable()
+	if cacheable {
+		if out, ok := c.readCache(); ok {
+			return out, nil
+		}
+	}
+
+	// The input is read once, so that it can be sent again if the command is retried.
+	var stdin []byte
+	if c.Stdin != nil {
+		b, err := io.ReadAll(c.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		stdin = b
+	}
+
+	var out []byte
+	err := retry.Default.Do(func() error {
+		var err error
+		out, err = c.run(stdin)
+		return err
+	})
+	if !readOnly(c.Args) {
+		clearCache()
+		audit.Command(c.Args, err)
+	}
+	if err == nil && cacheable {
+		c.writeCache(out)
+	}
+	return out, err
+}
+
+func (c Command) run(stdin []byte) ([]byte, error) {
+	if err := interrupt.Err(); err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	command := exec.Command("confluent", c.Args...)
+	if stdin != nil {
+		command.Stdin = bytes.NewReader(stdin)
+	}
+	command.Stderr = &stderr
+	if len(c.Env) > 0 {
+		command.Env = append(os.Environ(), c.Env...)
+	}
+
+	start := time.Now()
+	out, err := command.Output()
+	slog.Debug("Ran confluent", "args", c.Args, "elapsed", time.Since(start))
+	slog.Log(context.Background(), logging.LevelTrace, "confluent output", "stdout", string(out), "stderr", stderr.String())
+	if err != nil {
+		// Ctrl-C also stops the CLI, which fails for that reason rather than its own.
+		if interrupt.Err() != nil {
+			return nil, interrupt.ErrInterrupted
+		}
+		e := &Error{Args: c.Args, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+		if e.rateLimited() || e.unavailable() || (e.transient() && readOnly(c.Args)) {
+			return nil, &retry.Temporary{Err: e, After: e.retryAfter()}
+		}
+		return nil, e
+	}
+	return out, nil
+}
+
+// JSON runs the command with JSON output, and decodes the output into v.
+func (c Command) JSON(v any) error {
+	args := c.Args
+	c.Args = append(args[:len(args):len(args)], "--output", "json")
+	out, err := c.Output()
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("failed to parse the output of confluent %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// Run runs a confluent CLI command and returns its output.
+func Run(args ...string) ([]byte, error) {
+	return Command{Args: args}.Output()
+}
+
+// JSON runs a confluent CLI command with JSON output, and decodes the output into v.
+func JSON(v any, args ...string) error {
+	return Command{Args: args}.JSON(v)
+}
+
+// ClusterFlags scopes a command to the cluster and environment, which default to the CLI's current ones.
+func ClusterFlags(cluster, environment string) []string {
+	var args []string
+	if cluster != "" {
+		args = append(args, "--cluster", cluster)
+	}
+	if environment != "" {
+		args = append(args, "--environment", environment)
+	}
+	return args
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	rateLimited = regexp.MustCompile(`(?i)too many requests|rate.?limit|throttl`)
+	unavailable = regexp.MustCompile(`(?i)service unavailable`)
+	transient   = regexp.MustCompile(`(?i)bad gateway|gateway time-?out|connection reset|connection refused|i/o timeout|tls handshake timeout|unexpected eof|internal server error`)
+	retryAfter  = regexp.MustCompile(`(?i)retry.?after:?\s*(\d+)`)
+)
+
+// rateLimited is whether the API rejected the command's request since too many were sent.
+func (e *Error) rateLimited() bool {
+	return rateLimited.MatchString(e.Stderr)
+}
+
+// unavailable is whether the API rejected the command's request since it's down for a while.
+func (e *Error) unavailable() bool {
+	return unavailable.MatchString(e.Stderr)
+}
+
+// transient is whether the command failed in a way which may not happen again, but in which its request may have been
+// handled, so that only commands which don't change anything are retried.
+func (e *Error) transient() bool {
+	return transient.MatchString(e.Stderr)
+}
+
+// retryAfter is how long the API asked to wait before sending another request, in seconds, if it did.
+func (e *Error) retryAfter() time.Duration {
+	m := retryAfter.FindStringSubmatch(e.Stderr)
+	if m == nil {
+		return 0
+	}
+	seconds, _ := strconv.Atoi(m[1])
+	return time.Duration(seconds) * time.Second
+}
+
+// readOnly is whether the command only reads resources, such as "confluent kafka topic list".
+func readOnly(args []string) bool {
+	for _, arg := range args {
+		if len(arg) > 0 && arg[0] == '-' {
+			break
+		}
+		if arg == "list" || arg == "describe" {
+			return true
+		}
+	}
+	return false
+}
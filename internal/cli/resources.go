@@ -0,0 +1,141 @@
+package cli
+
+// Environment is an environment in the output of "confluent environment list".
+type Environment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// KafkaCluster is a cluster in the output of "confluent kafka cluster describe" and "confluent kafka cluster list".
+type KafkaCluster struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Cloud is the cloud provider in the output of "confluent kafka cluster list", and Provider is the one of
+	// "confluent kafka cluster describe".
+	Cloud        string `json:"cloud"`
+	Provider     string `json:"provider"`
+	Region       string `json:"region"`
+	Availability string `json:"availability"`
+	ClusterSize  int    `json:"cluster_size"`
+	Status       string `json:"status"`
+	Endpoint     string `json:"endpoint"`
+	RestEndpoint string `json:"rest_endpoint"`
+	Network      string `json:"network"`
+	// ByokID is the self-managed key which a dedicated cluster is encrypted with, if any.
+	ByokID string `json:"byok_id"`
+}
+
+// APIKey is an API key in the output of "confluent api-key list".
+type APIKey struct {
+	Key             string `json:"key"`
+	Description     string `json:"description"`
+	OwnerResourceID string `json:"owner_resource_id"`
+	OwnerEmail      string `json:"owner_email"`
+	ResourceType    string `json:"resource_type"`
+	ResourceID      string `json:"resource_id"`
+	Created         string `json:"created"`
+}
+
+// CreatedAPIKey is the output of "confluent api-key create", which is the only time that the secret is shown.
+type CreatedAPIKey struct {
+	Key    string `json:"api_key"`
+	Secret string `json:"api_secret"`
+}
+
+// Topic is a topic in the output of "confluent kafka topic list".
+type Topic struct {
+	Name              string `json:"name"`
+	IsInternal        bool   `json:"is_internal"`
+	ReplicationFactor int    `json:"replication_factor"`
+	PartitionCount    int    `json:"partition_count"`
+}
+
+// TopicConfig is a config in the output of "confluent kafka topic configuration list".
+type TopicConfig struct {
+	Name           string `json:"name"`
+	Value          string `json:"value"`
+	IsDefaultValue bool   `json:"is_default_value"`
+	IsReadOnly     bool   `json:"is_read_only"`
+	IsSensitive    bool   `json:"is_sensitive"`
+}
+
+// Connector is a connector in the output of "confluent connect cluster list".
+type Connector struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// RoleBinding is a role binding in the output of "confluent iam rbac role-binding list".
+type RoleBinding struct {
+	Principal      string `json:"principal"`
+	Role           string `json:"role"`
+	Environment    string `json:"environment"`
+	CloudCluster   string `json:"cloud_cluster"`
+	LogicalCluster string `json:"logical_cluster"`
+	ResourceType   string `json:"resource_type"`
+	Name           string `json:"name"`
+	PatternType    string `json:"pattern_type"`
+}
+
+// ACL is an ACL in the output of "confluent kafka acl list".
+type ACL struct {
+	Principal    string `json:"principal"`
+	Permission   string `json:"permission"`
+	Operation    string `json:"operation"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Host         string `json:"host"`
+}
+
+// ListEnvironments lists the environments of the organization.
+func ListEnvironments() ([]Environment, error) {
+	var environments []Environment
+	if err := JSON(&environments, "environment", "list"); err != nil {
+		return nil, err
+	}
+	return environments, nil
+}
+
+// ListKafkaClusters lists the clusters of the environment, which defaults to the current one.
+func ListKafkaClusters(environment string) ([]KafkaCluster, error) {
+	var clusters []KafkaCluster
+	if err := JSON(&clusters, append([]string{"kafka", "cluster", "list"}, ClusterFlags("", environment)...)...); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// DescribeKafkaCluster describes the cluster, which defaults to the current one.
+func DescribeKafkaCluster(cluster, environment string) (KafkaCluster, error) {
+	args := []string{"kafka", "cluster", "describe"}
+	if cluster != "" {
+		args = append(args, cluster)
+	}
+	var c KafkaCluster
+	if err := JSON(&c, append(args, ClusterFlags("", environment)...)...); err != nil {
+		return KafkaCluster{}, err
+	}
+	return c, nil
+}
+
+// ListAPIKeys lists the API keys, filtered by flags such as --resource or --service-account.
+func ListAPIKeys(flags ...string) ([]APIKey, error) {
+	var keys []APIKey
+	if err := JSON(&keys, append([]string{"api-key", "list"}, flags...)...); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ListTopics lists the topics of the cluster, which defaults to the current one.
+func ListTopics(cluster, environment string) ([]Topic, error) {
+	var topics []Topic
+	if err := JSON(&topics, append([]string{"kafka", "topic", "list"}, ClusterFlags(cluster, environment)...)...); err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
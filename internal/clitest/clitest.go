@@ -0,0 +1,274 @@
+// Package clitest fakes the confluent CLI in tests of the plugins, so that a plugin's control flow can be tested
+// without the CLI or Confluent Cloud: a test scripts the output of the commands which the plugin runs, runs the plugin,
+// and checks the commands which it ran.
+//
+// The fake confluent CLI is the test binary itself, which New puts first on $PATH, and which asks the test for the
+// output of each command. The TestMain of the test's package has to call Main for it:
+//
+//	func TestMain(m *testing.M) {
+//		clitest.Main(m)
+//	}
+//
+//	func TestTeardown(t *testing.T) {
+//		cli := clitest.New(t)
+//		cli.On("kafka", "cluster", "list").Return([]map[string]string{{"id": "lkc-123456", "name": "orders"}})
+//		cli.On("kafka", "cluster", "delete", "lkc-123456")
+//
+//		if _, _, err := clitest.Execute(newTeardownCommand(), "--force"); err != nil {
+//			t.Fatal(err)
+//		}
+//		if cli.Called("kafka", "cluster", "delete", "lkc-123456") != 1 {
+//			t.Error("the cluster wasn't deleted")
+//		}
+//	}
+//
+// Since New sets $PATH, tests which use it can't run in parallel.
+package clitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+// urlEnv is the environment variable with the URL which the fake CLI asks for the output of its commands.
+const urlEnv = "CONFLUENT_CLITEST_URL"
+
+// A Call is a command which the plugin ran.
+type Call struct {
+	Args  []string `json:"args"`
+	Stdin string   `json:"stdin"`
+}
+
+// A response is the output of a command.
+type response struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// A Rule is the output of the commands which start with its arguments. A rule succeeds without output until its output
+// is set.
+type Rule struct {
+	args     []string
+	once     bool
+	used     bool
+	response response
+}
+
+// Once makes the rule only match one command, so that the same command can be scripted to return different output
+// each time, such as a status which changes.
+func (r *Rule) Once() *Rule {
+	r.once = true
+	return r
+}
+
+// Return makes the commands print v as JSON, as with --output json.
+func (r *Rule) Return(v any) *Rule {
+	out, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("clitest: failed to encode the output of confluent %s: %v", strings.Join(r.args, " "), err))
+	}
+	return r.Output(string(out) + "\n")
+}
+
+// Output makes the commands print stdout.
+func (r *Rule) Output(stdout string) *Rule {
+	r.response = response{Stdout: stdout}
+	return r
+}
+
+// Fail makes the commands fail with the error message, such as "Error: 429 Too Many Requests", and exit code 1.
+func (r *Rule) Fail(stderr string) *Rule {
+	r.response = response{Stderr: stderr + "\n", ExitCode: 1}
+	return r
+}
+
+// A Fake is a fake confluent CLI, which returns the output of the first rule each command matches.
+type Fake struct {
+	t testing.TB
+
+	mu    sync.Mutex
+	rules []*Rule
+	calls []Call
+}
+
+// New puts a fake confluent CLI first on $PATH for the rest of the test, with the cache and the audit log turned off. A
+// command which no rule matches fails the test, and the command itself fails.
+func New(t testing.TB) *Fake {
+	t.Helper()
+	f := &Fake{t: t}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("clitest: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(f.serve)}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("clitest: %v", err)
+	}
+	dir := t.TempDir()
+	if err := link(executable, filepath.Join(dir, platform.Executable("confluent"))); err != nil {
+		t.Fatalf("clitest: %v", err)
+	}
+
+	t.Setenv(urlEnv, "http://"+listener.Addr().String())
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	// Every command reaches the fake, rather than the output of an earlier test in the cache.
+	t.Setenv(cli.NoCacheEnv, "1")
+	// The commands which the plugin changes resources with aren't recorded in the user's audit log.
+	t.Setenv(audit.Env, "off")
+	return f
+}
+
+// link makes path run the executable, with a symlink, or a copy where symlinks need privileges, such as on Windows.
+func link(executable, path string) error {
+	if err := os.Symlink(executable, path); err == nil {
+		return nil
+	}
+	in, err := os.Open(executable)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// On adds a rule for the commands which start with args, such as "kafka", "topic", "list", so that flags which the
+// plugin adds, such as --output json, don't have to be listed.
+func (f *Fake) On(args ...string) *Rule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := &Rule{args: args}
+	f.rules = append(f.rules, r)
+	return r
+}
+
+// Calls returns the commands which the plugin ran, in order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Called returns how many of the commands which the plugin ran start with args.
+func (f *Fake) Called(args ...string) int {
+	n := 0
+	for _, c := range f.Calls() {
+		if hasPrefix(c.Args, args) {
+			n++
+		}
+	}
+	return n
+}
+
+func hasPrefix(args, prefix []string) bool {
+	return len(args) >= len(prefix) && slices.Equal(args[:len(prefix)], prefix)
+}
+
+func (f *Fake) serve(w http.ResponseWriter, req *http.Request) {
+	var c Call
+	if err := json.NewDecoder(req.Body).Decode(&c); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, c)
+	var res *response
+	for _, r := range f.rules {
+		if r.once && r.used || !hasPrefix(c.Args, r.args) {
+			continue
+		}
+		r.used = true
+		res = &r.response
+		break
+	}
+	f.mu.Unlock()
+
+	if res == nil {
+		f.t.Errorf("clitest: unexpected command: confluent %s", strings.Join(c.Args, " "))
+		res = &response{Stderr: fmt.Sprintf("Error: clitest: no rule for confluent %s\n", strings.Join(c.Args, " ")), ExitCode: 1}
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Main runs the tests, or when the test binary is run as the fake confluent CLI, asks the test for the output of the
+// command, prints it, and exits with its exit code.
+func Main(m *testing.M) {
+	url := os.Getenv(urlEnv)
+	if url == "" || strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") != "confluent" {
+		os.Exit(m.Run())
+	}
+	r, err := ask(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: clitest: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprint(os.Stdout, r.Stdout)
+	fmt.Fprint(os.Stderr, r.Stderr)
+	os.Exit(r.ExitCode)
+}
+
+// ask asks the test for the output of the command which the fake CLI was run with.
+func ask(url string) (response, error) {
+	stdin, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return response{}, err
+	}
+	body, err := json.Marshal(Call{Args: os.Args[1:], Stdin: string(stdin)})
+	if err != nil {
+		return response{}, err
+	}
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return response{}, err
+	}
+	defer res.Body.Close()
+
+	var r response
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return response{}, err
+	}
+	return r, nil
+}
+
+// Execute runs the plugin's command with the args, as the confluent CLI runs a plugin, and returns what it wrote to
+// stdout and stderr. Its input is empty, unless it was set with cmd.SetIn.
+func Execute(cmd *cobra.Command, args ...string) (stdout, stderr string, err error) {
+	var out, errOut bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	if cmd.InOrStdin() == os.Stdin {
+		cmd.SetIn(strings.NewReader(""))
+	}
+	err = cmd.Execute()
+	return out.String(), errOut.String(), err
+}
@@ -0,0 +1,135 @@
+package clitest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+func TestMain(m *testing.M) {
+	Main(m)
+}
+
+func TestReturn(t *testing.T) {
+	fake := New(t)
+	fake.On("kafka", "topic", "list").Return([]cli.Topic{{Name: "orders"}, {Name: "users"}})
+
+	var topics []cli.Topic
+	if err := cli.JSON(&topics, "kafka", "topic", "list", "--cluster", "lkc-123456"); err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Name != "orders" || topics[1].Name != "users" {
+		t.Errorf("got topics %+v, want orders and users", topics)
+	}
+
+	calls := fake.Calls()
+	want := []string{"kafka", "topic", "list", "--cluster", "lkc-123456", "--output", "json"}
+	if len(calls) != 1 || !slices.Equal(calls[0].Args, want) {
+		t.Errorf("got calls %+v, want confluent %s", calls, strings.Join(want, " "))
+	}
+}
+
+func TestOnce(t *testing.T) {
+	fake := New(t)
+	fake.On("flink", "statement", "describe").Once().Output("PENDING\n")
+	fake.On("flink", "statement", "describe").Output("RUNNING\n")
+
+	var statuses []string
+	for i := 0; i < 3; i++ {
+		out, err := cli.Run("flink", "statement", "describe", "orders")
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, strings.TrimSpace(string(out)))
+	}
+	if want := []string{"PENDING", "RUNNING", "RUNNING"}; !slices.Equal(statuses, want) {
+		t.Errorf("got statuses %v, want %v", statuses, want)
+	}
+	if n := fake.Called("flink", "statement", "describe", "orders"); n != 3 {
+		t.Errorf("the statement was described %d times, want 3", n)
+	}
+}
+
+func TestFail(t *testing.T) {
+	fake := New(t)
+	fake.On("kafka", "topic", "delete").Fail(`Error: topic "orders" is protected`)
+
+	_, err := cli.Run("kafka", "topic", "delete", "orders", "--force")
+	var cliErr *cli.Error
+	if !errors.As(err, &cliErr) {
+		t.Fatalf("got error %v, want a *cli.Error", err)
+	}
+	if cliErr.ExitCode() != 1 {
+		t.Errorf("got exit code %d, want 1", cliErr.ExitCode())
+	}
+	if want := `Error: topic "orders" is protected`; cliErr.Stderr != want {
+		t.Errorf("got stderr %q, want %q", cliErr.Stderr, want)
+	}
+	// A failure which isn't temporary isn't retried.
+	if n := fake.Called("kafka", "topic", "delete"); n != 1 {
+		t.Errorf("the topic was deleted %d times, want 1", n)
+	}
+}
+
+func TestStdin(t *testing.T) {
+	fake := New(t)
+	fake.On("kafka", "topic", "produce")
+
+	if _, err := (cli.Command{Args: []string{"kafka", "topic", "produce", "orders"}, Stdin: strings.NewReader("1|{}\n")}).Output(); err != nil {
+		t.Fatal(err)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].Stdin != "1|{}\n" {
+		t.Errorf("got calls %+v, want the records on stdin", calls)
+	}
+}
+
+// recorder records the errors of a test, rather than failing it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestUnexpectedCommand(t *testing.T) {
+	r := &recorder{TB: t}
+	New(r)
+
+	_, err := cli.Run("environment", "delete", "env-123456", "--force")
+	if err == nil {
+		t.Fatal("a command without a rule succeeded")
+	}
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "unexpected command: confluent environment delete env-123456") {
+		t.Errorf("got test errors %q, want the unexpected command", r.errors)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "greet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if _, err := fmt.Fscanln(cmd.InOrStdin(), &name); err != nil {
+				name = "world"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Hello, %s.\n", name)
+			fmt.Fprintln(cmd.ErrOrStderr(), "Greeted.")
+			return nil
+		},
+	}
+
+	stdout, stderr, err := Execute(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != "Hello, world.\n" || stderr != "Greeted.\n" {
+		t.Errorf("got stdout %q and stderr %q, want the greeting of empty input", stdout, stderr)
+	}
+}
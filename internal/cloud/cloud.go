@@ -0,0 +1,227 @@
+// Package cloud sends requests to the Confluent Cloud API directly, for plugins which would otherwise run a confluent
+// CLI command for each of many resources, and spend most of their time starting the CLI. It authenticates as the CLI
+// is logged in, or with a Cloud API key.
+package cloud
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/audit"
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/paging"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+	"github.com/spf13/cobra"
+)
+
+// RESTEnv is the environment variable which turns REST mode on, as --rest does, when it's set to anything.
+const RESTEnv = "CONFLUENT_PLUGINS_REST"
+
+// URL is the URL of the Confluent Cloud API.
+var URL = "https://api.confluent.cloud"
+
+// rest is set by --rest.
+var rest bool
+
+// AddFlag adds --rest to the command and its subcommands, for plugins which can send their requests to the Cloud API
+// rather than run a confluent CLI command for each resource.
+func AddFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&rest, "rest", false, "Send requests to the Confluent Cloud API directly rather than running a confluent CLI command for each, as the CLI is logged in, or with $CONFLUENT_CLOUD_API_KEY and $CONFLUENT_CLOUD_API_SECRET.")
+}
+
+// Enabled returns whether the plugin runs in REST mode, with --rest or $CONFLUENT_PLUGINS_REST.
+func Enabled() bool {
+	return rest || os.Getenv(RESTEnv) != ""
+}
+
+// A Client sends requests to the Cloud API, retrying those which are rate limited.
+type Client struct {
+	authorize func(*http.Request)
+	client    *http.Client
+}
+
+// New returns a client which authenticates with the Cloud API key of $CONFLUENT_CLOUD_API_KEY and
+// $CONFLUENT_CLOUD_API_SECRET, or else with the token of the CLI's login.
+func New() (*Client, error) {
+	c := &Client{client: retry.Client(30 * time.Second)}
+	if key, secret := os.Getenv("CONFLUENT_CLOUD_API_KEY"), os.Getenv("CONFLUENT_CLOUD_API_SECRET"); key != "" && secret != "" {
+		c.authorize = func(req *http.Request) { req.SetBasicAuth(key, secret) }
+		return c, nil
+	}
+
+	token, err := loginToken()
+	if err != nil {
+		return nil, exitcode.With(exitcode.Auth, fmt.Errorf("%w; set CONFLUENT_CLOUD_API_KEY and CONFLUENT_CLOUD_API_SECRET to use --rest with a Cloud API key instead", err))
+	}
+	c.authorize = func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+	return c, nil
+}
+
+// loginToken returns the token of the CLI's current context, from ~/.confluent/config.json, if it's logged in to
+// Confluent Cloud and the token hasn't expired.
+func loginToken() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(filepath.Join(home, ".confluent", "config.json"))
+	if os.IsNotExist(err) {
+		return "", errors.New("the confluent CLI isn't logged in")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var config struct {
+		CurrentContext string `json:"current_context"`
+		ContextStates  map[string]struct {
+			AuthToken string `json:"auth_token"`
+		} `json:"context_states"`
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return "", fmt.Errorf("failed to parse the config of the confluent CLI: %w", err)
+	}
+	token := config.ContextStates[config.CurrentContext].AuthToken
+	if token == "" {
+		return "", errors.New("the confluent CLI isn't logged in to Confluent Cloud")
+	}
+
+	// The token is a JWT, unless the CLI encrypted it, and it can't be read.
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("the CLI's login token is encrypted")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("the CLI's login token is encrypted")
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err == nil && claims.Exp != 0 && time.Now().After(time.Unix(claims.Exp, 0)) {
+		return "", errors.New(`the login of the confluent CLI expired, log in again with "confluent login"`)
+	}
+	return token, nil
+}
+
+// Do sends a request to the path of the Cloud API, with the query and with body as JSON if it isn't nil, and decodes
+// the response into v if it isn't nil. A response other than 2xx is an error, with the API's message. A request which
+// changes resources is recorded in the audit log.
+func (c *Client) Do(method, path string, query url.Values, body, v any) (err error) {
+	defer func() { audit.Request(method, path, err) }()
+
+	u := URL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &Error{Method: method, Path: path, Status: res.Status, Message: message(b)}
+	}
+	if v == nil || len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse the response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// List lists the resources of the path, following their pages.
+func List[T any](c *Client, path string, query url.Values) ([]T, error) {
+	return paging.All(func(token string) ([]T, string, error) {
+		q := url.Values{"page_size": {"100"}}
+		for k, v := range query {
+			q[k] = v
+		}
+		if token != "" {
+			q.Set("page_token", token)
+		}
+		var page paging.CloudPage[T]
+		if err := c.Do(http.MethodGet, path, q, nil, &page); err != nil {
+			return nil, "", err
+		}
+		return page.Data, page.Next(), nil
+	})
+}
+
+// An Error is a request which the Cloud API failed, with its status, such as "404 Not Found", and its message.
+type Error struct {
+	Method  string
+	Path    string
+	Status  string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s: %s: %s", e.Method, e.Path, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Path, e.Status)
+}
+
+// message returns the message of an error response, whose shape differs between the APIs.
+func message(body []byte) string {
+	var v struct {
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return ""
+	}
+	var details []string
+	for _, e := range v.Errors {
+		if e.Detail != "" {
+			details = append(details, e.Detail)
+		}
+	}
+	switch {
+	case len(details) > 0:
+		return strings.Join(details, "; ")
+	case v.Error != nil:
+		return v.Error.Message
+	}
+	return v.Message
+}
@@ -0,0 +1,79 @@
+// Command index writes index.yml, the list of the plugins in the repository with their manifests, which "confluent
+// plugin search" reads rather than every manifest.yml. Run it from the root of the repository after adding a plugin or
+// changing a manifest:
+//
+//	go run ./internal/cmd/index
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const header = "# Code generated by go run ./internal/cmd/index. DO NOT EDIT.\n"
+
+// An index is the plugins of the repository.
+type index struct {
+	Plugins []plugin.Entry `yaml:"plugins"`
+}
+
+func main() {
+	cmd := cobra.Command{
+		Use:   "index",
+		Short: "Write the index of the plugins from their manifests.",
+		Args:  cobra.NoArgs,
+		RunE:  write,
+	}
+
+	cmd.Flags().String("file", "index.yml", "File of the index.")
+	cmd.Flags().Bool("check", false, "Fail if the index is out of date rather than write it, such as in CI.")
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func write(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	file, err := cmd.Flags().GetString("file")
+	cobra.CheckErr(err)
+
+	check, err := cmd.Flags().GetBool("check")
+	cobra.CheckErr(err)
+
+	entries, err := plugin.Index(".")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no plugins were found, run it from the root of the repository")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(header)
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(index{Plugins: entries}); err != nil {
+		return err
+	}
+
+	if check {
+		current, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		// A checkout on Windows can have "\r\n" line endings, which is still up to date.
+		if platform.Text(current) != b.String() {
+			return fmt.Errorf("%s is out of date, run go run ./internal/cmd/index", file)
+		}
+		return nil
+	}
+	return os.WriteFile(file, b.Bytes(), 0o644)
+}
@@ -0,0 +1,163 @@
+// Command release builds the files of a release, which "confluent plugin update" and "confluent plugin bundle"
+// download: the binary of every Go plugin for every platform, the script of every Python plugin, the index of the
+// plugins, and the checksums of the files. Run it from the root of the
+// repository, and upload the files in the directory to a GitHub release:
+//
+//	go run ./internal/cmd/release
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// defaultPlatforms are the platforms which the plugins are built for, as <os>/<arch>.
+var defaultPlatforms = []string{"darwin/amd64", "darwin/arm64", "linux/amd64", "linux/arm64", "windows/amd64", "windows/arm64"}
+
+// buildPackage is the package whose variables are the commit and the date of the build.
+const buildPackage = "github.com/confluentinc/cli-plugins/internal/plugin"
+
+func main() {
+	cmd := cobra.Command{
+		Use:   "release",
+		Short: "Build the binaries of the Go plugins for a release.",
+		Args:  cobra.NoArgs,
+		RunE:  build,
+	}
+
+	cmd.Flags().String("dir", "dist", "Directory to write the files of the release to.")
+	cmd.Flags().StringSlice("platform", defaultPlatforms, "Platforms to build for, as <os>/<arch>.")
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func build(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	dir, err := cmd.Flags().GetString("dir")
+	cobra.CheckErr(err)
+
+	platforms, err := cmd.Flags().GetStringSlice("platform")
+	cobra.CheckErr(err)
+
+	entries, err := plugin.Index(".")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no plugins were found, run it from the root of the repository")
+	}
+	// The index of the release is the index of the repository, which has to be up to date.
+	if err := exec.Command("go", "run", "./internal/cmd/index", "--check").Run(); err != nil {
+		return fmt.Errorf("%s is out of date, run go run ./internal/cmd/index", plugin.IndexFile)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// The plugins print the commit and the date of their build with --version.
+	commit, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return fmt.Errorf("failed to read the commit of the release: %w", err)
+	}
+	ldflags := fmt.Sprintf("-s -w -X %[1]s.commit=%[2]s -X %[1]s.date=%[3]s",
+		buildPackage, strings.TrimSpace(string(commit)), time.Now().UTC().Format(time.RFC3339))
+
+	out := cmd.OutOrStdout()
+	for _, e := range entries {
+		// The Python plugins are installed as scripts, with their requirements if they have any, and aren't built.
+		if e.Runtime.Name == "Python" {
+			if err := copyFile(filepath.Join(e.Name, plugin.Script(e.Name)), filepath.Join(dir, plugin.Script(e.Name))); err != nil {
+				return err
+			}
+			requirements := filepath.Join(e.Name, "requirements.txt")
+			if _, err := os.Stat(requirements); err == nil {
+				if err := copyFile(requirements, filepath.Join(dir, plugin.Requirements(e.Name))); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(out, "Copied %s.\n", plugin.Script(e.Name))
+			continue
+		}
+		if e.Runtime.Name != "Go" {
+			continue
+		}
+		for _, platform := range platforms {
+			goos, goarch, ok := strings.Cut(platform, "/")
+			if !ok {
+				return fmt.Errorf(`invalid platform "%s", expected <os>/<arch>`, platform)
+			}
+			asset := plugin.Asset(e.Name, goos, goarch)
+			command := exec.Command("go", "build", "-trimpath", "-ldflags", ldflags, "-o", filepath.Join(dir, asset), "./"+e.Name)
+			command.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch, "CGO_ENABLED=0")
+			command.Stderr = cmd.ErrOrStderr()
+			if err := command.Run(); err != nil {
+				return fmt.Errorf("failed to build %s: %w", asset, err)
+			}
+			fmt.Fprintf(out, "Built %s.\n", asset)
+		}
+	}
+
+	if err := copyFile(plugin.IndexFile, filepath.Join(dir, plugin.IndexFile)); err != nil {
+		return err
+	}
+	return writeChecksums(dir)
+}
+
+func copyFile(src, dst string) error {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, b, 0o644)
+}
+
+// writeChecksums writes the checksums of the files in the directory, sorted by name, as sha256sum does.
+func writeChecksums(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && f.Name() != plugin.ChecksumsFile {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		sum, err := checksum(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s\n", sum, name))
+	}
+	return os.WriteFile(filepath.Join(dir, plugin.ChecksumsFile), []byte(strings.Join(lines, "")), 0o644)
+}
+
+func checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
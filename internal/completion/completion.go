@@ -0,0 +1,142 @@
+// Package completion adds the completion command which the plugins share, which prints the script that completes a
+// plugin's subcommands and flags in bash, zsh, fish, or PowerShell, with the IDs of environments and Kafka clusters
+// listed by the confluent CLI.
+package completion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Add adds the completion command to the command, and completes the flags of the command and its subcommands. It's
+// called in main once the command and its subcommands are built, with the name of the plugin, which is the command
+// that the script completes.
+func Add(cmd *cobra.Command, plugin string) {
+	// A command without subcommands takes any arguments unless it says otherwise, but with the completion command it
+	// would take none.
+	if cmd.Args == nil && !cmd.HasSubCommands() {
+		cmd.Args = cobra.ArbitraryArgs
+	}
+	cmd.CompletionOptions.DisableDefaultCmd = true
+
+	cmd.AddCommand(&cobra.Command{
+		Use:       "completion <shell>",
+		Short:     "Print the script which completes the flags of the plugin in bash, zsh, fish, or PowerShell.",
+		Long:      fmt.Sprintf("Print the script which completes the subcommands and flags of %s in bash, zsh, fish, or PowerShell, including the IDs of environments and Kafka clusters, which are listed with the confluent CLI.", plugin),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: shells,
+		// The flags which the plugin requires aren't needed to print the script.
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			cmd.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
+				delete(flag.Annotations, cobra.BashCompOneRequiredFlag)
+			})
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return script(cmd, plugin, args[0])
+		},
+		Example: fmt.Sprintf(`source <(%[1]s completion bash)
+%[1]s completion zsh > "${fpath[1]}/_%[1]s"
+%[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+%[1]s completion powershell | Out-String | Invoke-Expression`, plugin),
+	})
+
+	registered := map[*pflag.Flag]bool{}
+	var visit func(*cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		for _, set := range []*pflag.FlagSet{cmd.PersistentFlags(), cmd.Flags()} {
+			set.VisitAll(func(flag *pflag.Flag) {
+				if complete := completer(flag); complete != nil && !registered[flag] {
+					registered[flag] = true
+					cobra.CheckErr(cmd.RegisterFlagCompletionFunc(flag.Name, complete))
+				}
+			})
+		}
+		for _, sub := range cmd.Commands() {
+			visit(sub)
+		}
+	}
+	visit(cmd)
+}
+
+// script prints the completion script of the shell, for the plugin's binary rather than for its command, which is
+// how the shell sees it.
+func script(cmd *cobra.Command, plugin, shell string) error {
+	root := cmd.Root()
+	root.Use = plugin
+	w := cmd.OutOrStdout()
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(w, true)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(w)
+	}
+	return fmt.Errorf(`unsupported shell "%s", supported shells: %s`, shell, strings.Join(shells, ", "))
+}
+
+type completeFunc func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)
+
+// completer returns how the flag is completed, from its name: flags of environments, such as --environment and
+// --source-environment, complete environment IDs, and flags of clusters complete the IDs of the Kafka clusters of the
+// environment of the matching flag, such as --source-environment for --source-cluster. Flags with formats, such as
+// --output, complete their formats.
+func completer(flag *pflag.Flag) completeFunc {
+	if formats, ok := flag.Annotations["formats"]; ok {
+		return cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp)
+	}
+	if flag.Value.Type() != "string" {
+		return nil
+	}
+	switch {
+	case flag.Name == "environment" || strings.HasSuffix(flag.Name, "-environment"):
+		return environments
+	case flag.Name == "cluster" || strings.HasSuffix(flag.Name, "-cluster"):
+		return clusters(strings.TrimSuffix(flag.Name, "cluster") + "environment")
+	}
+	return nil
+}
+
+func environments(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	list, err := cli.ListEnvironments()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var ids []string
+	for _, e := range list {
+		ids = append(ids, e.ID+"\t"+e.Name)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// clusters completes the Kafka clusters of the environment which is passed with the flag, or with --environment, or
+// else of the current environment.
+func clusters(environmentFlag string) completeFunc {
+	return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		var environment string
+		for _, name := range []string{environmentFlag, "environment"} {
+			if flag := cmd.Flags().Lookup(name); flag != nil && flag.Value.String() != "" {
+				environment = flag.Value.String()
+				break
+			}
+		}
+		list, err := cli.ListKafkaClusters(environment)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var ids []string
+		for _, c := range list {
+			ids = append(ids, c.ID+"\t"+c.Name)
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+}
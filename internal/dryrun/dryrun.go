@@ -0,0 +1,110 @@
+// Package dryrun is the --dry-run flag of the plugins which change resources, and how they print the changes which
+// they would make, and with --output json or yaml the ones they made, so that every plugin reports its changes the same
+// way.
+package dryrun
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// AddFlag adds --dry-run to the command.
+func AddFlag(cmd *cobra.Command, description string) {
+	cmd.Flags().Bool("dry-run", false, description)
+}
+
+// An Action is a change which a plugin would make, such as deleting a topic.
+type Action struct {
+	Verb     string `json:"action"`
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	// Detail is the rest of the sentence, such as "from orders.json".
+	Detail string `json:"detail,omitempty"`
+}
+
+// String describes the action, such as `delete topic "orders"`.
+func (a Action) String() string {
+	words := []string{a.Verb, a.Resource}
+	if a.Name != "" {
+		words = append(words, fmt.Sprintf(`"%s"`, a.Name))
+	}
+	if a.Detail != "" {
+		words = append(words, a.Detail)
+	}
+	return strings.Join(words, " ")
+}
+
+// A Plan is the changes which a plugin would make, in the order in which it would make them.
+type Plan struct {
+	Actions []Action `json:"actions"`
+}
+
+// Add adds an action to the plan.
+func (p *Plan) Add(verb, resource, name string) {
+	p.Actions = append(p.Actions, Action{Verb: verb, Resource: resource, Name: name})
+}
+
+// AddDetail adds an action to the plan, with the rest of its sentence.
+func (p *Plan) AddDetail(verb, resource, name, detail string) {
+	p.Actions = append(p.Actions, Action{Verb: verb, Resource: resource, Name: name, Detail: detail})
+}
+
+// Print prints the plan to the command's stdout, one action per line, such as `Would delete topic "orders".`, and
+// that nothing was changed. With --output json or yaml, if the command takes it, the actions are printed instead,
+// with "dry_run": true.
+func Print(cmd *cobra.Command, p Plan) error {
+	format := output.Table
+	if cmd.Flags().Lookup("output") != nil {
+		var err error
+		if format, err = output.Format(cmd); err != nil {
+			return err
+		}
+	}
+
+	actions := p.Actions
+	if actions == nil {
+		actions = []Action{}
+	}
+	v := struct {
+		DryRun  bool     `json:"dry_run"`
+		Actions []Action `json:"actions"`
+	}{true, actions}
+
+	out := cmd.OutOrStdout()
+	return output.Print(out, format, v, func() {
+		for _, a := range actions {
+			fmt.Fprintf(out, "Would %s.\n", a)
+		}
+		switch len(actions) {
+		case 0:
+			fmt.Fprintln(out, "Dry run: there is nothing to change.")
+		case 1:
+			fmt.Fprintln(out, "Dry run: 1 change was planned, and nothing was changed.")
+		default:
+			fmt.Fprintf(out, "Dry run: %d changes were planned, and nothing was changed.\n", len(actions))
+		}
+	})
+}
+
+// PrintApplied prints the changes which the plugin made with --output json or yaml, as Print prints the ones it would
+// make, with "dry_run": false. It prints nothing with --output table, since the plugins print their changes as a table
+// as they make them.
+func PrintApplied(cmd *cobra.Command, p Plan) error {
+	format, err := output.Format(cmd)
+	if err != nil || format == output.Table {
+		return err
+	}
+
+	actions := p.Actions
+	if actions == nil {
+		actions = []Action{}
+	}
+	v := struct {
+		DryRun  bool     `json:"dry_run"`
+		Actions []Action `json:"actions"`
+	}{false, actions}
+	return output.Print(cmd.OutOrStdout(), format, v, nil)
+}
@@ -0,0 +1,170 @@
+// Package exitcode maps the errors of the plugins to exit codes which mean the same in every plugin, so that scripts
+// which run them can tell why one failed, such as to log in again rather than retry, whichever plugin it was.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// The exit codes of the plugins. They're documented in the README, and are never renumbered. Codes from 10 are left to
+// the failures which only one plugin has, such as a browser which fails to start in confluent login headless-sso.
+const (
+	// Failure is a failure which isn't one of the others.
+	Failure = 1
+	// Findings is a check which ran and found problems, such as drift, incompatible schemas, or lagging consumers.
+	Findings = 2
+	// Auth is a failure to authenticate or a lack of permission, such as when the CLI isn't logged in.
+	Auth = 3
+	// NotFound is a resource which doesn't exist, such as a cluster ID with a typo.
+	NotFound = 4
+	// Quota is a quota or limit of Confluent Cloud which was reached, such as the number of clusters.
+	Quota = 5
+	// RateLimited is a request which was still rate limited after being retried.
+	RateLimited = 6
+	// Validation is an invalid flag or argument, which failed before anything was done.
+	Validation = 7
+	// PartialFailure is a bulk operation in which some resources succeeded and others failed.
+	PartialFailure = 8
+	// Interrupted is a plugin which was stopped with Ctrl-C.
+	Interrupted = interrupt.ExitCode
+)
+
+// An Error is an error with the exit code of its category.
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// With assigns the exit code to the error.
+func With(code int, err error) error {
+	return &Error{Code: code, Err: err}
+}
+
+// New returns an error with the message and the exit code, such as the error of a check which found problems.
+func New(code int, message string) error {
+	return &Error{Code: code, Err: errors.New(message)}
+}
+
+// Invalid returns a validation error, such as of a flag whose value is out of range.
+func Invalid(format string, a ...any) error {
+	return &Error{Code: Validation, Err: fmt.Errorf(format, a...)}
+}
+
+// The messages of the confluent CLI and of the Confluent Cloud APIs for each category, which are matched in that
+// order, since a rate limit is a limit too.
+var categories = []struct {
+	code    int
+	message *regexp.Regexp
+}{
+	{RateLimited, regexp.MustCompile(`(?i)too many requests|rate.?limit|throttl|\b429\b`)},
+	{Auth, regexp.MustCompile(`(?i)not logged in|must be logged in|log in|unauthori[sz]ed|forbidden|\b40[13]\b|permission denied|not authorized|authentication failed|invalid (api key|credentials)|token (is )?expired`)},
+	{Quota, regexp.MustCompile(`(?i)quota|exceeds? (the |your )?(limit|maximum)|limit (exceeded|reached)`)},
+	{NotFound, regexp.MustCompile(`(?i)not found|\b404\b|does not exist|doesn't exist`)},
+}
+
+// Of returns the exit code of the error: 0 if it's nil, the code of the first *Error which it wraps, or else the
+// category of a confluent CLI command or API request which failed, from its message.
+func Of(err error) int {
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		return Interrupted
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	// A confluent CLI which isn't installed isn't a resource which wasn't found.
+	if errors.Is(err, exec.ErrNotFound) {
+		return Failure
+	}
+
+	// The CLI's own message is classified rather than the command, whose arguments could match.
+	message := err.Error()
+	var cliErr *cli.Error
+	if errors.As(err, &cliErr) && cliErr.Stderr != "" {
+		message = cliErr.Stderr
+	}
+	for _, c := range categories {
+		if c.message.MatchString(message) {
+			return c.code
+		}
+	}
+	return Failure
+}
+
+// classes are the names of the categories of the exit codes, in telemetry.
+var classes = map[int]string{
+	Failure:        "failure",
+	Findings:       "findings",
+	Auth:           "auth",
+	NotFound:       "not_found",
+	Quota:          "quota",
+	RateLimited:    "rate_limited",
+	Validation:     "validation",
+	PartialFailure: "partial_failure",
+	Interrupted:    "interrupted",
+}
+
+// Class returns the name of the category of the exit code, such as "not_found", or "" for 0.
+func Class(code int) string {
+	if code == 0 {
+		return ""
+	}
+	if class, ok := classes[code]; ok {
+		return class
+	}
+	return "failure"
+}
+
+// Execute executes the command, reports the run with telemetry if the user opted in, and exits with the code of its
+// error if it fails. It's called last thing in main.
+func Execute(cmd *cobra.Command) {
+	start := time.Now()
+	executed, err := cmd.ExecuteC()
+	code := Of(err)
+	// Every command sets SilenceUsage once it's running, so a failure before then is of its flags or arguments.
+	if err != nil && !executed.SilenceUsage && code == Failure {
+		code = Validation
+	}
+
+	// Shell completion runs a hidden command on every Tab, which isn't a use of the plugin.
+	if !strings.HasPrefix(executed.Name(), "__") {
+		telemetry.Send(command(executed), time.Since(start), code, Class(code))
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
+
+// command returns the command as it's run through the confluent CLI, such as "confluent tag manager apply", from the
+// name of the plugin and the path of the subcommand.
+func command(executed *cobra.Command) string {
+	m := plugin.Current()
+	if m.Name == "" {
+		return executed.CommandPath()
+	}
+	path := strings.Fields(executed.CommandPath())
+	return strings.Join(append([]string{m.Entry().Command}, path[1:]...), " ")
+}
@@ -0,0 +1,154 @@
+// Package github downloads the files of the GitHub releases of the repository, for the plugins which install and
+// update the others: the binaries of the Go plugins, the scripts of the Python plugins, the index, and the checksums.
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+	"github.com/confluentinc/cli-plugins/internal/retry"
+)
+
+// DefaultAPIURL is the URL of the GitHub API, which GitHub Enterprise Server has its own of.
+const DefaultAPIURL = "https://api.github.com"
+
+// A Release is a GitHub release of the repository, with the download URL of each of its files.
+type Release struct {
+	Tag    string `json:"tag_name"`
+	Assets []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Has returns whether the release has the file.
+func (r Release) Has(name string) bool {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// A Client reads the releases of a repository. Requests are authenticated with $GITHUB_TOKEN if it's set, which has a
+// higher rate limit.
+type Client struct {
+	apiURL     string
+	repository string
+	client     *http.Client
+}
+
+// New returns a client of the releases of the repository, such as confluentinc/cli-plugins, through the GitHub API at
+// the URL.
+func New(apiURL, repository string) *Client {
+	// Binaries are downloaded with the same client, so requests have a generous timeout.
+	return &Client{apiURL: strings.TrimSuffix(apiURL, "/"), repository: repository, client: retry.Client(5 * time.Minute)}
+}
+
+// Release returns the release with the tag, or the latest release if the tag is empty.
+func (c *Client) Release(tag string) (Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", c.apiURL, c.repository)
+	if tag != "" {
+		url = fmt.Sprintf("%s/repos/%s/releases/tags/%s", c.apiURL, c.repository, tag)
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	body, err := c.get(req)
+	if err != nil {
+		if tag == "" {
+			return Release{}, fmt.Errorf("failed to read the latest release of %s: %w", c.repository, err)
+		}
+		return Release{}, fmt.Errorf(`failed to read release "%s" of %s: %w`, tag, c.repository, err)
+	}
+	var r Release
+	if err := json.Unmarshal(body, &r); err != nil {
+		return Release{}, fmt.Errorf("failed to parse the release: %w", err)
+	}
+	return r, nil
+}
+
+// Download returns the contents of a file of the release.
+func (c *Client) Download(r Release, name string) ([]byte, error) {
+	for _, asset := range r.Assets {
+		if asset.Name != name {
+			continue
+		}
+		req, err := http.NewRequest(http.MethodGet, asset.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/octet-stream")
+		b, err := c.get(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", name, err)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("release %s has no file %s", r.Tag, name)
+}
+
+// Index returns the plugins of the release, from its index.
+func (c *Client) Index(r Release) ([]plugin.Entry, error) {
+	b, err := c.Download(r, plugin.IndexFile)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ParseIndex(b)
+}
+
+// Checksums returns the checksum of each file of the release.
+func (c *Client) Checksums(r Release) (map[string]string, error) {
+	b, err := c.Download(r, plugin.ChecksumsFile)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ParseChecksums(b)
+}
+
+// DownloadVerified returns the contents of a file of the release, after checking them against the file's checksum.
+func (c *Client) DownloadVerified(r Release, checksums map[string]string, name string) ([]byte, error) {
+	want, ok := checksums[name]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no checksum of %s", r.Tag, name)
+	}
+	b, err := c.Download(r, name)
+	if err != nil {
+		return nil, err
+	}
+	if got := fmt.Sprintf("%x", sha256.Sum256(b)); got != want {
+		return nil, fmt.Errorf("the checksum of %s is %s rather than %s, so it wasn't installed", name, got, want)
+	}
+	return b, nil
+}
+
+func (c *Client) get(req *http.Request) ([]byte, error) {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", res.Status)
+	}
+	return b, nil
+}
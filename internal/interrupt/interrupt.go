@@ -0,0 +1,152 @@
+// Package interrupt lets the plugins stop cleanly on Ctrl-C: rather than being killed halfway through creating
+// resources, a plugin stops at the next confluent CLI command or wait, and reports the resources which it already
+// created, so that none are left behind unnoticed.
+package interrupt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ExitCode is the exit code of a plugin which was interrupted, as for a shell command killed by SIGINT.
+const ExitCode = 130
+
+// ErrInterrupted is returned by the confluent CLI commands and waits which are stopped by Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+var (
+	interrupted = make(chan struct{})
+	// passing is whether a command which the user interacts with is running, which handles Ctrl-C itself.
+	passing atomic.Bool
+)
+
+// Trap handles SIGINT and SIGTERM from now on. The first one interrupts the plugin, which stops at its next confluent
+// CLI command or wait, and a second one exits at once.
+func Trap() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for s := range signals {
+			switch {
+			case s == os.Interrupt && passing.Load():
+				// The interactive command gets Ctrl-C too, and handles it.
+			case Err() == nil:
+				close(interrupted)
+				fmt.Fprintln(os.Stderr, "\nInterrupted, stopping. Press Ctrl-C again to exit now.")
+			default:
+				os.Exit(ExitCode)
+			}
+		}
+	}()
+}
+
+// Run runs a command which the user interacts with, such as a shell, which handles Ctrl-C itself, so that Ctrl-C
+// doesn't interrupt the plugin while it runs.
+func Run(command *exec.Cmd) error {
+	passing.Store(true)
+	defer passing.Store(false)
+	return command.Run()
+}
+
+// Err returns ErrInterrupted once the plugin was interrupted, and nil before.
+func Err() error {
+	select {
+	case <-interrupted:
+		return ErrInterrupted
+	default:
+		return nil
+	}
+}
+
+// Sleep waits for d, and returns ErrInterrupted if the plugin is interrupted first.
+func Sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-interrupted:
+		return ErrInterrupted
+	}
+}
+
+// A Resource is something which a plugin created, with the confluent CLI arguments which delete it.
+type Resource struct {
+	Kind   string
+	Name   string
+	ID     string
+	Delete []string
+}
+
+func (r Resource) String() string {
+	switch {
+	case r.Name == "":
+		return fmt.Sprintf("%s %s", r.Kind, r.ID)
+	case r.ID == "":
+		return fmt.Sprintf(`%s "%s"`, r.Kind, r.Name)
+	default:
+		return fmt.Sprintf(`%s "%s" (%s)`, r.Kind, r.Name, r.ID)
+	}
+}
+
+// A Ledger is the resources which a plugin created, in order. It's safe to use from several goroutines.
+type Ledger struct {
+	mu        sync.Mutex
+	resources []Resource
+}
+
+// Add records a resource which was created, and the confluent CLI arguments which delete it, if any.
+func (l *Ledger) Add(kind, name, id string, deleteArgs ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.resources = append(l.resources, Resource{Kind: kind, Name: name, ID: id, Delete: deleteArgs})
+}
+
+// Resources returns the resources which were created, in order.
+func (l *Ledger) Resources() []Resource {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Resource(nil), l.resources...)
+}
+
+// Report prints the resources which were created, if the plugin was interrupted or err is non-nil, with the commands
+// which delete them, last first, since later resources may depend on earlier ones. It returns err, or ErrInterrupted.
+func (l *Ledger) Report(w io.Writer, err error) error {
+	if err == nil {
+		err = Err()
+	}
+	resources := l.Resources()
+	if err == nil || len(resources) == 0 {
+		return err
+	}
+
+	verb := "failed"
+	if errors.Is(err, ErrInterrupted) || Err() != nil {
+		verb = "was interrupted"
+	}
+	fmt.Fprintf(w, "\nThe plugin %s after creating these resources:\n", verb)
+	for _, r := range resources {
+		fmt.Fprintf(w, "  %s\n", r)
+	}
+
+	var commands []string
+	for i := len(resources) - 1; i >= 0; i-- {
+		if args := resources[i].Delete; len(args) > 0 {
+			commands = append(commands, "  confluent "+strings.Join(args, " "))
+		}
+	}
+	if len(commands) > 0 {
+		fmt.Fprintln(w, "Delete them with:")
+		fmt.Fprintln(w, strings.Join(commands, "\n"))
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+)
+
+// FindCoordinator learns which broker coordinates the group, which the other requests of the group are sent to.
+func (c *Client) FindCoordinator(group string) error {
+	conn, err := c.conn(-1)
+	if err != nil {
+		return err
+	}
+
+	var e encoder
+	e.string(group)
+	d, err := conn.request(apiFindCoordinator, 0, e.Bytes())
+	if err != nil {
+		return err
+	}
+
+	code, id, host, port := d.int16(), d.int32(), d.string(), d.int32()
+	if err := kafkaError(code); err != nil {
+		return fmt.Errorf(`failed to find the coordinator of consumer group "%s": %w`, group, err)
+	}
+	c.brokers[id] = net.JoinHostPort(host, fmt.Sprint(port))
+	c.coordinator = id
+	return d.err
+}
+
+// DescribeGroup returns the state of the group, such as Empty or Stable, and how many members it has.
+func (c *Client) DescribeGroup(group string) (string, int, error) {
+	conn, err := c.conn(c.coordinator)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var e encoder
+	e.int32(1)
+	e.string(group)
+	d, err := conn.request(apiDescribeGroups, 0, e.Bytes())
+	if err != nil {
+		return "", 0, err
+	}
+
+	d.int32()
+	code := d.int16()
+	d.string()
+	state := d.string()
+	d.string()
+	d.string()
+	members := d.int32()
+	if err := kafkaError(code); err != nil {
+		return "", 0, fmt.Errorf(`failed to describe consumer group "%s": %w`, group, err)
+	}
+	return state, int(members), d.err
+}
+
+// CommittedOffsets returns the group's committed offsets of the topic's partitions, which are -1 if the group hasn't
+// committed one.
+func (c *Client) CommittedOffsets(group, topic string, partitions []Partition) (map[int32]int64, error) {
+	conn, err := c.conn(c.coordinator)
+	if err != nil {
+		return nil, err
+	}
+
+	var e encoder
+	e.string(group)
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(partitions)))
+	for _, p := range partitions {
+		e.int32(p.ID)
+	}
+	d, err := conn.request(apiOffsetFetch, 1, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := map[int32]int64{}
+	for i := d.int32(); i > 0; i-- {
+		d.string()
+		for j := d.int32(); j > 0; j-- {
+			partition, offset := d.int32(), d.int64()
+			d.nullableString()
+			if err := kafkaError(d.int16()); err != nil {
+				return nil, fmt.Errorf(`failed to read the offset of partition %d of topic "%s": %w`, partition, topic, err)
+			}
+			offsets[partition] = offset
+		}
+	}
+	return offsets, d.err
+}
+
+// CommitOffsets commits the group's offsets of the topic's partitions. The group must be empty, since the offsets
+// aren't committed as one of its members.
+func (c *Client) CommitOffsets(group, topic string, offsets map[int32]int64) error {
+	conn, err := c.conn(c.coordinator)
+	if err != nil {
+		return err
+	}
+
+	var e encoder
+	e.string(group)
+	e.int32(-1) // No generation, since the commit isn't made by a member.
+	e.string("")
+	e.int64(-1) // Keep the offsets for the broker's default retention.
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(offsets)))
+	for partition, offset := range offsets {
+		e.int32(partition)
+		e.int64(offset)
+		e.int16(-1) // No metadata.
+	}
+	d, err := conn.request(apiOffsetCommit, 2, e.Bytes())
+	if err != nil {
+		return err
+	}
+
+	for i := d.int32(); i > 0; i-- {
+		d.string()
+		for j := d.int32(); j > 0; j-- {
+			partition := d.int32()
+			if err := kafkaError(d.int16()); err != nil {
+				return fmt.Errorf(`failed to commit the offset of partition %d of topic "%s": %w`, partition, topic, err)
+			}
+		}
+	}
+	return d.err
+}
+
+// GroupOffsets returns the group's committed offsets of every partition of every topic which it has committed one
+// for, by topic.
+func (c *Client) GroupOffsets(group string) (map[string]map[int32]int64, error) {
+	conn, err := c.conn(c.coordinator)
+	if err != nil {
+		return nil, err
+	}
+
+	var e encoder
+	e.string(group)
+	e.int32(-1) // Every topic.
+	d, err := conn.request(apiOffsetFetch, 2, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := map[string]map[int32]int64{}
+	for i := d.int32(); i > 0; i-- {
+		topic := d.string()
+		for j := d.int32(); j > 0; j-- {
+			partition, offset := d.int32(), d.int64()
+			d.nullableString()
+			if err := kafkaError(d.int16()); err != nil {
+				return nil, fmt.Errorf(`failed to read the offset of partition %d of topic "%s": %w`, partition, topic, err)
+			}
+			if offset < 0 {
+				continue
+			}
+			if offsets[topic] == nil {
+				offsets[topic] = map[int32]int64{}
+			}
+			offsets[topic][partition] = offset
+		}
+	}
+	if err := kafkaError(d.int16()); err != nil {
+		return nil, fmt.Errorf(`failed to read the offsets of consumer group "%s": %w`, group, err)
+	}
+	return offsets, d.err
+}
+
+// DeleteGroup deletes the group and its committed offsets, which fails unless the group is empty.
+func (c *Client) DeleteGroup(group string) error {
+	conn, err := c.conn(c.coordinator)
+	if err != nil {
+		return err
+	}
+
+	var e encoder
+	e.int32(1)
+	e.string(group)
+	d, err := conn.request(apiDeleteGroups, 0, e.Bytes())
+	if err != nil {
+		return err
+	}
+
+	d.int32()
+	for i := d.int32(); i > 0; i-- {
+		d.string()
+		if err := kafkaError(d.int16()); err != nil {
+			return fmt.Errorf(`failed to delete consumer group "%s": %w`, group, err)
+		}
+	}
+	return d.err
+}
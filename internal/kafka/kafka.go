@@ -0,0 +1,520 @@
+// Package kafka is a minimal client of the Kafka protocol, with just enough for the plugins which read and write topics
+// and consumer groups themselves: list the partitions and offsets of topics, fetch and produce uncompressed or gzipped
+// record batches, delete records, and describe, commit the offsets of, and delete consumer groups, over TLS with
+// SASL/PLAIN, as Confluent Cloud requires. It has no dependencies, so the plugins don't need a Kafka library.
+package kafka
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The Kafka APIs which the client uses, at the oldest versions which Kafka 4 still supports.
+const (
+	apiProduce          = 0
+	apiFetch            = 1
+	apiListOffsets      = 2
+	apiMetadata         = 3
+	apiOffsetCommit     = 8
+	apiOffsetFetch      = 9
+	apiFindCoordinator  = 10
+	apiDescribeGroups   = 15
+	apiSaslHandshake    = 17
+	apiDeleteRecords    = 21
+	apiDescribeLogDirs  = 35
+	apiSaslAuthenticate = 36
+	apiDeleteGroups     = 42
+)
+
+// The offsets which Offset lists instead of the offset of a timestamp.
+const (
+	EarliestOffset = -2
+	LatestOffset   = -1
+)
+
+// The acks which Produce waits for.
+const (
+	AllReplicas = -1
+	Leader      = 1
+)
+
+const timeout = 30 * time.Second
+
+// errUnknownTopic is the error code of a topic which doesn't exist.
+const errUnknownTopic = 3
+
+// ErrUnknownTopic is returned by Metadata for a topic which doesn't exist, such as one which was deleted after a
+// consumer group committed offsets for it.
+var ErrUnknownTopic = errors.New("kafka error UNKNOWN_TOPIC_OR_PARTITION")
+
+// errorNames names the error codes which are likely for the requests of the client. Others are reported by number.
+var errorNames = map[int16]string{
+	1:  "OFFSET_OUT_OF_RANGE",
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	7:  "REQUEST_TIMED_OUT",
+	10: "MESSAGE_TOO_LARGE",
+	14: "COORDINATOR_LOAD_IN_PROGRESS",
+	15: "COORDINATOR_NOT_AVAILABLE",
+	16: "NOT_COORDINATOR",
+	22: "ILLEGAL_GENERATION",
+	25: "UNKNOWN_MEMBER_ID",
+	27: "REBALANCE_IN_PROGRESS",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	30: "GROUP_AUTHORIZATION_FAILED",
+	31: "CLUSTER_AUTHORIZATION_FAILED",
+	44: "POLICY_VIOLATION",
+	58: "SASL_AUTHENTICATION_FAILED",
+	68: "NON_EMPTY_GROUP",
+	69: "GROUP_ID_NOT_FOUND",
+}
+
+func kafkaError(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	if code == errUnknownTopic {
+		return ErrUnknownTopic
+	}
+	if name, ok := errorNames[code]; ok {
+		return fmt.Errorf("kafka error %s", name)
+	}
+	return fmt.Errorf("kafka error code %d", code)
+}
+
+// Conn is a connection to a broker. Requests are identified by the client ID, which is the name of the plugin, such as
+// "confluent-topic-clone", and time out after the timeout.
+type Conn struct {
+	conn          net.Conn
+	clientID      string
+	timeout       time.Duration
+	correlationID int32
+}
+
+// NewConn returns a connection over a connection to a broker, which must already be secured with TLS.
+func NewConn(conn net.Conn, clientID string, timeout time.Duration) *Conn {
+	return &Conn{conn: conn, clientID: clientID, timeout: timeout}
+}
+
+// Broker is a broker as the cluster advertises it to clients.
+type Broker struct {
+	ID   int32
+	Addr string
+}
+
+// request sends a request and returns a decoder of the response body.
+func (kc *Conn) request(apiKey, version int16, body []byte) (*decoder, error) {
+	kc.correlationID++
+
+	var e encoder
+	e.int16(apiKey)
+	e.int16(version)
+	e.int32(kc.correlationID)
+	e.string(kc.clientID)
+	e.Write(body)
+
+	_ = kc.conn.SetDeadline(time.Now().Add(kc.timeout))
+
+	req := make([]byte, 4, 4+e.Len())
+	binary.BigEndian.PutUint32(req, uint32(e.Len()))
+	if _, err := kc.conn.Write(append(req, e.Bytes()...)); err != nil {
+		return nil, err
+	}
+
+	var size int32
+	if err := binary.Read(kc.conn, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	res := make([]byte, size)
+	if _, err := io.ReadFull(kc.conn, res); err != nil {
+		return nil, err
+	}
+
+	d := &decoder{b: res}
+	if id := d.int32(); id != kc.correlationID {
+		return nil, fmt.Errorf("unexpected correlation ID %d, expected %d", id, kc.correlationID)
+	}
+	return d, nil
+}
+
+// Authenticate authenticates the connection with an API key and secret.
+func (kc *Conn) Authenticate(username, password string) error {
+	var e encoder
+	e.string("PLAIN")
+	d, err := kc.request(apiSaslHandshake, 1, e.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := kafkaError(d.int16()); err != nil {
+		return fmt.Errorf("SASL handshake failed: %w", err)
+	}
+
+	e = encoder{}
+	e.bytes([]byte("\x00" + username + "\x00" + password))
+	d, err = kc.request(apiSaslAuthenticate, 0, e.Bytes())
+	if err != nil {
+		return err
+	}
+	if code, msg := d.int16(), d.nullableString(); code != 0 {
+		return fmt.Errorf("failed to authenticate with the API key: %s", msg)
+	}
+	return d.err
+}
+
+// Brokers lists the brokers of the cluster, sorted by ID.
+func (kc *Conn) Brokers() ([]Broker, error) {
+	var e encoder
+	e.int32(0) // No topics, only the brokers.
+	d, err := kc.request(apiMetadata, 1, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var brokers []Broker
+	for i := d.int32(); i > 0; i-- {
+		id, host, port := d.int32(), d.string(), d.int32()
+		d.nullableString()
+		brokers = append(brokers, Broker{ID: id, Addr: net.JoinHostPort(host, fmt.Sprint(port))})
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i].ID < brokers[j].ID })
+	return brokers, d.err
+}
+
+// Client connects to the brokers of a cluster as it needs them: the bootstrap server, the leaders of partitions, and
+// the coordinators of consumer groups. A client isn't safe for concurrent use.
+type Client struct {
+	clientID  string
+	bootstrap string
+	username  string
+	password  string
+
+	brokers     map[int32]string
+	conns       map[int32]*Conn
+	coordinator int32
+}
+
+// Partition is a partition of a topic, and the broker which leads it.
+type Partition struct {
+	ID     int32
+	Leader int32
+}
+
+// NewClient returns a client of the cluster with the bootstrap server, which authenticates with an API key and secret.
+// The client ID is the name of the plugin, such as "confluent-topic-clone".
+func NewClient(clientID, bootstrap, username, password string) *Client {
+	return &Client{
+		clientID:    clientID,
+		bootstrap:   strings.TrimPrefix(bootstrap, "SASL_SSL://"),
+		username:    username,
+		password:    password,
+		brokers:     map[int32]string{},
+		conns:       map[int32]*Conn{},
+		coordinator: -1,
+	}
+}
+
+// Close closes the connections to the brokers.
+func (c *Client) Close() {
+	for _, conn := range c.conns {
+		_ = conn.conn.Close()
+	}
+}
+
+func (c *Client) dial(addr string) (*Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: timeout}, Config: &tls.Config{ServerName: host}}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	kc := NewConn(conn, c.clientID, timeout)
+	if err := kc.Authenticate(c.username, c.password); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return kc, nil
+}
+
+// conn returns a connection to the broker, or to the bootstrap server for -1.
+func (c *Client) conn(broker int32) (*Conn, error) {
+	if conn, ok := c.conns[broker]; ok {
+		return conn, nil
+	}
+
+	addr := c.bootstrap
+	if broker >= 0 {
+		var ok bool
+		if addr, ok = c.brokers[broker]; !ok {
+			return nil, fmt.Errorf("unknown broker %d", broker)
+		}
+	}
+
+	conn, err := c.dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	c.conns[broker] = conn
+	return conn, nil
+}
+
+// Metadata returns the partitions of the topic and their leaders, and learns the addresses of the brokers.
+func (c *Client) Metadata(topic string) ([]Partition, error) {
+	conn, err := c.conn(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	d, err := conn.request(apiMetadata, 1, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := d.int32(); i > 0; i-- {
+		id, host, port := d.int32(), d.string(), d.int32()
+		d.nullableString()
+		c.brokers[id] = net.JoinHostPort(host, fmt.Sprint(port))
+	}
+	d.int32()
+
+	var partitions []Partition
+	for i := d.int32(); i > 0; i-- {
+		code, _ := d.int16(), d.string()
+		d.int8()
+		if err := kafkaError(code); err != nil {
+			return nil, fmt.Errorf(`failed to read the metadata of topic "%s": %w`, topic, err)
+		}
+
+		for j := d.int32(); j > 0; j-- {
+			code, partition, leader := d.int16(), d.int32(), d.int32()
+			d.int32Array()
+			d.int32Array()
+			if err := kafkaError(code); err != nil {
+				return nil, fmt.Errorf(`failed to read the metadata of partition %d of topic "%s": %w`, partition, topic, err)
+			}
+			partitions = append(partitions, Partition{ID: partition, Leader: leader})
+		}
+	}
+	return partitions, d.err
+}
+
+// Offset returns the earliest or latest offset of a partition, or the earliest offset whose timestamp is at least the
+// timestamp, which is -1 if there is none.
+func (c *Client) Offset(topic string, p Partition, timestamp int64) (int64, error) {
+	conn, err := c.conn(p.Leader)
+	if err != nil {
+		return 0, err
+	}
+
+	var e encoder
+	e.int32(-1)
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(p.ID)
+	e.int64(timestamp)
+	d, err := conn.request(apiListOffsets, 1, e.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	d.int32()
+	d.string()
+	d.int32()
+	d.int32()
+	code := d.int16()
+	d.int64()
+	offset := d.int64()
+	if err := kafkaError(code); err != nil {
+		return 0, err
+	}
+	return offset, d.err
+}
+
+// Fetch returns the records of a partition from the offset, and the offset to fetch next. Only whole batches are
+// decoded, and the broker always sends at least one, so the offset advances unless it's at the end of the partition,
+// or of the records which are committed.
+func (c *Client) Fetch(topic string, p Partition, offset int64) ([]Record, int64, error) {
+	conn, err := c.conn(p.Leader)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var e encoder
+	e.int32(-1)
+	e.int32(500)
+	e.int32(1)
+	e.int32(8 << 20)
+	e.int8(1) // Only committed records are read.
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(p.ID)
+	e.int64(offset)
+	e.int32(4 << 20)
+	d, err := conn.request(apiFetch, 4, e.Bytes())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d.int32()
+	d.int32()
+	d.string()
+	d.int32()
+	d.int32()
+	code := d.int16()
+	d.int64()
+	d.int64()
+	aborted := map[int64][]int64{}
+	for i := d.int32(); i > 0; i-- {
+		producerID, firstOffset := d.int64(), d.int64()
+		aborted[producerID] = append(aborted[producerID], firstOffset)
+	}
+	records := d.bytes()
+	if err := kafkaError(code); err != nil {
+		return nil, 0, err
+	}
+	if d.err != nil {
+		return nil, 0, d.err
+	}
+
+	return decodeBatches(records, offset, aborted)
+}
+
+// Produce appends a batch of records, which EncodeBatch encodes, to a partition, and waits for the acks: AllReplicas
+// or Leader.
+func (c *Client) Produce(topic string, p Partition, batch []byte, acks int16) error {
+	conn, err := c.conn(p.Leader)
+	if err != nil {
+		return err
+	}
+
+	var e encoder
+	e.int16(-1) // No transactional ID.
+	e.int16(acks)
+	e.int32(int32(timeout / time.Millisecond))
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(p.ID)
+	e.bytes(batch)
+	d, err := conn.request(apiProduce, 3, e.Bytes())
+	if err != nil {
+		return err
+	}
+
+	d.int32()
+	d.string()
+	d.int32()
+	d.int32()
+	if err := kafkaError(d.int16()); err != nil {
+		return err
+	}
+	return d.err
+}
+
+// LogSize returns the size in bytes of a partition's log on its leader, or -1 if the broker doesn't say, as brokers
+// which don't allow describing their log dirs don't.
+func (c *Client) LogSize(topic string, p Partition) (int64, error) {
+	conn, err := c.conn(p.Leader)
+	if err != nil {
+		return 0, err
+	}
+
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(p.ID)
+	d, err := conn.request(apiDescribeLogDirs, 1, e.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	d.int32()
+	size := int64(-1)
+	for i := d.int32(); i > 0; i-- {
+		code := d.int16()
+		d.string()
+		for j := d.int32(); j > 0; j-- {
+			d.string()
+			for k := d.int32(); k > 0; k-- {
+				partition, s := d.int32(), d.int64()
+				d.int64()
+				future := d.int8() != 0
+				// A future replica is a copy being moved between log dirs, which would count the log twice.
+				if code == 0 && partition == p.ID && !future {
+					size = s
+				}
+			}
+		}
+	}
+	return size, d.err
+}
+
+// DeleteRecords deletes the records of the partitions before the offsets. Each leader deletes the records of its
+// partitions, so they're deleted in one request per leader.
+func (c *Client) DeleteRecords(topic string, partitions []Partition, offsets map[int32]int64) error {
+	byLeader := map[int32][]int32{}
+	for _, p := range partitions {
+		if _, ok := offsets[p.ID]; ok {
+			byLeader[p.Leader] = append(byLeader[p.Leader], p.ID)
+		}
+	}
+
+	for leader, ps := range byLeader {
+		conn, err := c.conn(leader)
+		if err != nil {
+			return err
+		}
+
+		var e encoder
+		e.int32(1)
+		e.string(topic)
+		e.int32(int32(len(ps)))
+		for _, partition := range ps {
+			e.int32(partition)
+			e.int64(offsets[partition])
+		}
+		// The brokers wait for the followers to delete the records too, for up to half the client's timeout, so that they
+		// reply before the client gives up.
+		e.int32(int32((timeout / 2).Milliseconds()))
+		d, err := conn.request(apiDeleteRecords, 0, e.Bytes())
+		if err != nil {
+			return err
+		}
+
+		d.int32()
+		for i := d.int32(); i > 0; i-- {
+			d.string()
+			for j := d.int32(); j > 0; j-- {
+				partition := d.int32()
+				d.int64()
+				if err := kafkaError(d.int16()); err != nil {
+					return fmt.Errorf(`failed to delete the records of partition %d of topic "%s": %w`, partition, topic, err)
+				}
+			}
+		}
+		if d.err != nil {
+			return d.err
+		}
+	}
+	return nil
+}
+
+// errCompression is returned for record batches compressed with a codec other than gzip, which aren't supported
+// without third-party libraries.
+var errCompression = errors.New("records compressed with snappy, lz4, or zstd can't be read")
@@ -0,0 +1,215 @@
+package kafka
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// The compressions of record batches which the client can encode and decode.
+const (
+	CompressionNone = 0
+	CompressionGzip = 1
+)
+
+// CompressionNames names the compressions, as Kafka's clients configure them.
+var CompressionNames = map[int16]string{CompressionNone: "none", CompressionGzip: "gzip"}
+
+// Record is a record of a partition. The offset is only set when it's fetched, and the timestamp is when it was
+// produced, or appended to the log if the topic's message.timestamp.type is LogAppendTime.
+type Record struct {
+	Offset    int64
+	Timestamp int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+}
+
+// Header is a header of a record.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+const (
+	compressionMask    = 0x07
+	logAppendTime      = 0x08
+	transactionalBatch = 0x10
+	controlBatch       = 0x20
+)
+
+// decodeBatches decodes v2 record batches, skipping records before the offset, since a fetch returns whole batches,
+// control records, which mark the ends of transactions, and the records of aborted transactions, which start at the
+// offsets of their producers.
+func decodeBatches(b []byte, offset int64, aborted map[int64][]int64) ([]Record, int64, error) {
+	var records []Record
+	next := offset
+	aborting := map[int64]bool{}
+	for len(b) >= 12 {
+		baseOffset := int64(binary.BigEndian.Uint64(b))
+		length := int(binary.BigEndian.Uint32(b[8:]))
+		if len(b) < 12+length {
+			break
+		}
+		batch := &decoder{b: b[12 : 12+length]}
+		b = b[12+length:]
+
+		batch.int32()
+		if magic := batch.int8(); magic != 2 {
+			return nil, 0, fmt.Errorf("unsupported record batch version %d", magic)
+		}
+		batch.int32()
+		attributes := batch.int16()
+		lastOffsetDelta := batch.int32()
+		firstTimestamp, maxTimestamp := batch.int64(), batch.int64()
+		producerID := batch.int64()
+		batch.int16()
+		batch.int32()
+		count := batch.int32()
+		if batch.err != nil {
+			return nil, 0, batch.err
+		}
+		next = baseOffset + int64(lastOffsetDelta) + 1
+
+		if attributes&transactionalBatch != 0 {
+			// A control batch ends the producer's transaction, whether it was committed or aborted.
+			if attributes&controlBatch != 0 {
+				delete(aborting, producerID)
+				continue
+			}
+			// Each aborted transaction starts once, so it's forgotten when it does, and the producer's later
+			// transactions are read.
+			if !aborting[producerID] {
+				var later []int64
+				for _, first := range aborted[producerID] {
+					if first <= baseOffset {
+						aborting[producerID] = true
+					} else {
+						later = append(later, first)
+					}
+				}
+				aborted[producerID] = later
+			}
+			if aborting[producerID] {
+				continue
+			}
+		} else if attributes&controlBatch != 0 {
+			continue
+		}
+
+		body := &decoder{b: batch.b[batch.off:]}
+		switch attributes & compressionMask {
+		case 0:
+		case CompressionGzip:
+			r, err := gzip.NewReader(bytes.NewReader(body.b))
+			if err != nil {
+				return nil, 0, err
+			}
+			if body.b, err = io.ReadAll(r); err != nil {
+				return nil, 0, err
+			}
+		default:
+			return nil, 0, errCompression
+		}
+
+		for i := int32(0); i < count; i++ {
+			body.varint()
+			body.int8()
+			r := Record{Timestamp: firstTimestamp + body.varint(), Offset: baseOffset + body.varint()}
+			r.Key = body.varbytes()
+			r.Value = body.varbytes()
+			for j := body.varint(); j > 0; j-- {
+				r.Headers = append(r.Headers, Header{Key: string(body.varbytes()), Value: body.varbytes()})
+			}
+			if body.err != nil {
+				return nil, 0, body.err
+			}
+
+			if attributes&logAppendTime != 0 {
+				r.Timestamp = maxTimestamp
+			}
+			if r.Offset >= offset {
+				records = append(records, r)
+			}
+		}
+	}
+	return records, next, nil
+}
+
+// EncodeBatch encodes the records in a v2 record batch, compressed with gzip if the compression is CompressionGzip.
+func EncodeBatch(records []Record, compression int16) ([]byte, error) {
+	first, max := records[0].Timestamp, records[0].Timestamp
+	for _, r := range records {
+		if r.Timestamp < first {
+			first = r.Timestamp
+		}
+		if r.Timestamp > max {
+			max = r.Timestamp
+		}
+	}
+
+	var body encoder
+	for i, r := range records {
+		var e encoder
+		e.int8(0)
+		e.varint(r.Timestamp - first)
+		e.varint(int64(i))
+		e.varbytes(r.Key)
+		e.varbytes(r.Value)
+		e.varint(int64(len(r.Headers)))
+		for _, h := range r.Headers {
+			e.varbytes([]byte(h.Key))
+			e.varbytes(h.Value)
+		}
+		body.varint(int64(e.Len()))
+		body.Write(e.Bytes())
+	}
+
+	payload := body.Bytes()
+	if compression == CompressionGzip {
+		var compressed bytes.Buffer
+		w := gzip.NewWriter(&compressed)
+		if _, err := w.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		payload = compressed.Bytes()
+	}
+
+	// The CRC covers everything from the attributes to the end of the batch.
+	var crced encoder
+	crced.int16(compression)
+	crced.int32(int32(len(records) - 1))
+	crced.int64(first)
+	crced.int64(max)
+	crced.int64(-1)
+	crced.int16(-1)
+	crced.int32(-1)
+	crced.int32(int32(len(records)))
+	crced.Write(payload)
+
+	var batch encoder
+	batch.int64(0)
+	batch.int32(int32(4 + 1 + 4 + crced.Len()))
+	batch.int32(-1)
+	batch.int8(2)
+	batch.int32(int32(crc32.Checksum(crced.Bytes(), castagnoli)))
+	batch.Write(crced.Bytes())
+	return batch.Bytes(), nil
+}
+
+// Size estimates how many bytes the record takes in a batch.
+func (r Record) Size() int {
+	n := 20 + len(r.Key) + len(r.Value)
+	for _, h := range r.Headers {
+		n += 10 + len(h.Key) + len(h.Value)
+	}
+	return n
+}
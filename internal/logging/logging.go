@@ -0,0 +1,86 @@
+// Package logging sets up the log/slog logger of the plugins from the --verbose and --log-format flags which they
+// share, and masks secrets in every message, so that debug output is safe to paste into a support ticket.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+)
+
+// LevelTrace is enabled with -vv, and logs what the plugins send and receive, such as the output of confluent CLI
+// commands.
+const LevelTrace = slog.LevelDebug - 4
+
+// AddFlags adds --verbose (-v) and --log-format to the command and its subcommands, and sets up the default logger
+// from them before the command runs.
+func AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().CountP("verbose", "v", "Log what the plugin does (-v), and what it sends and receives (-vv).")
+	cmd.PersistentFlags().String("log-format", "text", "Format of log messages: text or json.")
+
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		return Setup(cmd)
+	}
+}
+
+// Setup sets the default logger to log to the command's stderr, at the level and in the format of its flags.
+func Setup(cmd *cobra.Command) error {
+	verbosity, err := cmd.Flags().GetCount("verbose")
+	cobra.CheckErr(err)
+
+	format, err := cmd.Flags().GetString("log-format")
+	cobra.CheckErr(err)
+
+	logger, err := New(cmd.ErrOrStderr(), verbosity, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// New returns a logger to w at the level selected by the number of -v flags, as text or as JSON, which masks secrets
+// in the values of its messages.
+func New(w io.Writer, verbosity int, format string) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	switch {
+	case verbosity == 1:
+		level = slog.LevelDebug
+	case verbosity >= 2:
+		level = LevelTrace
+	}
+
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && a.Value.Any() == LevelTrace {
+				a.Value = slog.StringValue("TRACE")
+			}
+
+			switch v := a.Value.Any().(type) {
+			case string:
+				a.Value = slog.StringValue(Redact(v))
+			case []string:
+				redacted := make([]string, len(v))
+				for i, s := range v {
+					redacted[i] = Redact(s)
+				}
+				a.Value = slog.AnyValue(redacted)
+			case error:
+				a.Value = slog.StringValue(Redact(v.Error()))
+			}
+			return a
+		},
+	}
+
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf(`unsupported log format "%s", supported formats: text, json`, format)
+	}
+}
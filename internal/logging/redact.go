@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// jwtPattern matches tokens such as the confluent CLI's auth token.
+	jwtPattern = regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]+`)
+
+	// flagPattern matches the values of flags such as --api-secret and --password, which are kept.
+	flagPattern = regexp.MustCompile(`(?i)(--[\w-]*(?:secret|password|passcode|token)[= ])("[^"]*"|'[^']*'|\S+)`)
+
+	// fieldPattern matches the values of fields such as "api_secret": "..." in JSON, password=... in configs, and
+	// password="..." in a JAAS config.
+	fieldPattern = regexp.MustCompile(`(?i)("?[\w.-]*(?:secret|password|passcode|token)"?\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,;&"'}]+)`)
+
+	// authorizationPattern matches the credentials of an Authorization header.
+	authorizationPattern = regexp.MustCompile(`(?i)\b(bearer|basic) [\w.~+/=-]+`)
+
+	// userinfoPattern matches the password of a URL, such as a proxy's.
+	userinfoPattern = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+
+	// apiSecretPattern matches Confluent Cloud API secrets, which are 64 characters long, even where they aren't
+	// labeled, such as in the output of "confluent api-key create".
+	apiSecretPattern = regexp.MustCompile(`\b[A-Za-z0-9+/]{64}\b`)
+
+	hexPattern = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+// redactions are the secrets which are masked in log messages, on top of those which the patterns match. Secrets are
+// added as they become known, such as the password which the plugin reads from stdin.
+var redactions = &redactor{}
+
+type redactor struct {
+	mu       sync.RWMutex
+	secrets  []string
+	patterns []*regexp.Regexp
+}
+
+// AddSecrets masks the secrets in every message which is logged from now on, and in Redact.
+func AddSecrets(secrets ...string) {
+	redactions.mu.Lock()
+	defer redactions.mu.Unlock()
+
+	for _, secret := range secrets {
+		if secret != "" {
+			redactions.secrets = append(redactions.secrets, secret)
+		}
+	}
+
+	// Longer secrets are masked first, in case one secret contains another.
+	sort.Slice(redactions.secrets, func(i, j int) bool { return len(redactions.secrets[i]) > len(redactions.secrets[j]) })
+}
+
+// AddPatterns masks what the patterns match in every message which is logged from now on, and in Redact.
+func AddPatterns(patterns ...*regexp.Regexp) {
+	redactions.mu.Lock()
+	defer redactions.mu.Unlock()
+
+	redactions.patterns = append(redactions.patterns, patterns...)
+}
+
+// Redact masks the secrets in s: those which were added, the values of secret flags and fields, URL passwords, tokens,
+// credentials of Authorization headers, and API secrets.
+func Redact(s string) string {
+	redactions.mu.RLock()
+	defer redactions.mu.RUnlock()
+
+	for _, secret := range redactions.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	for _, pattern := range redactions.patterns {
+		s = pattern.ReplaceAllString(s, redacted)
+	}
+
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = flagPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = fieldPattern.ReplaceAllStringFunc(s, func(field string) string {
+		m := fieldPattern.FindStringSubmatch(field)
+		if strings.HasPrefix(m[2], `"`) {
+			return m[1] + `"` + redacted + `"`
+		}
+		return m[1] + redacted
+	})
+	s = authorizationPattern.ReplaceAllString(s, "${1} "+redacted)
+	s = userinfoPattern.ReplaceAllString(s, "${1}"+redacted+"@")
+	// Digests, such as SHA-256 checksums, are also 64 characters long, but are hexadecimal.
+	s = apiSecretPattern.ReplaceAllStringFunc(s, func(secret string) string {
+		if hexPattern.MatchString(secret) {
+			return secret
+		}
+		return redacted
+	})
+	return s
+}
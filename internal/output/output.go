@@ -0,0 +1,126 @@
+// Package output prints the results of the plugins as a table, JSON, or YAML, in the format of the --output flag which
+// they share, so that scripts can read the results of any plugin the same way.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+)
+
+// AddFlag adds --output (-o) to the command, which takes table, json, yaml, and the command's other formats, such as
+// csv. The --format flag which the plugins took before is kept as a deprecated alias of it, unless the command has a
+// --format of its own, such as the format of the records it produces.
+func AddFlag(cmd *cobra.Command, description string, formats ...string) {
+	formats = append([]string{Table, JSON, YAML}, formats...)
+	cmd.Flags().StringP("output", "o", Table, fmt.Sprintf("%s: %s.", description, list(formats)))
+	cobra.CheckErr(cmd.Flags().SetAnnotation("output", "formats", formats))
+
+	if cmd.Flags().Lookup("format") == nil {
+		cmd.Flags().String("format", "", "Deprecated alias of --output.")
+		cobra.CheckErr(cmd.Flags().MarkDeprecated("format", "use --output instead"))
+	}
+}
+
+// SetDefault makes format the default of --output, for the commands whose result is a document which is usually
+// written to a file, such as an export, rather than a table.
+func SetDefault(cmd *cobra.Command, format string) {
+	flag := cmd.Flags().Lookup("output")
+	cobra.CheckErr(flag.Value.Set(format))
+	flag.DefValue = format
+}
+
+// Format returns the format which was passed, and fails if the command doesn't support it. "text", what the table
+// format was called before, is still accepted.
+func Format(cmd *cobra.Command) (string, error) {
+	flag := cmd.Flags().Lookup("output")
+	format := flag.Value.String()
+	if deprecated := cmd.Flags().Lookup("format"); deprecated != nil && deprecated.Deprecated != "" && deprecated.Changed {
+		format = deprecated.Value.String()
+	}
+	if format == "text" {
+		format = Table
+	}
+
+	if formats := flag.Annotations["formats"]; !slices.Contains(formats, format) {
+		return "", exitcode.Invalid(`unsupported format "%s", supported formats: %s`, format, strings.Join(formats, ", "))
+	}
+	return format, nil
+}
+
+// Print prints v as JSON or YAML, with the names of its JSON fields, or calls table to print it as a table. table may
+// be nil if the command prints its table itself.
+func Print(w io.Writer, format string, v any, table func()) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case YAML:
+		return printYAML(w, v)
+	default:
+		if table != nil {
+			table()
+		}
+		return nil
+	}
+}
+
+// Progress returns where the command prints its progress, such as the changes which it makes as it makes them: stdout
+// with --output table, and stderr with json or yaml, so that stdout is only the result.
+func Progress(cmd *cobra.Command) io.Writer {
+	if format, err := Format(cmd); err == nil && format != Table {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+// printYAML prints v as YAML by way of JSON, so that its fields are named and ordered as they are in JSON, and those
+// which are omitted from JSON are omitted from YAML too.
+func printYAML(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is YAML in flow style, which is set back to block style.
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
+
+// list lists the formats as "a, b, or c".
+func list(formats []string) string {
+	if len(formats) == 2 {
+		return formats[0] + " or " + formats[1]
+	}
+	return strings.Join(formats[:len(formats)-1], ", ") + ", or " + formats[len(formats)-1]
+}
@@ -0,0 +1,84 @@
+// Package paging follows the pages of the lists which the Confluent Cloud APIs return a page at a time, so that the
+// plugins which call them see every resource, and not only those of the first page. The lists of the confluent CLI are
+// already complete, since it follows their pages itself.
+package paging
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// All calls fetch with the token of each page, which is empty for the first page, and returns the items of every page.
+// fetch returns the items of its page and the token of the next page, which is empty on the last page.
+func All[T any](fetch func(token string) ([]T, string, error)) ([]T, error) {
+	var items []T
+	seen := map[string]bool{}
+	token := ""
+	for {
+		page, next, err := fetch(token)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" {
+			return items, nil
+		}
+		// An API which returns a page that it already returned would be followed forever.
+		if seen[next] {
+			return nil, fmt.Errorf(`the API returned the page "%s" more than once`, next)
+		}
+		seen[next] = true
+		token = next
+	}
+}
+
+// A CloudPage is a page of a list of the Confluent Cloud API, such as of /iam/v2/role-bindings, whose next page is at
+// the URL of metadata.next.
+type CloudPage[T any] struct {
+	Data     []T `json:"data"`
+	Metadata struct {
+		Next string `json:"next"`
+	} `json:"metadata"`
+}
+
+// Next returns the token of the next page, from the URL of metadata.next, or "" on the last page.
+func (p CloudPage[T]) Next() string {
+	if p.Metadata.Next == "" {
+		return ""
+	}
+	u, err := url.Parse(p.Metadata.Next)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("page_token")
+}
+
+// A MetricsPage is a page of a query of the Metrics API, whose next page is queried with the page_token parameter.
+type MetricsPage[T any] struct {
+	Data []T `json:"data"`
+	Meta struct {
+		Pagination struct {
+			NextPageToken string `json:"next_page_token"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// Next returns the token of the next page, or "" on the last page.
+func (p MetricsPage[T]) Next() string {
+	return p.Meta.Pagination.NextPageToken
+}
+
+// WithToken returns the URL with the page_token parameter of the page's token, or the URL itself for the first page.
+func WithToken(rawURL, token string) string {
+	if token == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	query.Set("page_token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,72 @@
+// Package parallel works through the items of bulk operations, such as a confluent CLI command for each of thousands
+// of API keys, a number at a time rather than one after another, and collects what happened to each item.
+package parallel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+)
+
+// Each calls f with each item and its index, at most parallelism at once, and returns the error of each item, in the
+// order of the items. Every item is attempted even if others fail, so that one which fails doesn't leave the rest
+// behind, except that once the plugin is interrupted, the items which haven't started fail with
+// interrupt.ErrInterrupted.
+func Each[T any](items []T, parallelism int, f func(i int, item T) error) []error {
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, max(parallelism, 1))
+		errs = make([]error, len(items))
+	)
+	for i, item := range items {
+		sem <- struct{}{}
+		if interrupt.Err() != nil {
+			<-sem
+			errs[i] = interrupt.ErrInterrupted
+			continue
+		}
+		wg.Add(1)
+		go func(i int, item T) {
+			defer func() { <-sem; wg.Done() }()
+			errs[i] = f(i, item)
+		}(i, item)
+	}
+	wg.Wait()
+	return errs
+}
+
+// Summary returns nil if every item succeeded, or else how many failed, such as "failed to delete 3 of 120 API keys",
+// followed by their errors. Items which were stopped since the plugin was interrupted are counted, but not listed, and
+// the error is interrupt.ErrInterrupted too. Otherwise, if some of the items succeeded, the error's exit code is
+// exitcode.PartialFailure.
+func Summary(errs []error, verb, noun string) error {
+	var failed []error
+	stopped := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, interrupt.ErrInterrupted):
+			stopped++
+		default:
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 0 && stopped == 0 {
+		return nil
+	}
+
+	partial := len(failed)+stopped < len(errs)
+	err := fmt.Errorf("failed to %s %d of %d %s", verb, len(failed)+stopped, len(errs), noun)
+	if stopped > 0 {
+		err = fmt.Errorf("%w, %d of which were stopped since the plugin was interrupted", err, stopped)
+		failed = append(failed, interrupt.ErrInterrupted)
+	}
+	err = fmt.Errorf("%w:\n%w", err, errors.Join(failed...))
+	if partial {
+		return exitcode.With(exitcode.PartialFailure, err)
+	}
+	return err
+}
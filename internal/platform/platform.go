@@ -0,0 +1,59 @@
+// Package platform hides the differences between Linux, macOS, and Windows from the plugins, so that none of them
+// assumes POSIX paths, "\n" line endings, or a terminal which draws ANSI escape sequences. The Go plugins are released
+// for windows/amd64 and windows/arm64 as well, so code which depends on the platform goes here rather than in a plugin.
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Executable returns the file name of the executable, such as confluent-topic-lint.exe on Windows.
+func Executable(name string) string {
+	if runtime.GOOS == "windows" && !strings.HasSuffix(name, ".exe") {
+		return name + ".exe"
+	}
+	return name
+}
+
+// ExpandHome replaces the "~" at the start of the path with the home directory, such as in ~/policies/topics.yaml, or
+// in ~\policies\topics.yaml on Windows, whose shells don't expand it themselves.
+func ExpandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || rest != "" && rest[0] != '/' && !(runtime.GOOS == "windows" && rest[0] == '\\') {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// Text returns the text of a file with "\n" line endings, since files which are checked out on Windows can have
+// "\r\n", such as SQL statements or schemas, which would otherwise differ from the same files checked out elsewhere.
+func Text(b []byte) string {
+	return strings.ReplaceAll(string(b), "\r\n", "\n")
+}
+
+// WriteTemp writes the contents to a new temporary file, such as the config of a connector to pass to the confluent
+// CLI, and returns its path, for the caller to remove. The file is closed before it's returned, since Windows neither
+// lets another process read a file which is open nor lets it be removed. It's only readable by the current user, as
+// the temporary directory of Windows is too, so it can hold secrets.
+func WriteTemp(pattern string, contents []byte) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(contents)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,19 @@
+package platform
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Terminal returns whether w is a terminal which draws ANSI escape sequences, such as those which redraw a progress
+// bar in place. It isn't with TERM=dumb, and on Windows, only once the console has processing of the sequences turned
+// on, which this turns on, and which consoles before Windows 10 don't have.
+func Terminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return enableEscapes(f)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package platform
+
+import "os"
+
+func enableEscapes(*os.File) bool {
+	return true
+}
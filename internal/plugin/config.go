@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigEnv is the environment variable of the path of the config file, which overrides ~/.confluent/plugins.yaml.
+const ConfigEnv = "CONFLUENT_PLUGINS_CONFIG"
+
+// A Config is ~/.confluent/plugins.yaml, the defaults of the flags of the plugins, so that flags which are the same
+// every time, such as the environment, cloud, and region, don't have to be passed every time, e.g.:
+//
+//	defaults:
+//	  environment: env-123456
+//	  cloud: aws
+//	  region: us-east-1
+//	  parallelism: 4
+//	plugins:
+//	  confluent-topic-lint:
+//	    policy: ~/policies/topics.yaml
+//	  confluent-ksql-quickstart:
+//	    csu: 4
+//	telemetry:
+//	  endpoint: https://telemetry.example.com/plugins
+//
+// Defaults apply to every plugin with a flag of the same name, and the settings of a plugin, under its name, apply to
+// its flags on top of them. Flags which are passed override both.
+type Config struct {
+	path      string
+	Defaults  map[string]yaml.Node            `yaml:"defaults"`
+	Plugins   map[string]map[string]yaml.Node `yaml:"plugins"`
+	Telemetry Telemetry                       `yaml:"telemetry"`
+}
+
+// Telemetry is where the plugins report how they were used, which is nowhere unless a user opts in.
+type Telemetry struct {
+	// Endpoint is the URL which each run of a plugin is posted to.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// ConfigPath returns the path of the config file, from $CONFLUENT_PLUGINS_CONFIG or in the confluent CLI's directory.
+func ConfigPath() (string, error) {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".confluent", "plugins.yaml"), nil
+}
+
+// ReadConfig reads the config file, which is empty if there's none.
+func ReadConfig() (Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return Config{}, err
+	}
+	c := Config{path: path}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read the plugins config: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return Config{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ApplyConfig reads the config file and sets the defaults of the flags of the command and its subcommands from it.
+// It's called in main once the command is built, before it's executed.
+func ApplyConfig(cmd *cobra.Command, plugin string) error {
+	c, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+	return c.Apply(cmd, plugin)
+}
+
+// Apply sets the defaults of the flags of the command and its subcommands from the defaults of the file, and then from
+// the settings of the plugin. A setting of the plugin for a flag which none of its commands has is an error, since
+// it's probably a typo, but defaults which a plugin doesn't have a flag for are ignored.
+func (c Config) Apply(cmd *cobra.Command, plugin string) error {
+	flags := map[string][]*pflag.Flag{}
+	var visit func(*cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		for _, set := range []*pflag.FlagSet{cmd.PersistentFlags(), cmd.Flags()} {
+			set.VisitAll(func(flag *pflag.Flag) {
+				flags[flag.Name] = append(flags[flag.Name], flag)
+			})
+		}
+		for _, sub := range cmd.Commands() {
+			visit(sub)
+		}
+	}
+	visit(cmd)
+
+	for name, value := range c.Defaults {
+		for _, flag := range flags[name] {
+			if err := set(flag, value); err != nil {
+				return fmt.Errorf("invalid default %s in %s: %w", name, c.path, err)
+			}
+		}
+	}
+	for name, value := range c.Plugins[plugin] {
+		if len(flags[name]) == 0 {
+			return fmt.Errorf(`%s has no flag --%s, which is set for it in %s`, plugin, name, c.path)
+		}
+		for _, flag := range flags[name] {
+			if err := set(flag, value); err != nil {
+				return fmt.Errorf("invalid setting %s of %s in %s: %w", name, plugin, c.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// set sets the default of the flag, so that it's still overridden when the flag is passed, and shown in the help.
+// Lists replace the default of slice flags, and maps are merged with the values of "<key>=<value>" flags which are
+// passed.
+func set(flag *pflag.Flag, value yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		s, err := platform.ExpandHome(value.Value)
+		if err != nil {
+			return err
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			if err := slice.Replace([]string{s}); err != nil {
+				return err
+			}
+		} else if err := flag.Value.Set(s); err != nil {
+			return err
+		}
+	case yaml.SequenceNode:
+		slice, ok := flag.Value.(pflag.SliceValue)
+		if !ok {
+			return fmt.Errorf("--%s takes a single value, not a list", flag.Name)
+		}
+		var values []string
+		if err := value.Decode(&values); err != nil {
+			return err
+		}
+		if err := slice.Replace(values); err != nil {
+			return err
+		}
+	case yaml.MappingNode:
+		if !strings.HasPrefix(flag.Value.Type(), "stringTo") {
+			return fmt.Errorf(`--%s doesn't take "<key>=<value>" pairs`, flag.Name)
+		}
+		var pairs map[string]string
+		if err := value.Decode(&pairs); err != nil {
+			return err
+		}
+		for k, v := range pairs {
+			if err := flag.Value.Set(k + "=" + v); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value")
+	}
+
+	flag.DefValue = flag.Value.String()
+	// A required flag which has a default in the config file doesn't have to be passed.
+	delete(flag.Annotations, cobra.BashCompOneRequiredFlag)
+	return nil
+}
@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// An Installed plugin is the first executable on $PATH with its name, which is the one the confluent CLI runs.
+type Installed struct {
+	Name string
+	Path string
+	// Version is the version in the plugin's manifest, or empty if the plugin doesn't print its manifest, which is the
+	// case for plugins built before they did.
+	Version string
+}
+
+// FindInstalled returns the installed plugins with the names, sorted by name.
+func FindInstalled(names map[string]bool) []Installed {
+	var plugins []Installed
+	found := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name := f.Name()
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, ".exe")
+			}
+			if !names[name] || found[name] || !executable(f) {
+				continue
+			}
+			found[name] = true
+			path := filepath.Join(dir, f.Name())
+			plugins = append(plugins, Installed{Name: name, Path: path, Version: InstalledVersion(path)})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+func executable(f os.DirEntry) bool {
+	info, err := f.Info()
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
+
+// InstalledVersion asks the plugin at the path for its manifest, and returns its version, or empty if it doesn't print
+// its manifest or isn't installed.
+func InstalledVersion(path string) string {
+	out, err := exec.Command(path, ManifestFlag).Output()
+	if err != nil {
+		return ""
+	}
+	var e Entry
+	if err := json.Unmarshal(out, &e); err != nil {
+		return ""
+	}
+	return e.Version
+}
+
+// WriteExecutable writes the plugin's binary or script to the path, or to the file which it links to, by writing it
+// next to it and renaming it, so that the plugin is never half written.
+func WriteExecutable(path string, b []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.new")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o755); err != nil {
+		return err
+	}
+
+	// Windows doesn't replace an executable which is running, such as this one, but it renames it.
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(f.Name(), path)
+}
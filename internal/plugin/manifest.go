@@ -0,0 +1,167 @@
+// Package plugin is what the plugins share about being a plugin of the confluent CLI: their manifest.yml, which
+// describes them to "confluent plugin search" and "confluent plugin list", and ~/.confluent/plugins.yaml, the defaults
+// of their flags.
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFlag is the flag with which the confluent CLI asks an installed plugin for its manifest.
+const ManifestFlag = "--plugin-manifest"
+
+// cliDependency is the name of the dependency whose version is the oldest confluent CLI which the plugin works with.
+const cliDependency = "Confluent CLI"
+
+// A Manifest is the manifest.yml of a plugin, e.g.:
+//
+//	name: confluent-topic-lint
+//	version: 1.0.0
+//	description: Check the topics of a Kafka cluster against a policy.
+//	dependencies:
+//	- name: Go
+//	  version: "1.21"
+//	- name: Confluent CLI
+//	  version: "3.0.0"
+//
+// The first dependency is the runtime which the plugin is written for.
+type Manifest struct {
+	Name         string       `yaml:"name" json:"name"`
+	Version      string       `yaml:"version" json:"version"`
+	Description  string       `yaml:"description" json:"description"`
+	Dependencies []Dependency `yaml:"dependencies" json:"dependencies"`
+}
+
+type Dependency struct {
+	Name    string `yaml:"name" json:"name"`
+	Version string `yaml:"version" json:"version"`
+}
+
+// An Entry is a manifest as it's printed and listed in the index, with the runtime and the minimum confluent CLI
+// version read from its dependencies.
+type Entry struct {
+	Manifest `yaml:",inline"`
+	// Command is how the plugin is run, such as "confluent topic lint" for confluent-topic-lint.
+	Command       string     `yaml:"command" json:"command"`
+	Runtime       Dependency `yaml:"runtime" json:"runtime"`
+	MinCLIVersion string     `yaml:"min_cli_version,omitempty" json:"min_cli_version,omitempty"`
+}
+
+// ParseManifest parses a manifest, and fails if it's missing a field which the CLI needs.
+func ParseManifest(b []byte) (Manifest, error) {
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse the manifest: %w", err)
+	}
+
+	switch {
+	case m.Name == "":
+		return Manifest{}, fmt.Errorf("the manifest has no name")
+	case m.Version == "":
+		return Manifest{}, fmt.Errorf("the manifest of %s has no version", m.Name)
+	case m.Description == "":
+		return Manifest{}, fmt.Errorf("the manifest of %s has no description", m.Name)
+	case len(m.Dependencies) == 0:
+		return Manifest{}, fmt.Errorf("the manifest of %s has no runtime dependency", m.Name)
+	}
+	return m, nil
+}
+
+// Entry returns the manifest with its runtime and minimum confluent CLI version.
+func (m Manifest) Entry() Entry {
+	e := Entry{Manifest: m, Command: command(m.Name), Runtime: m.Dependencies[0]}
+	for _, d := range m.Dependencies {
+		if d.Name == cliDependency {
+			e.MinCLIVersion = d.Version
+		}
+	}
+	return e
+}
+
+// command returns the command of a plugin from its name, in which dashes separate the words of the command, and
+// underscores are dashes within a word.
+func command(name string) string {
+	words := strings.Split(name, "-")
+	for i, word := range words {
+		words[i] = strings.ReplaceAll(word, "_", "-")
+	}
+	return strings.Join(words, " ")
+}
+
+// current is the manifest of the plugin which is running, which HandleManifest parses.
+var current Manifest
+
+// Current returns the manifest of the plugin which is running, or an empty manifest before HandleManifest is called.
+func Current() Manifest {
+	return current
+}
+
+// HandleManifest prints the manifest as JSON and exits if the plugin was run with only --plugin-manifest, or prints
+// its version and build and exits if it was run with only --version, or --version --output json, before its command
+// parses its arguments, so that they work whatever arguments and flags the command requires. It's called first thing
+// in main, with the plugin's embedded manifest.yml.
+func HandleManifest(b []byte) {
+	// A manifest which doesn't parse fails --plugin-manifest and --version below, and is checked by the index.
+	current, _ = ParseManifest(b)
+
+	var err error
+	if format, ok := versionFormat(os.Args[1:]); ok {
+		err = printVersion(os.Stdout, b, format)
+	} else if len(os.Args) == 2 && os.Args[1] == ManifestFlag {
+		err = printManifest(os.Stdout, b)
+	} else {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func printManifest(w io.Writer, b []byte) error {
+	m, err := ParseManifest(b)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m.Entry())
+}
+
+// Index reads the manifests of the plugins in the directory of the repository, sorted by name.
+func Index(dir string) ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "confluent-*", "manifest.yml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var entries []Entry
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m, err := ParseManifest(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if name := filepath.Base(filepath.Dir(path)); m.Name != name {
+			return nil, fmt.Errorf(`%s: the manifest is named "%s" rather than after its directory, "%s"`, path, m.Name, name)
+		}
+		entries = append(entries, m.Entry())
+	}
+	return entries, nil
+}
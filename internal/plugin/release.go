@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Repository is the GitHub repository whose releases have the binaries of the Go plugins.
+const Repository = "confluentinc/cli-plugins"
+
+// A release has the binary of each Go plugin for each platform, the script of each Python plugin, and these files.
+const (
+	// IndexFile is the index of the plugins, with the version of each one in the release.
+	IndexFile = "index.yml"
+	// ChecksumsFile has the SHA-256 checksum of each binary, one per line, as printed by sha256sum.
+	ChecksumsFile = "checksums.txt"
+)
+
+// Asset returns the name of the binary of a plugin in a release, such as confluent-topic-lint_linux_amd64, or
+// confluent-topic-lint_windows_amd64.exe.
+func Asset(name, goos, goarch string) string {
+	asset := fmt.Sprintf("%s_%s_%s", name, goos, goarch)
+	if goos == "windows" {
+		asset += ".exe"
+	}
+	return asset
+}
+
+// Script returns the name of the script of a Python plugin in a release, such as confluent-flink-quickstart.py, which
+// is the script in the plugin's directory.
+func Script(name string) string {
+	return name + ".py"
+}
+
+// Requirements returns the name of the pip requirements of a Python plugin in a release, such as
+// confluent-flink-quickstart.requirements.txt, which is the requirements.txt in the plugin's directory, if it has one.
+func Requirements(name string) string {
+	return name + ".requirements.txt"
+}
+
+// ParseIndex parses index.yml.
+func ParseIndex(b []byte) ([]Entry, error) {
+	var index struct {
+		Plugins []Entry `yaml:"plugins"`
+	}
+	if err := yaml.Unmarshal(b, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse the index: %w", err)
+	}
+	return index.Plugins, nil
+}
+
+// ParseChecksums parses checksums.txt into the checksum of each file, in hex.
+func ParseChecksums(b []byte) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf(`invalid line in %s: "%s"`, ChecksumsFile, line)
+		}
+		// sha256sum marks files which it read in binary mode with a "*".
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return checksums, scanner.Err()
+}
+
+// Newer returns whether version a is newer than version b, such as 1.10.0 than 1.9.2. A leading "v" is ignored, and
+// parts which aren't numbers are compared as strings.
+func Newer(a, b string) bool {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		// Missing parts are 0, so that 1.2 is as new as 1.2.0.
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x == y {
+			continue
+		}
+		m, errM := strconv.Atoi(x)
+		n, errN := strconv.Atoi(y)
+		if errM == nil && errN == nil {
+			return m > n
+		}
+		return x > y
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// VersionFlag is the flag with which a user asks a plugin which build it is, such as for a support ticket.
+const VersionFlag = "--version"
+
+// The commit and the date of the build, which the release sets with:
+//
+//	-ldflags "-X github.com/confluentinc/cli-plugins/internal/plugin.commit=<commit> -X github.com/confluentinc/cli-plugins/internal/plugin.date=<date>"
+//
+// Plugins built otherwise, such as with go install, read them from the build info which Go embeds instead.
+var (
+	commit string
+	date   string
+)
+
+// A Build is the version of a plugin and what it was built from.
+type Build struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Commit is the commit of the repository which the plugin was built from, with "-dirty" if it had changes which
+	// weren't committed, or empty if it isn't known.
+	Commit string `json:"commit"`
+	// Date is when the plugin was built, or when its commit was made if it wasn't built by the release, in RFC 3339.
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// BuildOf returns the build of the plugin with the manifest.
+func BuildOf(m Manifest) Build {
+	b := Build{
+		Name:      m.Name,
+		Version:   m.Version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if b.Commit != "" {
+		return b
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	var modified bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Commit = s.Value
+		case "vcs.time":
+			b.Date = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	// go install ...@<version> builds from the module proxy, without the repository, but the pseudo-versions of
+	// commits which aren't tagged, such as v0.0.0-20240101120000-1a2b3c4d5e6f, end with the commit.
+	if b.Commit == "" {
+		if parts := strings.Split(info.Main.Version, "-"); len(parts) >= 3 {
+			b.Commit = parts[len(parts)-1]
+		}
+	}
+	if b.Commit != "" && modified {
+		b.Commit += "-dirty"
+	}
+	return b
+}
+
+// String returns the build as a line, such as:
+//
+//	confluent-topic-lint 1.2.0 (commit 1a2b3c4d5e6f, built 2024-01-01T12:00:00Z, go1.21.5 linux/amd64)
+func (b Build) String() string {
+	revision := b.Commit
+	if revision == "" {
+		revision = "unknown"
+	}
+	details := []string{"commit " + revision}
+	if b.Date != "" {
+		details = append(details, "built "+b.Date)
+	}
+	details = append(details, b.GoVersion+" "+b.Platform)
+	return fmt.Sprintf("%s %s (%s)", b.Name, b.Version, strings.Join(details, ", "))
+}
+
+// versionFormat returns the format of the version if the arguments ask for it, which are --version, followed by
+// --output json for JSON.
+func versionFormat(args []string) (string, bool) {
+	if len(args) == 0 || args[0] != VersionFlag {
+		return "", false
+	}
+	switch strings.Join(args[1:], " ") {
+	case "":
+		return "text", true
+	case "--output json", "--output=json", "-o json", "-o=json", "-ojson":
+		return "json", true
+	}
+	return "", false
+}
+
+func printVersion(w io.Writer, b []byte, format string) error {
+	m, err := ParseManifest(b)
+	if err != nil {
+		return err
+	}
+	build := BuildOf(m)
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(build)
+	}
+	_, err = fmt.Fprintln(w, build)
+	return err
+}
@@ -0,0 +1,146 @@
+// Package progress shows that a plugin is still working: a spinner while it waits for something to be provisioned,
+// and a bar while it works through many resources. They are only drawn on a terminal: when a plugin's output is piped
+// or it runs in CI, a spinner's message is printed once instead, and bars aren't shown.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/platform"
+)
+
+// interval is how often a spinner is redrawn.
+const interval = 100 * time.Millisecond
+
+var frames = []string{"|", "/", "-", `\`}
+
+// interactive returns whether progress can be drawn on w, which is only when it's a terminal. Nothing is drawn with
+// --verbose either, since log messages would be written over it.
+func interactive(w io.Writer) bool {
+	if !platform.Terminal(w) {
+		return false
+	}
+	return !slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// A Spinner spins with how long it has been running, and a status, until it's stopped.
+type Spinner struct {
+	w       io.Writer
+	message string
+	started time.Time
+	done    chan struct{}
+	stopped sync.WaitGroup
+
+	mu     sync.Mutex
+	status string
+}
+
+// Spin starts a spinner on w with the message, such as "Waiting for the Kafka cluster to be up". When w isn't a
+// terminal, the message is printed once instead.
+func Spin(w io.Writer, message string) *Spinner {
+	s := &Spinner{w: w, message: message, started: time.Now()}
+	if !interactive(w) {
+		fmt.Fprintf(w, "%s.\n", message)
+		return s
+	}
+
+	s.done = make(chan struct{})
+	s.stopped.Add(1)
+	go func() {
+		defer s.stopped.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			s.draw(frames[i%len(frames)])
+			select {
+			case <-ticker.C:
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Status sets the status which is shown after the message, such as "PROVISIONING".
+func (s *Spinner) Status(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *Spinner) draw(frame string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	details := []string{time.Since(s.started).Round(time.Second).String()}
+	if s.status != "" {
+		details = append(details, s.status)
+	}
+	fmt.Fprintf(s.w, "\r\033[K%s %s (%s)", frame, s.message, strings.Join(details, ", "))
+}
+
+// Stop stops the spinner, and leaves the message on its line. It can be called more than once.
+func (s *Spinner) Stop() {
+	if s.done == nil {
+		return
+	}
+	close(s.done)
+	s.stopped.Wait()
+	s.done = nil
+	fmt.Fprintf(s.w, "\r\033[K%s.\n", s.message)
+}
+
+// A Bar shows how many of a number of resources have been worked through. It's safe to use from several goroutines.
+type Bar struct {
+	w       io.Writer
+	message string
+	total   int
+	shown   bool
+
+	mu   sync.Mutex
+	done int
+}
+
+// width is the number of characters of a bar between its brackets.
+const width = 30
+
+// NewBar starts a bar on w with the message, such as "Stopping statements", for the total number of resources. When
+// w isn't a terminal, nothing is shown.
+func NewBar(w io.Writer, message string, total int) *Bar {
+	b := &Bar{w: w, message: message, total: total, shown: interactive(w) && total > 0}
+	b.draw()
+	return b
+}
+
+// Add counts n more resources as worked through.
+func (b *Bar) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = min(b.done+n, b.total)
+	b.draw()
+}
+
+func (b *Bar) draw() {
+	if !b.shown {
+		return
+	}
+	filled := width * b.done / b.total
+	fmt.Fprintf(b.w, "\r\033[K%s [%s%s] %d/%d", b.message, strings.Repeat("=", filled), strings.Repeat(" ", width-filled), b.done, b.total)
+}
+
+// Done removes the bar, so that the plugin's results are printed where it was.
+func (b *Bar) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.shown {
+		fmt.Fprint(b.w, "\r\033[K")
+		b.shown = false
+	}
+}
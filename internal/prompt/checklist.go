@@ -0,0 +1,229 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"golang.org/x/term"
+)
+
+// Checklist returns the IDs of the options which are left checked, out of options which all start checked, so that the
+// resources which a plugin would act on, such as API keys to delete, are narrowed down by hand where its filters can't
+// express what to keep. In a terminal, the options are shown under the question, the arrow keys move between them,
+// Space checks or unchecks one, Ctrl-A checks or unchecks every one which matches the filter, typing filters them as
+// with Select, and Enter goes on. The checklist can't be answered without a terminal, or with --non-interactive, so it
+// fails with the flag which asked for it.
+func (p *Prompter) Checklist(flag, question string, options []Option) ([]string, error) {
+	if !p.terminal || NonInteractive() {
+		return nil, exitcode.Invalid("%s needs a terminal to pick from", flag)
+	}
+
+	checked := make([]bool, len(options))
+	for i := range checked {
+		checked[i] = true
+	}
+	var err error
+	if !platform.Terminal(p.out) {
+		err = p.checklistLine(question, options, checked)
+	} else {
+		err = p.checklistRaw(question, options, checked)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for i, o := range options {
+		if checked[i] {
+			ids = append(ids, o.ID)
+		}
+	}
+	return ids, nil
+}
+
+// checklistLine lists the options numbered with whether they're checked, and reads the numbers of the ones to check or
+// uncheck, until an empty answer.
+func (p *Prompter) checklistLine(question string, options []Option, checked []bool) error {
+	for {
+		for i, o := range options {
+			fmt.Fprintf(p.out, "%3d. %s %s\n", i+1, checkbox(checked[i]), o)
+		}
+		fmt.Fprintf(p.out, "%s (numbers or ranges to check or uncheck, such as 2 5-7, or Enter to go on): ", question)
+		answer, err := p.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return nil
+		}
+		toggled, ok := numbers(answer, len(options))
+		if !ok {
+			fmt.Fprintf(p.out, "\"%s\" isn't numbers from 1 to %d.\n", answer, len(options))
+		}
+		for _, n := range toggled {
+			checked[n-1] = !checked[n-1]
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// numbers parses numbers and ranges of numbers from 1 to limit, such as "2 5-7", separated by spaces or commas.
+func numbers(s string, limit int) ([]int, bool) {
+	var ns []int
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		from, to, isRange := strings.Cut(field, "-")
+		if !isRange {
+			to = from
+		}
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, false
+		}
+		last, err := strconv.Atoi(to)
+		if err != nil || first < 1 || last > limit || first > last {
+			return nil, false
+		}
+		for n := first; n <= last; n++ {
+			ns = append(ns, n)
+		}
+	}
+	return ns, true
+}
+
+// A checklist is the state of a checklist in a terminal, which filters and scrolls as a selector does.
+type checklist struct {
+	selector
+	checked []bool
+}
+
+// checklistRaw reads the keys of the terminal one at a time, and redraws the options after each.
+func (p *Prompter) checklistRaw(question string, options []Option, checked []bool) error {
+	// The prompter only reads from a terminal here, which is a file.
+	fd := int(p.source.(*os.File).Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	c := &checklist{selector: selector{options: options}, checked: checked}
+	c.match()
+	for {
+		c.draw(p.out, question)
+		r, _, err := p.in.ReadRune()
+		if err != nil {
+			return err
+		}
+		switch r {
+		case '\r', '\n':
+			c.clear(p.out)
+			fmt.Fprintf(p.out, "%s: %d of %d checked\r\n", question, c.count(), len(options))
+			return nil
+		case ' ':
+			if len(c.matches) > 0 {
+				i := c.matches[c.cursor]
+				c.checked[i] = !c.checked[i]
+			}
+		case 1: // Ctrl-A
+			c.toggleMatches()
+		case 3: // Ctrl-C, which doesn't raise SIGINT in raw mode.
+			c.clear(p.out)
+			return interrupt.ErrInterrupted
+		case 27: // Esc, or the start of the sequence of an arrow key.
+			if p.in.Buffered() == 0 {
+				c.clear(p.out)
+				return interrupt.ErrInterrupted
+			}
+			if next, _, _ := p.in.ReadRune(); next != '[' && next != 'O' {
+				continue
+			}
+			switch key, _, _ := p.in.ReadRune(); key {
+			case 'A':
+				c.move(-1)
+			case 'B':
+				c.move(1)
+			}
+		case 16: // Ctrl-P
+			c.move(-1)
+		case 14: // Ctrl-N
+			c.move(1)
+		case 127, 8: // Backspace
+			if len(c.filter) > 0 {
+				c.filter = c.filter[:len(c.filter)-1]
+				c.match()
+			}
+		case 21: // Ctrl-U
+			c.filter = nil
+			c.match()
+		default:
+			if unicode.IsPrint(r) {
+				c.filter = append(c.filter, r)
+				c.match()
+			}
+		}
+	}
+}
+
+// toggleMatches unchecks the options which match the filter if they're all checked, and checks them all otherwise.
+func (c *checklist) toggleMatches() {
+	all := true
+	for _, i := range c.matches {
+		all = all && c.checked[i]
+	}
+	for _, i := range c.matches {
+		c.checked[i] = !all
+	}
+}
+
+// count returns how many options are checked.
+func (c *checklist) count() int {
+	n := 0
+	for _, checked := range c.checked {
+		if checked {
+			n++
+		}
+	}
+	return n
+}
+
+// draw draws the checklist as selector.draw does, with whether each option is checked.
+func (c *checklist) draw(w io.Writer, question string) {
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+	fmt.Fprintf(&b, "%s: %s\r\n", question, string(c.filter))
+	lines := 1
+	end := min(c.top+visible, len(c.matches))
+	for i := c.top; i < end; i++ {
+		marker := "  "
+		if i == c.cursor {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%s %s\r\n", marker, checkbox(c.checked[c.matches[i]]), c.options[c.matches[i]])
+		lines++
+	}
+	if len(c.matches) == 0 {
+		b.WriteString("  No matches.\r\n")
+		lines++
+	}
+	fmt.Fprintf(&b, "  (%d of %d checked, ↑/↓ to move, Space to check, Ctrl-A for all, type to filter, Enter to go on, Esc to cancel)", c.count(), len(c.options))
+	fmt.Fprintf(&b, "\x1b[%dA\r\x1b[%dC", lines, len([]rune(question))+2+len(c.filter))
+	_, _ = io.WriteString(w, b.String())
+}
+
+// checkbox returns how an option is shown as checked or not.
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
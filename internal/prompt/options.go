@@ -0,0 +1,76 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/cli"
+)
+
+// EnvironmentOptions lists the environments of the organization to select from.
+func EnvironmentOptions() ([]Option, error) {
+	environments, err := cli.ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	options := make([]Option, len(environments))
+	for i, e := range environments {
+		options[i] = Option{ID: e.ID, Name: e.Name}
+	}
+	return options, nil
+}
+
+// KafkaClusterOptions lists the Kafka clusters of the environment, which defaults to the current one, to select from,
+// with their cloud and region.
+func KafkaClusterOptions(environment string) ([]Option, error) {
+	clusters, err := cli.ListKafkaClusters(environment)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]Option, len(clusters))
+	for i, c := range clusters {
+		options[i] = Option{ID: c.ID, Name: c.Name, Detail: strings.ToLower(c.Provider) + " " + c.Region}
+	}
+	return options, nil
+}
+
+// ComputePoolOptions lists the Flink compute pools of the environment, which defaults to the current one, to select
+// from, with their cloud, region, and size.
+func ComputePoolOptions(environment string) ([]Option, error) {
+	var pools []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Cloud  string `json:"cloud"`
+		Region string `json:"region"`
+		MaxCFU int    `json:"max_cfu"`
+	}
+	args := []string{"flink", "compute-pool", "list"}
+	if environment != "" {
+		args = append(args, "--environment", environment)
+	}
+	if err := cli.JSON(&pools, args...); err != nil {
+		return nil, err
+	}
+	options := make([]Option, len(pools))
+	for i, p := range pools {
+		options[i] = Option{ID: p.ID, Name: p.Name, Detail: fmt.Sprintf("%s %s, %d CFUs", strings.ToLower(p.Cloud), p.Region, p.MaxCFU)}
+	}
+	return options, nil
+}
+
+// ServiceAccountOptions lists the service accounts of the organization to select from, with their descriptions.
+func ServiceAccountOptions() ([]Option, error) {
+	var accounts []struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := cli.JSON(&accounts, "iam", "service-account", "list"); err != nil {
+		return nil, err
+	}
+	options := make([]Option, len(accounts))
+	for i, a := range accounts {
+		options[i] = Option{ID: a.ID, Name: a.Name, Detail: a.Description}
+	}
+	return options, nil
+}
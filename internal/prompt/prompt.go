@@ -0,0 +1,121 @@
+// Package prompt asks for the confirmations and the values which the plugins need and which weren't passed as flags,
+// unless the plugin is run unattended, such as in CI: --yes answers yes to every confirmation, and --non-interactive
+// fails at once with the flag to pass, rather than waiting for an answer which never comes.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// NonInteractiveEnv is the environment variable which turns prompts off, as --non-interactive does, when it's set to
+// anything, such as in the environment of a CI job.
+const NonInteractiveEnv = "CONFLUENT_PLUGINS_NON_INTERACTIVE"
+
+// yes and nonInteractive are set by --yes and --non-interactive.
+var (
+	yes            bool
+	nonInteractive bool
+)
+
+// AddFlags adds --yes and --non-interactive to the command and its subcommands, for plugins which prompt.
+func AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(&yes, "yes", "y", false, "Answer yes to every confirmation, such as in CI.")
+	cmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt, and fail if a value or a confirmation which would have been asked for wasn't passed, such as in CI.")
+}
+
+// Yes returns whether confirmations are answered with yes, with --yes.
+func Yes() bool {
+	return yes
+}
+
+// NonInteractive returns whether the plugin never prompts, with --non-interactive or its environment variable.
+func NonInteractive() bool {
+	return nonInteractive || os.Getenv(NonInteractiveEnv) != ""
+}
+
+// A Prompter asks questions on the command's stderr, and reads the answers from its stdin, so that the output of the
+// command stays clean.
+type Prompter struct {
+	terminal bool
+	source   io.Reader
+	in       *bufio.Reader
+	out      io.Writer
+}
+
+// New returns a prompter for the command. A plugin which asks several questions asks them all with one prompter,
+// since it reads ahead of each answer.
+func New(cmd *cobra.Command) *Prompter {
+	f, ok := cmd.InOrStdin().(*os.File)
+	terminal := ok && term.IsTerminal(int(f.Fd()))
+	return &Prompter{terminal: terminal, source: cmd.InOrStdin(), in: bufio.NewReader(cmd.InOrStdin()), out: cmd.ErrOrStderr()}
+}
+
+// Value returns the value of the flag, or asks for it when the plugin is run in a terminal, so that a wizard can be
+// driven entirely by flags in CI, or walked through by hand. An empty answer is only accepted if the value is optional.
+func (p *Prompter) Value(value *string, flag, question string, required bool) error {
+	if *value != "" {
+		return nil
+	}
+	if !p.terminal || NonInteractive() {
+		if required {
+			return exitcode.Invalid(`required flag "%s" not set`, flag)
+		}
+		return nil
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s: ", question)
+		answer, err := p.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		*value = strings.TrimSpace(answer)
+		if *value != "" || !required {
+			return nil
+		}
+		if err == io.EOF {
+			return exitcode.Invalid(`required flag "%s" not set`, flag)
+		}
+	}
+}
+
+// Confirm asks whether to go ahead, which is answered with "y". Without a terminal, the answer is read from stdin like
+// any other, unless the plugin is run with --non-interactive, in which case it fails unless it's run with --yes.
+func (p *Prompter) Confirm(question string) (bool, error) {
+	return p.confirm(question, question+" (y/n)", "y")
+}
+
+// Type asks for the answer which confirms, such as the ID of an environment which would be deleted, and returns
+// whether it was typed.
+func (p *Prompter) Type(question, answer string) (bool, error) {
+	return p.confirm(question, question, answer)
+}
+
+func (p *Prompter) confirm(question, prompt, answer string) (bool, error) {
+	if Yes() {
+		return true, nil
+	}
+	if NonInteractive() {
+		return false, exitcode.Invalid(`not asking "%s" with --non-interactive, pass --yes to confirm`, question)
+	}
+
+	fmt.Fprintf(p.out, "%s: ", prompt)
+	typed, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return strings.TrimSpace(typed) == answer, nil
+}
+
+// Confirm asks the command's user whether to go ahead, as Prompter.Confirm does, for a plugin with one question.
+func Confirm(cmd *cobra.Command, question string) (bool, error) {
+	return New(cmd).Confirm(question)
+}
@@ -0,0 +1,260 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/confluentinc/cli-plugins/internal/exitcode"
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+	"github.com/confluentinc/cli-plugins/internal/platform"
+	"golang.org/x/term"
+)
+
+// visible is how many options a selector shows at once. The others are scrolled to with the arrow keys, or found by
+// typing.
+const visible = 10
+
+// An Option is one of the resources which a selector picks from, such as a Kafka cluster.
+type Option struct {
+	ID   string
+	Name string
+	// Detail is shown after the name, such as the region of a cluster, and is matched when typing too.
+	Detail string
+}
+
+// String returns the option as it's listed, such as "lkc-123456  orders  aws us-east-1".
+func (o Option) String() string {
+	s := o.ID
+	if o.Name != "" && o.Name != o.ID {
+		s += "  " + o.Name
+	}
+	if o.Detail != "" {
+		s += "  " + o.Detail
+	}
+	return s
+}
+
+// Select sets value to the ID of the option which is picked, unless it was passed with its flag, so that the ID of an
+// environment, a cluster, or a service account is picked from a live list rather than typed. The options are only
+// listed, such as with EnvironmentOptions, if there's a question to ask. In a terminal, they're shown under the
+// question, the arrow keys move between them, typing filters them with a fuzzy match, and Enter picks one. Without a
+// terminal, or with --non-interactive, the flag is required, as with Value.
+func (p *Prompter) Select(value *string, flag, question string, list func() ([]Option, error)) error {
+	if *value != "" {
+		return nil
+	}
+	if !p.terminal || NonInteractive() {
+		return exitcode.Invalid(`required flag "%s" not set`, flag)
+	}
+	options, err := list()
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 {
+		return exitcode.Invalid(`required flag "%s" not set, and there's nothing to pick from`, flag)
+	}
+
+	// The options are only drawn over each other where the terminal can, and are numbered otherwise.
+	if !platform.Terminal(p.out) {
+		return p.selectLine(value, flag, question, options)
+	}
+	return p.selectRaw(value, question, options)
+}
+
+// selectLine lists the options numbered, and reads the number or the ID of the one which is picked.
+func (p *Prompter) selectLine(value *string, flag, question string, options []Option) error {
+	for i, o := range options {
+		fmt.Fprintf(p.out, "%3d. %s\n", i+1, o)
+	}
+	for {
+		fmt.Fprintf(p.out, "%s (number or ID): ", question)
+		answer, err := p.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		answer = strings.TrimSpace(answer)
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(options) {
+			*value = options[n-1].ID
+			return nil
+		}
+		for _, o := range options {
+			if o.ID == answer {
+				*value = o.ID
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return exitcode.Invalid(`required flag "%s" not set`, flag)
+		}
+		fmt.Fprintf(p.out, "\"%s\" isn't one of them.\n", answer)
+	}
+}
+
+// A selector is the state of a selection in a terminal.
+type selector struct {
+	options []Option
+	filter  []rune
+	// matches are the indexes of the options which match the filter, best first.
+	matches []int
+	// cursor is the index in matches of the option which Enter picks, and top is the first one shown.
+	cursor, top int
+}
+
+// selectRaw reads the keys of the terminal one at a time, and redraws the options after each.
+func (p *Prompter) selectRaw(value *string, question string, options []Option) error {
+	// The prompter only reads from a terminal here, which is a file.
+	fd := int(p.source.(*os.File).Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	s := &selector{options: options}
+	s.match()
+	for {
+		s.draw(p.out, question)
+		r, _, err := p.in.ReadRune()
+		if err != nil {
+			return err
+		}
+		switch r {
+		case '\r', '\n':
+			if len(s.matches) == 0 {
+				continue
+			}
+			picked := s.options[s.matches[s.cursor]]
+			s.clear(p.out)
+			fmt.Fprintf(p.out, "%s: %s\r\n", question, picked)
+			*value = picked.ID
+			return nil
+		case 3: // Ctrl-C, which doesn't raise SIGINT in raw mode.
+			s.clear(p.out)
+			return interrupt.ErrInterrupted
+		case 27: // Esc, or the start of the sequence of an arrow key.
+			if p.in.Buffered() == 0 {
+				s.clear(p.out)
+				return interrupt.ErrInterrupted
+			}
+			if next, _, _ := p.in.ReadRune(); next != '[' && next != 'O' {
+				continue
+			}
+			switch key, _, _ := p.in.ReadRune(); key {
+			case 'A':
+				s.move(-1)
+			case 'B':
+				s.move(1)
+			}
+		case 16: // Ctrl-P
+			s.move(-1)
+		case 14: // Ctrl-N
+			s.move(1)
+		case 127, 8: // Backspace
+			if len(s.filter) > 0 {
+				s.filter = s.filter[:len(s.filter)-1]
+				s.match()
+			}
+		case 21: // Ctrl-U
+			s.filter = nil
+			s.match()
+		default:
+			if unicode.IsPrint(r) {
+				s.filter = append(s.filter, r)
+				s.match()
+			}
+		}
+	}
+}
+
+// match filters the options with the filter, and moves the cursor back to the best match.
+func (s *selector) match() {
+	filter := strings.ToLower(string(s.filter))
+	type match struct {
+		index, score int
+	}
+	var matches []match
+	for i, o := range s.options {
+		if score, ok := fuzzy(filter, strings.ToLower(o.String())); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+	s.matches = make([]int, len(matches))
+	for i, m := range matches {
+		s.matches[i] = m.index
+	}
+	s.cursor, s.top = 0, 0
+}
+
+// fuzzy returns whether the runes of the filter are in the text in order, such as "ordprd" in "orders-prod", and a
+// score, lower for a better match: 0 if the filter is in the text as it is, and otherwise how far apart its runes are.
+func fuzzy(filter, text string) (int, bool) {
+	if strings.Contains(text, filter) {
+		return 0, true
+	}
+	runes := []rune(filter)
+	i, first, last := 0, -1, -1
+	for j, r := range []rune(text) {
+		if i < len(runes) && r == runes[i] {
+			if first < 0 {
+				first = j
+			}
+			last = j
+			i++
+		}
+	}
+	if i < len(runes) {
+		return 0, false
+	}
+	return 1 + last - first - len(runes), true
+}
+
+// move moves the cursor by n, scrolling the options which are shown to keep it in view.
+func (s *selector) move(n int) {
+	if len(s.matches) == 0 {
+		return
+	}
+	s.cursor = (s.cursor + n + len(s.matches)) % len(s.matches)
+	switch {
+	case s.cursor < s.top:
+		s.top = s.cursor
+	case s.cursor >= s.top+visible:
+		s.top = s.cursor - visible + 1
+	}
+}
+
+// draw draws the question with the filter, the options which are in view, and the keys, over the last draw, and leaves
+// the cursor at the end of the filter, where the next key is typed. Lines end with "\r\n", since the terminal is in raw
+// mode.
+func (s *selector) draw(w io.Writer, question string) {
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+	fmt.Fprintf(&b, "%s: %s\r\n", question, string(s.filter))
+	lines := 1
+	end := min(s.top+visible, len(s.matches))
+	for i := s.top; i < end; i++ {
+		marker := "  "
+		if i == s.cursor {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%s\r\n", marker, s.options[s.matches[i]])
+		lines++
+	}
+	if len(s.matches) == 0 {
+		b.WriteString("  No matches.\r\n")
+		lines++
+	}
+	fmt.Fprintf(&b, "  (%d of %d, ↑/↓ to move, type to filter, Enter to pick, Esc to cancel)", len(s.matches), len(s.options))
+	fmt.Fprintf(&b, "\x1b[%dA\r\x1b[%dC", lines, len([]rune(question))+2+len(s.filter))
+	_, _ = io.WriteString(w, b.String())
+}
+
+// clear clears the last draw, whose first line the cursor is on.
+func (s *selector) clear(w io.Writer) {
+	_, _ = io.WriteString(w, "\r\x1b[J")
+}
@@ -0,0 +1,117 @@
+package retry
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+)
+
+// Transport retries requests which are rate limited or which the API is unavailable for, which it didn't handle, and
+// for requests which can be repeated, such as GETs, requests which time out or fail at the gateway too.
+type Transport struct {
+	// Base sends the requests, which defaults to http.DefaultTransport.
+	Base   http.RoundTripper
+	Policy Policy
+}
+
+// Client returns an HTTP client which retries with the default policy, and whose requests time out after timeout.
+func Client(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: Transport{Policy: Default}}
+}
+
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Once the plugin is interrupted, no more requests are sent.
+	if err := interrupt.Err(); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	// A request whose body can't be read again is only sent once.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return base.RoundTrip(req)
+	}
+
+	var res *http.Response
+	attempt := 0
+	err := t.Policy.Do(func() error {
+		attempt++
+		r := req
+		if attempt > 1 {
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return err
+				}
+				r.Body = body
+			}
+		}
+
+		start := time.Now()
+		var err error
+		res, err = base.RoundTrip(r)
+		if err != nil {
+			slog.Debug("HTTP request failed", "method", req.Method, "url", req.URL.Redacted(), "error", err, "elapsed", time.Since(start))
+			if idempotent(req.Method) && req.Context().Err() == nil {
+				return &Temporary{Err: err}
+			}
+			return err
+		}
+		slog.Debug("Sent an HTTP request", "method", req.Method, "url", req.URL.Redacted(), "status", res.StatusCode, "elapsed", time.Since(start))
+
+		switch {
+		case res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable:
+		case (res.StatusCode == http.StatusBadGateway || res.StatusCode == http.StatusGatewayTimeout) && idempotent(req.Method):
+		default:
+			return nil
+		}
+		if attempt >= t.Policy.Attempts {
+			return nil
+		}
+		after := retryAfter(res.Header.Get("Retry-After"))
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		return &Temporary{Err: &statusError{res.Status}, After: after}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+type statusError struct {
+	status string
+}
+
+func (e *statusError) Error() string {
+	return e.status
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header, which is a number of seconds or a date.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+// Package retry retries calls to the confluent CLI and to Confluent Cloud APIs which fail for a while, such as when
+// they're rate limited, with exponential backoff and jitter, so that bulk plugins slow down rather than fail.
+package retry
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/interrupt"
+)
+
+// A Policy is how many times a call is made before its error is returned, and how long to wait between calls, which
+// doubles each time from Base up to Max.
+type Policy struct {
+	Attempts int
+	Base     time.Duration
+	Max      time.Duration
+}
+
+// Default waits about 1s, 2s, 4s, and 8s between five calls, or as long as the API asks to.
+var Default = Policy{Attempts: 5, Base: time.Second, Max: 30 * time.Second}
+
+// Temporary is an error which may not happen again, such as a rate limit, with how long the API asked to wait for, if
+// it did.
+type Temporary struct {
+	Err   error
+	After time.Duration
+}
+
+func (t *Temporary) Error() string {
+	return t.Err.Error()
+}
+
+func (t *Temporary) Unwrap() error {
+	return t.Err
+}
+
+// Do calls f until it succeeds, it returns an error which isn't *Temporary, or it has been called p.Attempts times.
+// The error of a *Temporary is returned without it.
+func (p Policy) Do(f func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := f()
+		var temporary *Temporary
+		if !errors.As(err, &temporary) {
+			return err
+		}
+		if attempt >= p.Attempts {
+			return temporary.Err
+		}
+		wait := max(p.delay(attempt), temporary.After)
+		slog.Warn("Retrying", "error", temporary.Err, "in", wait.Round(time.Millisecond), "attempt", attempt+1, "of", p.Attempts)
+		if err := interrupt.Sleep(wait); err != nil {
+			return err
+		}
+	}
+}
+
+// delay is how long to wait after the attempt, which is at least half the backoff, so that the waits still grow, and
+// at most all of it, so that plugins which were throttled at once don't all retry at once.
+func (p Policy) delay(attempt int) time.Duration {
+	backoff := p.Max
+	if shift := attempt - 1; shift < 32 && p.Base<<shift < p.Max {
+		backoff = p.Base << shift
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
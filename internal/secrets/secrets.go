@@ -0,0 +1,205 @@
+// Package secrets resolves the placeholders of secrets in the configs which plugins deploy, such as the configs of
+// connectors, from environment variables, HashiCorp Vault, and AWS, so that config files can be committed without them.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// Placeholder matches a "${...}" placeholder in a config value, whose reference is its first group.
+var Placeholder = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// A Resolver replaces the placeholders in config values with their values, from:
+//
+//	${NAME}                           the environment variable NAME
+//	${vault:secret/data/connect#key}  the key of a HashiCorp Vault KV secret
+//	${aws:arn:aws:...#key}            the key of an AWS Secrets Manager secret or SSM parameter which is a JSON object,
+//	                                  or the whole secret without "#key"
+//
+// Each secret is only read once, however many configs use it.
+type Resolver struct {
+	ctx     context.Context
+	secrets map[string]map[string]any
+}
+
+// NewResolver returns a resolver which reads secrets with the context, such as to cancel the requests to AWS.
+func NewResolver(ctx context.Context) *Resolver {
+	return &Resolver{ctx: ctx, secrets: map[string]map[string]any{}}
+}
+
+// Render replaces the placeholders in the value with their values.
+func (r *Resolver) Render(value string) (string, error) {
+	var err error
+	rendered := Placeholder.ReplaceAllStringFunc(value, func(p string) string {
+		if err != nil {
+			return ""
+		}
+		var v string
+		v, err = r.Resolve(Placeholder.FindStringSubmatch(p)[1])
+		return v
+	})
+	return rendered, err
+}
+
+// Resolve returns the value of the reference of a placeholder, such as "vault:secret/data/connect#password".
+func (r *Resolver) Resolve(ref string) (string, error) {
+	source, path, ok := strings.Cut(ref, ":")
+	if !ok {
+		value, ok := os.LookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", ref)
+		}
+		return value, nil
+	}
+
+	path, key, _ := strings.Cut(path, "#")
+	switch source {
+	case "vault":
+		if key == "" {
+			return "", fmt.Errorf(`Vault secret "%s" needs a key, such as "${vault:%s#password}"`, path, path)
+		}
+	case "aws":
+		if key == "" {
+			return r.readAWS(path)
+		}
+	default:
+		return "", fmt.Errorf(`unsupported secret source "%s", supported sources: vault, aws`, source)
+	}
+
+	id := source + ":" + path
+	secret, ok := r.secrets[id]
+	if !ok {
+		var err error
+		if source == "vault" {
+			secret, err = readVault(path)
+		} else {
+			secret, err = r.readAWSObject(path)
+		}
+		if err != nil {
+			return "", err
+		}
+		r.secrets[id] = secret
+	}
+
+	value, ok := secret[key]
+	if !ok {
+		return "", fmt.Errorf(`secret "%s" has no key "%s"`, path, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// readVault reads a KV secret, using the same environment variables as the Vault CLI. The path is the secret's API path,
+// which includes "data/" for version 2 of the KV secrets engine, e.g. "secret/data/connect".
+func readVault(path string) (map[string]any, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR must be set to read secrets from Vault")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN must be set to read secrets from Vault")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret from Vault: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Data   map[string]any `json:"data"`
+		Errors []string       `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil && res.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		if len(body.Errors) > 0 {
+			return nil, fmt.Errorf(`failed to read "%s" from Vault: %s`, path, strings.Join(body.Errors, ", "))
+		}
+		return nil, fmt.Errorf(`failed to read "%s" from Vault: %s`, path, res.Status)
+	}
+
+	// Version 2 of the KV secrets engine nests the secret's data alongside its metadata.
+	if data, ok := body.Data["data"].(map[string]any); ok && body.Data["metadata"] != nil {
+		return data, nil
+	}
+	return body.Data, nil
+}
+
+// readAWS reads an AWS Secrets Manager secret or an SSM parameter, using the default AWS credential chain. The region is
+// taken from the ARN.
+func (r *Resolver) readAWS(secretARN string) (string, error) {
+	parsed, err := arn.Parse(secretARN)
+	if err != nil {
+		return "", fmt.Errorf(`invalid AWS ARN "%s": %w`, secretARN, err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(r.ctx, config.WithRegion(parsed.Region))
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	switch parsed.Service {
+	case "secretsmanager":
+		out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(r.ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretARN),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret from AWS Secrets Manager: %w", err)
+		}
+		return aws.ToString(out.SecretString), nil
+	case "ssm":
+		out, err := ssm.NewFromConfig(cfg).GetParameter(r.ctx, &ssm.GetParameterInput{
+			Name:           aws.String(secretARN),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret from AWS SSM Parameter Store: %w", err)
+		}
+		return aws.ToString(out.Parameter.Value), nil
+	default:
+		return "", fmt.Errorf(`unsupported AWS service "%s", the ARN must be a Secrets Manager secret or an SSM parameter`, parsed.Service)
+	}
+}
+
+func (r *Resolver) readAWSObject(secretARN string) (map[string]any, error) {
+	value, err := r.readAWS(secretARN)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret map[string]any
+	if err := json.Unmarshal([]byte(value), &secret); err != nil {
+		return nil, fmt.Errorf(`failed to decode AWS secret "%s", which must be a JSON object to read a key from: %w`, secretARN, err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,87 @@
+// Package telemetry reports each run of a plugin, for users who opt in, so that the maintainers can see which flows
+// fail most often. An event only has the plugin, its version, the command, how long it took, and the class of its
+// error, and never the arguments or flags of the command, or the errors themselves, since they have the names of
+// resources and can have secrets.
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/confluentinc/cli-plugins/internal/plugin"
+)
+
+// Env is the environment variable of the endpoint which events are posted to, which overrides the telemetry endpoint
+// of ~/.confluent/plugins.yaml. Setting it to "off" turns telemetry off.
+const Env = "CONFLUENT_PLUGINS_TELEMETRY"
+
+// timeout is how long the plugin waits for the endpoint before it exits anyway.
+const timeout = 2 * time.Second
+
+// An Event is a run of a plugin.
+type Event struct {
+	Plugin   string `json:"plugin"`
+	Version  string `json:"version"`
+	Platform string `json:"platform"`
+	// Command is the command which was run, such as "confluent tag manager apply", without its arguments or flags.
+	Command    string `json:"command"`
+	DurationMS int64  `json:"duration_ms"`
+	ExitCode   int    `json:"exit_code"`
+	// ErrorClass is the category of the error, such as "auth" or "not_found", or empty if the command succeeded.
+	ErrorClass string `json:"error_class,omitempty"`
+}
+
+// Endpoint returns the URL which events are posted to, or "" if the user hasn't opted in.
+func Endpoint() string {
+	if endpoint := os.Getenv(Env); endpoint != "" {
+		if endpoint == "off" {
+			return ""
+		}
+		return endpoint
+	}
+	// A config file which can't be read has already failed the plugin, in plugin.ApplyConfig.
+	config, err := plugin.ReadConfig()
+	if err != nil {
+		return ""
+	}
+	return config.Telemetry.Endpoint
+}
+
+// Send posts the run of the command to the endpoint, if the user opted in, with the plugin and version of the running
+// plugin. Failing to send it isn't an error, and is only logged with --verbose.
+func Send(command string, duration time.Duration, exitCode int, errorClass string) {
+	endpoint := Endpoint()
+	if endpoint == "" {
+		return
+	}
+
+	m := plugin.Current()
+	e := Event{
+		Plugin:     m.Name,
+		Version:    m.Version,
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		Command:    command,
+		DurationMS: duration.Milliseconds(),
+		ExitCode:   exitCode,
+		ErrorClass: errorClass,
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	// The event isn't retried, so that a plugin never waits on telemetry for longer than the timeout.
+	client := http.Client{Timeout: timeout}
+	res, err := client.Post(endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		slog.Debug("Failed to send telemetry", "error", err)
+		return
+	}
+	_ = res.Body.Close()
+	slog.Debug("Sent telemetry", "event", string(b), "status", res.StatusCode)
+}